@@ -0,0 +1,36 @@
+// Thin wrapper of the codeowners WebAssembly module, which validates and matches CODEOWNERS files with
+// the same semantics as the CLI. Load `wasm_exec.js` from the Go distribution before this module, e.g.:
+//
+//   import { load } from './codeowners.js';
+//
+//   const codeowners = await load('codeowners.wasm');
+//   codeowners.parse('/docs/ @org/docs\n');           // [{lineNo: 1, pattern: '/docs/', owners: ['@org/docs']}]
+//   codeowners.match(content, ['docs/index.md']);     // [{path: 'docs/index.md', entry: {...}}]
+//   codeowners.validate(content, ['github', 'gitlab']); // [{severity: 'error', lineNo: 2, message: '...'}]
+
+function call(fn, request) {
+  const response = JSON.parse(globalThis.codeownersCall(fn, JSON.stringify(request)));
+  if (response.error) {
+    throw new Error(response.error);
+  }
+  return response.result;
+}
+
+// load instantiates the WebAssembly module from a given URL, or from given bytes, and returns its functions.
+export async function load(source) {
+  const go = new globalThis.Go();
+  const { instance } = source instanceof ArrayBuffer || ArrayBuffer.isView(source)
+    ? await WebAssembly.instantiate(source, go.importObject)
+    : await WebAssembly.instantiateStreaming(fetch(source), go.importObject);
+  // the module runs until the page is closed, so the promise is not awaited
+  go.run(instance);
+
+  return {
+    // parse returns entries of the CODEOWNERS content.
+    parse: (content) => call('parse', { content }),
+    // match returns the entry which owns each of given slash-separated paths, or null if a path is not owned.
+    match: (content, paths) => call('match', { content, paths }),
+    // validate returns syntax issues of the CODEOWNERS content for given dialects, github by default.
+    validate: (content, providers = []) => call('validate', { content, providers }),
+  };
+}
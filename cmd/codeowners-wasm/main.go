@@ -0,0 +1,25 @@
+//go:build js && wasm
+
+// Command codeowners-wasm is the WebAssembly build of the CODEOWNERS parser, matcher, and syntax check.
+// It registers the `codeownersCall(fn, request)` global function, which takes and returns JSON strings,
+// as described in the internal/bindings package. Use the codeowners.js wrapper instead of calling it directly.
+package main
+
+import (
+	"context"
+	"syscall/js"
+
+	"go.szostok.io/codeowners/internal/bindings"
+)
+
+func main() {
+	js.Global().Set("codeownersCall", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		if len(args) != 2 {
+			return string(bindings.Call(context.Background(), "", nil))
+		}
+		return string(bindings.Call(context.Background(), args[0].String(), []byte(args[1].String())))
+	}))
+
+	// keep the Go runtime alive, so the registered function can be called
+	select {}
+}
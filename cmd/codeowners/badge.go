@@ -0,0 +1,93 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/badge"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func badgeCmd(cfg *config.Config) *cobra.Command {
+	var (
+		metric string
+		output string
+		format string
+		opts   badge.CoverageOptions
+	)
+
+	badgeCmd := &cobra.Command{
+		Use:   "badge",
+		Short: "Generate an ownership badge for READMEs and dashboards",
+		Long: `Generate a badge which shows the ownership coverage, i.e. the percentage of repository files which have owners.
+
+The badge is rendered either as an SVG image, or as the shields.io endpoint JSON, which can be served
+from any static hosting and rendered by https://img.shields.io/endpoint?url=<badge-json-url>.
+If not set explicitly, the format is derived from the output file extension.`,
+		Example: `  codeowners report badge --metric coverage --output badge.svg
+  codeowners report badge --metric coverage --output badge.json
+  codeowners report badge --format shields-json > coverage.json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if metric != "coverage" {
+				exitOnError(errors.Errorf("unknown metric %q, possible values: coverage", metric))
+			}
+
+			if format == "" {
+				format = "svg"
+				if filepath.Ext(output) == ".json" {
+					format = "shields-json"
+				}
+			}
+
+			var write func(io.Writer, badge.Badge) error
+			switch format {
+			case "svg":
+				write = badge.WriteSVG
+			case "shields-json":
+				write = badge.WriteShieldsJSON
+			default:
+				exitOnError(errors.Errorf("unknown format %q, possible values: svg, shields-json", format))
+			}
+
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			f, err := os.Open(path)
+			exitOnError(err)
+			defer f.Close()
+
+			opts.Scope, err = pathScope(cfg)
+			exitOnError(err)
+
+			b, err := badge.Coverage(cfg.RepositoryPath, codeowners.ParseCodeowners(f), opts)
+			exitOnError(err)
+
+			if output == "-" {
+				exitOnError(write(cmd.OutOrStdout(), b))
+				return
+			}
+
+			out, err := os.Create(output)
+			exitOnError(err)
+			defer out.Close()
+
+			exitOnError(write(out, b))
+		},
+	}
+
+	badgeCmd.Flags().StringVar(&metric, "metric", "coverage", "Metric shown on the badge. Possible values: coverage")
+	badgeCmd.Flags().StringVarP(&output, "output", "o", "-", "Path to the badge file. Use - to write to the standard output")
+	badgeCmd.Flags().StringVar(&format, "format", "", "Format of the badge. Possible values: svg, shields-json. Defaults to shields-json for .json output files, and svg otherwise")
+	badgeCmd.Flags().BoolVar(&opts.SkipGenerated, "skip-generated", false, "Exclude files marked as linguist-generated or linguist-vendored in .gitattributes")
+	badgeCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+	addPathScopeFlags(badgeCmd)
+
+	return badgeCmd
+}
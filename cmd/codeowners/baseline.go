@@ -0,0 +1,70 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/redact"
+
+	"github.com/pkg/errors"
+)
+
+func baselineCmd(cfg *config.Config) *cobra.Command {
+	baselineCmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage the baseline file with known issues",
+	}
+	baselineCmd.AddCommand(baselineGenerateCmd(cfg))
+
+	return baselineCmd
+}
+
+func baselineGenerateCmd(cfg *config.Config) *cobra.Command {
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Record issues found in the repository into the baseline file",
+		Long: `Run the checks and record all reported issues into the baseline file, so they don't fail later validations,
+while new issues do. Files listed by issues, such as not owned files, are recorded one by one, and other issues
+are identified by their check, message, and CODEOWNERS entry, regardless of its line.
+
+The checks are configured the same way as for the 'validate' command. The file is written to the path set with
+the --baseline flag, or to ` + baseline.DefaultFilename + ` in the repository root, where it's read by default.
+The existing baseline is ignored, so regenerating the file removes issues which were fixed.`,
+		Example: `  codeowners baseline generate --experimental-checks not-owned
+  codeowners validate --experimental-checks not-owned                     # fails only on new issues
+  codeowners validate --experimental-checks not-owned --fail-on-new-only  # reports known issues too`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+
+			cfg.NoBaseline = true
+			checkRunner, err := validate(cmd.Context(), log, cfg, githubCacheOpts(log, cfg)...)
+			exitOnError(err)
+			exitOnError(cmd.Context().Err())
+
+			// a check which failed didn't report its issues, so they would fail later validations
+			for _, res := range checkRunner.Results() {
+				if res.Err != nil {
+					exitOnError(errors.Wrapf(res.Err, "check %q failed, the baseline is not written", res.Check))
+				}
+			}
+
+			path := cfg.Baseline
+			if path == "" {
+				path = filepath.Join(cfg.RepositoryPath, baseline.DefaultFilename)
+			}
+			file := baseline.NewFile(checkRunner.Findings())
+			exitOnError(file.Save(path))
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Recorded %d known issues in %s\n", len(file.Findings), path)
+		},
+	}
+	addValidateFlags(generateCmd)
+
+	return generateCmd
+}
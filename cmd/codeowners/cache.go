@@ -0,0 +1,71 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+func cacheCmd(cfg *config.Config) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the GitHub API response cache",
+	}
+	cacheCmd.AddCommand(cacheWarmCmd(cfg))
+
+	return cacheCmd
+}
+
+func cacheWarmCmd(cfg *config.Config) *cobra.Command {
+	var (
+		org   string
+		repos []string
+	)
+
+	warmCmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Prefetch GitHub organization data needed by the owners check into the cache file",
+		Long: `Prefetch organization members, teams, and users, and for the given repositories, outside collaborators
+and team permissions, into the GitHub cache file.
+
+Run it once per CI pipeline, before parallel validations that read the same file with the --github-cache-file flag,
+so the organization is not queried by each validation separately.`,
+		Example: `  codeowners cache warm --org my-org --repositories api,web --github-cache-file /tmp/github-cache.json
+  codeowners validate --github-cache-file /tmp/github-cache.json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if org == "" {
+				exitOnError(&api.ConfigError{Field: "ORG", Err: errors.New("organization is required")})
+			}
+			if cfg.GithubCacheFile == "" {
+				exitOnError(&api.ConfigError{Field: "GITHUB_CACHE_FILE", Err: errors.New("cache file is required")})
+			}
+
+			cache := github.NewResponseCache()
+			ghClient, _, err := github.NewClient(cmd.Context(), cfg, github.WithResponseCache(cache))
+			exitOnError(err)
+
+			stats, err := check.WarmOwnerCache(cmd.Context(), ghClient, org, repos)
+			exitOnError(err)
+			exitOnError(cache.Save(cfg.GithubCacheFile))
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Cached %d responses for %d members, %d teams, and %d repositories of %q in %s\n",
+				cache.Len(), stats.Members, stats.Teams, stats.Repositories, org, cfg.GithubCacheFile)
+		},
+	}
+
+	warmCmd.Flags().StringVar(&org, "org", "", "The GitHub organization which members and teams are prefetched")
+	warmCmd.Flags().StringSliceVar(&repos, "repositories", nil, "The comma-separated list of organization repositories which collaborators and team permissions are prefetched")
+	warmCmd.Flags().String("github-cache-file", "", "File to which the GitHub API responses are written")
+	addGitHubFlags(warmCmd)
+
+	return warmCmd
+}
@@ -1,25 +1,44 @@
+//go:build !minimal
+
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
-	"go.szostok.io/codeowners/internal/api"
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/fingerprint"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/httpheader"
+	"go.szostok.io/codeowners/internal/isolation"
+	"go.szostok.io/codeowners/internal/labeler"
 	"go.szostok.io/codeowners/internal/load"
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/internal/placeholder"
+	"go.szostok.io/codeowners/internal/printer"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/recording"
+	"go.szostok.io/codeowners/internal/redact"
 	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/internal/tree"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/codeowners"
 	"go.szostok.io/version/extension"
 )
 
-//var severity api.SeverityType
-
 // NewRoot returns a root cobra.Command for the whole Agent utility.
 func RootCmd() *cobra.Command {
 	cfg := &config.Config{}
@@ -31,115 +50,894 @@ func RootCmd() *cobra.Command {
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			return InitializeConfig(cmd, cfg, args)
 		},
+		// Deprecated: running the validation without the subcommand is kept for backward compatibility.
+		Run: func(cmd *cobra.Command, args []string) {
+			redact.NewLogger().Warn("Running the validation without a subcommand is deprecated and will be removed in a future release, use 'codeowners validate' instead")
+			runValidation(cmd, cfg)
+		},
 	}
+	addValidateFlags(rootCmd)
+	addValidationCacheFlags(rootCmd)
+	// validation flags are kept only for the deprecated root invocation, so they are not advertised
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Hidden = true
+	})
+	rootCmd.PersistentFlags().Bool(config.StrictKey, false, "Fail on unknown config file keys and CODEOWNERS_ environment variables instead of ignoring them")
+	rootCmd.PersistentFlags().String(config.ConfigFileKey, "", "Path to the configuration file. Defaults to "+config.DefaultConfigFilename+" in the current directory or in the repository root")
+	rootCmd.PersistentFlags().String("profile", "", "Name of the configuration profile, e.g. strict, relaxed, ci, local, or one defined in the config file")
 
 	rootCmd.AddCommand(
 		extension.NewVersionCobraCmd(),
 		validateCmd(cfg),
+		queryCmd(cfg),
+		whoOwnsCmd(cfg),
+		resolveCmd(cfg),
+		diffCmd(cfg),
+		reportCmd(cfg),
+		fmtCmd(cfg),
+		lspCmd(),
+		ctlCmd(),
+		workspaceCmd(cfg),
+		renameOwnerCmd(cfg),
+		migratePathsCmd(cfg),
+		editCmd(cfg),
+		verifyGeneratedCmd(cfg),
+		serveCmd(cfg),
+		configCmd(),
+		cacheCmd(cfg),
+		baselineCmd(cfg),
+		preReceiveCmd(cfg),
+		gateCmd(cfg),
+		deprecatedAlias(oncallExportCmd(cfg), "oncall-export", "report oncall"),
+		deprecatedAlias(remediationReportCmd(cfg), "remediation-report", "report remediation"),
+		deprecatedAlias(badgeCmd(cfg), "badge", "report badge"),
+		isolatedCheckCmd(cfg),
 	)
 
 	return rootCmd
 }
 
+// deprecatedAlias exposes a command under its previous name, so existing scripts keep working
+// after the command was moved in the command tree.
+func deprecatedAlias(cmd *cobra.Command, use, replacement string) *cobra.Command {
+	cmd.Use = use
+	cmd.Deprecated = fmt.Sprintf("use 'codeowners %s' instead", replacement)
+	return cmd
+}
+
 func validateCmd(cfg *config.Config) *cobra.Command {
 
 	var validateCmd = &cobra.Command{
 		Use:   "validate",
 		Short: "Validate a CODEOWNERS file",
 
+		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			log := logrus.New()
+			runValidation(cmd, cfg)
+		},
+	}
+	addValidateFlags(validateCmd)
+	addValidationCacheFlags(validateCmd)
+	return validateCmd
+}
 
-			// init checks
-			checks, err := load.Checks(cmd.Context(), cfg)
-			exitOnError(err)
+func addValidationCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-skip-cache", false, "Always run the validation, even if nothing changed since the last successful run")
+	cmd.Flags().String("skip-cache-dir", "", "Directory where fingerprints of successfully validated repositories are stored. Defaults to the user cache directory")
+}
 
-			// init codeowners entries
-			codeownersEntries, err := codeowners.NewFromPath(cfg.RepositoryPath)
-			exitOnError(err)
+// runValidation validates the repository and exits with the status code of the validation.
+func runValidation(cmd *cobra.Command, cfg *config.Config) {
+	log := redact.NewLogger()
 
-			// run check runner
-			absRepoPath, err := filepath.Abs(cfg.RepositoryPath)
-			exitOnError(err)
+	cache, fp := verdictCache(log, cfg)
+	if fp != "" && cache.Passed(fp) {
+		log.Infof("Skipping validation, nothing changed since the last successful run (fingerprint %s)", fp)
+		return
+	}
 
-			checkRunner := runner.NewCheckRunner(log, codeownersEntries, absRepoPath, cfg.CheckFailureLevel, checks...)
-			checkRunner.Run(cmd.Context())
+	checkRunner, err := validate(cmd.Context(), log, cfg, githubCacheOpts(log, cfg)...)
+	exitOnError(err)
 
-			if cmd.Context().Err() != nil {
-				log.Error("Application was interrupted by operating system")
-				os.Exit(2)
-			}
-			if checkRunner.ShouldExitWithCheckFailure() {
-				os.Exit(3)
-			}
-		},
+	if cmd.Context().Err() != nil {
+		log.Error("Application was interrupted by operating system")
+		os.Exit(2)
+	}
+	if code := checkRunner.ExitCode(); code != runner.ExitCodeOK {
+		os.Exit(code)
+	}
+
+	if fp != "" {
+		if err := cache.MarkPassed(fp); err != nil {
+			log.WithError(err).Warn("Cannot cache the validation verdict")
+		}
 	}
-	addValidateFlags(validateCmd)
-	return validateCmd
 }
 
 func addValidateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("approval-checker-base-ref", "origin/main", "The git reference against which the changes of protected CODEOWNERS entries are computed")
+	cmd.Flags().Int("approval-checker-pull-request-number", 0, "The number of the pull request which reviews are verified by the approvals checker")
+	cmd.Flags().String("baseline", "", "Path to the baseline file with known issues, which are not reported and don't fail the validation. Defaults to "+baseline.DefaultFilename+" in the repository root, if it exists")
+	cmd.Flags().Float64("broad-ownership-checker-threshold", check.DefaultBroadOwnershipThreshold, "Share of files owned per individual, i.e. the breadth of a root-level pattern divided by the number of its owners, above which the broad-ownership checker reports the pattern")
 	cmd.Flags().StringSlice("checks", nil, "List of checks to be executed")
-	//cmd.Flags().Var(&severity, "check-failure-level", "Defines the level on which the application should treat check issues as failures")
+	cmd.Flags().String("codeowners-flavor", flavorAuto, "Dialect in which the CODEOWNERS file is parsed. Possible values: auto, github, gitlab. The auto flavor parses GitLab sections if the file has section headers, or if GitLab is the only configured provider")
+	cmd.Flags().String("check-failure-level", "warning", "Defines the level on which the application should treat check issues as failures. Possible values: error, warning")
+	cmd.Flags().String("diff-base-ref", "", "The git reference against which the pull request changes are computed for diff-aware checks, e.g. origin/main")
+	cmd.Flags().StringSlice("escalate-paths", nil, "The comma-separated list of repository paths, e.g. /security/**, which issues are always reported as errors, regardless of the check severity")
+	cmd.Flags().String("events", "", "Emit machine-readable progress events of each check in real time to the --events-file. Possible values: ndjson")
+	cmd.Flags().String("events-file", "", "Path to the file where progress events are written, or '-' for stdout. Required with --events")
 	cmd.Flags().String("experimental-checks", "", "The comma-separated list of experimental checks that should be executed")
-	cmd.Flags().String("github-access-token", "", "GitHub access token")
-	cmd.Flags().String("github-base-url", "https://api.github.com/", "GitHub base URL for API requests")
-	cmd.Flags().String("github-upload-url", "https://uploads.github.com/", "GitHub upload URL for uploading files")
-	cmd.Flags().String("github-app-id", "", "Github App ID for authentication")
-	cmd.Flags().String("github-app-installation-id", "", "Github App Installation ID")
-	cmd.Flags().String("github-app-private-key", "", "Github App private key in PEM format")
+	cmd.Flags().Duration("expiration-checker-warn-before", 14*24*time.Hour, "Specifies how long before the expiration date the ownership is reported as expiring soon")
+	cmd.Flags().Bool("fail-on-new-only", false, "Report issues recorded in the baseline too, marked as baselined, but fail only on new issues")
+	cmd.Flags().Bool("fix", false, "Rewrite the CODEOWNERS file as UTF-8 without a byte order mark and with LF line endings before running the checks")
+	cmd.Flags().Bool("fix-json", false, "Print suggested fixes of the reported issues as a JSON array instead of the human-readable report")
+	cmd.Flags().String("format", "text", "Format of the checks results. Possible values: text, json, canonical-json, sarif. The canonical JSON report is followed by its SHA-256 hash printed on stderr. The SARIF log can be uploaded to GitHub code scanning")
+	cmd.Flags().String("git-backend", git.BackendAuto, "Backend which reads git repositories. Possible values: auto, exec, go-git. The auto backend executes the git binary if it's found in PATH, and uses the embedded go-git otherwise")
+	addGitHubFlags(cmd)
+	addGitHubCacheFlags(cmd)
+	cmd.Flags().String("github-errors-checker-ref", "", "The branch, tag, or commit which CODEOWNERS errors are fetched from GitHub by the github-errors checker. Defaults to the default branch")
+	cmd.Flags().StringSlice("governance-checker-paths", []string{".github/"}, "The comma-separated list of repository paths which must be owned by the governance team, in addition to the CODEOWNERS file")
+	cmd.Flags().String("governance-checker-team", "", "The team which must own the CODEOWNERS file and the governed paths")
+	cmd.Flags().Bool("group-issues", false, "Collapse issues reported by multiple checks for the same CODEOWNERS line into a single issue")
+	addPathScopeFlags(cmd)
+	cmd.Flags().String("identity-checker-source", "", "Path or URL of the identity source used by the identities checker")
+	cmd.Flags().String("identity-checker-source-token", "", "Bearer token used to fetch identities from an HTTP identity source, or the bind password of an LDAP identity source")
+	cmd.Flags().String("identity-checker-source-type", "csv", "Format of the identity source. Possible values: csv, json, scim, ldap")
+	cmd.Flags().String("isolation", isolation.None, "Isolation of checks that execute git commands on the repository. Possible values: none, docker")
+	cmd.Flags().String("isolation-image", isolation.DefaultImage, "Image used to execute isolated checks")
+	cmd.Flags().String("jira-checker-base-url", "", "Base URL of the JIRA instance used by the jira checker, e.g. https://example.atlassian.net")
+	cmd.Flags().String("jira-checker-mapping", "", "Path to the file which maps JIRA components to teams and repository paths, used by the jira checker")
+	cmd.Flags().String("jira-checker-token", "", "JIRA API token or personal access token used by the jira checker")
+	cmd.Flags().String("jira-checker-user", "", "JIRA user email. If set, the token is sent with the basic authentication, as required by JIRA Cloud")
+	cmd.Flags().String("labeler-checker-file", labeler.DefaultFile, "Path of the GitHub labeler configuration relative to the repository root, verified by the labeler checker")
+	cmd.Flags().String("labeler-checker-label-prefix", labeler.DefaultLabelPrefix, "Prefix of team labels verified by the labeler checker. Labels without the prefix are ignored")
+	cmd.Flags().Bool("no-baseline", false, "Report all issues, ignoring the baseline file")
+	cmd.Flags().Bool("no-git", false, "Validate a plain directory which is not a git repository. Files are listed by walking the filesystem, honoring .gitignore files")
+	cmd.Flags().Bool("no-network", false, "Fail if any check attempts an outbound connection. Checks which require network access are disabled by default")
+	cmd.Flags().StringSlice("not-owned-checker-ignore-sources", []string{"gitignore", "info-exclude", "global"}, "The comma-separated list of ignore rule sources which tracked files are treated as owned by the not-owned-checker. Possible values: gitignore, info-exclude, global")
+	cmd.Flags().Bool("not-owned-checker-skip-generated", false, "Exclude files marked as linguist-generated or linguist-vendored in .gitattributes from the not-owned-checker")
 	cmd.Flags().StringSlice("not-owned-checker-skip-patterns", nil, "The comma-separated list of patterns that should be ignored by not-owned-checker")
 	cmd.Flags().StringSlice("not-owned-checker-subdirectories", nil, "The comma-separated list of subdirectories to check in not-owned-checker")
+	cmd.Flags().Bool("not-owned-checker-suggest-owners", false, "Suggest owners of not owned files based on owners of nearby files and recent committers")
 	cmd.Flags().Bool("not-owned-checker-trust-workspace", false, "Specifies whether the repository path should be marked as safe")
+	cmd.Flags().String("oncall-checker-mapping", "", "Path to the file which maps owners to escalation policies, used by the oncall checker")
+	cmd.Flags().StringSlice("providers", nil, "The comma-separated list of providers which CODEOWNERS dialects are validated, e.g. github,gitlab. Defaults to github")
+	cmd.Flags().String("record", "", "Record interactions with the GitHub and other external APIs into a given file, with credentials scrubbed, e.g. to attach it to a bug report")
+	cmd.Flags().String("replay", "", "Serve requests to the GitHub and other external APIs from a file saved with --record instead of sending them, e.g. to reproduce a check failure offline")
 	cmd.Flags().String("repository-path", "", "Path to your repository on your local machine")
+	cmd.Flags().StringSlice("module-boundaries-checker-ecosystems", []string{"go"}, "The comma-separated list of ecosystems which module roots must have an explicit owner. Supported: go, bazel, npm")
+	cmd.Flags().StringSlice("stewardship-checker-extensions", nil, "The comma-separated list of file extensions which must be owned by the stewardship team, e.g. .pb.go,.jar")
+	cmd.Flags().Int64("stewardship-checker-size-threshold", 1<<20, "Size in bytes above which files must be owned by the stewardship team. Zero disables the size check")
+	cmd.Flags().String("stewardship-checker-team", "", "The team which must own binary and large files")
+	cmd.Flags().String("summary", "default", "Details of the summary printed after the checks results. Possible values: default, verbose. The verbose summary includes the wall time, external API calls, and cache hit rate of each check")
+	cmd.Flags().String("template-values", "", "Path to the YAML or JSON file with values of {{PLACEHOLDER}} placeholders used in the CODEOWNERS template. Placeholders are resolved before the validation")
+	addVCSFlags(cmd)
+	cmd.Flags().String("tree", tree.Git, "Backend which lists repository files. Possible values: git, fs, perforce, svn. Non-git backends support only checks which match files against patterns")
+	cmd.Flags().StringSlice("virtual-owners", nil, "Virtual owner groups in form 'NAME = OWNER, OWNER', e.g. '@virtual/payments = @alice, @org/payments-core'. Members of a group are accepted in place of the group by the governance, stewardship, jira, and approvals checkers")
 	cmd.Flags().String("owner-checker-repository", "", "The owner and repository name separated by slash")
 	cmd.Flags().StringSlice("owner-checker-ignored-owners", []string{"@ghost"}, "The comma-separated list of owners that should not be validated")
 	cmd.Flags().Bool("owner-checker-allow-unowned-patterns", true, "Specifies whether CODEOWNERS may have unowned files")
 	cmd.Flags().Bool("owner-checker-owners-must-be-teams", false, "Specifies whether only teams are allowed as owners of files")
+	cmd.Flags().String("owner-checker-platform", "", "Platform on which owners are validated by the owners checker, if it differs from the VCS provider. Possible values: github, gitlab, bitbucket, gitea. Other platforms than GitHub use the VCS token and base URL")
+	cmd.Flags().Duration("ownership-sla-checker-grace-period", check.DefaultOwnershipSLAGracePeriod, "The time new directories have to gain owners, e.g. 720h")
 }
 
-func exitOnError(err error) {
+// validate runs the configured checks against the repository.
+func validate(ctx context.Context, log logrus.FieldLogger, cfg *config.Config, opts ...github.ClientOption) (*runner.CheckRunner, error) {
+	var events runner.EventPrinter
+	switch cfg.Events {
+	case "":
+	case "ndjson":
+		// events are not written to stderr, as consumers would have to filter out log messages
+		switch cfg.EventsFile {
+		case "":
+			return nil, &api.ConfigError{Field: "EVENTS_FILE", Err: errors.New("must be set when EVENTS is enabled, use '-' for stdout")}
+		case "-":
+			events = printer.NewEventPrinter(os.Stdout)
+		default:
+			f, err := os.Create(cfg.EventsFile)
+			if err != nil {
+				return nil, &api.ConfigError{Field: "EVENTS_FILE", Err: errors.Wrap(err, "while creating events file")}
+			}
+			defer f.Close()
+			events = printer.NewEventPrinter(f)
+		}
+	default:
+		return nil, &api.ConfigError{Field: "EVENTS", Err: fmt.Errorf("unknown events format %q, possible values: ndjson", cfg.Events)}
+	}
+	switch cfg.Format {
+	case "", "text":
+	case "json", "canonical-json", "sarif":
+		if cfg.FixJSON {
+			return nil, &api.ConfigError{Field: "FORMAT", Err: fmt.Errorf("the %s format cannot be used together with FIX_JSON", cfg.Format)}
+		}
+	default:
+		return nil, &api.ConfigError{Field: "FORMAT", Err: fmt.Errorf("unknown format %q, possible values: text, json, canonical-json, sarif", cfg.Format)}
+	}
+	switch cfg.Summary {
+	case "", "default", "verbose":
+	default:
+		return nil, &api.ConfigError{Field: "SUMMARY", Err: fmt.Errorf("unknown summary %q, possible values: default, verbose", cfg.Summary)}
+	}
+
+	if cfg.NoNetwork {
+		netguard.Enforce()
+	}
+	switch {
+	case cfg.Record != "" && cfg.Replay != "":
+		return nil, &api.ConfigError{Field: "RECORD", Err: errors.New("cannot be used together with REPLAY")}
+	case cfg.Replay != "":
+		if err := recording.Replay(cfg.Replay); err != nil {
+			return nil, &api.ConfigError{Field: "REPLAY", Err: err}
+		}
+		// recorded requests are not sent, so credentials are not needed to reproduce the run
+		if cfg.GithubAccessToken == "" && cfg.GithubAppID == 0 {
+			cfg.GithubAccessToken = "replay"
+		}
+	case cfg.Record != "":
+		recorder := recording.Record()
+		defer func() {
+			if err := recorder.Fixture().Save(cfg.Record); err != nil {
+				log.WithError(err).Warn("Cannot save the recording")
+			}
+		}()
+	}
+	if !cfg.NoGit {
+		detectRepository(log, cfg)
+	}
+
+	backend := cfg.Tree
+	if cfg.NoGit && (backend == "" || backend == tree.Git) {
+		backend = tree.FS
+	}
+	repoTree, err := tree.New(backend)
+	if err != nil {
+		return nil, &api.ConfigError{Field: "TREE", Err: err}
+	}
+
+	flavor, err := codeownersFlavor(log, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := pathScope(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	known, err := loadBaseline(log, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// init checks
+	checks, err := load.Checks(ctx, cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Fix {
+		if err := normalizeCodeowners(log, cfg.RepositoryPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// init codeowners entries
+	codeownersEntries, err := loadCodeowners(cfg, flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	// run check runner
+	absRepoPath, err := filepath.Abs(cfg.RepositoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var out runner.Printer = printer.NewProgressPrinter()
+	switch {
+	case cfg.FixJSON:
+		out = &printer.FixJSONPrinter{}
+	case cfg.Format == "json":
+		out = printer.NewJSONPrinter(os.Stdout, codeownersEntries)
+	case cfg.Format == "canonical-json":
+		out = printer.NewCanonicalJSONPrinter(os.Stdout, os.Stderr, codeownersEntries)
+	case cfg.Format == "sarif":
+		artifact, err := codeownersArtifact(absRepoPath)
+		if err != nil {
+			return nil, err
+		}
+		out = printer.NewSARIFPrinter(os.Stdout, codeownersEntries, artifact)
+	}
+	if cfg.GroupIssues {
+		out = printer.NewGroupingPrinter(out)
+	}
+
+	checkRunner := runner.NewCheckRunner(log, codeownersEntries, absRepoPath, cfg.CheckFailureLevel, checks...).
+		WithPrinter(out)
+	if events != nil {
+		checkRunner.WithEvents(events)
+	}
+	if cfg.Summary == "verbose" {
+		checkRunner.WithVerboseSummary()
+	}
+	if len(cfg.EscalatePaths) > 0 {
+		checkRunner.WithEscalatedPaths(cfg.EscalatePaths)
+	}
+	if cfg.DiffBaseRef != "" {
+		checkRunner.WithDiffBaseRef(cfg.DiffBaseRef)
+	}
+	if scope != nil {
+		checkRunner.WithScope(scope)
+	}
+	if known != nil {
+		checkRunner.WithBaseline(known)
+	}
+	if cfg.FailOnNewOnly {
+		checkRunner.WithFailOnNewOnly()
+	}
+	if repoTree != nil {
+		checkRunner.WithTree(repoTree).WithNonGit()
+	}
+	checkRunner.Run(ctx)
+
+	return checkRunner, nil
+}
+
+// codeownersArtifact returns the path of the CODEOWNERS file relative to the repository root,
+// which locates issues in the SARIF log.
+func codeownersArtifact(repoPath string) (string, error) {
+	file, err := codeowners.FindCodeownersFile(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return codeowners.RelPath(repoPath, file)
+}
+
+// flavorAuto selects the CODEOWNERS dialect based on the file content and the configured providers.
+const flavorAuto = "auto"
+
+// codeownersFlavor returns the dialect in which the CODEOWNERS file is parsed. The GitLab flavor implies
+// the GitLab provider if no providers are configured, so GitLab owners are accepted by the checks.
+func codeownersFlavor(log logrus.FieldLogger, cfg *config.Config) (codeowners.Provider, error) {
+	var flavor codeowners.Provider
+	switch strings.ToLower(cfg.CodeownersFlavor) {
+	case "", flavorAuto:
+		if len(cfg.Providers) == 1 && strings.EqualFold(cfg.Providers[0], string(codeowners.GitLab)) {
+			flavor = codeowners.GitLab
+			break
+		}
+		path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+		if err != nil {
+			return "", err
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		flavor = codeowners.DetectProvider(raw)
+	case string(codeowners.GitHub), string(codeowners.GitLab):
+		flavor = codeowners.Provider(strings.ToLower(cfg.CodeownersFlavor))
+	default:
+		return "", &api.ConfigError{
+			Field: "CODEOWNERS_FLAVOR",
+			Err:   fmt.Errorf("unknown flavor %q, possible values: %s, %s, %s", cfg.CodeownersFlavor, flavorAuto, codeowners.GitHub, codeowners.GitLab),
+		}
+	}
+
+	if flavor == codeowners.GitLab && len(cfg.Providers) == 0 {
+		log.Info("Parsing CODEOWNERS in the GitLab flavor, with sections")
+		cfg.Providers = []string{string(codeowners.GitLab)}
+	}
+	return flavor, nil
+}
+
+// loadCodeowners parses the CODEOWNERS file of the repository in a given flavor. In the template mode,
+// placeholders are resolved first, so the line numbers of the template and the rendered file are the same.
+func loadCodeowners(cfg *config.Config, flavor codeowners.Provider) ([]codeowners.Entry, error) {
+	path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
 	if err != nil {
-		logrus.Fatal(err)
+		return nil, err
+	}
+
+	if cfg.TemplateValues != "" {
+		values, err := placeholder.LoadValues(cfg.TemplateValues)
+		if err != nil {
+			return nil, &api.ConfigError{Field: "TEMPLATE_VALUES", Err: err}
+		}
+		raw, err = placeholder.Render(raw, values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while rendering CODEOWNERS template %s", path)
+		}
+	}
+	return codeowners.ParseCodeownersAs(bytes.NewReader(raw), flavor)
+}
+
+// normalizeCodeowners rewrites the CODEOWNERS file of the repository as UTF-8 without a byte order mark
+// and with LF line endings. The file is not touched if it's already normalized.
+func normalizeCodeowners(log logrus.FieldLogger, repoPath string) error {
+	path, err := codeowners.FindCodeownersFile(repoPath)
+	if err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if codeowners.InspectEncoding(raw).IsNormalized() {
+		return nil
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, codeowners.NormalizeEncoding(raw), fi.Mode().Perm()); err != nil {
+		return errors.Wrapf(err, "while normalizing %s", path)
+	}
+	log.Infof("Normalized the encoding and line endings of %s", path)
+	return nil
+}
+
+// verdictCache returns the cache of successful validations together with the repository fingerprint.
+// The fingerprint is empty if the cache should not be used.
+func verdictCache(log logrus.FieldLogger, cfg *config.Config) (*fingerprint.Cache, string) {
+	// fixes and recordings are made only when the checks are executed,
+	// and plain directories have no git tree to fingerprint
+	if cfg.NoSkipCache || cfg.Fix || cfg.FixJSON || cfg.NoGit || cfg.Record != "" || cfg.Replay != "" {
+		return nil, ""
+	}
+
+	dir := cfg.SkipCacheDir
+	if dir == "" {
+		var err error
+		if dir, err = fingerprint.DefaultCacheDir(); err != nil {
+			log.WithError(err).Debug("Cannot find user cache directory")
+			return nil, ""
+		}
+	}
+
+	repoPath := cfg.RepositoryPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	// the detected repository is a part of the configuration fingerprint
+	detectRepository(log, cfg)
+	fp, err := fingerprint.Compute(repoPath, *cfg, time.Now())
+	if err != nil {
+		log.WithError(err).Debug("Cannot compute repository fingerprint")
+		return nil, ""
+	}
+
+	return fingerprint.NewCache(dir), fp
+}
+
+// detectRepository derives the repository and provider from the origin remote if they are not configured.
+func detectRepository(log logrus.FieldLogger, cfg *config.Config) {
+	if cfg.OwnerCheckerRepository != "" {
+		return
+	}
+
+	repoPath := cfg.RepositoryPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	remote, err := git.DetectRemote(repoPath, "origin")
+	if err != nil {
+		log.WithError(err).Debug("Cannot detect repository from git remote")
+		return
+	}
+
+	cfg.OwnerCheckerRepository = remote.Repository()
+	// GitHub is used by default, so only other providers need to be set explicitly
+	if len(cfg.Providers) == 0 && remote.Provider != codeowners.GitHub {
+		cfg.Providers = []string{string(remote.Provider)}
+	}
+	log.Infof("Detected %s repository %q from the origin remote", remote.Provider, cfg.OwnerCheckerRepository)
+
+	// self-hosted instances serve the API on the same host, unless other API URLs are set explicitly
+	switch {
+	case remote.Provider == codeowners.GitHub && !strings.EqualFold(remote.Host, "github.com") && cfg.GithubBaseURL == defaultGitHubBaseURL:
+		// the GitHub client adds the 'api/v3/' and 'api/uploads/' paths of GitHub Enterprise Server
+		cfg.GithubBaseURL = "https://" + remote.Host + "/"
+		if cfg.GithubUploadURL == defaultGitHubUploadURL {
+			cfg.GithubUploadURL = cfg.GithubBaseURL
+		}
+		log.Infof("Using GitHub Enterprise Server API at %s", cfg.GithubBaseURL)
+	case remote.Provider == codeowners.GitLab && !strings.EqualFold(remote.Host, "gitlab.com") && cfg.VCSBaseURL == "":
+		cfg.VCSBaseURL = "https://" + remote.Host + "/api/v4"
+		log.Infof("Using GitLab API at %s", cfg.VCSBaseURL)
 	}
 }
 
+const (
+	defaultGitHubBaseURL   = "https://api.github.com/"
+	defaultGitHubUploadURL = "https://uploads.github.com/"
+)
+
+func addGitHubFlags(cmd *cobra.Command) {
+	cmd.Flags().String("github-access-token", "", "GitHub access token")
+	cmd.Flags().String("github-base-url", defaultGitHubBaseURL, "GitHub base URL for API requests. Detected from the origin remote of GitHub Enterprise Server repositories")
+	cmd.Flags().String("github-upload-url", defaultGitHubUploadURL, "GitHub upload URL for uploading files")
+	cmd.Flags().String("github-app-id", "", "Github App ID for authentication")
+	cmd.Flags().String("github-app-installation-id", "", "Github App Installation ID")
+	cmd.Flags().String("github-app-private-key", "", "Github App private key in PEM format")
+	addHTTPFlags(cmd)
+}
+
+// addHTTPFlags adds flags shared by clients of GitHub and other VCS providers.
+func addHTTPFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("http-headers", nil, "The comma-separated list of headers in form 'Name: value' sent with each request to GitHub and other VCS providers, e.g. 'X-Org-Token: secret' required by an API gateway")
+	cmd.Flags().String("http-user-agent", "", "User-Agent sent with each request to GitHub and other VCS providers. Defaults to the one of the client library")
+}
+
+// loadBaseline returns known issues from the baseline file, or nil if no baseline is used.
+func loadBaseline(log logrus.FieldLogger, cfg *config.Config) (*baseline.Baseline, error) {
+	if cfg.NoBaseline {
+		return nil, nil
+	}
+	path, err := baseline.Find(cfg.RepositoryPath, cfg.Baseline)
+	if err != nil {
+		return nil, &api.ConfigError{Field: "BASELINE", Err: err}
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	log.Infof("Known issues are read from the baseline %s", path)
+	known, err := baseline.Load(path)
+	if err != nil {
+		return nil, &api.ConfigError{Field: "BASELINE", Err: err}
+	}
+	return known, nil
+}
+
+// addPathScopeFlags adds flags which limit files validated by file-oriented checks to an area of the repository.
+func addPathScopeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("include-path", nil, "The comma-separated list of CODEOWNERS patterns, e.g. /services/payments/, which limit files validated by file-oriented checks, such as not-owned and files, to the matching ones. Defaults to all files")
+	cmd.Flags().StringSlice("exclude-path", nil, "The comma-separated list of CODEOWNERS patterns, e.g. /vendor/, which files are not validated by file-oriented checks. Exclusions take precedence over INCLUDE_PATH")
+}
+
+// pathScope returns the scope of file-oriented checks, or nil if all files are validated.
+func pathScope(cfg *config.Config) (*api.Scope, error) {
+	scope, err := api.NewScope(cfg.IncludePaths, cfg.ExcludePaths)
+	if err != nil {
+		return nil, &api.ConfigError{Field: "INCLUDE_PATH", Err: err}
+	}
+	return scope, nil
+}
+
+func addVCSFlags(cmd *cobra.Command) {
+	cmd.Flags().String("vcs-provider", provider.GitHubName, "VCS provider which hosts the repository, used by the owners checker. Possible values: github, gitlab, bitbucket, gitea. GitHub uses the GitHub flags, other providers the VCS token")
+	cmd.Flags().String("vcs-base-url", "", "API base URL of the VCS provider other than GitHub, e.g. https://gitlab.example.com/api/v4. Defaults to the public instance of the provider")
+	cmd.Flags().String("vcs-token", "", "Access token of the VCS provider other than GitHub")
+}
+
+func addGitHubCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().String("github-cache-file", "", "File with GitHub API responses prefetched by the 'cache warm' command, shared by parallel validations")
+	cmd.Flags().Duration("github-cache-max-age", time.Hour, "Maximum age of the GitHub cache file. Older cache is ignored. Zero disables the expiration")
+}
+
+// githubCacheOpts returns the client option which serves GitHub responses from the cache file, if configured.
+func githubCacheOpts(log logrus.FieldLogger, cfg *config.Config) []github.ClientOption {
+	cache := loadGitHubCache(log, cfg)
+	if cache == nil {
+		return nil
+	}
+	return []github.ClientOption{github.WithResponseCache(cache)}
+}
+
+// loadGitHubCache returns the cache loaded from the cache file, or nil if it's not configured.
+// The validation continues without the cache if it cannot be loaded, e.g. it expired.
+func loadGitHubCache(log logrus.FieldLogger, cfg *config.Config) *github.ResponseCache {
+	if cfg.GithubCacheFile == "" {
+		return nil
+	}
+	cache, err := github.LoadResponseCache(cfg.GithubCacheFile, cfg.GithubCacheMaxAge)
+	if err != nil {
+		log.WithError(err).Warn("Ignoring GitHub cache file")
+		return nil
+	}
+	return cache
+}
+
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+	log := redact.NewLogger()
+	if hint := api.Hint(err); hint != "" {
+		log.WithField("hint", hint).Fatal(err)
+	}
+	log.Fatal(err)
+}
+
 func InitializeConfig(cmd *cobra.Command, cfg *config.Config, args []string) error {
 	v := viper.New()
 
 	// Look for config file, ignore if missing
-	v.SetConfigName(config.DefaultConfigFilename)
-	v.AddConfigPath(".")
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return err
-		}
+	origins, err := readConfigFile(cmd, v)
+	if err != nil {
+		return err
 	}
+	fileKeys := v.AllKeys()
+
 	// Look for environment variables
 	v.SetEnvPrefix(config.EnvPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	v.AutomaticEnv()
+	if err := bindDeprecatedEnvs(v); err != nil {
+		return err
+	}
+
+	if err := verifyKnownSettings(cmd, v, fileKeys); err != nil {
+		return err
+	}
+
+	// Profile settings override the config file, but not the environment variables and flags
+	if err := applyProfile(cmd, v); err != nil {
+		return err
+	}
 
 	// Bind flags to the configuration struct
-	bindFlags(cmd, v)
+	if err := bindFlags(cmd, v, origins); err != nil {
+		return err
+	}
 
 	// Unmarshal the configuration into the struct
-	if err := v.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(cfg, viper.DecodeHook(config.DecodeHook())); err != nil {
 		return err
 	}
-	cfg.CheckFailureLevel = api.Warning
+	var checkDeps, expDeps []config.Deprecation
+	cfg.Checks, checkDeps = config.Deprecated.MigrateChecks(cfg.Checks)
+	cfg.ExperimentalChecks, expDeps = config.Deprecated.MigrateChecks(cfg.ExperimentalChecks)
+	warnDeprecated(append(checkDeps, expDeps...))
+
+	// commands without the check-failure-level flag
+	if cfg.CheckFailureLevel == 0 {
+		cfg.CheckFailureLevel = api.Warning
+	}
+
+	// scrub configured credentials from logs and error messages
+	redact.AddSecrets(cfg.GithubAccessToken, cfg.GithubAppPrivateKey, cfg.IdentityCheckerSourceToken, cfg.JiraCheckerToken, cfg.VCSToken)
+	// headers required by API gateways usually carry credentials
+	headers, err := httpheader.Parse(cfg.HTTPHeaders)
+	if err != nil {
+		return &api.ConfigError{Field: "HTTP_HEADERS", Err: err}
+	}
+	for _, values := range headers {
+		redact.AddSecrets(values...)
+	}
+
+	if err := git.SetBackend(cfg.GitBackend); err != nil {
+		return &api.ConfigError{Field: "GIT_BACKEND", Err: err}
+	}
 
 	return nil
 }
 
-// Bind each cobra flag to its associated viper configuration environment variable
-func bindFlags(cmd *cobra.Command, v *viper.Viper) {
+// readConfigFile loads the config file into the viper with deprecated names migrated and per-check blocks
+// expanded. It returns key paths of expanded options in the file, see config.ExpandCheckBlocks.
+func readConfigFile(cmd *cobra.Command, v *viper.Viper) (map[string]string, error) {
+	file, err := findConfigFile(cmd)
+	if err != nil || file == "" {
+		return nil, err
+	}
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading config file")
+	}
+
+	migrated, deps, err := config.Deprecated.MigrateYAML(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(deps) > 0 {
+		warnDeprecated(deps)
+		redact.NewLogger().Warnf("Run 'codeowners config migrate' to update the %s file", file)
+	}
+
+	expanded, origins, err := config.ExpandCheckBlocks(migrated, knownSettings(cmd))
+	if err != nil {
+		return nil, &api.ConfigError{Field: "CONFIG", Err: errors.Wrapf(err, "in %s", file)}
+	}
+
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(expanded)); err != nil {
+		return nil, errors.Wrapf(err, "while reading config file %s", file)
+	}
+	return origins, nil
+}
+
+// findConfigFile returns the path of the config file set with the flag or the environment variable. Otherwise,
+// the default config file is searched for in the current directory, and then in the repository root. The path
+// is empty if the file is not found.
+func findConfigFile(cmd *cobra.Command) (string, error) {
+	file, _ := os.LookupEnv(config.EnvName(config.ConfigFileKey))
+	if f := cmd.Flags().Lookup(config.ConfigFileKey); f != nil && f.Changed {
+		file = f.Value.String()
+	}
+	if file != "" {
+		if _, err := os.Stat(file); err != nil {
+			return "", &api.ConfigError{Field: "CONFIG", Err: err}
+		}
+		return file, nil
+	}
+
+	dirs := []string{"."}
+	repoPath, _ := os.LookupEnv(config.EnvName("repository-path"))
+	if f := cmd.Flags().Lookup("repository-path"); f != nil && (f.Changed || repoPath == "") {
+		repoPath = f.Value.String()
+	}
+	if repoPath != "" {
+		dirs = append(dirs, repoPath)
+	}
+	if root, found := repositoryRoot("."); found {
+		dirs = append(dirs, root)
+	}
+
+	name := strings.TrimSuffix(config.DefaultConfigFilename, filepath.Ext(config.DefaultConfigFilename))
+	for _, dir := range dirs {
+		for _, ext := range []string{".yaml", ".yml", ".json"} {
+			path := filepath.Join(dir, name+ext)
+			if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+				return path, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// repositoryRoot returns the closest directory with the git metadata, starting from a given one.
+func repositoryRoot(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil {
+			return abs, true
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", false
+		}
+		abs = parent
+	}
+}
+
+// bindDeprecatedEnvs resolves options also from the environment variables with their previous names.
+func bindDeprecatedEnvs(v *viper.Viper) error {
+	var deps []config.Deprecation
+	for _, r := range config.Deprecated.Options {
+		if err := v.BindEnv(r.New, config.EnvName(r.New), config.EnvName(r.Old)); err != nil {
+			return err
+		}
+		if _, found := os.LookupEnv(config.EnvName(r.Old)); found {
+			deps = append(deps, config.Deprecation{Kind: config.KindOption, Old: config.EnvName(r.Old), New: config.EnvName(r.New)})
+		}
+	}
+	warnDeprecated(deps)
+	return nil
+}
+
+// warnDeprecated logs each usage of a deprecated name with structured fields, so they can be collected from CI logs.
+func warnDeprecated(deps []config.Deprecation) {
+	log := redact.NewLogger()
+	for _, d := range deps {
+		log.WithFields(logrus.Fields{
+			"kind":        d.Kind,
+			"deprecated":  d.Old,
+			"replacement": d.New,
+		}).Warn(d.String())
+	}
+}
+
+// verifyKnownSettings reports config file keys and environment variables which don't match any option, e.g. typos
+// such as CODEOWNERS_CHEKS. They are logged as warnings, or rejected in the strict mode.
+func verifyKnownSettings(cmd *cobra.Command, v *viper.Viper, fileKeys []string) error {
+	unknown := config.FindUnknown(knownSettings(cmd), fileKeys, os.Environ())
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	strict := v.GetBool(config.StrictKey)
+	if f := cmd.Flags().Lookup(config.StrictKey); f != nil && f.Changed {
+		strict = f.Value.String() == "true"
+	}
+
+	msgs := make([]string, 0, len(unknown))
+	for _, u := range unknown {
+		msgs = append(msgs, u.String())
+	}
+	if strict {
+		return &api.ConfigError{Field: "STRICT_CONFIG", Err: errors.New(strings.Join(msgs, "; "))}
+	}
+
+	log := redact.NewLogger()
+	for _, msg := range msgs {
+		log.Warnf("Ignoring %s", msg)
+	}
+	return nil
+}
+
+// knownSettings returns keys of all options. The config file is shared by all commands, so flags of other
+// commands are valid too.
+func knownSettings(cmd *cobra.Command) []string {
+	var flags []string
+	var collect func(c *cobra.Command)
+	collect = func(c *cobra.Command) {
+		c.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			flags = append(flags, f.Name)
+		})
+		for _, sub := range c.Commands() {
+			collect(sub)
+		}
+	}
+	collect(cmd.Root())
+	return config.KnownKeys(flags...)
+}
+
+// applyProfile merges settings of the selected profile into the config file layer.
+func applyProfile(cmd *cobra.Command, v *viper.Viper) error {
+	name := v.GetString("profile")
+	if f := cmd.Flags().Lookup("profile"); f != nil && f.Changed {
+		name = f.Value.String()
+	}
+	if name == "" {
+		return nil
+	}
+
+	settings, err := config.ResolveProfile(name, v.GetStringMap(config.ProfilesKey))
+	if err != nil {
+		return err
+	}
+
+	return v.MergeConfigMap(settings)
+}
+
+// Bind each cobra flag to its associated viper configuration environment variable. Invalid values are reported
+// with the environment variable or the config file key they come from, see readConfigFile for the origins.
+func bindFlags(cmd *cobra.Command, v *viper.Viper, origins map[string]string) error {
+	var errs []string
 	cmd.Flags().VisitAll(func(f *pflag.Flag) {
 		configName := f.Name
 		if !f.Changed && v.IsSet(configName) {
 			val := v.Get(configName)
-			cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))
+			// lists from the config file are converted to the comma-separated form expected by the flags
+			if list, ok := val.([]interface{}); ok {
+				items := make([]string, 0, len(list))
+				for _, item := range list {
+					items = append(items, fmt.Sprintf("%v", item))
+				}
+				val = strings.Join(items, ",")
+			}
+			if err := cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val)); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid value %q of %s: %v", val, settingSource(configName, origins), err))
+			}
 		}
 		v.BindPFlag(configName, f)
 	})
+	if len(errs) > 0 {
+		return &api.ConfigError{Field: "CONFIG", Err: errors.New(strings.Join(errs, "; "))}
+	}
+	return nil
+}
+
+// settingSource describes where the value of a given option is set, e.g. `config file key "checks.notOwned.skipPatterns"`.
+// Environment variables take precedence over the config file.
+func settingSource(key string, origins map[string]string) string {
+	if env := config.EnvName(key); os.Getenv(env) != "" {
+		return fmt.Sprintf("%s %q", config.SourceEnvironment, env)
+	}
+	if origin, found := origins[key]; found {
+		key = origin
+	}
+	return fmt.Sprintf("%s %q", config.SourceConfigFile, key)
 }
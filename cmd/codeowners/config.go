@@ -0,0 +1,68 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/textdiff"
+)
+
+func configCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration file",
+	}
+	configCmd.AddCommand(configMigrateCmd())
+
+	return configCmd
+}
+
+func configMigrateCmd() *cobra.Command {
+	var (
+		check bool
+		file  string
+	)
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite deprecated option names and check IDs in the configuration file",
+		Long: `Rewrite deprecated option names and check IDs in the configuration file, including settings
+of profiles, to their current names. Comments and the order of keys are preserved.
+
+Use the --check flag on CI to verify that the file doesn't use deprecated names without modifying it.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			current, err := os.ReadFile(file)
+			exitOnError(err)
+
+			migrated, deps, err := config.Deprecated.MigrateYAML(current)
+			exitOnError(err)
+
+			if len(deps) == 0 {
+				return
+			}
+
+			if check {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s uses deprecated names:\n%s", file, textdiff.Lines(string(current), string(migrated)))
+				os.Exit(3)
+			}
+
+			fi, err := os.Stat(file)
+			exitOnError(err)
+			exitOnError(os.WriteFile(file, migrated, fi.Mode().Perm()))
+
+			for _, d := range deps {
+				fmt.Fprintf(cmd.OutOrStdout(), "Migrated %s %q to %q\n", d.Kind, d.Old, d.New)
+			}
+		},
+	}
+
+	migrateCmd.Flags().BoolVar(&check, "check", false, "Do not rewrite the file, exit with code 3 and print a diff if the file uses deprecated names")
+	migrateCmd.Flags().StringVar(&file, "file", config.DefaultConfigFilename, "Path to the configuration file")
+
+	return migrateCmd
+}
@@ -0,0 +1,146 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/server"
+)
+
+func ctlCmd() *cobra.Command {
+	var (
+		serverURL  string
+		adminToken string
+		format     string
+		since      time.Duration
+	)
+
+	client := func() *server.AdminClient {
+		if adminToken == "" {
+			exitOnError(errors.New("the --admin-token flag is required"))
+		}
+		redact.AddSecrets(adminToken)
+		return server.NewAdminClient(serverURL, adminToken)
+	}
+
+	ctlCmd := &cobra.Command{
+		Use:   "ctl",
+		Short: "Operate the ownership server through its admin API",
+		Long: `Operate the ownership server started with the 'serve' command and the --admin-token flag:
+list the tracked repositories, view their last results, and revalidate them on demand.`,
+		Example: `  export CODEOWNERS_ADMIN_TOKEN=<token>
+  codeowners ctl repos --server https://codeowners.example.com
+  codeowners ctl report org/service-a
+  codeowners ctl history org/service-a --since 168h
+  codeowners ctl revalidate org/service-a --format json`,
+	}
+
+	reposCmd := &cobra.Command{
+		Use:   "repos",
+		Short: "List repositories tracked by the server with the status of their last scan",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			repos, err := client().ListRepositories(cmd.Context())
+			exitOnError(err)
+			exitOnError(printCtl(cmd.OutOrStdout(), format, repos, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "REPOSITORY\tTENANT\tLAST SCAN\tFINDINGS")
+				for _, r := range repos {
+					tenant, lastScan, findings := "-", "never", "-"
+					if r.Tenant != "" {
+						tenant = r.Tenant
+					}
+					if r.LastScan != nil {
+						lastScan, findings = r.LastScan.Format(time.RFC3339), fmt.Sprint(r.Findings)
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Repository, tenant, lastScan, findings)
+				}
+			}))
+		},
+	}
+
+	reportCmd := &cobra.Command{
+		Use:   "report REPOSITORY",
+		Short: "Print findings of the last scan of a given repository",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshot, err := client().Report(cmd.Context(), args[0])
+			exitOnError(err)
+			exitOnError(printCtl(cmd.OutOrStdout(), format, snapshot, snapshotTable(snapshot)))
+		},
+	}
+
+	historyCmd := &cobra.Command{
+		Use:   "history REPOSITORY",
+		Short: "Print the number of findings of each scan of a given repository",
+		Long: `Print the number of findings of each scan of a given repository, so ownership trends can be followed.
+The history is available only if the server stores snapshots in a database.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			points, err := client().History(cmd.Context(), args[0], time.Now().Add(-since))
+			exitOnError(err)
+			exitOnError(printCtl(cmd.OutOrStdout(), format, points, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "TAKEN AT\tFINDINGS\tUNOWNED DIRECTORIES\tISSUES")
+				for _, p := range points {
+					fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", p.TakenAt.Format(time.RFC3339), p.Findings, p.Kinds[server.UnownedDirectory], p.Kinds[server.CheckIssue])
+				}
+			}))
+		},
+	}
+	historyCmd.Flags().DurationVar(&since, "since", 30*24*time.Hour, "How far back the history goes")
+
+	revalidateCmd := &cobra.Command{
+		Use:   "revalidate REPOSITORY",
+		Short: "Scan a given repository immediately and print its findings",
+		Long: `Scan a given repository immediately and print its findings. As with the periodic scans,
+changed findings are posted to the configured webhook.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshot, err := client().Revalidate(cmd.Context(), args[0])
+			exitOnError(err)
+			exitOnError(printCtl(cmd.OutOrStdout(), format, snapshot, snapshotTable(snapshot)))
+		},
+	}
+
+	ctlCmd.AddCommand(reposCmd, reportCmd, historyCmd, revalidateCmd)
+	ctlCmd.PersistentFlags().StringVar(&serverURL, "server", "http://localhost:8080", "The address of the ownership server")
+	ctlCmd.PersistentFlags().StringVar(&adminToken, "admin-token", "", "The token of the admin API, set by the --admin-token flag of the 'serve' command")
+	ctlCmd.PersistentFlags().StringVar(&format, "format", "text", "Format of the output. Possible values: text, json")
+
+	return ctlCmd
+}
+
+func snapshotTable(s *server.Snapshot) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "Repository %s scanned at %s with %d finding(s)\n\n", s.Repository, s.TakenAt.Format(time.RFC3339), len(s.Findings))
+		if len(s.Findings) == 0 {
+			return
+		}
+		fmt.Fprintln(w, "KIND\tSUBJECT")
+		for _, f := range s.Findings {
+			fmt.Fprintf(w, "%s\t%s\n", f.Kind, f.Subject)
+		}
+	}
+}
+
+func printCtl(out io.Writer, format string, v interface{}, table func(w *tabwriter.Writer)) error {
+	switch format {
+	case "text":
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	default:
+		return errors.Errorf("unknown format %q, possible values: text, json", format)
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/ownershipdiff"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+func diffCmd(cfg *config.Config) *cobra.Command {
+	var (
+		format    string
+		commentPR int
+	)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff <base-ref> [<head-ref>]",
+		Short: "Print files which effective owners differ between two revisions",
+		Long: `Print files which effective owners differ between two revisions of the repository.
+
+A file is reported either because the CODEOWNERS rules changed, or because the file was moved
+under a pattern with different owners. Renames are detected by git, so moved files are compared
+with their previous path. If the head revision is not given, HEAD is used.
+
+With the --comment-pr flag, moved files which changed their owners are also reported as a comment
+on a given pull request, which mentions both the owners losing and gaining the files. The comment
+is updated on subsequent runs, and deleted once the pull request no longer transfers ownership.`,
+		Example: `  codeowners diff origin/main
+  codeowners diff v1.0.0 v2.0.0 --format json
+
+  # in a GitHub Actions workflow triggered with 'on: pull_request'
+  codeowners diff origin/${{ github.base_ref }} --comment-pr ${{ github.event.pull_request.number }}`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			baseRef, headRef := args[0], "HEAD"
+			if len(args) == 2 {
+				headRef = args[1]
+			}
+
+			changes, err := ownershipdiff.Compute(cfg.RepositoryPath, baseRef, headRef)
+			exitOnError(err)
+
+			switch format {
+			case "text":
+				err = ownershipdiff.WriteText(cmd.OutOrStdout(), changes)
+			case "json":
+				err = ownershipdiff.WriteJSON(cmd.OutOrStdout(), changes)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: text, json", format)
+			}
+			exitOnError(err)
+
+			if commentPR > 0 {
+				exitOnError(commentMoves(cmd.Context(), redact.NewLogger(), cfg, commentPR, changes))
+			}
+		},
+	}
+
+	diffCmd.Flags().StringVar(&format, "format", "text", "Format of the output. Possible values: text, json")
+	diffCmd.Flags().IntVar(&commentPR, "comment-pr", 0, "Number of the pull request commented with moved files which changed their owners. Zero disables the comment")
+	diffCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+	diffCmd.Flags().String("owner-checker-repository", "", "The owner and repository name separated by slash. Detected from the origin remote if not set")
+	addGitHubFlags(diffCmd)
+
+	return diffCmd
+}
+
+// commentMoves creates, updates, or deletes the pull request comment about moved files which changed their owners.
+func commentMoves(ctx context.Context, log logrus.FieldLogger, cfg *config.Config, number int, changes []ownershipdiff.Change) error {
+	detectRepository(log, cfg)
+	if cfg.OwnerCheckerRepository == "" {
+		return &api.ConfigError{Field: "OWNER_CHECKER_REPOSITORY", Err: errors.New("required to comment the pull request")}
+	}
+
+	client, _, err := github.NewClient(ctx, cfg)
+	if err != nil {
+		return errors.Wrap(err, "while creating GitHub client")
+	}
+
+	body := ownershipdiff.MovesComment(changes)
+	if err := ownershipdiff.NewPRCommenter(client).Sync(ctx, cfg.OwnerCheckerRepository, number, body); err != nil {
+		return err
+	}
+	if body == "" {
+		log.Infof("No moved file changed its owners in pull request #%d", number)
+		return nil
+	}
+	log.Infof("Commented ownership changes of moved files on pull request #%d", number)
+	return nil
+}
@@ -0,0 +1,252 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func editCmd(cfg *config.Config) *cobra.Command {
+	var (
+		addOwners      []string
+		removeOwners   []string
+		where          string
+		skipValidation bool
+	)
+
+	editCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Add or remove owners of entries matching a given glob",
+		Long: `Add or remove owners of entries matching a given glob, e.g. for scripted ownership migrations.
+
+Owners are matched case-insensitively. Formatting and comments of the file are preserved.
+Before the file is rewritten, added owners are validated against GitHub. Once rewritten,
+files whose effective ownership changed are printed.`,
+		Example: `  codeowners edit --add-owner @org/security --where '/auth/**'
+  codeowners edit --remove-owner @org/legacy --add-owner @org/platform --where '/infra/**'`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+
+			if len(addOwners) == 0 && len(removeOwners) == 0 {
+				exitOnError(errors.New("at least one of --add-owner or --remove-owner is required"))
+			}
+
+			scope, err := newEntryScope(where)
+			exitOnError(err)
+
+			if !skipValidation {
+				for _, owner := range addOwners {
+					exitOnError(validateOwner(cmd.Context(), log, cfg, owner))
+				}
+			}
+
+			changed, err := editCodeowners(cmd.OutOrStdout(), cfg.RepositoryPath, func(ast *codeowners.AST) int {
+				edited := 0
+				for _, entry := range ast.Entries() {
+					if !scope(entry) {
+						continue
+					}
+					if ast.EditOwners(entry.LineNo, func(owners []string) []string {
+						return addOwner(removeOwner(owners, removeOwners), addOwners)
+					}) {
+						edited++
+					}
+				}
+				return edited
+			})
+			exitOnError(err)
+
+			if changed == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No entries changed")
+			}
+		},
+	}
+
+	editCmd.Flags().StringSliceVar(&addOwners, "add-owner", nil, "The comma-separated list of owners to add to matching entries")
+	editCmd.Flags().StringSliceVar(&removeOwners, "remove-owner", nil, "The comma-separated list of owners to remove from matching entries")
+	editCmd.Flags().StringVar(&where, "where", "", "Edit only entries which patterns are within a given glob, e.g. '/auth/**'. By default, all entries are edited")
+	editCmd.Flags().BoolVar(&skipValidation, "skip-owner-validation", false, "Do not validate if added owners exist on GitHub")
+	editCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+	editCmd.Flags().String("owner-checker-repository", "", "The owner and repository name separated by slash")
+	addGitHubFlags(editCmd)
+
+	return editCmd
+}
+
+// addOwner appends owners which are not listed yet.
+func addOwner(owners []string, toAdd []string) []string {
+	for _, o := range toAdd {
+		if !containsOwner(owners, o) {
+			owners = append(owners, o)
+		}
+	}
+	return owners
+}
+
+// removeOwner removes given owners.
+func removeOwner(owners []string, toRemove []string) []string {
+	var out []string
+	for _, o := range owners {
+		if !containsOwner(toRemove, o) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func containsOwner(owners []string, owner string) bool {
+	for _, o := range owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// newEntryScope returns a function which reports whether the entry pattern is within a given glob.
+// Directory patterns, such as `/docs/`, are within the glob if the glob matches their content.
+func newEntryScope(glob string) (func(codeowners.Entry) bool, error) {
+	if glob == "" {
+		return func(codeowners.Entry) bool { return true }, nil
+	}
+
+	scope, err := codeowners.NewPattern(glob)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while compiling glob %s", glob)
+	}
+
+	return func(e codeowners.Entry) bool {
+		p := strings.Trim(e.Pattern, "/")
+		return scope.Match(p) || scope.Match(p+"/*")
+	}, nil
+}
+
+// validateOwner checks if the owner exists on GitHub using the 'owners' checker.
+func validateOwner(ctx context.Context, log logrus.FieldLogger, cfg *config.Config, owner string) error {
+	detectRepository(log, cfg)
+
+	ghClient, isApp, err := github.NewClient(ctx, cfg)
+	if err != nil {
+		return errors.Wrap(err, "while creating GitHub client")
+	}
+
+	owners, err := check.NewValidOwner(cfg, ghClient, !isApp)
+	if err != nil {
+		return errors.Wrap(err, "while creating owner validator")
+	}
+	if err := owners.CheckSatisfied(ctx); err != nil {
+		return errors.Wrap(err, "while checking if owner validator is satisfied")
+	}
+
+	out, err := owners.Check(ctx, api.Input{
+		CodeownersEntries: []codeowners.Entry{{LineNo: 1, Pattern: "*", Owners: []string{owner}}},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "while validating owner %s", owner)
+	}
+	if len(out.Issues) > 0 {
+		return errors.Errorf("owner %s is not valid: %s", owner, out.Issues[0].Message)
+	}
+
+	return nil
+}
+
+// editCodeowners applies the edit to the CODEOWNERS file, rewrites it if anything changed,
+// and prints the repository files which effective ownership changed.
+func editCodeowners(out io.Writer, repoPath string, edit func(ast *codeowners.AST) int) (int, error) {
+	path, err := codeowners.FindCodeownersFile(repoPath)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	ast, err := codeowners.ParseAST(bytes.NewReader(current))
+	if err != nil {
+		return 0, err
+	}
+	before := ast.Entries()
+
+	changed := edit(ast)
+	if changed == 0 {
+		return 0, nil
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(path, ast.Bytes(), fi.Mode().Perm()); err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(out, "Updated %d entry(ies) in %s\n", changed, path)
+
+	return changed, printOwnershipChanges(out, repoPath, before, ast.Entries())
+}
+
+func printOwnershipChanges(out io.Writer, repoPath string, before, after []codeowners.Entry) error {
+	absRepoPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return err
+	}
+
+	files, err := git.ListFiles(absRepoPath)
+	if err != nil {
+		return errors.Wrap(err, "while listing repository files")
+	}
+
+	beforeMatcher, err := codeowners.NewMatcher(before)
+	if err != nil {
+		return err
+	}
+	afterMatcher, err := codeowners.NewMatcher(after)
+	if err != nil {
+		return err
+	}
+
+	var changes []string
+	for _, file := range files {
+		oldOwners, newOwners := effectiveOwners(beforeMatcher, file), effectiveOwners(afterMatcher, file)
+		if oldOwners == newOwners {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("    %s: %s -> %s", file, oldOwners, newOwners))
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No effective ownership changes")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Effective ownership changed for %d file(s):\n%s\n", len(changes), strings.Join(changes, "\n"))
+	return nil
+}
+
+func effectiveOwners(m *codeowners.Matcher, file string) string {
+	e, found := m.Match(file)
+	if !found || len(e.Owners) == 0 {
+		return "nobody"
+	}
+	return strings.Join(e.Owners, ", ")
+}
@@ -0,0 +1,61 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/textdiff"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func fmtCmd(cfg *config.Config) *cobra.Command {
+	var (
+		check bool
+		sort  bool
+	)
+
+	fmtCmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Rewrite a CODEOWNERS file into the canonical style",
+		Long: `Rewrite a CODEOWNERS file into the canonical style: owners aligned in a single column,
+normalized spacing, collapsed blank lines, and preserved comments.
+
+Use the --check flag on CI to verify that the file is already formatted without modifying it.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			current, err := os.ReadFile(path)
+			exitOnError(err)
+
+			formatted, err := codeowners.Format(bytes.NewReader(current), codeowners.FormatOptions{
+				SortEntries: sort,
+			})
+			exitOnError(err)
+
+			if bytes.Equal(current, formatted) {
+				return
+			}
+
+			if check {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is not formatted:\n%s", path, textdiff.Lines(string(current), string(formatted)))
+				os.Exit(3)
+			}
+
+			fi, err := os.Stat(path)
+			exitOnError(err)
+			exitOnError(os.WriteFile(path, formatted, fi.Mode().Perm()))
+		},
+	}
+
+	fmtCmd.Flags().BoolVar(&check, "check", false, "Do not rewrite the file, exit with code 3 and print a diff if the file is not formatted")
+	fmtCmd.Flags().BoolVar(&sort, "sort", false, "Sort entries by pattern within comment-delimited blocks. Sorting changes the precedence of patterns")
+	fmtCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return fmtCmd
+}
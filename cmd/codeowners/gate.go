@@ -0,0 +1,151 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/gate"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/prereceive"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func gateCmd(cfg *config.Config) *cobra.Command {
+	var (
+		mergeQueue   bool
+		baseRef      string
+		checkRunName string
+	)
+
+	gateCmd := &cobra.Command{
+		Use:   "gate",
+		Short: "Validate CODEOWNERS of a commit range and report the result as a GitHub check run",
+		Long: `Validate CODEOWNERS of the checked out commit range and report the result as a GitHub check run,
+which can be marked as required in the branch protection rules.
+
+With the --merge-queue flag, the range is the merge group tested by the GitHub merge queue on a temporary
+gh-readonly-queue/<base>/pr-<number>-<sha> branch. It combines the base branch with all pull requests queued
+ahead, so CODEOWNERS changes of pull requests validated separately are validated together before they land.
+The group is read from the merge_group event payload, or parsed from the GITHUB_REF branch.
+
+The base of the range is used as the base reference for diff-aware checks. The checks are configured
+the same way as for the 'validate' command. Creating check runs requires a GitHub App token, e.g. the
+GITHUB_TOKEN of GitHub Actions with the 'checks: write' permission.`,
+		Example: `  # .github/workflows/codeowners.yaml, triggered with 'on: merge_group'
+  codeowners gate --merge-queue --checks files,duppatterns,syntax
+
+  # validate the current branch against main without reporting a check run
+  codeowners gate --base-ref origin/main --check-run-name ""`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+
+			repoPath := cfg.RepositoryPath
+			if repoPath == "" {
+				repoPath = "."
+			}
+
+			rng, err := gateRange(repoPath, mergeQueue, baseRef)
+			exitOnError(err)
+			changed, err := prereceive.CodeownersChanged(repoPath, rng.Base(), rng.HeadSHA)
+			exitOnError(errors.Wrapf(err, "while comparing CODEOWNERS of %s and %s", rng.Base(), rng.HeadSHA))
+
+			if rng.IsMergeGroup() {
+				log.Infof("Validating merge group %s of pull requests queued up to #%d", rng.HeadRef, rng.PullRequest)
+			}
+			log.Infof("Validating CODEOWNERS of %s..%s", rng.Base(), rng.HeadSHA)
+			cfg.DiffBaseRef = rng.Base()
+			checkRunner, err := validate(cmd.Context(), log, cfg, githubCacheOpts(log, cfg)...)
+			exitOnError(err)
+
+			if cmd.Context().Err() != nil {
+				log.Error("Application was interrupted by operating system")
+				os.Exit(2)
+			}
+
+			if checkRunName != "" {
+				exitOnError(reportCheckRun(cmd.Context(), log, cfg, repoPath, checkRunName, rng, changed, checkRunner))
+			}
+			if code := checkRunner.ExitCode(); code != runner.ExitCodeOK {
+				os.Exit(code)
+			}
+		},
+	}
+
+	addValidateFlags(gateCmd)
+	gateCmd.Flags().BoolVar(&mergeQueue, "merge-queue", false, "Validate the merge group tested by the GitHub merge queue, detected from the GitHub Actions environment")
+	gateCmd.Flags().StringVar(&baseRef, "base-ref", "", "The revision the validated range starts from, e.g. origin/main. Required without the --merge-queue flag")
+	gateCmd.Flags().StringVar(&checkRunName, "check-run-name", gate.DefaultCheckRunName, "Name of the reported check run, which can be marked as required. Empty name disables the check run")
+
+	return gateCmd
+}
+
+// gateRange returns the validated range. The head of the range defaults to the checked out commit.
+func gateRange(repoPath string, mergeQueue bool, baseRef string) (gate.Range, error) {
+	var (
+		rng gate.Range
+		err error
+	)
+	switch {
+	case mergeQueue && baseRef != "":
+		return gate.Range{}, errors.New("the --base-ref flag cannot be used together with --merge-queue")
+	case mergeQueue:
+		rng, err = gate.DetectMergeGroup(os.Getenv)
+		if err != nil {
+			return gate.Range{}, err
+		}
+	case baseRef == "":
+		return gate.Range{}, errors.New("the --base-ref flag is required without the --merge-queue flag")
+	default:
+		if rng.BaseSHA, err = git.RevParse(repoPath, baseRef); err != nil {
+			return gate.Range{}, errors.Wrapf(err, "while resolving base revision %q", baseRef)
+		}
+	}
+
+	if rng.HeadSHA == "" {
+		if rng.HeadSHA, err = git.RevParse(repoPath, "HEAD"); err != nil {
+			return gate.Range{}, errors.Wrap(err, "while resolving the checked out commit")
+		}
+	}
+	return rng, nil
+}
+
+// reportCheckRun reports results of the validation as a check run of the head commit.
+func reportCheckRun(ctx context.Context, log logrus.FieldLogger, cfg *config.Config, repoPath, name string, rng gate.Range, changed bool, checkRunner *runner.CheckRunner) error {
+	// the repository is detected from the origin remote by the validation, if it's not configured
+	if cfg.OwnerCheckerRepository == "" {
+		return &api.ConfigError{Field: "OWNER_CHECKER_REPOSITORY", Err: errors.New("required to report the check run")}
+	}
+
+	file, err := codeowners.FindCodeownersFile(repoPath)
+	if err != nil {
+		return err
+	}
+	relPath, err := filepath.Rel(repoPath, file)
+	if err != nil {
+		return err
+	}
+
+	client, _, err := github.NewClient(ctx, cfg)
+	if err != nil {
+		return errors.Wrap(err, "while creating GitHub client")
+	}
+
+	run := gate.NewCheckRun(rng, filepath.ToSlash(relPath), changed, checkRunner.Results(), checkRunner.ExitCode())
+	if err := gate.NewCheckRunReporter(client).Report(ctx, cfg.OwnerCheckerRepository, name, run); err != nil {
+		return err
+	}
+	log.Infof("Reported %q check run with the %s conclusion for %s", name, run.Conclusion, rng.HeadSHA)
+	return nil
+}
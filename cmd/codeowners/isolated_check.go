@@ -0,0 +1,42 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/isolation"
+	"go.szostok.io/codeowners/internal/load"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// isolatedCheckCmd is executed in a container by the isolation.Checker and is not meant to be used directly.
+func isolatedCheckCmd(cfg *config.Config) *cobra.Command {
+	var (
+		source  string
+		workdir string
+		checkID string
+	)
+
+	isolatedCheckCmd := &cobra.Command{
+		Use:    "isolated-check",
+		Short:  "Execute a single check on a copy of the repository and print the result as JSON",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			res := isolation.RunCheck(cmd.Context(), cfg, cmd.InOrStdin(), source, workdir, checkID, func(ctx context.Context, cfg *config.Config) ([]api.Checker, error) {
+				return load.Checks(ctx, cfg)
+			})
+			exitOnError(json.NewEncoder(cmd.OutOrStdout()).Encode(res))
+		},
+	}
+
+	addValidateFlags(isolatedCheckCmd)
+	isolatedCheckCmd.Flags().StringVar(&source, "source", isolation.SourceDir, "Path to the read-only repository")
+	isolatedCheckCmd.Flags().StringVar(&workdir, "workdir", "/work", "Path where the repository is copied before the check is executed")
+	isolatedCheckCmd.Flags().StringVar(&checkID, "check", "", "The check to be executed")
+
+	return isolatedCheckCmd
+}
@@ -0,0 +1,71 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/labeler"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func labelerCmd(cfg *config.Config) *cobra.Command {
+	var write bool
+
+	labelerCmd := &cobra.Command{
+		Use:   "labeler",
+		Short: "Generate the GitHub labeler configuration from CODEOWNERS",
+		Long: `Generate the configuration of the GitHub labeler action, so pull requests are labeled with the teams
+which own the changed files, e.g. team/payments for @org/payments.
+
+Team labels of the existing configuration are replaced by the generated ones. Other labels are kept as they are.
+The labeler applies all matching labels, so unlike in CODEOWNERS, labels of earlier entries are applied
+even if a later pattern matches the same file.`,
+		Example: `  codeowners report labeler > .github/labeler.yml
+  codeowners report labeler --write
+  codeowners report labeler --labeler-checker-label-prefix "area: " --write`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			f, err := os.Open(path)
+			exitOnError(err)
+			defer f.Close()
+
+			file := filepath.Join(cfg.RepositoryPath, cfg.LabelerCheckerFile)
+			existing, err := labeler.Load(file)
+			if os.IsNotExist(err) {
+				existing, err = &labeler.Config{}, nil
+			}
+			exitOnError(err)
+
+			prefix := cfg.LabelerCheckerLabelPrefix
+			out, err := labeler.Render(existing, labeler.Generate(codeowners.ParseCodeowners(f), prefix), prefix)
+			exitOnError(err)
+
+			if !write {
+				_, err = cmd.OutOrStdout().Write(out)
+				exitOnError(err)
+				return
+			}
+
+			mode := os.FileMode(0o644)
+			if fi, err := os.Stat(file); err == nil {
+				mode = fi.Mode().Perm()
+			}
+			exitOnError(os.MkdirAll(filepath.Dir(file), 0o755))
+			exitOnError(os.WriteFile(file, out, mode))
+		},
+	}
+
+	labelerCmd.Flags().BoolVar(&write, "write", false, "Write the configuration to the labeler file instead of printing it")
+	labelerCmd.Flags().String("labeler-checker-file", labeler.DefaultFile, "Path of the labeler configuration relative to the repository root")
+	labelerCmd.Flags().String("labeler-checker-label-prefix", labeler.DefaultLabelPrefix, "Prefix of team labels. Labels without the prefix are kept as they are")
+	labelerCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return labelerCmd
+}
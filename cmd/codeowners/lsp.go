@@ -0,0 +1,32 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/lsp"
+)
+
+func lspCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lsp",
+		Short: "Start a language server for CODEOWNERS files over stdio",
+		Long: `Start a language server for CODEOWNERS files that communicates over stdin and stdout.
+
+The server publishes issues of the offline checks as diagnostics and exposes their suggested
+fixes, such as removing a duplicated line or correcting owner casing, as quick fix code actions.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srv := lsp.NewServer(os.Stdin, os.Stdout,
+				check.NewValidSyntax(),
+				check.NewDuplicatedPattern(),
+				check.NewOwnerCasing(),
+				check.NewUnsafePattern(),
+				check.NewInvisibleChars(),
+			)
+			return srv.Run(cmd.Context())
+		},
+	}
+}
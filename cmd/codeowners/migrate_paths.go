@@ -0,0 +1,144 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/pathmigrate"
+	"go.szostok.io/codeowners/internal/textdiff"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+func migratePathsCmd(cfg *config.Config) *cobra.Command {
+	var (
+		renames  []string
+		fromDiff string
+		dryRun   bool
+	)
+
+	migratePathsCmd := &cobra.Command{
+		Use:   "migrate-paths",
+		Short: "Rewrite CODEOWNERS patterns of moved directories",
+		Long: `Rewrite CODEOWNERS patterns of directories moved during a restructure, so moved files keep their owners.
+
+Moved paths are given with the --map flag, or detected from renames reported by 'git diff -M' for a given
+revision or range. A directory is detected as moved only if all its files moved to the same directory,
+otherwise renamed files are migrated one by one.
+
+Only patterns anchored to the repository root which start with a moved path are rewritten. Patterns which
+match at any level, such as '*.go' or 'docs/', are left as they are. Formatting and comments of the file
+are preserved. A diff of the file is printed for review, followed by moved files whose owners change anyway,
+e.g. because of the patterns that matched at any level. The file is not rewritten if ownership of any file
+outside the moved paths would change.`,
+		Example: `  codeowners migrate-paths --map services/payments=platform/payments --dry-run
+  codeowners migrate-paths --map docs=handbook --map tools/ci=build/ci
+  codeowners migrate-paths --from-diff main...HEAD`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			out := cmd.OutOrStdout()
+
+			absRepoPath, err := filepath.Abs(cfg.RepositoryPath)
+			exitOnError(err)
+
+			moves, err := pathmigrate.ParseRenames(renames)
+			exitOnError(err)
+			if fromDiff != "" {
+				detected, err := detectRenames(absRepoPath, fromDiff)
+				exitOnError(err)
+				for _, r := range detected {
+					fmt.Fprintf(out, "Detected move: %s -> %s\n", r.From, r.To)
+				}
+				moves = append(moves, detected...)
+			}
+			if len(moves) == 0 {
+				exitOnError(errors.New("no moved paths, use the --map or --from-diff flag"))
+			}
+
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+			current, err := os.ReadFile(path)
+			exitOnError(err)
+			ast, err := codeowners.ParseAST(bytes.NewReader(current))
+			exitOnError(err)
+
+			before := ast.Entries()
+			rewritten := pathmigrate.Rewrite(ast, moves)
+			if rewritten == 0 {
+				fmt.Fprintln(out, "No patterns of the moved paths found in the CODEOWNERS file")
+				return
+			}
+			fmt.Fprintf(out, "%s:\n%s", path, textdiff.Lines(string(current), string(ast.Bytes())))
+
+			files, err := git.ListFiles(absRepoPath)
+			exitOnError(errors.Wrap(err, "while listing repository files"))
+			report, err := pathmigrate.Verify(before, ast.Entries(), files, moves)
+			exitOnError(err)
+
+			if len(report.Moved) > 0 {
+				fmt.Fprintf(out, "Effective ownership of %d moved file(s) changed:\n", len(report.Moved))
+				printMigrationChanges(out, report.Moved)
+			}
+			if len(report.Unrelated) > 0 {
+				fmt.Fprintf(out, "Effective ownership of %d file(s) outside the moved paths changed:\n", len(report.Unrelated))
+				printMigrationChanges(out, report.Unrelated)
+				exitOnError(errors.Errorf("the migration changes ownership of files outside the moved paths, %s is not rewritten", path))
+			}
+
+			if dryRun {
+				return
+			}
+			fi, err := os.Stat(path)
+			exitOnError(err)
+			exitOnError(os.WriteFile(path, ast.Bytes(), fi.Mode().Perm()))
+			fmt.Fprintf(out, "Updated %d entry(ies) in %s\n", rewritten, path)
+		},
+	}
+
+	migratePathsCmd.Flags().StringArrayVar(&renames, "map", nil, "Moved directory or file in the form old/dir=new/dir. Can be repeated")
+	migratePathsCmd.Flags().StringVar(&fromDiff, "from-diff", "", "Detect moved paths from renames in 'git diff -M' of a given revision or range, e.g. main...HEAD")
+	migratePathsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the diff without rewriting the file")
+	migratePathsCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return migratePathsCmd
+}
+
+// detectRenames returns moved paths detected from renames in a given revision or range.
+func detectRenames(repoDir, revRange string) ([]pathmigrate.Rename, error) {
+	changes, err := git.ChangedFilesInRange(repoDir, revRange)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while listing renames in %s", revRange)
+	}
+	files, err := git.ListFiles(repoDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing repository files")
+	}
+	return pathmigrate.DetectRenames(changes, files), nil
+}
+
+func printMigrationChanges(out io.Writer, changes []pathmigrate.Change) {
+	for _, c := range changes {
+		path := c.Path
+		if c.OldPath != "" {
+			path = c.OldPath + " -> " + c.Path
+		}
+		fmt.Fprintf(out, "    %s: %s -> %s\n", path, describeMigrationOwners(c.OldOwners), describeMigrationOwners(c.NewOwners))
+	}
+}
+
+func describeMigrationOwners(owners []string) string {
+	if len(owners) == 0 {
+		return "nobody"
+	}
+	return strings.Join(owners, ", ")
+}
@@ -0,0 +1,68 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/oncall"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func oncallExportCmd(cfg *config.Config) *cobra.Command {
+	var format string
+
+	exportCmd := &cobra.Command{
+		Use:   "oncall",
+		Short: "Export the incident routing table of CODEOWNERS entries",
+		Long: `Export the incident routing table which joins CODEOWNERS entries with escalation policies of their owners.
+
+Owners are mapped to PagerDuty escalation policies and Opsgenie escalations in YAML format, e.g.:
+
+  teams:
+    "@org/platform":
+      pagerduty: PABC123
+      opsgenie: platform-escalation
+
+The table lists entries in the CODEOWNERS order, so, as in CODEOWNERS, the last matching pattern
+takes the most precedence. Owners without an escalation policy are listed as unmapped.`,
+		Example: `  codeowners report oncall --oncall-checker-mapping oncall.yaml
+  codeowners report oncall --oncall-checker-mapping oncall.yaml --format csv > routing.csv`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.OnCallCheckerMapping == "" {
+				exitOnError(errors.New("--oncall-checker-mapping is required"))
+			}
+
+			mapping, err := oncall.LoadMapping(cfg.OnCallCheckerMapping)
+			exitOnError(err)
+
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			f, err := os.Open(path)
+			exitOnError(err)
+			defer f.Close()
+
+			routes := oncall.RoutingTable(codeowners.ParseCodeowners(f), mapping)
+			switch format {
+			case "json":
+				err = oncall.WriteJSON(cmd.OutOrStdout(), routes)
+			case "csv":
+				err = oncall.WriteCSV(cmd.OutOrStdout(), routes)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: json, csv", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	exportCmd.Flags().StringVar(&format, "format", "json", "Format of the routing table. Possible values: json, csv")
+	exportCmd.Flags().String("oncall-checker-mapping", "", "Path to the file which maps owners to escalation policies")
+	exportCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return exportCmd
+}
@@ -0,0 +1,126 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/prereceive"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+type preReceiveOptions struct {
+	refs   []string
+	always bool
+}
+
+func preReceiveCmd(cfg *config.Config) *cobra.Command {
+	var (
+		opts   preReceiveOptions
+		gitDir string
+	)
+
+	preReceiveCmd := &cobra.Command{
+		Use:   "pre-receive",
+		Short: "Validate CODEOWNERS of pushed refs in a server-side pre-receive git hook",
+		Long: `Validate CODEOWNERS of pushed refs in a server-side pre-receive git hook, and reject the push if any configured check fails.
+
+The command reads the standard pre-receive hook input from stdin, i.e. one '<old-rev> <new-rev> <ref>' line per updated ref.
+Each pushed revision is checked out to a temporary directory, and the configured checks are executed against it. The previous
+revision of the ref is used as the base reference for diff-aware checks. By default, only pushes which change the CODEOWNERS
+file, or create a new ref, are validated. Deleted refs are never validated.
+
+The checks are configured the same way as for the 'validate' command, e.g. with the codeowners-config.yaml file in the repository directory
+on the git server, or environment variables.`,
+		Example: `  # .git/hooks/pre-receive or the server-specific custom hooks directory
+  #!/bin/sh
+  exec codeowners pre-receive --refs 'refs/heads/*' --checks files,duppatterns,syntax`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+
+			updates, err := prereceive.ParseUpdates(cmd.InOrStdin())
+			exitOnError(err)
+
+			repo, err := prereceive.NewRepositoryFromEnv(gitDir)
+			exitOnError(err)
+
+			rejected := 0
+			for _, u := range updates {
+				accepted, err := validateUpdate(cmd.Context(), log.WithField("ref", u.Ref), *cfg, repo, u, opts)
+				exitOnError(err)
+				if !accepted {
+					rejected++
+				}
+			}
+
+			if cmd.Context().Err() != nil {
+				log.Error("Application was interrupted by operating system")
+				os.Exit(2)
+			}
+			if rejected > 0 {
+				log.Errorf("Push rejected, CODEOWNERS validation failed for %d ref(s)", rejected)
+				os.Exit(runner.ExitCodeCheckFailure)
+			}
+		},
+	}
+
+	addValidateFlags(preReceiveCmd)
+	preReceiveCmd.Flags().StringSliceVar(&opts.refs, "refs", []string{"refs/heads/*"}, "The comma-separated list of glob patterns of refs which pushes are validated. Empty list validates all refs")
+	preReceiveCmd.Flags().BoolVar(&opts.always, "always", false, "Validate each pushed ref, even if the CODEOWNERS file was not changed")
+	preReceiveCmd.Flags().StringVar(&gitDir, "git-dir", ".", "Path to the receiving repository, used if the GIT_DIR environment variable is not set")
+
+	return preReceiveCmd
+}
+
+// validateUpdate runs the configured checks against the pushed revision and returns whether the update is accepted.
+// The config is passed by value, as it's adjusted for each update.
+func validateUpdate(ctx context.Context, log logrus.FieldLogger, cfg config.Config, repo *prereceive.Repository, u prereceive.Update, opts preReceiveOptions) (bool, error) {
+	if u.IsDelete() {
+		return true, nil
+	}
+	matched, err := prereceive.MatchRef(u.Ref, opts.refs)
+	if err != nil || !matched {
+		return true, err
+	}
+
+	dir, cleanup, err := repo.Checkout(u.NewRev)
+	if err != nil {
+		return false, err
+	}
+	defer cleanup()
+
+	if _, err := codeowners.FindCodeownersFile(dir); err != nil {
+		log.WithError(err).Info("Skipping validation")
+		return true, nil
+	}
+
+	if !u.IsCreate() {
+		if !opts.always {
+			changed, err := prereceive.CodeownersChanged(dir, u.OldRev, u.NewRev)
+			if err != nil {
+				return false, err
+			}
+			if !changed {
+				log.Debug("Skipping validation, CODEOWNERS was not changed")
+				return true, nil
+			}
+		}
+		cfg.DiffBaseRef = u.OldRev
+	}
+	cfg.RepositoryPath = dir
+
+	log.Infof("Validating CODEOWNERS at %s", u.NewRev)
+	checkRunner, err := validate(ctx, log, &cfg, githubCacheOpts(log, &cfg)...)
+	if err != nil {
+		return false, err
+	}
+
+	return checkRunner.ExitCode() == runner.ExitCodeOK, nil
+}
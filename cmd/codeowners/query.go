@@ -0,0 +1,92 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func queryCmd(cfg *config.Config) *cobra.Command {
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query the ownership of repository paths and CI jobs of their owners",
+	}
+
+	queryCmd.AddCommand(queryOwnersCmd(cfg), queryJobsCmd(cfg))
+
+	return queryCmd
+}
+
+func queryOwnersCmd(cfg *config.Config) *cobra.Command {
+	var format string
+
+	ownersCmd := &cobra.Command{
+		Use:   "owners PATH...",
+		Short: "Print owners of given paths",
+		Long: `Print owners of given paths together with the CODEOWNERS pattern that assigns them.
+
+Paths are relative to the repository root. Absolute paths are converted to paths relative to the repository root.
+As in GitHub, the last matching pattern takes the most precedence.`,
+		Example: `  codeowners query owners docs/README.md main.go
+  codeowners query owners --format json $(git diff --name-only origin/main)`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := codeowners.NewFromPath(cfg.RepositoryPath)
+			exitOnError(err)
+
+			ruleset, err := server.NewRuleset("", entries)
+			exitOnError(err)
+
+			absRepo, err := filepath.Abs(cfg.RepositoryPath)
+			exitOnError(err)
+
+			var out []server.Resolution
+			for _, p := range args {
+				if filepath.IsAbs(p) {
+					p, err = codeowners.RelPath(absRepo, p)
+					exitOnError(err)
+				}
+				out = append(out, ruleset.Resolve(p))
+			}
+
+			switch format {
+			case "text":
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "PATH\tOWNERS\tPATTERN")
+				for _, r := range out {
+					owners, pattern := "nobody", "-"
+					if len(r.Owners) > 0 {
+						owners = strings.Join(r.Owners, ",")
+					}
+					if r.Pattern != "" {
+						pattern = fmt.Sprintf("%s (line %d)", r.Pattern, r.Line)
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", r.Path, owners, pattern)
+				}
+				err = w.Flush()
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				err = enc.Encode(out)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: text, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	ownersCmd.Flags().StringVar(&format, "format", "text", "Format of the output. Possible values: text, json")
+	ownersCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return ownersCmd
+}
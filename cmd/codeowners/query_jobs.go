@@ -0,0 +1,119 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/testscope"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func queryJobsCmd(cfg *config.Config) *cobra.Command {
+	var (
+		format   string
+		mapping  string
+		useStdin bool
+		revRange string
+	)
+
+	jobsCmd := &cobra.Command{
+		Use:   "jobs [PATH...]",
+		Short: "Print CI jobs which test the code owned by owners of given paths",
+		Long: `Print CI jobs to run for changed paths, based on the test scope declared for each owner in the mapping file:
+
+  teams:
+    "@org/payments": [payments-unit, payments-e2e]
+    "@org/docs": [docs-lint]
+  fallback: [full-suite]
+
+As in GitHub, the last matching CODEOWNERS pattern decides the owners of a path. If none of the owners
+of a path has a test scope, or the path is not owned, the fallback jobs are selected, so changes are never
+left untested. Previous paths of renamed files are included with the --diff flag.
+
+The text format prints the deduplicated jobs, one per line. The json format prints the jobs together with
+the owners and the selected jobs of each path.`,
+		Example: `  codeowners query jobs --test-scope-mapping test-scope.yaml --diff origin/main...HEAD
+  git diff --name-only origin/main | codeowners query jobs --test-scope-mapping test-scope.yaml --stdin --format json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if mapping == "" {
+				exitOnError(errors.New("--test-scope-mapping is required"))
+			}
+			sources := 0
+			for _, set := range []bool{len(args) > 0, useStdin, revRange != ""} {
+				if set {
+					sources++
+				}
+			}
+			if sources != 1 {
+				exitOnError(errors.New("provide paths either as arguments, with the --stdin flag, or with the --diff flag"))
+			}
+
+			m, err := testscope.LoadMapping(mapping)
+			exitOnError(err)
+
+			entries, err := codeowners.NewFromPath(cfg.RepositoryPath)
+			exitOnError(err)
+
+			selector, err := testscope.NewSelector(entries, m)
+			exitOnError(err)
+
+			absRepo, err := filepath.Abs(cfg.RepositoryPath)
+			exitOnError(err)
+
+			var paths []string
+			collect := func(p string) error {
+				if filepath.IsAbs(p) {
+					p, err = codeowners.RelPath(absRepo, p)
+					if err != nil {
+						return err
+					}
+				}
+				paths = append(paths, p)
+				return nil
+			}
+
+			switch {
+			case useStdin:
+				err = scanPaths(cmd.InOrStdin(), '\n', collect)
+			case revRange != "":
+				err = diffPaths(cfg.RepositoryPath, revRange, collect)
+			default:
+				for _, p := range args {
+					if err = collect(p); err != nil {
+						break
+					}
+				}
+			}
+			exitOnError(err)
+
+			selection := selector.Select(paths)
+			switch format {
+			case "text":
+				for _, j := range selection.Jobs {
+					fmt.Fprintln(cmd.OutOrStdout(), j)
+				}
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				err = enc.Encode(selection)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: text, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	jobsCmd.Flags().StringVar(&format, "format", "text", "Format of the output. Possible values: text, json")
+	jobsCmd.Flags().StringVar(&mapping, "test-scope-mapping", "", "Path to the file which maps owners to CI jobs")
+	jobsCmd.Flags().BoolVar(&useStdin, "stdin", false, "Read newline-terminated paths from the standard input")
+	jobsCmd.Flags().StringVar(&revRange, "diff", "", "Select jobs for paths changed in a given revision range, e.g. origin/main..HEAD, or origin/main...HEAD to compare with the merge base")
+	jobsCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return jobsCmd
+}
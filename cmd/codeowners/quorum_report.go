@@ -0,0 +1,63 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/quorum"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func quorumReportCmd(cfg *config.Config) *cobra.Command {
+	var format string
+
+	reportCmd := &cobra.Command{
+		Use:   "quorum <revision-range>",
+		Short: "Estimate the smallest set of code owner approvals required by a diff",
+		Long: `Estimate the smallest set of owners whose approvals satisfy the code owner review of all files
+changed in a given revision range, e.g. to plan reviews of big cross-cutting pull requests.
+
+As in GitHub, a file is approved if any one of its owners approves the change. Both the previous and the new
+path of renamed files are taken into account, and deleted files as well. Owners are resolved with the CODEOWNERS
+file from the working tree. Owners marked as required are the only owners of some of the changed files.`,
+		Example: `  codeowners report quorum origin/main...HEAD
+  codeowners report quorum v1.0.0..v1.1.0 --format json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			f, err := os.Open(path)
+			exitOnError(err)
+			defer f.Close()
+
+			var files []string
+			exitOnError(diffPaths(cfg.RepositoryPath, args[0], func(p string) error {
+				files = append(files, p)
+				return nil
+			}))
+
+			result, err := quorum.Estimate(codeowners.ParseCodeowners(f), files)
+			exitOnError(err)
+
+			switch format {
+			case "text":
+				err = quorum.WriteText(cmd.OutOrStdout(), result)
+			case "json":
+				err = quorum.WriteJSON(cmd.OutOrStdout(), result)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: text, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	reportCmd.Flags().StringVar(&format, "format", "text", "Format of the report. Possible values: text, json")
+	reportCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return reportCmd
+}
@@ -0,0 +1,52 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/releasenotes"
+)
+
+func releaseNotesCmd(cfg *config.Config) *cobra.Command {
+	var format string
+
+	releaseNotesCmd := &cobra.Command{
+		Use:   "release-notes <from-ref> [<to-ref>]",
+		Short: "Attribute changes of a release to owners and print a per-team changelog skeleton",
+		Long: `Attribute commits between two revisions, e.g. tags of consecutive releases, to the owners of the files
+they changed, and print a changelog skeleton with a section per owner.
+
+Files are resolved with the CODEOWNERS file of the newer revision. A commit is listed in the section
+of each owner of any file it changed, and commits which changed files without owners are listed
+in the Unowned section. Merge commits are skipped. If the newer revision is not given, HEAD is used.`,
+		Example: `  codeowners report release-notes v1.2.0 v1.3.0 > CHANGELOG-draft.md
+  codeowners report release-notes v1.2.0 --format json`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			fromRef, toRef := args[0], "HEAD"
+			if len(args) == 2 {
+				toRef = args[1]
+			}
+
+			notes, err := releasenotes.Attribute(cfg.RepositoryPath, fromRef, toRef)
+			exitOnError(err)
+
+			switch format {
+			case "markdown":
+				err = releasenotes.WriteMarkdown(cmd.OutOrStdout(), notes)
+			case "json":
+				err = releasenotes.WriteJSON(cmd.OutOrStdout(), notes)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: markdown, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	releaseNotesCmd.Flags().StringVar(&format, "format", "markdown", "Format of the output. Possible values: markdown, json")
+	releaseNotesCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return releaseNotesCmd
+}
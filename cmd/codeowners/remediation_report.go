@@ -0,0 +1,68 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/remediation"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func remediationReportCmd(cfg *config.Config) *cobra.Command {
+	var (
+		format string
+		since  time.Duration
+		opts   remediation.Options
+	)
+
+	reportCmd := &cobra.Command{
+		Use:   "remediation",
+		Short: "Rank unowned directories to prioritize which of them should get owners first",
+		Long: `Rank directories with unowned files by the change frequency of those files and then by their size.
+
+Frequently changed unowned files are the most expensive ones, as their changes are not reviewed by the
+right people automatically. The change frequency is computed from the git history of the current branch.`,
+		Example: `  codeowners report remediation
+  codeowners report remediation --since 720h --depth 2 --limit 10 --format json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			f, err := os.Open(path)
+			exitOnError(err)
+			defer f.Close()
+
+			if since > 0 {
+				opts.Since = time.Now().Add(-since)
+			}
+
+			offenders, err := remediation.TopOffenders(cfg.RepositoryPath, codeowners.ParseCodeowners(f), opts)
+			exitOnError(err)
+
+			switch format {
+			case "markdown":
+				err = remediation.WriteMarkdown(cmd.OutOrStdout(), offenders)
+			case "json":
+				err = remediation.WriteJSON(cmd.OutOrStdout(), offenders)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: markdown, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	reportCmd.Flags().StringVar(&format, "format", "markdown", "Format of the report. Possible values: markdown, json")
+	reportCmd.Flags().DurationVar(&since, "since", 90*24*time.Hour, "How far back the change history is analyzed. Zero means the whole history")
+	reportCmd.Flags().IntVar(&opts.Depth, "depth", 0, "Group unowned files by directories at most this many levels deep. Zero groups files by their parent directory")
+	reportCmd.Flags().IntVar(&opts.Limit, "limit", 20, "Maximum number of reported directories. Zero means no limit")
+	reportCmd.Flags().BoolVar(&opts.SkipGenerated, "skip-generated", false, "Exclude files marked as linguist-generated or linguist-vendored in .gitattributes")
+	reportCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return reportCmd
+}
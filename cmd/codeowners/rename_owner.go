@@ -0,0 +1,93 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func renameOwnerCmd(cfg *config.Config) *cobra.Command {
+	var (
+		pathScope      string
+		skipValidation bool
+	)
+
+	renameOwnerCmd := &cobra.Command{
+		Use:   "rename-owner OLD_OWNER NEW_OWNER",
+		Short: "Rename an owner across the CODEOWNERS file",
+		Long: `Rename an owner across the CODEOWNERS file, e.g. to transfer ownership to a new team.
+
+Owners are matched case-insensitively. Formatting and comments of the file are preserved.
+Before the file is rewritten, the new owner is validated against GitHub. Once rewritten,
+files whose effective ownership changed are printed.`,
+		Example: `  codeowners rename-owner @org/old-team @org/new-team
+  codeowners rename-owner @org/old-team @org/new-team --path-scope '/docs/**'`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+			oldOwner, newOwner := args[0], args[1]
+
+			scope, err := newEntryScope(pathScope)
+			exitOnError(err)
+
+			if !skipValidation {
+				exitOnError(validateOwner(cmd.Context(), log, cfg, newOwner))
+			}
+
+			changed, err := editCodeowners(cmd.OutOrStdout(), cfg.RepositoryPath, func(ast *codeowners.AST) int {
+				renamed := 0
+				for _, entry := range ast.Entries() {
+					if !scope(entry) {
+						continue
+					}
+					if ast.EditOwners(entry.LineNo, func(owners []string) []string {
+						return renameOwner(owners, oldOwner, newOwner)
+					}) {
+						renamed++
+					}
+				}
+				return renamed
+			})
+			exitOnError(err)
+
+			if changed == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Owner %s not found in the CODEOWNERS file\n", oldOwner)
+			}
+		},
+	}
+
+	renameOwnerCmd.Flags().StringVar(&pathScope, "path-scope", "", "Rename the owner only in entries which patterns are within a given glob, e.g. '/docs/**'")
+	renameOwnerCmd.Flags().BoolVar(&skipValidation, "skip-owner-validation", false, "Do not validate if the new owner exists on GitHub")
+	renameOwnerCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+	renameOwnerCmd.Flags().String("owner-checker-repository", "", "The owner and repository name separated by slash")
+	addGitHubFlags(renameOwnerCmd)
+
+	return renameOwnerCmd
+}
+
+// renameOwner replaces the old owner with the new one. If the new owner is already listed, the old one is removed.
+func renameOwner(owners []string, oldOwner, newOwner string) []string {
+	var (
+		out      []string
+		hasOwner bool
+	)
+	for _, o := range owners {
+		if strings.EqualFold(o, oldOwner) {
+			o = newOwner
+		}
+		if strings.EqualFold(o, newOwner) {
+			if hasOwner {
+				continue
+			}
+			hasOwner = true
+		}
+		out = append(out, o)
+	}
+	return out
+}
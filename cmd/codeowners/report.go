@@ -0,0 +1,27 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+)
+
+func reportCmd(cfg *config.Config) *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate ownership reports, such as the remediation ranking, review load, review quorum, incident routing table, labeler configuration, release notes, or badges",
+	}
+
+	reportCmd.AddCommand(
+		remediationReportCmd(cfg),
+		reviewLoadReportCmd(cfg),
+		quorumReportCmd(cfg),
+		oncallExportCmd(cfg),
+		labelerCmd(cfg),
+		releaseNotesCmd(cfg),
+		badgeCmd(cfg),
+	)
+
+	return reportCmd
+}
@@ -0,0 +1,245 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func resolveCmd(cfg *config.Config) *cobra.Command {
+	var (
+		format       string
+		useStdin     bool
+		nul          bool
+		revRange     string
+		uniqueOwners bool
+	)
+
+	resolveCmd := &cobra.Command{
+		Use:   "resolve [PATH...]",
+		Short: "Print effective owners of paths in a machine-readable format",
+		Long: `Print effective owners of given paths in a machine-readable format, e.g. for build systems which know
+which files a target touches.
+
+With the --stdin flag, paths are read from the standard input, one per line, or NUL-terminated with the -z flag.
+With the --diff flag, paths changed in a given revision range are resolved. Both the previous and the new path
+of renamed files are resolved, and deleted files as well, as their owners review the change too.
+The CODEOWNERS patterns are compiled once, and the output is buffered, so large path lists are resolved quickly.
+
+The tsv format prints 'path<TAB>owners' lines, where owners are separated by spaces and empty for unowned paths.
+The json format prints a JSON object per line. With the -z flag, output records are NUL-terminated as well.
+
+With the --unique-owners flag, only the deduplicated set of owners of all paths is printed, one owner per line,
+or as a JSON array. Owners are compared case-insensitively, as in GitHub.`,
+		Example: `  git diff --name-only origin/main | codeowners resolve --stdin
+  git ls-files -z | codeowners resolve --stdin -z --format json
+  codeowners resolve --diff origin/main...HEAD --unique-owners`,
+		Run: func(cmd *cobra.Command, args []string) {
+			sources := 0
+			for _, set := range []bool{len(args) > 0, useStdin, revRange != ""} {
+				if set {
+					sources++
+				}
+			}
+			if sources != 1 {
+				exitOnError(errors.New("provide paths either as arguments, with the --stdin flag, or with the --diff flag"))
+			}
+
+			write, err := resolutionWriter(format)
+			exitOnError(err)
+
+			entries, err := codeowners.NewFromPath(cfg.RepositoryPath)
+			exitOnError(err)
+
+			ruleset, err := server.NewRuleset("", entries)
+			exitOnError(err)
+
+			absRepo, err := filepath.Abs(cfg.RepositoryPath)
+			exitOnError(err)
+
+			terminator := byte('\n')
+			if nul {
+				terminator = 0
+			}
+
+			out := bufio.NewWriter(cmd.OutOrStdout())
+			owners := newOwnerSet()
+			resolve := func(p string) error {
+				if filepath.IsAbs(p) {
+					p, err = codeowners.RelPath(absRepo, p)
+					if err != nil {
+						return err
+					}
+				}
+				r := ruleset.Resolve(p)
+				if uniqueOwners {
+					owners.Add(r.Owners...)
+					return nil
+				}
+				if err := write(out, r); err != nil {
+					return err
+				}
+				return out.WriteByte(terminator)
+			}
+
+			switch {
+			case useStdin:
+				err = scanPaths(cmd.InOrStdin(), terminator, resolve)
+			case revRange != "":
+				err = diffPaths(cfg.RepositoryPath, revRange, resolve)
+			default:
+				for _, p := range args {
+					if err = resolve(p); err != nil {
+						break
+					}
+				}
+			}
+			exitOnError(err)
+
+			if uniqueOwners {
+				exitOnError(owners.Write(out, format, terminator))
+			}
+			exitOnError(out.Flush())
+		},
+	}
+
+	resolveCmd.Flags().StringVar(&format, "format", "tsv", "Format of the output. Possible values: tsv, json")
+	resolveCmd.Flags().BoolVar(&useStdin, "stdin", false, "Read paths from the standard input")
+	resolveCmd.Flags().BoolVarP(&nul, "null", "z", false, "Paths on the standard input and output records are NUL-terminated instead of newline-terminated")
+	resolveCmd.Flags().StringVar(&revRange, "diff", "", "Resolve paths changed in a given revision range, e.g. origin/main..HEAD, or origin/main...HEAD to compare with the merge base")
+	resolveCmd.Flags().BoolVar(&uniqueOwners, "unique-owners", false, "Print only the deduplicated set of owners of all paths")
+	resolveCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return resolveCmd
+}
+
+// resolutionWriter returns the function which writes a single resolution without the record terminator.
+func resolutionWriter(format string) (func(w io.Writer, r server.Resolution) error, error) {
+	switch format {
+	case "tsv":
+		return func(w io.Writer, r server.Resolution) error {
+			_, err := fmt.Fprintf(w, "%s\t%s", r.Path, strings.Join(r.Owners, " "))
+			return err
+		}, nil
+	case "json":
+		return func(w io.Writer, r server.Resolution) error {
+			raw, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(raw)
+			return err
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown format %q, possible values: tsv, json", format)
+	}
+}
+
+// scanPaths calls fn for each non-empty path read from r. Paths are terminated by a given byte,
+// and the trailing carriage return of newline-terminated paths is trimmed.
+func scanPaths(r io.Reader, terminator byte, fn func(string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, terminator); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+
+	for scanner.Scan() {
+		p := scanner.Text()
+		if terminator == '\n' {
+			p = strings.TrimSuffix(p, "\r")
+		}
+		if p == "" {
+			continue
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(scanner.Err(), "while reading paths")
+}
+
+// diffPaths calls fn for each path changed in a given revision range, including previous paths of renamed files.
+func diffPaths(repoDir, revRange string, fn func(string) error) error {
+	changes, err := git.ChangedFilesInRange(repoDir, revRange)
+	if err != nil {
+		return errors.Wrapf(err, "while listing files changed in %s", revRange)
+	}
+
+	for _, c := range changes {
+		if c.OldPath != "" {
+			if err := fn(c.OldPath); err != nil {
+				return err
+			}
+		}
+		if err := fn(c.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ownerSet collects owners, deduplicated case-insensitively. The first spelling of an owner is kept.
+type ownerSet map[string]string
+
+func newOwnerSet() ownerSet {
+	return ownerSet{}
+}
+
+func (s ownerSet) Add(owners ...string) {
+	for _, o := range owners {
+		if _, found := s[strings.ToLower(o)]; !found {
+			s[strings.ToLower(o)] = o
+		}
+	}
+}
+
+// Write prints the sorted owners, terminated by a given byte, or as a JSON array.
+func (s ownerSet) Write(w io.Writer, format string, terminator byte) error {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	owners := make([]string, 0, len(keys))
+	for _, k := range keys {
+		owners = append(owners, s[k])
+	}
+
+	if format == "json" {
+		raw, err := json.Marshal(owners)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", raw)
+		return err
+	}
+
+	for _, o := range owners {
+		if _, err := fmt.Fprintf(w, "%s%c", o, terminator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
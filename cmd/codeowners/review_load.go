@@ -0,0 +1,71 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/reviewload"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func reviewLoadReportCmd(cfg *config.Config) *cobra.Command {
+	var (
+		format string
+		since  time.Duration
+		opts   reviewload.Options
+	)
+
+	reportCmd := &cobra.Command{
+		Use:   "review-load",
+		Short: "Estimate the review load of each owner to find teams that are likely review bottlenecks",
+		Long: `Estimate the review load of each owner by combining the ownership breadth with the change frequency.
+
+Each commit from the git history of the current branch is treated as a pull request that requests a review
+from the owners of all changed files, according to the current CODEOWNERS rules. Owners that get significantly
+more review requests than the average owner are reported as bottlenecks.`,
+		Example: `  codeowners report review-load
+  codeowners report review-load --since 720h --bottleneck-factor 3 --format markdown`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			f, err := os.Open(path)
+			exitOnError(err)
+			defer f.Close()
+
+			if since > 0 {
+				opts.Since = time.Now().Add(-since)
+			}
+
+			report, err := reviewload.Analyze(cfg.RepositoryPath, codeowners.ParseCodeowners(f), opts)
+			exitOnError(err)
+
+			switch format {
+			case "stats":
+				err = reviewload.WriteStats(cmd.OutOrStdout(), report)
+			case "markdown":
+				err = reviewload.WriteMarkdown(cmd.OutOrStdout(), report)
+			case "json":
+				err = reviewload.WriteJSON(cmd.OutOrStdout(), report)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: stats, markdown, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	reportCmd.Flags().StringVar(&format, "format", "stats", "Format of the report. Possible values: stats, markdown, json")
+	reportCmd.Flags().DurationVar(&since, "since", 90*24*time.Hour, "How far back the change history is analyzed. Zero means the whole history")
+	reportCmd.Flags().IntVar(&opts.Limit, "limit", 0, "Maximum number of reported owners. Zero means no limit")
+	reportCmd.Flags().Float64Var(&opts.BottleneckFactor, "bottleneck-factor", reviewload.DefaultBottleneckFactor, "Report owners with at least this many times more review requests than the average owner as bottlenecks")
+	reportCmd.Flags().BoolVar(&opts.SkipGenerated, "skip-generated", false, "Exclude files marked as linguist-generated or linguist-vendored in .gitattributes")
+	reportCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return reportCmd
+}
@@ -0,0 +1,354 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/internal/store"
+	"go.szostok.io/codeowners/internal/workspace"
+	"go.szostok.io/codeowners/pkg/api"
+	"google.golang.org/grpc"
+)
+
+// serverShutdownTimeout is the time given to in-flight requests to finish.
+const serverShutdownTimeout = 10 * time.Second
+
+func serveCmd(cfg *config.Config) *cobra.Command {
+	var (
+		addr       string
+		grpcAddr   string
+		file       string
+		rulesetTTL time.Duration
+		useGitHub  bool
+
+		scanInterval   time.Duration
+		snapshotDir    string
+		storeDSN       string
+		storeRetention time.Duration
+		notifyURL      string
+
+		remindInterval   time.Duration
+		remindWarnBefore time.Duration
+
+		tenantsFile string
+		tenantsRepo string
+
+		adminToken string
+		readToken  string
+	)
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve ownership queries over HTTP and gRPC",
+		Long: `Serve ownership queries over HTTP, so internal tools can resolve owners of repository paths centrally:
+
+  GET  /owners?repo=org/repo&path=docs/README.md   the path parameter can be repeated
+  POST /match  {"repo": "org/repo", "paths": ["docs/README.md"]}
+
+If the --grpc-addr flag is set, the codeowners.ownership.v1.OwnershipService gRPC service is served as well.
+It streams path batches for the owners resolution and coverage, and validates CODEOWNERS content with the offline checks.
+
+CODEOWNERS files are read from the repositories listed in the workspace file and, if GitHub authorization
+is configured, fetched from the default branch of other GitHub repositories. Parsed files are cached, and so are
+repositories without the CODEOWNERS file.
+
+If the --read-token flag is set, ownership queries must send the token in the 'Authorization: Bearer <token>' header,
+or the 'authorization' metadata over gRPC. The token is required when files are fetched from the VCS provider,
+as otherwise anyone who can reach the server could read CODEOWNERS files of all repositories available to its credentials.
+Without the token, only repositories listed in the workspace file are served.
+
+If the --scan-interval flag is set, local repositories are scanned periodically for unowned directories and issues
+reported by the offline checks. Findings are stored as snapshots, and only their changes are posted to the --notify-url webhook.
+Snapshots are stored as JSON files, or, if the --store-dsn flag is set, in a SQLite or Postgres database which keeps
+the history of all scans.
+
+If the --remind-interval flag is set, an issue is opened in each local GitHub repository for every team with entries
+which ownership expires within --remind-warn-before, patterns which don't match any file, or unowned directories next
+to its areas. Issues are updated on each run and closed once there is nothing left to review.
+
+If the --tenants-file flag is set, repositories are assigned to tenants, e.g. business units, by their organization
+or name. Each tenant has its own policy: the enabled offline checks, severity overrides, and the webhook URL for
+changed findings. With the --tenants-repo flag, the file is fetched from the central config repository instead.
+The configuration is reloaded after the --ruleset-ttl period.
+
+If the --admin-token flag is set, the admin endpoints are served under the /admin/ path for the 'ctl' command:
+they list local repositories with the status of their last scan, return the last findings, and revalidate
+repositories on demand. Requests must send the token in the 'Authorization: Bearer <token>' header.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+
+			paths, err := workspacePaths(log, file)
+			exitOnError(err)
+			if useGitHub && hasProviderAuth(cfg) && readToken == "" {
+				exitOnError(errors.New("fetching CODEOWNERS files from the VCS provider requires the --read-token flag, set it or disable fetching with --github=false"))
+			}
+			source, err := rulesetSource(cmd.Context(), log, cfg, paths, useGitHub)
+			exitOnError(err)
+
+			svc := server.NewService(server.NewCachedSource(source, rulesetTTL),
+				check.NewValidSyntax(),
+				check.NewDuplicatedPattern(),
+				check.NewOwnerCasing(),
+				check.NewUnsafePattern(),
+				check.NewInvisibleChars(),
+			)
+			tenants, err := serveTenants(cmd.Context(), cfg, tenantsFile, tenantsRepo, rulesetTTL)
+			exitOnError(err)
+			if tenants != nil {
+				svc.WithTenants(tenants)
+			}
+
+			handler := server.NewHandler(log, svc)
+			var grpcOpts []grpc.ServerOption
+			if readToken != "" {
+				redact.AddSecrets(readToken)
+				handler.WithReadToken(readToken)
+				grpcOpts = server.TokenAuth(readToken)
+			}
+			if scanInterval > 0 || adminToken != "" {
+				if len(paths) == 0 {
+					exitOnError(errors.New("scanning and the admin API require local repositories listed in the workspace file"))
+				}
+				var notifier server.Notifier
+				switch {
+				case tenants != nil:
+					notifier = server.NewTenantNotifier(tenants, notifyURL)
+				case notifyURL != "":
+					notifier = server.NewWebhookNotifier(notifyURL)
+				case scanInterval > 0:
+					exitOnError(errors.New("scanning requires the --notify-url flag"))
+				}
+				snapshots, err := snapshotStore(log, snapshotDir, storeDSN, storeRetention)
+				exitOnError(err)
+				watcher := server.NewWatcher(log, svc, paths, snapshots, notifier)
+				if scanInterval > 0 {
+					log.Infof("Scanning local repositories every %s", scanInterval)
+					go watcher.Run(cmd.Context(), scanInterval)
+				}
+				if adminToken != "" {
+					redact.AddSecrets(adminToken)
+					handler.WithAdmin(server.NewAdminHandler(log, adminToken, watcher, tenants))
+					log.Info("Serving the admin API under /admin/")
+				}
+			}
+			if remindInterval > 0 {
+				if len(paths) == 0 {
+					exitOnError(errors.New("reminders require local repositories listed in the workspace file"))
+				}
+				if !provider.IsGitHub(cfg) || !hasProviderAuth(cfg) {
+					exitOnError(errors.New("reminders require GitHub authorization"))
+				}
+				ghClient, _, err := github.NewClient(cmd.Context(), cfg)
+				exitOnError(err)
+
+				reminders := server.NewReminders(log, paths, server.NewGitHubIssueTracker(ghClient), remindWarnBefore)
+				log.Infof("Reminding owners every %s", remindInterval)
+				go reminders.Run(cmd.Context(), remindInterval)
+			}
+
+			srv := &http.Server{
+				Addr:              addr,
+				Handler:           handler,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			var grpcSrv *grpc.Server
+			if grpcAddr != "" {
+				lis, err := net.Listen("tcp", grpcAddr)
+				exitOnError(err)
+
+				grpcSrv = grpc.NewServer(grpcOpts...)
+				server.NewGRPCServer(log, svc).Register(grpcSrv)
+
+				log.Infof("Serving ownership queries over gRPC on %s", grpcAddr)
+				go func() {
+					exitOnError(grpcSrv.Serve(lis))
+				}()
+			}
+
+			go func() {
+				<-cmd.Context().Done()
+				ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+				defer cancel()
+				if grpcSrv != nil {
+					go func() {
+						<-ctx.Done()
+						grpcSrv.Stop()
+					}()
+					grpcSrv.GracefulStop()
+				}
+				if err := srv.Shutdown(ctx); err != nil {
+					log.WithError(err).Error("Cannot shut down server gracefully")
+				}
+			}()
+
+			log.Infof("Serving ownership queries on %s", addr)
+			if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+				exitOnError(err)
+			}
+		},
+	}
+
+	addGitHubFlags(serveCmd)
+	addVCSFlags(serveCmd)
+	serveCmd.Flags().StringVar(&addr, "addr", ":8080", "The address on which the server listens")
+	serveCmd.Flags().StringVar(&grpcAddr, "grpc-addr", "", "The address on which the gRPC server listens. If empty, gRPC is disabled")
+	serveCmd.Flags().StringVar(&file, "file", "", "Path to the workspace file which lists local repositories. Defaults to "+workspace.DefaultFilename+" if it exists")
+	serveCmd.Flags().DurationVar(&rulesetTTL, "ruleset-ttl", 5*time.Minute, "How long parsed CODEOWNERS files are cached")
+	serveCmd.Flags().BoolVar(&useGitHub, "github", true, "Fetch CODEOWNERS files of repositories not listed in the workspace file from the VCS provider, GitHub by default, if its authorization is configured")
+	serveCmd.Flags().DurationVar(&scanInterval, "scan-interval", 0, "How often local repositories are scanned for changed findings. If zero, scanning is disabled")
+	serveCmd.Flags().StringVar(&snapshotDir, "snapshot-dir", ".codeowners-snapshots", "Directory in which snapshots of findings are stored")
+	serveCmd.Flags().StringVar(&storeDSN, "store-dsn", "", "The database in which snapshots are stored with their history: a path to the SQLite file, or a postgres:// URL. If empty, only the last snapshots are stored in the --snapshot-dir directory")
+	serveCmd.Flags().DurationVar(&storeRetention, "store-retention", 90*24*time.Hour, "How long snapshots are kept in the database. If zero, they are never pruned")
+	serveCmd.Flags().StringVar(&notifyURL, "notify-url", "", "The webhook URL to which changed findings are posted. With tenants, it's used by policies without the webhook URL")
+	serveCmd.Flags().DurationVar(&remindInterval, "remind-interval", 0, "How often reminder issues are synced with the ownership problems of each team. If zero, reminders are disabled")
+	serveCmd.Flags().DurationVar(&remindWarnBefore, "remind-warn-before", 14*24*time.Hour, "How long before the expiration date the ownership is included in reminders")
+
+	serveCmd.Flags().StringVar(&tenantsFile, "tenants-file", "", "Path to the file which assigns repositories to tenants with their own policies. If empty, all repositories share the same policy")
+	serveCmd.Flags().StringVar(&adminToken, "admin-token", "", "The token which authenticates requests to the admin API. If empty, the admin API is disabled")
+	serveCmd.Flags().StringVar(&readToken, "read-token", "", "The token which authenticates ownership queries. Required if CODEOWNERS files are fetched from the VCS provider")
+	serveCmd.Flags().StringVar(&tenantsRepo, "tenants-repo", "", "The central config repository in form 'owner/repository', from which the --tenants-file is fetched. If empty, the file is read from the local disk")
+
+	return serveCmd
+}
+
+// snapshotStore returns the database store if the DSN is set, or the JSON files store otherwise.
+func snapshotStore(log logrus.FieldLogger, dir, dsn string, retention time.Duration) (server.SnapshotStore, error) {
+	if dsn == "" {
+		return server.NewFileSnapshotStore(dir), nil
+	}
+
+	redact.AddSecrets(dsnPassword(dsn))
+	s, err := store.Open(dsn, retention)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Storing snapshots in the database")
+	return s, nil
+}
+
+// dsnPassword returns the password of the database URL, so it's scrubbed from logs.
+func dsnPassword(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil {
+		return ""
+	}
+	p, _ := u.User.Password()
+	return p
+}
+
+// serveTenants returns the tenants of the server, or nil if they are not configured. The configuration is loaded
+// immediately, so the server doesn't start with a broken one.
+func serveTenants(ctx context.Context, cfg *config.Config, file, repo string, ttl time.Duration) (*server.Tenants, error) {
+	if file == "" {
+		if repo != "" {
+			return nil, errors.New("the --tenants-repo flag requires the --tenants-file flag")
+		}
+		return nil, nil
+	}
+
+	var source server.TenantSource = server.NewFileTenantSource(file)
+	if repo != "" {
+		if !hasProviderAuth(cfg) {
+			return nil, errors.New("fetching tenants from the central config repository requires authorization of the VCS provider")
+		}
+		p, err := provider.New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		source = server.NewRepositoryTenantSource(p, repo, file)
+	}
+
+	tenants := server.NewTenants(source, map[string]api.Checker{
+		"syntax":          check.NewValidSyntax(),
+		"duppatterns":     check.NewDuplicatedPattern(),
+		"owner-casing":    check.NewOwnerCasing(),
+		"unsafe-patterns": check.NewUnsafePattern(),
+		"invisible-chars": check.NewInvisibleChars(),
+	}, ttl)
+	if _, err := tenants.Config(ctx); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// workspacePaths returns paths of the local repositories listed in the workspace file, indexed by the repository name.
+// It returns nil if there is no workspace file.
+func workspacePaths(log logrus.FieldLogger, file string) (map[string]string, error) {
+	if file == "" {
+		if _, err := os.Stat(workspace.DefaultFilename); err == nil {
+			file = workspace.DefaultFilename
+		}
+	}
+	if file == "" {
+		return nil, nil
+	}
+
+	ws, err := workspace.Load(file)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	for _, repo := range ws.Repositories {
+		name := repo.Repository
+		if name == "" {
+			remote, err := git.DetectRemote(repo.Path, "origin")
+			if err != nil {
+				log.WithError(err).Warnf("Skipping %s, cannot detect repository name", repo.Path)
+				continue
+			}
+			name = remote.Repository()
+		}
+		paths[name] = repo.Path
+		log.Infof("Serving %s from %s", name, repo.Path)
+	}
+	return paths, nil
+}
+
+// rulesetSource returns the source of CODEOWNERS files: first the local repositories, then GitHub.
+// hasProviderAuth returns true if the authorization of the configured VCS provider is set.
+func hasProviderAuth(cfg *config.Config) bool {
+	if provider.IsGitHub(cfg) {
+		return cfg.GithubAccessToken != "" || cfg.GithubAppID != 0
+	}
+	return cfg.VCSToken != ""
+}
+
+func rulesetSource(ctx context.Context, log logrus.FieldLogger, cfg *config.Config, paths map[string]string, useGitHub bool) (server.RulesetSource, error) {
+	var chain server.ChainSource
+
+	if paths != nil {
+		chain = append(chain, server.NewLocalSource(paths))
+	}
+
+	if useGitHub && hasProviderAuth(cfg) {
+		p, err := provider.New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, server.NewProviderSource(p))
+		log.Infof("Serving other repositories from %s", p.Name())
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("no CODEOWNERS source configured, provide the workspace file or GitHub authorization")
+	}
+	return chain, nil
+}
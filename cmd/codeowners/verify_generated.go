@@ -0,0 +1,100 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/generate"
+	"go.szostok.io/codeowners/internal/textdiff"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func verifyGeneratedCmd(cfg *config.Config) *cobra.Command {
+	var (
+		generatorCmd string
+		source       string
+	)
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify-generated",
+		Short: "Verify that the committed CODEOWNERS file is exactly what the generation produces",
+		Long: `Verify that the committed CODEOWNERS file is exactly what the generation produces.
+
+The expected content is either printed to stdout by the generator command executed in the repository
+directory, or generated from the ownership source of truth in YAML format, e.g.:
+
+  header: |
+    # Generated from ownership.yaml, do not edit.
+  owners:
+    - owner: "@org/platform"
+      paths: [/infra/, /deploy/]
+
+If the committed file differs, the diff is printed and the command exits with code 3.`,
+		Example: `  codeowners verify-generated --generator-command 'go run ./hack/gen-codeowners'
+  codeowners verify-generated --source ownership.yaml`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if (generatorCmd == "") == (source == "") {
+				exitOnError(errors.New("exactly one of --generator-command or --source is required"))
+			}
+
+			path, err := codeowners.FindCodeownersFile(cfg.RepositoryPath)
+			exitOnError(err)
+
+			committed, err := os.ReadFile(path)
+			exitOnError(err)
+
+			var expected []byte
+			if generatorCmd != "" {
+				expected, err = runGenerator(cfg.RepositoryPath, generatorCmd)
+			} else {
+				expected, err = generateFromSource(source)
+			}
+			exitOnError(err)
+
+			if bytes.Equal(committed, expected) {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", path)
+				return
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s differs from the generated content:\n%s", path, textdiff.Lines(string(committed), string(expected)))
+			os.Exit(3)
+		},
+	}
+
+	verifyCmd.Flags().StringVar(&generatorCmd, "generator-command", "", "Shell command which prints the expected CODEOWNERS content to stdout")
+	verifyCmd.Flags().StringVar(&source, "source", "", "Path to the ownership source of truth file in YAML format")
+	verifyCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return verifyCmd
+}
+
+func runGenerator(repoPath, command string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	gen := exec.Command("sh", "-c", command)
+	gen.Dir = repoPath
+	gen.Stdout, gen.Stderr = &stdout, &stderr
+	if err := gen.Run(); err != nil {
+		return nil, errors.Wrapf(err, "while running generator command %q: %s", command, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func generateFromSource(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening ownership source")
+	}
+	defer f.Close()
+
+	return generate.FromSource(f)
+}
@@ -0,0 +1,146 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func whoOwnsCmd(cfg *config.Config) *cobra.Command {
+	var (
+		format      string
+		unownedOnly bool
+	)
+
+	whoOwnsCmd := &cobra.Command{
+		Use:   "who-owns PATH...",
+		Short: "Print the CODEOWNERS entry which owns given paths or globs",
+		Long: `Print the CODEOWNERS entry which owns given paths, with its pattern, line number, and owners, e.g. to find
+who to page during an incident.
+
+As in GitHub, the last matching pattern takes the most precedence. Arguments with the *, ?, or [ characters
+are globs written in the CODEOWNERS pattern syntax, expanded to files tracked in the repository. Other arguments
+are resolved as they are, so deleted or not yet added files can be queried too. Absolute paths are converted
+to paths relative to the repository root.
+
+With the --unowned-only flag, only paths which match no CODEOWNERS entry are printed.`,
+		Example: `  codeowners who-owns internal/server/server.go
+  codeowners who-owns 'docs/**' '*.proto' --format json
+  codeowners who-owns 'cmd/**' --unowned-only`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := codeowners.NewFromPath(cfg.RepositoryPath)
+			exitOnError(err)
+
+			ruleset, err := server.NewRuleset("", entries)
+			exitOnError(err)
+
+			paths, err := expandWhoOwnsArgs(cfg.RepositoryPath, args)
+			exitOnError(err)
+
+			out := []server.Resolution{}
+			for _, p := range paths {
+				r := ruleset.Resolve(p)
+				if unownedOnly && r.Pattern != "" {
+					continue
+				}
+				out = append(out, r)
+			}
+
+			switch format {
+			case "text":
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "PATH\tPATTERN\tLINE\tOWNERS")
+				for _, r := range out {
+					pattern, line, owners := "-", "-", "nobody"
+					if r.Pattern != "" {
+						pattern, line = r.Pattern, fmt.Sprint(r.Line)
+					}
+					if len(r.Owners) > 0 {
+						owners = strings.Join(r.Owners, ",")
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Path, pattern, line, owners)
+				}
+				err = w.Flush()
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				err = enc.Encode(out)
+			default:
+				err = errors.Errorf("unknown format %q, possible values: text, json", format)
+			}
+			exitOnError(err)
+		},
+	}
+
+	whoOwnsCmd.Flags().StringVar(&format, "format", "text", "Format of the output. Possible values: text, json")
+	whoOwnsCmd.Flags().BoolVar(&unownedOnly, "unowned-only", false, "Print only paths which match no CODEOWNERS entry")
+	whoOwnsCmd.Flags().String("repository-path", ".", "Path to your repository on your local machine")
+
+	return whoOwnsCmd
+}
+
+// expandWhoOwnsArgs returns paths relative to the repository root. Globs are expanded to files tracked
+// in the repository, in the order of the git index, and each path is returned only once.
+func expandWhoOwnsArgs(repoPath string, args []string) ([]string, error) {
+	absRepo, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out     []string
+		seen    = map[string]struct{}{}
+		tracked []string
+	)
+	add := func(p string) {
+		if _, dup := seen[p]; !dup {
+			seen[p] = struct{}{}
+			out = append(out, p)
+		}
+	}
+
+	for _, arg := range args {
+		if filepath.IsAbs(arg) {
+			if arg, err = codeowners.RelPath(absRepo, arg); err != nil {
+				return nil, err
+			}
+		}
+		if !strings.ContainsAny(arg, "*?[") {
+			add(codeowners.NormalizePath(arg))
+			continue
+		}
+
+		glob, err := codeowners.NewPattern(arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while compiling glob %q", arg)
+		}
+		if tracked == nil {
+			if tracked, err = git.ListFiles(repoPath); err != nil {
+				return nil, errors.Wrap(err, "while listing repository files")
+			}
+		}
+		matched := false
+		for _, f := range tracked {
+			if glob.Match(f) {
+				matched = true
+				add(f)
+			}
+		}
+		if !matched {
+			return nil, errors.Errorf("glob %q doesn't match any file tracked in the repository", arg)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,169 @@
+//go:build !minimal
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/load"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/internal/workspace"
+)
+
+func workspaceCmd(cfg *config.Config) *cobra.Command {
+	var (
+		file               string
+		resume             bool
+		progressFile       string
+		rateLimitThreshold int
+	)
+
+	workspaceCmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Validate CODEOWNERS files of all repositories listed in a workspace file",
+		Long: `Validate CODEOWNERS files of all repositories listed in a workspace file and print a combined report.
+
+Each repository can override the providers, checks, experimental checks, and check failure level.
+GitHub API responses are cached and shared between repositories.
+
+Results of validated repositories are saved in the progress file. If the run is interrupted, e.g. by a crash
+or a signal, rerun it with the --resume flag to validate only the remaining repositories. Repositories which
+failed with a temporary error, e.g. an exceeded API rate limit, are validated again as well. The progress file
+is removed once all repositories are validated.
+
+Repositories validated with checks which call the GitHub API are interleaved with the ones which don't.
+When the remaining API rate limit falls below the --rate-limit-threshold, only the latter are validated,
+and once none of them are left, the run pauses until the rate limit resets.`,
+		Example: `  codeowners workspace --file codeowners-workspace.yaml
+  codeowners workspace --file codeowners-workspace.yaml --resume`,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := redact.NewLogger()
+
+			ws, err := workspace.Load(file)
+			exitOnError(err)
+
+			repos, err := ws.Configs(*cfg)
+			exitOnError(err)
+
+			if progressFile == "" {
+				progressFile = workspace.ProgressPath(file)
+			}
+			var progress *workspace.Progress
+			if resume {
+				progress, err = workspace.LoadProgress(progressFile, file)
+			} else {
+				progress, err = workspace.NewProgress(file)
+			}
+			exitOnError(err)
+
+			cache := loadGitHubCache(log, cfg)
+			if cache == nil {
+				cache = github.NewResponseCache()
+			}
+			rateLimit := github.NewRateLimitTracker()
+			out := cmd.OutOrStdout()
+
+			// progress is keyed by the absolute path, so the run can be resumed from a different directory
+			progressKey := func(repo workspace.RepositoryConfig) string {
+				key, err := filepath.Abs(repo.Config.RepositoryPath)
+				exitOnError(err)
+				return key
+			}
+			// repositories validated by the interrupted run don't use the API anymore
+			apiWeight := func(repo workspace.RepositoryConfig) int {
+				if _, done := progress.Done(progressKey(repo)); done {
+					return 0
+				}
+				return len(load.GitHubChecks(repo.Config))
+			}
+
+			var (
+				failed   []string
+				retry    int
+				exitCode = runner.ExitCodeOK
+			)
+			scheduler := workspace.NewScheduler(log, rateLimit, rateLimitThreshold, apiWeight)
+			err = scheduler.Run(cmd.Context(), repos, func(repo workspace.RepositoryConfig) {
+				fmt.Fprintf(out, "\n### Repository %s\n\n", repo.Name)
+
+				key := progressKey(repo)
+				res, done := progress.Done(key)
+				if done {
+					fmt.Fprintln(out, "    Skipped, validated by the interrupted run")
+				} else {
+					res = validateRepository(cmd, log, repo, github.WithResponseCache(cache), github.WithRateLimitTracker(rateLimit))
+					if res.ExitCode == runner.ExitCodeRetryableError {
+						retry++
+					} else {
+						progress.Record(key, res)
+						if err := progress.Save(progressFile); err != nil {
+							log.WithError(err).Warn("Cannot save the workspace progress")
+						}
+					}
+				}
+
+				switch {
+				case res.Error != "":
+					failed = append(failed, fmt.Sprintf("%s (%s)", repo.Name, res.Error))
+				case res.ExitCode != runner.ExitCodeOK:
+					failed = append(failed, repo.Name)
+				}
+				// check failures take precedence over temporary errors
+				if res.ExitCode == runner.ExitCodeCheckFailure || exitCode == runner.ExitCodeOK {
+					exitCode = res.ExitCode
+				}
+			})
+			if cmd.Context().Err() != nil {
+				log.Error("Application was interrupted by operating system, rerun with --resume to validate the remaining repositories")
+				os.Exit(2)
+			}
+			exitOnError(err)
+
+			fmt.Fprintf(out, "\n%d repository(ies) validated, %d failure(s)\n", len(repos), len(failed))
+			for _, name := range failed {
+				fmt.Fprintf(out, "    - %s\n", name)
+			}
+
+			if retry > 0 {
+				log.Infof("%d repository(ies) failed with a temporary error, rerun with --resume to validate only them", retry)
+			} else if err := os.Remove(progressFile); err != nil && !os.IsNotExist(err) {
+				log.WithError(err).Warn("Cannot remove the workspace progress")
+			}
+
+			if exitCode != runner.ExitCodeOK {
+				os.Exit(exitCode)
+			}
+		},
+	}
+
+	addValidateFlags(workspaceCmd)
+	workspaceCmd.Flags().StringVar(&file, "file", workspace.DefaultFilename, "Path to the workspace file")
+	workspaceCmd.Flags().BoolVar(&resume, "resume", false, "Resume the interrupted run, skipping repositories already validated by it")
+	workspaceCmd.Flags().IntVar(&rateLimitThreshold, "rate-limit-threshold", 500, "Remaining GitHub API requests below which only repositories validated without GitHub API calls are scheduled, and the run pauses until the rate limit resets if none of them are left. Zero disables pausing")
+	workspaceCmd.Flags().StringVar(&progressFile, "progress-file", "", "File where results of validated repositories are saved. Defaults to the workspace file name with the .progress.json extension")
+
+	return workspaceCmd
+}
+
+// validateRepository validates a single workspace repository. The application exits if it was interrupted,
+// so the repository is not recorded as completed, and is validated again by the resumed run.
+func validateRepository(cmd *cobra.Command, log logrus.FieldLogger, repo workspace.RepositoryConfig, opts ...github.ClientOption) workspace.Result {
+	checkRunner, err := validate(cmd.Context(), log, repo.Config, opts...)
+	if cmd.Context().Err() != nil {
+		log.Error("Application was interrupted by operating system, rerun with --resume to validate the remaining repositories")
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "    [Error] %s\n", err)
+		return workspace.Result{Name: repo.Name, ExitCode: runner.ExitCodeCheckFailure, Error: err.Error()}
+	}
+	return workspace.Result{Name: repo.Name, ExitCode: checkRunner.ExitCode()}
+}
@@ -0,0 +1,57 @@
+//go:build cgo
+
+// Command libcodeowners is the C shared library of the CODEOWNERS parser, matcher, and syntax check, built with:
+//
+//	go build -buildmode=c-shared -o libcodeowners.so ./cmd/libcodeowners
+//
+// All functions take a NUL-terminated JSON request and return a NUL-terminated JSON response, as described in
+// the internal/bindings package. Responses are allocated by the library and must be released with codeowners_free.
+// The ABI is versioned with codeowners_abi_version, which is bumped on incompatible changes.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"unsafe"
+
+	"go.szostok.io/codeowners/internal/bindings"
+)
+
+// abiVersion is the version of the exported functions and the JSON format.
+const abiVersion = 1
+
+//export codeowners_abi_version
+func codeowners_abi_version() C.int { //nolint:revive // the C ABI name
+	return abiVersion
+}
+
+//export codeowners_parse
+func codeowners_parse(request *C.char) *C.char { //nolint:revive // the C ABI name
+	return call(bindings.ParseFunc, request)
+}
+
+//export codeowners_match
+func codeowners_match(request *C.char) *C.char { //nolint:revive // the C ABI name
+	return call(bindings.MatchFunc, request)
+}
+
+//export codeowners_validate
+func codeowners_validate(request *C.char) *C.char { //nolint:revive // the C ABI name
+	return call(bindings.ValidateFunc, request)
+}
+
+//export codeowners_free
+func codeowners_free(response *C.char) { //nolint:revive // the C ABI name
+	C.free(unsafe.Pointer(response))
+}
+
+func call(fn string, request *C.char) *C.char {
+	return C.CString(string(bindings.Call(context.Background(), fn, []byte(C.GoString(request)))))
+}
+
+// main is required by the c-shared build mode, but it's never called.
+func main() {}
@@ -0,0 +1,167 @@
+package badge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// Badge is a label and message pair rendered as a status badge.
+type Badge struct {
+	Label   string
+	Message string
+	// Color is one of the shields.io named colors, e.g. brightgreen or red.
+	Color string
+}
+
+// CoverageOptions customizes the coverage computation.
+type CoverageOptions struct {
+	// SkipGenerated excludes files marked as `linguist-generated` or `linguist-vendored` in .gitattributes.
+	SkipGenerated bool
+	// Scope limits files to the ones in a given area of the repository. All files are counted if it's nil.
+	Scope *api.Scope
+}
+
+// Coverage returns the badge with the percentage of repository files which have owners.
+func Coverage(repoDir string, entries []codeowners.Entry, opts CoverageOptions) (Badge, error) {
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return Badge{}, errors.Wrap(err, "while compiling CODEOWNERS patterns")
+	}
+
+	files, err := git.ListFiles(repoDir)
+	if err != nil {
+		return Badge{}, errors.Wrap(err, "while listing repository files")
+	}
+	if opts.SkipGenerated {
+		files, err = git.WithoutGenerated(repoDir, files)
+		if err != nil {
+			return Badge{}, errors.Wrap(err, "while excluding generated files")
+		}
+	}
+	files = opts.Scope.Filter(files)
+
+	owned := 0
+	for _, f := range files {
+		if entry, found := matcher.Match(f); found && len(entry.Owners) > 0 {
+			owned++
+		}
+	}
+
+	percent := 100.0
+	if len(files) > 0 {
+		percent = float64(owned) * 100 / float64(len(files))
+	}
+
+	return Badge{
+		Label:   "ownership",
+		Message: formatPercent(percent),
+		Color:   coverageColor(percent),
+	}, nil
+}
+
+// formatPercent rounds down, so that the badge shows 100% only if all files are owned.
+func formatPercent(percent float64) string {
+	return fmt.Sprintf("%d%%", int(percent))
+}
+
+func coverageColor(percent float64) string {
+	switch {
+	case percent >= 95:
+		return "brightgreen"
+	case percent >= 80:
+		return "green"
+	case percent >= 60:
+		return "yellow"
+	case percent >= 40:
+		return "orange"
+	default:
+		return "red"
+	}
+}
+
+// WriteShieldsJSON writes the badge as the shields.io endpoint JSON, see: https://shields.io/badges/endpoint-badge
+func WriteShieldsJSON(w io.Writer, b Badge) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		Label         string `json:"label"`
+		Message       string `json:"message"`
+		Color         string `json:"color"`
+	}{
+		SchemaVersion: 1,
+		Label:         b.Label,
+		Message:       b.Message,
+		Color:         b.Color,
+	})
+}
+
+// colors maps the shields.io named colors to their hex values.
+var colors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+}
+
+var svgTpl = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{ .Width }}" height="20" role="img" aria-label="{{ .Label }}: {{ .Message }}">
+  <title>{{ .Label }}: {{ .Message }}</title>
+  <linearGradient id="s" x2="0" y2="100%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r">
+    <rect width="{{ .Width }}" height="20" rx="3" fill="#fff"/>
+  </clipPath>
+  <g clip-path="url(#r)">
+    <rect width="{{ .LabelWidth }}" height="20" fill="#555"/>
+    <rect x="{{ .LabelWidth }}" width="{{ .MessageWidth }}" height="20" fill="{{ .Color }}"/>
+    <rect width="{{ .Width }}" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="{{ .LabelX }}" y="15" fill="#010101" fill-opacity=".3">{{ .Label }}</text>
+    <text x="{{ .LabelX }}" y="14">{{ .Label }}</text>
+    <text x="{{ .MessageX }}" y="15" fill="#010101" fill-opacity=".3">{{ .Message }}</text>
+    <text x="{{ .MessageX }}" y="14">{{ .Message }}</text>
+  </g>
+</svg>
+`))
+
+// WriteSVG renders the badge in the shields.io flat style. Text widths are approximated, so the output
+// does not depend on the fonts installed on the host.
+func WriteSVG(w io.Writer, b Badge) error {
+	color, found := colors[b.Color]
+	if !found {
+		return errors.Errorf("unknown color %q", b.Color)
+	}
+
+	labelWidth, messageWidth := textWidth(b.Label), textWidth(b.Message)
+	return svgTpl.Execute(w, map[string]interface{}{
+		"Label":        b.Label,
+		"Message":      b.Message,
+		"Color":        color,
+		"Width":        labelWidth + messageWidth,
+		"LabelWidth":   labelWidth,
+		"MessageWidth": messageWidth,
+		"LabelX":       float64(labelWidth) / 2,
+		"MessageX":     float64(labelWidth) + float64(messageWidth)/2,
+	})
+}
+
+// textWidth approximates the width of the text rendered with the 11px Verdana font, including the horizontal padding.
+func textWidth(text string) int {
+	const (
+		charWidth = 7
+		padding   = 10
+	)
+	return len([]rune(text))*charWidth + padding
+}
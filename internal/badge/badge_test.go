@@ -0,0 +1,121 @@
+package badge_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/badge"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	// given
+	repoDir := prepareRepo(t)
+
+	tests := map[string]struct {
+		codeowners string
+		opts       badge.CoverageOptions
+		expBadge   badge.Badge
+	}{
+		"All files owned": {
+			codeowners: "* @org/platform",
+			expBadge:   badge.Badge{Label: "ownership", Message: "100%", Color: "brightgreen"},
+		},
+		"Partially owned": {
+			codeowners: "/svc/ @org/svc\n/docs/ @org/docs\n/docs/drafts/",
+			expBadge:   badge.Badge{Label: "ownership", Message: "50%", Color: "orange"},
+		},
+		"Skip generated and vendored files": {
+			codeowners: "/svc/ @org/svc\n/docs/ @org/docs\n/docs/drafts/",
+			opts:       badge.CoverageOptions{SkipGenerated: true},
+			expBadge:   badge.Badge{Label: "ownership", Message: "60%", Color: "yellow"},
+		},
+		"Only files in scope": {
+			codeowners: "/svc/api/ @org/api\n/docs/ @org/docs",
+			opts:       badge.CoverageOptions{Scope: mustScope(t, []string{"/svc/"}, nil)},
+			expBadge:   badge.Badge{Label: "ownership", Message: "50%", Color: "orange"},
+		},
+		"Nothing owned": {
+			codeowners: "",
+			expBadge:   badge.Badge{Label: "ownership", Message: "0%", Color: "red"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			entries := codeowners.ParseCodeowners(strings.NewReader(tc.codeowners))
+
+			// when
+			got, err := badge.Coverage(repoDir, entries, tc.opts)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expBadge, got)
+		})
+	}
+}
+
+func TestWriteShieldsJSON(t *testing.T) {
+	// given
+	var buff bytes.Buffer
+
+	// when
+	err := badge.WriteShieldsJSON(&buff, badge.Badge{Label: "ownership", Message: "87%", Color: "green"})
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion": 1, "label": "ownership", "message": "87%", "color": "green"}`, buff.String())
+}
+
+func TestWriteSVG(t *testing.T) {
+	// given
+	var buff bytes.Buffer
+
+	// when
+	err := badge.WriteSVG(&buff, badge.Badge{Label: "ownership", Message: "87%", Color: "green"})
+
+	// then
+	require.NoError(t, err)
+	g := goldie.New(t, goldie.WithNameSuffix(".golden.svg"))
+	g.Assert(t, t.Name(), buff.Bytes())
+}
+
+func TestWriteSVGUnknownColor(t *testing.T) {
+	// when
+	err := badge.WriteSVG(&bytes.Buffer{}, badge.Badge{Label: "ownership", Message: "87%", Color: "purple"})
+
+	// then
+	assert.EqualError(t, err, `unknown color "purple"`)
+}
+
+func mustScope(t *testing.T, include, exclude []string) *api.Scope {
+	t.Helper()
+
+	scope, err := api.NewScope(include, exclude)
+	require.NoError(t, err)
+	return scope
+}
+
+func prepareRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := gitrepo.New(t)
+
+	files := map[string]string{
+		".gitattributes":       "gen/** linguist-generated\n",
+		"docs/index.md":        "docs",
+		"docs/drafts/todo.md":  "todo",
+		"gen/api.pb.go":        "generated",
+		"svc/api/main.go":      "api",
+		"svc/worker/worker.go": "worker",
+	}
+	gitrepo.CommitFiles(t, dir, files)
+
+	return dir
+}
@@ -0,0 +1,229 @@
+// Package baseline records issues found in a repository, so they don't fail later validations, while new issues do.
+// It allows adopting the validation in repositories with many existing problems, e.g. thousands of not owned files.
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultFilename is the name of the baseline file searched for in the repository root.
+const DefaultFilename = ".codeowners-baseline.json"
+
+// Version is the version of the baseline file format.
+const Version = 1
+
+// File is the content of the baseline file.
+type File struct {
+	Version  int       `json:"version"`
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is a single known issue, or a single file listed by an issue, e.g. a not owned file.
+type Finding struct {
+	// Check is the name of the check which reported the issue.
+	Check string `json:"check"`
+	// Fingerprint identifies the issue regardless of its line, so moving entries within the CODEOWNERS file
+	// doesn't make known issues new.
+	Fingerprint string `json:"fingerprint"`
+	// Summary describes the issue for people reviewing the baseline, e.g. the path of the not owned file.
+	Summary string `json:"summary"`
+}
+
+// Fingerprinter computes findings of issues.
+type Fingerprinter struct {
+	byLineNo map[uint64]codeowners.Entry
+}
+
+// NewFingerprinter returns new instance of the Fingerprinter for issues of given CODEOWNERS entries.
+func NewFingerprinter(entries []codeowners.Entry) *Fingerprinter {
+	f := &Fingerprinter{byLineNo: map[uint64]codeowners.Entry{}}
+	for _, e := range entries {
+		f.byLineNo[e.LineNo] = e
+	}
+	return f
+}
+
+// volatileParts match parts of messages which change between runs without changing the issue, with their
+// replacements, e.g. references to lines of the CODEOWNERS file, which change when entries are moved,
+// or the number of days since a date, which changes every day.
+var volatileParts = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{re: regexp.MustCompile(`\b(lines?) \d+((, | and )\d+)*`), repl: "$1 N"},
+	{re: regexp.MustCompile(`\((in )?\d+ day\(s\)( ago)?\)`), repl: "(${1}N day(s)${2})"},
+	{re: regexp.MustCompile(`\b\d+ of its file\(s\)`), repl: "N of its file(s)"},
+	{re: regexp.MustCompile(`\btook [0-9][0-9.a-zµ]*`), repl: "took D"},
+}
+
+// Findings returns findings of a given issue. Issues which list files have a finding per file. Other issues
+// are identified by the check, the message, and the entry they are reported for, with volatile parts
+// of the message, such as line numbers, durations, and day counts, dropped.
+func (f *Fingerprinter) Findings(check string, issue api.Issue) []Finding {
+	if len(issue.Paths) > 0 {
+		out := make([]Finding, 0, len(issue.Paths))
+		for _, p := range issue.Paths {
+			out = append(out, Finding{Check: check, Fingerprint: hash(check, "path", p), Summary: p})
+		}
+		return out
+	}
+
+	var entry string
+	if issue.LineNo != nil {
+		if e, found := f.byLineNo[*issue.LineNo]; found {
+			entry = e.Pattern + " " + strings.Join(e.Owners, " ")
+		}
+	}
+	msg := issue.Message
+	for _, v := range volatileParts {
+		msg = v.re.ReplaceAllString(msg, v.repl)
+	}
+	summary, _, _ := strings.Cut(issue.Message, "\n")
+	return []Finding{{Check: check, Fingerprint: hash(check, "issue", entry, msg), Summary: summary}}
+}
+
+func (f Finding) key() string {
+	return f.Check + "\x00" + f.Fingerprint
+}
+
+func hash(parts ...string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(parts, "\x00"))))
+}
+
+// NewFile returns the baseline file with given findings, sorted and without duplicates, so the file
+// doesn't change if the issues are the same.
+func NewFile(findings []Finding) File {
+	seen := map[string]struct{}{}
+	out := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if _, dup := seen[f.key()]; dup {
+			continue
+		}
+		seen[f.key()] = struct{}{}
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Check != out[j].Check {
+			return out[i].Check < out[j].Check
+		}
+		if out[i].Summary != out[j].Summary {
+			return out[i].Summary < out[j].Summary
+		}
+		return out[i].Fingerprint < out[j].Fingerprint
+	})
+	return File{Version: Version, Findings: out}
+}
+
+// Save writes the baseline file to a given path.
+func (f File) Save(path string) error {
+	raw, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "while marshaling baseline")
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+// Baseline holds known issues loaded from the baseline file. It's safe for concurrent use.
+type Baseline struct {
+	known map[string]struct{}
+}
+
+// Find returns the path of the baseline file. The configured path must exist. Otherwise, the default file in
+// the repository root is used if it exists. The path is empty if no baseline is used.
+func Find(repoDir, configured string) (string, error) {
+	if configured != "" {
+		if _, err := os.Stat(configured); err != nil {
+			return "", err
+		}
+		return configured, nil
+	}
+
+	path := filepath.Join(repoDir, DefaultFilename)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// Load returns the baseline with findings read from a given file.
+func Load(path string) (*Baseline, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading baseline")
+	}
+
+	var f File
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, errors.Wrapf(err, "while parsing baseline %s", path)
+	}
+	if f.Version != Version {
+		return nil, errors.Errorf("unsupported version %d of baseline %s, expected %d", f.Version, path, Version)
+	}
+
+	b := &Baseline{known: map[string]struct{}{}}
+	for _, finding := range f.Findings {
+		b.known[finding.key()] = struct{}{}
+	}
+	return b, nil
+}
+
+// MarshalJSON encodes the known findings, e.g. to pass the baseline to a check executed in another process.
+func (b *Baseline) MarshalJSON() ([]byte, error) {
+	known := make([]string, 0, len(b.known))
+	for key := range b.known {
+		known = append(known, key)
+	}
+	sort.Strings(known)
+	return json.Marshal(known)
+}
+
+// UnmarshalJSON decodes the baseline encoded by MarshalJSON.
+func (b *Baseline) UnmarshalJSON(data []byte) error {
+	var known []string
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	b.known = make(map[string]struct{}, len(known))
+	for _, key := range known {
+		b.known[key] = struct{}{}
+	}
+	return nil
+}
+
+// Contains returns true if all given findings are known. No findings are never known.
+func (b *Baseline) Contains(findings []Finding) bool {
+	if b == nil || len(findings) == 0 {
+		return false
+	}
+	for _, f := range findings {
+		if _, found := b.known[f.key()]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// HasPath returns true if a given file listed by an issue of a given check is known. It implements the api.Baseline.
+func (b *Baseline) HasPath(check, path string) bool {
+	if b == nil {
+		return false
+	}
+	_, found := b.known[Finding{Check: check, Fingerprint: hash(check, "path", path)}.key()]
+	return found
+}
@@ -0,0 +1,187 @@
+package baseline_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindingsIgnoreLineNumbers(t *testing.T) {
+	// given
+	before := baseline.NewFingerprinter(codeowners.ParseCodeowners(strings.NewReader("/docs/ @org/docs\n/src/ @org/dev\n")))
+	after := baseline.NewFingerprinter(codeowners.ParseCodeowners(strings.NewReader("# moved\n\n/src/ @org/dev\n/docs/ @org/docs\n")))
+
+	// when
+	known := before.Findings("Duplicated Pattern Checker", api.Issue{LineNo: ptr.Uint64Ptr(2), Message: "Pattern /src/ is defined in lines 2 and 5"})
+	moved := after.Findings("Duplicated Pattern Checker", api.Issue{LineNo: ptr.Uint64Ptr(3), Message: "Pattern /src/ is defined in lines 3 and 7"})
+	other := after.Findings("Duplicated Pattern Checker", api.Issue{LineNo: ptr.Uint64Ptr(4), Message: "Pattern /src/ is defined in lines 3 and 7"})
+
+	// then
+	assert.Equal(t, known[0].Fingerprint, moved[0].Fingerprint)
+	assert.NotEqual(t, known[0].Fingerprint, other[0].Fingerprint)
+	assert.Equal(t, "Pattern /src/ is defined in lines 2 and 5", known[0].Summary)
+}
+
+func TestFindingsIgnoreVolatileParts(t *testing.T) {
+	tests := map[string]struct {
+		check  string
+		before string
+		after  string
+	}{
+		"Days since expiration": {
+			check:  "Expiration Checker",
+			before: `Ownership of "/src/" expired on 2026-01-01 (3 day(s) ago)`,
+			after:  `Ownership of "/src/" expired on 2026-01-01 (4 day(s) ago)`,
+		},
+		"Days until expiration": {
+			check:  "Expiration Checker",
+			before: `Ownership of "/src/" expires on 2026-01-10 (in 7 day(s))`,
+			after:  `Ownership of "/src/" expires on 2026-01-10 (in 6 day(s))`,
+		},
+		"Days since directory creation and number of unowned files": {
+			check:  "Ownership SLA Checker",
+			before: `Directory "src" was created on 2026-01-01 (30 day(s) ago), but 2 of its file(s) still don't have owners after the 14 day(s) grace period`,
+			after:  `Directory "src" was created on 2026-01-01 (31 day(s) ago), but 3 of its file(s) still don't have owners after the 14 day(s) grace period`,
+		},
+		"Measured duration": {
+			check:  "Custom Checker",
+			before: `Matching "/src/**" took 1.2ms`,
+			after:  `Matching "/src/**" took 980µs`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			fingerprints := baseline.NewFingerprinter(nil)
+
+			// when
+			known := fingerprints.Findings(tc.check, api.Issue{Message: tc.before})
+			later := fingerprints.Findings(tc.check, api.Issue{Message: tc.after})
+
+			// then
+			assert.Equal(t, known[0].Fingerprint, later[0].Fingerprint)
+			assert.Equal(t, tc.before, known[0].Summary)
+		})
+	}
+}
+
+func TestFindingsOfExpiredEntryDifferFromExpiringOne(t *testing.T) {
+	// given
+	fingerprints := baseline.NewFingerprinter(nil)
+
+	// when
+	expiring := fingerprints.Findings("Expiration Checker", api.Issue{Message: `Ownership of "/src/" expires on 2026-01-10 (in 1 day(s))`})
+	expired := fingerprints.Findings("Expiration Checker", api.Issue{Message: `Ownership of "/src/" expired on 2026-01-10 (1 day(s) ago)`})
+
+	// then
+	assert.NotEqual(t, expiring[0].Fingerprint, expired[0].Fingerprint)
+}
+
+func TestFindingsOfListedFiles(t *testing.T) {
+	// given
+	fingerprints := baseline.NewFingerprinter(nil)
+
+	// when
+	findings := fingerprints.Findings("Not Owned File Checker", api.Issue{
+		Message: "Found 2 not owned files",
+		Paths:   []string{"README.md", "tools/build.sh"},
+	})
+
+	// then
+	require.Len(t, findings, 2)
+	assert.Equal(t, "README.md", findings[0].Summary)
+	assert.Equal(t, "tools/build.sh", findings[1].Summary)
+}
+
+func TestBaseline(t *testing.T) {
+	// given
+	fingerprints := baseline.NewFingerprinter(nil)
+	readme := fingerprints.Findings("Not Owned File Checker", api.Issue{Paths: []string{"README.md"}})
+	empty := fingerprints.Findings("Syntax Checker", api.Issue{Message: "The CODEOWNERS file is empty"})
+	file := baseline.NewFile(append(append(empty, readme...), readme...))
+
+	path := filepath.Join(t.TempDir(), baseline.DefaultFilename)
+	require.NoError(t, file.Save(path))
+
+	// when
+	b, err := baseline.Load(path)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, baseline.Version, file.Version)
+	assert.Equal(t, append(readme, empty...), file.Findings, "findings are sorted by check without duplicates")
+
+	assert.True(t, b.Contains(readme))
+	assert.True(t, b.Contains(empty))
+	assert.True(t, b.HasPath("Not Owned File Checker", "README.md"))
+	assert.False(t, b.HasPath("Not Owned File Checker", "main.go"))
+	assert.False(t, b.HasPath("Files Checker", "README.md"))
+	assert.False(t, b.Contains(fingerprints.Findings("Not Owned File Checker", api.Issue{Paths: []string{"README.md", "main.go"}})))
+	assert.False(t, b.Contains(nil))
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), baseline.DefaultFilename)
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 2, "findings": []}`), 0o600))
+
+	// when
+	_, err := baseline.Load(path)
+
+	// then
+	assert.EqualError(t, err, "unsupported version 2 of baseline "+path+", expected 1")
+}
+
+func TestFind(t *testing.T) {
+	// given
+	withDefault, withoutDefault := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(withDefault, baseline.DefaultFilename), []byte("{}"), 0o600))
+
+	tests := map[string]struct {
+		repoDir    string
+		configured string
+		expPath    string
+		expErr     bool
+	}{
+		"Default file in the repository root": {
+			repoDir: withDefault,
+			expPath: filepath.Join(withDefault, baseline.DefaultFilename),
+		},
+		"No baseline": {
+			repoDir: withoutDefault,
+		},
+		"Configured file": {
+			repoDir:    withoutDefault,
+			configured: filepath.Join(withDefault, baseline.DefaultFilename),
+			expPath:    filepath.Join(withDefault, baseline.DefaultFilename),
+		},
+		"Missing configured file": {
+			repoDir:    withDefault,
+			configured: filepath.Join(withoutDefault, "baseline.json"),
+			expErr:     true,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			path, err := baseline.Find(tc.repoDir, tc.configured)
+
+			// then
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expPath, path)
+		})
+	}
+}
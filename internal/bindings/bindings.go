@@ -0,0 +1,159 @@
+// Package bindings exposes the CODEOWNERS parser, matcher, and syntax check to non-Go consumers, such as
+// the WebAssembly module and the C shared library. Requests and responses are JSON-encoded, so the semantics are identical to the CLI
+// regardless of the language of the caller.
+package bindings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the exposed functions.
+const (
+	ParseFunc    = "parse"
+	MatchFunc    = "match"
+	ValidateFunc = "validate"
+)
+
+// Request is the input of all functions.
+type Request struct {
+	// Content of the CODEOWNERS file.
+	Content string `json:"content"`
+	// Paths are slash-separated repository paths, which owners are returned by the match function.
+	Paths []string `json:"paths,omitempty"`
+	// Providers are CODEOWNERS dialects verified by the validate function. Defaults to github.
+	Providers []string `json:"providers,omitempty"`
+}
+
+// Response is the output of all functions. Either the Result or the Error is set.
+type Response struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Entry is a parsed CODEOWNERS entry.
+type Entry struct {
+	LineNo  uint64   `json:"lineNo"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// Match holds the entry which owns a given path. The entry is nil if the path is not owned.
+type Match struct {
+	Path  string `json:"path"`
+	Entry *Entry `json:"entry"`
+}
+
+// Issue is a problem reported by the syntax check.
+type Issue struct {
+	Severity string `json:"severity"`
+	LineNo   uint64 `json:"lineNo,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Call executes a function with a given name and a JSON-encoded Request and returns the JSON-encoded Response.
+// Errors are returned in the Response, so callers handle them in the same way as results.
+func Call(ctx context.Context, fn string, req []byte) []byte {
+	result, err := call(ctx, fn, req)
+
+	resp := Response{Result: result}
+	if err != nil {
+		resp = Response{Error: err.Error()}
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		out, _ = json.Marshal(Response{Error: fmt.Sprintf("while encoding response: %v", err)})
+	}
+	return out
+}
+
+func call(ctx context.Context, fn string, raw []byte) (interface{}, error) {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, errors.Wrap(err, "while decoding request")
+	}
+
+	switch fn {
+	case ParseFunc:
+		return Parse(req.Content), nil
+	case MatchFunc:
+		return MatchPaths(req.Content, req.Paths)
+	case ValidateFunc:
+		return Validate(ctx, req.Content, req.Providers)
+	default:
+		return nil, errors.Errorf("unknown function %q, possible values: %s, %s, %s", fn, ParseFunc, MatchFunc, ValidateFunc)
+	}
+}
+
+// Parse returns entries of a given CODEOWNERS content.
+func Parse(content string) []Entry {
+	entries := codeowners.ParseCodeowners(strings.NewReader(content))
+
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, toEntry(e))
+	}
+	return out
+}
+
+// MatchPaths returns the entry which owns each of given paths. The last matching entry wins, as in GitHub.
+func MatchPaths(content string, paths []string) ([]Match, error) {
+	m, err := codeowners.NewMatcher(codeowners.ParseCodeowners(strings.NewReader(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Match, 0, len(paths))
+	for _, p := range paths {
+		match := Match{Path: p}
+		if e, found := m.Match(p); found {
+			entry := toEntry(e)
+			match.Entry = &entry
+		}
+		out = append(out, match)
+	}
+	return out, nil
+}
+
+// Validate executes the syntax check, the same as the `syntax` check of the CLI, for given providers.
+func Validate(ctx context.Context, content string, providers []string) ([]Issue, error) {
+	parsed, err := codeowners.ParseProviders(providers)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := check.NewValidSyntax().WithProviders(parsed...).Check(ctx, api.Input{
+		CodeownersEntries: codeowners.ParseCodeowners(strings.NewReader(content)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Issue, 0, len(res.Issues))
+	for _, i := range res.Issues {
+		issue := Issue{Severity: strings.ToLower(i.Severity.String()), Message: i.Message}
+		if i.LineNo != nil {
+			issue.LineNo = *i.LineNo
+		}
+		out = append(out, issue)
+	}
+	return out, nil
+}
+
+func toEntry(e codeowners.Entry) Entry {
+	owners := e.Owners
+	if owners == nil {
+		owners = []string{}
+	}
+	return Entry{LineNo: e.LineNo, Pattern: e.Pattern, Owners: owners, Comment: e.Comment}
+}
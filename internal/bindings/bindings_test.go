@@ -0,0 +1,70 @@
+package bindings_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/bindings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	tests := map[string]struct {
+		fn      string
+		request string
+		exp     string
+	}{
+		"Should parse entries": {
+			fn:      bindings.ParseFunc,
+			request: `{"content": "/docs/ @org/docs # docs\n/build/"}`,
+			exp: `{"result": [
+				{"lineNo": 1, "pattern": "/docs/", "owners": ["@org/docs"], "comment": "docs"},
+				{"lineNo": 2, "pattern": "/build/", "owners": []}
+			]}`,
+		},
+		"Should match paths with the last matching entry": {
+			fn:      bindings.MatchFunc,
+			request: `{"content": "/docs/ @org/docs\n/docs/api/ @org/api", "paths": ["docs/api/index.md", "./docs/README.md", "main.go"]}`,
+			exp: `{"result": [
+				{"path": "docs/api/index.md", "entry": {"lineNo": 2, "pattern": "/docs/api/", "owners": ["@org/api"]}},
+				{"path": "./docs/README.md", "entry": {"lineNo": 1, "pattern": "/docs/", "owners": ["@org/docs"]}},
+				{"path": "main.go", "entry": null}
+			]}`,
+		},
+		"Should validate syntax": {
+			fn:      bindings.ValidateFunc,
+			request: `{"content": "* @org/all\n/x/ not-an-owner\n"}`,
+			exp:     `{"result": [{"severity": "error", "lineNo": 2, "message": "Owner 'not-an-owner' does not look like an email"}]}`,
+		},
+		"Should validate syntax of given dialects": {
+			fn:      bindings.ValidateFunc,
+			request: `{"content": "/docs/ @@maintainers\n", "providers": ["gitlab"]}`,
+			exp:     `{"result": []}`,
+		},
+		"Should return unknown provider error": {
+			fn:      bindings.ValidateFunc,
+			request: `{"content": "", "providers": ["svn"]}`,
+			exp:     `{"error": "not a valid provider: \"svn\", possible values: github, gitlab"}`,
+		},
+		"Should return unknown function error": {
+			fn:      "format",
+			request: `{}`,
+			exp:     `{"error": "unknown function \"format\", possible values: parse, match, validate"}`,
+		},
+		"Should return malformed request error": {
+			fn:      bindings.ParseFunc,
+			request: `{`,
+			exp:     `{"error": "while decoding request: unexpected end of JSON input"}`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			out := bindings.Call(context.Background(), tc.fn, []byte(tc.request))
+
+			// then
+			assert.JSONEq(t, tc.exp, string(out))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/identity"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+type ActiveIdentityConfig struct {
+	// IgnoredOwners are not looked up in the identity source, e.g. bots or service accounts.
+	IgnoredOwners []string
+}
+
+// ActiveIdentity verifies that each user and email owner maps to an active identity in the external
+// identity source, e.g. HR system or IdP. It ensures that owners are current employees, not only
+// existing GitHub accounts. Team owners are not verified.
+type ActiveIdentity struct {
+	source        identity.Source
+	ignoredOwners map[string]struct{}
+}
+
+// NewActiveIdentity returns new instance of the ActiveIdentity
+func NewActiveIdentity(cfg ActiveIdentityConfig, source identity.Source) *ActiveIdentity {
+	ignored := map[string]struct{}{}
+	for _, o := range cfg.IgnoredOwners {
+		ignored[strings.ToLower(o)] = struct{}{}
+	}
+
+	return &ActiveIdentity{
+		source:        source,
+		ignoredOwners: ignored,
+	}
+}
+
+// Check searches for owners without an active identity.
+func (c *ActiveIdentity) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	identities, err := c.source.Identities(ctx)
+	if err != nil {
+		return api.Output{}, err
+	}
+	directory := identity.NewDirectory(identities)
+
+	checkedOwners := map[string]struct{}{}
+	for _, entry := range in.CodeownersEntries {
+		for _, owner := range entry.Owners {
+			if ctxutil.ShouldExit(ctx) {
+				return api.Output{}, ctx.Err()
+			}
+
+			key := strings.ToLower(owner)
+			if _, checked := checkedOwners[key]; checked {
+				continue
+			}
+			checkedOwners[key] = struct{}{}
+
+			if _, ignored := c.ignoredOwners[key]; ignored || !(isGitHubUser(owner) || isEmailAddress(owner)) {
+				continue
+			}
+
+			id, found := directory.Lookup(owner)
+			switch {
+			case !found:
+				msg := fmt.Sprintf("Owner %s does not map to any identity in the identity source", owner)
+				bldr.ReportIssue(msg, api.WithEntry(entry))
+			case !id.Active:
+				msg := fmt.Sprintf("Owner %s maps to an inactive identity. Transfer the ownership to a current employee.", owner)
+				bldr.ReportIssue(msg, api.WithEntry(entry))
+			}
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// Name returns human-readable name of the validator
+func (ActiveIdentity) Name() string {
+	return "[Experimental] Active Identity Checker"
+}
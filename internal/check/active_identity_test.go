@@ -0,0 +1,78 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/identity"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIdentitySource struct {
+	identities []identity.Identity
+	err        error
+}
+
+func (f *fakeIdentitySource) Identities(context.Context) ([]identity.Identity, error) {
+	return f.identities, f.err
+}
+
+func TestActiveIdentity(t *testing.T) {
+	// given
+	source := &fakeIdentitySource{
+		identities: []identity.Identity{
+			{Login: "alice", Email: "alice@example.com", Active: true},
+			{Login: "bob", Email: "bob@example.com", Active: false},
+		},
+	}
+	sut := check.NewActiveIdentity(check.ActiveIdentityConfig{IgnoredOwners: []string{"@ci-bot"}}, source)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  "Owner @bob maps to an inactive identity. Transfer the ownership to a current employee.",
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  "Owner @dave does not map to any identity in the identity source",
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(4),
+			Message:  "Owner bob@example.com maps to an inactive identity. Transfer the ownership to a current employee.",
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), LoadInput(`
+		*          @Alice @bob @org/team
+		/docs/     @dave @ci-bot alice@example.com
+		/infra/    bob@example.com @BOB
+	`))
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
+
+func TestActiveIdentitySourceError(t *testing.T) {
+	// given
+	sut := check.NewActiveIdentity(check.ActiveIdentityConfig{}, &fakeIdentitySource{err: errors.New("connection refused")})
+
+	// when
+	out, err := sut.Check(context.Background(), LoadInput(FixtureValidCODEOWNERS))
+
+	// then
+	assert.EqualError(t, err, "connection refused")
+	assert.Empty(t, out)
+}
@@ -6,9 +6,8 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/pkg/errors"
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/codeowners"
 )
 
@@ -28,7 +27,7 @@ func (c *AvoidShadowing) Check(ctx context.Context, in api.Input) (output api.Ou
 		}
 		re, err := wildCardToRegexp(endWithSlash(entry.Pattern))
 		if err != nil {
-			return api.Output{}, errors.Wrapf(err, "while compiling pattern %s into a regexp", entry.Pattern)
+			return api.Output{}, &api.MatchError{Pattern: entry.Pattern, Err: err}
 		}
 		shadowed := []codeowners.Entry{}
 		for _, previous := range previousEntries {
@@ -4,9 +4,9 @@ import (
 	"context"
 	"testing"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
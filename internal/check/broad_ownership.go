@@ -0,0 +1,111 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBroadOwnershipThreshold reports root-level patterns which give at least 75% of files to a single individual.
+const DefaultBroadOwnershipThreshold = 0.75
+
+type BroadOwnershipConfig struct {
+	// Threshold is the share of repository files owned per individual above which the pattern is reported.
+	// The share is the pattern breadth, i.e. the fraction of files the pattern effectively owns, divided
+	// by the number of its owners.
+	Threshold float64
+}
+
+// BroadOwnership reports root-level broad patterns, such as `*` or `/**`, owned only by individuals.
+// Every change to the files they own waits for the review of the same few people, which creates review
+// bottlenecks and a single point of failure when they are not available.
+type BroadOwnership struct {
+	threshold float64
+}
+
+// NewBroadOwnership returns new instance of the BroadOwnership
+func NewBroadOwnership(cfg BroadOwnershipConfig) (*BroadOwnership, error) {
+	if cfg.Threshold <= 0 || cfg.Threshold > 1 {
+		return nil, &api.ConfigError{
+			Field: "BROAD_OWNERSHIP_CHECKER_THRESHOLD",
+			Err:   errors.Errorf("threshold must be greater than 0 and at most 1, got %v", cfg.Threshold),
+		}
+	}
+	return &BroadOwnership{threshold: cfg.Threshold}, nil
+}
+
+// Check computes the breadth of root-level patterns owned only by individuals.
+func (c *BroadOwnership) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+	if len(files) == 0 {
+		return bldr.Output(), nil
+	}
+
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	// only the files for which the entry is the last matching one are really owned by it
+	owned := map[uint64]int{}
+	for _, f := range files {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+		if entry, found := matcher.Match(f); found {
+			owned[entry.LineNo]++
+		}
+	}
+
+	for _, entry := range in.CodeownersEntries {
+		if len(entry.Owners) == 0 || !isRootLevel(entry.Pattern) || !onlyIndividuals(entry.Owners) {
+			continue
+		}
+
+		breadth := float64(owned[entry.LineNo]) / float64(len(files))
+		if breadth/float64(len(entry.Owners)) < c.threshold {
+			continue
+		}
+
+		msg := fmt.Sprintf("Pattern %q owns %.0f%% of files, but only %s can review them, which creates a review bottleneck. Add a team as the owner.",
+			entry.Pattern, breadth*100, strings.Join(entry.Owners, ", "))
+		bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning))
+	}
+
+	return bldr.Output(), nil
+}
+
+// isRootLevel returns true if the pattern is not anchored in a specific directory, e.g. `*`, `/**`, or `*.go`.
+func isRootLevel(pattern string) bool {
+	first := strings.SplitN(strings.Trim(pattern, "/"), "/", 2)[0]
+	return strings.ContainsAny(first, "*?[")
+}
+
+// onlyIndividuals returns true if none of the owners is a team, so the reviews depend on specific people.
+func onlyIndividuals(owners []string) bool {
+	for _, o := range owners {
+		if isGitHubTeam(o) || strings.HasPrefix(o, "@@") {
+			return false
+		}
+	}
+	return true
+}
+
+// Name returns human-readable name of the validator
+func (BroadOwnership) Name() string {
+	return "[Experimental] Broad Ownership Checker"
+}
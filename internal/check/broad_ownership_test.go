@@ -0,0 +1,105 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadOwnership(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		"main.go":         "package main",
+		"go.mod":          "module x",
+		"Makefile":        "all:",
+		"cmd/root.go":     "package cmd",
+		"pkg/lib/lib.go":  "package lib",
+		"pkg/lib/util.go": "package lib",
+		"docs/README.md":  "docs",
+		"docs/guide.md":   "guide",
+	})
+
+	tests := map[string]struct {
+		threshold      float64
+		codeowners     string
+		expectedIssues []api.Issue
+	}{
+		"Should report root-level patterns owned by a single individual": {
+			threshold: check.DefaultBroadOwnershipThreshold,
+			codeowners: `
+				*          @alice
+				/docs/     @bob
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  `Pattern "*" owns 75% of files, but only @alice can review them, which creates a review bottleneck. Add a team as the owner.`,
+				},
+			},
+		},
+		"Should not report patterns overridden by more specific ones": {
+			threshold: check.DefaultBroadOwnershipThreshold,
+			codeowners: `
+				/**        alice@example.com
+				/pkg/      @org/lib
+				/docs/     @org/docs
+			`,
+		},
+		"Should not report patterns owned by teams": {
+			threshold: check.DefaultBroadOwnershipThreshold,
+			codeowners: `
+				*          @alice @org/platform
+			`,
+		},
+		"Should take the number of owners into account": {
+			threshold: 0.3,
+			codeowners: `
+				*          @alice @bob
+				*.md       @carol @dave @erin
+				/cmd/      @frank
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  `Pattern "*" owns 62% of files, but only @alice, @bob can review them, which creates a review bottleneck. Add a team as the owner.`,
+				},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			sut, err := check.NewBroadOwnership(check.BroadOwnershipConfig{Threshold: tc.threshold})
+			require.NoError(t, err)
+
+			// when
+			out, err := sut.Check(context.Background(), api.Input{
+				RepoDir:           repoDir,
+				CodeownersEntries: LoadInput(tc.codeowners).CodeownersEntries,
+			})
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
+
+func TestNewBroadOwnershipValidatesThreshold(t *testing.T) {
+	// when
+	_, err := check.NewBroadOwnership(check.BroadOwnershipConfig{Threshold: 1.5})
+
+	// then
+	assert.EqualError(t, err, "threshold must be greater than 0 and at most 1, got 1.5")
+	assert.Equal(t, "Check the BROAD_OWNERSHIP_CHECKER_THRESHOLD configuration.", api.Hint(err))
+}
@@ -0,0 +1,165 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stressRounds is the number of times all checks are executed concurrently. Run with -race to detect data races.
+const stressRounds = 10
+
+// TestChecksConcurrently executes all checks which don't need external services, and the owners check with
+// a fake GitHub API, concurrently over the same repository, as the runner does. Each check is also executed
+// concurrently with itself, as it happens when the same checks validate several CODEOWNERS files.
+// Results must be the same as results of the sequential execution.
+func TestChecksConcurrently(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		".github/CODEOWNERS": `
+*                @org/platform
+/docs/           @alice
+/docs/           @alice
+/api/**/*.go     @org/api @Alice
+/assets/         @org/platform
+`,
+		".gitattributes":           "*.png binary\n",
+		".github/workflows/ci.yml": "on: push",
+		"main.go":                  "package main",
+		"api/v1/api.go":            "package v1",
+		"docs/README.md":           "docs",
+		"assets/logo.png":          "png",
+		"big.bin":                  strings.Repeat("x", 200),
+		"unowned/file.txt":         "unowned",
+	})
+
+	entries, err := codeowners.NewFromPath(repoDir)
+	require.NoError(t, err)
+
+	expected := map[string]api.Output{}
+	for _, c := range stressChecks(t) {
+		out, err := c.Check(context.Background(), api.Input{RepoDir: repoDir, CodeownersEntries: entries})
+		require.NoError(t, err, c.Name())
+		expected[c.Name()] = out
+	}
+
+	for round := 0; round < stressRounds; round++ {
+		// new instances, so state initialized on the first use is initialized concurrently
+		checks := stressChecks(t)
+		// the analysis is shared by all checks of a run
+		runs := []api.Input{
+			{RepoDir: repoDir, CodeownersEntries: entries, Analysis: api.NewAnalysis(entries)},
+			{RepoDir: repoDir, CodeownersEntries: entries, Analysis: api.NewAnalysis(entries)},
+		}
+
+		var wg sync.WaitGroup
+		for _, in := range runs {
+			for _, c := range checks {
+				wg.Add(1)
+				go func(c api.Checker, in api.Input) {
+					defer wg.Done()
+
+					// when
+					out, err := c.Check(context.Background(), in)
+
+					// then
+					assert.NoError(t, err, c.Name())
+					assert.Equal(t, expected[c.Name()], out, c.Name())
+				}(c, in)
+			}
+		}
+		wg.Wait()
+	}
+}
+
+func stressChecks(t *testing.T) []api.Checker {
+	t.Helper()
+
+	governance, err := check.NewGovernance(check.GovernanceConfig{Team: "@org/governance", Paths: []string{".github/"}})
+	require.NoError(t, err)
+	stewardship, err := check.NewStewardship(check.StewardshipConfig{Team: "@org/artifacts", SizeThreshold: 100})
+	require.NoError(t, err)
+	broadOwnership, err := check.NewBroadOwnership(check.BroadOwnershipConfig{Threshold: check.DefaultBroadOwnershipThreshold})
+	require.NoError(t, err)
+	ownershipSLA, err := check.NewOwnershipSLA(check.OwnershipSLAConfig{})
+	require.NoError(t, err)
+
+	return []api.Checker{
+		check.NewValidSyntax(),
+		check.NewDuplicatedPattern(),
+		check.NewFileExist(),
+		check.NewAvoidShadowing(),
+		check.NewPathHazards(),
+		check.NewOwnerCasing(),
+		check.NewUnsafePattern(),
+		check.NewInvisibleChars(),
+		check.NewFileEncoding(),
+		check.NewExpensivePattern(),
+		check.NewNotOwnedFile(check.NotOwnedFileConfig{SuggestOwners: true}),
+		governance,
+		stewardship,
+		broadOwnership,
+		ownershipSLA,
+		validOwnerCheck(t),
+	}
+}
+
+// validOwnerCheck returns the owners check which lazily fetches organization members and teams from a fake GitHub API.
+func validOwnerCheck(t *testing.T) api.Checker {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/org/members", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"login": "alice"}]`)
+	})
+	mux.HandleFunc("/repos/org/repo/collaborators", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/users/alice", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"login": "alice"}`)
+	})
+	mux.HandleFunc("/users/Alice", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"login": "alice"}`)
+	})
+	mux.HandleFunc("/orgs/org/teams", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"slug": "platform"}, {"slug": "api"}]`)
+	})
+	mux.HandleFunc("/orgs/org/teams/", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"permissions": {"push": true}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	owners, err := check.NewValidOwner(&config.Config{
+		OwnerCheckerRepository:           "org/repo",
+		OwnerCheckerAllowUnownedPatterns: true,
+	}, ghClient, false)
+	require.NoError(t, err)
+	return owners
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"strings"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/codeowners"
 )
 
 // DuplicatedPattern validates if CODEOWNERS file does not contain
 // the duplicated lines with the same file pattern.
+//
+// In the GitLab flavor, each section resolves owners on its own, so the same pattern may be repeated
+// in different sections. Only duplicates within a section are reported.
 type DuplicatedPattern struct{}
 
 // NewDuplicatedPattern returns instance of the DuplicatedPattern
@@ -26,25 +29,44 @@ func (d *DuplicatedPattern) Check(ctx context.Context, in api.Input) (api.Output
 	// TODO(mszostok): decide if the `CodeownersEntries` entry by default should be
 	//  indexed by pattern (`map[string][]codeowners.Entry{}`)
 	//  Required changes in pkg/codeowners/owners.go.
-	patterns := map[string][]codeowners.Entry{}
+	type sectionPattern struct{ section, pattern string }
+	patterns := map[sectionPattern][]codeowners.Entry{}
 	for _, entry := range in.CodeownersEntries {
 		if ctxutil.ShouldExit(ctx) {
 			return api.Output{}, ctx.Err()
 		}
 
-		patterns[entry.Pattern] = append(patterns[entry.Pattern], entry)
+		key := sectionPattern{section: codeowners.SectionKey(entry), pattern: entry.Pattern}
+		patterns[key] = append(patterns[key], entry)
 	}
 
-	for name, entries := range patterns {
+	for key, entries := range patterns {
 		if len(entries) > 1 {
-			msg := fmt.Sprintf("Pattern %q is defined %d times in lines:\n%s", name, len(entries), d.listFormatFunc(entries))
-			bldr.ReportIssue(msg)
+			where := "lines"
+			if s := entries[0].Section; s != nil {
+				where = fmt.Sprintf("section %q in lines", s.Name)
+			}
+			msg := fmt.Sprintf("Pattern %q is defined %d times in %s:\n%s", key.pattern, len(entries), where, d.listFormatFunc(entries))
+			bldr.ReportIssue(msg, api.WithFix(d.removeDuplicatesFix(entries)))
 		}
 	}
 
 	return bldr.Output(), nil
 }
 
+// removeDuplicatesFix removes all duplicated entries except the last one, which takes the most precedence.
+func (d *DuplicatedPattern) removeDuplicatesFix(es []codeowners.Entry) api.Fix {
+	last := es[len(es)-1]
+	fix := api.Fix{
+		Description: fmt.Sprintf("Remove duplicated lines, keep line %d which takes precedence", last.LineNo),
+	}
+	for _, e := range es[:len(es)-1] {
+		fix.Edits = append(fix.Edits, api.LineEdit{LineNo: e.LineNo, Delete: true})
+	}
+
+	return fix
+}
+
 // listFormatFunc is a basic formatter that outputs a bullet point list of the pattern.
 func (d *DuplicatedPattern) listFormatFunc(es []codeowners.Entry) string {
 	points := make([]string, len(es))
@@ -4,8 +4,8 @@ import (
 	"context"
 	"testing"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -33,6 +33,12 @@ func TestDuplicatedPattern(t *testing.T) {
 					Message: `Pattern "/build/logs/" is defined 2 times in lines:
             * 4: with owners: [@doctocat]
             * 5: with owners: [@doctocat]`,
+					Fixes: []api.Fix{
+						{
+							Description: "Remove duplicated lines, keep line 5 which takes precedence",
+							Edits:       []api.LineEdit{{LineNo: 4, Delete: true}},
+						},
+					},
 				},
 				{
 					Severity: api.Error,
@@ -40,6 +46,12 @@ func TestDuplicatedPattern(t *testing.T) {
 					Message: `Pattern "/script" is defined 2 times in lines:
             * 7: with owners: [@mszostok]
             * 8: with owners: [m.t@g.com]`,
+					Fixes: []api.Fix{
+						{
+							Description: "Remove duplicated lines, keep line 8 which takes precedence",
+							Edits:       []api.LineEdit{{LineNo: 7, Delete: true}},
+						},
+					},
 				},
 			},
 		},
@@ -63,3 +75,38 @@ func TestDuplicatedPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestDuplicatedPatternGitLabSections(t *testing.T) {
+	// given
+	in := LoadGitLabInput(t, `
+		/docs/ @org/core
+
+		[Docs] @org/writers
+		/docs/
+		*.md
+
+		[docs]
+		*.md @org/editors
+	`)
+	sut := check.NewDuplicatedPattern()
+
+	// when
+	out, err := sut.Check(context.TODO(), in)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []api.Issue{
+		{
+			Severity: api.Error,
+			Message: `Pattern "*.md" is defined 2 times in section "Docs" in lines:
+            * 6: with owners: [@org/writers]
+            * 9: with owners: [@org/editors]`,
+			Fixes: []api.Fix{
+				{
+					Description: "Remove duplicated lines, keep line 9 which takes precedence",
+					Edits:       []api.LineEdit{{LineNo: 6, Delete: true}},
+				},
+			},
+		},
+	}, out.Issues)
+}
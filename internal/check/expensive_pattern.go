@@ -0,0 +1,117 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExpensivePattern reports pathological patterns that are slow to evaluate, such as patterns
+// with multiple `**` segments or a leading `**/*` combination. Issues contain only the reasons, so the output
+// is the same on each run, and the measured cost of matching the pattern against all files tracked
+// in the repository is logged at the debug level.
+type ExpensivePattern struct {
+	log logrus.FieldLogger
+}
+
+// NewExpensivePattern returns new instance of the ExpensivePattern
+func NewExpensivePattern() *ExpensivePattern {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return &ExpensivePattern{log: log}
+}
+
+// WithLogger sets the logger used to log the measured cost of expensive patterns.
+func (c *ExpensivePattern) WithLogger(log logrus.FieldLogger) *ExpensivePattern {
+	c.log = log
+	return c
+}
+
+// Check searches for patterns which shape makes them expensive to evaluate.
+func (c *ExpensivePattern) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		reasons := c.expensiveReasons(entry.Pattern)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		pattern, err := in.Pattern(entry.Pattern)
+		if err != nil {
+			return api.Output{}, &api.MatchError{Pattern: entry.Pattern, Err: err}
+		}
+
+		c.log.WithField("pattern", entry.Pattern).Debugf("Matching pattern against %d repository files took %v", len(files), c.measureCost(pattern, files))
+
+		msg := fmt.Sprintf("Pattern %q is expensive to evaluate: %s.", entry.Pattern, strings.Join(reasons, ", "))
+		bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning))
+	}
+
+	return bldr.Output(), nil
+}
+
+func (c *ExpensivePattern) expensiveReasons(pattern string) []string {
+	var (
+		reasons  []string
+		segments = strings.Split(strings.Trim(pattern, "/"), "/")
+		doubles  int
+	)
+	for idx, s := range segments {
+		if s != "**" {
+			continue
+		}
+		doubles++
+		if idx > 0 && segments[idx-1] == "**" {
+			reasons = append(reasons, `contains consecutive "**/**" segments which are equal to a single "**"`)
+		}
+	}
+
+	if doubles > 1 {
+		reasons = append(reasons, fmt.Sprintf(`contains %d "**" segments`, doubles))
+	}
+
+	if strings.HasPrefix(strings.TrimPrefix(pattern, "/"), "**/*") {
+		msg := `starts with "**/*"`
+		if rest := strings.TrimPrefix(strings.TrimPrefix(pattern, "/"), "**/"); !strings.Contains(strings.TrimSuffix(rest, "/"), "/") {
+			msg += fmt.Sprintf(" which can be simplified to %q", rest)
+		}
+		reasons = append(reasons, msg)
+	}
+
+	return reasons
+}
+
+func (*ExpensivePattern) measureCost(p codeowners.Pattern, files []string) time.Duration {
+	start := time.Now()
+	for _, f := range files {
+		p.Match(f)
+	}
+	return time.Since(start)
+}
+
+// Name returns human-readable name of the validator
+func (ExpensivePattern) Name() string {
+	return "[Experimental] Expensive Pattern Checker"
+}
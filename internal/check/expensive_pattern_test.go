@@ -0,0 +1,62 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpensivePattern(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		"src/app/main.go": "package main",
+		"docs/README.md":  "# Docs",
+	})
+
+	in := LoadInput(`
+			*                  @global-owner
+			**/*.go            @go-owner
+			/src/**/app/**/*   @app-owner
+			/docs/**/**        @docs-owner
+			/build/**/logs/    @build-owner
+	`)
+	in.RepoDir = repoDir
+
+	expMessages := map[uint64]string{
+		3: `Pattern "**/*.go" is expensive to evaluate: starts with "**/*" which can be simplified to "*.go".`,
+		4: `Pattern "/src/**/app/**/*" is expensive to evaluate: contains 2 "**" segments.`,
+		5: `Pattern "/docs/**/**" is expensive to evaluate: contains consecutive "**/**" segments which are equal to a single "**", contains 2 "**" segments.`,
+	}
+
+	log, hook := test.NewNullLogger()
+	log.SetLevel(logrus.DebugLevel)
+
+	// when
+	out, err := check.NewExpensivePattern().WithLogger(log).Check(context.Background(), in)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, out.Issues, len(expMessages))
+	for _, issue := range out.Issues {
+		require.NotNil(t, issue.LineNo)
+		assert.Equal(t, api.Warning, issue.Severity)
+		assert.Equal(t, expMessages[*issue.LineNo], issue.Message)
+	}
+
+	// the measured cost is only logged, so the issues are the same on each run
+	require.Len(t, hook.AllEntries(), len(expMessages))
+	for _, entry := range hook.AllEntries() {
+		assert.Equal(t, logrus.DebugLevel, entry.Level)
+		assert.Regexp(t, `^Matching pattern against 2 repository files took .+$`, entry.Message)
+	}
+}
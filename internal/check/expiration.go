@@ -0,0 +1,83 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+const (
+	// expiresAnnotation is the inline comment annotation that marks a temporary ownership, e.g.:
+	//
+	//  /payments/  @org/payments-interim # expires:2025-12-31
+	expiresAnnotation = "expires"
+	expiresLayout     = "2006-01-02"
+)
+
+type OwnershipExpirationConfig struct {
+	// WarnBefore defines how long before the expiration date the entry is reported as soon-expiring.
+	WarnBefore time.Duration
+}
+
+// OwnershipExpiration reports entries whose temporary ownership has expired or expires soon.
+type OwnershipExpiration struct {
+	warnBefore time.Duration
+	now        func() time.Time
+}
+
+// NewOwnershipExpiration returns new instance of the OwnershipExpiration
+func NewOwnershipExpiration(cfg OwnershipExpirationConfig) *OwnershipExpiration {
+	return &OwnershipExpiration{
+		warnBefore: cfg.WarnBefore,
+		now:        time.Now,
+	}
+}
+
+// Check searches for entries annotated with the `expires:YYYY-MM-DD` comment
+// which are already expired or expire within the configured period.
+func (c *OwnershipExpiration) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	y, m, d := c.now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		raw, found := entry.Annotation(expiresAnnotation)
+		if !found {
+			continue
+		}
+
+		expires, err := time.Parse(expiresLayout, raw)
+		if err != nil {
+			msg := fmt.Sprintf("Cannot parse expiration date %q, expected format YYYY-MM-DD", raw)
+			bldr.ReportIssue(msg, api.WithEntry(entry))
+			continue
+		}
+
+		switch left := expires.Sub(today); {
+		case left < 0:
+			msg := fmt.Sprintf("Ownership of %q expired on %s (%d day(s) ago)", entry.Pattern, raw, -days(left))
+			bldr.ReportIssue(msg, api.WithEntry(entry))
+		case left <= c.warnBefore:
+			msg := fmt.Sprintf("Ownership of %q expires on %s (in %d day(s))", entry.Pattern, raw, days(left))
+			bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning))
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+func days(d time.Duration) int {
+	return int(d / (24 * time.Hour))
+}
+
+// Name returns human-readable name of the validator
+func (OwnershipExpiration) Name() string {
+	return "[Experimental] Ownership Expiration Checker"
+}
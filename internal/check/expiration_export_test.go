@@ -0,0 +1,7 @@
+package check
+
+import "time"
+
+func (c *OwnershipExpiration) SetNow(now func() time.Time) {
+	c.now = now
+}
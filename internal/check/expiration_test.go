@@ -0,0 +1,77 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnershipExpiration(t *testing.T) {
+	tests := map[string]struct {
+		codeownersInput string
+		expectedIssues  []api.Issue
+	}{
+		"Should report expired and soon-expiring entries": {
+			codeownersInput: `
+					*             @global-owner1
+					/payments/    @org/payments-interim # expires:2023-05-01
+					/billing/     @org/billing-interim  # expires: 2023-05-20
+					/search/      @org/search-interim   # expires:2023-05-10
+					/ledger/      @org/ledger-interim   # expires:2023-07-01
+					/legacy/      @org/legacy           # expires:soon
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(3),
+					Message:  `Ownership of "/payments/" expired on 2023-05-01 (9 day(s) ago)`,
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(4),
+					Message:  `Ownership of "/billing/" expires on 2023-05-20 (in 10 day(s))`,
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(5),
+					Message:  `Ownership of "/search/" expires on 2023-05-10 (in 0 day(s))`,
+				},
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(7),
+					Message:  `Cannot parse expiration date "soon", expected format YYYY-MM-DD`,
+				},
+			},
+		},
+		"Should not report any issues with correct CODEOWNERS file": {
+			codeownersInput: FixtureValidCODEOWNERS,
+			expectedIssues:  nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewOwnershipExpiration(check.OwnershipExpirationConfig{
+				WarnBefore: 14 * 24 * time.Hour,
+			})
+			sut.SetNow(func() time.Time {
+				return time.Date(2023, 5, 10, 15, 4, 5, 0, time.UTC)
+			})
+
+			// when
+			out, err := sut.Check(context.TODO(), LoadInput(tc.codeownersInput))
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
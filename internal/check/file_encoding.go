@@ -0,0 +1,62 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// FileEncoding reports CODEOWNERS files which are not UTF-8 with LF line endings: files with Windows line
+// endings, a byte order mark, or another encoding. GitHub handles some of them, but other tools which read
+// the file, e.g. editors, linters, or scripts, may treat a BOM or CR as a part of patterns and owners.
+type FileEncoding struct{}
+
+// NewFileEncoding returns new instance of the FileEncoding
+func NewFileEncoding() *FileEncoding {
+	return &FileEncoding{}
+}
+
+// Check reads the CODEOWNERS file of the repository and reports how it differs from the normalized form.
+func (c *FileEncoding) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	path, err := codeowners.FindCodeownersFile(in.RepoDir)
+	if err != nil {
+		return api.Output{}, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return api.Output{}, errors.Wrap(err, "while reading CODEOWNERS file")
+	}
+
+	report := codeowners.InspectEncoding(raw)
+	switch {
+	case report.Encoding != codeowners.UTF8:
+		bldr.ReportIssue(fmt.Sprintf("CODEOWNERS file is encoded in %s. Use UTF-8, as GitHub and most tools read the file only in this encoding.", report.Encoding))
+	case report.BOM:
+		bldr.ReportIssue("CODEOWNERS file starts with a UTF-8 byte order mark, which some tools read as a part of the first line.", api.WithSeverity(api.Warning))
+	}
+
+	if n := len(report.CRLFLines); n > 0 {
+		bldr.ReportIssue(fmt.Sprintf("CODEOWNERS file uses Windows line endings (CRLF) in %d line(s), starting from line %d. Use LF line endings.", n, report.CRLFLines[0]), api.WithSeverity(api.Warning))
+	}
+
+	for _, no := range report.InvalidLines {
+		no := no
+		bldr.ReportIssue("Line is not valid UTF-8. Use UTF-8 encoding.", func(i *api.Issue) {
+			i.LineNo = &no
+		})
+	}
+
+	return bldr.Output(), nil
+}
+
+// Name returns human-readable name of the validator
+func (FileEncoding) Name() string {
+	return "[Experimental] File Encoding Checker"
+}
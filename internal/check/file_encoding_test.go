@@ -0,0 +1,75 @@
+package check_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileEncoding(t *testing.T) {
+	tests := map[string]struct {
+		givenContent   string
+		expectedIssues []api.Issue
+	}{
+		"Should report byte order mark and CRLF line endings": {
+			givenContent: "\xEF\xBB\xBF* @org/platform\r\n/docs/ @org/docs\n/src/ @org/dev\r\n",
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Warning,
+					Message:  "CODEOWNERS file starts with a UTF-8 byte order mark, which some tools read as a part of the first line.",
+				},
+				{
+					Severity: api.Warning,
+					Message:  "CODEOWNERS file uses Windows line endings (CRLF) in 2 line(s), starting from line 1. Use LF line endings.",
+				},
+			},
+		},
+		"Should report UTF-16 encoding": {
+			givenContent: "\xFF\xFE*\x00 \x00@\x00a\x00\n\x00",
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  "CODEOWNERS file is encoded in UTF-16LE. Use UTF-8, as GitHub and most tools read the file only in this encoding.",
+				},
+			},
+		},
+		"Should report lines which are not valid UTF-8": {
+			givenContent: "* @org/platform\n/caf\xE9/ @org/cafe\n",
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "Line is not valid UTF-8. Use UTF-8 encoding.",
+				},
+			},
+		},
+		"Should not report any issues with normalized CODEOWNERS file": {
+			givenContent:   FixtureValidCODEOWNERS,
+			expectedIssues: nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			repoDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(repoDir, "CODEOWNERS"), []byte(tc.givenContent), 0o644))
+			sut := check.NewFileEncoding()
+
+			// when
+			out, err := sut.Check(context.TODO(), api.Input{RepoDir: repoDir})
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
@@ -3,16 +3,17 @@ package check
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/ctxutil"
-
-	"github.com/mattn/go-zglob"
-	"github.com/pkg/errors"
+	"go.szostok.io/codeowners/pkg/api"
 )
 
+// FileExist validates that each CODEOWNERS pattern matches at least one file tracked in the repository.
+//
+// Patterns are judged with the same matcher that resolves owners, so directory patterns (`docs/`),
+// extension-wide patterns (`*.md`), and patterns with wildcards (`/build/**/output`) follow the CODEOWNERS semantics.
+// If the input has the Scope, only patterns which may own files in the scope are reported, and patterns
+// which match files outside the scope are not reported at all.
 type FileExist struct{}
 
 func NewFileExist() *FileExist {
@@ -22,24 +23,29 @@ func NewFileExist() *FileExist {
 func (f *FileExist) Check(ctx context.Context, in api.Input) (api.Output, error) {
 	var bldr api.OutputBuilder
 
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	files, err := in.AllFiles()
+	if err != nil {
+		return api.Output{}, err
+	}
+
 	for _, entry := range in.CodeownersEntries {
 		if ctxutil.ShouldExit(ctx) {
 			return api.Output{}, ctx.Err()
 		}
-
-		fullPath := filepath.Join(in.RepoDir, f.fnmatchPattern(entry.Pattern))
-		matches, err := zglob.Glob(fullPath)
-		switch {
-		case err == nil:
-		case errors.Is(err, os.ErrNotExist):
-			msg := fmt.Sprintf("%q does not match any files in repository", entry.Pattern)
-			bldr.ReportIssue(msg, api.WithEntry(entry))
+		if !in.Scope.CoversPattern(entry.Pattern) {
 			continue
-		default:
-			return api.Output{}, errors.Wrapf(err, "while checking if there is any file in %s matching pattern %s", in.RepoDir, entry.Pattern)
 		}
 
-		if len(matches) == 0 {
+		pattern, err := in.Pattern(entry.Pattern)
+		if err != nil {
+			return api.Output{}, &api.MatchError{Pattern: entry.Pattern, Err: err}
+		}
+
+		if !matchesAny(pattern, files) {
 			msg := fmt.Sprintf("%q does not match any files in repository", entry.Pattern)
 			bldr.ReportIssue(msg, api.WithEntry(entry))
 		}
@@ -48,14 +54,6 @@ func (f *FileExist) Check(ctx context.Context, in api.Input) (api.Output, error)
 	return bldr.Output(), nil
 }
 
-func (*FileExist) fnmatchPattern(pattern string) string {
-	if len(pattern) >= 2 && pattern[:1] == "*" && pattern[1:2] != "*" {
-		return "**/" + pattern
-	}
-
-	return pattern
-}
-
 func (*FileExist) Name() string {
 	return "File Exist Checker"
 }
@@ -8,9 +8,10 @@ import (
 	"testing"
 	"time"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,6 +96,52 @@ func TestFileExists(t *testing.T) {
 				newErrIssue(`"!/codeowners" does not match any files in repository`),
 			},
 		},
+		"Should match directory pattern": {
+			codeownersInput: `
+					docs/ @pico
+			`,
+			paths: []string{
+				"/src/docs/guide.md",
+			},
+		},
+		"Should match extension-wide pattern": {
+			codeownersInput: `
+					*.md @pico
+			`,
+			paths: []string{
+				"/docs/nested/README.md",
+			},
+		},
+		"Should match directories between anchored pattern segments": {
+			codeownersInput: `
+					/build/**/output @bello
+			`,
+			paths: []string{
+				"/build/linux/amd64/output/app.bin",
+			},
+		},
+		"Should not match anchored directory pattern in nested directory": {
+			codeownersInput: `
+					/docs/ @pico
+			`,
+			paths: []string{
+				"/src/docs/guide.md",
+			},
+			expectedIssues: []api.Issue{
+				newErrIssue(`"/docs/" does not match any files in repository`),
+			},
+		},
+		"Should not match nested files with direct children pattern": {
+			codeownersInput: `
+					docs/* @pico
+			`,
+			paths: []string{
+				"/docs/nested/README.md",
+			},
+			expectedIssues: []api.Issue{
+				newErrIssue(`"docs/*" does not match any files in repository`),
+			},
+		},
 		"Should not found JS file": {
 			codeownersInput: `
 					*.js @pico
@@ -162,11 +209,13 @@ func TestFileExists(t *testing.T) {
 				assert.NoError(t, os.RemoveAll(tmp))
 			}()
 
+			gitrepo.Init(t, tmp)
 			initFSStructure(t, tmp, tc.paths)
+			gitrepo.CommitFiles(t, tmp, nil)
 
 			fchecker := check.NewFileExist()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 			defer cancel()
 
 			// when
@@ -181,6 +230,38 @@ func TestFileExists(t *testing.T) {
 	}
 }
 
+func TestFileExistsInScope(t *testing.T) {
+	// given
+	tmp := t.TempDir()
+	gitrepo.Init(t, tmp)
+	initFSStructure(t, tmp, []string{
+		"/services/payments/api.go",
+		"/services/search/index.go",
+	})
+	gitrepo.CommitFiles(t, tmp, nil)
+
+	scope, err := api.NewScope([]string{"/services/payments/"}, nil)
+	require.NoError(t, err)
+
+	in := LoadInput(`
+		/services/search/ @search
+		/services/search/legacy/ @search
+		/services/payments/legacy/ @payments
+		*.md @docs
+	`)
+	in.RepoDir = tmp
+	in.Scope = scope
+
+	// when
+	out, err := check.NewFileExist().Check(context.Background(), in)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, out.Issues, 2)
+	assert.Equal(t, `"/services/payments/legacy/" does not match any files in repository`, out.Issues[0].Message)
+	assert.Equal(t, `"*.md" does not match any files in repository`, out.Issues[1].Message)
+}
+
 func TestFileExistCheckFileSystemFailure(t *testing.T) {
 	// given
 	tmpdir, err := ioutil.TempDir("", "file-checker")
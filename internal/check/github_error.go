@@ -0,0 +1,45 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"time"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// newGitHubError converts the GitHub client error into the api.APIError. Context errors are returned as they are.
+func newGitHubError(err error, op string) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	out := &api.APIError{Provider: "GitHub", Op: op, Err: err}
+
+	var (
+		rateErr  *github.RateLimitError
+		abuseErr *github.AbuseRateLimitError
+		respErr  *github.ErrorResponse
+	)
+	switch {
+	case errors.As(err, &rateErr):
+		out.StatusCode, out.RateLimited = rateErr.Response.StatusCode, true
+		if reset := time.Until(rateErr.Rate.Reset.Time); reset > 0 {
+			out.RetryAfter = reset
+		}
+	case errors.As(err, &abuseErr):
+		out.StatusCode, out.RateLimited = abuseErr.Response.StatusCode, true
+		if abuseErr.RetryAfter != nil {
+			out.RetryAfter = *abuseErr.RetryAfter
+		}
+	case errors.As(err, &respErr):
+		out.StatusCode = respErr.Response.StatusCode
+		out.RateLimited, out.RetryAfter = api.RateLimit(respErr.Response)
+	}
+
+	return out
+}
@@ -0,0 +1,173 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// unknownOwnerKind is the kind of GitHub errors about owners which don't exist. They are not a parser
+// disagreement, as the local syntax check doesn't verify the existence of owners, the 'owners' checker does.
+const unknownOwnerKind = "Unknown owner"
+
+type GitHubErrorsConfig struct {
+	// Repository is in form 'owner/repository'.
+	Repository string
+	// Ref is the branch, tag, or commit which CODEOWNERS is validated by GitHub. Defaults to the default branch.
+	Ref string
+}
+
+// GitHubErrors reconciles errors which GitHub reports for the CODEOWNERS file with the local parse,
+// so disagreements between our parser and GitHub's are detected:
+//   - lines rejected by GitHub, but accepted by the local syntax check, are reported as errors, as GitHub
+//     ignores them when requesting reviews,
+//   - lines rejected by the local syntax check, but accepted by GitHub, are reported as warnings.
+type GitHubErrors struct {
+	ghClient    *github.Client
+	syntax      *ValidSyntax
+	orgName     string
+	orgRepoName string
+	ref         string
+}
+
+// codeownersError is a single error returned by the GitHub CODEOWNERS errors API.
+type codeownersError struct {
+	Line    uint64 `json:"line"`
+	Column  uint64 `json:"column"`
+	Kind    string `json:"kind"`
+	Source  string `json:"source"`
+	Message string `json:"message"`
+	Path    string `json:"path"`
+}
+
+// NewGitHubErrors returns new instance of the GitHubErrors
+func NewGitHubErrors(cfg GitHubErrorsConfig, ghClient *github.Client) (*GitHubErrors, error) {
+	split := strings.Split(cfg.Repository, "/")
+	if len(split) != 2 {
+		return nil, &api.ConfigError{
+			Field: "OWNER_CHECKER_REPOSITORY",
+			Err:   errors.Errorf("Wrong repository name. Expected pattern 'owner/repository', got '%s'", cfg.Repository),
+		}
+	}
+
+	return &GitHubErrors{
+		ghClient:    ghClient,
+		syntax:      NewValidSyntax(),
+		orgName:     split[0],
+		orgRepoName: split[1],
+		ref:         cfg.Ref,
+	}, nil
+}
+
+// Check fetches CODEOWNERS errors from GitHub and reports lines on which GitHub and the local parse disagree.
+func (c *GitHubErrors) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	ghErrs, err := c.fetchErrors(ctx)
+	if err != nil {
+		return api.Output{}, newGitHubError(err, "fetching CODEOWNERS errors")
+	}
+
+	local, err := c.syntax.Check(ctx, in)
+	if err != nil {
+		return api.Output{}, err
+	}
+	localByLine := map[uint64][]string{}
+	for _, i := range local.Issues {
+		if i.LineNo != nil {
+			localByLine[*i.LineNo] = append(localByLine[*i.LineNo], i.Message)
+		}
+	}
+
+	entries := map[uint64]codeowners.Entry{}
+	for _, e := range in.CodeownersEntries {
+		entries[e.LineNo] = e
+	}
+
+	var bldr api.OutputBuilder
+
+	rejected := map[uint64]struct{}{}
+	for _, ghErr := range ghErrs {
+		entry, found := entries[ghErr.Line]
+		if ghErr.Source != "" && (!found || strings.TrimSpace(entry.Raw) != strings.TrimSpace(ghErr.Source)) {
+			// line numbers refer to another version of the file, so they cannot be reconciled
+			bldr.ReportIssue(c.versionMismatchMsg(ghErr), api.WithSeverity(api.Warning))
+			return bldr.Output(), nil
+		}
+
+		rejected[ghErr.Line] = struct{}{}
+		if ghErr.Kind == unknownOwnerKind || len(localByLine[ghErr.Line]) > 0 {
+			continue
+		}
+
+		msg := fmt.Sprintf("GitHub reports %q in line %d, but the line is accepted by the local parser: %s", ghErr.Kind, ghErr.Line, firstLine(ghErr.Message))
+		if found {
+			bldr.ReportIssue(msg, api.WithEntry(entry))
+		} else {
+			bldr.ReportIssue(msg)
+		}
+	}
+
+	for _, e := range in.CodeownersEntries {
+		msgs := localByLine[e.LineNo]
+		if _, found := rejected[e.LineNo]; found || len(msgs) == 0 {
+			continue
+		}
+		msg := fmt.Sprintf("Line %d is accepted by GitHub, but rejected by the local parser: %s", e.LineNo, strings.Join(msgs, "; "))
+		bldr.ReportIssue(msg, api.WithEntry(e), api.WithSeverity(api.Warning))
+	}
+
+	return bldr.Output(), nil
+}
+
+func (c *GitHubErrors) versionMismatchMsg(ghErr codeownersError) string {
+	ref := c.ref
+	if ref == "" {
+		ref = "the default branch"
+	}
+	return fmt.Sprintf("GitHub validated a different version of %s from %s, line %d is %q there. Set GITHUB_ERRORS_CHECKER_REF to the checked out revision to compare the same version.",
+		ghErr.Path, ref, ghErr.Line, ghErr.Source)
+}
+
+// fetchErrors calls the CODEOWNERS errors API, which is not supported by the GitHub client.
+func (c *GitHubErrors) fetchErrors(ctx context.Context) ([]codeownersError, error) {
+	u := fmt.Sprintf("repos/%s/%s/codeowners/errors", c.orgName, c.orgRepoName)
+	if c.ref != "" {
+		u += "?ref=" + url.QueryEscape(c.ref)
+	}
+
+	req, err := c.ghClient.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Errors []codeownersError `json:"errors"`
+	}
+	if _, err := c.ghClient.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out.Errors, nil
+}
+
+// firstLine returns the first line of GitHub error messages, which are followed by the source line and a caret.
+func firstLine(msg string) string {
+	return strings.TrimSpace(strings.SplitN(msg, "\n", 2)[0])
+}
+
+// Name returns human-readable name of the validator
+func (GitHubErrors) Name() string {
+	return "[Experimental] GitHub CODEOWNERS Errors Checker"
+}
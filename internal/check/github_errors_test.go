@@ -0,0 +1,125 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubErrors(t *testing.T) {
+	const codeowners = `
+		*          @org/platform
+		/docs/     @org/docs @missing
+		/legacy/   platform-team
+		/src/**/*.[ch]  @org/native
+	`
+
+	tests := map[string]struct {
+		ref            string
+		ghErrors       string
+		expectedIssues []api.Issue
+	}{
+		"Should report lines on which GitHub and the local parser disagree": {
+			ghErrors: `[
+				{"line": 3, "column": 22, "kind": "Unknown owner", "source": "/docs/     @org/docs @missing", "message": "Unknown owner on line 3: make sure @missing exists\n\n  /docs/ @org/docs @missing\n                   ^", "path": ".github/CODEOWNERS"},
+				{"line": 5, "column": 1, "kind": "Invalid pattern", "source": "/src/**/*.[ch]  @org/native", "message": "Invalid pattern on line 5: Range not supported\n\n  /src/**/*.[ch]\n  ^", "path": ".github/CODEOWNERS"}
+			]`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(5),
+					Message:  `GitHub reports "Invalid pattern" in line 5, but the line is accepted by the local parser: Invalid pattern on line 5: Range not supported`,
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(4),
+					Message:  "Line 4 is accepted by GitHub, but rejected by the local parser: Owner 'platform-team' does not look like an email",
+				},
+			},
+		},
+		"Should report only the mismatch if GitHub validated another version": {
+			ref: "feature/x",
+			ghErrors: `[
+				{"line": 2, "column": 1, "kind": "Invalid pattern", "source": "/old/**/[a]  @org/old", "message": "Invalid pattern on line 2", "path": ".github/CODEOWNERS"}
+			]`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Warning,
+					Message:  `GitHub validated a different version of .github/CODEOWNERS from feature/x, line 2 is "/old/**/[a]  @org/old" there. Set GITHUB_ERRORS_CHECKER_REF to the checked out revision to compare the same version.`,
+				},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repos/org/repo/codeowners/errors", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.ref, r.URL.Query().Get("ref"))
+				fmt.Fprintf(w, `{"errors": %s}`, tc.ghErrors)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			ghClient := github.NewClient(nil)
+			ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+			sut, err := check.NewGitHubErrors(check.GitHubErrorsConfig{Repository: "org/repo", Ref: tc.ref}, ghClient)
+			require.NoError(t, err)
+
+			// when
+			out, err := sut.Check(context.Background(), LoadInput(codeowners))
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
+
+func TestGitHubErrorsFailures(t *testing.T) {
+	t.Run("Should return config error for wrong repository name", func(t *testing.T) {
+		// when
+		_, err := check.NewGitHubErrors(check.GitHubErrorsConfig{Repository: "repo"}, github.NewClient(nil))
+
+		// then
+		var cfgErr *api.ConfigError
+		require.ErrorAs(t, err, &cfgErr)
+		assert.Equal(t, "OWNER_CHECKER_REPOSITORY", cfgErr.Field)
+	})
+
+	t.Run("Should return API error if GitHub fails", func(t *testing.T) {
+		// given
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		ghClient := github.NewClient(nil)
+		ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+		sut, err := check.NewGitHubErrors(check.GitHubErrorsConfig{Repository: "org/repo"}, ghClient)
+		require.NoError(t, err)
+
+		// when
+		_, err = sut.Check(context.Background(), LoadInput("* @org/platform"))
+
+		// then
+		var apiErr *api.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+}
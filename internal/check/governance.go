@@ -0,0 +1,114 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// codeownersLocations are the locations in which GitHub looks up the CODEOWNERS file.
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+type GovernanceConfig struct {
+	// Team is the governance team which must own the CODEOWNERS file and the governed paths.
+	Team string
+	// Paths are repository paths which must be owned by the team, e.g. `.github/`. The CODEOWNERS file
+	// is always governed.
+	Paths []string
+	// VirtualOwners are owner groups which members may own the governed files in place of the team.
+	VirtualOwners VirtualOwners
+}
+
+// Governance verifies that the CODEOWNERS file, and other governed paths such as `.github/`, are owned
+// by the configured governance team. If CODEOWNERS is not owned by the team, anyone can rewrite the review
+// routing without the team's approval.
+type Governance struct {
+	team    string
+	paths   []string
+	virtual VirtualOwners
+}
+
+// NewGovernance returns new instance of the Governance
+func NewGovernance(cfg GovernanceConfig) (*Governance, error) {
+	if cfg.Team == "" {
+		return nil, &api.ConfigError{Field: "GOVERNANCE_CHECKER_TEAM", Err: errors.New("governance team is required")}
+	}
+
+	paths := make([]string, 0, len(cfg.Paths))
+	for _, p := range cfg.Paths {
+		if p = strings.Trim(p, "/"); p != "" {
+			paths = append(paths, p)
+		}
+	}
+
+	return &Governance{team: cfg.Team, paths: paths, virtual: cfg.VirtualOwners}, nil
+}
+
+// Check searches for governed files which are not owned by the governance team.
+func (c *Governance) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	for _, f := range files {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		reason := c.governanceReason(f)
+		if reason == "" {
+			continue
+		}
+
+		entry, found := matcher.Match(f)
+		if found && c.virtual.Covers(entry.Owners, c.team) {
+			continue
+		}
+
+		msg := fmt.Sprintf("File %q %s, so it must be owned by %s, but it is owned by %s", f, reason, c.team, ownersOf(entry, found))
+		var opts []api.ReportIssueOpt
+		if found {
+			opts = append(opts, api.WithEntry(entry))
+		}
+		bldr.ReportIssue(msg, opts...)
+	}
+
+	return bldr.Output(), nil
+}
+
+// governanceReason returns why a given file must be owned by the governance team, or empty string if it mustn't.
+func (c *Governance) governanceReason(file string) string {
+	for _, loc := range codeownersLocations {
+		if file == loc {
+			return "controls the review routing"
+		}
+	}
+	for _, p := range c.paths {
+		if file == p || strings.HasPrefix(file, p+"/") {
+			return fmt.Sprintf("is in the governed path /%s", p)
+		}
+	}
+	return ""
+}
+
+// Name returns human-readable name of the validator
+func (Governance) Name() string {
+	return "[Experimental] Governance Checker"
+}
@@ -0,0 +1,110 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGovernance(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		".github/CODEOWNERS":         "* @org/platform",
+		".github/workflows/ci.yaml":  "on: push",
+		".github/dependabot.yml":     "version: 2",
+		"policies/branch-rules.yaml": "rules: []",
+		"policies/README.md":         "policies",
+		"main.go":                    "package main",
+	})
+
+	sut, err := check.NewGovernance(check.GovernanceConfig{
+		Team:  "@org/governance",
+		Paths: []string{"/.github/workflows/", "policies/branch-rules.yaml"},
+	})
+	require.NoError(t, err)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  `File ".github/CODEOWNERS" controls the review routing, so it must be owned by @org/governance, but it is owned by @org/platform`,
+		},
+		{
+			Severity: api.Error,
+			Message:  `File "policies/branch-rules.yaml" is in the governed path /policies/branch-rules.yaml, so it must be owned by @org/governance, but it is owned by nobody`,
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), api.Input{
+		RepoDir: repoDir,
+		CodeownersEntries: LoadInput(`
+			/*.go                   @org/platform
+			/.github/               @org/platform
+			/.github/workflows/     @org/Governance
+		`).CodeownersEntries,
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
+
+func TestNewGovernanceRequiresTeam(t *testing.T) {
+	// when
+	_, err := check.NewGovernance(check.GovernanceConfig{Paths: []string{".github/"}})
+
+	// then
+	assert.EqualError(t, err, "governance team is required")
+	assert.Equal(t, "Check the GOVERNANCE_CHECKER_TEAM configuration.", api.Hint(err))
+}
+
+func TestGovernanceVirtualOwners(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		".github/CODEOWNERS":        "* @org/platform",
+		".github/workflows/ci.yaml": "on: push",
+	})
+
+	virtual, err := check.ParseVirtualOwners([]string{"@virtual/governance = @alice, @org/security"})
+	require.NoError(t, err)
+
+	sut, err := check.NewGovernance(check.GovernanceConfig{
+		Team:          "@virtual/governance",
+		Paths:         []string{".github/workflows/"},
+		VirtualOwners: virtual,
+	})
+	require.NoError(t, err)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  `File ".github/CODEOWNERS" controls the review routing, so it must be owned by @virtual/governance, but it is owned by @org/platform`,
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), api.Input{
+		RepoDir: repoDir,
+		CodeownersEntries: LoadInput(`
+			/.github/               @org/platform
+			/.github/workflows/     @org/platform @org/Security
+		`).CodeownersEntries,
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.szostok.io/codeowners/internal/api"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"go.szostok.io/codeowners/pkg/codeowners"
 )
@@ -33,6 +33,15 @@ func LoadInput(in string) api.Input {
 	}
 }
 
+// LoadGitLabInput parses the CODEOWNERS content in the GitLab flavor, with sections.
+func LoadGitLabInput(t *testing.T, in string) api.Input {
+	t.Helper()
+
+	entries, err := codeowners.ParseCodeownersAs(strings.NewReader(in), codeowners.GitLab)
+	require.NoError(t, err)
+	return api.Input{CodeownersEntries: entries}
+}
+
 func assertIssue(t *testing.T, expIssue *api.Issue, gotIssues []api.Issue) {
 	t.Helper()
 
@@ -0,0 +1,121 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// invisibleChars are characters which are not rendered, or rendered as a regular space, so patterns
+// and owners containing them look correct, but don't match any file or GitHub user.
+var invisibleChars = map[rune]string{
+	'\u00A0': "non-breaking space",
+	'\u00AD': "soft hyphen",
+	'\u061C': "arabic letter mark",
+	'\u180E': "mongolian vowel separator",
+	'\u200B': "zero-width space",
+	'\u200C': "zero-width non-joiner",
+	'\u200D': "zero-width joiner",
+	'\u200E': "left-to-right mark",
+	'\u200F': "right-to-left mark",
+	'\u202A': "left-to-right embedding",
+	'\u202B': "right-to-left embedding",
+	'\u202C': "pop directional formatting",
+	'\u202D': "left-to-right override",
+	'\u202E': "right-to-left override",
+	'\u202F': "narrow non-breaking space",
+	'\u2060': "word joiner",
+	'\u2066': "left-to-right isolate",
+	'\u2067': "right-to-left isolate",
+	'\u2068': "first strong isolate",
+	'\u2069': "pop directional isolate",
+	'\uFEFF': "zero-width no-break space",
+}
+
+// InvisibleChars reports invisible Unicode characters in patterns and owners, such as zero-width spaces,
+// non-breaking spaces, and bidirectional text controls, which are often pasted from chats and documents,
+// and trailing whitespace. Characters in comments are not reported.
+type InvisibleChars struct{}
+
+// NewInvisibleChars returns new instance of the InvisibleChars
+func NewInvisibleChars() *InvisibleChars {
+	return &InvisibleChars{}
+}
+
+// Check searches entry lines for invisible characters and trailing whitespace. Each issue points to
+// the column of the character and suggests the line without them.
+func (c *InvisibleChars) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		line := []rune(entry.Raw)
+		fix := api.WithFix(api.Fix{
+			Description: "Remove invisible characters",
+			Edits:       []api.LineEdit{{LineNo: entry.LineNo, NewText: cleanInvisibleChars(entry.Raw)}},
+		})
+
+		for idx, r := range line[:commentStart(line)] {
+			name, found := invisibleChars[r]
+			if !found && r > unicode.MaxASCII && unicode.IsSpace(r) {
+				name, found = "non-ASCII space", true
+			}
+			if !found {
+				continue
+			}
+			bldr.ReportIssue(fmt.Sprintf("Line contains a %s (U+%04X), which makes the pattern or owner silently fail to match.", name, r),
+				api.WithEntry(entry), api.WithColumn(uint64(idx+1)), fix)
+		}
+
+		if trimmed := strings.TrimRightFunc(entry.Raw, unicode.IsSpace); trimmed != entry.Raw {
+			bldr.ReportIssue("Line has trailing whitespace.",
+				api.WithEntry(entry), api.WithColumn(uint64(len([]rune(trimmed))+1)), api.WithSeverity(api.Warning), fix)
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// commentStart returns the index of the inline comment, or the line length if there is none.
+// As in the parser, a comment starts with `#` preceded by whitespace.
+func commentStart(line []rune) int {
+	for idx := 1; idx < len(line); idx++ {
+		if line[idx] == '#' && unicode.IsSpace(line[idx-1]) {
+			return idx
+		}
+	}
+	return len(line)
+}
+
+// cleanInvisibleChars removes invisible characters and trailing whitespace from the line. Non-ASCII spaces
+// are replaced with regular ones, so they keep separating the pattern and owners.
+func cleanInvisibleChars(raw string) string {
+	line := []rune(raw)
+	end := commentStart(line)
+
+	var out strings.Builder
+	for idx, r := range line {
+		switch {
+		case idx >= end:
+			out.WriteRune(r)
+		case r > unicode.MaxASCII && unicode.IsSpace(r):
+			out.WriteRune(' ')
+		case invisibleChars[r] != "":
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return strings.TrimRightFunc(out.String(), unicode.IsSpace)
+}
+
+// Name returns human-readable name of the validator
+func (InvisibleChars) Name() string {
+	return "[Experimental] Invisible Characters Checker"
+}
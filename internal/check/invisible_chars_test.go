@@ -0,0 +1,91 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvisibleChars(t *testing.T) {
+	fix := func(lineNo uint64, text string) []api.Fix {
+		return []api.Fix{{
+			Description: "Remove invisible characters",
+			Edits:       []api.LineEdit{{LineNo: lineNo, NewText: text}},
+		}}
+	}
+
+	tests := map[string]struct {
+		codeownersInput string
+		expectedIssues  []api.Issue
+	}{
+		"Should report zero-width and bidirectional control characters": {
+			codeownersInput: "*  @org/platform\n/docs/\u200B @org/docs\n/src/ @a\u202Elice # \u200B in comment\n",
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Column:   ptr.Uint64Ptr(7),
+					Message:  "Line contains a zero-width space (U+200B), which makes the pattern or owner silently fail to match.",
+					Fixes:    fix(2, "/docs/ @org/docs"),
+				},
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(3),
+					Column:   ptr.Uint64Ptr(9),
+					Message:  "Line contains a right-to-left override (U+202E), which makes the pattern or owner silently fail to match.",
+					Fixes:    fix(3, "/src/ @alice # \u200B in comment"),
+				},
+			},
+		},
+		"Should report non-breaking spaces and trailing whitespace": {
+			codeownersInput: "/docs/\u00A0@org/docs \t\n/src/\u3000@org/dev\n",
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(1),
+					Column:   ptr.Uint64Ptr(7),
+					Message:  "Line contains a non-breaking space (U+00A0), which makes the pattern or owner silently fail to match.",
+					Fixes:    fix(1, "/docs/ @org/docs"),
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(1),
+					Column:   ptr.Uint64Ptr(17),
+					Message:  "Line has trailing whitespace.",
+					Fixes:    fix(1, "/docs/ @org/docs"),
+				},
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Column:   ptr.Uint64Ptr(6),
+					Message:  "Line contains a non-ASCII space (U+3000), which makes the pattern or owner silently fail to match.",
+					Fixes:    fix(2, "/src/ @org/dev"),
+				},
+			},
+		},
+		"Should not report any issues with correct CODEOWNERS file": {
+			codeownersInput: FixtureValidCODEOWNERS,
+			expectedIssues:  nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewInvisibleChars()
+
+			// when
+			out, err := sut.Check(context.TODO(), LoadInput(tc.codeownersInput))
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/jira"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// JIRAComponentSource returns components of a given JIRA project.
+type JIRAComponentSource interface {
+	Components(ctx context.Context, project string) ([]jira.Component, error)
+}
+
+// JIRAComponents verifies that bug triage routing and review routing are consistent. Paths of each
+// JIRA component must be owned by the team that triages the component bugs. Additionally, it reports
+// mapped components which no longer exist in the JIRA project, and JIRA components without the mapping.
+type JIRAComponents struct {
+	mapping *jira.Mapping
+	source  JIRAComponentSource
+	virtual VirtualOwners
+}
+
+// NewJIRAComponents returns new instance of the JIRAComponents
+func NewJIRAComponents(mapping *jira.Mapping, source JIRAComponentSource) *JIRAComponents {
+	return &JIRAComponents{mapping: mapping, source: source}
+}
+
+// WithVirtualOwners allows members of virtual owner groups to own paths of components triaged by the groups.
+func (c *JIRAComponents) WithVirtualOwners(v VirtualOwners) *JIRAComponents {
+	c.virtual = v
+	return c
+}
+
+// Check searches for drift between the JIRA components mapping and CODEOWNERS.
+func (c *JIRAComponents) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	components, err := c.source.Components(ctx, c.mapping.Project)
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	existing := map[string]struct{}{}
+	for _, comp := range components {
+		existing[strings.ToLower(comp.Name)] = struct{}{}
+	}
+	mapped := map[string]struct{}{}
+	for name := range c.mapping.Components {
+		mapped[strings.ToLower(name)] = struct{}{}
+	}
+
+	for _, name := range sortedKeys(c.mapping.Components) {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		if _, found := existing[strings.ToLower(name)]; !found {
+			bldr.ReportIssue(fmt.Sprintf("Component %q is mapped, but it does not exist in the JIRA project %s", name, c.mapping.Project))
+			continue
+		}
+
+		comp := c.mapping.Components[name]
+		for _, p := range comp.Paths {
+			entry, found := matcher.Match(resolvablePath(p))
+			if found && c.virtual.Covers(entry.Owners, comp.Team) {
+				continue
+			}
+
+			msg := fmt.Sprintf("Path %q belongs to the JIRA component %q triaged by %s, but it is owned by %s in CODEOWNERS",
+				p, name, comp.Team, ownersOf(entry, found))
+			var opts []api.ReportIssueOpt
+			if found {
+				opts = append(opts, api.WithEntry(entry))
+			}
+			bldr.ReportIssue(msg, opts...)
+		}
+	}
+
+	for _, comp := range components {
+		if _, found := mapped[strings.ToLower(comp.Name)]; found {
+			continue
+		}
+		msg := fmt.Sprintf("JIRA component %q of the project %s is not mapped to any team", comp.Name, c.mapping.Project)
+		bldr.ReportIssue(msg, api.WithSeverity(api.Warning))
+	}
+
+	return bldr.Output(), nil
+}
+
+// resolvablePath returns a path which ownership can be resolved by the matcher.
+func resolvablePath(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return path.Join(p, dirProbe)
+	}
+	return p
+}
+
+func sortedKeys(m map[string]jira.ComponentMapping) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Name returns human-readable name of the validator
+func (JIRAComponents) Name() string {
+	return "[Experimental] JIRA Components Checker"
+}
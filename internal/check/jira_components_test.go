@@ -0,0 +1,100 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/jira"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJIRAComponentSource struct {
+	components []jira.Component
+	err        error
+}
+
+func (f *fakeJIRAComponentSource) Components(context.Context, string) ([]jira.Component, error) {
+	return f.components, f.err
+}
+
+func TestJIRAComponents(t *testing.T) {
+	// given
+	mapping, err := jira.ParseMapping(strings.NewReader(`
+project: PAY
+components:
+  Payments API:
+    team: "@org/payments"
+    paths: [/services/payments/, /api/payments.proto]
+  Docs:
+    team: "@org/docs"
+    paths: [/docs/]
+  Billing:
+    team: "@org/billing"
+    paths: [/billing/]
+`))
+	require.NoError(t, err)
+
+	source := &fakeJIRAComponentSource{
+		components: []jira.Component{
+			{ID: "1", Name: "payments api"},
+			{ID: "2", Name: "Docs"},
+			{ID: "3", Name: "Mobile"},
+		},
+	}
+	sut := check.NewJIRAComponents(mapping, source)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Error,
+			Message:  `Component "Billing" is mapped, but it does not exist in the JIRA project PAY`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  `Path "/docs/" belongs to the JIRA component "Docs" triaged by @org/docs, but it is owned by @org/platform in CODEOWNERS`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(4),
+			Message:  `Path "/api/payments.proto" belongs to the JIRA component "Payments API" triaged by @org/payments, but it is owned by @org/api in CODEOWNERS`,
+		},
+		{
+			Severity: api.Warning,
+			Message:  `JIRA component "Mobile" of the project PAY is not mapped to any team`,
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), LoadInput(`
+		*                      @org/platform
+		/services/payments/    @org/Payments
+		/api/                  @org/api
+		/docs/*.md             @org/docs
+	`))
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
+
+func TestJIRAComponentsSourceError(t *testing.T) {
+	// given
+	mapping := &jira.Mapping{Project: "PAY"}
+	sut := check.NewJIRAComponents(mapping, &fakeJIRAComponentSource{err: errors.New("connection refused")})
+
+	// when
+	out, err := sut.Check(context.Background(), LoadInput(FixtureValidCODEOWNERS))
+
+	// then
+	assert.EqualError(t, err, "connection refused")
+	assert.Empty(t, out)
+}
@@ -0,0 +1,85 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/labeler"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+type LabelerSyncConfig struct {
+	// File is the path of the labeler configuration relative to the repository root.
+	File string
+	// LabelPrefix is the prefix of team labels. Other labels are not verified.
+	LabelPrefix string
+}
+
+// LabelerSync verifies that team labels of the GitHub labeler configuration match CODEOWNERS,
+// so pull requests are labeled with the same teams that are requested to review them.
+type LabelerSync struct {
+	file   string
+	prefix string
+}
+
+// NewLabelerSync returns new instance of the LabelerSync
+func NewLabelerSync(cfg LabelerSyncConfig) (*LabelerSync, error) {
+	if cfg.File == "" {
+		return nil, &api.ConfigError{Field: "LABELER_CHECKER_FILE", Err: errors.New("cannot be empty")}
+	}
+	if cfg.LabelPrefix == "" {
+		return nil, &api.ConfigError{Field: "LABELER_CHECKER_LABEL_PREFIX", Err: errors.New("cannot be empty, as all labels would be treated as team labels")}
+	}
+
+	return &LabelerSync{file: cfg.File, prefix: cfg.LabelPrefix}, nil
+}
+
+// Check searches for drift between team labels of the labeler configuration and CODEOWNERS.
+func (c *LabelerSync) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	cfg, err := labeler.Load(filepath.Join(in.RepoDir, c.file))
+	switch {
+	case os.IsNotExist(err):
+		bldr.ReportIssue(fmt.Sprintf("Labeler configuration %s does not exist. Generate it with 'codeowners report labeler --write'.", c.file))
+		return bldr.Output(), nil
+	case err != nil:
+		return api.Output{}, errors.Wrapf(err, "while loading %s", c.file)
+	}
+
+	for _, d := range labeler.Diff(cfg, labeler.Generate(in.CodeownersEntries, c.prefix), c.prefix) {
+		switch {
+		case d.Missing:
+			bldr.ReportIssue(fmt.Sprintf("Label %q of %s is missing in %s", d.Label, d.Team, c.file))
+		case d.Stale:
+			msg := fmt.Sprintf("Label %q is not owned by any team in CODEOWNERS", d.Label)
+			bldr.ReportIssue(msg, api.WithSeverity(api.Warning))
+		default:
+			var diffs []string
+			if len(d.MissingGlobs) > 0 {
+				diffs = append(diffs, "missing globs: "+strings.Join(d.MissingGlobs, ", "))
+			}
+			if len(d.ExtraGlobs) > 0 {
+				diffs = append(diffs, "unexpected globs: "+strings.Join(d.ExtraGlobs, ", "))
+			}
+			bldr.ReportIssue(fmt.Sprintf("Label %q of %s is out of sync with CODEOWNERS, %s", d.Label, d.Team, strings.Join(diffs, "; ")))
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// Name returns human-readable name of the validator
+func (LabelerSync) Name() string {
+	return "[Experimental] Labeler Sync Checker"
+}
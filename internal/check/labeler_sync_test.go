@@ -0,0 +1,84 @@
+package check_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelerSync(t *testing.T) {
+	tests := map[string]struct {
+		// labeler is the content of the labeler configuration, which is not created if empty
+		labeler   string
+		expIssues []api.Issue
+	}{
+		"In sync": {
+			labeler: `
+team/docs: ['docs/**']
+team/platform: ['**']
+documentation: ['**/*.md']
+`,
+		},
+		"Drift": {
+			labeler: `
+team/docs:
+- changed-files:
+  - any-glob-to-any-file: ['docs/**', 'website/**']
+team/old: ['legacy/**']
+`,
+			expIssues: []api.Issue{
+				{Severity: api.Error, Message: `Label "team/docs" of @org/docs is out of sync with CODEOWNERS, unexpected globs: website/**`},
+				{Severity: api.Error, Message: `Label "team/platform" of @org/platform is missing in .github/labeler.yml`},
+				{Severity: api.Warning, Message: `Label "team/old" is not owned by any team in CODEOWNERS`},
+			},
+		},
+		"Missing file": {
+			expIssues: []api.Issue{
+				{Severity: api.Error, Message: "Labeler configuration .github/labeler.yml does not exist. Generate it with 'codeowners report labeler --write'."},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			repoDir := t.TempDir()
+			if tc.labeler != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(repoDir, ".github"), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".github", "labeler.yml"), []byte(tc.labeler), 0o600))
+			}
+
+			in := LoadInput(`
+				*        @org/platform
+				/docs/   @org/docs @alice
+			`)
+			in.RepoDir = repoDir
+
+			sut, err := check.NewLabelerSync(check.LabelerSyncConfig{File: ".github/labeler.yml", LabelPrefix: "team/"})
+			require.NoError(t, err)
+
+			// when
+			out, err := sut.Check(context.Background(), in)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expIssues, out.Issues)
+		})
+	}
+}
+
+func TestNewLabelerSyncErrors(t *testing.T) {
+	// when
+	_, err := check.NewLabelerSync(check.LabelerSyncConfig{File: ".github/labeler.yml"})
+
+	// then
+	var cfgErr *api.ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "LABELER_CHECKER_LABEL_PREFIX", cfgErr.Field)
+}
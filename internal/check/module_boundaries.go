@@ -0,0 +1,240 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// dirProbe is the file name used to resolve the ownership of a directory. It does not match patterns
+// of specific file types, e.g. `*.go`, so the directory is resolved by its default owner.
+const dirProbe = "codeowners-dir-probe"
+
+type ModuleBoundariesConfig struct {
+	// Ecosystems are names of ecosystems which module roots are verified, e.g. go, bazel, npm.
+	Ecosystems []string
+}
+
+// ecosystem detects module roots, such as Go modules or Bazel packages, in the repository.
+type ecosystem struct {
+	// kind is the human-readable name of a single module, e.g. "Go module".
+	kind string
+	// roots returns module root directories relative to the repository root. The repository root itself is omitted.
+	roots func(repoDir string, files []string) ([]string, error)
+}
+
+var ecosystems = map[string]ecosystem{
+	"go":    {kind: "Go module", roots: goModuleRoots},
+	"bazel": {kind: "Bazel package", roots: bazelPackageRoots},
+	"npm":   {kind: "npm workspace", roots: npmWorkspaceRoots},
+}
+
+// ModuleBoundaries verifies that each module root in a multi-module repository has an explicit
+// ownership rule, instead of inheriting the owners from a catch-all pattern, such as `*`. It keeps
+// module owners accountable for their modules. Supported ecosystems:
+//   - go: directories with a nested `go.mod` file, except `testdata` and `vendor` directories,
+//   - bazel: directories with a `BUILD` or `BUILD.bazel` file,
+//   - npm: npm and yarn workspaces listed in the root `package.json` file.
+type ModuleBoundaries struct {
+	ecosystems []string
+}
+
+// NewModuleBoundaries returns new instance of the ModuleBoundaries
+func NewModuleBoundaries(cfg ModuleBoundariesConfig) (*ModuleBoundaries, error) {
+	if len(cfg.Ecosystems) == 0 {
+		return nil, &api.ConfigError{Field: "MODULE_BOUNDARIES_CHECKER_ECOSYSTEMS", Err: errors.New("at least one ecosystem is required")}
+	}
+	for _, name := range cfg.Ecosystems {
+		if _, found := ecosystems[name]; !found {
+			return nil, &api.ConfigError{
+				Field: "MODULE_BOUNDARIES_CHECKER_ECOSYSTEMS",
+				Err:   errors.Errorf("unknown ecosystem %q, supported: %s", name, strings.Join(supportedEcosystems(), ", ")),
+			}
+		}
+	}
+
+	return &ModuleBoundaries{ecosystems: cfg.Ecosystems}, nil
+}
+
+// Check searches for module roots without an explicit ownership rule.
+func (c *ModuleBoundaries) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	for _, name := range c.ecosystems {
+		eco := ecosystems[name]
+		roots, err := eco.roots(in.RepoDir, files)
+		if err != nil {
+			return api.Output{}, errors.Wrapf(err, "while detecting %s roots", eco.kind)
+		}
+
+		for _, root := range roots {
+			if ctxutil.ShouldExit(ctx) {
+				return api.Output{}, ctx.Err()
+			}
+
+			entry, found := matcher.Match(path.Join(root, dirProbe))
+			switch {
+			case !found || len(entry.Owners) == 0:
+				msg := fmt.Sprintf("%s %q is not owned. Add an explicit ownership rule, e.g. `/%s/ @org/team`.", eco.kind, root, root)
+				var opts []api.ReportIssueOpt
+				if found {
+					opts = append(opts, api.WithEntry(entry))
+				}
+				bldr.ReportIssue(msg, opts...)
+			case isCatchAll(entry.Pattern):
+				msg := fmt.Sprintf("%s %q inherits owners from the catch-all pattern %q. Add an explicit ownership rule, e.g. `/%s/ @org/team`.", eco.kind, root, entry.Pattern, root)
+				bldr.ReportIssue(msg, api.WithEntry(entry))
+			}
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// goModuleRoots returns directories of nested go.mod files.
+func goModuleRoots(_ string, files []string) ([]string, error) {
+	return dirsOf(files, func(dir, base string) bool {
+		return base == "go.mod" && !isIgnoredByGo(dir)
+	}), nil
+}
+
+// bazelPackageRoots returns directories of nested BUILD files.
+func bazelPackageRoots(_ string, files []string) ([]string, error) {
+	return dirsOf(files, func(_, base string) bool {
+		return base == "BUILD" || base == "BUILD.bazel"
+	}), nil
+}
+
+// npmWorkspaceRoots returns directories of packages matched by the `workspaces` globs from the root package.json.
+// Both the npm (`"workspaces": [...]`) and the yarn (`"workspaces": {"packages": [...]}`) forms are supported.
+func npmWorkspaceRoots(repoDir string, files []string) ([]string, error) {
+	raw, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, errors.Wrap(err, "while decoding package.json")
+	}
+	if len(manifest.Workspaces) == 0 {
+		return nil, nil
+	}
+
+	var globs []string
+	if err := json.Unmarshal(manifest.Workspaces, &globs); err != nil {
+		var yarn struct {
+			Packages []string `json:"packages"`
+		}
+		if err := json.Unmarshal(manifest.Workspaces, &yarn); err != nil {
+			return nil, errors.Wrap(err, "while decoding package.json workspaces")
+		}
+		globs = yarn.Packages
+	}
+
+	var patterns []codeowners.Pattern
+	for _, g := range globs {
+		p, err := codeowners.NewPattern("/" + strings.TrimPrefix(path.Clean(g), "./"))
+		if err != nil {
+			return nil, &api.MatchError{Pattern: g, Err: err}
+		}
+		patterns = append(patterns, p)
+	}
+
+	return dirsOf(files, func(dir, base string) bool {
+		return base == "package.json" && !strings.Contains("/"+dir+"/", "/node_modules/") && matchesDir(dir, patterns)
+	}), nil
+}
+
+func matchesDir(dir string, patterns []codeowners.Pattern) bool {
+	for _, p := range patterns {
+		if p.Match(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func supportedEcosystems() []string {
+	out := make([]string, 0, len(ecosystems))
+	for name := range ecosystems {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// dirsOf returns unique directories of files accepted by a given function, except the repository root.
+func dirsOf(files []string, accept func(dir, base string) bool) []string {
+	var (
+		out  []string
+		seen = map[string]struct{}{}
+	)
+	for _, f := range files {
+		dir, base := path.Dir(f), path.Base(f)
+		if dir == "." || !accept(dir, base) {
+			continue
+		}
+		if _, found := seen[dir]; found {
+			continue
+		}
+		seen[dir] = struct{}{}
+		out = append(out, dir)
+	}
+	return out
+}
+
+// isIgnoredByGo returns true if the go command ignores a given directory when it resolves modules.
+func isIgnoredByGo(dir string) bool {
+	for _, segment := range strings.Split(dir, "/") {
+		if segment == "testdata" || segment == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// isCatchAll returns true if a given pattern matches all files in the repository.
+func isCatchAll(pattern string) bool {
+	switch strings.TrimPrefix(pattern, "/") {
+	case "*", "**", "**/*":
+		return true
+	}
+	return false
+}
+
+// Name returns human-readable name of the validator
+func (ModuleBoundaries) Name() string {
+	return "[Experimental] Module Boundaries Checker"
+}
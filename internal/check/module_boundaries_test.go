@@ -0,0 +1,166 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleBoundaries(t *testing.T) {
+	tests := map[string]struct {
+		ecosystems     []string
+		files          map[string]string
+		codeowners     string
+		expectedIssues []api.Issue
+	}{
+		"Go modules": {
+			ecosystems: []string{"go"},
+			files: map[string]string{
+				"go.mod":                      "module example.com/root",
+				"services/api/go.mod":         "module example.com/api",
+				"services/worker/go.mod":      "module example.com/worker",
+				"libs/shared/go.mod":          "module example.com/shared",
+				"tools/go.mod":                "module example.com/tools",
+				"pkg/testdata/fixture/go.mod": "module example.com/fixture",
+				"vendor/example.com/x/go.mod": "module example.com/x",
+			},
+			codeowners: `
+				*                   @org/platform
+				/services/api/      @org/api
+				/libs/shared/*.go   @org/shared
+				/tools/
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "Go module \"services/worker\" inherits owners from the catch-all pattern \"*\". Add an explicit ownership rule, e.g. `/services/worker/ @org/team`.",
+				},
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "Go module \"libs/shared\" inherits owners from the catch-all pattern \"*\". Add an explicit ownership rule, e.g. `/libs/shared/ @org/team`.",
+				},
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(5),
+					Message:  "Go module \"tools\" is not owned. Add an explicit ownership rule, e.g. `/tools/ @org/team`.",
+				},
+			},
+		},
+		"Bazel packages": {
+			ecosystems: []string{"bazel"},
+			files: map[string]string{
+				"BUILD.bazel":            "",
+				"WORKSPACE":              "",
+				"src/server/BUILD":       "",
+				"src/client/BUILD":       "",
+				"src/client/BUILD.bazel": "",
+			},
+			codeowners: `
+				**            @org/platform
+				/src/server/  @org/backend
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "Bazel package \"src/client\" inherits owners from the catch-all pattern \"**\". Add an explicit ownership rule, e.g. `/src/client/ @org/team`.",
+				},
+			},
+		},
+		"npm workspaces": {
+			ecosystems: []string{"npm"},
+			files: map[string]string{
+				"package.json":                            `{"name": "root", "workspaces": ["packages/*", "./apps/web"]}`,
+				"packages/ui/package.json":                `{"name": "ui"}`,
+				"packages/ui/node_modules/x/package.json": `{"name": "x"}`,
+				"packages/api/package.json":               `{"name": "api"}`,
+				"apps/web/package.json":                   `{"name": "web"}`,
+				"apps/docs/package.json":                  `{"name": "docs"}`,
+			},
+			codeowners: `
+				*               @org/platform
+				/packages/ui/   @org/frontend
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "npm workspace \"apps/web\" inherits owners from the catch-all pattern \"*\". Add an explicit ownership rule, e.g. `/apps/web/ @org/team`.",
+				},
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "npm workspace \"packages/api\" inherits owners from the catch-all pattern \"*\". Add an explicit ownership rule, e.g. `/packages/api/ @org/team`.",
+				},
+			},
+		},
+		"yarn workspaces": {
+			ecosystems: []string{"npm"},
+			files: map[string]string{
+				"package.json":             `{"name": "root", "workspaces": {"packages": ["libs/**"]}}`,
+				"libs/core/package.json":   `{"name": "core"}`,
+				"libs/ui/kit/package.json": `{"name": "kit"}`,
+			},
+			codeowners: `
+				/libs/core/   @org/core
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  "npm workspace \"libs/ui/kit\" is not owned. Add an explicit ownership rule, e.g. `/libs/ui/kit/ @org/team`.",
+				},
+			},
+		},
+		"Repository without npm workspaces": {
+			ecosystems: []string{"npm"},
+			files: map[string]string{
+				"package.json":            `{"name": "root"}`,
+				"tools/lint/package.json": `{"name": "lint"}`,
+			},
+			codeowners: `
+				* @org/platform
+			`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			repoDir := t.TempDir()
+			gitrepo.Init(t, repoDir)
+			gitrepo.CommitFiles(t, repoDir, tc.files)
+
+			sut, err := check.NewModuleBoundaries(check.ModuleBoundariesConfig{Ecosystems: tc.ecosystems})
+			require.NoError(t, err)
+
+			// when
+			out, err := sut.Check(context.Background(), api.Input{
+				RepoDir:           repoDir,
+				CodeownersEntries: LoadInput(tc.codeowners).CodeownersEntries,
+			})
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
+
+func TestModuleBoundariesConfig(t *testing.T) {
+	// when
+	_, err := check.NewModuleBoundaries(check.ModuleBoundariesConfig{Ecosystems: []string{"go", "maven"}})
+
+	// then
+	var cfgErr *api.ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "MODULE_BOUNDARIES_CHECKER_ECOSYSTEMS", cfgErr.Field)
+	assert.EqualError(t, err, `unknown ecosystem "maven", supported: bazel, go, npm`)
+}
@@ -4,14 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path"
+	"sort"
 	"strings"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/codeowners"
 
-	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"gopkg.in/pipe.v2"
 )
@@ -23,12 +23,36 @@ type NotOwnedFileConfig struct {
 	TrustWorkspace bool     `envconfig:"default=false"`
 	SkipPatterns   []string `envconfig:"optional"`
 	Subdirectories []string `envconfig:"optional"`
+	// SkipGenerated excludes files marked as `linguist-generated` or `linguist-vendored` in .gitattributes,
+	// as they don't need human owners.
+	SkipGenerated bool `envconfig:"default=false"`
+	// SuggestOwners suggests owners of not owned files based on owners of nearby files and recent committers.
+	SuggestOwners bool `envconfig:"default=false"`
+	// IgnoreSources lists sources of ignore rules which tracked files are treated as owned.
+	// The global gitignore depends on the machine configuration, so results may differ between machines if it's enabled.
+	IgnoreSources []string `envconfig:"default=gitignore;info-exclude;global"`
 }
 
+// Sources of ignore rules honored by the not-owned check.
+const (
+	// IgnoreSourceGitignore are the .gitignore files in the repository.
+	IgnoreSourceGitignore = "gitignore"
+	// IgnoreSourceInfoExclude is the $GIT_DIR/info/exclude file.
+	IgnoreSourceInfoExclude = "info-exclude"
+	// IgnoreSourceGlobal is the file set by the core.excludesFile git option, $XDG_CONFIG_HOME/git/ignore by default.
+	IgnoreSourceGlobal = "global"
+)
+
+// renameHistoryDepth is the number of recent commits in which renames of not owned files are detected.
+const renameHistoryDepth = 1000
+
 type NotOwnedFile struct {
 	skipPatterns   map[string]struct{}
 	subDirectories []string
 	trustWorkspace bool
+	skipGenerated  bool
+	suggestOwners  bool
+	ignoreSources  []string
 }
 
 func NewNotOwnedFile(cfg NotOwnedFileConfig) *NotOwnedFile {
@@ -41,10 +65,16 @@ func NewNotOwnedFile(cfg NotOwnedFileConfig) *NotOwnedFile {
 		skipPatterns:   skip,
 		subDirectories: cfg.Subdirectories,
 		trustWorkspace: cfg.TrustWorkspace,
+		skipGenerated:  cfg.SkipGenerated,
+		suggestOwners:  cfg.SuggestOwners,
+		ignoreSources:  cfg.IgnoreSources,
 	}
 }
 
-func (c *NotOwnedFile) Check(ctx context.Context, in api.Input) (output api.Output, err error) {
+// Check reports files which are not matched by any CODEOWNERS pattern. Files are listed once and matched
+// in memory, so the repository is never modified, and the check works in read-only checkouts and with
+// uncommitted changes.
+func (c *NotOwnedFile) Check(ctx context.Context, in api.Input) (api.Output, error) {
 	if ctxutil.ShouldExit(ctx) {
 		return api.Output{}, ctx.Err()
 	}
@@ -56,146 +86,299 @@ func (c *NotOwnedFile) Check(ctx context.Context, in api.Input) (output api.Outp
 		return bldr.Output(), nil
 	}
 
-	patterns := c.patternsToBeIgnored(in.CodeownersEntries)
+	isGit := !in.NonGit
+	if !isGit && c.skipGenerated {
+		return api.Output{}, &api.ConfigError{
+			Field: "NOT_OWNED_CHECKER_SKIP_GENERATED",
+			Err:   errors.New("excluding generated files is supported only for git repositories"),
+		}
+	}
+	if isGit {
+		if err := c.validateIgnoreSources(); err != nil {
+			return api.Output{}, err
+		}
+		if err := c.trustWorkspaceIfNeeded(in.RepoDir); err != nil {
+			return api.Output{}, &api.GitError{Op: "marking repository as safe", Err: err}
+		}
+	}
 
-	if err := c.trustWorkspaceIfNeeded(in.RepoDir); err != nil {
+	patterns, err := c.compilePatterns(in)
+	if err != nil {
 		return api.Output{}, err
 	}
 
-	statusOut, err := c.GitCheckStatus(in.RepoDir)
+	files, err := in.Files()
 	if err != nil {
 		return api.Output{}, err
 	}
-	if len(statusOut) != 0 {
-		bldr.ReportIssue("git state is dirty: commit all changes before executing this check")
-		return bldr.Output(), nil
+
+	var ignored map[string]struct{}
+	if isGit {
+		ignored, err = c.GitIgnoredFiles(in.RepoDir)
+		if err != nil {
+			return api.Output{}, &api.GitError{Op: "listing ignored files", Err: err}
+		}
+	}
+
+	var notOwned []string
+	for _, f := range c.filesInSubdirectories(files) {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+		if _, found := ignored[f]; found {
+			continue
+		}
+		if !matchesAnyPattern(patterns, f) {
+			notOwned = append(notOwned, f)
+		}
 	}
 
-	defer func() {
-		errReset := c.GitResetCurrentBranch(in.RepoDir)
+	if c.skipGenerated {
+		notOwned, err = git.WithoutGenerated(in.RepoDir, notOwned)
 		if err != nil {
-			output = api.Output{}
-			err = multierror.Append(err, errReset).ErrorOrNil()
+			return api.Output{}, &api.GitError{Op: "excluding generated files", Err: err}
 		}
-	}()
+	}
 
-	err = c.AppendToGitignoreFile(in.RepoDir, patterns)
-	if err != nil {
-		return api.Output{}, err
+	notOwned, known := c.splitBaselined(in, notOwned)
+
+	var out api.Output
+	if len(notOwned) > 0 {
+		hinted := notOwned
+		if isGit {
+			hinted, err = c.withRenameHints(in, notOwned)
+			if err != nil {
+				return api.Output{}, err
+			}
+		}
+		out, err = c.reportNotOwned(in, files, notOwned, hinted)
+		if err != nil {
+			return api.Output{}, err
+		}
 	}
 
-	err = c.GitRemoveIgnoredFiles(in.RepoDir)
-	if err != nil {
-		return api.Output{}, err
+	// known files are reported in a separate issue, so the baseline doesn't hide new files listed together with them
+	if len(known) > 0 {
+		bldr.ReportIssue(fmt.Sprintf("Found %d not owned files recorded in the baseline:\n%s", len(known), c.ListFormatFunc(known)), api.WithPaths(known...))
+		out.Issues = append(out.Issues, bldr.Output().Issues...)
+	}
+	return out, nil
+}
+
+// splitBaselined splits not owned files into new ones and the ones recorded in the baseline.
+func (c *NotOwnedFile) splitBaselined(in api.Input, notOwned []string) ([]string, []string) {
+	if in.Baseline == nil {
+		return notOwned, nil
+	}
+
+	var fresh, known []string
+	for _, f := range notOwned {
+		if in.Baseline.HasPath(c.Name(), f) {
+			known = append(known, f)
+			continue
+		}
+		fresh = append(fresh, f)
+	}
+	return fresh, known
+}
+
+// reportNotOwned reports not owned files, listed together with hints, and suggests their owners if enabled.
+func (c *NotOwnedFile) reportNotOwned(in api.Input, allFiles, notOwned, hinted []string) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	msg := fmt.Sprintf("Found %d not owned files (skipped patterns: %q):\n%s", len(notOwned), c.skipPatternsList(), c.ListFormatFunc(hinted))
+	if !c.suggestOwners {
+		bldr.ReportIssue(msg, api.WithPaths(notOwned...))
+		return bldr.Output(), nil
 	}
 
-	out, err := c.GitListFiles(in.RepoDir)
+	suggestions, err := c.suggest(in, allFiles, notOwned)
 	if err != nil {
 		return api.Output{}, err
 	}
 
-	lsOut := strings.TrimSpace(out)
-	if lsOut != "" {
-		lines := strings.Split(lsOut, "\n")
-		msg := fmt.Sprintf("Found %d not owned files (skipped patterns: %q):\n%s", len(lines), c.skipPatternsList(), c.ListFormatFunc(lines))
-		bldr.ReportIssue(msg)
+	var (
+		opts   = []api.ReportIssueOpt{api.WithPaths(notOwned...)}
+		points []string
+	)
+	for _, s := range suggestions {
+		points = append(points, fmt.Sprintf("%s (%s)", s.entry(), s.reason))
+		opts = append(opts, api.WithFix(api.Fix{
+			Description: fmt.Sprintf("Add %q entry", s.entry()),
+			Edits:       []api.LineEdit{{LineNo: lastLineNo(in.CodeownersEntries), NewText: s.entry(), Insert: true}},
+		}))
+	}
+	if len(points) > 0 {
+		msg += fmt.Sprintf("\n          Suggested entries:\n%s", c.ListFormatFunc(points))
 	}
 
+	bldr.ReportIssue(msg, opts...)
 	return bldr.Output(), nil
 }
 
-func (c *NotOwnedFile) patternsToBeIgnored(entries []codeowners.Entry) []string {
-	var patterns []string
-	for _, entry := range entries {
-		if _, found := c.skipPatterns[entry.Pattern]; found {
-			continue
-		}
-		patterns = append(patterns, entry.Pattern)
+// withRenameHints annotates files which were recently renamed from a path owned by some pattern,
+// as it's likely that the pattern should be updated to the new path. Reading the history requires
+// the git binary, so hints are skipped without it.
+func (c *NotOwnedFile) withRenameHints(in api.Input, files []string) ([]string, error) {
+	previous, err := git.PreviousPaths(in.RepoDir, renameHistoryDepth)
+	if errors.Is(err, git.ErrNoBinary) {
+		// hints are optional, so files are listed without them if the history cannot be read
+		return files, nil
+	}
+	if err != nil {
+		return nil, &api.GitError{Op: "detecting renamed files", Err: err}
+	}
+	if len(previous) == 0 {
+		return files, nil
 	}
 
-	return patterns
+	matcher, err := c.ownershipMatcher(in)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		hint := f
+		for _, old := range previous[f] {
+			if e, found := matcher.Match(old); found {
+				hint = fmt.Sprintf("%s (renamed from %s, update the %q pattern in line %d)", f, old, e.Pattern, e.LineNo)
+				break
+			}
+		}
+		out = append(out, hint)
+	}
+	return out, nil
 }
 
-func (c *NotOwnedFile) AppendToGitignoreFile(repoDir string, patterns []string) error {
-	f, err := os.OpenFile(path.Join(repoDir, ".gitignore"),
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		return err
+// compilePatterns returns patterns of files which are owned.
+func (c *NotOwnedFile) compilePatterns(in api.Input) ([]codeowners.Pattern, error) {
+	raw := c.patternsToBeIgnored(in.CodeownersEntries)
+	compiled := make([]codeowners.Pattern, 0, len(raw))
+	for _, p := range raw {
+		pattern, err := in.Pattern(p)
+		if err != nil {
+			return nil, &api.MatchError{Pattern: p, Err: err}
+		}
+		compiled = append(compiled, pattern)
 	}
+	return compiled, nil
+}
 
-	defer f.Close()
+// filesInSubdirectories returns files under the configured subdirectories, or all files if none are configured.
+func (c *NotOwnedFile) filesInSubdirectories(files []string) []string {
+	if len(c.subDirectories) == 0 {
+		return files
+	}
 
-	content := strings.Builder{}
-	// ensure we are starting from new line
-	content.WriteString("\n")
-	for _, p := range patterns {
-		content.WriteString(fmt.Sprintf("%s\n", p))
+	var out []string
+	for _, f := range files {
+		for _, dir := range c.subDirectories {
+			dir = strings.TrimSuffix(codeowners.NormalizePath(dir), "/")
+			if dir == "" || dir == "." || strings.HasPrefix(f, dir+"/") {
+				out = append(out, f)
+				break
+			}
+		}
 	}
+	return out
+}
 
-	_, err = f.WriteString(content.String())
-	if err != nil {
-		return err
+func matchesAnyPattern(patterns []codeowners.Pattern, file string) bool {
+	for _, p := range patterns {
+		if p.Match(file) {
+			return true
+		}
 	}
-	return nil
+	return false
 }
 
-func (c *NotOwnedFile) GitRemoveIgnoredFiles(repoDir string) error {
-	gitrm := pipe.Script(
-		pipe.ChDir(repoDir),
-		pipe.Line(
-			pipe.Exec("git", "ls-files", "-ci", "--exclude-standard", "-z"),
-			pipe.Exec("xargs", "-0", "-r", "git", "rm", "--cached"),
-		),
-	)
+// ownershipMatcher matches files against entries which are not skipped.
+func (c *NotOwnedFile) ownershipMatcher(in api.Input) (*codeowners.Matcher, error) {
+	var entries []codeowners.Entry
+	for _, e := range in.CodeownersEntries {
+		if _, found := c.skipPatterns[e.Pattern]; !found {
+			entries = append(entries, e)
+		}
+	}
 
-	_, stderr, err := pipe.DividedOutput(gitrm)
+	matcher, err := codeowners.NewMatcher(entries)
 	if err != nil {
-		return errors.Wrap(err, string(stderr))
+		return nil, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
 	}
-	return nil
+	return matcher, nil
 }
 
-func (c *NotOwnedFile) GitCheckStatus(repoDir string) ([]byte, error) {
-	gitstate := pipe.Script(
-		pipe.ChDir(repoDir),
-		pipe.Exec("git", "status", "--porcelain"),
-	)
-
-	out, stderr, err := pipe.DividedOutput(gitstate)
-	if err != nil {
-		return nil, errors.Wrap(err, string(stderr))
+// patternsToBeIgnored returns patterns of files which are owned. In the GitLab flavor, a file is owned
+// if any section matches it, so patterns of all sections are combined.
+func (c *NotOwnedFile) patternsToBeIgnored(entries []codeowners.Entry) []string {
+	var patterns []string
+	for _, entry := range entries {
+		if _, found := c.skipPatterns[entry.Pattern]; found {
+			continue
+		}
+		patterns = append(patterns, entry.Pattern)
 	}
 
-	return out, nil
+	return patterns
 }
 
-func (c *NotOwnedFile) GitResetCurrentBranch(repoDir string) error {
-	gitreset := pipe.Script(
-		pipe.ChDir(repoDir),
-		pipe.Exec("git", "reset", "--hard"),
-	)
-	_, stderr, err := pipe.DividedOutput(gitreset)
-	if err != nil {
-		return errors.Wrap(err, string(stderr))
+func (c *NotOwnedFile) validateIgnoreSources() error {
+	for _, src := range c.ignoreSources {
+		switch src {
+		case IgnoreSourceGitignore, IgnoreSourceInfoExclude, IgnoreSourceGlobal:
+		default:
+			return &api.ConfigError{
+				Field: "NOT_OWNED_CHECKER_IGNORE_SOURCES",
+				Err: errors.Errorf("unknown ignore source %q, possible values: %s, %s, %s",
+					src, IgnoreSourceGitignore, IgnoreSourceInfoExclude, IgnoreSourceGlobal),
+			}
+		}
 	}
 	return nil
 }
 
-func (c *NotOwnedFile) GitListFiles(repoDir string) (string, error) {
-	args := []string{"ls-files"}
-	args = append(args, c.subDirectories...)
-
-	gitls := pipe.Script(
-		pipe.ChDir(repoDir),
-		pipe.Exec("git", args...),
-	)
+// GitIgnoredFiles returns tracked files ignored by the configured ignore sources, which are treated as owned.
+// Ignore rules are evaluated by git, or by go-git if it's the selected backend, which only read the index.
+func (c *NotOwnedFile) GitIgnoredFiles(repoDir string) (map[string]struct{}, error) {
+	var excludeFiles []string
+	// sources are ordered as in git, from the lowest precedence
+	if c.honors(IgnoreSourceGlobal) {
+		p, err := git.GlobalExcludesFile(repoDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "while resolving global gitignore")
+		}
+		if fileExists(p) {
+			excludeFiles = append(excludeFiles, p)
+		}
+	}
+	if c.honors(IgnoreSourceInfoExclude) {
+		p, err := git.InfoExcludeFile(repoDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "while resolving info/exclude file")
+		}
+		if fileExists(p) {
+			excludeFiles = append(excludeFiles, p)
+		}
+	}
+	return git.IgnoredFiles(repoDir, excludeFiles, c.honors(IgnoreSourceGitignore))
+}
 
-	stdout, stderr, err := pipe.DividedOutput(gitls)
-	if err != nil {
-		return "", errors.Wrap(err, string(stderr))
+func (c *NotOwnedFile) honors(source string) bool {
+	for _, src := range c.ignoreSources {
+		if src == source {
+			return true
+		}
 	}
+	return false
+}
 
-	return string(stdout), nil
+func fileExists(p string) bool {
+	if p == "" {
+		return false
+	}
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
 }
 
 func (c *NotOwnedFile) trustWorkspaceIfNeeded(repo string) error {
@@ -217,6 +400,8 @@ func (c *NotOwnedFile) skipPatternsList() string {
 	for k := range c.skipPatterns {
 		list = append(list, k)
 	}
+	// map iteration order is random, and the message must be the same on each run
+	sort.Strings(list)
 	return strings.Join(list, ",")
 }
 
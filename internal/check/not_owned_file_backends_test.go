@@ -0,0 +1,74 @@
+//go:build !minimal && !js
+
+package check_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/checktest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotOwnedFileGitBackends(t *testing.T) {
+	// given
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	// ignored files are committed before ignore rules are added, as otherwise they wouldn't be tracked
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/src/  @src-owner\n/CODEOWNERS  @admins\n.git*  @admins\n",
+		Files: map[string]string{
+			"main.go":             "package main",
+			"src/lib.go":          "package src",
+			"build/out.bin":       "binary",
+			"debug.log":           "log",
+			"logs/keep.log":       "log",
+			"local/notes.txt":     "notes",
+			"gen/api.pb.go":       "package gen",
+			"vendor/lib/lib.go":   "package lib",
+			"web/dist/bundle.js":  "bundle",
+			"web/src/index.js":    "index",
+			"web/src/generated.x": "generated",
+		},
+		Changes: map[string]string{
+			".gitignore":         "build/\n",
+			"logs/.gitignore":    "!keep.log\n",
+			".gitattributes":     "gen/** linguist-generated\nvendor/** linguist-vendored=true\n*.x linguist-generated\n",
+			"web/.gitattributes": "dist/* linguist-generated\nsrc/generated.x -linguist-generated\n",
+		},
+	})
+	writeFile(t, filepath.Join(in.RepoDir, ".git", "info", "exclude"), "local/\n")
+	writeFile(t, filepath.Join(home, ".config", "git", "ignore"), "*.log\n")
+
+	sut := check.NewNotOwnedFile(check.NotOwnedFileConfig{
+		SkipGenerated: true,
+		IgnoreSources: []string{check.IgnoreSourceGitignore, check.IgnoreSourceInfoExclude, check.IgnoreSourceGlobal},
+	})
+
+	run := func(t *testing.T, backend string) api.Output {
+		t.Helper()
+		require.NoError(t, git.SetBackend(backend))
+		t.Cleanup(func() { _ = git.SetBackend(git.BackendAuto) })
+
+		out, err := sut.Check(context.Background(), in)
+		require.NoError(t, err)
+		return out
+	}
+
+	// when
+	execOut := run(t, git.BackendExec)
+	goGitOut := run(t, git.BackendGoGit)
+
+	// then
+	require.Len(t, execOut.Issues, 1)
+	assert.Equal(t, []string{"logs/keep.log", "main.go", "web/src/generated.x", "web/src/index.js"}, execOut.Issues[0].Paths)
+	assert.Equal(t, execOut, goGitOut)
+}
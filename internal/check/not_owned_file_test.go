@@ -0,0 +1,302 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/checktest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticTree lists a fixed set of files, like trees not managed by git.
+type staticTree []string
+
+func (t staticTree) ListFiles(string, ...string) ([]string, error) {
+	return t, nil
+}
+
+func TestNotOwnedFileWithTree(t *testing.T) {
+	givenTree := staticTree{"README.md", "docs/index.md", "src/main.go", "src/gen/api.go", "tools/build.sh"}
+
+	tests := map[string]struct {
+		codeownersInput string
+		givenConfig     check.NotOwnedFileConfig
+		expectedIssues  []api.Issue
+	}{
+		"Should report files not matched by any pattern": {
+			codeownersInput: `
+					/docs/     @docs-owner
+					*.go       @go-owner
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  "Found 2 not owned files (skipped patterns: \"\"):\n            * README.md\n            * tools/build.sh",
+					Paths:    []string{"README.md", "tools/build.sh"},
+				},
+			},
+		},
+		"Should respect skipped patterns and subdirectories": {
+			codeownersInput: `
+					*          @global-owner
+					/src/gen/  @gen-owner
+			`,
+			givenConfig: check.NotOwnedFileConfig{
+				SkipPatterns:   []string{"*"},
+				Subdirectories: []string{"./src"},
+			},
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  "Found 1 not owned files (skipped patterns: \"*\"):\n            * src/main.go",
+					Paths:    []string{"src/main.go"},
+				},
+			},
+		},
+		"Should suggest owners of nearby files": {
+			codeownersInput: `
+					/docs/index.md  @docs-owner
+					/src/main.go    @go-owner
+			`,
+			givenConfig: check.NotOwnedFileConfig{
+				SuggestOwners: true,
+			},
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message: "Found 3 not owned files (skipped patterns: \"\"):\n            * README.md\n            * src/gen/api.go\n            * tools/build.sh\n" +
+						"          Suggested entries:\n            * /src/gen/ @go-owner (owners of files in /src/)",
+					Fixes: []api.Fix{
+						{
+							Description: `Add "/src/gen/ @go-owner" entry`,
+							Edits:       []api.LineEdit{{LineNo: 3, NewText: "/src/gen/ @go-owner", Insert: true}},
+						},
+					},
+					Paths: []string{"README.md", "src/gen/api.go", "tools/build.sh"},
+				},
+			},
+		},
+		"Should not report issues when all files are owned": {
+			codeownersInput: `
+					*  @global-owner
+			`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			in := LoadInput(tc.codeownersInput)
+			in.Tree = givenTree
+			in.NonGit = true
+			sut := check.NewNotOwnedFile(tc.givenConfig)
+
+			// when
+			out, err := sut.Check(context.Background(), in)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
+
+func TestNotOwnedFileGitLabSections(t *testing.T) {
+	// given
+	in := LoadGitLabInput(t, `
+		# the section header is not a pattern, so it doesn't match the "B" file
+		[Backend] @org/backend
+		/src/
+
+		^[Docs]
+		*.md @org/writers
+	`)
+	in.Tree = staticTree{"B", "README.md", "src/main.go", "tools/build.sh"}
+	in.NonGit = true
+	sut := check.NewNotOwnedFile(check.NotOwnedFileConfig{SuggestOwners: true})
+
+	// when
+	out, err := sut.Check(context.Background(), in)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []api.Issue{
+		{
+			Severity: api.Error,
+			Message: "Found 2 not owned files (skipped patterns: \"\"):\n            * B\n            * tools/build.sh\n" +
+				"          Suggested entries:\n            * /B @org/writers (owners of sibling files)\n            * /tools/ @org/writers (owners of files in /)",
+			// entries are added to the default section, before the first section header
+			Fixes: []api.Fix{
+				{
+					Description: `Add "/B @org/writers" entry`,
+					Edits:       []api.LineEdit{{LineNo: 2, NewText: "/B @org/writers", Insert: true}},
+				},
+				{
+					Description: `Add "/tools/ @org/writers" entry`,
+					Edits:       []api.LineEdit{{LineNo: 2, NewText: "/tools/ @org/writers", Insert: true}},
+				},
+			},
+			Paths: []string{"B", "tools/build.sh"},
+		},
+	}, out.Issues)
+}
+
+func TestNotOwnedFileWithBaseline(t *testing.T) {
+	// given
+	in := LoadInput(`
+		*.go  @go-owner
+	`)
+	in.Tree = staticTree{"README.md", "main.go", "tools/build.sh", "tools/release.sh"}
+	in.NonGit = true
+	in.Baseline = staticBaseline{"[Experimental] Not Owned File Checker": {"README.md", "tools/build.sh"}}
+	sut := check.NewNotOwnedFile(check.NotOwnedFileConfig{})
+
+	// when
+	out, err := sut.Check(context.Background(), in)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []api.Issue{
+		{
+			Severity: api.Error,
+			Message:  "Found 1 not owned files (skipped patterns: \"\"):\n            * tools/release.sh",
+			Paths:    []string{"tools/release.sh"},
+		},
+		{
+			Severity: api.Error,
+			Message:  "Found 2 not owned files recorded in the baseline:\n            * README.md\n            * tools/build.sh",
+			Paths:    []string{"README.md", "tools/build.sh"},
+		},
+	}, out.Issues)
+}
+
+// staticBaseline holds known files listed by issues of checks.
+type staticBaseline map[string][]string
+
+func (b staticBaseline) HasPath(check, path string) bool {
+	for _, p := range b[check] {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNotOwnedFileWithTreeRejectsSkipGenerated(t *testing.T) {
+	// given
+	in := LoadInput(`
+		*  @global-owner
+	`)
+	in.Tree = staticTree{"README.md"}
+	in.NonGit = true
+	sut := check.NewNotOwnedFile(check.NotOwnedFileConfig{SkipGenerated: true})
+
+	// when
+	_, err := sut.Check(context.Background(), in)
+
+	// then
+	var cfgErr *api.ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "NOT_OWNED_CHECKER_SKIP_GENERATED", cfgErr.Field)
+}
+
+func TestNotOwnedFileIgnoreSources(t *testing.T) {
+	tests := map[string]struct {
+		givenSources []string
+		expNotOwned  []string
+	}{
+		"All sources": {
+			givenSources: []string{check.IgnoreSourceGitignore, check.IgnoreSourceInfoExclude, check.IgnoreSourceGlobal},
+			expNotOwned:  []string{"main.go"},
+		},
+		"Only repository .gitignore files": {
+			givenSources: []string{check.IgnoreSourceGitignore},
+			expNotOwned:  []string{"debug.log", "local/notes.txt", "main.go"},
+		},
+		"No sources": {
+			expNotOwned: []string{"build/out.bin", "debug.log", "local/notes.txt", "main.go"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+			// ignored files are committed before ignore rules are added, as otherwise they wouldn't be tracked
+			in := checktest.Repo(t, checktest.Fixture{
+				Codeowners: "/src/         @src-owner\n/CODEOWNERS   @admins\n/.gitignore   @admins\n",
+				Files: map[string]string{
+					"main.go":         "package main",
+					"src/lib.go":      "package src",
+					"build/out.bin":   "binary",
+					"debug.log":       "log",
+					"local/notes.txt": "notes",
+				},
+				Changes: map[string]string{".gitignore": "build/\n"},
+			})
+			writeFile(t, filepath.Join(in.RepoDir, ".git", "info", "exclude"), "local/\n")
+			writeFile(t, filepath.Join(home, ".config", "git", "ignore"), "*.log\n")
+
+			sut := check.NewNotOwnedFile(check.NotOwnedFileConfig{IgnoreSources: tc.givenSources})
+
+			// when
+			out, err := sut.Check(context.Background(), in)
+
+			// then
+			require.NoError(t, err)
+			require.Len(t, out.Issues, 1)
+			assert.Equal(t, fmt.Sprintf("Found %d not owned files (skipped patterns: \"\"):\n%s", len(tc.expNotOwned), sut.ListFormatFunc(tc.expNotOwned)), out.Issues[0].Message)
+		})
+	}
+}
+
+func TestNotOwnedFileDoesNotModifyRepository(t *testing.T) {
+	// given
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/src/  @src-owner\n",
+		Files: map[string]string{
+			"main.go":    "package main",
+			"src/lib.go": "package src",
+		},
+	})
+	// uncommitted changes and untracked files used to stop the check
+	writeFile(t, filepath.Join(in.RepoDir, "main.go"), "package main // modified")
+	writeFile(t, filepath.Join(in.RepoDir, "scratch.txt"), "untracked")
+
+	indexPath := filepath.Join(in.RepoDir, ".git", "index")
+	indexBefore, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+
+	sut := check.NewNotOwnedFile(check.NotOwnedFileConfig{})
+
+	// when
+	out, err := sut.Check(context.Background(), in)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, out.Issues, 1)
+	assert.Equal(t, "Found 2 not owned files (skipped patterns: \"\"):\n"+sut.ListFormatFunc([]string{"CODEOWNERS", "main.go"}), out.Issues[0].Message)
+
+	indexAfter, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	assert.Equal(t, indexBefore, indexAfter, "index must not be modified")
+	modified, err := os.ReadFile(filepath.Join(in.RepoDir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main // modified", string(modified))
+	assert.FileExists(t, filepath.Join(in.RepoDir, "scratch.txt"))
+}
+
+func writeFile(t *testing.T, p, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+}
@@ -0,0 +1,159 @@
+package check
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+const (
+	// suggestionHistoryDepth is the number of recent commits in which committers of not owned files are looked up.
+	suggestionHistoryDepth = 100
+	// suggestedCommitters is the maximum number of committers suggested as owners.
+	suggestedCommitters = 2
+)
+
+// ownerSuggestion is the most likely owner of not owned files covered by the pattern.
+type ownerSuggestion struct {
+	pattern string
+	owners  []string
+	// reason explains where the owners come from.
+	reason string
+}
+
+func (s ownerSuggestion) entry() string {
+	return strings.Join(append([]string{s.pattern}, s.owners...), " ")
+}
+
+// suggest returns owners of not owned files grouped by directories. Owners are taken from entries owning
+// files in the same directory, or the nearest parent directory. If there are no such entries, the top recent
+// committers of the directory are suggested. Files in the root directory are suggested one by one.
+func (c *NotOwnedFile) suggest(in api.Input, allFiles, notOwned []string) ([]ownerSuggestion, error) {
+	matcher, err := c.ownershipMatcher(in)
+	if err != nil {
+		return nil, err
+	}
+
+	// counts how many files in a directory are owned by a given owners list
+	dirOwners := map[string]map[string]int{}
+	for _, f := range allFiles {
+		e, found := matcher.Match(f)
+		if !found || len(e.Owners) == 0 {
+			continue
+		}
+		dir := path.Dir(f)
+		if dirOwners[dir] == nil {
+			dirOwners[dir] = map[string]int{}
+		}
+		dirOwners[dir][strings.Join(e.Owners, " ")]++
+	}
+
+	var out []ownerSuggestion
+	for _, group := range groupNotOwned(notOwned) {
+		s, found := nearestOwners(dirOwners, group.dir)
+		if !found && !in.NonGit {
+			committers, err := git.TopAuthors(in.RepoDir, group.gitPath(), suggestionHistoryDepth, suggestedCommitters)
+			if err != nil {
+				return nil, &api.GitError{Op: "looking up recent committers", Err: err}
+			}
+			s = ownerSuggestion{owners: committers, reason: "top recent committers"}
+			found = len(committers) > 0
+		}
+		if !found {
+			continue
+		}
+
+		s.pattern = group.pattern
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// nearestOwners returns the most common owners of files in a given directory or its nearest parent directory.
+func nearestOwners(dirOwners map[string]map[string]int, dir string) (ownerSuggestion, bool) {
+	for d := dir; ; d = path.Dir(d) {
+		if counts := dirOwners[d]; len(counts) > 0 {
+			reason := "owners of sibling files"
+			if d != dir {
+				reason = fmt.Sprintf("owners of files in %s", displayDir(d))
+			}
+			return ownerSuggestion{owners: strings.Fields(mostCommon(counts)), reason: reason}, true
+		}
+		if d == "." {
+			return ownerSuggestion{}, false
+		}
+	}
+}
+
+// mostCommon returns the key with the highest count, the first one in lexicographical order on a tie.
+func mostCommon(counts map[string]int) string {
+	var best string
+	for k, n := range counts {
+		if n > counts[best] || (n == counts[best] && k < best) {
+			best = k
+		}
+	}
+	return best
+}
+
+func displayDir(dir string) string {
+	if dir == "." {
+		return "/"
+	}
+	return "/" + dir + "/"
+}
+
+// notOwnedGroup is a set of not owned files covered by a single suggested pattern.
+type notOwnedGroup struct {
+	dir     string
+	pattern string
+}
+
+func (g notOwnedGroup) gitPath() string {
+	return strings.TrimPrefix(g.pattern, "/")
+}
+
+// groupNotOwned groups not owned files by their directories. Files in the root directory are not grouped,
+// as a pattern for the root directory would cover the whole repository.
+func groupNotOwned(notOwned []string) []notOwnedGroup {
+	seen := map[string]struct{}{}
+	var out []notOwnedGroup
+	for _, f := range notOwned {
+		g := notOwnedGroup{dir: path.Dir(f), pattern: "/" + f}
+		if g.dir != "." {
+			g.pattern = displayDir(g.dir)
+		}
+		if _, found := seen[g.pattern]; found {
+			continue
+		}
+		seen[g.pattern] = struct{}{}
+		out = append(out, g)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].pattern < out[j].pattern })
+	return out
+}
+
+// lastLineNo returns the line of the last entry, after which new entries are added to take precedence.
+// GitLab sections resolve owners independently, so entries are added to the end of the default section,
+// before the first section header. Otherwise, they could land in an optional section.
+func lastLineNo(entries []codeowners.Entry) uint64 {
+	var last uint64
+	for _, e := range entries {
+		if e.Section != nil {
+			if last == 0 {
+				return e.Section.LineNo - 1
+			}
+			return last
+		}
+		if e.LineNo > last {
+			last = e.LineNo
+		}
+	}
+	return last
+}
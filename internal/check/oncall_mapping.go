@@ -0,0 +1,62 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/oncall"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// OnCallMapping verifies that each team owner has an escalation policy in the on-call mapping,
+// so incidents in the owned files can be routed by the incident tooling. User and email owners
+// are not verified.
+type OnCallMapping struct {
+	mapping *oncall.Mapping
+}
+
+// NewOnCallMapping returns new instance of the OnCallMapping
+func NewOnCallMapping(mapping *oncall.Mapping) *OnCallMapping {
+	return &OnCallMapping{mapping: mapping}
+}
+
+// Check searches for team owners without the on-call mapping.
+func (c *OnCallMapping) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	checkedOwners := map[string]struct{}{}
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		for _, owner := range entry.Owners {
+			key := strings.ToLower(owner)
+			if _, checked := checkedOwners[key]; checked {
+				continue
+			}
+			checkedOwners[key] = struct{}{}
+
+			if !isGitHubTeam(owner) {
+				continue
+			}
+			if _, found := c.mapping.Lookup(owner); found {
+				continue
+			}
+
+			msg := fmt.Sprintf("Team %s has no on-call mapping. Incidents in the owned files cannot be routed to its escalation policy.", owner)
+			bldr.ReportIssue(msg, api.WithEntry(entry))
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// Name returns human-readable name of the validator
+func (OnCallMapping) Name() string {
+	return "[Experimental] On-Call Mapping Checker"
+}
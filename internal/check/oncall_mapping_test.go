@@ -0,0 +1,50 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/oncall"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnCallMapping(t *testing.T) {
+	// given
+	mapping, err := oncall.ParseMapping(strings.NewReader(`
+teams:
+  "@org/platform":
+    pagerduty: PABC123
+  "@org/docs":
+    opsgenie: docs-escalation
+`))
+	require.NoError(t, err)
+
+	sut := check.NewOnCallMapping(mapping)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  "Team @org/sre has no on-call mapping. Incidents in the owned files cannot be routed to its escalation policy.",
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), LoadInput(`
+		*          @org/Platform @alice
+		/infra/    @org/sre @org/platform
+		/docs/     @org/docs @org/SRE docs@example.com
+	`))
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
@@ -0,0 +1,82 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// OwnerCasing reports owners written with inconsistent casing across the CODEOWNERS file.
+// GitHub compares owners case-insensitively, but inconsistent spelling makes the file harder
+// to search and maintain. The first occurrence of a given owner is treated as the canonical spelling.
+type OwnerCasing struct{}
+
+// NewOwnerCasing returns new instance of the OwnerCasing
+func NewOwnerCasing() *OwnerCasing {
+	return &OwnerCasing{}
+}
+
+// Check searches for owners spelled differently than their first occurrence.
+func (c *OwnerCasing) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	type occurrence struct {
+		name   string
+		lineNo uint64
+	}
+	canonical := map[string]occurrence{}
+
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		fixed := entry
+		fixed.Owners = make([]string, len(entry.Owners))
+
+		var msgs []string
+		for idx, owner := range entry.Owners {
+			fixed.Owners[idx] = owner
+
+			key := strings.ToLower(owner)
+			first, found := canonical[key]
+			if !found {
+				canonical[key] = occurrence{name: owner, lineNo: entry.LineNo}
+				continue
+			}
+			if first.name == owner {
+				continue
+			}
+
+			fixed.Owners[idx] = first.name
+			msgs = append(msgs, fmt.Sprintf("Owner %q is written as %q in line %d. Use consistent casing.", owner, first.name, first.lineNo))
+		}
+
+		for _, msg := range msgs {
+			bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning), api.WithFix(api.Fix{
+				Description: "Correct owner casing",
+				Edits:       []api.LineEdit{{LineNo: entry.LineNo, NewText: entryText(fixed)}},
+			}))
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// entryText returns the entry in the CODEOWNERS line form.
+func entryText(e codeowners.Entry) string {
+	parts := append([]string{e.Pattern}, e.Owners...)
+	if e.Comment != "" {
+		parts = append(parts, "# "+e.Comment)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Name returns human-readable name of the validator
+func (OwnerCasing) Name() string {
+	return "[Experimental] Owner Casing Checker"
+}
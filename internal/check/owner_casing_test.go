@@ -0,0 +1,69 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnerCasing(t *testing.T) {
+	tests := map[string]struct {
+		codeownersInput string
+		expectedIssues  []api.Issue
+	}{
+		"Should report owners with inconsistent casing": {
+			codeownersInput: `
+					*         @org/Platform @Alice
+					/docs/    @org/platform @alice # docs team
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(3),
+					Message:  `Owner "@org/platform" is written as "@org/Platform" in line 2. Use consistent casing.`,
+					Fixes: []api.Fix{
+						{
+							Description: "Correct owner casing",
+							Edits:       []api.LineEdit{{LineNo: 3, NewText: "/docs/ @org/Platform @Alice # docs team"}},
+						},
+					},
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(3),
+					Message:  `Owner "@alice" is written as "@Alice" in line 2. Use consistent casing.`,
+					Fixes: []api.Fix{
+						{
+							Description: "Correct owner casing",
+							Edits:       []api.LineEdit{{LineNo: 3, NewText: "/docs/ @org/Platform @Alice # docs team"}},
+						},
+					},
+				},
+			},
+		},
+		"Should not report any issues with correct CODEOWNERS file": {
+			codeownersInput: FixtureValidCODEOWNERS,
+			expectedIssues:  nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewOwnerCasing()
+
+			// when
+			out, err := sut.Check(context.TODO(), LoadInput(tc.codeownersInput))
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package check
+
+import (
+	"net/mail"
+	"strings"
+)
+
+func isEmailAddress(s string) bool {
+	_, err := mail.ParseAddress(s)
+	return err == nil
+}
+
+func isGitHubTeam(s string) bool {
+	hasPrefix := strings.HasPrefix(s, "@")
+	containsSlash := strings.Contains(s, "/")
+	split := strings.SplitN(s, "/", 3) // 3 is enough to confirm that is invalid + will not overflow the buffer
+	return hasPrefix && containsSlash && len(split) == 2 && len(split[1]) > 0
+}
+
+func isGitHubUser(s string) bool {
+	return !strings.Contains(s, "/") && strings.HasPrefix(s, "@")
+}
@@ -0,0 +1,134 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultOwnershipSLAGracePeriod is the time new directories have to gain owners by default.
+const DefaultOwnershipSLAGracePeriod = 30 * 24 * time.Hour
+
+type OwnershipSLAConfig struct {
+	// GracePeriod is the time a new directory has to gain owners.
+	GracePeriod time.Duration
+}
+
+// OwnershipSLA enforces that new code is owned within the grace period, e.g. "new code must be owned within 30 days".
+// A directory is reported if it directly contains files not matched by any entry, and it was created before the grace
+// period. Files matched by entries without owners are deliberately unowned, so they are skipped.
+// The directory was created when the first file under it was added, according to the git history. Renames are
+// not followed, so a moved directory is as new as the commit which moved it.
+type OwnershipSLA struct {
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+// NewOwnershipSLA returns new instance of the OwnershipSLA
+func NewOwnershipSLA(cfg OwnershipSLAConfig) (*OwnershipSLA, error) {
+	if cfg.GracePeriod < 0 {
+		return nil, &api.ConfigError{Field: "OWNERSHIP_SLA_CHECKER_GRACE_PERIOD", Err: errors.New("grace period cannot be negative")}
+	}
+	if cfg.GracePeriod == 0 {
+		cfg.GracePeriod = DefaultOwnershipSLAGracePeriod
+	}
+
+	return &OwnershipSLA{
+		gracePeriod: cfg.GracePeriod,
+		now:         time.Now,
+	}, nil
+}
+
+// Check searches for directories with unowned files which are older than the grace period.
+func (c *OwnershipSLA) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	unowned := map[string]int{}
+	for _, f := range files {
+		if _, found := matcher.Match(f); !found {
+			unowned[path.Dir(f)]++
+		}
+	}
+	if len(unowned) == 0 {
+		return bldr.Output(), nil
+	}
+
+	added, err := git.FirstAdded(in.RepoDir)
+	if err != nil {
+		return api.Output{}, &api.GitError{Op: "reading history of added files", Err: err}
+	}
+	created := directoryCreationTimes(added)
+
+	dirs := make([]string, 0, len(unowned))
+	for d := range unowned {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	now := c.now()
+	for _, d := range dirs {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		// directories without history are not committed yet, so they are new
+		createdAt, found := created[d]
+		if !found {
+			continue
+		}
+		age := now.Sub(createdAt)
+		if age <= c.gracePeriod {
+			continue
+		}
+
+		msg := fmt.Sprintf("Directory %q was created on %s (%d day(s) ago), but %d of its file(s) still don't have owners after the %d day(s) grace period",
+			displayDir(d), createdAt.Format(expiresLayout), days(age), unowned[d], days(c.gracePeriod))
+		bldr.ReportIssue(msg)
+	}
+
+	return bldr.Output(), nil
+}
+
+// directoryCreationTimes returns the time at which the first file under each directory was added.
+// The repository root is indexed as ".".
+func directoryCreationTimes(added map[string]time.Time) map[string]time.Time {
+	out := map[string]time.Time{}
+	for f, at := range added {
+		for d := path.Dir(f); ; d = path.Dir(d) {
+			if cur, found := out[d]; !found || at.Before(cur) {
+				out[d] = at
+			}
+			if d == "." {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Name returns human-readable name of the validator
+func (OwnershipSLA) Name() string {
+	return "[Experimental] Ownership SLA Checker"
+}
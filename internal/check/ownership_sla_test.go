@@ -0,0 +1,112 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnershipSLA(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+
+	t.Setenv("GIT_COMMITTER_DATE", "2026-01-01T12:00:00Z")
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		"README.md":        "# repo",
+		"api/api.go":       "package api",
+		"legacy/a.go":      "package legacy",
+		"legacy/b.go":      "package legacy",
+		"owned/x.go":       "package owned",
+		"deliberate/y.txt": "no owners",
+	})
+	t.Setenv("GIT_COMMITTER_DATE", "2026-03-01T12:00:00Z")
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		"fresh/f.go": "package fresh",
+		// an old directory stays old when new files are added
+		"legacy/nested/c.go": "package nested",
+	})
+
+	codeowners := `
+		/api/        @org/api
+		/owned/      @org/owned
+		/deliberate/
+	`
+
+	tests := map[string]struct {
+		gracePeriod    time.Duration
+		expectedIssues []api.Issue
+	}{
+		"Should report directories older than the grace period": {
+			gracePeriod: 10 * 24 * time.Hour,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  `Directory "/" was created on 2026-01-01 (74 day(s) ago), but 1 of its file(s) still don't have owners after the 10 day(s) grace period`,
+				},
+				{
+					Severity: api.Error,
+					Message:  `Directory "/fresh/" was created on 2026-03-01 (15 day(s) ago), but 1 of its file(s) still don't have owners after the 10 day(s) grace period`,
+				},
+				{
+					Severity: api.Error,
+					Message:  `Directory "/legacy/" was created on 2026-01-01 (74 day(s) ago), but 2 of its file(s) still don't have owners after the 10 day(s) grace period`,
+				},
+				{
+					Severity: api.Error,
+					Message:  `Directory "/legacy/nested/" was created on 2026-03-01 (15 day(s) ago), but 1 of its file(s) still don't have owners after the 10 day(s) grace period`,
+				},
+			},
+		},
+		"Should skip directories within the grace period": {
+			gracePeriod: 30 * 24 * time.Hour,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  `Directory "/" was created on 2026-01-01 (74 day(s) ago), but 1 of its file(s) still don't have owners after the 30 day(s) grace period`,
+				},
+				{
+					Severity: api.Error,
+					Message:  `Directory "/legacy/" was created on 2026-01-01 (74 day(s) ago), but 2 of its file(s) still don't have owners after the 30 day(s) grace period`,
+				},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			sut, err := check.NewOwnershipSLA(check.OwnershipSLAConfig{GracePeriod: tc.gracePeriod})
+			require.NoError(t, err)
+			sut.SetNow(func() time.Time {
+				return time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)
+			})
+
+			in := LoadInput(codeowners)
+			in.RepoDir = repoDir
+
+			// when
+			out, err := sut.Check(context.Background(), in)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
+
+func TestOwnershipSLARejectsNegativeGracePeriod(t *testing.T) {
+	// when
+	_, err := check.NewOwnershipSLA(check.OwnershipSLAConfig{GracePeriod: -time.Hour})
+
+	// then
+	var cfgErr *api.ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "OWNERSHIP_SLA_CHECKER_GRACE_PERIOD", cfgErr.Field)
+	assert.EqualError(t, err, "grace period cannot be negative")
+}
@@ -1,3 +1,5 @@
+//go:build !minimal
+
 package check_test
 
 import (
@@ -5,9 +7,10 @@ import (
 	"errors"
 	"testing"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +27,14 @@ func TestRespectingCanceledContext(t *testing.T) {
 		check.NewFileExist(),
 		check.NewValidSyntax(),
 		check.NewNotOwnedFile(check.NotOwnedFileConfig{}),
+		check.NewOwnershipExpiration(check.OwnershipExpirationConfig{}),
+		check.NewPathHazards(),
+		check.NewExpensivePattern(),
+		check.NewOwnerCasing(),
+		check.NewProviderDialects([]codeowners.Provider{codeowners.GitHub}),
+		check.NewStaleTeams(nil),
+		check.NewActiveIdentity(check.ActiveIdentityConfig{}, nil),
+		must(check.NewProtectedApproval(check.ProtectedApprovalConfig{Repository: "org/repo", BaseRef: "main", PullRequestNumber: 1}, nil)),
 		must(check.NewValidOwner(&config.Config{OwnerCheckerRepository: "org/repo"}, nil, true)),
 	}
 
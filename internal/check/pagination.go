@@ -0,0 +1,96 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// pageConcurrency limits the number of pages of a single GitHub list fetched at the same time.
+const pageConcurrency = 8
+
+// pageFetcher returns a given page of a GitHub list. The page 0 is the first one.
+type pageFetcher[T any] func(ctx context.Context, page int) ([]T, *github.Response, error)
+
+// listAllPages returns items of all pages of a GitHub list, in the page order. The first page is fetched
+// to learn the number of pages from the Link header, and the remaining ones are fetched concurrently,
+// as lists of large organizations, e.g. with thousands of teams, have dozens of pages.
+// Pages are fetched one by one if the response doesn't report the last page.
+func listAllPages[T any](ctx context.Context, fetch pageFetcher[T]) ([]T, error) {
+	first, resp, err := fetch(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if resp.NextPage == 0 {
+		return first, nil
+	}
+	if resp.LastPage < resp.NextPage {
+		return listPagesSequentially(ctx, first, resp.NextPage, fetch)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, pageConcurrency)
+		pages    = make([][]T, resp.LastPage-resp.NextPage+1)
+	)
+	for i := range pages {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			items, _, err := fetch(ctx, resp.NextPage+i)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					// the list is incomplete anyway, so other pages are not needed
+					cancel()
+				}
+				return
+			}
+			pages[i] = items
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := first
+	for _, items := range pages {
+		out = append(out, items...)
+	}
+	return out, nil
+}
+
+func listPagesSequentially[T any](ctx context.Context, out []T, page int, fetch pageFetcher[T]) ([]T, error) {
+	for page != 0 {
+		items, resp, err := fetch(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		page = resp.NextPage
+	}
+	return out, nil
+}
@@ -0,0 +1,194 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// PathHazards reports patterns that confuse review routing depending on how files are accessed:
+//   - patterns that match repository paths only when the case is ignored. Such patterns
+//     work on case-insensitive filesystems, but are not matched by GitHub.
+//   - symlinks whose destinations fall under a different owner than the symlink itself.
+type PathHazards struct{}
+
+// NewPathHazards returns new instance of the PathHazards
+func NewPathHazards() *PathHazards {
+	return &PathHazards{}
+}
+
+// Check searches for case-sensitivity and symlink hazards in a given repository.
+func (c *PathHazards) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	if err := c.checkCaseSensitivity(ctx, &bldr, in, files); err != nil {
+		return api.Output{}, err
+	}
+
+	if err := c.checkSymlinks(ctx, &bldr, in, files); err != nil {
+		return api.Output{}, err
+	}
+
+	return bldr.Output(), nil
+}
+
+func (c *PathHazards) checkCaseSensitivity(ctx context.Context, bldr *api.OutputBuilder, in api.Input, files []string) error {
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return ctx.Err()
+		}
+
+		pattern, err := in.Pattern(entry.Pattern)
+		if err != nil {
+			return &api.MatchError{Pattern: entry.Pattern, Err: err}
+		}
+		if matchesAny(pattern, files) {
+			continue
+		}
+
+		foldPattern, err := codeowners.NewPattern(entry.Pattern, codeowners.WithIgnoreCase())
+		if err != nil {
+			return &api.MatchError{Pattern: entry.Pattern, Err: err}
+		}
+		for _, f := range files {
+			if foldPattern.Match(f) {
+				msg := fmt.Sprintf("Pattern %q does not match any file, but matches %q when the case is ignored. It behaves differently on case-insensitive filesystems.", entry.Pattern, f)
+				bldr.ReportIssue(msg, api.WithEntry(entry))
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *PathHazards) checkSymlinks(ctx context.Context, bldr *api.OutputBuilder, in api.Input, files []string) error {
+	matcher, err := in.Matcher()
+	if err != nil {
+		return &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	tracked := map[string]struct{}{}
+	for _, f := range files {
+		tracked[f] = struct{}{}
+	}
+
+	for _, f := range files {
+		if ctxutil.ShouldExit(ctx) {
+			return ctx.Err()
+		}
+
+		dest, isLink, err := c.symlinkDestination(in.RepoDir, f)
+		if err != nil {
+			return err
+		}
+		if !isLink {
+			continue
+		}
+
+		linkEntry, linkOwned := matcher.Match(f)
+		for _, destFile := range c.destinationFiles(dest, tracked, files) {
+			destEntry, destOwned := matcher.Match(destFile)
+			if ownersOf(linkEntry, linkOwned) == ownersOf(destEntry, destOwned) {
+				continue
+			}
+
+			msg := fmt.Sprintf("Symlink %q is owned by %s, but its destination %q is owned by %s. Changes to the destination are reviewed by different owners than the symlink suggests.",
+				f, ownersOf(linkEntry, linkOwned), destFile, ownersOf(destEntry, destOwned))
+
+			opts := []api.ReportIssueOpt{api.WithSeverity(api.Warning)}
+			if linkOwned {
+				opts = append(opts, api.WithEntry(linkEntry))
+			}
+			bldr.ReportIssue(msg, opts...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// symlinkDestination returns the symlink destination relative to the repository root.
+// Destinations outside the repository are ignored.
+func (c *PathHazards) symlinkDestination(repoDir, file string) (string, bool, error) {
+	fullPath := filepath.Join(repoDir, filepath.FromSlash(file))
+	fi, err := os.Lstat(fullPath)
+	switch {
+	case err == nil:
+	case os.IsNotExist(err): // tracked but removed from the working tree
+		return "", false, nil
+	default:
+		return "", false, err
+	}
+	if fi.Mode()&os.ModeSymlink == 0 {
+		return "", false, nil
+	}
+
+	target, err := os.Readlink(fullPath)
+	if err != nil {
+		return "", false, err
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(fullPath), target)
+	}
+
+	rel, err := codeowners.RelPath(repoDir, target)
+	if err != nil || rel == "" { // outside the repository or the repository root itself
+		return "", false, nil
+	}
+
+	return rel, true, nil
+}
+
+// destinationFiles returns tracked files pointed by the symlink destination, which can be either a file or a directory.
+func (c *PathHazards) destinationFiles(dest string, tracked map[string]struct{}, files []string) []string {
+	if _, found := tracked[dest]; found {
+		return []string{dest}
+	}
+
+	var out []string
+	prefix := path.Clean(dest) + "/"
+	for _, f := range files {
+		if strings.HasPrefix(f, prefix) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func matchesAny(p codeowners.Pattern, files []string) bool {
+	for _, f := range files {
+		if p.Match(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func ownersOf(e codeowners.Entry, found bool) string {
+	if !found || len(e.Owners) == 0 {
+		return "nobody"
+	}
+	return strings.Join(e.Owners, ", ")
+}
+
+// Name returns human-readable name of the validator
+func (PathHazards) Name() string {
+	return "[Experimental] Path Hazards Checker"
+}
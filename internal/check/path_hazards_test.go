@@ -0,0 +1,78 @@
+package check_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathHazards(t *testing.T) {
+	tests := map[string]struct {
+		codeownersInput string
+		expectedIssues  []api.Issue
+	}{
+		"Should report case-sensitivity and symlink hazards": {
+			codeownersInput: `
+					*         @global-owner
+					/docs/    @docs-owner
+					/src/     @src-owner
+			`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(3),
+					Message:  `Pattern "/docs/" does not match any file, but matches "Docs/README.md" when the case is ignored. It behaves differently on case-insensitive filesystems.`,
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  `Symlink "pkg" is owned by @global-owner, but its destination "src/main.go" is owned by @src-owner. Changes to the destination are reviewed by different owners than the symlink suggests.`,
+				},
+			},
+		},
+		"Should not report any issues when symlinks and destinations have the same owners": {
+			codeownersInput: `
+					*         @global-owner
+					/Docs/    @docs-owner
+					/src/     @src-owner
+					/pkg      @src-owner
+					/README.md @docs-owner
+			`,
+			expectedIssues: nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			repoDir := t.TempDir()
+			gitrepo.Init(t, repoDir)
+			require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "src"), 0o755))
+			require.NoError(t, os.Symlink("src", filepath.Join(repoDir, "pkg")))
+			require.NoError(t, os.Symlink(filepath.Join("Docs", "README.md"), filepath.Join(repoDir, "README.md")))
+			gitrepo.CommitFiles(t, repoDir, map[string]string{
+				"Docs/README.md": "# Docs",
+				"src/main.go":    "package main",
+			})
+
+			in := LoadInput(tc.codeownersInput)
+			in.RepoDir = repoDir
+
+			// when
+			out, err := check.NewPathHazards().Check(context.Background(), in)
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// approvedByAnnotation is the inline comment annotation that marks a protected entry, e.g.:
+//
+//	/infra/  @org/sre # approved-by: @org/eng-leads
+const approvedByAnnotation = "approved-by"
+
+type ProtectedApprovalConfig struct {
+	// Repository is in form 'owner/repository'.
+	Repository string
+	// BaseRef is the git reference against which the CODEOWNERS changes are computed.
+	BaseRef string
+	// PullRequestNumber is the number of the pull request which reviews are verified.
+	PullRequestNumber int
+	// VirtualOwners are owner groups which may be used in the `approved-by` metadata. An approval from
+	// any member of the group is enough.
+	VirtualOwners VirtualOwners
+}
+
+// ProtectedApproval verifies that changes to protected CODEOWNERS entries were approved
+// by the owners listed in the `approved-by` metadata. The approval must be given by someone
+// else than the pull request author (two-person integrity).
+type ProtectedApproval struct {
+	ghClient    *github.Client
+	orgName     string
+	orgRepoName string
+	baseRef     string
+	prNumber    int
+	virtual     VirtualOwners
+}
+
+// protectedChange represents a changed CODEOWNERS line that requires an approval.
+type protectedChange struct {
+	entry      codeowners.Entry
+	approvedBy string
+	removed    bool
+}
+
+// NewProtectedApproval returns new instance of the ProtectedApproval
+func NewProtectedApproval(cfg ProtectedApprovalConfig, ghClient *github.Client) (*ProtectedApproval, error) {
+	split := strings.Split(cfg.Repository, "/")
+	if len(split) != 2 {
+		return nil, &api.ConfigError{
+			Field: "OWNER_CHECKER_REPOSITORY",
+			Err:   errors.Errorf("Wrong repository name. Expected pattern 'owner/repository', got '%s'", cfg.Repository),
+		}
+	}
+	if cfg.PullRequestNumber <= 0 {
+		return nil, &api.ConfigError{Field: "APPROVAL_CHECKER_PULL_REQUEST_NUMBER", Err: errors.New("pull request number is required")}
+	}
+	if cfg.BaseRef == "" {
+		return nil, &api.ConfigError{Field: "APPROVAL_CHECKER_BASE_REF", Err: errors.New("base reference is required")}
+	}
+
+	return &ProtectedApproval{
+		ghClient:    ghClient,
+		orgName:     split[0],
+		orgRepoName: split[1],
+		baseRef:     cfg.BaseRef,
+		prNumber:    cfg.PullRequestNumber,
+		virtual:     cfg.VirtualOwners,
+	}, nil
+}
+
+// Check verifies that each added, modified, or removed protected entry was approved per its metadata.
+func (c *ProtectedApproval) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	changes, err := c.protectedChanges(in)
+	if err != nil {
+		return api.Output{}, err
+	}
+	if len(changes) == 0 {
+		return bldr.Output(), nil
+	}
+
+	approvers, err := c.approvers(ctx)
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	for _, change := range changes {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		approved, err := c.isApprovedBy(ctx, change.approvedBy, approvers)
+		if err != nil {
+			return api.Output{}, err
+		}
+		if approved {
+			continue
+		}
+
+		if change.removed {
+			msg := fmt.Sprintf("Removal of the protected entry %q (line %d in %s) requires approval from %s, but no such approval was found in pull request #%d",
+				change.entry.Pattern, change.entry.LineNo, c.baseRef, change.approvedBy, c.prNumber)
+			bldr.ReportIssue(msg)
+			continue
+		}
+
+		msg := fmt.Sprintf("Change of the protected entry %q requires approval from %s, but no such approval was found in pull request #%d",
+			change.entry.Pattern, change.approvedBy, c.prNumber)
+		bldr.ReportIssue(msg, api.WithEntry(change.entry))
+	}
+
+	return bldr.Output(), nil
+}
+
+// protectedChanges returns changed lines that are protected either in the current or in the base version.
+// Checking the base version ensures that the `approved-by` metadata itself cannot be removed without approval.
+func (c *ProtectedApproval) protectedChanges(in api.Input) ([]protectedChange, error) {
+	file, err := codeowners.FindCodeownersFile(in.RepoDir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := codeowners.RelPath(in.RepoDir, file)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := git.DiffFile(in.RepoDir, c.baseRef, rel)
+	if err != nil {
+		return nil, &api.GitError{Op: fmt.Sprintf("computing CODEOWNERS changes against %s", c.baseRef), Err: err}
+	}
+
+	byLineNo := map[uint64]codeowners.Entry{}
+	for _, entry := range in.CodeownersEntries {
+		byLineNo[entry.LineNo] = entry
+	}
+
+	var (
+		changes  []protectedChange
+		approved = map[string]struct{}{}
+	)
+	for _, line := range diff.Added {
+		entry, found := byLineNo[line.No]
+		if !found {
+			continue
+		}
+		approvedBy, found := entry.Annotation(approvedByAnnotation)
+		if !found {
+			continue
+		}
+		approved[entry.Pattern+approvedBy] = struct{}{}
+		changes = append(changes, protectedChange{entry: entry, approvedBy: approvedBy})
+	}
+
+	for _, line := range diff.Removed {
+		for _, entry := range codeowners.ParseCodeowners(strings.NewReader(line.Content)) {
+			approvedBy, found := entry.Annotation(approvedByAnnotation)
+			if !found {
+				continue
+			}
+			if _, reported := approved[entry.Pattern+approvedBy]; reported {
+				continue
+			}
+			entry.LineNo = line.No
+			changes = append(changes, protectedChange{entry: entry, approvedBy: approvedBy, removed: true})
+		}
+	}
+
+	return changes, nil
+}
+
+// approvers returns users whose latest review approved the pull request, excluding the pull request author.
+func (c *ProtectedApproval) approvers(ctx context.Context) ([]string, error) {
+	pr, _, err := c.ghClient.PullRequests.Get(ctx, c.orgName, c.orgRepoName, c.prNumber)
+	if err != nil {
+		return nil, newGitHubError(err, fmt.Sprintf("getting pull request #%d", c.prNumber))
+	}
+	author := pr.GetUser().GetLogin()
+
+	latestState := map[string]string{}
+	var order []string
+
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := c.ghClient.PullRequests.ListReviews(ctx, c.orgName, c.orgRepoName, c.prNumber, opt)
+		if err != nil {
+			return nil, newGitHubError(err, fmt.Sprintf("listing reviews of pull request #%d", c.prNumber))
+		}
+		for _, r := range reviews {
+			login := r.GetUser().GetLogin()
+			if _, seen := latestState[login]; !seen {
+				order = append(order, login)
+			}
+			// comments do not change the approval state
+			if r.GetState() != "COMMENTED" {
+				latestState[login] = r.GetState()
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	var out []string
+	for _, login := range order {
+		if latestState[login] != "APPROVED" || strings.EqualFold(login, author) {
+			continue
+		}
+		out = append(out, login)
+	}
+	return out, nil
+}
+
+func (c *ProtectedApproval) isApprovedBy(ctx context.Context, requiredOwner string, approvers []string) (bool, error) {
+	for _, member := range c.virtual.Members(requiredOwner) {
+		approved, err := c.isApprovedByOwner(ctx, member, approvers)
+		if err != nil || approved {
+			return approved, err
+		}
+	}
+	return false, nil
+}
+
+func (c *ProtectedApproval) isApprovedByOwner(ctx context.Context, requiredOwner string, approvers []string) (bool, error) {
+	for _, login := range approvers {
+		switch {
+		case isGitHubTeam(requiredOwner):
+			isMember, err := c.isTeamMember(ctx, requiredOwner, login)
+			if err != nil {
+				return false, err
+			}
+			if isMember {
+				return true, nil
+			}
+		case strings.EqualFold(strings.TrimPrefix(requiredOwner, "@"), login):
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *ProtectedApproval) isTeamMember(ctx context.Context, team, login string) (bool, error) {
+	parts := strings.SplitN(strings.TrimPrefix(team, "@"), "/", 2)
+	membership, _, err := c.ghClient.Teams.GetTeamMembershipBySlug(ctx, parts[0], parts[1], login)
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, newGitHubError(err, fmt.Sprintf("checking if %q is a member of %q", login, team))
+	}
+
+	return membership.GetState() == "active", nil
+}
+
+// Name returns human-readable name of the validator
+func (ProtectedApproval) Name() string {
+	return "[Experimental] Protected Entries Approval Checker"
+}
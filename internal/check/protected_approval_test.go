@@ -0,0 +1,124 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedApproval(t *testing.T) {
+	const (
+		baseCodeowners = `*          @global-owner1
+/infra/    @org/sre # approved-by: @org/eng-leads
+/secrets/  @org/sec # approved-by: @alice
+`
+		headCodeowners = `*          @global-owner1
+/infra/    @org/platform # approved-by: @org/eng-leads
+/docs/     @doctocat
+`
+	)
+
+	tests := map[string]struct {
+		reviews        string
+		expectedIssues []api.Issue
+	}{
+		"Should accept changes approved by required owners": {
+			reviews: `[
+				{"user": {"login": "bob"}, "state": "APPROVED"},
+				{"user": {"login": "alice"}, "state": "APPROVED"}
+			]`,
+		},
+		"Should ignore approval given by the pull request author": {
+			reviews: `[
+				{"user": {"login": "bob"}, "state": "APPROVED"},
+				{"user": {"login": "author"}, "state": "APPROVED"}
+			]`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					Message:  `Removal of the protected entry "/secrets/" (line 3 in base) requires approval from @alice, but no such approval was found in pull request #1`,
+				},
+			},
+		},
+		"Should report changes without required approvals": {
+			reviews: `[
+				{"user": {"login": "alice"}, "state": "APPROVED"},
+				{"user": {"login": "alice"}, "state": "CHANGES_REQUESTED"},
+				{"user": {"login": "alice"}, "state": "COMMENTED"},
+				{"user": {"login": "carol"}, "state": "APPROVED"}
+			]`,
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  `Change of the protected entry "/infra/" requires approval from @org/eng-leads, but no such approval was found in pull request #1`,
+				},
+				{
+					Severity: api.Error,
+					Message:  `Removal of the protected entry "/secrets/" (line 3 in base) requires approval from @alice, but no such approval was found in pull request #1`,
+				},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			repoDir := t.TempDir()
+			gitrepo.Init(t, repoDir)
+			gitrepo.CommitFiles(t, repoDir, map[string]string{".github/CODEOWNERS": baseCodeowners})
+			gitrepo.Run(t, repoDir, "tag", "base")
+			gitrepo.CommitFiles(t, repoDir, map[string]string{".github/CODEOWNERS": headCodeowners})
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repos/org/repo/pulls/1", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"number": 1, "user": {"login": "author"}}`)
+			})
+			mux.HandleFunc("/repos/org/repo/pulls/1/reviews", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, tc.reviews)
+			})
+			mux.HandleFunc("/orgs/org/teams/eng-leads/memberships/bob", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"state": "active"}`)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			ghClient := github.NewClient(nil)
+			ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+			sut, err := check.NewProtectedApproval(check.ProtectedApprovalConfig{
+				Repository:        "org/repo",
+				BaseRef:           "base",
+				PullRequestNumber: 1,
+			}, ghClient)
+			require.NoError(t, err)
+
+			entries, err := codeowners.NewFromPath(repoDir)
+			require.NoError(t, err)
+
+			// when
+			out, err := sut.Check(context.Background(), api.Input{
+				RepoDir:           repoDir,
+				CodeownersEntries: entries,
+			})
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
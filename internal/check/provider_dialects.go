@@ -0,0 +1,167 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// gitlabSectionRegexp matches GitLab section headers, such as `[Docs]`, `^[Optional docs]`, or `[Docs][2] @docs-team`.
+var gitlabSectionRegexp = regexp.MustCompile(`^\^?\[[^\]]+\](\[\d+\])?`)
+
+// ProviderDialects reports constructs which are valid in one provider dialect but ignored by another.
+// It allows repositories that are mirrored between providers to keep a single CODEOWNERS file
+// that behaves the same way on all of them.
+type ProviderDialects struct {
+	providers []codeowners.Provider
+}
+
+// dialectConstruct is a CODEOWNERS construct supported only by some providers.
+type dialectConstruct struct {
+	description string
+	supportedBy []codeowners.Provider
+}
+
+// NewProviderDialects returns new instance of the ProviderDialects
+func NewProviderDialects(providers []codeowners.Provider) *ProviderDialects {
+	return &ProviderDialects{providers: providers}
+}
+
+// Check searches for constructs not supported by all configured providers.
+func (c *ProviderDialects) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	sections := map[uint64]struct{}{}
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		// in the GitLab flavor, section headers are not entries, so each header is reported once,
+		// in its own line, when the first entry of the section is visited
+		if s := entry.Section; s != nil {
+			if _, found := sections[s.LineNo]; !found {
+				sections[s.LineNo] = struct{}{}
+				header := dialectConstruct{
+					description: fmt.Sprintf("Section header %q", sectionHeader(s)),
+					supportedBy: []codeowners.Provider{codeowners.GitLab},
+				}
+				for _, construct := range append([]dialectConstruct{header}, ownerConstructs(s.Owners)...) {
+					c.report(&bldr, s.LineNo, construct)
+				}
+			}
+		}
+
+		for _, construct := range c.constructs(entry) {
+			c.report(&bldr, entry.LineNo, construct)
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// report reports a construct of a given line if it's not supported by all configured providers.
+func (c *ProviderDialects) report(bldr *api.OutputBuilder, lineNo uint64, construct dialectConstruct) {
+	var supported, ignored []codeowners.Provider
+	for _, p := range c.providers {
+		if containsProvider(construct.supportedBy, p) {
+			supported = append(supported, p)
+		} else {
+			ignored = append(ignored, p)
+		}
+	}
+	if len(ignored) == 0 {
+		return
+	}
+
+	// if at least one provider supports it, it is a valid construct that behaves differently across providers
+	severity := api.Error
+	if len(supported) > 0 {
+		severity = api.Warning
+	}
+
+	msg := fmt.Sprintf("%s is supported only by %s and is ignored by %s",
+		construct.description, joinProviders(construct.supportedBy), joinProviders(ignored))
+	bldr.ReportIssue(msg, api.WithLineNo(lineNo), api.WithSeverity(severity))
+}
+
+// sectionHeader returns the header of a given section without its default owners, e.g. `^[Docs][2]`.
+func sectionHeader(s *codeowners.Section) string {
+	header := "[" + s.Name + "]"
+	if s.Optional {
+		header = "^" + header
+	}
+	if s.Approvals > 0 {
+		header += fmt.Sprintf("[%d]", s.Approvals)
+	}
+	return header
+}
+
+func (c *ProviderDialects) constructs(entry codeowners.Entry) []dialectConstruct {
+	var out []dialectConstruct
+
+	line := strings.Join(append([]string{entry.Pattern}, entry.Owners...), " ")
+	owners := entry.Owners
+	if header := gitlabSectionRegexp.FindString(line); header != "" {
+		out = append(out, dialectConstruct{
+			description: fmt.Sprintf("Section header %q", header),
+			supportedBy: []codeowners.Provider{codeowners.GitLab},
+		})
+		// section name may contain spaces, only default section owners are left
+		owners = strings.Fields(strings.TrimPrefix(line, header))
+	}
+	// default owners are reported with the section header
+	if entry.DefaultOwners {
+		owners = nil
+	}
+
+	return append(out, ownerConstructs(owners)...)
+}
+
+// ownerConstructs returns constructs of given owners which are supported only by some providers.
+func ownerConstructs(owners []string) []dialectConstruct {
+	var out []dialectConstruct
+	for _, owner := range owners {
+		switch {
+		case strings.HasPrefix(owner, "@@"):
+			out = append(out, dialectConstruct{
+				description: fmt.Sprintf("Role owner %q", owner),
+				supportedBy: []codeowners.Provider{codeowners.GitLab},
+			})
+		case strings.HasPrefix(owner, "@") && strings.Count(owner, "/") > 1:
+			out = append(out, dialectConstruct{
+				description: fmt.Sprintf("Nested group owner %q", owner),
+				supportedBy: []codeowners.Provider{codeowners.GitLab},
+			})
+		}
+	}
+
+	return out
+}
+
+func containsProvider(providers []codeowners.Provider, p codeowners.Provider) bool {
+	for _, item := range providers {
+		if item == p {
+			return true
+		}
+	}
+	return false
+}
+
+func joinProviders(providers []codeowners.Provider) string {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, string(p))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Name returns human-readable name of the validator
+func (ProviderDialects) Name() string {
+	return "Provider Dialects Checker"
+}
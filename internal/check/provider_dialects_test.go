@@ -0,0 +1,122 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderDialects(t *testing.T) {
+	tests := map[string]struct {
+		codeownersInput string
+		providers       []codeowners.Provider
+		expectedIssues  []api.Issue
+	}{
+		"Should report GitLab constructs ignored by GitHub": {
+			codeownersInput: `
+					*                      @org/team
+					[Documentation Team][2] @org/docs/writers
+					docs/                  @@maintainer
+			`,
+			providers: []codeowners.Provider{codeowners.GitHub, codeowners.GitLab},
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(3),
+					Message:  `Section header "[Documentation Team][2]" is supported only by gitlab and is ignored by github`,
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(3),
+					Message:  `Nested group owner "@org/docs/writers" is supported only by gitlab and is ignored by github`,
+				},
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(4),
+					Message:  `Role owner "@@maintainer" is supported only by gitlab and is ignored by github`,
+				},
+			},
+		},
+		"Should report GitLab constructs as errors if only GitHub is used": {
+			codeownersInput: `
+					^[Optional]
+			`,
+			providers: []codeowners.Provider{codeowners.GitHub},
+			expectedIssues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  `Section header "^[Optional]" is supported only by gitlab and is ignored by github`,
+				},
+			},
+		},
+		"Should not report GitLab constructs if only GitLab is used": {
+			codeownersInput: `
+					[Docs] @org/docs/writers
+					docs/  @@maintainer
+			`,
+			providers:      []codeowners.Provider{codeowners.GitLab},
+			expectedIssues: nil,
+		},
+		"Should not report any issues with correct CODEOWNERS file": {
+			codeownersInput: FixtureValidCODEOWNERS,
+			providers:       []codeowners.Provider{codeowners.GitHub, codeowners.GitLab},
+			expectedIssues:  nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewProviderDialects(tc.providers)
+
+			// when
+			out, err := sut.Check(context.TODO(), LoadInput(tc.codeownersInput))
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
+
+func TestProviderDialectsGitLabSections(t *testing.T) {
+	// given
+	in := LoadGitLabInput(t, `
+		*                       @org/team
+		[Documentation Team][2] @org/docs/writers
+		docs/
+		guides/                 @@maintainer
+	`)
+	sut := check.NewProviderDialects([]codeowners.Provider{codeowners.GitHub, codeowners.GitLab})
+
+	// when
+	out, err := sut.Check(context.TODO(), in)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []api.Issue{
+		{
+			Severity: api.Warning,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  `Section header "[Documentation Team][2]" is supported only by gitlab and is ignored by github`,
+		},
+		{
+			Severity: api.Warning,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  `Nested group owner "@org/docs/writers" is supported only by gitlab and is ignored by github`,
+		},
+		{
+			Severity: api.Warning,
+			LineNo:   ptr.Uint64Ptr(5),
+			Message:  `Role owner "@@maintainer" is supported only by gitlab and is ignored by github`,
+		},
+	}, out.Issues)
+}
@@ -0,0 +1,125 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// ProviderOwner validates owners with a provider.Provider, so the same check works for any forge.
+// It's used instead of the ValidOwner for providers other than GitHub. Owners must exist in the provider,
+// and teams must have at least one member, as otherwise nobody can approve changes of the files they own.
+// If the provider implements the provider.AccessChecker, owners must also have access to the repository.
+type ProviderOwner struct {
+	provider             provider.Provider
+	repository           string
+	ignOwners            map[string]struct{}
+	allowUnownedPatterns bool
+	ownersMustBeTeams    bool
+}
+
+// NewProviderOwner returns new instance of the ProviderOwner
+func NewProviderOwner(cfg *config.Config, p provider.Provider) *ProviderOwner {
+	ignOwners := map[string]struct{}{}
+	for _, n := range cfg.OwnerCheckerIgnoredOwners {
+		ignOwners[n] = struct{}{}
+	}
+
+	return &ProviderOwner{
+		provider:             p,
+		repository:           cfg.OwnerCheckerRepository,
+		ignOwners:            ignOwners,
+		allowUnownedPatterns: cfg.OwnerCheckerAllowUnownedPatterns,
+		ownersMustBeTeams:    cfg.OwnerCheckerOwnersMustBeTeams,
+	}
+}
+
+// Check resolves each owner once and reports the ones which don't exist. Problems of default owners
+// of a GitLab section are reported once, in the section header line.
+func (v *ProviderOwner) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	problems := map[string]string{}
+	reported := map[string]struct{}{}
+	for _, entry := range in.CodeownersEntries {
+		if len(entry.Owners) == 0 && !v.allowUnownedPatterns {
+			bldr.ReportIssue("Missing owner, at least one owner is required", api.WithEntry(entry), api.WithSeverity(api.Warning))
+			continue
+		}
+
+		for _, name := range entry.Owners {
+			if ctxutil.ShouldExit(ctx) {
+				return api.Output{}, ctx.Err()
+			}
+			if _, ignored := v.ignOwners[name]; ignored {
+				continue
+			}
+
+			problem, checked := problems[name]
+			if !checked {
+				var err error
+				if problem, err = v.validate(ctx, name); err != nil {
+					return api.Output{}, err
+				}
+				problems[name] = problem
+			}
+			if problem == "" {
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", entry.OwnersLineNo(), name)
+			if _, found := reported[key]; !found {
+				reported[key] = struct{}{}
+				bldr.ReportIssue(problem, api.WithOwnersOf(entry))
+			}
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// validate returns the problem of a given owner, or an error if the owner cannot be resolved.
+func (v *ProviderOwner) validate(ctx context.Context, name string) (string, error) {
+	owner, err := v.provider.ResolveOwner(ctx, name)
+	switch {
+	case provider.IsNotFound(err):
+		return fmt.Sprintf("Owner %q does not exist in %s", name, v.provider.Name()), nil
+	case err != nil:
+		return "", err
+	}
+
+	if v.ownersMustBeTeams && owner.Kind != provider.Team {
+		return fmt.Sprintf("Only team owners allowed and %q is not a team", name), nil
+	}
+	if checker, ok := v.provider.(provider.AccessChecker); ok && v.repository != "" {
+		hasAccess, err := checker.HasAccess(ctx, v.repository, owner)
+		if err != nil {
+			return "", err
+		}
+		if !hasAccess {
+			return fmt.Sprintf("Owner %q does not have access to the repository %q which allows approving changes", name, v.repository), nil
+		}
+	}
+	if owner.Kind != provider.Team {
+		return "", nil
+	}
+
+	members, err := v.provider.ListTeamMembers(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(members) == 0 {
+		return fmt.Sprintf("Team %q does not have any members, so nobody can approve changes of the files it owns", name), nil
+	}
+	return "", nil
+}
+
+// Name returns human-readable name of the validator
+func (ProviderOwner) Name() string {
+	return "Valid Owner Checker"
+}
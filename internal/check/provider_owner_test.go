@@ -0,0 +1,140 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderOwnerCheck(t *testing.T) {
+	tests := map[string]struct {
+		codeowners        string
+		ownersMustBeTeams bool
+		issue             *api.Issue
+	}{
+		"Should accept existing owners": {
+			codeowners: `* @alice @org/docs alice@example.com @@maintainers`,
+		},
+		"Should report missing owner": {
+			codeowners: `* @alice @bob`,
+			issue: &api.Issue{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(1),
+				Message:  `Owner "@bob" does not exist in GitLab`,
+			},
+		},
+		"Should report team without members": {
+			codeowners: `* @org/empty`,
+			issue: &api.Issue{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(1),
+				Message:  `Team "@org/empty" does not have any members, so nobody can approve changes of the files it owns`,
+			},
+		},
+		"Should report user if owners must be teams": {
+			codeowners:        `* @alice`,
+			ownersMustBeTeams: true,
+			issue: &api.Issue{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(1),
+				Message:  `Only team owners allowed and "@alice" is not a team`,
+			},
+		},
+		"Should report owner without access to the repository": {
+			codeowners: `* @alice @carol`,
+			issue: &api.Issue{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(1),
+				Message:  `Owner "@carol" does not have access to the repository "org/repo" which allows approving changes`,
+			},
+		},
+		"Should skip ignored owners": {
+			codeowners: `* @ignored`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewProviderOwner(&config.Config{
+				OwnerCheckerRepository:        "org/repo",
+				OwnerCheckerIgnoredOwners:     []string{"@ignored"},
+				OwnerCheckerOwnersMustBeTeams: tc.ownersMustBeTeams,
+			}, providerMock())
+
+			// when
+			out, err := sut.Check(context.Background(), LoadInput(tc.codeowners))
+
+			// then
+			require.NoError(t, err)
+			assertIssue(t, tc.issue, out.Issues)
+		})
+	}
+}
+
+func TestProviderOwnerCheckReturnsProviderErrors(t *testing.T) {
+	// given
+	sut := check.NewProviderOwner(&config.Config{}, &provider.Mock{Err: errors.New("connection refused")})
+
+	// when
+	_, err := sut.Check(context.Background(), LoadInput(`* @alice`))
+
+	// then
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestProviderOwnerCheckGitLabSections(t *testing.T) {
+	// given
+	in := LoadGitLabInput(t, `
+		* @alice
+
+		[Docs] @org/empty @org/docs
+		/docs/
+		/guides/
+		/api/docs/ @bob
+	`)
+	sut := check.NewProviderOwner(&config.Config{}, providerMock())
+
+	// when
+	out, err := sut.Check(context.Background(), in)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []api.Issue{
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(4),
+			Message:  `Team "@org/empty" does not have any members, so nobody can approve changes of the files it owns`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(7),
+			Message:  `Owner "@bob" does not exist in GitLab`,
+		},
+	}, out.Issues)
+}
+
+// providerMock knows the @alice user, the @carol user without access to repositories, the @org/docs team
+// with members, the @org/empty team without members, and the @@maintainers role.
+func providerMock() *provider.Mock {
+	return &provider.Mock{
+		ProviderName: "GitLab",
+		Users:        []string{"@alice", "@carol"},
+		Outsiders:    []string{"@carol"},
+		Teams: map[string][]string{
+			"@org/docs":  {"alice"},
+			"@org/empty": nil,
+		},
+		Roles: []string{"@@maintainers"},
+	}
+}
@@ -0,0 +1,124 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// StaleTeams reports team owners that indicate governance debt:
+//   - teams which have access only to archived repositories, so their only purpose was an archived project,
+//   - teams without maintainers, so nobody is responsible for managing their membership.
+type StaleTeams struct {
+	ghClient *github.Client
+}
+
+// NewStaleTeams returns new instance of the StaleTeams
+func NewStaleTeams(ghClient *github.Client) *StaleTeams {
+	return &StaleTeams{ghClient: ghClient}
+}
+
+// Check searches for stale teams using the GitHub teams API.
+func (c *StaleTeams) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	checkedTeams := map[string]struct{}{}
+	for _, entry := range in.CodeownersEntries {
+		for _, owner := range entry.Owners {
+			if ctxutil.ShouldExit(ctx) {
+				return api.Output{}, ctx.Err()
+			}
+
+			if !isGitHubTeam(owner) {
+				continue
+			}
+			key := strings.ToLower(owner)
+			if _, checked := checkedTeams[key]; checked {
+				continue
+			}
+			checkedTeams[key] = struct{}{}
+
+			parts := strings.SplitN(strings.TrimPrefix(owner, "@"), "/", 2)
+			org, slug := parts[0], parts[1]
+
+			onlyArchived, err := c.hasOnlyArchivedRepos(ctx, org, slug)
+			switch {
+			case isNotFound(err):
+				continue // reported by the 'owners' checker
+			case err != nil:
+				return api.Output{}, newGitHubError(err, fmt.Sprintf("listing repositories of team %s", owner))
+			case onlyArchived:
+				msg := fmt.Sprintf("Team %s has access only to archived repositories. Consider transferring the ownership to an active team.", owner)
+				bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning))
+			}
+
+			hasMaintainers, err := c.hasMaintainers(ctx, org, slug)
+			switch {
+			case isNotFound(err):
+				continue
+			case err != nil:
+				return api.Output{}, newGitHubError(err, fmt.Sprintf("listing maintainers of team %s", owner))
+			case !hasMaintainers:
+				msg := fmt.Sprintf("Team %s has no maintainers. Nobody is responsible for managing its membership.", owner)
+				bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning))
+			}
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+func (c *StaleTeams) hasOnlyArchivedRepos(ctx context.Context, org, slug string) (bool, error) {
+	var (
+		opt   = &github.ListOptions{PerPage: 100}
+		repos int
+	)
+	for {
+		page, resp, err := c.ghClient.Teams.ListTeamReposBySlug(ctx, org, slug, opt)
+		if err != nil {
+			return false, err
+		}
+		for _, r := range page {
+			if !r.GetArchived() {
+				return false, nil
+			}
+			repos++
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return repos > 0, nil
+}
+
+func (c *StaleTeams) hasMaintainers(ctx context.Context, org, slug string) (bool, error) {
+	maintainers, _, err := c.ghClient.Teams.ListTeamMembersBySlug(ctx, org, slug, &github.TeamListTeamMembersOptions{
+		Role:        "maintainer",
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(maintainers) > 0, nil
+}
+
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+// Name returns human-readable name of the validator
+func (StaleTeams) Name() string {
+	return "[Experimental] Stale Teams Checker"
+}
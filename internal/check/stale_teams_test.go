@@ -0,0 +1,72 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleTeams(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/org/teams/active/repos", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"name": "old", "archived": true}, {"name": "repo", "archived": false}]`)
+	})
+	mux.HandleFunc("/orgs/org/teams/active/members", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "maintainer", r.URL.Query().Get("role"))
+		fmt.Fprint(w, `[{"login": "alice"}]`)
+	})
+	mux.HandleFunc("/orgs/org/teams/legacy/repos", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"name": "old", "archived": true}]`)
+	})
+	mux.HandleFunc("/orgs/org/teams/legacy/members", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/orgs/org/teams/missing/repos", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	sut := check.NewStaleTeams(ghClient)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Warning,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  "Team @org/legacy has access only to archived repositories. Consider transferring the ownership to an active team.",
+		},
+		{
+			Severity: api.Warning,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  "Team @org/legacy has no maintainers. Nobody is responsible for managing its membership.",
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), LoadInput(`
+		*       @org/active @alice
+		/old/   @org/legacy @org/missing
+		/docs/  @org/Legacy
+	`))
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
@@ -0,0 +1,149 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+type StewardshipConfig struct {
+	// Team is the stewardship team which must own the binary and large files.
+	Team string
+	// SizeThreshold is the size in bytes above which files must be owned by the team. Zero disables the size check.
+	SizeThreshold int64
+	// Extensions of files which must be owned by the team, e.g. `.pb.go` or `.jar`.
+	Extensions []string
+	// VirtualOwners are owner groups which members may own the files in place of the team.
+	VirtualOwners VirtualOwners
+}
+
+// Stewardship verifies that binary and large files are owned by the designated stewardship team.
+// Such files are hard to review, so their changes should be approved by people who understand
+// their impact, e.g. on the repository size. A file is a subject of stewardship if:
+//   - it's larger than the configured threshold,
+//   - it has one of the configured extensions,
+//   - it's marked as `binary` or stored in Git LFS (`filter=lfs`) in .gitattributes.
+type Stewardship struct {
+	team          string
+	sizeThreshold int64
+	extensions    []string
+	virtual       VirtualOwners
+}
+
+// NewStewardship returns new instance of the Stewardship
+func NewStewardship(cfg StewardshipConfig) (*Stewardship, error) {
+	if cfg.Team == "" {
+		return nil, &api.ConfigError{Field: "STEWARDSHIP_CHECKER_TEAM", Err: errors.New("stewardship team is required")}
+	}
+
+	exts := make([]string, 0, len(cfg.Extensions))
+	for _, ext := range cfg.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, strings.ToLower(ext))
+	}
+
+	return &Stewardship{
+		team:          cfg.Team,
+		sizeThreshold: cfg.SizeThreshold,
+		extensions:    exts,
+		virtual:       cfg.VirtualOwners,
+	}, nil
+}
+
+// Check searches for binary and large files which are not owned by the stewardship team.
+func (c *Stewardship) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if ctxutil.ShouldExit(ctx) {
+		return api.Output{}, ctx.Err()
+	}
+
+	var bldr api.OutputBuilder
+
+	files, err := in.Files()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	attrs, err := git.Attributes(in.RepoDir, files, "binary", "filter")
+	if err != nil {
+		return api.Output{}, &api.GitError{Op: "reading gitattributes", Err: err}
+	}
+
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, &api.MatchError{Pattern: "CODEOWNERS patterns", Err: err}
+	}
+
+	for _, f := range files {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		reason, err := c.stewardshipReason(in.RepoDir, f, attrs[f])
+		if err != nil {
+			return api.Output{}, err
+		}
+		if reason == "" {
+			continue
+		}
+
+		entry, found := matcher.Match(f)
+		if found && c.virtual.Covers(entry.Owners, c.team) {
+			continue
+		}
+
+		msg := fmt.Sprintf("File %q %s, so it must be owned by %s, but it is owned by %s", f, reason, c.team, ownersOf(entry, found))
+		var opts []api.ReportIssueOpt
+		if found {
+			opts = append(opts, api.WithEntry(entry))
+		}
+		bldr.ReportIssue(msg, opts...)
+	}
+
+	return bldr.Output(), nil
+}
+
+// stewardshipReason returns why a given file must be owned by the stewardship team, or empty string if it mustn't.
+func (c *Stewardship) stewardshipReason(repoDir, file string, attrs map[string]string) (string, error) {
+	lower := strings.ToLower(file)
+	for _, ext := range c.extensions {
+		if strings.HasSuffix(lower, ext) {
+			return fmt.Sprintf("has the %s extension", ext), nil
+		}
+	}
+
+	switch {
+	case attrs["filter"] == "lfs":
+		return "is stored in Git LFS", nil
+	case attrs["binary"] == "set":
+		return "is marked as binary in .gitattributes", nil
+	}
+
+	if c.sizeThreshold <= 0 {
+		return "", nil
+	}
+	fi, err := os.Lstat(filepath.Join(repoDir, filepath.FromSlash(file)))
+	switch {
+	case os.IsNotExist(err): // tracked but removed from the working tree
+		return "", nil
+	case err != nil:
+		return "", err
+	case fi.Size() > c.sizeThreshold:
+		return fmt.Sprintf("has %d bytes, which is more than the %d bytes threshold", fi.Size(), c.sizeThreshold), nil
+	}
+	return "", nil
+}
+
+// Name returns human-readable name of the validator
+func (Stewardship) Name() string {
+	return "[Experimental] Stewardship Checker"
+}
@@ -0,0 +1,89 @@
+package check_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStewardship(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	gitrepo.Init(t, repoDir)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		".gitattributes":             "*.png binary\n*.safetensors filter=lfs diff=lfs merge=lfs -text\n",
+		"main.go":                    "package main",
+		"api/v1/api.pb.go":           "package v1",
+		"libs/vendor.JAR":            "jar",
+		"assets/logo.png":            "png",
+		"models/weights.safetensors": "weights",
+		"data/dump.sql":              strings.Repeat("x", 101),
+		"artifacts/big.bin":          strings.Repeat("x", 200),
+	})
+
+	sut, err := check.NewStewardship(check.StewardshipConfig{
+		Team:          "@org/artifacts",
+		SizeThreshold: 100,
+		Extensions:    []string{".pb.go", "jar"},
+	})
+	require.NoError(t, err)
+
+	expectedIssues := []api.Issue{
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(3),
+			Message:  `File "api/v1/api.pb.go" has the .pb.go extension, so it must be owned by @org/artifacts, but it is owned by @org/api`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  `File "libs/vendor.JAR" has the .jar extension, so it must be owned by @org/artifacts, but it is owned by @org/platform`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  `File "assets/logo.png" is marked as binary in .gitattributes, so it must be owned by @org/artifacts, but it is owned by @org/platform`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  `File "models/weights.safetensors" is stored in Git LFS, so it must be owned by @org/artifacts, but it is owned by @org/platform`,
+		},
+		{
+			Severity: api.Error,
+			LineNo:   ptr.Uint64Ptr(2),
+			Message:  `File "data/dump.sql" has 101 bytes, which is more than the 100 bytes threshold, so it must be owned by @org/artifacts, but it is owned by @org/platform`,
+		},
+	}
+
+	// when
+	out, err := sut.Check(context.Background(), api.Input{
+		RepoDir: repoDir,
+		CodeownersEntries: LoadInput(`
+			*              @org/platform
+			/api/          @org/api
+			/artifacts/    @org/platform @org/Artifacts
+		`).CodeownersEntries,
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expectedIssues, out.Issues)
+}
+
+func TestNewStewardshipRequiresTeam(t *testing.T) {
+	// when
+	_, err := check.NewStewardship(check.StewardshipConfig{SizeThreshold: 100})
+
+	// then
+	assert.EqualError(t, err, "stewardship team is required")
+	assert.Equal(t, "Check the STEWARDSHIP_CHECKER_TEAM configuration.", api.Hint(err))
+}
@@ -0,0 +1,162 @@
+package check
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+var (
+	// windowsDriveRegexp matches absolute Windows paths, e.g. `C:\repo` or `C:/repo`.
+	windowsDriveRegexp = regexp.MustCompile(`^[A-Za-z]:/`)
+	// escapedChars are characters which may be escaped with a backslash in gitignore-like patterns.
+	escapedChars = ` #!*?[\`
+)
+
+// UnsafePattern reports patterns written as local filesystem paths: patterns with backslashes, parent
+// directory (`..`) segments, or absolute OS paths. GitHub resolves patterns only against the repository
+// root with forward slashes as separators, so such patterns silently match a different set of files or none.
+type UnsafePattern struct{}
+
+// NewUnsafePattern returns new instance of the UnsafePattern
+func NewUnsafePattern() *UnsafePattern {
+	return &UnsafePattern{}
+}
+
+// Check searches for patterns which are not repository-relative paths with forward slashes.
+// If possible, each issue suggests the pattern normalized to such form.
+func (c *UnsafePattern) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	for _, entry := range in.CodeownersEntries {
+		if ctxutil.ShouldExit(ctx) {
+			return api.Output{}, ctx.Err()
+		}
+
+		normalized, problems, ok := normalizePattern(entry.Pattern, in.RepoDir)
+		if len(problems) == 0 {
+			continue
+		}
+
+		opts := []api.ReportIssueOpt{api.WithEntry(entry)}
+		if ok {
+			fixed := entry
+			fixed.Pattern = normalized
+			opts = append(opts, api.WithFix(api.Fix{
+				Description: "Use repository-relative pattern",
+				Edits:       []api.LineEdit{{LineNo: entry.LineNo, NewText: entryText(fixed)}},
+			}))
+		}
+		for _, msg := range problems {
+			bldr.ReportIssue(msg, opts...)
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// normalizePattern returns the pattern as a repository-relative path with forward slashes together with
+// problems found in the original one. It returns false if the pattern cannot be normalized, e.g. it points
+// outside the repository.
+func normalizePattern(pattern, repoDir string) (string, []string, bool) {
+	var problems []string
+
+	p := pattern
+	if hasSeparatorBackslash(p) {
+		problems = append(problems, "Pattern contains a backslash. GitHub uses only forward slashes as path separators.")
+		p = replaceSeparatorBackslashes(p)
+	}
+
+	if rel, absolute, ok := trimAbsolutePrefix(p, repoDir); absolute {
+		problems = append(problems, "Pattern is an absolute path of the local filesystem. Use a path relative to the repository root.")
+		if !ok {
+			return "", problems, false
+		}
+		p = rel
+	}
+
+	if hasSegment(p, "..") {
+		problems = append(problems, "Pattern contains a parent directory (`..`) segment, which GitHub doesn't resolve.")
+		dir := strings.HasSuffix(p, "/")
+		cleaned := path.Clean(strings.TrimPrefix(p, "/"))
+		if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return "", problems, false
+		}
+		// the original pattern contains a slash, so it's relative to the root, and the cleaned one must be too
+		p = "/" + cleaned
+		if dir {
+			p += "/"
+		}
+	}
+
+	return p, problems, true
+}
+
+// hasSeparatorBackslash returns true if the pattern has a backslash which doesn't escape a special character.
+func hasSeparatorBackslash(p string) bool {
+	return replaceSeparatorBackslashes(p) != p
+}
+
+func replaceSeparatorBackslashes(p string) string {
+	var out strings.Builder
+	for i := 0; i < len(p); i++ {
+		if p[i] != '\\' {
+			out.WriteByte(p[i])
+			continue
+		}
+		if i+1 < len(p) && strings.IndexByte(escapedChars, p[i+1]) >= 0 {
+			out.WriteString(p[i : i+2])
+			i++
+			continue
+		}
+		out.WriteByte('/')
+	}
+	return out.String()
+}
+
+// trimAbsolutePrefix detects absolute paths of the local filesystem: Windows paths with a drive letter,
+// paths of the home directory, and paths of the repository directory itself. If the path contains
+// the repository directory name, it returns the path relative to it, anchored to the root.
+func trimAbsolutePrefix(p, repoDir string) (rel string, absolute, ok bool) {
+	if repoDir != "" {
+		if abs, err := filepath.Abs(repoDir); err == nil {
+			repoDir = abs
+		}
+		root := strings.TrimSuffix(filepath.ToSlash(repoDir), "/") + "/"
+		if root != "/" && strings.HasPrefix(p, root) {
+			return "/" + strings.TrimPrefix(p, root), true, true
+		}
+	}
+	if !windowsDriveRegexp.MatchString(p) && !strings.HasPrefix(p, "~/") {
+		return p, false, false
+	}
+	if repoDir == "" {
+		return "", true, false
+	}
+
+	name := "/" + filepath.Base(repoDir) + "/"
+	idx := strings.Index(p, name)
+	if idx < 0 {
+		return "", true, false
+	}
+	return p[idx+len(name)-1:], true, true
+}
+
+func hasSegment(p, segment string) bool {
+	for _, s := range strings.Split(p, "/") {
+		if s == segment {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns human-readable name of the validator
+func (UnsafePattern) Name() string {
+	return "[Experimental] Unsafe Pattern Checker"
+}
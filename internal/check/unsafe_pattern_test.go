@@ -0,0 +1,90 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsafePattern(t *testing.T) {
+	const (
+		backslashMsg = "Pattern contains a backslash. GitHub uses only forward slashes as path separators."
+		absoluteMsg  = "Pattern is an absolute path of the local filesystem. Use a path relative to the repository root."
+		parentMsg    = "Pattern contains a parent directory (`..`) segment, which GitHub doesn't resolve."
+	)
+	fix := func(lineNo uint64, text string) []api.Fix {
+		return []api.Fix{{
+			Description: "Use repository-relative pattern",
+			Edits:       []api.LineEdit{{LineNo: lineNo, NewText: text}},
+		}}
+	}
+
+	tests := map[string]struct {
+		codeownersInput string
+		repoDir         string
+		expectedIssues  []api.Issue
+	}{
+		"Should report backslashes used as separators": {
+			codeownersInput: `
+					docs\api\  @org/docs
+					\#notes    @org/docs
+			`,
+			expectedIssues: []api.Issue{
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(2), Message: backslashMsg, Fixes: fix(2, "docs/api/ @org/docs")},
+			},
+		},
+		"Should report parent directory segments": {
+			codeownersInput: `
+					/src/../docs/  @org/docs # moved
+					src/api/../*.go @org/api
+					../shared/     @org/shared
+			`,
+			expectedIssues: []api.Issue{
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(2), Message: parentMsg, Fixes: fix(2, "/docs/ @org/docs # moved")},
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(3), Message: parentMsg, Fixes: fix(3, "/src/*.go @org/api")},
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(4), Message: parentMsg},
+			},
+		},
+		"Should report absolute paths of the local filesystem": {
+			codeownersInput: `
+					/home/dev/repo/docs/     @org/docs
+					C:\work\repo\src\app.go  @org/app
+					~/projects/other/x.go    @org/x
+			`,
+			repoDir: "/home/dev/repo",
+			expectedIssues: []api.Issue{
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(2), Message: absoluteMsg, Fixes: fix(2, "/docs/ @org/docs")},
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(3), Message: backslashMsg, Fixes: fix(3, "/src/app.go @org/app")},
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(3), Message: absoluteMsg, Fixes: fix(3, "/src/app.go @org/app")},
+				{Severity: api.Error, LineNo: ptr.Uint64Ptr(4), Message: absoluteMsg},
+			},
+		},
+		"Should not report any issues with correct CODEOWNERS file": {
+			codeownersInput: FixtureValidCODEOWNERS,
+			repoDir:         "/home/dev/repo",
+			expectedIssues:  nil,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewUnsafePattern()
+			in := LoadInput(tc.codeownersInput)
+			in.RepoDir = tc.repoDir
+
+			// when
+			out, err := sut.Check(context.TODO(), in)
+
+			// then
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIssues, out.Issues)
+		})
+	}
+}
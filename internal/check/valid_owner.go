@@ -1,15 +1,17 @@
+//go:build !minimal
+
 package check
 
 import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/mail"
 	"strings"
+	"sync"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/config"
 	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"github.com/google/go-github/v41/github"
 	"github.com/pkg/errors"
@@ -44,11 +46,14 @@ type ValidOwnerConfig struct {
 
 // ValidOwner validates each owner
 type ValidOwner struct {
-	ghClient             *github.Client
-	checkScopes          bool
+	ghClient    *github.Client
+	checkScopes bool
+	// m guards the lazily fetched members, teams, and outside collaborators, as the check
+	// may be executed concurrently for several CODEOWNERS files
+	m                    sync.Mutex
 	orgMembers           *map[string]struct{}
 	orgName              string
-	orgTeams             []*github.Team
+	orgTeams             map[string]*github.Team
 	orgRepoName          string
 	outsideCollaborators *map[string]struct{}
 	ignOwners            map[string]struct{}
@@ -60,7 +65,10 @@ type ValidOwner struct {
 func NewValidOwner(cfg *config.Config, ghClient *github.Client, checkScopes bool) (*ValidOwner, error) {
 	split := strings.Split(cfg.OwnerCheckerRepository, "/")
 	if len(split) != 2 {
-		return nil, errors.Errorf("Wrong repository name. Expected pattern 'owner/repository', got '%s'", cfg.OwnerCheckerRepository)
+		return nil, &api.ConfigError{
+			Field: "OWNER_CHECKER_REPOSITORY",
+			Err:   errors.Errorf("Wrong repository name. Expected pattern 'owner/repository', got '%s'", cfg.OwnerCheckerRepository),
+		}
 	}
 
 	ignOwners := map[string]struct{}{}
@@ -115,10 +123,16 @@ func (v *ValidOwner) Check(ctx context.Context, in api.Input) (api.Output, error
 				continue
 			}
 
-			validFn := v.selectValidateFn(ownerName)
-			if err := validFn(ctx, ownerName); err != nil {
-				bldr.ReportIssue(err.msg, api.WithEntry(entry))
-				if err.permanent { // Doesn't make sense to process further
+			validation := in.ValidateOwner(ownerName, func() api.OwnerValidation {
+				validFn := v.selectValidateFn(ownerName)
+				if err := validFn(ctx, ownerName); err != nil {
+					return api.OwnerValidation{Problem: err.msg, Permanent: err.permanent}
+				}
+				return api.OwnerValidation{}
+			})
+			if validation.Problem != "" {
+				bldr.ReportIssue(validation.Problem, api.WithOwnersOf(entry))
+				if validation.Permanent { // Doesn't make sense to process further
 					return bldr.Output(), nil
 				}
 			}
@@ -129,22 +143,6 @@ func (v *ValidOwner) Check(ctx context.Context, in api.Input) (api.Output, error
 	return bldr.Output(), nil
 }
 
-func isEmailAddress(s string) bool {
-	_, err := mail.ParseAddress(s)
-	return err == nil
-}
-
-func isGitHubTeam(s string) bool {
-	hasPrefix := strings.HasPrefix(s, "@")
-	containsSlash := strings.Contains(s, "/")
-	split := strings.SplitN(s, "/", 3) // 3 is enough to confirm that is invalid + will not overflow the buffer
-	return hasPrefix && containsSlash && len(split) == 2 && len(split[1]) > 0
-}
-
-func isGitHubUser(s string) bool {
-	return !strings.Contains(s, "/") && strings.HasPrefix(s, "@")
-}
-
 func (v *ValidOwner) isIgnoredOwner(name string) bool {
 	_, found := v.ignOwners[name]
 	return found
@@ -174,43 +172,70 @@ func (v *ValidOwner) selectValidateFn(name string) func(context.Context, string)
 }
 
 func (v *ValidOwner) initOrgListTeams(ctx context.Context) *validateError {
-	var teams []*github.Team
-	req := &github.ListOptions{
-		PerPage: 100,
-	}
-	for {
-		resultPage, resp, err := v.ghClient.Teams.ListTeams(ctx, v.orgName, req)
-		if err != nil { // TODO(mszostok): implement retry?
-			switch err := err.(type) {
-			case *github.ErrorResponse:
-				if err.Response.StatusCode == http.StatusUnauthorized {
-					return newValidateError("Teams for organization %q could not be queried. Requires GitHub authorization.", v.orgName)
-				}
-				return newValidateError("HTTP error occurred while calling GitHub: %v", err)
-			case *github.RateLimitError:
-				return newValidateError("GitHub rate limit reached: %v", err.Message)
-			default:
-				return newValidateError("Unknown error occurred while calling GitHub: %v", err)
+	teams, err := listAllPages(ctx, func(ctx context.Context, page int) ([]*github.Team, *github.Response, error) {
+		return v.ghClient.Teams.ListTeams(ctx, v.orgName, &github.ListOptions{PerPage: 100, Page: page})
+	})
+	if err != nil { // TODO(mszostok): implement retry?
+		switch err := err.(type) {
+		case *github.ErrorResponse:
+			if err.Response.StatusCode == http.StatusUnauthorized {
+				return newValidateError("Teams for organization %q could not be queried. Requires GitHub authorization.", v.orgName)
 			}
+			return newValidateError("HTTP error occurred while calling GitHub: %v", err)
+		case *github.RateLimitError:
+			return newValidateError("GitHub rate limit reached: %v", err.Message)
+		default:
+			return newValidateError("Unknown error occurred while calling GitHub: %v", err)
 		}
-		teams = append(teams, resultPage...)
-		if resp.NextPage == 0 {
-			break
-		}
-		req.Page = resp.NextPage
 	}
 
-	v.orgTeams = teams
+	// GitHub normalizes slugs before comparison, so teams are indexed by the lowercase slug,
+	// which keeps lookups fast in organizations with thousands of teams
+	v.orgTeams = make(map[string]*github.Team, len(teams))
+	for _, t := range teams {
+		v.orgTeams[strings.ToLower(t.GetSlug())] = t
+	}
 
 	return nil
 }
 
-func (v *ValidOwner) validateTeam(ctx context.Context, name string) *validateError {
+// teams returns teams of the organization, which are fetched on the first call.
+func (v *ValidOwner) teams(ctx context.Context) (map[string]*github.Team, *validateError) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
 	if v.orgTeams == nil {
 		if err := v.initOrgListTeams(ctx); err != nil {
-			return err.AsPermanent()
+			return nil, err
 		}
 	}
+	return v.orgTeams, nil
+}
+
+// repositoryUsers returns members of the organization and outside collaborators of the repository,
+// which are fetched on the first call.
+func (v *ValidOwner) repositoryUsers(ctx context.Context) (map[string]struct{}, map[string]struct{}, *validateError) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if v.orgMembers == nil {
+		if err := v.initOrgListMembers(ctx); err != nil {
+			return nil, nil, newValidateError("Cannot initialize organization member list: %v", err)
+		}
+	}
+	if v.outsideCollaborators == nil {
+		if err := v.initOutsideCollaboratorsList(ctx); err != nil {
+			return nil, nil, newValidateError("Cannot initialize outside collaborators list: %v", err)
+		}
+	}
+	return *v.orgMembers, *v.outsideCollaborators, nil
+}
+
+func (v *ValidOwner) validateTeam(ctx context.Context, name string) *validateError {
+	orgTeams, vErr := v.teams(ctx)
+	if vErr != nil {
+		return vErr.AsPermanent()
+	}
 
 	// called after validation it's safe to work on `parts` slice
 	parts := strings.SplitN(name, "/", 2)
@@ -223,17 +248,7 @@ func (v *ValidOwner) validateTeam(ctx context.Context, name string) *validateErr
 		return newValidateError("Team %q does not belong to %q organization.", name, v.orgName)
 	}
 
-	teamExists := func() bool {
-		for _, v := range v.orgTeams {
-			// GitHub normalizes name before comparison
-			if strings.EqualFold(v.GetSlug(), team) {
-				return true
-			}
-		}
-		return false
-	}
-
-	if !teamExists() {
+	if _, teamExists := orgTeams[strings.ToLower(team)]; !teamExists {
 		return newValidateError("Team %q does not exist in organization %q.", name, org)
 	}
 
@@ -294,16 +309,9 @@ func (v *ValidOwner) validateTeam(ctx context.Context, name string) *validateErr
 }
 
 func (v *ValidOwner) validateGitHubUser(ctx context.Context, name string) *validateError {
-	if v.orgMembers == nil { // TODO(mszostok): lazy init, make it more robust.
-		if err := v.initOrgListMembers(ctx); err != nil {
-			return newValidateError("Cannot initialize organization member list: %v", err).AsPermanent()
-		}
-	}
-
-	if v.outsideCollaborators == nil { // TODO(mszostok): lazy init, make it more robust.
-		if err := v.initOutsideCollaboratorsList(ctx); err != nil {
-			return newValidateError("Cannot initialize outside collaborators list: %v", err).AsPermanent()
-		}
+	orgMembers, outsideCollaborators, vErr := v.repositoryUsers(ctx)
+	if vErr != nil {
+		return vErr.AsPermanent()
 	}
 
 	userName := strings.TrimPrefix(name, "@")
@@ -322,8 +330,8 @@ func (v *ValidOwner) validateGitHubUser(ctx context.Context, name string) *valid
 		}
 	}
 
-	_, isMember := (*v.orgMembers)[userName]
-	_, isOutsideCollaborator := (*v.outsideCollaborators)[userName]
+	_, isMember := orgMembers[userName]
+	_, isOutsideCollaborator := outsideCollaborators[userName]
 	if !(isMember || isOutsideCollaborator) {
 		return newValidateError("User %q is not an owner of the repository", name)
 	}
@@ -338,21 +346,14 @@ func (v *ValidOwner) validateGitHubUser(ctx context.Context, name string) *valid
 // But latency is too huge for checking each single user independent
 // better and faster is to ask for all members and cache them.
 func (v *ValidOwner) initOrgListMembers(ctx context.Context) error {
-	opt := &github.ListMembersOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
-	}
-
-	var allMembers []*github.User
-	for {
-		users, resp, err := v.ghClient.Organizations.ListMembers(ctx, v.orgName, opt)
-		if err != nil {
-			return err
+	allMembers, err := listAllPages(ctx, func(ctx context.Context, page int) ([]*github.User, *github.Response, error) {
+		opt := &github.ListMembersOptions{
+			ListOptions: github.ListOptions{PerPage: 100, Page: page},
 		}
-		allMembers = append(allMembers, users...)
-		if resp.NextPage == 0 {
-			break
-		}
-		opt.Page = resp.NextPage
+		return v.ghClient.Organizations.ListMembers(ctx, v.orgName, opt)
+	})
+	if err != nil {
+		return err
 	}
 
 	v.orgMembers = &map[string]struct{}{}
@@ -367,22 +368,15 @@ func (v *ValidOwner) initOrgListMembers(ctx context.Context) error {
 //
 //	outsideCollaborators *map[string]struct{}
 func (v *ValidOwner) initOutsideCollaboratorsList(ctx context.Context) error {
-	opt := &github.ListCollaboratorsOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
-		Affiliation: "outside",
-	}
-
-	var allMembers []*github.User
-	for {
-		collaborators, resp, err := v.ghClient.Repositories.ListCollaborators(ctx, v.orgName, v.orgRepoName, opt)
-		if err != nil {
-			return err
+	allMembers, err := listAllPages(ctx, func(ctx context.Context, page int) ([]*github.User, *github.Response, error) {
+		opt := &github.ListCollaboratorsOptions{
+			ListOptions: github.ListOptions{PerPage: 100, Page: page},
+			Affiliation: "outside",
 		}
-		allMembers = append(allMembers, collaborators...)
-		if resp.NextPage == 0 {
-			break
-		}
-		opt.Page = resp.NextPage
+		return v.ghClient.Repositories.ListCollaborators(ctx, v.orgName, v.orgRepoName, opt)
+	})
+	if err != nil {
+		return err
 	}
 
 	v.outsideCollaborators = &map[string]struct{}{}
@@ -394,7 +388,7 @@ func (v *ValidOwner) initOutsideCollaboratorsList(ctx context.Context) error {
 }
 
 // Name returns human-readable name of the validator
-func (ValidOwner) Name() string {
+func (*ValidOwner) Name() string {
 	return "Valid Owner Checker"
 }
 
@@ -402,17 +396,7 @@ func (ValidOwner) Name() string {
 func (v *ValidOwner) CheckSatisfied(ctx context.Context) error {
 	_, resp, err := v.ghClient.Repositories.Get(ctx, v.orgName, v.orgRepoName)
 	if err != nil {
-		switch err := err.(type) {
-		case *github.ErrorResponse:
-			if err.Response.StatusCode == http.StatusNotFound {
-				return fmt.Errorf("repository %s/%s not found, or it's private and token doesn't have enough permission", v.orgName, v.orgRepoName)
-			}
-			return fmt.Errorf("HTTP error occurred while calling GitHub: %v", err)
-		case *github.RateLimitError:
-			return fmt.Errorf("GitHub rate limit reached: %v", err.Message)
-		default:
-			return fmt.Errorf("unknown error occurred while calling GitHub: %v", err)
-		}
+		return newGitHubError(err, fmt.Sprintf("getting repository %s/%s", v.orgName, v.orgRepoName))
 	}
 
 	if !v.checkScopes {
@@ -441,7 +425,10 @@ func (*ValidOwner) checkRequiredScopes(header http.Header) error {
 	}
 
 	if len(missing) > 0 {
-		return fmt.Errorf("missing scopes: %q", strings.Join(missing, ", "))
+		return &api.ConfigError{
+			Field: "GITHUB_ACCESS_TOKEN",
+			Err:   fmt.Errorf("missing scopes: %q", strings.Join(missing, ", ")),
+		}
 	}
 
 	return nil
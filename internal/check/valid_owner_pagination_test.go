@@ -0,0 +1,114 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paginatedTeams serves teams of the org organization in pages of 100 teams, with the Link headers sent by GitHub.
+// Teams are named team-0, team-1, and so on. Requested pages are counted.
+type paginatedTeams struct {
+	pages     int
+	failPage  int
+	mu        sync.Mutex
+	requested map[int]int
+}
+
+func (p *paginatedTeams) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	page := 1
+	if raw := r.URL.Query().Get("page"); raw != "" {
+		page, _ = strconv.Atoi(raw)
+	}
+	p.mu.Lock()
+	p.requested[page]++
+	p.mu.Unlock()
+
+	if page == p.failPage {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var links []string
+	if page < p.pages {
+		links = append(links,
+			fmt.Sprintf(`<http://%s/orgs/org/teams?page=%d&per_page=100>; rel="next"`, r.Host, page+1),
+			fmt.Sprintf(`<http://%s/orgs/org/teams?page=%d&per_page=100>; rel="last"`, r.Host, p.pages),
+		)
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	teams := make([]string, 0, 100)
+	for i := (page - 1) * 100; i < page*100; i++ {
+		teams = append(teams, fmt.Sprintf(`{"slug": "team-%d"}`, i))
+	}
+	fmt.Fprintf(w, "[%s]", strings.Join(teams, ","))
+}
+
+func TestValidOwnerCheckerPaginatedTeams(t *testing.T) {
+	tests := map[string]struct {
+		givenFailPage int
+		expIssue      string
+	}{
+		"Should find teams on all pages": {},
+		"Should report failed page": {
+			givenFailPage: 27,
+			expIssue:      "HTTP error occurred while calling GitHub",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			teams := &paginatedTeams{pages: 40, failPage: tc.givenFailPage, requested: map[int]int{}}
+			mux := http.NewServeMux()
+			mux.Handle("/orgs/org/teams", teams)
+			mux.HandleFunc("/orgs/org/teams/", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"name": "repo", "permissions": {"push": true}}`)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			client := github.NewClient(nil)
+			client.BaseURL, _ = url.Parse(srv.URL + "/")
+
+			sut, err := check.NewValidOwner(&config.Config{OwnerCheckerRepository: "org/repo"}, client, false)
+			require.NoError(t, err)
+
+			// when
+			out, err := sut.Check(context.Background(), LoadInput(`
+				/api/   @org/team-0
+				/docs/  @org/TEAM-3999
+			`))
+
+			// then
+			require.NoError(t, err)
+			if tc.expIssue != "" {
+				require.Len(t, out.Issues, 1)
+				assert.Contains(t, out.Issues[0].Message, tc.expIssue)
+				return
+			}
+			assert.Empty(t, out.Issues)
+			require.Len(t, teams.requested, 40)
+			for page, count := range teams.requested {
+				assert.Equal(t, 1, count, "page %d requested more than once", page)
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
+//go:build !minimal
+
 package check_test
 
 import (
 	"context"
 	"testing"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"github.com/stretchr/testify/require"
 
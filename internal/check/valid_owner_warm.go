@@ -0,0 +1,140 @@
+//go:build !minimal
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// warmConcurrency limits the number of concurrent GitHub requests while warming the cache.
+const warmConcurrency = 8
+
+// WarmStats summarizes the prefetched data.
+type WarmStats struct {
+	Members      int
+	Teams        int
+	Repositories int
+}
+
+// WarmOwnerCache prefetches all GitHub responses needed by the ValidOwner check: organization members, teams, and users,
+// and for each of the given repositories, the repository itself, its outside collaborators, and permissions of all teams.
+// The responses are stored by the cache wrapped around the client transport, e.g. github.WithResponseCache,
+// so validations that share the cache don't call GitHub for them anymore.
+func WarmOwnerCache(ctx context.Context, ghClient *github.Client, org string, repos []string) (WarmStats, error) {
+	v := &ValidOwner{ghClient: ghClient, orgName: org}
+
+	if err := v.initOrgListMembers(ctx); err != nil {
+		return WarmStats{}, newGitHubError(err, fmt.Sprintf("listing members of organization %q", org))
+	}
+	if err := v.initOrgListTeams(ctx); err != nil {
+		return WarmStats{}, errors.New(err.msg)
+	}
+
+	logins := make([]string, 0, len(*v.orgMembers))
+	for login := range *v.orgMembers {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+
+	err := forEachConcurrently(ctx, logins, func(login string) error {
+		_, _, err := ghClient.Users.Get(ctx, login)
+		return ignoreNotFound(err, fmt.Sprintf("getting user %q", login))
+	})
+	if err != nil {
+		return WarmStats{}, err
+	}
+
+	for _, repo := range repos {
+		if err := v.warmRepository(ctx, repo); err != nil {
+			return WarmStats{}, err
+		}
+	}
+
+	return WarmStats{Members: len(logins), Teams: len(v.orgTeams), Repositories: len(repos)}, nil
+}
+
+func (v *ValidOwner) warmRepository(ctx context.Context, repo string) error {
+	v.orgRepoName = repo
+
+	if _, _, err := v.ghClient.Repositories.Get(ctx, v.orgName, repo); err != nil {
+		return newGitHubError(err, fmt.Sprintf("getting repository %s/%s", v.orgName, repo))
+	}
+	if err := v.initOutsideCollaboratorsList(ctx); err != nil {
+		return newGitHubError(err, fmt.Sprintf("listing outside collaborators of repository %s/%s", v.orgName, repo))
+	}
+
+	collaborators := make([]string, 0, len(*v.outsideCollaborators))
+	for login := range *v.outsideCollaborators {
+		collaborators = append(collaborators, login)
+	}
+	err := forEachConcurrently(ctx, collaborators, func(login string) error {
+		_, _, err := v.ghClient.Users.Get(ctx, login)
+		return ignoreNotFound(err, fmt.Sprintf("getting user %q", login))
+	})
+	if err != nil {
+		return err
+	}
+
+	slugs := make([]string, 0, len(v.orgTeams))
+	for _, t := range v.orgTeams {
+		slugs = append(slugs, t.GetSlug())
+	}
+	sort.Strings(slugs)
+	return forEachConcurrently(ctx, slugs, func(slug string) error {
+		// teams without access to the repository respond with "not found", which is cached as well
+		_, _, err := v.ghClient.Teams.IsTeamRepoBySlug(ctx, v.orgName, slug, v.orgName, repo)
+		return ignoreNotFound(err, fmt.Sprintf("getting permissions of team %q to repository %s/%s", slug, v.orgName, repo))
+	})
+}
+
+func ignoreNotFound(err error, op string) error {
+	var respErr *github.ErrorResponse
+	if err == nil || (errors.As(err, &respErr) && respErr.Response.StatusCode == http.StatusNotFound) {
+		return nil
+	}
+	return newGitHubError(err, op)
+}
+
+// forEachConcurrently calls fn for each item with a limited concurrency and returns the first error.
+func forEachConcurrently(ctx context.Context, items []string, fn func(string) error) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, warmConcurrency)
+	)
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(item string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(item); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,84 @@
+//go:build !minimal
+
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	ghcache "go.szostok.io/codeowners/internal/github"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmOwnerCache(t *testing.T) {
+	// given
+	var hits int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/org/members", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"login": "alice"}, {"login": "bob"}]`)
+	})
+	mux.HandleFunc("/orgs/org/teams", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"slug": "platform"}, {"slug": "docs"}]`)
+	})
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"login": %q}`, r.URL.Path[len("/users/"):])
+	})
+	mux.HandleFunc("/repos/org/repo", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"name": "repo"}`)
+	})
+	mux.HandleFunc("/repos/org/repo/collaborators", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "outside", r.URL.Query().Get("affiliation"))
+		fmt.Fprint(w, `[{"login": "carol"}]`)
+	})
+	mux.HandleFunc("/orgs/org/teams/platform/repos/org/repo", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"name": "repo", "permissions": {"push": true}}`)
+	})
+	mux.HandleFunc("/orgs/org/teams/docs/repos/org/repo", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		mux.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cache := ghcache.NewResponseCache()
+	newClient := func() *github.Client {
+		c := github.NewClient(&http.Client{Transport: cache.Wrap(nil)})
+		c.BaseURL, _ = url.Parse(srv.URL + "/")
+		return c
+	}
+
+	// when
+	stats, err := check.WarmOwnerCache(context.Background(), newClient(), "org", []string{"repo"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, check.WarmStats{Members: 2, Teams: 2, Repositories: 1}, stats)
+
+	// when
+	warmHits := atomic.LoadInt32(&hits)
+	sut, err := check.NewValidOwner(&config.Config{OwnerCheckerRepository: "org/repo"}, newClient(), false)
+	require.NoError(t, err)
+	require.NoError(t, sut.CheckSatisfied(context.Background()))
+	out, err := sut.Check(context.Background(), LoadInput(`
+		*       @org/platform @alice
+		/docs/  @org/docs @carol
+	`))
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, out.Issues, 1)
+	assert.Equal(t, `Team "docs" does not have permissions associated with the repository "repo".`, out.Issues[0].Message)
+	assert.Equal(t, warmHits, atomic.LoadInt32(&hits), "the owners check should be served from the cache")
+}
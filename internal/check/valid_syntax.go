@@ -6,8 +6,9 @@ import (
 	"regexp"
 	"strings"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/ctxutil"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
 )
 
 var (
@@ -19,17 +20,32 @@ var (
 	// Per: https://davidcel.is/posts/stop-validating-email-addresses-with-regex/
 	// just check if there is '@' and a '.' afterwards
 	emailRegexp = regexp.MustCompile(`.+@.+\..+`)
+
+	// GitLab allows dots and underscores in usernames, nested subgroups, and roles prefixed with '@@'
+	// see: https://docs.gitlab.com/ee/user/project/codeowners/reference.html
+	gitlabUsernameOrGroupRegexp = regexp.MustCompile(`^@(?:@(?i:developers?|maintainers?|owners?)|[\w.-]+(?:/[\w.-]+)*)$`)
 )
 
 // ValidSyntax provides a syntax validation for CODEOWNERS file.
 //
 // If any line in your CODEOWNERS file contains invalid syntax, the file will not be detected and will
 // not be used to request reviews. Invalid syntax includes inline comments and user or team names that do not exist on GitHub.
-type ValidSyntax struct{}
+type ValidSyntax struct {
+	providers []codeowners.Provider
+}
 
-// NewValidSyntax returns new ValidSyntax instance.
+// NewValidSyntax returns new ValidSyntax instance which validates the GitHub dialect.
 func NewValidSyntax() *ValidSyntax {
-	return &ValidSyntax{}
+	return &ValidSyntax{
+		providers: []codeowners.Provider{codeowners.GitHub},
+	}
+}
+
+// WithProviders sets the provider dialects against which the syntax is validated.
+// An owner is valid if it is valid in any of the given dialects.
+func (v *ValidSyntax) WithProviders(providers ...codeowners.Provider) *ValidSyntax {
+	v.providers = providers
+	return v
 }
 
 // Check for syntax issues in your CODEOWNERS file.
@@ -51,8 +67,8 @@ func (v *ValidSyntax) Check(ctx context.Context, in api.Input) (api.Output, erro
 			case strings.EqualFold(item, "#"):
 				break ownersLoop // no need to check for the rest items in this line, as they are ignored
 			case strings.HasPrefix(item, "@"):
-				if !usernameOrTeamRegexp.MatchString(item) {
-					msg := fmt.Sprintf("Owner '%s' does not look like a GitHub username or team name", item)
+				if !v.isValidOwnerName(item) {
+					msg := fmt.Sprintf("Owner '%s' does not look like a %s username or team name", item, v.providerNames())
 					bldr.ReportIssue(msg, api.WithEntry(entry), api.WithSeverity(api.Warning))
 				}
 			default:
@@ -67,6 +83,35 @@ func (v *ValidSyntax) Check(ctx context.Context, in api.Input) (api.Output, erro
 	return bldr.Output(), nil
 }
 
+func (v *ValidSyntax) isValidOwnerName(owner string) bool {
+	for _, p := range v.providers {
+		switch p {
+		case codeowners.GitHub:
+			if usernameOrTeamRegexp.MatchString(owner) {
+				return true
+			}
+		case codeowners.GitLab:
+			if gitlabUsernameOrGroupRegexp.MatchString(owner) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *ValidSyntax) providerNames() string {
+	names := make([]string, 0, len(v.providers))
+	for _, p := range v.providers {
+		switch p {
+		case codeowners.GitHub:
+			names = append(names, "GitHub")
+		case codeowners.GitLab:
+			names = append(names, "GitLab")
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
 func (ValidSyntax) Name() string {
 	return "Valid Syntax Checker"
 }
@@ -4,9 +4,9 @@ import (
 	"context"
 	"testing"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/codeowners"
 
 	"github.com/stretchr/testify/assert"
@@ -16,6 +16,7 @@ import (
 func TestValidSyntaxChecker(t *testing.T) {
 	tests := map[string]struct {
 		codeowners string
+		providers  []codeowners.Provider
 		issue      *api.Issue
 	}{
 		"No owners": {
@@ -65,12 +66,38 @@ func TestValidSyntaxChecker(t *testing.T) {
 		"Comment in pattern line": {
 			codeowners: `* @org/hakuna-matata # this is allowed`,
 		},
+		"GitLab nested group in GitHub dialect": {
+			codeowners: `* @org/platform/sre`,
+			issue: &api.Issue{
+				Severity: api.Warning,
+				LineNo:   ptr.Uint64Ptr(1),
+				Message:  "Owner '@org/platform/sre' does not look like a GitHub username or team name",
+			},
+		},
+		"GitLab nested group and role in GitHub and GitLab dialects": {
+			codeowners: `* @org/platform/sre @@maintainer @john.doe`,
+			providers:  []codeowners.Provider{codeowners.GitHub, codeowners.GitLab},
+		},
+		"Bad username in GitHub and GitLab dialects": {
+			codeowners: `* @bad+org`,
+			providers:  []codeowners.Provider{codeowners.GitHub, codeowners.GitLab},
+			issue: &api.Issue{
+				Severity: api.Warning,
+				LineNo:   ptr.Uint64Ptr(1),
+				Message:  "Owner '@bad+org' does not look like a GitHub or GitLab username or team name",
+			},
+		},
 	}
 	for tn, tc := range tests {
 		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := check.NewValidSyntax()
+			if tc.providers != nil {
+				sut.WithProviders(tc.providers...)
+			}
+
 			// when
-			out, err := check.NewValidSyntax().
-				Check(context.Background(), LoadInput(tc.codeowners))
+			out, err := sut.Check(context.Background(), LoadInput(tc.codeowners))
 
 			// then
 			require.NoError(t, err)
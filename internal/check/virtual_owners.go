@@ -0,0 +1,104 @@
+package check
+
+import (
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// VirtualOwners are owner groups defined in the configuration, e.g. `@virtual/payments = @alice, @org/payments-core`.
+// They bridge gaps where the GitHub team structure doesn't match the real responsibility. Checks which require
+// a given owner accept any member of the virtual group in place of the group. Keys are lower-cased group names.
+type VirtualOwners map[string][]string
+
+// ParseVirtualOwners parses definitions in form `NAME = OWNER, OWNER`. Owners may be separated with commas
+// or spaces. Definitions split on commas, e.g. by comma-separated flags and environment variables, are joined
+// back, so an item without `=` continues the previous definition.
+func ParseVirtualOwners(defs []string) (VirtualOwners, error) {
+	out := VirtualOwners{}
+
+	var current string
+	for _, def := range defs {
+		members := def
+		if name, rest, found := strings.Cut(def, "="); found {
+			current = strings.TrimSpace(name)
+			members = rest
+			if !strings.HasPrefix(current, "@") || strings.ContainsAny(current, " \t,") {
+				return nil, virtualOwnersError("group name %q must be a single owner-like name, e.g. @virtual/payments", current)
+			}
+			key := strings.ToLower(current)
+			if _, found := out[key]; found {
+				return nil, virtualOwnersError("group %s is defined more than once", current)
+			}
+			out[key] = nil
+		}
+		if current == "" {
+			return nil, virtualOwnersError("definition %q must be in form 'NAME = OWNER, OWNER'", def)
+		}
+
+		key := strings.ToLower(current)
+		out[key] = append(out[key], strings.FieldsFunc(members, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+
+	for _, name := range out.names() {
+		if len(out[name]) == 0 {
+			return nil, virtualOwnersError("group %s doesn't have any members", name)
+		}
+		for _, m := range out[name] {
+			if _, found := out[strings.ToLower(m)]; found {
+				return nil, virtualOwnersError("group %s has the virtual group %s as a member, nested groups are not supported", name, m)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Members returns members of a given virtual group, or the owner itself if it's not a virtual group.
+func (v VirtualOwners) Members(owner string) []string {
+	if members, found := v[strings.ToLower(owner)]; found {
+		return members
+	}
+	return []string{owner}
+}
+
+// Covers returns true if owners contain the required owner or, if the required owner is a virtual group,
+// any of its members.
+func (v VirtualOwners) Covers(owners []string, required string) bool {
+	if containsOwner(owners, required) {
+		return true
+	}
+	for _, m := range v.Members(required) {
+		if containsOwner(owners, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v VirtualOwners) names() []string {
+	out := make([]string, 0, len(v))
+	for name := range v {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func virtualOwnersError(format string, args ...interface{}) error {
+	return &api.ConfigError{Field: "VIRTUAL_OWNERS", Err: errors.Errorf(format, args...)}
+}
+
+func containsOwner(owners []string, owner string) bool {
+	for _, o := range owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
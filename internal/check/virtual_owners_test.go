@@ -0,0 +1,99 @@
+package check_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVirtualOwners(t *testing.T) {
+	tests := map[string]struct {
+		defs     []string
+		expected check.VirtualOwners
+	}{
+		"Should parse definitions with commas and spaces": {
+			defs: []string{"@virtual/payments = @alice, @org/payments-core", "@virtual/Docs=@bob @org/writers"},
+			expected: check.VirtualOwners{
+				"@virtual/payments": {"@alice", "@org/payments-core"},
+				"@virtual/docs":     {"@bob", "@org/writers"},
+			},
+		},
+		"Should join definitions split on commas": {
+			defs: []string{"@virtual/payments = @alice", " @org/payments-core", "@virtual/docs = @bob"},
+			expected: check.VirtualOwners{
+				"@virtual/payments": {"@alice", "@org/payments-core"},
+				"@virtual/docs":     {"@bob"},
+			},
+		},
+		"Should accept no definitions": {
+			expected: check.VirtualOwners{},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			out, err := check.ParseVirtualOwners(tc.defs)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestParseVirtualOwnersErrors(t *testing.T) {
+	tests := map[string]struct {
+		defs     []string
+		expError string
+	}{
+		"Missing name": {
+			defs:     []string{"@alice, @bob"},
+			expError: `definition "@alice, @bob" must be in form 'NAME = OWNER, OWNER'`,
+		},
+		"Name is not an owner": {
+			defs:     []string{"payments = @alice"},
+			expError: `group name "payments" must be a single owner-like name, e.g. @virtual/payments`,
+		},
+		"Duplicated group": {
+			defs:     []string{"@virtual/payments = @alice", "@virtual/Payments = @bob"},
+			expError: "group @virtual/Payments is defined more than once",
+		},
+		"Group without members": {
+			defs:     []string{"@virtual/payments ="},
+			expError: "group @virtual/payments doesn't have any members",
+		},
+		"Nested group": {
+			defs:     []string{"@virtual/payments = @virtual/core", "@virtual/core = @alice"},
+			expError: "group @virtual/payments has the virtual group @virtual/core as a member, nested groups are not supported",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := check.ParseVirtualOwners(tc.defs)
+
+			// then
+			var cfgErr *api.ConfigError
+			require.ErrorAs(t, err, &cfgErr)
+			assert.Equal(t, "VIRTUAL_OWNERS", cfgErr.Field)
+			assert.EqualError(t, err, tc.expError)
+		})
+	}
+}
+
+func TestVirtualOwnersCovers(t *testing.T) {
+	// given
+	virtual, err := check.ParseVirtualOwners([]string{"@virtual/payments = @alice, @org/payments-core"})
+	require.NoError(t, err)
+
+	// then
+	assert.True(t, virtual.Covers([]string{"@org/Payments-Core"}, "@virtual/payments"))
+	assert.True(t, virtual.Covers([]string{"@virtual/payments"}, "@virtual/Payments"))
+	assert.True(t, virtual.Covers([]string{"@org/sre"}, "@org/sre"))
+	assert.False(t, virtual.Covers([]string{"@bob"}, "@virtual/payments"))
+	assert.False(t, check.VirtualOwners(nil).Covers([]string{"@alice"}, "@virtual/payments"))
+}
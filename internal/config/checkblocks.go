@@ -0,0 +1,241 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ChecksKey is the config file key which holds either the list of checks, or per-check configuration blocks, e.g.:
+//
+//	checks:
+//	  enabled: [syntax, owners, files]
+//	  notOwned:
+//	    skipPatterns: [/vendor/]
+//	  validOwner:
+//	    ignoredOwners: ["@ghost"]
+//
+// Settings of a block are the options with the `<check>-checker-` prefix, e.g. `checks.notOwned.skipPatterns`
+// is the same as `not-owned-checker-skip-patterns`. The `enabled` list is the same as the `checks` option.
+const ChecksKey = "checks"
+
+// enabledKey is the key of per-check configuration blocks which holds the list of checks.
+const enabledKey = "enabled"
+
+// checkBlockAliases maps names of blocks to prefixes of their options, if they differ, e.g. check IDs.
+var checkBlockAliases = map[string]string{
+	"owners":     "owner",
+	"validOwner": "owner",
+	"identities": "identity",
+	"approvals":  "approval",
+}
+
+// ExpandCheckBlocks rewrites per-check configuration blocks of the config file content into the options
+// they stand for, so the rest of the configuration handles only flat options. Blocks of profiles are expanded too.
+// The returned origins map each expanded option to the key path used in the file, e.g. `not-owned-checker-skip-patterns`
+// to `checks.notOwned.skipPatterns`, to point at the offending key in errors. Unknown checks and settings
+// are reported with their line numbers. Known are option keys, as returned by KnownKeys.
+func ExpandCheckBlocks(in []byte, known []string) ([]byte, map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(in, &doc); err != nil {
+		return nil, nil, errors.Wrap(err, "while parsing config file")
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return in, nil, nil
+	}
+
+	root := doc.Content[0]
+	e := blockExpander{known: known, origins: map[string]string{}}
+	if err := e.expand(root, ""); err != nil {
+		return nil, nil, err
+	}
+	for idx := 0; idx+1 < len(root.Content); idx += 2 {
+		if root.Content[idx].Value != ProfilesKey || root.Content[idx+1].Kind != yaml.MappingNode {
+			continue
+		}
+		profiles := root.Content[idx+1]
+		for p := 0; p+1 < len(profiles.Content); p += 2 {
+			if profiles.Content[p+1].Kind != yaml.MappingNode {
+				continue
+			}
+			if err := e.expand(profiles.Content[p+1], ProfilesKey+"."+profiles.Content[p].Value+"."); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if !e.changed {
+		return in, nil, nil
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, errors.Wrap(err, "while encoding config file")
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, errors.Wrap(err, "while encoding config file")
+	}
+	return out.Bytes(), e.origins, nil
+}
+
+type blockExpander struct {
+	known   []string
+	origins map[string]string
+	changed bool
+}
+
+// expand replaces the blocks of a single mapping of settings. The parent is the key path of the mapping, used in errors.
+func (e *blockExpander) expand(settings *yaml.Node, parent string) error {
+	idx := -1
+	for i := 0; i+1 < len(settings.Content); i += 2 {
+		if settings.Content[i].Value == ChecksKey && settings.Content[i+1].Kind == yaml.MappingNode {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil
+	}
+	e.changed = true
+
+	// options set directly take part in conflict detection
+	lines := map[string]int{}
+	for i := 0; i+1 < len(settings.Content); i += 2 {
+		lines[settings.Content[i].Value] = settings.Content[i].Line
+	}
+
+	var expanded []*yaml.Node
+	blocks := settings.Content[idx+1]
+	for b := 0; b+1 < len(blocks.Content); b += 2 {
+		name, block := blocks.Content[b], blocks.Content[b+1]
+		path := parent + ChecksKey + "." + name.Value
+
+		if name.Value == enabledKey {
+			expanded = append(expanded, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ChecksKey}, block)
+			continue
+		}
+
+		prefix, err := e.blockPrefix(name, parent)
+		if err != nil {
+			return err
+		}
+		if block.Kind != yaml.MappingNode {
+			return errors.Errorf("line %d: %q must be a map of settings of the check", name.Line, path)
+		}
+
+		for s := 0; s+1 < len(block.Content); s += 2 {
+			key := block.Content[s]
+			option := prefix + kebabCase(key.Value)
+			if !containsString(e.known, option) {
+				msg := fmt.Sprintf("line %d: unknown setting %q", key.Line, path+"."+key.Value)
+				if hint := suggestSetting(key.Value, prefix, e.known); hint != "" {
+					msg += fmt.Sprintf(", did you mean %q?", path+"."+hint)
+				}
+				return errors.New(msg)
+			}
+			if line, found := lines[option]; found {
+				return errors.Errorf("line %d: %q is already set by %q on line %d", key.Line, path+"."+key.Value, parent+option, line)
+			}
+			lines[option] = key.Line
+			e.origins[parent+option] = path + "." + key.Value
+
+			expanded = append(expanded, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: option, Line: key.Line}, block.Content[s+1])
+		}
+	}
+
+	content := append([]*yaml.Node{}, settings.Content[:idx]...)
+	content = append(content, expanded...)
+	settings.Content = append(content, settings.Content[idx+2:]...)
+	return nil
+}
+
+// blockPrefix returns the prefix of options of a given block, e.g. `not-owned-checker-` for the `notOwned` block.
+func (e *blockExpander) blockPrefix(name *yaml.Node, parent string) (string, error) {
+	check := kebabCase(name.Value)
+	if alias, found := checkBlockAliases[name.Value]; found {
+		check = alias
+	}
+	prefix := check + "-checker-"
+	for _, k := range e.known {
+		if strings.HasPrefix(k, prefix) {
+			return prefix, nil
+		}
+	}
+
+	path := parent + ChecksKey + "."
+	names := e.blockNames()
+	if hint := suggest(name.Value, names); hint != "" {
+		return "", errors.Errorf("line %d: unknown check %q, did you mean %q?", name.Line, path+name.Value, path+hint)
+	}
+	return "", errors.Errorf("line %d: unknown check %q, checks with settings: %s", name.Line, path+name.Value, strings.Join(names, ", "))
+}
+
+// blockNames returns sorted names of blocks which have at least one known setting.
+func (e *blockExpander) blockNames() []string {
+	names := map[string]struct{}{}
+	for _, k := range e.known {
+		if check, _, found := strings.Cut(k, "-checker-"); found {
+			names[camelCase(check)] = struct{}{}
+		}
+	}
+
+	out := make([]string, 0, len(names))
+	for name := range names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// suggestSetting returns the camel case name of the known setting of a block which is the most similar to a given one.
+func suggestSetting(setting, prefix string, known []string) string {
+	var candidates []string
+	for _, k := range known {
+		if strings.HasPrefix(k, prefix) {
+			candidates = append(candidates, camelCase(strings.TrimPrefix(k, prefix)))
+		}
+	}
+	return suggest(setting, candidates)
+}
+
+// kebabCase converts a camel case name, e.g. `skipPatterns`, to the form of option keys, i.e. `skip-patterns`.
+// Names which are already in the kebab case are returned as they are.
+func kebabCase(in string) string {
+	var out strings.Builder
+	for i, r := range in {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				out.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// camelCase converts an option key, e.g. `skip-patterns`, to the camel case, i.e. `skipPatterns`.
+func camelCase(in string) string {
+	parts := strings.Split(in, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKnownKeys = []string{
+	"check-failure-level",
+	"checks",
+	"not-owned-checker-skip-patterns",
+	"not-owned-checker-subdirectories",
+	"owner-checker-allow-unowned-patterns",
+	"owner-checker-ignored-owners",
+}
+
+func TestExpandCheckBlocks(t *testing.T) {
+	tests := map[string]struct {
+		in         string
+		expOut     string
+		expOrigins map[string]string
+	}{
+		"Expands blocks into options": {
+			in: `check-failure-level: error
+checks:
+  enabled: [syntax, owners]
+  notOwned:
+    skipPatterns: [/vendor/]
+  validOwner:
+    ignoredOwners: ["@ghost"]
+    allowUnownedPatterns: false
+`,
+			expOut: `check-failure-level: error
+checks: [syntax, owners]
+not-owned-checker-skip-patterns: [/vendor/]
+owner-checker-ignored-owners: ["@ghost"]
+owner-checker-allow-unowned-patterns: false
+`,
+			expOrigins: map[string]string{
+				"not-owned-checker-skip-patterns":      "checks.notOwned.skipPatterns",
+				"owner-checker-ignored-owners":         "checks.validOwner.ignoredOwners",
+				"owner-checker-allow-unowned-patterns": "checks.validOwner.allowUnownedPatterns",
+			},
+		},
+		"Accepts check IDs and kebab case settings": {
+			in: `checks:
+  not-owned:
+    subdirectories: [services]
+  owners:
+    ignored-owners: ["@bot"]
+`,
+			expOut: `not-owned-checker-subdirectories: [services]
+owner-checker-ignored-owners: ["@bot"]
+`,
+			expOrigins: map[string]string{
+				"not-owned-checker-subdirectories": "checks.not-owned.subdirectories",
+				"owner-checker-ignored-owners":     "checks.owners.ignored-owners",
+			},
+		},
+		"Expands blocks of profiles": {
+			in: `profiles:
+  nightly:
+    checks:
+      notOwned:
+        skipPatterns: [/docs/]
+`,
+			expOut: `profiles:
+  nightly:
+    not-owned-checker-skip-patterns: [/docs/]
+`,
+			expOrigins: map[string]string{
+				"profiles.nightly.not-owned-checker-skip-patterns": "profiles.nightly.checks.notOwned.skipPatterns",
+			},
+		},
+		"Keeps the list of checks untouched": {
+			in:     "checks: [syntax, owners]\n",
+			expOut: "checks: [syntax, owners]\n",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			out, origins, err := config.ExpandCheckBlocks([]byte(tc.in), testKnownKeys)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOut, string(out))
+			assert.Equal(t, tc.expOrigins, origins)
+		})
+	}
+}
+
+func TestExpandCheckBlocksErrors(t *testing.T) {
+	tests := map[string]struct {
+		in     string
+		expErr string
+	}{
+		"Unknown setting": {
+			in: `checks:
+  notOwned:
+    skipPattern: [/vendor/]
+`,
+			expErr: `line 3: unknown setting "checks.notOwned.skipPattern", did you mean "checks.notOwned.skipPatterns"?`,
+		},
+		"Unknown check": {
+			in: `checks:
+  notOwnd:
+    skipPatterns: [/vendor/]
+`,
+			expErr: `line 2: unknown check "checks.notOwnd", did you mean "checks.notOwned"?`,
+		},
+		"Unknown check without similar names": {
+			in: `checks:
+  syntax:
+    strict: true
+`,
+			expErr: `line 2: unknown check "checks.syntax", checks with settings: notOwned, owner`,
+		},
+		"Block which is not a map": {
+			in: `checks:
+  notOwned: [/vendor/]
+`,
+			expErr: `line 2: "checks.notOwned" must be a map of settings of the check`,
+		},
+		"Setting also set as option": {
+			in: `owner-checker-ignored-owners: ["@ghost"]
+checks:
+  validOwner:
+    ignoredOwners: ["@bot"]
+`,
+			expErr: `line 4: "checks.validOwner.ignoredOwners" is already set by "owner-checker-ignored-owners" on line 1`,
+		},
+		"Unknown setting of profile": {
+			in: `profiles:
+  nightly:
+    checks:
+      validOwner:
+        ignored: ["@bot"]
+`,
+			expErr: `line 5: unknown setting "profiles.nightly.checks.validOwner.ignored"`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, _, err := config.ExpandCheckBlocks([]byte(tc.in), testKnownKeys)
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
@@ -1,29 +1,129 @@
 package config
 
-import "go.szostok.io/codeowners/internal/api"
+import (
+	"reflect"
+	"time"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/mitchellh/mapstructure"
+)
 
 const (
 	DefaultConfigFilename = "codeowners-config.yaml"
 	EnvPrefix             = "CODEOWNERS"
+	// ConfigFileKey is the option with the path to the config file, which is then not searched for.
+	ConfigFileKey = "config"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Checks                           []string         `mapstructure:"checks"`
-	CheckFailureLevel                api.SeverityType `mapstructure:"check-failure-level"`
-	ExperimentalChecks               []string         `mapstructure:"experimental-checks"`
-	GithubAccessToken                string           `mapstructure:"github-access-token"`
-	GithubBaseURL                    string           `mapstructure:"github-base-url"`
-	GithubUploadURL                  string           `mapstructure:"github-upload-url"`
-	GithubAppID                      int64            `mapstructure:"github-app-id"`
-	GithubAppInstallationID          int64            `mapstructure:"github-app-installation-id"`
-	GithubAppPrivateKey              string           `mapstructure:"github-app-private-key"`
-	NotOwnedCheckerSkipPatterns      []string         `mapstructure:"not-owned-checker-skip-patterns"`
-	NotOwnedCheckerSubdirectories    []string         `mapstructure:"not-owned-checker-subdirectories"`
-	NotOwnedCheckerTrustWorkspace    bool             `mapstructure:"not-owned-checker-trust-workspace"`
-	OwnerCheckerRepository           string           `mapstructure:"owner-checker-repository"`
-	OwnerCheckerIgnoredOwners        []string         `mapstructure:"owner-checker-ignored-owners"`
-	OwnerCheckerAllowUnownedPatterns bool             `mapstructure:"owner-checker-allow-unowned-patterns"`
-	OwnerCheckerOwnersMustBeTeams    bool             `mapstructure:"owner-checker-owners-must-be-teams"`
-	RepositoryPath                   string           `mapstructure:"repository-path"`
+	ApprovalCheckerBaseRef            string           `mapstructure:"approval-checker-base-ref"`
+	ApprovalCheckerPullRequestNumber  int              `mapstructure:"approval-checker-pull-request-number"`
+	Baseline                          string           `mapstructure:"baseline"`
+	BroadOwnershipCheckerThreshold    float64          `mapstructure:"broad-ownership-checker-threshold"`
+	Checks                            []string         `mapstructure:"checks"`
+	CodeownersFlavor                  string           `mapstructure:"codeowners-flavor"`
+	CheckFailureLevel                 api.SeverityType `mapstructure:"check-failure-level"`
+	DiffBaseRef                       string           `mapstructure:"diff-base-ref"`
+	EscalatePaths                     []string         `mapstructure:"escalate-paths"`
+	Events                            string           `mapstructure:"events"`
+	EventsFile                        string           `mapstructure:"events-file"`
+	ExcludePaths                      []string         `mapstructure:"exclude-path"`
+	ExperimentalChecks                []string         `mapstructure:"experimental-checks"`
+	ExpirationCheckerWarnBefore       time.Duration    `mapstructure:"expiration-checker-warn-before"`
+	FailOnNewOnly                     bool             `mapstructure:"fail-on-new-only"`
+	Fix                               bool             `mapstructure:"fix"`
+	FixJSON                           bool             `mapstructure:"fix-json"`
+	Format                            string           `mapstructure:"format"`
+	GitBackend                        string           `mapstructure:"git-backend"`
+	GithubAccessToken                 string           `mapstructure:"github-access-token"`
+	GithubErrorsCheckerRef            string           `mapstructure:"github-errors-checker-ref"`
+	GovernanceCheckerPaths            []string         `mapstructure:"governance-checker-paths"`
+	GovernanceCheckerTeam             string           `mapstructure:"governance-checker-team"`
+	GroupIssues                       bool             `mapstructure:"group-issues"`
+	GithubBaseURL                     string           `mapstructure:"github-base-url"`
+	GithubUploadURL                   string           `mapstructure:"github-upload-url"`
+	GithubAppID                       int64            `mapstructure:"github-app-id"`
+	GithubAppInstallationID           int64            `mapstructure:"github-app-installation-id"`
+	GithubAppPrivateKey               string           `mapstructure:"github-app-private-key"`
+	GithubCacheFile                   string           `mapstructure:"github-cache-file"`
+	GithubCacheMaxAge                 time.Duration    `mapstructure:"github-cache-max-age"`
+	HTTPHeaders                       []string         `mapstructure:"http-headers"`
+	HTTPUserAgent                     string           `mapstructure:"http-user-agent"`
+	IdentityCheckerSource             string           `mapstructure:"identity-checker-source"`
+	IdentityCheckerSourceToken        string           `mapstructure:"identity-checker-source-token"`
+	IdentityCheckerSourceType         string           `mapstructure:"identity-checker-source-type"`
+	IncludePaths                      []string         `mapstructure:"include-path"`
+	Isolation                         string           `mapstructure:"isolation"`
+	JiraCheckerBaseURL                string           `mapstructure:"jira-checker-base-url"`
+	JiraCheckerMapping                string           `mapstructure:"jira-checker-mapping"`
+	JiraCheckerToken                  string           `mapstructure:"jira-checker-token"`
+	JiraCheckerUser                   string           `mapstructure:"jira-checker-user"`
+	IsolationImage                    string           `mapstructure:"isolation-image"`
+	LabelerCheckerFile                string           `mapstructure:"labeler-checker-file"`
+	LabelerCheckerLabelPrefix         string           `mapstructure:"labeler-checker-label-prefix"`
+	ModuleBoundariesCheckerEcosystems []string         `mapstructure:"module-boundaries-checker-ecosystems"`
+	NoBaseline                        bool             `mapstructure:"no-baseline"`
+	NoGit                             bool             `mapstructure:"no-git"`
+	NoNetwork                         bool             `mapstructure:"no-network"`
+	NoSkipCache                       bool             `mapstructure:"no-skip-cache"`
+	NotOwnedCheckerIgnoreSources      []string         `mapstructure:"not-owned-checker-ignore-sources"`
+	NotOwnedCheckerSkipGenerated      bool             `mapstructure:"not-owned-checker-skip-generated"`
+	NotOwnedCheckerSkipPatterns       []string         `mapstructure:"not-owned-checker-skip-patterns"`
+	NotOwnedCheckerSubdirectories     []string         `mapstructure:"not-owned-checker-subdirectories"`
+	NotOwnedCheckerSuggestOwners      bool             `mapstructure:"not-owned-checker-suggest-owners"`
+	NotOwnedCheckerTrustWorkspace     bool             `mapstructure:"not-owned-checker-trust-workspace"`
+	OnCallCheckerMapping              string           `mapstructure:"oncall-checker-mapping"`
+	OwnerCheckerRepository            string           `mapstructure:"owner-checker-repository"`
+	OwnerCheckerIgnoredOwners         []string         `mapstructure:"owner-checker-ignored-owners"`
+	OwnerCheckerAllowUnownedPatterns  bool             `mapstructure:"owner-checker-allow-unowned-patterns"`
+	OwnerCheckerOwnersMustBeTeams     bool             `mapstructure:"owner-checker-owners-must-be-teams"`
+	OwnerCheckerPlatform              string           `mapstructure:"owner-checker-platform"`
+	OwnershipSLACheckerGracePeriod    time.Duration    `mapstructure:"ownership-sla-checker-grace-period"`
+	Providers                         []string         `mapstructure:"providers"`
+	Record                            string           `mapstructure:"record"`
+	Replay                            string           `mapstructure:"replay"`
+	RepositoryPath                    string           `mapstructure:"repository-path"`
+	SkipCacheDir                      string           `mapstructure:"skip-cache-dir"`
+	StewardshipCheckerExtensions      []string         `mapstructure:"stewardship-checker-extensions"`
+	StewardshipCheckerSizeThreshold   int64            `mapstructure:"stewardship-checker-size-threshold"`
+	StewardshipCheckerTeam            string           `mapstructure:"stewardship-checker-team"`
+	Summary                           string           `mapstructure:"summary"`
+	TemplateValues                    string           `mapstructure:"template-values"`
+	Tree                              string           `mapstructure:"tree"`
+	VCSBaseURL                        string           `mapstructure:"vcs-base-url"`
+	VCSProvider                       string           `mapstructure:"vcs-provider"`
+	VCSToken                          string           `mapstructure:"vcs-token"`
+	VirtualOwners                     []string         `mapstructure:"virtual-owners"`
+}
+
+// WithoutSecrets returns a copy of the configuration without credentials, e.g. to be passed to isolated checks.
+// HTTP headers are removed too, as they usually carry credentials.
+func (c Config) WithoutSecrets() Config {
+	c.GithubAccessToken, c.GithubAppPrivateKey, c.IdentityCheckerSourceToken, c.JiraCheckerToken, c.VCSToken = "", "", "", "", ""
+	c.HTTPHeaders = nil
+	return c
+}
+
+// DecodeHook returns the hook which decodes configuration values from their string form,
+// e.g. durations, comma-separated lists, and severities.
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToSeverityHookFunc,
+	)
+}
+
+func stringToSeverityHookFunc(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(api.SeverityType(0)) {
+		return data, nil
+	}
+
+	var severity api.SeverityType
+	if err := severity.Unmarshal(data.(string)); err != nil {
+		return nil, &api.ConfigError{Field: "CHECK_FAILURE_LEVEL", Err: err}
+	}
+	return severity, nil
 }
@@ -0,0 +1,36 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHook(t *testing.T) {
+	// given
+	var cfg config.Config
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: config.DecodeHook(),
+		Result:     &cfg,
+	})
+	require.NoError(t, err)
+
+	// when
+	err = dec.Decode(map[string]interface{}{
+		"check-failure-level":            "error",
+		"experimental-checks":            "not-owned,owner-casing",
+		"expiration-checker-warn-before": "72h",
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, api.Error, cfg.CheckFailureLevel)
+	assert.Equal(t, []string{"not-owned", "owner-casing"}, cfg.ExperimentalChecks)
+	assert.Equal(t, 72*time.Hour, cfg.ExpirationCheckerWarnBefore)
+}
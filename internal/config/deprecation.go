@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of deprecated names.
+const (
+	KindOption = "option"
+	KindCheck  = "check"
+)
+
+// Rename maps the previous name of an option or a check ID to the current one.
+type Rename struct {
+	Old string
+	New string
+}
+
+// Renames holds options and check IDs that were renamed. Old names are still accepted, but reported as deprecated.
+type Renames struct {
+	// Options are config keys, e.g. `experimental-checks`. The environment variables are renamed accordingly.
+	Options []Rename
+	// Checks are IDs used in the `checks` and `experimental-checks` options.
+	Checks []Rename
+}
+
+// Deprecated holds all renames. When renaming an option or a check, add the previous name here,
+// so existing config files, environment variables, and check selections keep working.
+var Deprecated = Renames{
+	Checks: []Rename{
+		{Old: "notowned", New: "not-owned"},
+	},
+}
+
+// Deprecation describes a single usage of a deprecated name.
+type Deprecation struct {
+	// Kind is either KindOption or KindCheck.
+	Kind string
+	Old  string
+	New  string
+}
+
+func (d Deprecation) String() string {
+	return fmt.Sprintf("%s %q is deprecated, use %q instead", d.Kind, d.Old, d.New)
+}
+
+// EnvName returns the environment variable name of a given config key, e.g. CODEOWNERS_EXPERIMENTAL_CHECKS.
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// MigrateChecks replaces deprecated check IDs with the current ones.
+func (r Renames) MigrateChecks(ids []string) ([]string, []Deprecation) {
+	var (
+		out  = make([]string, 0, len(ids))
+		deps []Deprecation
+	)
+	for _, id := range ids {
+		if renamed, found := find(r.Checks, id); found {
+			deps = append(deps, Deprecation{Kind: KindCheck, Old: id, New: renamed})
+			id = renamed
+		}
+		out = append(out, id)
+	}
+	return out, deps
+}
+
+// MigrateYAML rewrites the config file content, so it uses only the current option names and check IDs.
+// Both the top-level settings and the settings of profiles are migrated. Comments and the order of keys are preserved.
+func (r Renames) MigrateYAML(in []byte) ([]byte, []Deprecation, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(in, &doc); err != nil {
+		return nil, nil, errors.Wrap(err, "while parsing config file")
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return in, nil, nil
+	}
+
+	root := doc.Content[0]
+	deps := r.migrateSettings(root)
+	for idx := 0; idx+1 < len(root.Content); idx += 2 {
+		if root.Content[idx].Value != ProfilesKey || root.Content[idx+1].Kind != yaml.MappingNode {
+			continue
+		}
+		profiles := root.Content[idx+1]
+		for p := 1; p < len(profiles.Content); p += 2 {
+			if profiles.Content[p].Kind == yaml.MappingNode {
+				deps = append(deps, r.migrateSettings(profiles.Content[p])...)
+			}
+		}
+	}
+	if len(deps) == 0 {
+		return in, nil, nil
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, errors.Wrap(err, "while encoding config file")
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, errors.Wrap(err, "while encoding config file")
+	}
+	return out.Bytes(), deps, nil
+}
+
+// migrateSettings renames keys and check IDs of a single mapping of settings.
+func (r Renames) migrateSettings(settings *yaml.Node) []Deprecation {
+	var deps []Deprecation
+	for idx := 0; idx+1 < len(settings.Content); idx += 2 {
+		key, value := settings.Content[idx], settings.Content[idx+1]
+		if renamed, found := find(r.Options, key.Value); found {
+			deps = append(deps, Deprecation{Kind: KindOption, Old: key.Value, New: renamed})
+			key.Value = renamed
+		}
+		if key.Value != "checks" && key.Value != "experimental-checks" {
+			continue
+		}
+
+		switch value.Kind {
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if renamed, found := find(r.Checks, item.Value); found {
+					deps = append(deps, Deprecation{Kind: KindCheck, Old: item.Value, New: renamed})
+					item.Value = renamed
+				}
+			}
+		case yaml.ScalarNode: // comma-separated list
+			ids, checkDeps := r.MigrateChecks(strings.Split(value.Value, ","))
+			if len(checkDeps) > 0 {
+				deps = append(deps, checkDeps...)
+				value.Value = strings.Join(ids, ",")
+			}
+		}
+	}
+	return deps
+}
+
+func find(renames []Rename, old string) (string, bool) {
+	for _, r := range renames {
+		if r.Old == strings.TrimSpace(old) {
+			return r.New, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRenames = config.Renames{
+	Options: []config.Rename{{Old: "owners-ignored", New: "owner-checker-ignored-owners"}},
+	Checks:  []config.Rename{{Old: "notowned", New: "not-owned"}},
+}
+
+func TestMigrateChecks(t *testing.T) {
+	// when
+	ids, deps := testRenames.MigrateChecks([]string{"syntax", "notowned"})
+
+	// then
+	assert.Equal(t, []string{"syntax", "not-owned"}, ids)
+	assert.Equal(t, []config.Deprecation{{Kind: config.KindCheck, Old: "notowned", New: "not-owned"}}, deps)
+}
+
+func TestMigrateYAML(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		expOut  string
+		expDeps []config.Deprecation
+	}{
+		"Renames options and check IDs in settings and profiles": {
+			in: `# shared settings
+owners-ignored: [ "@ghost" ]
+experimental-checks: notowned,owner-casing # all files must be owned
+profiles:
+  nightly:
+    experimental-checks:
+      - notowned
+      - stale-teams
+    owners-ignored: [ "@bot" ]
+`,
+			expOut: `# shared settings
+owner-checker-ignored-owners: ["@ghost"]
+experimental-checks: not-owned,owner-casing # all files must be owned
+profiles:
+  nightly:
+    experimental-checks:
+      - not-owned
+      - stale-teams
+    owner-checker-ignored-owners: ["@bot"]
+`,
+			expDeps: []config.Deprecation{
+				{Kind: config.KindOption, Old: "owners-ignored", New: "owner-checker-ignored-owners"},
+				{Kind: config.KindCheck, Old: "notowned", New: "not-owned"},
+				{Kind: config.KindCheck, Old: "notowned", New: "not-owned"},
+				{Kind: config.KindOption, Old: "owners-ignored", New: "owner-checker-ignored-owners"},
+			},
+		},
+		"Keeps up-to-date file untouched": {
+			in:     "checks:   [syntax]   # only syntax\n",
+			expOut: "checks:   [syntax]   # only syntax\n",
+		},
+		"Keeps empty file untouched": {
+			in:     "",
+			expOut: "",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			out, deps, err := testRenames.MigrateYAML([]byte(tc.in))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOut, string(out))
+			assert.Equal(t, tc.expDeps, deps)
+		})
+	}
+}
+
+func TestMigrateYAMLInvalidFile(t *testing.T) {
+	// when
+	_, _, err := testRenames.MigrateYAML([]byte("checks: [syntax"))
+
+	// then
+	assert.ErrorContains(t, err, "while parsing config file")
+}
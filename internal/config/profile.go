@@ -0,0 +1,93 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// ProfilesKey is the config file key under which named profiles are defined, e.g.:
+//
+//	checks: [syntax, duppatterns, owners]
+//	profiles:
+//	  nightly:
+//	    experimental-checks: [not-owned, stale-teams]
+//	    check-failure-level: warning
+const ProfilesKey = "profiles"
+
+// builtinProfiles are available without any config file. Profiles with the same name defined in the
+// config file extend them.
+var builtinProfiles = map[string]map[string]interface{}{
+	// strict treats warnings as failures and requires every file to be owned.
+	"strict": {
+		"check-failure-level":                  "warning",
+		"experimental-checks":                  "not-owned",
+		"owner-checker-allow-unowned-patterns": false,
+	},
+	// relaxed fails only on errors.
+	"relaxed": {
+		"check-failure-level":                  "error",
+		"owner-checker-allow-unowned-patterns": true,
+	},
+	// ci runs on ephemeral runners, where the verdict cache is lost between runs anyway.
+	"ci": {
+		"check-failure-level": "warning",
+		"no-skip-cache":       true,
+	},
+	// local runs only the fast offline checks, e.g. in a pre-commit hook.
+	"local": {
+		"checks":     "syntax,duppatterns,files",
+		"no-network": true,
+	},
+}
+
+// ResolveProfile returns settings of a given profile. Settings from the config file profile with the same
+// name as a built-in one take precedence over the built-in settings.
+func ResolveProfile(name string, fileProfiles map[string]interface{}) (map[string]interface{}, error) {
+	builtin, isBuiltin := builtinProfiles[name]
+	raw, inFile := fileProfiles[name]
+	if !isBuiltin && !inFile {
+		return nil, &api.ConfigError{
+			Field: "PROFILE",
+			Err:   errors.Errorf("unknown profile %q, available: %s", name, strings.Join(profileNames(fileProfiles), ", ")),
+		}
+	}
+
+	out := map[string]interface{}{}
+	for k, v := range builtin {
+		out[k] = v
+	}
+
+	if !inFile || raw == nil {
+		return out, nil
+	}
+	fromFile, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, &api.ConfigError{Field: "PROFILE", Err: errors.Errorf("profile %q must be a map of settings, got %T", name, raw)}
+	}
+	for k, v := range fromFile {
+		out[strings.ToLower(k)] = v
+	}
+
+	return out, nil
+}
+
+func profileNames(fileProfiles map[string]interface{}) []string {
+	names := map[string]struct{}{}
+	for name := range builtinProfiles {
+		names[name] = struct{}{}
+	}
+	for name := range fileProfiles {
+		names[name] = struct{}{}
+	}
+
+	out := make([]string, 0, len(names))
+	for name := range names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProfile(t *testing.T) {
+	fileProfiles := map[string]interface{}{
+		"nightly": map[string]interface{}{
+			"experimental-checks": []interface{}{"not-owned", "stale-teams"},
+		},
+		"local": map[string]interface{}{
+			"Checks": []interface{}{"syntax"},
+		},
+	}
+
+	tests := map[string]struct {
+		name        string
+		expSettings map[string]interface{}
+	}{
+		"Built-in profile": {
+			name: "relaxed",
+			expSettings: map[string]interface{}{
+				"check-failure-level":                  "error",
+				"owner-checker-allow-unowned-patterns": true,
+			},
+		},
+		"Profile defined in the config file": {
+			name: "nightly",
+			expSettings: map[string]interface{}{
+				"experimental-checks": []interface{}{"not-owned", "stale-teams"},
+			},
+		},
+		"Config file extends the built-in profile": {
+			name: "local",
+			expSettings: map[string]interface{}{
+				"checks":     []interface{}{"syntax"},
+				"no-network": true,
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			settings, err := config.ResolveProfile(tc.name, fileProfiles)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expSettings, settings)
+		})
+	}
+}
+
+func TestResolveProfileErrors(t *testing.T) {
+	tests := map[string]struct {
+		name         string
+		fileProfiles map[string]interface{}
+		expErr       string
+	}{
+		"Unknown profile": {
+			name:         "nope",
+			fileProfiles: map[string]interface{}{"nightly": nil},
+			expErr:       `unknown profile "nope", available: ci, local, nightly, relaxed, strict`,
+		},
+		"Malformed profile": {
+			name:         "nightly",
+			fileProfiles: map[string]interface{}{"nightly": "strict"},
+			expErr:       `profile "nightly" must be a map of settings, got string`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := config.ResolveProfile(tc.name, tc.fileProfiles)
+
+			// then
+			var cfgErr *api.ConfigError
+			require.ErrorAs(t, err, &cfgErr)
+			assert.Equal(t, "PROFILE", cfgErr.Field)
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
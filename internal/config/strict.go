@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// StrictKey is the option which turns unknown config file keys and environment variables into errors.
+const StrictKey = "strict-config"
+
+// Sources of unknown settings.
+const (
+	SourceConfigFile  = "config file key"
+	SourceEnvironment = "environment variable"
+)
+
+// UnknownSetting is a config file key or an environment variable which doesn't match any option.
+type UnknownSetting struct {
+	// Name is the config file key, e.g. `chekcs`, or the environment variable name, e.g. `CODEOWNERS_CHEKS`.
+	Name string
+	// Source is either SourceConfigFile or SourceEnvironment.
+	Source string
+	// Suggestion is the most similar known name in the same form as Name, empty if nothing is similar enough.
+	Suggestion string
+}
+
+func (u UnknownSetting) String() string {
+	msg := fmt.Sprintf("unknown %s %q", u.Source, u.Name)
+	if u.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", u.Suggestion)
+	}
+	return msg
+}
+
+// KnownKeys returns keys of all options of the Config struct together with the given extra keys,
+// e.g. names of command-specific flags.
+func KnownKeys(extra ...string) []string {
+	keys := map[string]struct{}{
+		"profile":     {},
+		ProfilesKey:   {},
+		StrictKey:     {},
+		ConfigFileKey: {},
+	}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("mapstructure"); tag != "" {
+			keys[tag] = struct{}{}
+		}
+	}
+	for _, r := range Deprecated.Options {
+		keys[r.Old] = struct{}{}
+	}
+	for _, k := range extra {
+		keys[k] = struct{}{}
+	}
+
+	out := make([]string, 0, len(keys))
+	for k := range keys {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// FindUnknown returns config file keys and environment variables with the EnvPrefix which don't match any of the known keys.
+// Config file keys are in the viper form, i.e. nested keys are joined with dots. Settings of profiles, e.g.
+// `profiles.nightly.checks`, are verified too. The environ is in the `os.Environ` form.
+func FindUnknown(known, fileKeys, environ []string) []UnknownSetting {
+	isKnown := map[string]struct{}{}
+	for _, k := range known {
+		isKnown[k] = struct{}{}
+	}
+
+	var (
+		out      []UnknownSetting
+		reported = map[string]struct{}{}
+	)
+	for _, key := range fileKeys {
+		parent, setting := "", strings.SplitN(key, ".", 2)[0]
+		if setting == ProfilesKey {
+			// profiles.<name>.<setting>
+			parts := strings.SplitN(key, ".", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			parent, setting = parts[0]+"."+parts[1]+".", strings.SplitN(parts[2], ".", 2)[0]
+		}
+		if _, found := isKnown[setting]; found {
+			continue
+		}
+		// nested values of an unknown key are reported once
+		name := parent + setting
+		if _, found := reported[name]; found {
+			continue
+		}
+		reported[name] = struct{}{}
+
+		u := UnknownSetting{Name: name, Source: SourceConfigFile}
+		if s := suggest(setting, known); s != "" {
+			u.Suggestion = parent + s
+		}
+		out = append(out, u)
+	}
+
+	prefix := EnvPrefix + "_"
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, prefix), "_", "-"))
+		if _, found := isKnown[key]; found {
+			continue
+		}
+
+		u := UnknownSetting{Name: name, Source: SourceEnvironment}
+		if s := suggest(key, known); s != "" {
+			u.Suggestion = prefix + strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+		}
+		out = append(out, u)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Source != out[j].Source {
+			return out[i].Source < out[j].Source
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// suggest returns the known key with the smallest edit distance, if it's close enough to be a typo.
+func suggest(key string, known []string) string {
+	var (
+		best     string
+		bestDist = len(key)/3 + 1
+	)
+	for _, k := range known {
+		if d := editDistance(key, k); d <= bestDist && (best == "" || d < bestDist) {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance between two strings, i.e. the Levenshtein distance
+// in which swapping two adjacent characters, the most common typo, counts as a single edit.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(first int, rest ...int) int {
+	out := first
+	for _, v := range rest {
+		if v < out {
+			out = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUnknown(t *testing.T) {
+	known := config.KnownKeys("format")
+
+	tests := map[string]struct {
+		fileKeys   []string
+		environ    []string
+		expUnknown []config.UnknownSetting
+	}{
+		"Known settings": {
+			fileKeys: []string{"checks", "profiles.nightly.experimental-checks", "profile", "format"},
+			environ:  []string{"CODEOWNERS_REPOSITORY_PATH=.", "CODEOWNERS_STRICT_CONFIG=true", "HOME=/root"},
+		},
+		"Typos are reported with suggestions": {
+			fileKeys: []string{"chekcs", "profiles.nightly.experimantal-checks"},
+			environ:  []string{"CODEOWNERS_CHEKS=files", "CODEOWNERS_GITHUB_ACESS_TOKEN=secret"},
+			expUnknown: []config.UnknownSetting{
+				{Name: "chekcs", Source: config.SourceConfigFile, Suggestion: "checks"},
+				{Name: "profiles.nightly.experimantal-checks", Source: config.SourceConfigFile, Suggestion: "profiles.nightly.experimental-checks"},
+				{Name: "CODEOWNERS_CHEKS", Source: config.SourceEnvironment, Suggestion: "CODEOWNERS_CHECKS"},
+				{Name: "CODEOWNERS_GITHUB_ACESS_TOKEN", Source: config.SourceEnvironment, Suggestion: "CODEOWNERS_GITHUB_ACCESS_TOKEN"},
+			},
+		},
+		"Unknown nested keys are reported once without suggestions": {
+			fileKeys: []string{"jira.url", "jira.token"},
+			environ:  []string{"CODEOWNERS_SOMETHING_ELSE=1"},
+			expUnknown: []config.UnknownSetting{
+				{Name: "jira", Source: config.SourceConfigFile},
+				{Name: "CODEOWNERS_SOMETHING_ELSE", Source: config.SourceEnvironment},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			unknown := config.FindUnknown(known, tc.fileKeys, tc.environ)
+
+			// then
+			assert.Equal(t, tc.expUnknown, unknown)
+		})
+	}
+}
+
+func TestUnknownSettingString(t *testing.T) {
+	// given
+	withSuggestion := config.UnknownSetting{Name: "CODEOWNERS_CHEKS", Source: config.SourceEnvironment, Suggestion: "CODEOWNERS_CHECKS"}
+	withoutSuggestion := config.UnknownSetting{Name: "jira", Source: config.SourceConfigFile}
+
+	// then
+	assert.Equal(t, `unknown environment variable "CODEOWNERS_CHEKS", did you mean "CODEOWNERS_CHECKS"?`, withSuggestion.String())
+	assert.Equal(t, `unknown config file key "jira"`, withoutSuggestion.String())
+}
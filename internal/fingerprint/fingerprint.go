@@ -0,0 +1,228 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/identity"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"go.szostok.io/version"
+)
+
+// ErrDirtyWorkingTree is returned when the fingerprint cannot be computed because of uncommitted changes.
+var ErrDirtyWorkingTree = errors.New("working tree contains uncommitted changes")
+
+// ErrExternalState is returned when enabled checks read the state of external services, which is not covered
+// by the fingerprint, so their verdict cannot be cached.
+var ErrExternalState = errors.New("enabled checks depend on the state of external services")
+
+// treeChecks inspect files tracked in the repository, so their results depend on the whole tree.
+var treeChecks = []string{
+	"files", "not-owned", "path-hazards", "expensive-patterns", "approvals", "stewardship", "module-boundaries", "labeler",
+	"ownership-sla", "broad-ownership", "governance",
+}
+
+// defaultChecks are executed if a user does not specify concrete checks.
+var defaultChecks = []string{"syntax", "dialects", "duppatterns", "files", "owners"}
+
+// externalChecks read the state of external services, e.g. GitHub teams or Jira components.
+var externalChecks = []string{"owners", "stale-teams", "identities", "jira", "github-errors", "approvals"}
+
+// dateChecks report issues which depend on the current date, e.g. expired entries.
+var dateChecks = []string{"expiration", "ownership-sla"}
+
+// envs are read directly by checks, so they affect the validation result.
+var envs = []string{"NOT_OWNED_CHECKER_SKIP_PATTERNS", "NOT_OWNED_CHECKER_SUBDIRECTORIES"}
+
+// Compute returns the fingerprint of everything that affects the validation result:
+//   - the CODEOWNERS blob SHA,
+//   - the HEAD tree SHA, only if enabled checks inspect repository files,
+//   - the configuration hash, without credentials and the repository path,
+//   - the baseline file content, if it's used,
+//   - the content of files read by enabled checks, e.g. the on-call mapping or the local identity source,
+//   - the codeowners version,
+//   - the current UTC date, only if enabled checks depend on it, so the verdict is cached for a day.
+//
+// It returns the ErrExternalState if enabled checks read the state of external services.
+func Compute(repoDir string, cfg config.Config, now time.Time) (string, error) {
+	// checks which need the network are skipped or rejected if it's disabled
+	if !cfg.NoNetwork && dependsOn(cfg, externalChecks) {
+		return "", ErrExternalState
+	}
+
+	file, err := codeowners.FindCodeownersFile(repoDir)
+	if err != nil {
+		return "", err
+	}
+	blob, err := git.HashObject(repoDir, file)
+	if err != nil {
+		return "", errors.Wrap(err, "while hashing CODEOWNERS file")
+	}
+
+	var tree string
+	if dependsOn(cfg, treeChecks) {
+		dirty, err := git.IsDirty(repoDir)
+		if err != nil {
+			return "", errors.Wrap(err, "while checking git status")
+		}
+		if dirty {
+			return "", ErrDirtyWorkingTree
+		}
+		tree, err = git.TreeSHA(repoDir)
+		if err != nil {
+			return "", errors.Wrap(err, "while getting HEAD tree")
+		}
+	}
+
+	cfgHash, err := configHash(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// template values may be stored outside the repository, so they are not covered by the tree
+	var values string
+	if cfg.TemplateValues != "" {
+		raw, err := os.ReadFile(cfg.TemplateValues)
+		if err != nil {
+			return "", errors.Wrap(err, "while reading template values")
+		}
+		values = fmt.Sprintf("%x", sha256.Sum256(raw))
+	}
+
+	// the baseline decides which issues fail the validation, and it may be not committed yet
+	var known string
+	if !cfg.NoBaseline {
+		path, err := baseline.Find(repoDir, cfg.Baseline)
+		if err != nil {
+			return "", errors.Wrap(err, "while finding baseline")
+		}
+		if path != "" {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return "", errors.Wrap(err, "while reading baseline")
+			}
+			known = fmt.Sprintf("%x", sha256.Sum256(raw))
+		}
+	}
+
+	// input files may be stored outside the repository too
+	inputs := sha256.New()
+	for _, path := range inputFiles(cfg) {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "while reading %s", path)
+		}
+		fmt.Fprintf(inputs, "%s %x\n", path, sha256.Sum256(raw))
+	}
+
+	var date string
+	if dependsOn(cfg, dateChecks) {
+		date = now.UTC().Format("2006-01-02")
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "version %s\nblob %s\ntree %s\nconfig %s\nvalues %s\nbaseline %s\ninputs %x\ndate %s\n",
+		version.Get().Version, blob, tree, cfgHash, values, known, inputs.Sum(nil), date)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// inputFiles returns paths of files read by enabled checks.
+func inputFiles(cfg config.Config) []string {
+	var out []string
+	if dependsOn(cfg, []string{"oncall"}) && cfg.OnCallCheckerMapping != "" {
+		out = append(out, cfg.OnCallCheckerMapping)
+	}
+	// remote identity sources are external state, which is rejected unless the network is disabled
+	source := identity.Config{Type: identity.SourceType(cfg.IdentityCheckerSourceType), Location: cfg.IdentityCheckerSource}
+	if dependsOn(cfg, []string{"identities"}) && cfg.IdentityCheckerSource != "" && !source.IsRemote() {
+		out = append(out, cfg.IdentityCheckerSource)
+	}
+	return out
+}
+
+// dependsOn returns true if any of given checks is enabled.
+func dependsOn(cfg config.Config, checks []string) bool {
+	for _, name := range checks {
+		// if a user does not specify concrete checks then all default checks are enabled
+		if len(cfg.Checks) == 0 && contains(defaultChecks, name) {
+			return true
+		}
+		if contains(cfg.Checks, name) || contains(cfg.ExperimentalChecks, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func configHash(cfg config.Config) (string, error) {
+	// credentials can be rotated and the repository can be cloned into a different path without changing the result
+	cfg = cfg.WithoutSecrets()
+	cfg.RepositoryPath = ""
+	// the cache settings don't change the result
+	cfg.NoSkipCache, cfg.SkipCacheDir = false, ""
+	cfg.GithubCacheFile, cfg.GithubCacheMaxAge = "", 0
+
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return "", errors.Wrap(err, "while marshaling configuration")
+	}
+
+	h := sha256.New()
+	h.Write(raw)
+	for _, env := range envs {
+		fmt.Fprintf(h, "\n%s=%s", env, os.Getenv(env))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func contains(checks []string, name string) bool {
+	for _, c := range checks {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Cache stores fingerprints of repositories that passed the validation.
+type Cache struct {
+	dir string
+}
+
+// NewCache returns new instance of the Cache that stores fingerprints in a given directory.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// DefaultCacheDir returns the cache directory in the user cache directory.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "codeowners", "fingerprints"), nil
+}
+
+// Passed returns true if the validation of a given fingerprint already passed.
+func (c *Cache) Passed(fingerprint string) bool {
+	_, err := os.Stat(filepath.Join(c.dir, fingerprint))
+	return err == nil
+}
+
+// MarkPassed stores a given fingerprint as passed.
+func (c *Cache) MarkPassed(fingerprint string) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, fingerprint), []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0o644)
+}
@@ -0,0 +1,212 @@
+package fingerprint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/fingerprint"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	syntaxOnly := config.Config{Checks: []string{"syntax"}, GithubAccessToken: "token"}
+	withFiles := config.Config{Checks: []string{"syntax", "files"}}
+	withBroadOwnership := config.Config{Checks: []string{"syntax"}, ExperimentalChecks: []string{"broad-ownership"}}
+
+	// input files are stored outside the repository
+	inputs := t.TempDir()
+	mapping, identities := filepath.Join(inputs, "oncall.yaml"), filepath.Join(inputs, "identities.csv")
+	require.NoError(t, os.WriteFile(mapping, []byte("'@org/docs': docs-rotation\n"), 0o644))
+	require.NoError(t, os.WriteFile(identities, []byte("login,email,active\nalice,alice@example.com,true\n"), 0o644))
+	withOnCall := config.Config{Checks: []string{"syntax"}, ExperimentalChecks: []string{"oncall"}, OnCallCheckerMapping: mapping}
+	withIdentities := config.Config{
+		Checks:                    []string{"syntax"},
+		ExperimentalChecks:        []string{"identities"},
+		IdentityCheckerSource:     identities,
+		IdentityCheckerSourceType: "csv",
+		NoNetwork:                 true,
+	}
+
+	tests := map[string]struct {
+		cfg       config.Config
+		change    func(t *testing.T, dir string) config.Config
+		expChange bool
+	}{
+		"Nothing changed": {
+			cfg: withFiles,
+			change: func(t *testing.T, dir string) config.Config {
+				return withFiles
+			},
+			expChange: false,
+		},
+		"CODEOWNERS changed": {
+			cfg: syntaxOnly,
+			change: func(t *testing.T, dir string) config.Config {
+				gitrepo.CommitFiles(t, dir, map[string]string{"CODEOWNERS": "* @org/platform\n"})
+				return syntaxOnly
+			},
+			expChange: true,
+		},
+		"Other file changed when only CODEOWNERS is validated": {
+			cfg: syntaxOnly,
+			change: func(t *testing.T, dir string) config.Config {
+				gitrepo.CommitFiles(t, dir, map[string]string{"main.go": "package main\n"})
+				return syntaxOnly
+			},
+			expChange: false,
+		},
+		"Other file changed when repository files are validated": {
+			cfg: withFiles,
+			change: func(t *testing.T, dir string) config.Config {
+				gitrepo.CommitFiles(t, dir, map[string]string{"main.go": "package main\n"})
+				return withFiles
+			},
+			expChange: true,
+		},
+		"Other file changed when the ownership breadth is validated": {
+			cfg: withBroadOwnership,
+			change: func(t *testing.T, dir string) config.Config {
+				gitrepo.CommitFiles(t, dir, map[string]string{"main.go": "package main\n"})
+				return withBroadOwnership
+			},
+			expChange: true,
+		},
+		"On-call mapping changed": {
+			cfg: withOnCall,
+			change: func(t *testing.T, dir string) config.Config {
+				require.NoError(t, os.WriteFile(mapping, []byte("'@org/docs': other-rotation\n"), 0o644))
+				return withOnCall
+			},
+			expChange: true,
+		},
+		"Local identity source changed": {
+			cfg: withIdentities,
+			change: func(t *testing.T, dir string) config.Config {
+				require.NoError(t, os.WriteFile(identities, []byte("login,email,active\nalice,alice@example.com,false\n"), 0o644))
+				return withIdentities
+			},
+			expChange: true,
+		},
+		"Token and repository path changed": {
+			cfg: syntaxOnly,
+			change: func(t *testing.T, dir string) config.Config {
+				cfg := syntaxOnly
+				cfg.GithubAccessToken, cfg.RepositoryPath = "rotated", "/other/path"
+				return cfg
+			},
+			expChange: false,
+		},
+		"Configuration changed": {
+			cfg: syntaxOnly,
+			change: func(t *testing.T, dir string) config.Config {
+				cfg := syntaxOnly
+				cfg.Providers = []string{"gitlab"}
+				return cfg
+			},
+			expChange: true,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			dir := gitrepo.New(t)
+			gitrepo.CommitFiles(t, dir, map[string]string{"CODEOWNERS": "* @org/docs\n", "README.md": "# test\n"})
+
+			before, err := fingerprint.Compute(dir, tc.cfg, now)
+			require.NoError(t, err)
+
+			// when
+			cfg := tc.change(t, dir)
+			after, err := fingerprint.Compute(dir, cfg, now)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expChange, before != after)
+		})
+	}
+}
+
+func TestComputeDirtyWorkingTree(t *testing.T) {
+	// given
+	dir := gitrepo.New(t)
+	gitrepo.CommitFiles(t, dir, map[string]string{"CODEOWNERS": "* @org/docs\n"})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main\n"), 0o600))
+
+	// when
+	_, err := fingerprint.Compute(dir, config.Config{NoNetwork: true}, time.Now())
+
+	// then
+	assert.ErrorIs(t, err, fingerprint.ErrDirtyWorkingTree)
+}
+
+func TestComputeExternalState(t *testing.T) {
+	tests := map[string]struct {
+		cfg config.Config
+	}{
+		"Default checks": {
+			cfg: config.Config{},
+		},
+		"Owners check": {
+			cfg: config.Config{Checks: []string{"syntax", "owners"}},
+		},
+		"Experimental check": {
+			cfg: config.Config{Checks: []string{"syntax"}, ExperimentalChecks: []string{"stale-teams"}},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			dir := gitrepo.New(t)
+			gitrepo.CommitFiles(t, dir, map[string]string{"CODEOWNERS": "* @org/docs\n"})
+
+			// when
+			_, err := fingerprint.Compute(dir, tc.cfg, time.Now())
+
+			// then
+			assert.ErrorIs(t, err, fingerprint.ErrExternalState)
+		})
+	}
+}
+
+func TestComputeRechecksExpiringEntriesOnLaterDate(t *testing.T) {
+	// given
+	dir := gitrepo.New(t)
+	gitrepo.CommitFiles(t, dir, map[string]string{"CODEOWNERS": "* @org/docs # expires:2026-03-02\n"})
+	cfg := config.Config{Checks: []string{"syntax"}, ExperimentalChecks: []string{"expiration"}}
+
+	cache := fingerprint.NewCache(t.TempDir())
+	passed, err := fingerprint.Compute(dir, cfg, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NoError(t, cache.MarkPassed(passed))
+
+	// when
+	sameDay, err := fingerprint.Compute(dir, cfg, time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	laterDay, err := fingerprint.Compute(dir, cfg, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	// then
+	assert.True(t, cache.Passed(sameDay))
+	assert.False(t, cache.Passed(laterDay), "expiring entries must be checked again on a later date")
+}
+
+func TestCache(t *testing.T) {
+	// given
+	sut := fingerprint.NewCache(filepath.Join(t.TempDir(), "cache"))
+	require.False(t, sut.Passed("abc"))
+
+	// when
+	err := sut.MarkPassed("abc")
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, sut.Passed("abc"))
+	assert.False(t, sut.Passed("def"))
+}
@@ -0,0 +1,171 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// DefaultCheckRunName is the default name of the reported check run.
+const DefaultCheckRunName = "codeowners"
+
+// maxAnnotations is the maximum number of annotations accepted by GitHub in a single check run request.
+const maxAnnotations = 50
+
+// Conclusions of the check run.
+const (
+	ConclusionSuccess = "success"
+	ConclusionFailure = "failure"
+)
+
+// CheckRun is the result of the gate reported to GitHub.
+type CheckRun struct {
+	HeadSHA    string
+	Conclusion string
+	Title      string
+	Summary    string
+	Text       string
+	// Annotations point to CODEOWNERS lines with issues.
+	Annotations []Annotation
+}
+
+// Annotation is an issue reported for a CODEOWNERS line.
+type Annotation struct {
+	Path    string
+	LineNo  uint64
+	Level   string
+	Title   string
+	Message string
+}
+
+// NewCheckRun summarizes results of the validation of a given range. Issues are annotated on the codeownersPath,
+// i.e. the repository-relative path of the CODEOWNERS file, and exitCode is the exit code of the validation.
+func NewCheckRun(rng Range, codeownersPath string, codeownersChanged bool, results []runner.CheckResult, exitCode int) CheckRun {
+	out := CheckRun{HeadSHA: rng.HeadSHA, Conclusion: ConclusionSuccess}
+
+	var issues int
+	var text strings.Builder
+	for _, res := range results {
+		if len(res.Output.Issues) == 0 && res.Err == nil {
+			continue
+		}
+		fmt.Fprintf(&text, "### %s\n\n", res.Check)
+		if res.Err != nil {
+			fmt.Fprintf(&text, "- **Internal error:** %s\n", redact.String(res.Err.Error()))
+		}
+		for _, i := range res.Output.Issues {
+			issues++
+			line := ""
+			if i.LineNo != nil {
+				line = fmt.Sprintf("line %d: ", *i.LineNo)
+				out.Annotations = append(out.Annotations, Annotation{
+					Path:    codeownersPath,
+					LineNo:  *i.LineNo,
+					Level:   annotationLevel(i.Severity),
+					Title:   res.Check,
+					Message: i.Message,
+				})
+			}
+			fmt.Fprintf(&text, "- [%s] %s%s\n", strings.ToLower(i.Severity.String()), line, i.Message)
+		}
+		text.WriteString("\n")
+	}
+	out.Text = text.String()
+
+	switch {
+	case exitCode != runner.ExitCodeOK:
+		out.Conclusion = ConclusionFailure
+		out.Title = fmt.Sprintf("CODEOWNERS validation failed with %d issue(s)", issues)
+	case issues > 0:
+		out.Title = fmt.Sprintf("CODEOWNERS validation passed with %d issue(s)", issues)
+	default:
+		out.Title = "CODEOWNERS validation passed"
+	}
+
+	var summary strings.Builder
+	if rng.IsMergeGroup() {
+		fmt.Fprintf(&summary, "Validated the merge group `%s`, which combines `%s` with pull requests queued up to #%d.", rng.HeadRef, rng.BaseRef, rng.PullRequest)
+	} else {
+		fmt.Fprintf(&summary, "Validated the changes on top of `%s`.", rng.Base())
+	}
+	if codeownersChanged {
+		summary.WriteString(" The CODEOWNERS file was changed in the validated range.")
+	} else {
+		summary.WriteString(" The CODEOWNERS file was not changed in the validated range.")
+	}
+	out.Summary = summary.String()
+
+	return out
+}
+
+func annotationLevel(s api.SeverityType) string {
+	if s == api.Warning {
+		return "warning"
+	}
+	return "failure"
+}
+
+// CheckRunReporter reports results of the gate as GitHub check runs.
+type CheckRunReporter struct {
+	client *github.Client
+}
+
+// NewCheckRunReporter returns new instance of the CheckRunReporter.
+func NewCheckRunReporter(client *github.Client) *CheckRunReporter {
+	return &CheckRunReporter{client: client}
+}
+
+// Report creates a completed check run with a given name for the head commit of the range. GitHub accepts
+// a limited number of annotations, so only the first ones are attached, and all issues are listed in the text.
+func (r *CheckRunReporter) Report(ctx context.Context, repo, name string, run CheckRun) error {
+	idx := strings.LastIndex(repo, "/")
+	if idx <= 0 || idx == len(repo)-1 {
+		return errors.Errorf("wrong repository name, expected pattern 'owner/repository', got %q", repo)
+	}
+	if run.HeadSHA == "" {
+		return errors.New("cannot report check run, the head commit is not known")
+	}
+
+	output := &github.CheckRunOutput{
+		Title:   github.String(run.Title),
+		Summary: github.String(run.Summary),
+	}
+	if run.Text != "" {
+		output.Text = github.String(run.Text)
+	}
+	for i, a := range run.Annotations {
+		if i == maxAnnotations {
+			break
+		}
+		line := int(a.LineNo)
+		output.Annotations = append(output.Annotations, &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       &line,
+			EndLine:         &line,
+			AnnotationLevel: github.String(a.Level),
+			Title:           github.String(a.Title),
+			Message:         github.String(a.Message),
+		})
+	}
+
+	_, _, err := r.client.Checks.CreateCheckRun(ctx, repo[:idx], repo[idx+1:], github.CreateCheckRunOptions{
+		Name:        name,
+		HeadSHA:     run.HeadSHA,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(run.Conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output:      output,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "while creating check run %q for %s", name, run.HeadSHA)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package gate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/gate"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCheckRun(t *testing.T) {
+	// given
+	rng := gate.Range{BaseRef: "main", BaseSHA: "base", HeadRef: "gh-readonly-queue/main/pr-7-abc", HeadSHA: "head", PullRequest: 7}
+	results := []runner.CheckResult{
+		{Check: "Duplicated Pattern Checker"},
+		{Check: "Valid Syntax Checker", Output: api.Output{Issues: []api.Issue{
+			{Severity: api.Error, LineNo: ptr.Uint64Ptr(3), Message: "Owner '@' does not look like a GitHub username"},
+			{Severity: api.Warning, Message: "File-level warning"},
+		}}},
+		{Check: "File Exist Checker", Err: errors.New("git is not installed")},
+	}
+
+	// when
+	run := gate.NewCheckRun(rng, ".github/CODEOWNERS", true, results, runner.ExitCodeCheckFailure)
+
+	// then
+	assert.Equal(t, gate.CheckRun{
+		HeadSHA:    "head",
+		Conclusion: gate.ConclusionFailure,
+		Title:      "CODEOWNERS validation failed with 2 issue(s)",
+		Summary:    "Validated the merge group `gh-readonly-queue/main/pr-7-abc`, which combines `main` with pull requests queued up to #7. The CODEOWNERS file was changed in the validated range.",
+		Text: `### Valid Syntax Checker
+
+- [error] line 3: Owner '@' does not look like a GitHub username
+- [warning] File-level warning
+
+### File Exist Checker
+
+- **Internal error:** git is not installed
+
+`,
+		Annotations: []gate.Annotation{
+			{Path: ".github/CODEOWNERS", LineNo: 3, Level: "failure", Title: "Valid Syntax Checker", Message: "Owner '@' does not look like a GitHub username"},
+		},
+	}, run)
+}
+
+func TestNewCheckRunPassed(t *testing.T) {
+	// given
+	rng := gate.Range{BaseRef: "main", HeadSHA: "head"}
+
+	// when
+	run := gate.NewCheckRun(rng, "CODEOWNERS", false, []runner.CheckResult{{Check: "Valid Syntax Checker"}}, runner.ExitCodeOK)
+
+	// then
+	assert.Equal(t, gate.CheckRun{
+		HeadSHA:    "head",
+		Conclusion: gate.ConclusionSuccess,
+		Title:      "CODEOWNERS validation passed",
+		Summary:    "Validated the changes on top of `origin/main`. The CODEOWNERS file was not changed in the validated range.",
+	}, run)
+}
+
+func TestCheckRunReporterReport(t *testing.T) {
+	// given
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/repos/org/repo/check-runs", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer srv.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	sut := gate.NewCheckRunReporter(client)
+
+	// when
+	err := sut.Report(context.Background(), "org/repo", "codeowners", gate.CheckRun{
+		HeadSHA:     "head",
+		Conclusion:  gate.ConclusionFailure,
+		Title:       "CODEOWNERS validation failed with 1 issue(s)",
+		Summary:     "summary",
+		Text:        "text",
+		Annotations: []gate.Annotation{{Path: "CODEOWNERS", LineNo: 3, Level: "failure", Title: "Valid Syntax Checker", Message: "message"}},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.NotEmpty(t, got["completed_at"])
+	delete(got, "completed_at")
+	assert.Equal(t, map[string]interface{}{
+		"name":       "codeowners",
+		"head_sha":   "head",
+		"status":     "completed",
+		"conclusion": "failure",
+		"output": map[string]interface{}{
+			"title":   "CODEOWNERS validation failed with 1 issue(s)",
+			"summary": "summary",
+			"text":    "text",
+			"annotations": []interface{}{
+				map[string]interface{}{
+					"path":             "CODEOWNERS",
+					"start_line":       float64(3),
+					"end_line":         float64(3),
+					"annotation_level": "failure",
+					"title":            "Valid Syntax Checker",
+					"message":          "message",
+				},
+			},
+		},
+	}, got)
+}
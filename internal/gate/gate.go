@@ -0,0 +1,125 @@
+// Package gate validates CODEOWNERS of a commit range before it lands on the base branch and reports the result
+// as a GitHub check run, which can be marked as required in the branch protection.
+//
+// The GitHub merge queue tests pull requests on temporary `gh-readonly-queue/<base>/pr-<number>-<sha>` branches,
+// which combine the base branch with all pull requests queued ahead. CODEOWNERS changes of pull requests which were
+// validated separately meet only there, so the combined range of the merge group must be validated again.
+package gate
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MergeQueueBranchPrefix is the prefix of temporary branches created by the GitHub merge queue.
+const MergeQueueBranchPrefix = "gh-readonly-queue/"
+
+// mergeGroupEvent is the name of the GitHub Actions event triggered for merge groups.
+const mergeGroupEvent = "merge_group"
+
+// Range is a range of commits which is validated before it lands on the base branch.
+type Range struct {
+	// BaseRef is the name of the base branch, e.g. `main`.
+	BaseRef string
+	// BaseSHA is the commit of the base branch the range starts from. It's empty if only the branch name is known.
+	BaseSHA string
+	// HeadRef is the name of the validated branch. It's empty if the range is not a merge group.
+	HeadRef string
+	// HeadSHA is the validated commit, which the check run is reported for.
+	HeadSHA string
+	// PullRequest is the number of the last pull request of the merge group, or zero if the range is not a merge group.
+	PullRequest int
+}
+
+// Base returns the revision the range starts from. If the base commit is not known, the remote-tracking
+// branch of the base branch is used.
+func (r Range) Base() string {
+	if r.BaseSHA != "" {
+		return r.BaseSHA
+	}
+	return "origin/" + r.BaseRef
+}
+
+// IsMergeGroup returns true if the range is a merge group of the GitHub merge queue.
+func (r Range) IsMergeGroup() bool {
+	return r.PullRequest > 0
+}
+
+// ParseMergeQueueBranch parses the name of a temporary merge queue branch, e.g.
+// `refs/heads/gh-readonly-queue/main/pr-42-<sha>`. The base branch may contain slashes.
+func ParseMergeQueueBranch(ref string) (Range, error) {
+	name := strings.TrimPrefix(ref, "refs/heads/")
+	if !strings.HasPrefix(name, MergeQueueBranchPrefix) {
+		return Range{}, errors.Errorf("%q is not a merge queue branch, expected the %q prefix", ref, MergeQueueBranchPrefix)
+	}
+
+	rest := strings.TrimPrefix(name, MergeQueueBranchPrefix)
+	idx := strings.LastIndex(rest, "/pr-")
+	if idx <= 0 {
+		return Range{}, errors.Errorf("wrong merge queue branch %q, expected pattern '%s<base>/pr-<number>-<sha>'", ref, MergeQueueBranchPrefix)
+	}
+	number, _, _ := strings.Cut(rest[idx+len("/pr-"):], "-")
+	pr, err := strconv.Atoi(number)
+	if err != nil || pr <= 0 {
+		return Range{}, errors.Errorf("wrong merge queue branch %q, expected pattern '%s<base>/pr-<number>-<sha>'", ref, MergeQueueBranchPrefix)
+	}
+
+	return Range{BaseRef: rest[:idx], HeadRef: name, PullRequest: pr}, nil
+}
+
+// LoadMergeGroupEvent reads the range of the merge group from the payload of the `merge_group` webhook event,
+// e.g. the file under the GITHUB_EVENT_PATH in GitHub Actions.
+func LoadMergeGroupEvent(path string) (Range, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Range{}, errors.Wrap(err, "while reading merge group event")
+	}
+
+	var event struct {
+		MergeGroup struct {
+			HeadSHA string `json:"head_sha"`
+			HeadRef string `json:"head_ref"`
+			BaseSHA string `json:"base_sha"`
+			BaseRef string `json:"base_ref"`
+		} `json:"merge_group"`
+	}
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return Range{}, errors.Wrap(err, "while decoding merge group event")
+	}
+	group := event.MergeGroup
+	if group.HeadSHA == "" || group.BaseSHA == "" {
+		return Range{}, errors.Errorf("event %s doesn't describe a merge group, the head_sha and base_sha fields are required", path)
+	}
+
+	out, err := ParseMergeQueueBranch(group.HeadRef)
+	if err != nil {
+		return Range{}, err
+	}
+	out.BaseRef = strings.TrimPrefix(group.BaseRef, "refs/heads/")
+	out.BaseSHA, out.HeadSHA = group.BaseSHA, group.HeadSHA
+	return out, nil
+}
+
+// DetectMergeGroup returns the merge group which is being tested in GitHub Actions. The `merge_group` event
+// payload is preferred, as it contains the exact base commit. Otherwise, the group is parsed from the checked
+// out merge queue branch, and its head commit is left empty to be resolved by the caller.
+func DetectMergeGroup(getenv func(string) string) (Range, error) {
+	if getenv("GITHUB_EVENT_NAME") == mergeGroupEvent && getenv("GITHUB_EVENT_PATH") != "" {
+		return LoadMergeGroupEvent(getenv("GITHUB_EVENT_PATH"))
+	}
+
+	ref := getenv("GITHUB_REF")
+	if ref == "" {
+		return Range{}, errors.New("cannot detect the merge group, neither the merge_group event nor the GITHUB_REF environment variable is available")
+	}
+	out, err := ParseMergeQueueBranch(ref)
+	if err != nil {
+		return Range{}, err
+	}
+	out.HeadSHA = getenv("GITHUB_SHA")
+	return out, nil
+}
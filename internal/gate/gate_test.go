@@ -0,0 +1,123 @@
+package gate_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/gate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergeQueueBranch(t *testing.T) {
+	tests := map[string]struct {
+		givenRef string
+		expRange gate.Range
+		expErr   string
+	}{
+		"Should parse merge queue branch": {
+			givenRef: "refs/heads/gh-readonly-queue/main/pr-42-0123abcd",
+			expRange: gate.Range{BaseRef: "main", HeadRef: "gh-readonly-queue/main/pr-42-0123abcd", PullRequest: 42},
+		},
+		"Should parse base branch with slashes": {
+			givenRef: "gh-readonly-queue/release/v1.2/pr-7-0123abcd",
+			expRange: gate.Range{BaseRef: "release/v1.2", HeadRef: "gh-readonly-queue/release/v1.2/pr-7-0123abcd", PullRequest: 7},
+		},
+		"Should reject other branches": {
+			givenRef: "refs/heads/main",
+			expErr:   `"refs/heads/main" is not a merge queue branch, expected the "gh-readonly-queue/" prefix`,
+		},
+		"Should reject branch without pull request number": {
+			givenRef: "gh-readonly-queue/main/pr-x-0123abcd",
+			expErr:   `wrong merge queue branch "gh-readonly-queue/main/pr-x-0123abcd", expected pattern 'gh-readonly-queue/<base>/pr-<number>-<sha>'`,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			rng, err := gate.ParseMergeQueueBranch(tc.givenRef)
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expRange, rng)
+		})
+	}
+}
+
+func TestDetectMergeGroup(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventPath, []byte(`{
+		"action": "checks_requested",
+		"merge_group": {
+			"head_sha": "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+			"head_ref": "refs/heads/gh-readonly-queue/main/pr-104-9ebb7a5f8f26f5a8c0ff8e0c6b7ab5d36d5e6f11",
+			"base_sha": "380387ee1a2e7a6fdbf6a0c0ad41d8a1ec6d27d6",
+			"base_ref": "refs/heads/main"
+		}
+	}`), 0o644))
+
+	tests := map[string]struct {
+		givenEnv map[string]string
+		expRange gate.Range
+		expErr   string
+	}{
+		"Should read merge group from event payload": {
+			givenEnv: map[string]string{
+				"GITHUB_EVENT_NAME": "merge_group",
+				"GITHUB_EVENT_PATH": eventPath,
+				"GITHUB_REF":        "refs/heads/ignored",
+			},
+			expRange: gate.Range{
+				BaseRef:     "main",
+				BaseSHA:     "380387ee1a2e7a6fdbf6a0c0ad41d8a1ec6d27d6",
+				HeadRef:     "gh-readonly-queue/main/pr-104-9ebb7a5f8f26f5a8c0ff8e0c6b7ab5d36d5e6f11",
+				HeadSHA:     "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+				PullRequest: 104,
+			},
+		},
+		"Should parse merge group from checked out branch": {
+			givenEnv: map[string]string{
+				"GITHUB_EVENT_NAME": "push",
+				"GITHUB_REF":        "refs/heads/gh-readonly-queue/main/pr-104-9ebb7a5f",
+				"GITHUB_SHA":        "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+			},
+			expRange: gate.Range{
+				BaseRef:     "main",
+				HeadRef:     "gh-readonly-queue/main/pr-104-9ebb7a5f",
+				HeadSHA:     "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+				PullRequest: 104,
+			},
+		},
+		"Should fail outside of GitHub Actions": {
+			givenEnv: map[string]string{},
+			expErr:   "cannot detect the merge group, neither the merge_group event nor the GITHUB_REF environment variable is available",
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			rng, err := gate.DetectMergeGroup(func(key string) string { return tc.givenEnv[key] })
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expRange, rng)
+		})
+	}
+}
+
+func TestRangeBase(t *testing.T) {
+	assert.Equal(t, "380387ee", gate.Range{BaseRef: "main", BaseSHA: "380387ee"}.Base())
+	assert.Equal(t, "origin/main", gate.Range{BaseRef: "main"}.Base())
+}
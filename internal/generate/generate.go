@@ -0,0 +1,76 @@
+package generate
+
+import (
+	"io"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is the source of truth of the repository ownership, e.g.:
+//
+//	header: |
+//	  # Generated from ownership.yaml, do not edit.
+//	owners:
+//	  - owner: "@org/platform"
+//	    paths: [/infra/, /deploy/]
+//	  - owner: "@org/docs"
+//	    paths: [/docs/]
+type Source struct {
+	// Header is placed at the beginning of the generated file.
+	Header string `yaml:"header"`
+	// Owners maps owners to paths they own.
+	Owners []OwnerPaths `yaml:"owners"`
+}
+
+// OwnerPaths lists paths owned by a given owner.
+type OwnerPaths struct {
+	Owner string   `yaml:"owner"`
+	Paths []string `yaml:"paths"`
+}
+
+// FromSource generates the CODEOWNERS file from the source of truth in YAML format.
+// Paths are emitted in the order of their first occurrence. If a path is listed by multiple owners,
+// all of them own it. The output is formatted in the canonical style.
+func FromSource(r io.Reader) ([]byte, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var src Source
+	if err := dec.Decode(&src); err != nil {
+		return nil, errors.Wrap(err, "while decoding ownership source")
+	}
+
+	var (
+		order  []string
+		owners = map[string][]string{}
+	)
+	for _, o := range src.Owners {
+		if o.Owner == "" {
+			return nil, errors.New("owner name is required")
+		}
+		for _, p := range o.Paths {
+			if _, found := owners[p]; !found {
+				order = append(order, p)
+			}
+			owners[p] = append(owners[p], o.Owner)
+		}
+	}
+
+	var out strings.Builder
+	if src.Header != "" {
+		out.WriteString(strings.TrimRight(src.Header, "\n"))
+		out.WriteString("\n\n")
+	}
+	for _, p := range order {
+		out.WriteString(p)
+		out.WriteString(" ")
+		out.WriteString(strings.Join(owners[p], " "))
+		out.WriteString("\n")
+	}
+
+	return codeowners.Format(strings.NewReader(out.String()), codeowners.FormatOptions{})
+}
@@ -0,0 +1,63 @@
+package generate_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/generate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSource(t *testing.T) {
+	// given
+	src := `
+header: |
+  # Generated from ownership.yaml, do not edit.
+owners:
+  - owner: "@org/platform"
+    paths: [/infra/, /deploy/]
+  - owner: "@org/docs"
+    paths: [/docs/, /deploy/]
+`
+	expOutput := `# Generated from ownership.yaml, do not edit.
+
+/infra/  @org/platform
+/deploy/ @org/platform @org/docs
+/docs/   @org/docs
+`
+
+	// when
+	got, err := generate.FromSource(strings.NewReader(src))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, expOutput, string(got))
+}
+
+func TestFromSourceErrors(t *testing.T) {
+	tests := map[string]struct {
+		src    string
+		expErr string
+	}{
+		"Should reject unknown fields": {
+			src:    "teams: []\n",
+			expErr: "field teams not found",
+		},
+		"Should require owner name": {
+			src:    "owners:\n  - paths: [/docs/]\n",
+			expErr: "owner name is required",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := generate.FromSource(strings.NewReader(tc.src))
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// generatedAttributes mark files which don't need human owners, see:
+// https://github.com/github/linguist/blob/master/docs/overrides.md
+var generatedAttributes = []string{"linguist-generated", "linguist-vendored"}
+
+// Attributes returns values of given gitattributes for each file. The value is `set`, `unset`, `unspecified`,
+// or the value assigned in the .gitattributes file, e.g. `true` for `linguist-generated=true`.
+func Attributes(repoDir string, files []string, attrs ...string) (map[string]map[string]string, error) {
+	out := map[string]map[string]string{}
+	if len(files) == 0 || len(attrs) == 0 {
+		return out, nil
+	}
+	if UsesGoGit() {
+		return attributesGoGit(repoDir, files, attrs...)
+	}
+
+	var stdin bytes.Buffer
+	for _, f := range files {
+		stdin.WriteString(f)
+		stdin.WriteByte(0)
+	}
+
+	args := append([]string{"check-attr", "-z", "--stdin"}, attrs...)
+	gitattr := pipe.Script(
+		pipe.ChDir(repoDir),
+		pipe.Line(
+			pipe.Read(&stdin),
+			gitExec(args...),
+		),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitattr)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	// the output is a sequence of NUL-terminated <path> <attribute> <value> triplets
+	fields := strings.Split(string(stdout), "\x00")
+	for idx := 0; idx+2 < len(fields); idx += 3 {
+		file, attr, value := fields[idx], fields[idx+1], fields[idx+2]
+		if out[file] == nil {
+			out[file] = map[string]string{}
+		}
+		out[file][attr] = value
+	}
+	return out, nil
+}
+
+// WithoutGenerated returns files which are not marked as generated or vendored with the
+// `linguist-generated` or `linguist-vendored` gitattributes.
+func WithoutGenerated(repoDir string, files []string) ([]string, error) {
+	attrs, err := Attributes(repoDir, files, generatedAttributes...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if isGenerated(attrs[f]) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func isGenerated(values map[string]string) bool {
+	for _, attr := range generatedAttributes {
+		if v := values[attr]; v == "set" || v == "true" {
+			return true
+		}
+	}
+	return false
+}
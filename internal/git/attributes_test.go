@@ -0,0 +1,54 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/git"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithoutGenerated(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	cmd := exec.Command("git", "init", "--quiet")
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".gitattributes"), []byte(`
+*.pb.go              linguist-generated
+docs/api.md          linguist-generated=true
+third_party/**       linguist-vendored
+third_party/ours/**  -linguist-vendored
+main_gen.go          linguist-generated=false
+`), 0o600))
+
+	files := []string{
+		"main.go",
+		"main_gen.go",
+		"api/v1/api.pb.go",
+		"docs/api.md",
+		"docs/index.md",
+		"third_party/lib/lib.go",
+		"third_party/ours/patch.go",
+		"dir with space/file.go",
+	}
+
+	// when
+	got, err := git.WithoutGenerated(repoDir, files)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"main.go",
+		"main_gen.go",
+		"docs/index.md",
+		"third_party/ours/patch.go",
+		"dir with space/file.go",
+	}, got)
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// Supported git backends.
+const (
+	// BackendAuto uses the git binary if it's found in PATH, and the embedded go-git otherwise.
+	BackendAuto = "auto"
+	// BackendExec executes the git binary.
+	BackendExec = "exec"
+	// BackendGoGit reads the repository with the embedded go-git, e.g. in distroless containers without git.
+	// Listing files, reading remotes, ignore rules, and attributes are supported, while reading the history
+	// and diffs still requires the git binary.
+	BackendGoGit = "go-git"
+)
+
+var (
+	backendMu   sync.Mutex
+	backend     = BackendAuto
+	useGoGit    bool
+	useGoGitSet bool
+)
+
+// SetBackend selects the git backend. The empty name means BackendAuto.
+func SetBackend(name string) error {
+	switch name {
+	case "":
+		name = BackendAuto
+	case BackendAuto, BackendExec, BackendGoGit:
+	default:
+		return errors.Errorf("unknown git backend %q, possible values: %s", name, strings.Join([]string{BackendAuto, BackendExec, BackendGoGit}, ", "))
+	}
+
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend, useGoGitSet = name, false
+	return nil
+}
+
+// UsesGoGit returns true if the embedded go-git is used instead of the git binary.
+// With the BackendAuto, the git binary is looked up only once.
+func UsesGoGit() bool {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+
+	if !useGoGitSet {
+		switch backend {
+		case BackendExec:
+			useGoGit = false
+		case BackendGoGit:
+			useGoGit = true
+		default:
+			_, err := exec.LookPath("git")
+			useGoGit = err != nil
+		}
+		useGoGitSet = true
+	}
+	return useGoGit
+}
+
+// ErrNoBinary is returned by operations which read the history or diffs, if the git binary is not found in PATH.
+// The go-git backend doesn't implement them.
+var ErrNoBinary = errors.New("the git binary is not found in PATH, it's required to read the git history and diffs")
+
+var (
+	hasBinaryOnce sync.Once
+	hasBinary     bool
+)
+
+// HasBinary returns true if the git binary is found in PATH. It's looked up only once.
+func HasBinary() bool {
+	hasBinaryOnce.Do(func() {
+		_, err := exec.LookPath("git")
+		hasBinary = err == nil
+	})
+	return hasBinary
+}
+
+// gitExec executes the git binary with given arguments. It fails with the ErrNoBinary if the binary is not found,
+// so operations which the go-git backend doesn't implement fail with a clear error.
+func gitExec(args ...string) pipe.Pipe {
+	if !HasBinary() {
+		return func(*pipe.State) error {
+			return ErrNoBinary
+		}
+	}
+	return pipe.Exec("git", args...)
+}
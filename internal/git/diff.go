@@ -0,0 +1,165 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Line represents a single line changed in a file.
+type Line struct {
+	// No is the line number in the old (removed lines) or new (added lines) file version.
+	No      uint64
+	Content string
+}
+
+// FileDiff holds lines changed in a single file.
+type FileDiff struct {
+	Added   []Line
+	Removed []Line
+}
+
+// DiffFile returns lines changed in a given file between the merge base of baseRef and HEAD.
+func DiffFile(repoDir, baseRef, file string) (FileDiff, error) {
+	gitdiff := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("diff", "--no-color", "--no-ext-diff", "-U0", fmt.Sprintf("%s...HEAD", baseRef), "--", file),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitdiff)
+	if err != nil {
+		return FileDiff{}, errors.Wrap(err, string(stderr))
+	}
+
+	return ParseUnifiedDiff(stdout)
+}
+
+// ParseUnifiedDiff parses a single file unified diff.
+func ParseUnifiedDiff(in []byte) (FileDiff, error) {
+	var (
+		out          FileDiff
+		oldNo, newNo uint64
+		insideHunk   bool
+		scanner      = bufio.NewScanner(bytes.NewReader(in))
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := hunkHeaderRegexp.FindStringSubmatch(line); m != nil {
+			var err error
+			if oldNo, err = strconv.ParseUint(m[1], 10, 64); err != nil {
+				return FileDiff{}, errors.Wrapf(err, "while parsing hunk header %q", line)
+			}
+			if newNo, err = strconv.ParseUint(m[3], 10, 64); err != nil {
+				return FileDiff{}, errors.Wrapf(err, "while parsing hunk header %q", line)
+			}
+			insideHunk = true
+			continue
+		}
+
+		if !insideHunk || line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case '+':
+			out.Added = append(out.Added, Line{No: newNo, Content: line[1:]})
+			newNo++
+		case '-':
+			out.Removed = append(out.Removed, Line{No: oldNo, Content: line[1:]})
+			oldNo++
+		case ' ':
+			oldNo++
+			newNo++
+		case '\\': // "\ No newline at end of file"
+		default:
+			insideHunk = false
+		}
+	}
+
+	return out, scanner.Err()
+}
+
+// ChangeStatus is the kind of change of a single file, as reported by `git diff --name-status`.
+type ChangeStatus string
+
+// Change statuses of files.
+const (
+	Added    ChangeStatus = "added"
+	Modified ChangeStatus = "modified"
+	Deleted  ChangeStatus = "deleted"
+	Renamed  ChangeStatus = "renamed"
+)
+
+// ChangedFile represents a single file changed between two revisions.
+type ChangedFile struct {
+	Path   string
+	Status ChangeStatus
+	// OldPath is the path before the change, set only for renamed files.
+	OldPath string
+}
+
+// ChangedFiles returns files changed between the merge base of baseRef and HEAD, e.g. files changed in a pull request.
+// Copies are reported as added files and type changes as modified files.
+func ChangedFiles(repoDir, baseRef string) ([]ChangedFile, error) {
+	gitdiff := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("diff", "--no-color", "--no-ext-diff", "--name-status", "-z", "-M", fmt.Sprintf("%s...HEAD", baseRef)),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitdiff)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	return ParseNameStatus(stdout)
+}
+
+// ParseNameStatus parses the output of `git diff --name-status -z`.
+func ParseNameStatus(in []byte) ([]ChangedFile, error) {
+	fields := strings.Split(strings.TrimSuffix(string(in), "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return nil, nil
+	}
+
+	var out []ChangedFile
+	for idx := 0; idx < len(fields); {
+		status := fields[idx]
+		if status == "" {
+			return nil, errors.New("missing change status")
+		}
+
+		paths := 1
+		if status[0] == 'R' || status[0] == 'C' {
+			paths = 2
+		}
+		if idx+paths >= len(fields) {
+			return nil, errors.Errorf("missing path of the %q change", status)
+		}
+
+		f := ChangedFile{Path: fields[idx+paths]}
+		switch status[0] {
+		case 'A', 'C':
+			f.Status = Added
+		case 'D':
+			f.Status = Deleted
+		case 'R':
+			f.Status = Renamed
+			f.OldPath = fields[idx+1]
+		default:
+			f.Status = Modified
+		}
+		out = append(out, f)
+		idx += paths + 1
+	}
+
+	return out, nil
+}
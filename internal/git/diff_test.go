@@ -0,0 +1,83 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/internal/git"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	// given
+	givenDiff := `diff --git a/.github/CODEOWNERS b/.github/CODEOWNERS
+index 3b18e51..a9c3c6b 100644
+--- a/.github/CODEOWNERS
++++ b/.github/CODEOWNERS
+@@ -2 +2 @@
+-/infra/ @org/sre # approved-by: @org/eng-leads
++/infra/ @org/platform # approved-by: @org/eng-leads
+@@ -7,0 +8,2 @@ *.js @js-owner
++/docs/ @doctocat
++/build/ @builder
+\ No newline at end of file
+`
+	expDiff := git.FileDiff{
+		Added: []git.Line{
+			{No: 2, Content: "/infra/ @org/platform # approved-by: @org/eng-leads"},
+			{No: 8, Content: "/docs/ @doctocat"},
+			{No: 9, Content: "/build/ @builder"},
+		},
+		Removed: []git.Line{
+			{No: 2, Content: "/infra/ @org/sre # approved-by: @org/eng-leads"},
+		},
+	}
+
+	// when
+	gotDiff, err := git.ParseUnifiedDiff([]byte(givenDiff))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, expDiff, gotDiff)
+}
+
+func TestParseNameStatus(t *testing.T) {
+	tests := map[string]struct {
+		given    string
+		expFiles []git.ChangedFile
+		expErr   string
+	}{
+		"All change kinds": {
+			given: "M\x00main.go\x00A\x00docs/new.md\x00D\x00old.txt\x00R087\x00a/x.go\x00b/x.go\x00C100\x00tpl.yaml\x00copy.yaml\x00T\x00link\x00",
+			expFiles: []git.ChangedFile{
+				{Path: "main.go", Status: git.Modified},
+				{Path: "docs/new.md", Status: git.Added},
+				{Path: "old.txt", Status: git.Deleted},
+				{Path: "b/x.go", Status: git.Renamed, OldPath: "a/x.go"},
+				{Path: "copy.yaml", Status: git.Added},
+				{Path: "link", Status: git.Modified},
+			},
+		},
+		"No changes": {
+			given: "",
+		},
+		"Truncated rename": {
+			given:  "R100\x00a.go\x00",
+			expErr: `missing path of the "R100" change`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			files, err := git.ParseNameStatus([]byte(tc.given))
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expFiles, files)
+		})
+	}
+}
@@ -0,0 +1,392 @@
+//go:build !minimal && !js
+
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	gogit "gopkg.in/src-d/go-git.v4"
+	format "gopkg.in/src-d/go-git.v4/plumbing/format/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitattributes"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
+)
+
+// openGoGit opens the repository which contains a given directory, and returns the path of the directory
+// relative to the repository root, the same way as git resolves it from the working directory.
+func openGoGit(repoDir string) (*gogit.Repository, string, error) {
+	repo, err := gogit.PlainOpenWithOptions(repoDir, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "while opening repository %s", repoDir)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "while getting worktree")
+	}
+
+	absDir, err := filepath.Abs(repoDir)
+	if err != nil {
+		return nil, "", err
+	}
+	rel, err := filepath.Rel(wt.Filesystem.Root(), absDir)
+	if err != nil {
+		return nil, "", err
+	}
+	if rel == "." {
+		rel = ""
+	}
+	return repo, filepath.ToSlash(rel), nil
+}
+
+// ListFilesGoGit is the go-git implementation of ListFiles, which reads the index instead of executing `git ls-files`.
+func ListFilesGoGit(repoDir string, paths ...string) ([]string, error) {
+	repo, prefix, err := openGoGit(repoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading index")
+	}
+
+	var files []string
+	for _, e := range idx.Entries {
+		name := e.Name
+		if prefix != "" {
+			if !strings.HasPrefix(name, prefix+"/") {
+				continue
+			}
+			name = strings.TrimPrefix(name, prefix+"/")
+		}
+		if underAny(name, paths) {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// underAny returns true if a given file is any of given paths, or is in any of given directories.
+// All files are matched if no paths are given.
+func underAny(file string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(p), "./"), "/")
+		if p == "" || p == "." || file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteURLGoGit is the go-git implementation of RemoteURL.
+func remoteURLGoGit(repoDir, name string) (string, error) {
+	repo, _, err := openGoGit(repoDir)
+	if err != nil {
+		return "", err
+	}
+
+	remote, err := repo.Remote(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "while getting remote %q", name)
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", errors.Errorf("remote %q has no URL", name)
+	}
+	return urls[0], nil
+}
+
+// gitDirGoGit returns the absolute path of the $GIT_DIR of a given repository.
+func gitDirGoGit(repo *gogit.Repository) (string, error) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", errors.New("repository is not stored in the file system")
+	}
+	return storage.Filesystem().Root(), nil
+}
+
+// infoExcludeFileGoGit is the go-git implementation of InfoExcludeFile.
+func infoExcludeFileGoGit(repoDir string) (string, error) {
+	repo, _, err := openGoGit(repoDir)
+	if err != nil {
+		return "", err
+	}
+	gitDir, err := gitDirGoGit(repo)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "info", "exclude"), nil
+}
+
+// globalExcludesFileGoGit is the go-git implementation of GlobalExcludesFile.
+func globalExcludesFileGoGit(repoDir string) (string, error) {
+	repo, _, err := openGoGit(repoDir)
+	if err != nil {
+		return "", err
+	}
+	path, err := coreOptionGoGit(repo, "excludesFile")
+	if err != nil || path != "" {
+		return path, err
+	}
+	return xdgConfigFile("ignore"), nil
+}
+
+// coreOptionGoGit returns the value of a given option of the core section, read from the repository config,
+// and the global and system config files, in the order of git precedence. The `~/` prefix is expanded,
+// as with `git config --path`. It returns an empty string if the option is not set.
+func coreOptionGoGit(repo *gogit.Repository, key string) (string, error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", errors.Wrap(err, "while reading repository config")
+	}
+	value := cfg.Raw.Section("core").Options.Get(key)
+
+	home := os.Getenv("HOME")
+	var globals []string
+	if home != "" {
+		globals = append(globals, filepath.Join(home, ".gitconfig"))
+	}
+	if xdg := xdgConfigFile("config"); xdg != "" {
+		globals = append(globals, xdg)
+	}
+	globals = append(globals, "/etc/gitconfig")
+
+	for _, path := range globals {
+		if value != "" {
+			break
+		}
+		raw, err := readConfigFile(path)
+		if err != nil {
+			return "", err
+		}
+		value = raw.Section("core").Options.Get(key)
+	}
+
+	if strings.HasPrefix(value, "~/") && home != "" {
+		value = filepath.Join(home, value[2:])
+	}
+	return value, nil
+}
+
+// readConfigFile parses a given git config file. A missing file is treated as empty.
+func readConfigFile(path string) (*format.Config, error) {
+	raw := format.New()
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return raw, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := format.NewDecoder(f).Decode(raw); err != nil {
+		return nil, errors.Wrapf(err, "while parsing %s", path)
+	}
+	return raw, nil
+}
+
+// ignoredFilesGoGit is the go-git implementation of IgnoredFiles, which matches files from the index
+// against rules parsed by go-git instead of executing `git ls-files --ignored`.
+func ignoredFilesGoGit(repoDir string, excludeFiles []string, perDirectory bool) (map[string]struct{}, error) {
+	repo, prefix, err := openGoGit(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting worktree")
+	}
+
+	var patterns []gitignore.Pattern
+	for _, f := range excludeFiles {
+		ps, err := readIgnoreFile(f)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, ps...)
+	}
+	if perDirectory {
+		ps, err := gitignore.ReadPatterns(wt.Filesystem, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "while reading .gitignore files")
+		}
+		patterns = append(patterns, ps...)
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	matcher := gitignore.NewMatcher(patterns)
+
+	files, err := ListFilesGoGit(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	ignored := map[string]struct{}{}
+	for _, f := range files {
+		if matcher.Match(strings.Split(repoFile(prefix, f), "/"), false) {
+			ignored[f] = struct{}{}
+		}
+	}
+	return ignored, nil
+}
+
+// readIgnoreFile parses rules of a given ignore file, e.g. $GIT_DIR/info/exclude. A missing file has no rules.
+func readIgnoreFile(path string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		out = append(out, gitignore.ParsePattern(line, nil))
+	}
+	return out, errors.Wrapf(scanner.Err(), "while reading %s", path)
+}
+
+// attributesGoGit is the go-git implementation of Attributes. Files are matched against the global attributes file,
+// the .gitattributes files, and $GIT_DIR/info/attributes, in the order of increasing precedence.
+func attributesGoGit(repoDir string, files []string, attrs ...string) (map[string]map[string]string, error) {
+	repo, prefix, err := openGoGit(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting worktree")
+	}
+	gitDir, err := gitDirGoGit(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	global, err := coreOptionGoGit(repo, "attributesFile")
+	if err != nil {
+		return nil, err
+	}
+	if global == "" {
+		global = xdgConfigFile("attributes")
+	}
+
+	var stack []gitattributes.MatchAttribute
+	if global != "" {
+		ps, err := readAttributesFile(global)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, ps...)
+	}
+	ps, err := gitattributes.ReadPatterns(wt.Filesystem, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading .gitattributes files")
+	}
+	stack = append(stack, ps...)
+	ps, err = readAttributesFile(filepath.Join(gitDir, "info", "attributes"))
+	if err != nil {
+		return nil, err
+	}
+	stack = append(stack, ps...)
+
+	macros := map[string][]gitattributes.Attribute{
+		"binary": {unsetAttribute("diff"), unsetAttribute("merge"), unsetAttribute("text")},
+	}
+	for _, m := range stack {
+		if m.Pattern == nil && m.Name != "" {
+			macros[m.Name] = m.Attributes
+		}
+	}
+
+	out := map[string]map[string]string{}
+	for _, f := range files {
+		values := map[string]string{}
+		for _, a := range attrs {
+			values[a] = "unspecified"
+		}
+		path := strings.Split(repoFile(prefix, f), "/")
+		for _, m := range stack {
+			if m.Pattern == nil || !m.Pattern.Match(path) {
+				continue
+			}
+			for _, a := range m.Attributes {
+				applyAttribute(values, a, macros)
+			}
+		}
+		out[f] = values
+	}
+	return out, nil
+}
+
+// applyAttribute stores the value of a given attribute if it's requested, expanding set macros.
+func applyAttribute(values map[string]string, a gitattributes.Attribute, macros map[string][]gitattributes.Attribute) {
+	if _, requested := values[a.Name()]; requested {
+		values[a.Name()] = attributeValue(a)
+	}
+	if expanded, isMacro := macros[a.Name()]; isMacro && a.IsSet() {
+		for _, e := range expanded {
+			if _, requested := values[e.Name()]; requested {
+				values[e.Name()] = attributeValue(e)
+			}
+		}
+	}
+}
+
+// attributeValue returns the value of a given attribute in the `git check-attr` format.
+func attributeValue(a gitattributes.Attribute) string {
+	switch {
+	case a.IsSet():
+		return "set"
+	case a.IsUnset():
+		return "unset"
+	case a.IsValueSet():
+		return a.Value()
+	default:
+		return "unspecified"
+	}
+}
+
+// unsetAttribute returns an attribute unset with the `-` prefix.
+func unsetAttribute(name string) gitattributes.Attribute {
+	m, _ := gitattributes.ParseAttributesLine("* -"+name, nil, false)
+	return m.Attributes[0]
+}
+
+// readAttributesFile parses a given attributes file, e.g. $GIT_DIR/info/attributes. A missing file has no rules.
+func readAttributesFile(path string) ([]gitattributes.MatchAttribute, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	out, err := gitattributes.ReadAttributes(f, nil, true)
+	return out, errors.Wrapf(err, "while reading %s", path)
+}
+
+// repoFile returns the path of a given file relative to the repository root.
+func repoFile(prefix, file string) string {
+	if prefix == "" {
+		return file
+	}
+	return prefix + "/" + file
+}
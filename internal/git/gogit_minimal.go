@@ -0,0 +1,39 @@
+//go:build minimal || js
+
+package git
+
+import "github.com/pkg/errors"
+
+// errNoGoGit is returned in the minimal build, which doesn't embed go-git to keep the binary small,
+// and in the WebAssembly build, as go-git needs OS-specific file system calls.
+var errNoGoGit = errors.New("the go-git backend is not available in the minimal and WebAssembly builds, install the git binary")
+
+// ListFilesGoGit is the go-git implementation of ListFiles, which is not available in the minimal and WebAssembly builds.
+func ListFilesGoGit(string, ...string) ([]string, error) {
+	return nil, errNoGoGit
+}
+
+// remoteURLGoGit is the go-git implementation of RemoteURL, which is not available in the minimal and WebAssembly builds.
+func remoteURLGoGit(string, string) (string, error) {
+	return "", errNoGoGit
+}
+
+// infoExcludeFileGoGit is the go-git implementation of InfoExcludeFile, which is not available in the minimal and WebAssembly builds.
+func infoExcludeFileGoGit(string) (string, error) {
+	return "", errNoGoGit
+}
+
+// globalExcludesFileGoGit is the go-git implementation of GlobalExcludesFile, which is not available in the minimal and WebAssembly builds.
+func globalExcludesFileGoGit(string) (string, error) {
+	return "", errNoGoGit
+}
+
+// ignoredFilesGoGit is the go-git implementation of IgnoredFiles, which is not available in the minimal and WebAssembly builds.
+func ignoredFilesGoGit(string, []string, bool) (map[string]struct{}, error) {
+	return nil, errNoGoGit
+}
+
+// attributesGoGit is the go-git implementation of Attributes, which is not available in the minimal and WebAssembly builds.
+func attributesGoGit(string, []string, ...string) (map[string]map[string]string, error) {
+	return nil, errNoGoGit
+}
@@ -0,0 +1,81 @@
+//go:build !minimal && !js
+
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoGitBackend(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	gitrepo.Run(t, repoDir, "remote", "add", "origin", "git@github.com:org/repo.git")
+	for _, f := range []string{"CODEOWNERS", "docs/index.md", "docs/api/api.md", "src/main.go", "untracked.txt"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoDir, filepath.Dir(f)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, f), []byte(f), 0o600))
+	}
+	gitrepo.Run(t, repoDir, "add", "CODEOWNERS", "docs", "src")
+
+	tests := map[string]struct {
+		dir   string
+		paths []string
+	}{
+		"Should list all tracked files": {
+			dir: repoDir,
+		},
+		"Should list files under given paths": {
+			dir:   repoDir,
+			paths: []string{"docs/api", "src/main.go"},
+		},
+		"Should list files relative to a subdirectory": {
+			dir: filepath.Join(repoDir, "docs"),
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			require.NoError(t, git.SetBackend(git.BackendExec))
+			expected, err := git.ListFiles(tc.dir, tc.paths...)
+			require.NoError(t, err)
+			require.NotEmpty(t, expected)
+
+			// when
+			require.NoError(t, git.SetBackend(git.BackendGoGit))
+			t.Cleanup(func() { _ = git.SetBackend(git.BackendAuto) })
+			got, err := git.ListFiles(tc.dir, tc.paths...)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, expected, got)
+		})
+	}
+
+	t.Run("Should read remote URL", func(t *testing.T) {
+		// given
+		require.NoError(t, git.SetBackend(git.BackendGoGit))
+		t.Cleanup(func() { _ = git.SetBackend(git.BackendAuto) })
+
+		// when
+		remote, err := git.DetectRemote(repoDir, "origin")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "org/repo", remote.Repository())
+	})
+}
+
+func TestSetBackendUnknown(t *testing.T) {
+	// when
+	err := git.SetBackend("libgit2")
+
+	// then
+	assert.EqualError(t, err, `unknown git backend "libgit2", possible values: auto, exec, go-git`)
+	assert.NoError(t, git.SetBackend(""))
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// InfoExcludeFile returns the absolute path of the $GIT_DIR/info/exclude file. The file may not exist.
+func InfoExcludeFile(repoDir string) (string, error) {
+	if UsesGoGit() {
+		return infoExcludeFileGoGit(repoDir)
+	}
+
+	revparse := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("rev-parse", "--git-path", "info/exclude"),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(revparse)
+	if err != nil {
+		return "", errors.Wrap(err, string(stderr))
+	}
+
+	p := strings.TrimSpace(string(stdout))
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(repoDir, p)
+	}
+	return p, nil
+}
+
+// GlobalExcludesFile returns the path of the global gitignore file, set by the core.excludesFile option,
+// or $XDG_CONFIG_HOME/git/ignore if the option is not set. The file may not exist.
+// It returns an empty string if the path cannot be determined.
+func GlobalExcludesFile(repoDir string) (string, error) {
+	if UsesGoGit() {
+		return globalExcludesFileGoGit(repoDir)
+	}
+
+	gitconfig := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("config", "--path", "core.excludesFile"),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitconfig)
+	switch {
+	case err == nil:
+		return strings.TrimSpace(string(stdout)), nil
+	case len(stdout) == 0 && len(stderr) == 0:
+		// the option is not set
+	default:
+		return "", errors.Wrap(err, string(stderr))
+	}
+
+	return xdgConfigFile("ignore"), nil
+}
+
+// xdgConfigFile returns the path of a given file in the git directory of $XDG_CONFIG_HOME, which git reads
+// if the corresponding option is not set. It returns an empty string if the path cannot be determined.
+func xdgConfigFile(name string) string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", name)
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		return filepath.Join(home, ".config", "git", name)
+	}
+	return ""
+}
+
+// IgnoredFiles returns tracked files ignored by rules read from given files, e.g. the global gitignore,
+// and, if perDirectory is true, from the .gitignore files in the repository. Rules of later files take
+// precedence, and the .gitignore files take precedence over all given files, as in git.
+// Paths are relative to the repoDir.
+func IgnoredFiles(repoDir string, excludeFiles []string, perDirectory bool) (map[string]struct{}, error) {
+	if UsesGoGit() {
+		return ignoredFilesGoGit(repoDir, excludeFiles, perDirectory)
+	}
+
+	args := []string{"ls-files", "-ci", "-z"}
+	for _, f := range excludeFiles {
+		args = append(args, "--exclude-from="+f)
+	}
+	if perDirectory {
+		args = append(args, "--exclude-per-directory=.gitignore")
+	}
+	// git requires at least one source of ignore rules
+	if len(args) == 3 {
+		return nil, nil
+	}
+
+	gitls := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec(args...),
+	)
+	stdout, stderr, err := pipe.DividedOutput(gitls)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	ignored := map[string]struct{}{}
+	for _, f := range strings.Split(string(stdout), "\x00") {
+		if f != "" {
+			ignored[f] = struct{}{}
+		}
+	}
+	return ignored, nil
+}
@@ -0,0 +1,228 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// ChangeCounts returns how many commits reachable from HEAD changed each file since a given time.
+// Renames are not followed, so changes made before a rename are counted for the old path.
+func ChangeCounts(repoDir string, since time.Time) (map[string]int, error) {
+	args := []string{"log", "--format=", "--name-only", "--no-renames", "-z"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+
+	gitlog := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec(args...),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitlog)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	out := map[string]int{}
+	for _, f := range strings.Split(string(stdout), "\x00") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		out[f]++
+	}
+	return out, nil
+}
+
+// commitMarker starts each commit in the CommitFiles output.
+const commitMarker = "\x01"
+
+// CommitFiles returns files changed by each commit reachable from HEAD since a given time, the newest commit first.
+// Renames are not followed, so a renamed file is reported under both paths.
+func CommitFiles(repoDir string, since time.Time) ([][]string, error) {
+	args := []string{"log", "--format=%x01", "--name-only", "--no-renames", "-z"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+
+	gitlog := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec(args...),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitlog)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	return parseCommitFiles(string(stdout)), nil
+}
+
+func parseCommitFiles(in string) [][]string {
+	var out [][]string
+	for _, token := range strings.Split(in, "\x00") {
+		token = strings.TrimPrefix(token, "\n")
+		switch {
+		case strings.HasPrefix(token, commitMarker):
+			out = append(out, nil)
+			token = strings.TrimPrefix(token, commitMarker)
+		case len(out) == 0:
+			continue
+		}
+		if token = strings.TrimSpace(token); token != "" {
+			out[len(out)-1] = append(out[len(out)-1], token)
+		}
+	}
+	return out
+}
+
+// PreviousPaths returns previous paths of files renamed in the last maxCommits commits reachable from HEAD,
+// indexed by the current path. Chains of renames are followed, and previous paths are ordered from the newest one.
+func PreviousPaths(repoDir string, maxCommits int) (map[string][]string, error) {
+	gitlog := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("log", fmt.Sprintf("--max-count=%d", maxCommits), "--format=", "--name-status", "-z", "-M", "--diff-filter=R"),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitlog)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	renames, err := ParseNameStatus(stdout)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing renames")
+	}
+	return previousPaths(renames), nil
+}
+
+// previousPaths indexes renames, listed from the newest one, by the current path of the file.
+func previousPaths(renames []ChangedFile) map[string][]string {
+	out := map[string][]string{}
+	// current maps a path which the file had at some point to its current path
+	current := map[string]string{}
+	for _, r := range renames {
+		cur, found := current[r.Path]
+		if !found {
+			cur = r.Path
+		}
+		out[cur] = append(out[cur], r.OldPath)
+		current[r.OldPath] = cur
+	}
+	return out
+}
+
+// TopAuthors returns emails of authors of the last maxCommits commits reachable from HEAD which changed a given path,
+// ordered by the number of commits, at most limit authors.
+func TopAuthors(repoDir, path string, maxCommits, limit int) ([]string, error) {
+	gitlog := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("log", fmt.Sprintf("--max-count=%d", maxCommits), "--no-merges", "--format=%ae", "--", path),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitlog)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	return topAuthors(string(stdout), limit), nil
+}
+
+func topAuthors(in string, limit int) []string {
+	counts := map[string]int{}
+	var authors []string
+	for _, email := range strings.Split(in, "\n") {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		if counts[email] == 0 {
+			authors = append(authors, email)
+		}
+		counts[email]++
+	}
+
+	// stable sort keeps the most recent author first among authors with the same number of commits
+	sort.SliceStable(authors, func(i, j int) bool {
+		return counts[authors[i]] > counts[authors[j]]
+	})
+	if limit > 0 && len(authors) > limit {
+		authors = authors[:limit]
+	}
+	return authors
+}
+
+// Commit is a single commit together with files it changed.
+type Commit struct {
+	SHA     string   `json:"sha"`
+	Subject string   `json:"subject"`
+	Files   []string `json:"files"`
+}
+
+// CommitsInRange returns non-merge commits of a given revision range, e.g. `v1.0.0..v2.0.0`, the newest commit first.
+// Renames are not followed, so a renamed file is reported under both paths.
+func CommitsInRange(repoDir, revRange string) ([]Commit, error) {
+	stdout, err := rawOutput(repoDir, "log", "--no-merges", "--format=%x01%H%x1f%s", "--name-only", "--no-renames", "-z", revRange, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommits(string(stdout)), nil
+}
+
+func parseCommits(in string) []Commit {
+	var out []Commit
+	for _, chunk := range strings.Split(in, commitMarker) {
+		if chunk == "" {
+			continue
+		}
+		tokens := strings.Split(chunk, "\x00")
+		sha, subject, _ := strings.Cut(tokens[0], "\x1f")
+		commit := Commit{SHA: sha, Subject: subject}
+		for _, f := range tokens[1:] {
+			if f = strings.TrimSpace(f); f != "" {
+				commit.Files = append(commit.Files, f)
+			}
+		}
+		out = append(out, commit)
+	}
+	return out
+}
+
+// FirstAdded returns the commit time at which each path was first added in commits reachable from HEAD.
+// Renames are not followed, so a moved file is reported as added by the commit which moved it.
+func FirstAdded(repoDir string) (map[string]time.Time, error) {
+	stdout, err := rawOutput(repoDir, "log", "--format=%x01%ct", "--name-only", "--no-renames", "--diff-filter=A", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFirstAdded(string(stdout))
+}
+
+// parseFirstAdded parses commits listed from the newest one, so the time of the oldest addition wins.
+func parseFirstAdded(in string) (map[string]time.Time, error) {
+	out := map[string]time.Time{}
+	for _, chunk := range strings.Split(in, commitMarker) {
+		if chunk == "" {
+			continue
+		}
+		tokens := strings.Split(chunk, "\x00")
+		unix, err := strconv.ParseInt(strings.TrimSpace(tokens[0]), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while parsing commit time %q", tokens[0])
+		}
+		for _, f := range tokens[1:] {
+			if f = strings.TrimSpace(f); f != "" {
+				out[f] = time.Unix(unix, 0).UTC()
+			}
+		}
+	}
+	return out, nil
+}
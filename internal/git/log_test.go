@@ -0,0 +1,135 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviousPaths(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "old"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "old", "a.go"), []byte("package a\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "b.go"), []byte("package b\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "c.go"), []byte("package c\n\nfunc C() {}\n"), 0o600))
+	gitrepo.Run(t, repoDir, "add", ".")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "initial")
+
+	gitrepo.Run(t, repoDir, "mv", "old", "new")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "move directory")
+	gitrepo.Run(t, repoDir, "mv", "new/a.go", "new/x.go")
+	gitrepo.Run(t, repoDir, "mv", "b.go", "d.go")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "rename files")
+
+	// when
+	got, err := git.PreviousPaths(repoDir, 10)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"new/x.go": {"new/a.go", "old/a.go"},
+		"d.go":     {"b.go"},
+	}, got)
+
+	// when
+	got, err = git.PreviousPaths(repoDir, 1)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"new/x.go": {"new/a.go"},
+		"d.go":     {"b.go"},
+	}, got)
+}
+
+func TestTopAuthors(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	commits := []struct {
+		file   string
+		author string
+	}{
+		{file: "docs/a.md", author: "Alice <alice@example.com>"},
+		{file: "docs/b.md", author: "Bob <bob@example.com>"},
+		{file: "docs/c.md", author: "Carol <carol@example.com>"},
+		{file: "docs/d.md", author: "Bob <bob@example.com>"},
+		{file: "src/main.go", author: "Dave <dave@example.com>"},
+	}
+	for _, c := range commits {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoDir, filepath.Dir(c.file)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, c.file), []byte(c.author), 0o600))
+		gitrepo.Run(t, repoDir, "add", ".")
+		gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "add "+c.file, "--author", c.author)
+	}
+
+	// when
+	got, err := git.TopAuthors(repoDir, "docs/", 10, 2)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob@example.com", "carol@example.com"}, got)
+}
+
+func TestCommitsInRange(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "a.go"), []byte("package a\n"), 0o600))
+	gitrepo.Run(t, repoDir, "add", ".")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "initial")
+	gitrepo.Run(t, repoDir, "tag", "v1")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "docs", "index.md"), []byte("# Docs\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "a.go"), []byte("package a\n\nfunc A() {}\n"), 0o600))
+	gitrepo.Run(t, repoDir, "add", ".")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "feat: add docs")
+	gitrepo.Run(t, repoDir, "rm", "--quiet", "a.go")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "remove a.go")
+
+	// when
+	got, err := git.CommitsInRange(repoDir, "v1..HEAD")
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "remove a.go", got[0].Subject)
+	assert.Equal(t, []string{"a.go"}, got[0].Files)
+	assert.Equal(t, "feat: add docs", got[1].Subject)
+	assert.Equal(t, []string{"a.go", "docs/index.md"}, got[1].Files)
+	assert.Len(t, got[0].SHA, 40)
+}
+
+func TestFirstAdded(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+
+	t.Setenv("GIT_COMMITTER_DATE", "2026-01-01T10:00:00Z")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "docs", "index.md"), []byte("# Docs\n"), 0o600))
+	gitrepo.Run(t, repoDir, "add", ".")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "initial")
+
+	t.Setenv("GIT_COMMITTER_DATE", "2026-02-01T10:00:00Z")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "docs", "index.md"), []byte("# Docs v2\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "docs", "faq.md"), []byte("# FAQ\n"), 0o600))
+	gitrepo.Run(t, repoDir, "add", ".")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "add faq")
+
+	// when
+	got, err := git.FirstAdded(repoDir)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"docs/index.md": time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+		"docs/faq.md":   time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC),
+	}, got)
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// ListFiles returns paths of all files tracked in the repository, relative to the repository root.
+// If paths are given, only files under them are returned.
+func ListFiles(repoDir string, paths ...string) ([]string, error) {
+	if UsesGoGit() {
+		return ListFilesGoGit(repoDir, paths...)
+	}
+
+	args := []string{"ls-files", "-z", "--"}
+	args = append(args, paths...)
+
+	gitls := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec(args...),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitls)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(stdout), "\x00") {
+		if f == "" {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
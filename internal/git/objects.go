@@ -0,0 +1,45 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// HashObject returns the blob SHA of a given file from the working tree, as it would be stored by git.
+func HashObject(repoDir, file string) (string, error) {
+	return output(repoDir, "hash-object", "--", file)
+}
+
+// RevParse returns the SHA of the commit a given revision points to.
+func RevParse(repoDir, rev string) (string, error) {
+	return output(repoDir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+}
+
+// TreeSHA returns the SHA of the tree of the HEAD commit.
+func TreeSHA(repoDir string) (string, error) {
+	return output(repoDir, "rev-parse", "HEAD^{tree}")
+}
+
+// IsDirty returns true if the working tree contains changes or untracked files.
+func IsDirty(repoDir string) (bool, error) {
+	out, err := output(repoDir, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+func output(repoDir string, args ...string) (string, error) {
+	gitcmd := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec(args...),
+	)
+	stdout, stderr, err := pipe.DividedOutput(gitcmd)
+	if err != nil {
+		return "", errors.Wrap(err, string(stderr))
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// scpLikeRegexp matches the scp-like SSH syntax, e.g. `git@github.com:owner/repo.git`.
+var scpLikeRegexp = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// Remote holds the repository coordinates parsed from the git remote URL.
+type Remote struct {
+	Host     string
+	Owner    string
+	Repo     string
+	Provider codeowners.Provider
+}
+
+// Repository returns the repository name in form 'owner/repository'.
+func (r Remote) Repository() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// RemoteURL returns URL of a given remote, e.g. origin.
+func RemoteURL(repoDir, name string) (string, error) {
+	if UsesGoGit() {
+		return remoteURLGoGit(repoDir, name)
+	}
+
+	gitremote := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec("remote", "get-url", name),
+	)
+
+	stdout, stderr, err := pipe.DividedOutput(gitremote)
+	if err != nil {
+		return "", errors.Wrap(err, string(stderr))
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// DetectRemote returns repository coordinates of a given remote.
+func DetectRemote(repoDir, name string) (Remote, error) {
+	rawURL, err := RemoteURL(repoDir, name)
+	if err != nil {
+		return Remote{}, errors.Wrapf(err, "while getting URL of the %q remote", name)
+	}
+	return ParseRemoteURL(rawURL)
+}
+
+// ParseRemoteURL parses HTTPS and SSH remote URLs, including the scp-like syntax and enterprise hosts.
+// GitLab repositories may be nested in subgroups, in such case the owner contains the whole group path.
+// Hosts with 'gitlab' in their name are treated as GitLab, all others as GitHub.
+func ParseRemoteURL(rawURL string) (Remote, error) {
+	var host, path string
+
+	if m := scpLikeRegexp.FindStringSubmatch(rawURL); m != nil && !strings.Contains(rawURL, "://") {
+		host, path = m[1], m[2]
+	} else {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return Remote{}, errors.Wrapf(err, "while parsing remote URL %q", rawURL)
+		}
+		host, path = u.Hostname(), u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	idx := strings.LastIndex(path, "/")
+	if host == "" || idx <= 0 || idx == len(path)-1 {
+		return Remote{}, errors.Errorf("cannot detect owner and repository from remote URL %q", rawURL)
+	}
+
+	provider := codeowners.GitHub
+	if strings.Contains(strings.ToLower(host), "gitlab") {
+		provider = codeowners.GitLab
+	}
+
+	return Remote{
+		Host:     host,
+		Owner:    path[:idx],
+		Repo:     path[idx+1:],
+		Provider: provider,
+	}, nil
+}
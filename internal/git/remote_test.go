@@ -0,0 +1,56 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := map[string]struct {
+		url      string
+		expected git.Remote
+	}{
+		"HTTPS": {
+			url:      "https://github.com/mszostok/codeowners.git",
+			expected: git.Remote{Host: "github.com", Owner: "mszostok", Repo: "codeowners", Provider: codeowners.GitHub},
+		},
+		"HTTPS without .git suffix and with credentials": {
+			url:      "https://token@github.com/mszostok/codeowners",
+			expected: git.Remote{Host: "github.com", Owner: "mszostok", Repo: "codeowners", Provider: codeowners.GitHub},
+		},
+		"SCP-like SSH": {
+			url:      "git@github.com:mszostok/codeowners.git",
+			expected: git.Remote{Host: "github.com", Owner: "mszostok", Repo: "codeowners", Provider: codeowners.GitHub},
+		},
+		"SSH with port on enterprise host": {
+			url:      "ssh://git@github.example.com:2222/platform/codeowners.git",
+			expected: git.Remote{Host: "github.example.com", Owner: "platform", Repo: "codeowners", Provider: codeowners.GitHub},
+		},
+		"GitLab with subgroups": {
+			url:      "git@gitlab.example.com:org/platform/codeowners.git",
+			expected: git.Remote{Host: "gitlab.example.com", Owner: "org/platform", Repo: "codeowners", Provider: codeowners.GitLab},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got, err := git.ParseRemoteURL(tc.url)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("Should return error if repository cannot be detected", func(t *testing.T) {
+		// when
+		_, err := git.ParseRemoteURL("https://github.com/mszostok")
+
+		// then
+		assert.EqualError(t, err, `cannot detect owner and repository from remote URL "https://github.com/mszostok"`)
+	})
+}
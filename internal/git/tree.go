@@ -0,0 +1,66 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// ListTree returns paths of all files in the tree of a given revision, relative to the repository root.
+func ListTree(repoDir, ref string) ([]string, error) {
+	stdout, err := rawOutput(repoDir, "ls-tree", "-r", "-z", "--name-only", ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(stdout), "\x00") {
+		if f == "" {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// ShowFile returns the content of a given file at a given revision.
+func ShowFile(repoDir, ref, file string) ([]byte, error) {
+	return rawOutput(repoDir, "show", fmt.Sprintf("%s:%s", ref, file))
+}
+
+// ChangedFilesBetween returns files changed between two revisions. Unlike ChangedFiles,
+// the changes are computed directly between both revisions, not from their merge base.
+func ChangedFilesBetween(repoDir, fromRef, toRef string) ([]ChangedFile, error) {
+	stdout, err := rawOutput(repoDir, "diff", "--no-color", "--no-ext-diff", "--name-status", "-z", "-M", fromRef, toRef, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNameStatus(stdout)
+}
+
+// ChangedFilesInRange returns files changed in a given revision range, e.g. `main..HEAD`, or `main...HEAD`
+// to compare with the merge base of both revisions.
+func ChangedFilesInRange(repoDir, revRange string) ([]ChangedFile, error) {
+	stdout, err := rawOutput(repoDir, "diff", "--no-color", "--no-ext-diff", "--name-status", "-z", "-M", revRange, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNameStatus(stdout)
+}
+
+func rawOutput(repoDir string, args ...string) ([]byte, error) {
+	gitcmd := pipe.Script(
+		pipe.ChDir(repoDir),
+		gitExec(args...),
+	)
+	stdout, stderr, err := pipe.DividedOutput(gitcmd)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	return stdout, nil
+}
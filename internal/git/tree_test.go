@@ -0,0 +1,60 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFilesInRange(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	gitrepo.Run(t, repoDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "a.go"), []byte("package a\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "b.go"), []byte("package b\n"), 0o600))
+	gitrepo.Run(t, repoDir, "add", ".")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "initial")
+
+	gitrepo.Run(t, repoDir, "checkout", "--quiet", "-b", "feature")
+	gitrepo.Run(t, repoDir, "mv", "a.go", "c.go")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "rename")
+
+	gitrepo.Run(t, repoDir, "checkout", "--quiet", "main")
+	gitrepo.Run(t, repoDir, "rm", "--quiet", "b.go")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "remove")
+
+	tests := map[string]struct {
+		revRange string
+		expected []git.ChangedFile
+	}{
+		"Should compare both revisions": {
+			revRange: "main..feature",
+			expected: []git.ChangedFile{
+				{Path: "b.go", Status: git.Added},
+				{Path: "c.go", Status: git.Renamed, OldPath: "a.go"},
+			},
+		},
+		"Should compare with the merge base": {
+			revRange: "main...feature",
+			expected: []git.ChangedFile{
+				{Path: "c.go", Status: git.Renamed, OldPath: "a.go"},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got, err := git.ChangedFilesInRange(repoDir, tc.revRange)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/internal/usage"
+
+	"github.com/pkg/errors"
+)
+
+// ResponseCache caches responses of GitHub API GET requests in memory.
+// It allows sharing the results of organization, team, and user lookups between validations
+// of multiple repositories, e.g. in the workspace mode, or between processes if saved to a file.
+type ResponseCache struct {
+	m         sync.RWMutex
+	createdAt time.Time
+	responses map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// cacheFile is the on-disk form of the ResponseCache.
+type cacheFile struct {
+	CreatedAt time.Time                 `json:"createdAt"`
+	Responses map[string]cachedResponse `json:"responses"`
+}
+
+// NewResponseCache returns new instance of the ResponseCache
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{
+		createdAt: time.Now(),
+		responses: map[string]cachedResponse{},
+	}
+}
+
+// LoadResponseCache reads the cache saved with the Save method. The cache older than maxAge is rejected,
+// as memberships and permissions may have changed in the meantime. Zero maxAge disables the expiration.
+func LoadResponseCache(path string, maxAge time.Duration) (*ResponseCache, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading GitHub response cache")
+	}
+
+	var f cacheFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, errors.Wrap(err, "while decoding GitHub response cache")
+	}
+	if age := time.Since(f.CreatedAt); maxAge > 0 && age > maxAge {
+		return nil, errors.Errorf("GitHub response cache %s expired, it was created %v ago", path, age.Round(time.Second))
+	}
+	if f.Responses == nil {
+		f.Responses = map[string]cachedResponse{}
+	}
+
+	return &ResponseCache{createdAt: f.CreatedAt, responses: f.Responses}, nil
+}
+
+// Save writes the cache to a given file, so it can be shared by multiple processes. The file is replaced
+// atomically and is readable only by the owner, as it contains organization data.
+func (c *ResponseCache) Save(path string) error {
+	c.m.RLock()
+	raw, err := json.Marshal(cacheFile{CreatedAt: c.createdAt, Responses: c.responses})
+	c.m.RUnlock()
+	if err != nil {
+		return errors.Wrap(err, "while encoding GitHub response cache")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "while creating GitHub response cache")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "while writing GitHub response cache")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "while writing GitHub response cache")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), "while writing GitHub response cache")
+}
+
+// Len returns the number of cached responses.
+func (c *ResponseCache) Len() int {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return len(c.responses)
+}
+
+// Wrap returns transport which serves GET requests from the cache if possible.
+func (c *ResponseCache) Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = netguard.BaseTransport()
+	}
+	return &cachingTransport{cache: c, base: base}
+}
+
+type cachingTransport struct {
+	cache *ResponseCache
+	base  http.RoundTripper
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	counters := usage.FromContext(req.Context())
+	key := req.URL.String()
+	if resp, found := t.cache.get(key); found {
+		counters.CacheHit()
+		return resp.toResponse(req), nil
+	}
+	counters.CacheMiss()
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// only successful and "not found" responses are deterministic enough to be cached
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	cached := cachedResponse{StatusCode: resp.StatusCode, Header: withoutRateLimit(resp.Header), Body: body}
+	t.cache.set(key, cached)
+
+	return cached.toResponse(req), nil
+}
+
+func (c *ResponseCache) get(key string) (cachedResponse, bool) {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	resp, found := c.responses[key]
+	return resp, found
+}
+
+func (c *ResponseCache) set(key string, resp cachedResponse) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.responses[key] = resp
+}
+
+func (r cachedResponse) toResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(r.StatusCode),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// withoutRateLimit drops the rate limit headers, as the client would otherwise
+// reject requests based on the outdated limits served from the cache.
+func withoutRateLimit(header http.Header) http.Header {
+	out := header.Clone()
+	for name := range out {
+		if strings.HasPrefix(strings.ToLower(name), "x-ratelimit-") {
+			out.Del(name)
+		}
+	}
+	return out
+}
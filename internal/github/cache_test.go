@@ -0,0 +1,137 @@
+package github_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/usage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	// given
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	cache := github.NewResponseCache()
+	clientA := &http.Client{Transport: cache.Wrap(nil)}
+	clientB := &http.Client{Transport: cache.Wrap(nil)}
+
+	// when
+	for _, c := range []*http.Client{clientA, clientB} {
+		resp, err := c.Get(srv.URL + "/ok")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "hello", string(body))
+
+		resp, err = c.Get(srv.URL + "/missing")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	// then
+	assert.Equal(t, 2, hits)
+}
+
+func TestResponseCacheSaveAndLoad(t *testing.T) {
+	// given
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "github-cache.json")
+	warm := github.NewResponseCache()
+	resp, err := (&http.Client{Transport: warm.Wrap(nil)}).Get(srv.URL + "/ok")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// when
+	require.NoError(t, warm.Save(path))
+	loaded, err := github.LoadResponseCache(path, time.Hour)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded.Len())
+
+	resp, err = (&http.Client{Transport: loaded.Wrap(nil)}).Get(srv.URL + "/ok")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "hello", string(body))
+	assert.Empty(t, resp.Header.Get("X-RateLimit-Remaining"), "outdated rate limits should not be served")
+	assert.Equal(t, 1, hits)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestLoadResponseCacheExpired(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "github-cache.json")
+	require.NoError(t, github.NewResponseCache().Save(path))
+
+	// when
+	_, err := github.LoadResponseCache(path, time.Nanosecond)
+
+	// then
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestResponseCacheCountsLookups(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	cache := github.NewResponseCache()
+	client := &http.Client{Transport: cache.Wrap(usage.Transport(nil))}
+
+	counters := &usage.Counters{}
+	ctx := usage.WithCounters(context.Background(), counters)
+
+	// when
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/ok", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// then
+	stats := counters.Stats("Foo Checker", time.Second)
+	assert.Equal(t, 1, stats.APICalls)
+	assert.Equal(t, 2, stats.CacheHits)
+	assert.Equal(t, 1, stats.CacheMisses)
+}
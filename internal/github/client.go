@@ -9,6 +9,10 @@ import (
 	"github.com/bradleyfalzon/ghinstallation/v2"
 
 	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/httpheader"
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/url"
 
 	"github.com/google/go-github/v41/github"
@@ -20,30 +24,57 @@ var httpRequestTimeout int = 30
 // Validate validates if provided client options are valid.
 func Validate(cfg *config.Config) error {
 	if cfg.GithubAccessToken == "" && cfg.GithubAppID == 0 {
-		return errors.New("GitHub authorization is required, provide ACCESS_TOKEN or APP_ID")
+		return &api.ConfigError{Field: "GITHUB_ACCESS_TOKEN", Err: errors.New("GitHub authorization is required, provide ACCESS_TOKEN or APP_ID")}
 	}
 
 	if cfg.GithubAccessToken != "" && cfg.GithubAppID != 0 {
-		return errors.New("GitHub ACCESS_TOKEN cannot be provided when APP_ID is specified")
+		return &api.ConfigError{Field: "GITHUB_ACCESS_TOKEN", Err: errors.New("GitHub ACCESS_TOKEN cannot be provided when APP_ID is specified")}
 	}
 
 	if cfg.GithubAppID != 0 {
 		if cfg.GithubAppInstallationID == 0 {
-			return errors.New("GitHub APP_INSTALLATION_ID is required with APP_ID")
+			return &api.ConfigError{Field: "GITHUB_APP_INSTALLATION_ID", Err: errors.New("GitHub APP_INSTALLATION_ID is required with APP_ID")}
 		}
 		if cfg.GithubAppPrivateKey == "" {
-			return errors.New("GitHub APP_PRIVATE_KEY is required with APP_ID")
+			return &api.ConfigError{Field: "GITHUB_APP_PRIVATE_KEY", Err: errors.New("GitHub APP_PRIVATE_KEY is required with APP_ID")}
 		}
 	}
 
 	return nil
 }
 
-func NewClient(ctx context.Context, cfg *config.Config) (ghClient *github.Client, isApp bool, err error) {
+// ClientOption allows to customize the GitHub client.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	cache     *ResponseCache
+	rateLimit *RateLimitTracker
+}
+
+// WithResponseCache serves GET requests from a given cache, which can be shared between clients.
+func WithResponseCache(cache *ResponseCache) ClientOption {
+	return func(o *clientOptions) {
+		o.cache = cache
+	}
+}
+
+// WithRateLimitTracker records the rate limit reported by GitHub responses in a given tracker.
+func WithRateLimitTracker(tracker *RateLimitTracker) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimit = tracker
+	}
+}
+
+func NewClient(ctx context.Context, cfg *config.Config, opts ...ClientOption) (ghClient *github.Client, isApp bool, err error) {
 	if err := Validate(cfg); err != nil {
 		return nil, false, err
 	}
 
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	httpClient := &http.Client{
 		Transport:     http.DefaultClient.Transport,
 		CheckRedirect: http.DefaultClient.CheckRedirect,
@@ -63,6 +94,21 @@ func NewClient(ctx context.Context, cfg *config.Config) (ghClient *github.Client
 		}
 	}
 
+	// configured headers replace the ones set by the GitHub client, e.g. the User-Agent, and don't affect cache keys
+	httpClient.Transport, err = httpheader.Wrap(cfg, httpClient.Transport)
+	if err != nil {
+		return nil, false, err
+	}
+	// requests are counted below the cache, so only the ones which reach the GitHub API are reported
+	httpClient.Transport = usage.Transport(httpClient.Transport)
+	// cached responses don't have the rate limit headers, so the tracker observes only the GitHub API responses
+	if options.rateLimit != nil {
+		httpClient.Transport = options.rateLimit.Wrap(httpClient.Transport)
+	}
+	if options.cache != nil {
+		httpClient.Transport = options.cache.Wrap(httpClient.Transport)
+	}
+
 	baseURL, uploadURL := cfg.GithubBaseURL, cfg.GithubUploadURL
 
 	if baseURL == "" {
@@ -80,7 +126,7 @@ func NewClient(ctx context.Context, cfg *config.Config) (ghClient *github.Client
 }
 
 func createAppInstallationHTTPClient(cfg *config.Config) (client *http.Client, err error) {
-	tr := http.DefaultTransport
+	tr := netguard.BaseTransport()
 	itr, err := ghinstallation.New(tr, cfg.GithubAppID, cfg.GithubAppInstallationID, []byte(cfg.GithubAppPrivateKey))
 	if err != nil {
 		return nil, err
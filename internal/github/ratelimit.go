@@ -0,0 +1,73 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/netguard"
+)
+
+// RateLimitTracker records the core API rate limit reported by GitHub responses, so callers can pause before
+// the limit is exceeded instead of failing with rate limit errors. It can be shared between clients which
+// use the same credentials.
+type RateLimitTracker struct {
+	m         sync.RWMutex
+	known     bool
+	remaining int
+	reset     time.Time
+}
+
+// NewRateLimitTracker returns new instance of the RateLimitTracker.
+func NewRateLimitTracker() *RateLimitTracker {
+	return &RateLimitTracker{}
+}
+
+// Remaining returns the number of requests left in the current rate limit window and the time the window
+// resets. The known result is false until the first response with rate limit headers is received.
+func (t *RateLimitTracker) Remaining() (remaining int, reset time.Time, known bool) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	return t.remaining, t.reset, t.known
+}
+
+// Wrap returns transport which records the rate limit of responses returned by a given transport.
+func (t *RateLimitTracker) Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = netguard.BaseTransport()
+	}
+	return &rateLimitTransport{tracker: t, base: base}
+}
+
+type rateLimitTransport struct {
+	tracker *RateLimitTracker
+	base    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.tracker.observe(resp.Header)
+	}
+	return resp, err
+}
+
+func (t *RateLimitTracker) observe(header http.Header) {
+	// search and GraphQL APIs have separate limits, which are not used by checks
+	if res := header.Get("X-RateLimit-Resource"); res != "" && res != "core" {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.known, t.remaining, t.reset = true, remaining, time.Unix(reset, 0)
+}
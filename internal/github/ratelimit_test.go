@@ -0,0 +1,48 @@
+package github_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/github"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitTracker(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			w.Header().Set("X-RateLimit-Resource", "search")
+			w.Header().Set("X-RateLimit-Remaining", "1")
+		default:
+			w.Header().Set("X-RateLimit-Resource", "core")
+			w.Header().Set("X-RateLimit-Remaining", "42")
+		}
+		w.Header().Set("X-RateLimit-Reset", "1664625600")
+	}))
+	defer srv.Close()
+
+	tracker := github.NewRateLimitTracker()
+	client := &http.Client{Transport: tracker.Wrap(nil)}
+
+	_, _, known := tracker.Remaining()
+	assert.False(t, known)
+
+	// when
+	for _, path := range []string{"/users/foo", "/search"} {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// then
+	remaining, reset, known := tracker.Remaining()
+	assert.True(t, known)
+	assert.Equal(t, 42, remaining)
+	assert.Equal(t, time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC), reset.UTC())
+}
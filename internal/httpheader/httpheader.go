@@ -0,0 +1,84 @@
+package httpheader
+
+import (
+	"net/http"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// Parse returns headers given in the 'Name: value' form, e.g. 'X-Org-Token: secret'.
+// Repeated names add multiple values of the same header.
+func Parse(raw []string) (http.Header, error) {
+	out := http.Header{}
+	for _, entry := range raw {
+		idx := strings.Index(entry, ":")
+		if idx == -1 {
+			return nil, errors.Errorf("wrong header %q, expected pattern 'Name: value'", entry)
+		}
+		name, value := strings.TrimSpace(entry[:idx]), strings.TrimSpace(entry[idx+1:])
+		if name == "" || strings.IndexFunc(name, notTokenChar) != -1 {
+			return nil, errors.Errorf("wrong header %q, %q is not a valid header name", entry, name)
+		}
+		if strings.IndexFunc(value, isControl) != -1 {
+			return nil, errors.Errorf("wrong header %q, the value has control characters", name)
+		}
+		out.Add(name, value)
+	}
+	return out, nil
+}
+
+// notTokenChar returns true for characters which are not allowed in header names, see RFC 7230.
+func notTokenChar(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	default:
+		return !strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+	}
+}
+
+func isControl(r rune) bool {
+	return (r < ' ' && r != '\t') || r == 0x7f
+}
+
+// Wrap returns the transport which adds headers and the User-Agent configured with HTTP_HEADERS and
+// HTTP_USER_AGENT to requests sent by a given base transport. If nothing is configured, the base
+// transport is returned as it is. If the base transport is nil, the netguard.BaseTransport is used.
+func Wrap(cfg *config.Config, base http.RoundTripper) (http.RoundTripper, error) {
+	header, err := Parse(cfg.HTTPHeaders)
+	if err != nil {
+		return nil, &api.ConfigError{Field: "HTTP_HEADERS", Err: err}
+	}
+	if len(header) == 0 && cfg.HTTPUserAgent == "" {
+		return base, nil
+	}
+	if base == nil {
+		base = netguard.BaseTransport()
+	}
+	return &headerTransport{base: base, header: header, userAgent: cfg.HTTPUserAgent}, nil
+}
+
+// headerTransport sets configured headers on each request. Configured headers replace the ones set by clients,
+// e.g. the default User-Agent of the GitHub client.
+type headerTransport struct {
+	base      http.RoundTripper
+	header    http.Header
+	userAgent string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// round trippers must not modify the original request
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,112 @@
+package httpheader_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/httpheader"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		givenHeaders []string
+		expHeader    http.Header
+		expErr       string
+	}{
+		"Should parse headers": {
+			givenHeaders: []string{"X-Org-Token: secret", "x-tenant:  eu:west ", "X-Tenant: us"},
+			expHeader:    http.Header{"X-Org-Token": {"secret"}, "X-Tenant": {"eu:west", "us"}},
+		},
+		"Should allow empty values": {
+			givenHeaders: []string{"X-Debug:"},
+			expHeader:    http.Header{"X-Debug": {""}},
+		},
+		"Should reject header without separator": {
+			givenHeaders: []string{"X-Org-Token secret"},
+			expErr:       `wrong header "X-Org-Token secret", expected pattern 'Name: value'`,
+		},
+		"Should reject invalid name": {
+			givenHeaders: []string{"X Org: secret"},
+			expErr:       `wrong header "X Org: secret", "X Org" is not a valid header name`,
+		},
+		"Should reject control characters in value": {
+			givenHeaders: []string{"X-Org-Token: sec\x00ret"},
+			expErr:       `wrong header "X-Org-Token", the value has control characters`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			header, err := httpheader.Parse(tc.givenHeaders)
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expHeader, header)
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("Should set headers and replace the User-Agent", func(t *testing.T) {
+		// given
+		var got http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+		}))
+		defer srv.Close()
+
+		transport, err := httpheader.Wrap(&config.Config{
+			HTTPHeaders:   []string{"X-Org-Token: secret"},
+			HTTPUserAgent: "corp-validator/1.0",
+		}, nil)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("User-Agent", "go-github")
+		req.Header.Set("Accept", "application/json")
+
+		// when
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "secret", got.Get("X-Org-Token"))
+		assert.Equal(t, "corp-validator/1.0", got.Get("User-Agent"))
+		assert.Equal(t, "application/json", got.Get("Accept"))
+		assert.Empty(t, req.Header.Get("X-Org-Token"), "original request must not be modified")
+	})
+
+	t.Run("Should return base transport if nothing is configured", func(t *testing.T) {
+		// given
+		base := &http.Transport{}
+
+		// when
+		transport, err := httpheader.Wrap(&config.Config{}, base)
+
+		// then
+		require.NoError(t, err)
+		assert.Same(t, base, transport)
+	})
+
+	t.Run("Should return config error for invalid headers", func(t *testing.T) {
+		// when
+		_, err := httpheader.Wrap(&config.Config{HTTPHeaders: []string{"X-Org-Token"}}, nil)
+
+		// then
+		var cfgErr *api.ConfigError
+		require.ErrorAs(t, err, &cfgErr)
+		assert.Equal(t, "HTTP_HEADERS", cfgErr.Field)
+	})
+}
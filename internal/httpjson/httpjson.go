@@ -0,0 +1,82 @@
+// Package httpjson fetches JSON documents from HTTP APIs, e.g. identity sources or JIRA.
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// Client fetches JSON documents from an HTTP API. Failed requests are returned as api.APIError.
+type Client struct {
+	provider  string
+	http      *http.Client
+	authorize func(*http.Request)
+}
+
+// New returns new instance of the Client. The provider is a human-readable API name used in errors,
+// and the authorize function, if not nil, sets credentials of each request.
+func New(provider string, client *http.Client, authorize func(*http.Request)) *Client {
+	return &Client{
+		provider:  provider,
+		http:      client,
+		authorize: authorize,
+	}
+}
+
+// BearerToken returns the authorize function which sends a given token as a bearer token.
+// It returns nil if the token is empty, so requests are sent without credentials.
+func BearerToken(token string) func(*http.Request) {
+	if token == "" {
+		return nil
+	}
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// Get fetches a given URL and decodes the JSON response into out.
+func (c *Client) Get(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "while creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authorize != nil {
+		c.authorize(req)
+	}
+
+	op := fmt.Sprintf("fetching %s", req.URL.Redacted())
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &api.APIError{Provider: c.provider, Op: op, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		limited, retryAfter := api.RateLimit(resp)
+		return &api.APIError{
+			Provider:    c.provider,
+			Op:          op,
+			StatusCode:  resp.StatusCode,
+			RateLimited: limited,
+			RetryAfter:  retryAfter,
+			Err:         errors.Errorf("unexpected status %s: %s", resp.Status, body),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "while decoding response from %s", req.URL.Redacted())
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package httpjson_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/httpjson"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGet(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprintf(w, `{"authorization": %q}`, r.Header.Get("Authorization"))
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/forbidden-limited":
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "not found")
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		token         string
+		path          string
+		expAuth       string
+		expStatusCode int
+		expLimited    bool
+		expRetryAfter time.Duration
+	}{
+		"Decodes response with bearer token": {
+			token:   "secret",
+			path:    "/ok",
+			expAuth: "Bearer secret",
+		},
+		"Sends no credentials without token": {
+			path: "/ok",
+		},
+		"Returns API error on unexpected status": {
+			path:          "/missing",
+			expStatusCode: http.StatusNotFound,
+		},
+		"Marks rate limited requests": {
+			path:          "/limited",
+			expStatusCode: http.StatusTooManyRequests,
+			expLimited:    true,
+		},
+		"Marks forbidden requests with Retry-After as rate limited": {
+			path:          "/forbidden-limited",
+			expStatusCode: http.StatusForbidden,
+			expLimited:    true,
+			expRetryAfter: 30 * time.Second,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			sut := httpjson.New("Test API", srv.Client(), httpjson.BearerToken(tc.token))
+
+			// when
+			var out struct {
+				Authorization string `json:"authorization"`
+			}
+			err := sut.Get(context.Background(), srv.URL+tc.path, &out)
+
+			// then
+			if tc.expStatusCode == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expAuth, out.Authorization)
+				return
+			}
+			var apiErr *api.APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, "Test API", apiErr.Provider)
+			assert.Equal(t, tc.expStatusCode, apiErr.StatusCode)
+			assert.Equal(t, tc.expLimited, apiErr.RateLimited)
+			assert.Equal(t, tc.expRetryAfter, apiErr.RetryAfter)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CSVSource reads identities from a CSV file with a header row, e.g.:
+//
+//	login,email,active
+//	alice,alice@example.com,true
+//	bob,bob@example.com,false
+//
+// The `active` column is optional, identities are active by default.
+type CSVSource struct {
+	path string
+}
+
+// NewCSVSource returns new instance of the CSVSource.
+func NewCSVSource(path string) *CSVSource {
+	return &CSVSource{path: path}
+}
+
+// Identities returns all identities listed in the CSV file.
+func (s *CSVSource) Identities(_ context.Context) ([]Identity, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening identity source")
+	}
+	defer f.Close()
+
+	return parseCSV(f)
+}
+
+func parseCSV(r io.Reader) ([]Identity, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading CSV header")
+	}
+
+	columns := map[string]int{}
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+	_, hasLogin := columns["login"]
+	_, hasEmail := columns["email"]
+	if !hasLogin && !hasEmail {
+		return nil, errors.New("CSV header must contain the 'login' or 'email' column")
+	}
+
+	get := func(record []string, name string) string {
+		idx, found := columns[name]
+		if !found || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	var out []Identity
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "while reading CSV record")
+		}
+
+		active := true
+		if raw := get(record, "active"); raw != "" {
+			active, err = strconv.ParseBool(raw)
+			if err != nil {
+				line, _ := reader.FieldPos(0)
+				return nil, errors.Wrapf(err, "while parsing 'active' column in line %d", line)
+			}
+		}
+
+		out = append(out, Identity{
+			Login:  get(record, "login"),
+			Email:  get(record, "email"),
+			Active: active,
+		})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,20 @@
+package identity
+
+import (
+	"net/http"
+	"time"
+
+	"go.szostok.io/codeowners/internal/httpjson"
+	"go.szostok.io/codeowners/internal/usage"
+)
+
+const (
+	providerName = "Identity source"
+	// requestTimeout limits each request to the identity source.
+	requestTimeout = 30 * time.Second
+)
+
+// newAPIClient returns the client of HTTP identity sources, which sends a given bearer token.
+func newAPIClient(token string) *httpjson.Client {
+	return httpjson.New(providerName, &http.Client{Transport: usage.Transport(nil), Timeout: requestTimeout}, httpjson.BearerToken(token))
+}
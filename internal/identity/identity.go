@@ -0,0 +1,112 @@
+package identity
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Identity represents a person known to the external identity source, e.g. HR system or IdP.
+type Identity struct {
+	// Login is the username, matched against the `@username` owners.
+	Login string
+	// Email is matched against the `user@example.com` owners.
+	Email string
+	// Active is false for suspended or deprovisioned identities, e.g. former employees.
+	Active bool
+}
+
+// Source lists identities from the external directory.
+type Source interface {
+	Identities(ctx context.Context) ([]Identity, error)
+}
+
+// SourceType defines the format of the identity source.
+type SourceType string
+
+const (
+	// CSV is a CSV file with the `login`, `email`, and `active` columns.
+	CSV SourceType = "csv"
+	// JSON is a JSON array of identities, read from a file or from an HTTP endpoint.
+	JSON SourceType = "json"
+	// SCIM is the SCIM 2.0 `/Users` endpoint, e.g. exposed by Okta or Azure AD.
+	SCIM SourceType = "scim"
+	// LDAP is an LDAP directory, e.g. OpenLDAP or Active Directory, searched as described by the LDAP URL.
+	LDAP SourceType = "ldap"
+)
+
+// Config holds the identity source configuration.
+type Config struct {
+	Type SourceType
+	// Location is either a file path or an HTTP(S) URL. For SCIM, it is the base URL of the SCIM API,
+	// and for LDAP, the LDAP URL of the search.
+	Location string
+	// Token is sent as a bearer token to HTTP sources, and as the bind password to LDAP.
+	Token string
+}
+
+// IsRemote returns true if identities are fetched over the network.
+func (c Config) IsRemote() bool {
+	switch SourceType(strings.ToLower(string(c.Type))) {
+	case SCIM, LDAP:
+		return true
+	default:
+		return isHTTP(c.Location)
+	}
+}
+
+// NewSource returns the identity source for a given configuration.
+func NewSource(cfg Config) (Source, error) {
+	if cfg.Location == "" {
+		return nil, errors.New("identity source location is required")
+	}
+
+	switch SourceType(strings.ToLower(string(cfg.Type))) {
+	case CSV:
+		return NewCSVSource(cfg.Location), nil
+	case JSON:
+		return NewJSONSource(cfg.Location, cfg.Token), nil
+	case SCIM:
+		return NewSCIMSource(cfg.Location, cfg.Token), nil
+	case LDAP:
+		return NewLDAPSource(cfg.Location, cfg.Token)
+	default:
+		return nil, errors.Errorf("unknown identity source type %q, supported types: %s, %s, %s, %s", cfg.Type, CSV, JSON, SCIM, LDAP)
+	}
+}
+
+// Directory indexes identities by login and email. Lookups are case-insensitive.
+type Directory struct {
+	byLogin map[string]Identity
+	byEmail map[string]Identity
+}
+
+// NewDirectory returns new instance of the Directory.
+func NewDirectory(identities []Identity) *Directory {
+	d := &Directory{
+		byLogin: map[string]Identity{},
+		byEmail: map[string]Identity{},
+	}
+	for _, i := range identities {
+		if i.Login != "" {
+			d.byLogin[strings.ToLower(i.Login)] = i
+		}
+		if i.Email != "" {
+			d.byEmail[strings.ToLower(i.Email)] = i
+		}
+	}
+	return d
+}
+
+// Lookup returns the identity of a given CODEOWNERS owner, either `@username` or `user@example.com`.
+func (d *Directory) Lookup(owner string) (Identity, bool) {
+	owner = strings.ToLower(owner)
+	if login := strings.TrimPrefix(owner, "@"); login != owner {
+		i, found := d.byLogin[login]
+		return i, found
+	}
+
+	i, found := d.byEmail[owner]
+	return i, found
+}
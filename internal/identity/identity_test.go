@@ -0,0 +1,187 @@
+package identity_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/identity"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var expectedIdentities = []identity.Identity{
+	{Login: "alice", Email: "alice@example.com", Active: true},
+	{Login: "bob", Email: "bob@example.com", Active: false},
+	{Email: "carol@example.com", Active: true},
+}
+
+func TestSources(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	mux.HandleFunc("/identities.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		http.ServeFile(w, r, filepath.Join("testdata", "identities.json"))
+	})
+	mux.HandleFunc("/scim/v2/Users", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Query().Get("startIndex") {
+		case "1":
+			fmt.Fprint(w, `{"totalResults": 3, "Resources": [
+				{"userName": "alice", "active": true, "emails": [{"value": "alice@private.com"}, {"value": "alice@example.com", "primary": true}]},
+				{"userName": "bob", "active": false, "emails": [{"value": "bob@example.com"}]}
+			]}`)
+		case "3":
+			fmt.Fprint(w, `{"totalResults": 3, "Resources": [{"userName": "", "emails": [{"value": "carol@example.com"}]}]}`)
+		default:
+			t.Errorf("unexpected start index %q", r.URL.Query().Get("startIndex"))
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ldapAddr := newLDAPServer(t, "cn=reader,dc=example,dc=com", "secret", "ou=people,dc=example,dc=com", []ldapEntry{
+		{dn: "uid=alice,ou=people,dc=example,dc=com", attrs: map[string]string{"sAMAccountName": "alice", "mail": "alice@example.com", "userAccountControl": "512"}},
+		{dn: "uid=bob,ou=people,dc=example,dc=com", attrs: map[string]string{"sAMAccountName": "bob", "mail": "bob@example.com", "userAccountControl": "514"}},
+		{dn: "cn=carol,ou=people,dc=example,dc=com", attrs: map[string]string{"mail": "carol@example.com"}},
+	})
+
+	tests := map[string]struct {
+		cfg identity.Config
+	}{
+		"CSV file": {
+			cfg: identity.Config{Type: identity.CSV, Location: filepath.Join("testdata", "identities.csv")},
+		},
+		"JSON file": {
+			cfg: identity.Config{Type: identity.JSON, Location: filepath.Join("testdata", "identities.json")},
+		},
+		"JSON endpoint": {
+			cfg: identity.Config{Type: identity.JSON, Location: srv.URL + "/identities.json", Token: "token"},
+		},
+		"SCIM endpoint": {
+			cfg: identity.Config{Type: identity.SCIM, Location: srv.URL + "/scim/v2/", Token: "token"},
+		},
+		"LDAP directory": {
+			cfg: identity.Config{
+				Type:     identity.LDAP,
+				Location: "ldap://" + ldapAddr + "/ou=people,dc=example,dc=com?sAMAccountName,mail?sub?(objectClass=user)?bindname=cn=reader%2cdc=example%2cdc=com",
+				Token:    "secret",
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			sut, err := identity.NewSource(tc.cfg)
+			require.NoError(t, err)
+
+			// when
+			got, err := sut.Identities(context.Background())
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, expectedIdentities, got)
+		})
+	}
+}
+
+func TestSourceHTTPError(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sut := identity.NewSCIMSource(srv.URL, "token")
+
+	// when
+	_, err := sut.Identities(context.Background())
+
+	// then
+	require.Error(t, err)
+	assert.True(t, api.IsRetryable(err))
+	assert.Equal(t, "Identity source API is temporarily unavailable, retry later.", api.Hint(err))
+}
+
+func TestLDAPSourceInvalidCredentials(t *testing.T) {
+	// given
+	addr := newLDAPServer(t, "cn=reader,dc=example,dc=com", "secret", "dc=example,dc=com", nil)
+	sut, err := identity.NewLDAPSource("ldap://"+addr+"/dc=example,dc=com????bindname=cn=reader%2cdc=example%2cdc=com", "wrong")
+	require.NoError(t, err)
+
+	// when
+	_, err = sut.Identities(context.Background())
+
+	// then
+	require.Error(t, err)
+	assert.False(t, api.IsRetryable(err))
+	assert.Equal(t, "Verify that the Identity source token is valid and not expired.", api.Hint(err))
+}
+
+func TestNewSourceErrors(t *testing.T) {
+	tests := map[string]struct {
+		cfg    identity.Config
+		expErr string
+	}{
+		"Missing location": {
+			cfg:    identity.Config{Type: identity.CSV},
+			expErr: "identity source location is required",
+		},
+		"Unknown type": {
+			cfg:    identity.Config{Type: "okta", Location: "https://example.okta.com"},
+			expErr: `unknown identity source type "okta", supported types: csv, json, scim, ldap`,
+		},
+		"LDAP URL with unsupported scheme": {
+			cfg:    identity.Config{Type: identity.LDAP, Location: "https://ldap.example.com/dc=example,dc=com"},
+			expErr: `unsupported LDAP URL scheme "https", supported schemes: ldap, ldaps`,
+		},
+		"LDAP URL with unknown scope": {
+			cfg:    identity.Config{Type: identity.LDAP, Location: "ldap://ldap.example.com/dc=example,dc=com?uid,mail?all"},
+			expErr: `unknown LDAP search scope "all", supported scopes: base, one, sub`,
+		},
+		"LDAP URL with critical extension": {
+			cfg:    identity.Config{Type: identity.LDAP, Location: "ldap://ldap.example.com/dc=example,dc=com????!x-starttls"},
+			expErr: `unsupported critical LDAP URL extension "x-starttls"`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := identity.NewSource(tc.cfg)
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestDirectoryLookup(t *testing.T) {
+	// given
+	sut := identity.NewDirectory(expectedIdentities)
+
+	tests := map[string]struct {
+		owner    string
+		expFound bool
+		expLogin string
+	}{
+		"User":                     {owner: "@alice", expFound: true, expLogin: "alice"},
+		"User with different case": {owner: "@Alice", expFound: true, expLogin: "alice"},
+		"Email":                    {owner: "bob@example.com", expFound: true, expLogin: "bob"},
+		"Unknown user":             {owner: "@dave", expFound: false},
+		"Login is not an email":    {owner: "alice", expFound: false},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got, found := sut.Lookup(tc.owner)
+
+			// then
+			assert.Equal(t, tc.expFound, found)
+			assert.Equal(t, tc.expLogin, got.Login)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/httpjson"
+
+	"github.com/pkg/errors"
+)
+
+// JSONSource reads identities from a JSON array, e.g.:
+//
+//	[
+//	  {"login": "alice", "email": "alice@example.com", "active": true},
+//	  {"login": "bob", "email": "bob@example.com", "active": false}
+//	]
+//
+// The array is read from a local file or fetched from an HTTP(S) endpoint. The `active` field is optional,
+// identities are active by default.
+type JSONSource struct {
+	location string
+	api      *httpjson.Client
+}
+
+type jsonIdentity struct {
+	Login  string `json:"login"`
+	Email  string `json:"email"`
+	Active *bool  `json:"active"`
+}
+
+// NewJSONSource returns new instance of the JSONSource.
+func NewJSONSource(location, token string) *JSONSource {
+	return &JSONSource{
+		location: location,
+		api:      newAPIClient(token),
+	}
+}
+
+// Identities returns all identities from the JSON array.
+func (s *JSONSource) Identities(ctx context.Context) ([]Identity, error) {
+	var raw []jsonIdentity
+	if isHTTP(s.location) {
+		if err := s.api.Get(ctx, s.location, &raw); err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := os.ReadFile(s.location)
+		if err != nil {
+			return nil, errors.Wrap(err, "while reading identity source")
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, errors.Wrapf(err, "while decoding %s", s.location)
+		}
+	}
+
+	out := make([]Identity, 0, len(raw))
+	for _, i := range raw {
+		out = append(out, Identity{
+			Login:  i.Login,
+			Email:  i.Email,
+			Active: i.Active == nil || *i.Active,
+		})
+	}
+	return out, nil
+}
+
+func isHTTP(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
@@ -0,0 +1,220 @@
+package identity
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	ldapPageSize = 500
+	// accountDisable is the ACCOUNTDISABLE flag of the Active Directory `userAccountControl` attribute.
+	accountDisable = 0x2
+)
+
+// LDAPSource lists identities from an LDAP directory, e.g. OpenLDAP or Active Directory. The search is described
+// by the LDAP URL (RFC 4516), e.g.:
+//
+//	ldaps://ldap.example.com/ou=people,dc=example,dc=com?uid,mail?sub?(objectClass=inetOrgPerson)?bindname=cn=reader%2cdc=example%2cdc=com
+//
+// The first attribute is used as the login and the second one as the email, `uid` and `mail` by default.
+// The scope is `sub` and the filter `(objectClass=*)` by default. If the `bindname` extension is set, the source
+// binds as that DN with the token as the password, otherwise it searches anonymously.
+// Entries with the ACCOUNTDISABLE flag of the Active Directory `userAccountControl` attribute are inactive,
+// other returned entries are active, so directories which mark former employees differently must exclude
+// them with the filter.
+type LDAPSource struct {
+	addr       string
+	useTLS     bool
+	serverName string
+	baseDN     string
+	loginAttr  string
+	emailAttr  string
+	scope      int
+	filter     string
+	bindDN     string
+	password   string
+}
+
+// NewLDAPSource returns new instance of the LDAPSource.
+func NewLDAPSource(location, password string) (*LDAPSource, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing LDAP URL")
+	}
+
+	s := &LDAPSource{
+		serverName: u.Hostname(),
+		baseDN:     strings.TrimPrefix(u.Path, "/"),
+		loginAttr:  "uid",
+		emailAttr:  "mail",
+		scope:      ldap.ScopeWholeSubtree,
+		filter:     "(objectClass=*)",
+		password:   password,
+	}
+	port := u.Port()
+	switch strings.ToLower(u.Scheme) {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		s.useTLS = true
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, errors.Errorf("unsupported LDAP URL scheme %q, supported schemes: ldap, ldaps", u.Scheme)
+	}
+	if s.serverName == "" {
+		return nil, errors.New("LDAP URL must have the host")
+	}
+	s.addr = net.JoinHostPort(s.serverName, port)
+
+	// the query holds the attributes, scope, filter, and extensions, separated with '?'
+	parts := strings.Split(u.RawQuery, "?")
+	if len(parts) > 4 {
+		return nil, errors.Errorf("LDAP URL %q has too many '?' separators", location)
+	}
+	for len(parts) < 4 {
+		parts = append(parts, "")
+	}
+	// extensions are decoded one by one, as their values may have escaped commas, e.g. in DNs
+	for idx, p := range parts[:3] {
+		if parts[idx], err = url.PathUnescape(p); err != nil {
+			return nil, errors.Wrap(err, "while decoding LDAP URL")
+		}
+	}
+	attrs, scope, filter, extensions := parts[0], parts[1], parts[2], parts[3]
+
+	if attrs != "" {
+		names := strings.Split(attrs, ",")
+		if len(names) > 2 {
+			return nil, errors.Errorf("LDAP URL lists %d attributes, only the login and email attributes are supported", len(names))
+		}
+		if names[0] != "" {
+			s.loginAttr = names[0]
+		}
+		if len(names) == 2 && names[1] != "" {
+			s.emailAttr = names[1]
+		}
+	}
+
+	switch strings.ToLower(scope) {
+	case "", "sub":
+	case "one":
+		s.scope = ldap.ScopeSingleLevel
+	case "base":
+		s.scope = ldap.ScopeBaseObject
+	default:
+		return nil, errors.Errorf("unknown LDAP search scope %q, supported scopes: base, one, sub", scope)
+	}
+
+	if filter != "" {
+		s.filter = filter
+	}
+	if _, err := ldap.CompileFilter(s.filter); err != nil {
+		return nil, errors.Wrapf(err, "while parsing LDAP filter %q", s.filter)
+	}
+
+	if extensions != "" {
+		for _, ext := range strings.Split(extensions, ",") {
+			ext, err := url.PathUnescape(ext)
+			if err != nil {
+				return nil, errors.Wrap(err, "while decoding LDAP URL extension")
+			}
+			name, value, _ := strings.Cut(ext, "=")
+			switch strings.ToLower(strings.TrimPrefix(name, "!")) {
+			case "bindname":
+				s.bindDN = value
+			default:
+				if strings.HasPrefix(name, "!") {
+					return nil, errors.Errorf("unsupported critical LDAP URL extension %q", strings.TrimPrefix(name, "!"))
+				}
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// Identities returns all entries matched by the search.
+func (s *LDAPSource) Identities(ctx context.Context) ([]Identity, error) {
+	op := fmt.Sprintf("searching LDAP directory %s", s.addr)
+	conn, err := s.dial(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &api.APIError{Provider: providerName, Op: op, Err: err}
+	}
+	defer conn.Close()
+
+	if s.bindDN != "" {
+		if err := conn.Bind(s.bindDN, s.password); err != nil {
+			apiErr := &api.APIError{Provider: providerName, Op: fmt.Sprintf("binding to LDAP directory %s as %s", s.addr, s.bindDN), Err: err}
+			if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+				// wrong credentials are not retried
+				apiErr.StatusCode = http.StatusUnauthorized
+			}
+			return nil, apiErr
+		}
+	}
+
+	req := ldap.NewSearchRequest(s.baseDN, s.scope, ldap.NeverDerefAliases, 0, 0, false, s.filter,
+		[]string{s.loginAttr, s.emailAttr, "userAccountControl"}, nil)
+	res, err := conn.SearchWithPaging(req, ldapPageSize)
+	if err != nil {
+		return nil, &api.APIError{Provider: providerName, Op: op, Err: err}
+	}
+
+	out := make([]Identity, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		out = append(out, Identity{
+			Login:  e.GetAttributeValue(s.loginAttr),
+			Email:  e.GetAttributeValue(s.emailAttr),
+			Active: !disabledAccount(e.GetAttributeValue("userAccountControl")),
+		})
+	}
+	return out, nil
+}
+
+// dial connects through the netguard.DialContext, so the connection is rejected when the network is disabled.
+func (s *LDAPSource) dial(ctx context.Context) (*ldap.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	raw, err := netguard.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+	if s.useTLS {
+		tlsConn := tls.Client(raw, &tls.Config{ServerName: s.serverName, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = raw.Close()
+			return nil, err
+		}
+		raw = tlsConn
+	}
+
+	conn := ldap.NewConn(raw, s.useTLS)
+	conn.Start()
+	conn.SetTimeout(requestTimeout)
+	return conn, nil
+}
+
+func disabledAccount(userAccountControl string) bool {
+	flags, err := strconv.ParseInt(userAccountControl, 10, 64)
+	return err == nil && flags&accountDisable != 0
+}
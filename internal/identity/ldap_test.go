@@ -0,0 +1,113 @@
+package identity_test
+
+import (
+	"net"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// LDAP protocol operations, see: https://www.rfc-editor.org/rfc/rfc4511#section-4.2
+const (
+	ldapBindRequest      ber.Tag = 0
+	ldapBindResponse     ber.Tag = 1
+	ldapUnbindRequest    ber.Tag = 2
+	ldapSearchRequest    ber.Tag = 3
+	ldapSearchResultItem ber.Tag = 4
+	ldapSearchResultDone ber.Tag = 5
+)
+
+// ldapEntry holds the distinguished name and attributes of a directory entry.
+type ldapEntry struct {
+	dn    string
+	attrs map[string]string
+}
+
+// newLDAPServer serves given entries to all searches of the expected base DN, and accepts only the bind
+// with the expected DN and password. It returns the server address.
+func newLDAPServer(t *testing.T, bindDN, password, baseDN string, entries []ldapEntry) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go serveLDAP(t, conn, bindDN, password, baseDN, entries)
+		}
+	}()
+
+	return lis.Addr().String()
+}
+
+func serveLDAP(t *testing.T, conn net.Conn, bindDN, password, baseDN string, entries []ldapEntry) {
+	defer conn.Close()
+
+	for {
+		req, err := ber.ReadPacket(conn)
+		if err != nil {
+			// the client closed the connection
+			return
+		}
+		msgID, op := req.Children[0].Value, req.Children[1]
+
+		switch op.Tag {
+		case ldapBindRequest:
+			code := 0
+			if op.Children[1].Value != bindDN || op.Children[2].Data.String() != password {
+				code = 49 // invalidCredentials
+			}
+			writeLDAP(t, conn, msgID, ldapResult(ldapBindResponse, code))
+		case ldapSearchRequest:
+			assert.Equal(t, baseDN, op.Children[0].Value)
+			for _, e := range entries {
+				writeLDAP(t, conn, msgID, ldapSearchEntry(e))
+			}
+			writeLDAP(t, conn, msgID, ldapResult(ldapSearchResultDone, 0))
+		case ldapUnbindRequest:
+			return
+		default:
+			t.Errorf("unexpected LDAP operation %d", op.Tag)
+			return
+		}
+	}
+}
+
+func ldapResult(tag ber.Tag, code int) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result code"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic message"))
+	return res
+}
+
+func ldapSearchEntry(e ldapEntry) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapSearchResultItem, nil, "Search result entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "Object name"))
+	attrs := ber.NewSequence("Attributes")
+	for name, value := range e.attrs {
+		attr := ber.NewSequence("Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		attr.AppendChild(values)
+		attrs.AppendChild(attr)
+	}
+	entry.AppendChild(attrs)
+	return entry
+}
+
+func writeLDAP(t *testing.T, conn net.Conn, msgID interface{}, op *ber.Packet) {
+	msg := ber.NewSequence("LDAP message")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, msgID, "Message ID"))
+	msg.AppendChild(op)
+	_, err := conn.Write(msg.Bytes())
+	assert.NoError(t, err)
+}
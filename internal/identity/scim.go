@@ -0,0 +1,80 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/httpjson"
+)
+
+const scimPageSize = 100
+
+// SCIMSource lists identities from the SCIM 2.0 `/Users` endpoint, e.g. exposed by Okta or Azure AD.
+// The `userName` attribute is used as the login, and the primary email as the email.
+type SCIMSource struct {
+	baseURL string
+	api     *httpjson.Client
+}
+
+type scimListResponse struct {
+	TotalResults int        `json:"totalResults"`
+	Resources    []scimUser `json:"Resources"`
+}
+
+type scimUser struct {
+	UserName string `json:"userName"`
+	Active   *bool  `json:"active"`
+	Emails   []struct {
+		Value   string `json:"value"`
+		Primary bool   `json:"primary"`
+	} `json:"emails"`
+}
+
+// NewSCIMSource returns new instance of the SCIMSource.
+func NewSCIMSource(baseURL, token string) *SCIMSource {
+	return &SCIMSource{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		api:     newAPIClient(token),
+	}
+}
+
+// Identities returns all users, including the deactivated ones.
+func (s *SCIMSource) Identities(ctx context.Context) ([]Identity, error) {
+	var out []Identity
+	// SCIM indexes are 1-based
+	for startIndex := 1; ; {
+		var page scimListResponse
+		url := fmt.Sprintf("%s/Users?startIndex=%d&count=%d", s.baseURL, startIndex, scimPageSize)
+		if err := s.api.Get(ctx, url, &page); err != nil {
+			return nil, err
+		}
+
+		for _, u := range page.Resources {
+			out = append(out, Identity{
+				Login:  u.UserName,
+				Email:  u.primaryEmail(),
+				Active: u.Active == nil || *u.Active,
+			})
+		}
+
+		startIndex += len(page.Resources)
+		if len(page.Resources) == 0 || startIndex > page.TotalResults {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+func (u scimUser) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
@@ -0,0 +1,243 @@
+package isolation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/tree"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// None executes checks directly on the host.
+	None = "none"
+	// Docker executes git-touching checks in disposable Docker containers.
+	Docker = "docker"
+
+	// DefaultImage contains the codeowners binary and git. Use the image in the same version as the host binary.
+	DefaultImage = "ghcr.io/mszostok/codeowners:stable"
+
+	// SourceDir is the container directory where the repository is mounted in read-only mode.
+	SourceDir = "/repo"
+
+	// ConfigEnv is the environment variable with the JSON-encoded configuration of the host, without credentials,
+	// which the isolated check is loaded with.
+	ConfigEnv = "ISOLATED_CHECK_CONFIG"
+)
+
+// isolatedChecks are checks that execute git commands on the repository or read its files, so they are executed
+// in containers. Checks which require network access, such as approvals, are always executed on the host.
+var isolatedChecks = map[string]struct{}{
+	"files":              {},
+	"not-owned":          {},
+	"path-hazards":       {},
+	"expensive-patterns": {},
+	"stewardship":        {},
+	"ownership-sla":      {},
+	"broad-ownership":    {},
+	"governance":         {},
+	"module-boundaries":  {},
+	"labeler":            {},
+}
+
+// IsIsolated returns true if a given check is executed in a container when the isolation is enabled.
+func IsIsolated(checkID string) bool {
+	_, found := isolatedChecks[checkID]
+	return found
+}
+
+// DockerConfig holds the Docker isolation configuration.
+type DockerConfig struct {
+	// Binary is the Docker CLI binary, defaults to `docker`.
+	Binary string
+	// Image contains the codeowners binary as an entrypoint, defaults to DefaultImage.
+	Image string
+	// Config is the resolved configuration of the host, passed to the container in the ConfigEnv variable,
+	// so the isolated check is configured the same way as on the host.
+	Config *config.Config
+}
+
+// Input is the check input resolved on the host, passed to the container on the standard input, so the isolated
+// check validates the same entries, e.g. parsed in the GitLab flavor or rendered from a template, and files.
+type Input struct {
+	Entries     []codeowners.Entry `json:"entries"`
+	DiffBaseRef string             `json:"diffBaseRef,omitempty"`
+	Scope       *api.Scope         `json:"scope,omitempty"`
+	// Tree holds files listed on the host with the tree of the input. It's nil if files are listed with git.
+	Tree     *tree.ListedTree   `json:"tree,omitempty"`
+	NonGit   bool               `json:"nonGit,omitempty"`
+	Baseline *baseline.Baseline `json:"baseline,omitempty"`
+}
+
+// NewInput returns the Input of a given check input.
+func NewInput(in api.Input) (Input, error) {
+	out := Input{
+		Entries:     in.CodeownersEntries,
+		DiffBaseRef: in.DiffBaseRef,
+		Scope:       in.Scope,
+		NonGit:      in.NonGit,
+	}
+
+	if in.Tree != nil {
+		// trees don't execute git commands, so files are listed on the host, where the tree tools are available
+		files, err := in.AllFiles()
+		if err != nil {
+			return Input{}, errors.Wrap(err, "while listing repository files")
+		}
+		out.Tree = &tree.ListedTree{Files: files}
+	}
+
+	if in.Baseline != nil {
+		known, ok := in.Baseline.(*baseline.Baseline)
+		if !ok {
+			return Input{}, errors.Errorf("baseline of type %T cannot be passed to container", in.Baseline)
+		}
+		out.Baseline = known
+	}
+
+	return out, nil
+}
+
+// APIInput returns the check input for a given repository directory.
+func (in Input) APIInput(repoDir string) api.Input {
+	out := api.Input{
+		RepoDir:           repoDir,
+		CodeownersEntries: in.Entries,
+		DiffBaseRef:       in.DiffBaseRef,
+		Scope:             in.Scope,
+		NonGit:            in.NonGit,
+	}
+	// interfaces are set only for non-nil values, as checks compare them with nil
+	if in.Tree != nil {
+		out.Tree = in.Tree
+	}
+	if in.Baseline != nil {
+		out.Baseline = in.Baseline
+	}
+	return out
+}
+
+// Result is printed by the isolated check to report its output to the host.
+type Result struct {
+	Output api.Output `json:"output"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// Checker executes a given check in a disposable Docker container. The repository is bind-mounted
+// in read-only mode and copied into the container writable layer before the check is executed,
+// so the check can never mutate the repository on the host.
+type Checker struct {
+	cfg     DockerConfig
+	checkID string
+	inner   api.Checker
+}
+
+// NewChecker returns new instance of the Checker
+func NewChecker(cfg DockerConfig, checkID string, inner api.Checker) *Checker {
+	if cfg.Binary == "" {
+		cfg.Binary = "docker"
+	}
+	if cfg.Image == "" {
+		cfg.Image = DefaultImage
+	}
+
+	return &Checker{
+		cfg:     cfg,
+		checkID: checkID,
+		inner:   inner,
+	}
+}
+
+// CheckAvailable returns an error if the Docker daemon cannot be reached.
+func (c DockerConfig) CheckAvailable(ctx context.Context) error {
+	binary := c.Binary
+	if binary == "" {
+		binary = "docker"
+	}
+
+	if _, err := exec.LookPath(binary); err != nil {
+		return errors.Wrap(err, "while looking for Docker CLI")
+	}
+	out, err := exec.CommandContext(ctx, binary, "version", "--format", "{{.Server.Version}}").CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "while connecting to Docker daemon: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Check executes the check in a container.
+func (c *Checker) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	if err := ctx.Err(); err != nil {
+		return api.Output{}, err
+	}
+
+	repoDir, err := filepath.Abs(in.RepoDir)
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	input, err := NewInput(in)
+	if err != nil {
+		return api.Output{}, err
+	}
+	rawInput, err := json.Marshal(input)
+	if err != nil {
+		return api.Output{}, errors.Wrap(err, "while encoding input for container")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.cfg.Binary, c.runArgs(repoDir)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = bytes.NewReader(rawInput), &stdout, &stderr
+	if c.cfg.Config != nil {
+		rawCfg, err := json.Marshal(c.cfg.Config.WithoutSecrets())
+		if err != nil {
+			return api.Output{}, errors.Wrap(err, "while encoding configuration for container")
+		}
+		// the configuration is passed in the environment, as it may exceed the command line length limit
+		cmd.Env = append(os.Environ(), ConfigEnv+"="+string(rawCfg))
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return api.Output{}, ctx.Err()
+		}
+		return api.Output{}, errors.Wrapf(err, "while running %s check in container: %s", c.checkID, strings.TrimSpace(stderr.String()))
+	}
+
+	var res Result
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return api.Output{}, errors.Wrapf(err, "while decoding %s check result from container", c.checkID)
+	}
+	if res.Error != "" {
+		return api.Output{}, errors.New(res.Error)
+	}
+
+	return res.Output, nil
+}
+
+func (c *Checker) runArgs(repoDir string) []string {
+	args := []string{
+		"run", "--rm", "--interactive",
+		"--network", "none",
+		"--volume", fmt.Sprintf("%s:%s:ro", repoDir, SourceDir),
+		"--env", ConfigEnv,
+	}
+
+	return append(args, c.cfg.Image, "isolated-check", "--source", SourceDir, "--check", c.checkID)
+}
+
+// Name returns human-readable name of the isolated validator
+func (c *Checker) Name() string {
+	return c.inner.Name()
+}
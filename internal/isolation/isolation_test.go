@@ -0,0 +1,285 @@
+package isolation_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/isolation"
+	"go.szostok.io/codeowners/internal/load"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/internal/tree"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckerRunsCheckInContainer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Docker CLI is a shell script")
+	}
+
+	// given
+	tmp := t.TempDir()
+	argsFile := filepath.Join(tmp, "args")
+	docker := fakeDocker(t, tmp, `echo "$@" > `+argsFile+`
+echo '{"output": {"Issues": [{"Severity": 2, "LineNo": 3, "Message": "issue"}]}}'`)
+
+	sut := isolation.NewChecker(isolation.DockerConfig{Binary: docker, Image: "codeowners:test"}, "files", check.NewFileExist())
+
+	// when
+	out, err := sut.Check(context.Background(), api.Input{RepoDir: "/src/repo"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, api.Output{Issues: []api.Issue{
+		{Severity: api.Warning, LineNo: ptr.Uint64Ptr(3), Message: "issue"},
+	}}, out)
+	assert.Equal(t, "File Exist Checker", sut.Name())
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "run --rm --interactive --network none --volume /src/repo:/repo:ro --env ISOLATED_CHECK_CONFIG "+
+		"codeowners:test isolated-check --source /repo --check files", strings.TrimSpace(string(args)))
+}
+
+func TestCheckerPassesConfigAndInputToContainer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Docker CLI is a shell script")
+	}
+
+	// given
+	repo := gitrepo.New(t)
+	gitrepo.CommitFiles(t, repo, map[string]string{
+		"CODEOWNERS": "* @org/all\n/docs/ @org/docs\n",
+		"main.go":    "package main\n",
+		"docs/a.md":  "# docs\n",
+	})
+	// the fake Docker CLI runs the isolated check in the test binary, see TestIsolatedCheckProcess
+	docker := fakeDocker(t, t.TempDir(), fmt.Sprintf(`%s=1 exec %q -test.run='^TestIsolatedCheckProcess$' -- "$@"`, isolatedProcessEnv, os.Args[0]))
+
+	allEntries := []codeowners.Entry{{LineNo: 1, Pattern: "*", Owners: []string{"@org/all"}}, {LineNo: 2, Pattern: "/docs/", Owners: []string{"@org/docs"}}}
+	docsEntries := []codeowners.Entry{{LineNo: 1, Pattern: "/docs/", Owners: []string{"@org/docs"}}}
+	docsScope, err := api.NewScope([]string{"/docs/"}, nil)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		cfg       config.Config
+		in        api.Input
+		expIssues []string
+	}{
+		"Default configuration": {
+			cfg: config.Config{},
+			in:  api.Input{CodeownersEntries: allEntries},
+		},
+		"Skipped patterns": {
+			cfg:       config.Config{NotOwnedCheckerSkipPatterns: []string{"*"}},
+			in:        api.Input{CodeownersEntries: allEntries},
+			expIssues: []string{"main.go"},
+		},
+		"Entries resolved on the host": {
+			in:        api.Input{CodeownersEntries: docsEntries},
+			expIssues: []string{"main.go"},
+		},
+		"Scope": {
+			in: api.Input{CodeownersEntries: docsEntries, Scope: docsScope},
+		},
+		"Tree": {
+			in:        api.Input{CodeownersEntries: docsEntries, Tree: &tree.ListedTree{Files: []string{"docs/a.md", "docs/b.md", "tools/build.sh"}}, NonGit: true},
+			expIssues: []string{"tools/build.sh"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			cfg := tc.cfg
+			sut := isolation.NewChecker(isolation.DockerConfig{Binary: docker, Config: &cfg}, "not-owned", check.NewNotOwnedFile(check.NotOwnedFileConfig{}))
+			in := tc.in
+			in.RepoDir = repo
+
+			// when
+			out, err := sut.Check(context.Background(), in)
+
+			// then
+			require.NoError(t, err)
+			require.Len(t, out.Issues, len(tc.expIssues))
+			for idx, path := range tc.expIssues {
+				assert.Contains(t, out.Issues[idx].Message, path)
+			}
+		})
+	}
+}
+
+func TestInputRoundTrip(t *testing.T) {
+	// given
+	scope, err := api.NewScope([]string{"/docs/"}, []string{"*.tmp"})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), baseline.DefaultFilename)
+	fingerprints := baseline.NewFingerprinter(nil)
+	require.NoError(t, baseline.NewFile(fingerprints.Findings("Not Owned File Checker", api.Issue{Paths: []string{"README.md"}})).Save(path))
+	known, err := baseline.Load(path)
+	require.NoError(t, err)
+
+	given := api.Input{
+		RepoDir:           "/src/repo",
+		CodeownersEntries: []codeowners.Entry{{LineNo: 2, Pattern: "/docs/", DefaultOwners: true, Section: &codeowners.Section{Name: "Docs", LineNo: 1, Owners: []string{"@docs"}}}},
+		DiffBaseRef:       "origin/main",
+		Tree:              &tree.ListedTree{Files: []string{"docs/a.md"}},
+		NonGit:            true,
+		Scope:             scope,
+		Baseline:          known,
+	}
+
+	// when
+	input, err := isolation.NewInput(given)
+	require.NoError(t, err)
+	raw, err := json.Marshal(input)
+	require.NoError(t, err)
+	var decoded isolation.Input
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	got := decoded.APIInput("/work")
+
+	// then
+	assert.Equal(t, "/work", got.RepoDir)
+	assert.Equal(t, given.CodeownersEntries, got.CodeownersEntries)
+	assert.Equal(t, given.DiffBaseRef, got.DiffBaseRef)
+	assert.True(t, got.NonGit)
+	assert.Equal(t, scope, got.Scope)
+	files, err := got.Tree.ListFiles("/work")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/a.md"}, files)
+	assert.True(t, got.Baseline.HasPath("Not Owned File Checker", "README.md"))
+	assert.False(t, got.Baseline.HasPath("Not Owned File Checker", "main.go"))
+
+	empty := isolation.Input{}.APIInput("/work")
+	assert.Nil(t, empty.Tree)
+	assert.Nil(t, empty.Baseline)
+}
+
+// TestIsolatedCheckProcess is not a real test. It's executed by the fake Docker CLI to run the isolated check
+// as the container would.
+func TestIsolatedCheckProcess(t *testing.T) {
+	if os.Getenv(isolatedProcessEnv) != "1" {
+		t.Skip("executed only by the fake Docker CLI")
+	}
+
+	var source, checkID string
+	args := os.Args
+	for i := 0; i < len(args)-1; i++ {
+		switch args[i] {
+		case "--volume":
+			source = strings.TrimSuffix(args[i+1], ":"+isolation.SourceDir+":ro")
+		case "--check":
+			checkID = args[i+1]
+		}
+	}
+
+	res := isolation.RunCheck(context.Background(), &config.Config{}, os.Stdin, source, filepath.Join(t.TempDir(), "work"), checkID,
+		func(ctx context.Context, cfg *config.Config) ([]api.Checker, error) {
+			return load.Checks(ctx, cfg)
+		})
+	require.NoError(t, json.NewEncoder(os.Stdout).Encode(res))
+	os.Exit(0)
+}
+
+func TestCheckerErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Docker CLI is a shell script")
+	}
+
+	tests := map[string]struct {
+		script string
+		expErr string
+	}{
+		"Container failed": {
+			script: `echo "Unable to find image" >&2; exit 125`,
+			expErr: "while running files check in container: Unable to find image: exit status 125",
+		},
+		"Check failed": {
+			script: `echo '{"error": "while listing repository files: exit status 128"}'`,
+			expErr: "while listing repository files: exit status 128",
+		},
+		"Malformed result": {
+			script: `echo 'not a json'`,
+			expErr: "while decoding files check result from container: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			docker := fakeDocker(t, t.TempDir(), tc.script)
+			sut := isolation.NewChecker(isolation.DockerConfig{Binary: docker}, "files", check.NewFileExist())
+
+			// when
+			out, err := sut.Check(context.Background(), api.Input{RepoDir: "/src/repo"})
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+			assert.Empty(t, out)
+		})
+	}
+}
+
+func TestIsIsolated(t *testing.T) {
+	assert.True(t, isolation.IsIsolated("not-owned"))
+	assert.True(t, isolation.IsIsolated("files"))
+	assert.True(t, isolation.IsIsolated("governance"))
+	assert.True(t, isolation.IsIsolated("labeler"))
+	assert.False(t, isolation.IsIsolated("syntax"))
+	assert.False(t, isolation.IsIsolated("owners"))
+	assert.False(t, isolation.IsIsolated("approvals"))
+}
+
+func TestCopyDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires additional privileges on Windows")
+	}
+
+	// given
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "copy")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".git", "refs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+	require.NoError(t, os.Symlink("run.sh", filepath.Join(src, "link.sh")))
+
+	// when
+	err := isolation.CopyDir(src, dst)
+
+	// then
+	require.NoError(t, err)
+
+	head, err := os.ReadFile(filepath.Join(dst, ".git", "HEAD"))
+	require.NoError(t, err)
+	assert.Equal(t, "ref: refs/heads/main\n", string(head))
+
+	assert.DirExists(t, filepath.Join(dst, ".git", "refs"))
+
+	fi, err := os.Stat(filepath.Join(dst, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dst, "link.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "run.sh", link)
+}
+
+const isolatedProcessEnv = "ISOLATION_TEST_PROCESS"
+
+func fakeDocker(t *testing.T, dir, script string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+	return path
+}
@@ -0,0 +1,72 @@
+package isolation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// Loader returns checks enabled in a given configuration.
+type Loader func(ctx context.Context, cfg *config.Config) ([]api.Checker, error)
+
+// RunCheck is executed in the container. It copies the repository from the source to the workdir and executes
+// a given check on the copy with the Input read from a given reader. The check is loaded with the configuration
+// of the host from the ConfigEnv variable, if set, or with a given configuration otherwise.
+func RunCheck(ctx context.Context, cfg *config.Config, input io.Reader, source, workdir, checkID string, load Loader) Result {
+	failed := func(err error) Result {
+		return Result{Error: err.Error()}
+	}
+
+	// isolated checks never need network, the container is also started without it
+	netguard.Enforce()
+
+	if raw, found := os.LookupEnv(ConfigEnv); found {
+		*cfg = config.Config{}
+		if err := json.Unmarshal([]byte(raw), cfg); err != nil {
+			return failed(errors.Wrapf(err, "while decoding %s", ConfigEnv))
+		}
+		if err := git.SetBackend(cfg.GitBackend); err != nil {
+			return failed(err)
+		}
+	}
+
+	var in Input
+	if err := json.NewDecoder(input).Decode(&in); err != nil {
+		return failed(errors.Wrap(err, "while decoding check input"))
+	}
+
+	if err := os.MkdirAll(workdir, 0o755); err != nil {
+		return failed(err)
+	}
+	if err := CopyDir(source, workdir); err != nil {
+		return failed(err)
+	}
+
+	// check IDs are unique across the checks and experimental checks, so exactly one check is loaded
+	cfg.Checks, cfg.ExperimentalChecks = []string{checkID}, []string{checkID}
+	cfg.Isolation, cfg.NoNetwork, cfg.RepositoryPath = None, true, workdir
+
+	checks, err := load(ctx, cfg)
+	if err != nil {
+		return failed(err)
+	}
+
+	var out api.Output
+	for _, c := range checks {
+		checkOut, err := c.Check(ctx, in.APIInput(workdir))
+		if err != nil {
+			return failed(err)
+		}
+		out.Issues = append(out.Issues, checkOut.Issues...)
+	}
+
+	return Result{Output: out}
+}
@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/httpjson"
+	"go.szostok.io/codeowners/internal/usage"
+
+	"github.com/pkg/errors"
+)
+
+const providerName = "JIRA"
+
+// Component is a JIRA project component.
+type Component struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Client fetches data from the JIRA REST API.
+type Client struct {
+	baseURL string
+	api     *httpjson.Client
+}
+
+// NewClient returns new instance of the Client. If the user is set, the token is sent with the basic
+// authentication, as required by JIRA Cloud API tokens. Otherwise, it's sent as a bearer token,
+// as required by JIRA Data Center personal access tokens.
+func NewClient(baseURL, user, token string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.Errorf("invalid JIRA base URL %q", baseURL)
+	}
+
+	authorize := httpjson.BearerToken(token)
+	if user != "" {
+		authorize = func(req *http.Request) {
+			req.SetBasicAuth(user, token)
+		}
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		api:     httpjson.New(providerName, &http.Client{Transport: usage.Transport(nil)}, authorize),
+	}, nil
+}
+
+// Components returns all components of a given project.
+func (c *Client) Components(ctx context.Context, project string) ([]Component, error) {
+	var out []Component
+	if err := c.api.Get(ctx, fmt.Sprintf("%s/rest/api/2/project/%s/components", c.baseURL, url.PathEscape(project)), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package jira_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/jira"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientComponents(t *testing.T) {
+	tests := map[string]struct {
+		user    string
+		token   string
+		expAuth string
+	}{
+		"Bearer token": {
+			token:   "pat",
+			expAuth: "Bearer pat",
+		},
+		"Basic authentication": {
+			user:    "bot@example.com",
+			token:   "api-token",
+			expAuth: "Basic Ym90QGV4YW1wbGUuY29tOmFwaS10b2tlbg==",
+		},
+		"Anonymous": {},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			var gotAuth string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/jira/rest/api/2/project/PAY/components", r.URL.Path)
+				gotAuth = r.Header.Get("Authorization")
+				_, _ = w.Write([]byte(`[{"id": "10000", "name": "Payments API", "description": "ignored"}]`))
+			}))
+			defer srv.Close()
+
+			sut, err := jira.NewClient(srv.URL+"/jira/", tc.user, tc.token)
+			require.NoError(t, err)
+
+			// when
+			components, err := sut.Components(context.Background(), "PAY")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []jira.Component{{ID: "10000", Name: "Payments API"}}, components)
+			assert.Equal(t, tc.expAuth, gotAuth)
+		})
+	}
+}
+
+func TestClientComponentsError(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sut, err := jira.NewClient(srv.URL, "", "")
+	require.NoError(t, err)
+
+	// when
+	_, err = sut.Components(context.Background(), "PAY")
+
+	// then
+	var apiErr *api.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.True(t, api.IsRetryable(err))
+}
+
+func TestNewClientInvalidURL(t *testing.T) {
+	// when
+	_, err := jira.NewClient("jira.example.com", "", "")
+
+	// then
+	assert.EqualError(t, err, `invalid JIRA base URL "jira.example.com"`)
+}
@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping maps JIRA components of a project to the teams that triage their bugs, and to the repository
+// paths they cover, e.g.:
+//
+//	project: PAY
+//	components:
+//	  Payments API:
+//	    team: "@org/payments"
+//	    paths: [/services/payments/, /api/payments.proto]
+type Mapping struct {
+	// Project is the JIRA project key.
+	Project    string                      `yaml:"project"`
+	Components map[string]ComponentMapping `yaml:"components"`
+}
+
+// ComponentMapping describes a single component.
+type ComponentMapping struct {
+	// Team is the CODEOWNERS owner expected for all component paths.
+	Team string `yaml:"team"`
+	// Paths relative to the repository root. Paths ending with a slash are directories.
+	Paths []string `yaml:"paths"`
+}
+
+// LoadMapping reads the mapping file in YAML format.
+func LoadMapping(path string) (*Mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening JIRA mapping file")
+	}
+	defer f.Close()
+
+	return ParseMapping(f)
+}
+
+// ParseMapping decodes the mapping in YAML format.
+func ParseMapping(r io.Reader) (*Mapping, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var out Mapping
+	if err := dec.Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "while decoding JIRA mapping")
+	}
+
+	if out.Project == "" {
+		return nil, errors.New("JIRA project is required")
+	}
+	for name, c := range out.Components {
+		if c.Team == "" {
+			return nil, errors.Errorf("component %q has no team", name)
+		}
+	}
+	return &out, nil
+}
@@ -0,0 +1,348 @@
+// Package labeler keeps the configuration of the GitHub labeler action (https://github.com/actions/labeler)
+// in sync with CODEOWNERS. Each team owner gets a label, e.g. `team/payments` for `@org/payments`,
+// applied to pull requests which change files matched by the team patterns.
+package labeler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the default location of the labeler configuration in the repository.
+const DefaultFile = ".github/labeler.yml"
+
+// DefaultLabelPrefix is the default prefix of team labels.
+const DefaultLabelPrefix = "team/"
+
+// Keys of the labeler rules which globs are compared with CODEOWNERS.
+const (
+	// anyKey is the rule of the labeler v4 and older, e.g. `- any: ['docs/**']`.
+	anyKey = "any"
+	// changedFilesKey and anyGlobKey form the rule of the labeler v5, e.g.
+	// `- changed-files: [{any-glob-to-any-file: ['docs/**']}]`.
+	changedFilesKey = "changed-files"
+	anyGlobKey      = "any-glob-to-any-file"
+)
+
+// Label is a single label of the labeler configuration.
+type Label struct {
+	Name string
+	// Team is the owner of the generated label. It's empty for labels read from the configuration.
+	Team string
+	// Globs are sorted globs of changed files for which the label is applied.
+	Globs []string
+}
+
+// Config is the labeler configuration. Labels are kept in the file order.
+type Config struct {
+	Labels []Label
+	// Legacy is true if the configuration uses the format of the labeler v4 and older.
+	Legacy bool
+
+	root *yaml.Node
+}
+
+// Load reads the labeler configuration. It returns an error satisfying os.IsNotExist if the file doesn't exist.
+func Load(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(raw))
+}
+
+// Parse decodes the labeler configuration. Globs are read from rules matching any changed file, both in the v5
+// format and in the format of older versions. Other rules, e.g. `all-globs-to-all-files` or `head-branch`,
+// are kept as they are, but they are not compared with CODEOWNERS.
+func Parse(r io.Reader) (*Config, error) {
+	var doc yaml.Node
+	err := yaml.NewDecoder(r).Decode(&doc)
+	switch {
+	case errors.Is(err, io.EOF):
+		return &Config{}, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "while decoding labeler configuration")
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("labeler configuration must be a mapping of labels to rules")
+	}
+
+	out := &Config{root: &doc}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		globs, legacy := ruleGlobs(root.Content[i+1])
+		out.Labels = append(out.Labels, Label{Name: root.Content[i].Value, Globs: sortedUnique(globs)})
+		out.Legacy = out.Legacy || legacy
+	}
+	return out, nil
+}
+
+// Lookup returns the label with a given name.
+func (c *Config) Lookup(name string) (Label, bool) {
+	for _, l := range c.Labels {
+		if l.Name == name {
+			return l, true
+		}
+	}
+	return Label{}, false
+}
+
+// ruleGlobs returns globs of rules matching any changed file. It returns true if rules use the legacy format.
+func ruleGlobs(rules *yaml.Node) (globs []string, legacy bool) {
+	if rules.Kind != yaml.SequenceNode {
+		return nil, false
+	}
+
+	for _, rule := range rules.Content {
+		switch rule.Kind {
+		case yaml.ScalarNode:
+			globs, legacy = append(globs, rule.Value), true
+		case yaml.MappingNode:
+			if seq := mappingValue(rule, anyKey); seq != nil {
+				globs, legacy = append(globs, scalars(seq)...), true
+			}
+			changed := mappingValue(rule, changedFilesKey)
+			if changed == nil || changed.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, match := range changed.Content {
+				if seq := mappingValue(match, anyGlobKey); seq != nil {
+					globs = append(globs, scalars(seq)...)
+				}
+			}
+		}
+	}
+	return globs, legacy
+}
+
+// Generate returns the team labels of CODEOWNERS entries, sorted by name. Globs of each entry are added to labels
+// of all its team owners. The labeler applies all matching labels, so unlike in CODEOWNERS, labels of earlier
+// entries are applied even if a later pattern matches the same file.
+func Generate(entries []codeowners.Entry, prefix string) []Label {
+	byName := map[string]*Label{}
+	for _, e := range entries {
+		for _, owner := range e.Owners {
+			name, ok := LabelName(owner, prefix)
+			if !ok {
+				continue
+			}
+			if byName[name] == nil {
+				byName[name] = &Label{Name: name, Team: owner}
+			}
+			byName[name].Globs = append(byName[name].Globs, Globs(e.Pattern)...)
+		}
+	}
+
+	out := make([]Label, 0, len(byName))
+	for _, l := range byName {
+		out = append(out, Label{Name: l.Name, Team: l.Team, Globs: sortedUnique(l.Globs)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// LabelName returns the label of a given team owner, e.g. `team/payments` for `@org/payments`.
+// Users and emails don't have labels.
+func LabelName(owner, prefix string) (string, bool) {
+	if !strings.HasPrefix(owner, "@") {
+		return "", false
+	}
+	idx := strings.LastIndex(owner, "/")
+	if idx < 0 || idx == len(owner)-1 {
+		return "", false
+	}
+	return prefix + strings.ToLower(owner[idx+1:]), true
+}
+
+// Globs converts the CODEOWNERS pattern into labeler globs, which match the same files:
+//
+//	/docs/     -> docs/**
+//	docs/      -> **/docs/**
+//	/build.go  -> build.go, build.go/**
+//	*.go       -> **/*.go
+//	/docs/*    -> docs/*
+func Globs(pattern string) []string {
+	p := strings.TrimSpace(pattern)
+	if p == "*" || p == "/*" || p == "**" || p == "/**" {
+		return []string{"**"}
+	}
+
+	// as in gitignore, patterns with a slash other than the trailing one are relative to the root
+	anchored := strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	dir := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(strings.TrimPrefix(p, "/"), "/")
+	if !anchored && !strings.HasPrefix(p, "**/") {
+		p = "**/" + p
+	}
+
+	last := path.Base(p)
+	switch {
+	case dir:
+		return []string{p + "/**"}
+	case last == "**" || strings.ContainsAny(last, "*?["):
+		return []string{p}
+	default:
+		// a literal name matches both a file and a directory with all its files
+		return []string{p, p + "/**"}
+	}
+}
+
+// Drift describes a difference between the labeler configuration and the labels generated from CODEOWNERS.
+type Drift struct {
+	Label string
+	// Team is the owner of the label, empty for stale labels.
+	Team string
+	// Missing is true if the label is generated, but it's not in the configuration.
+	Missing bool
+	// Stale is true if the label is in the configuration, but no team owns any pattern in CODEOWNERS.
+	Stale bool
+	// MissingGlobs are generated globs which are not in the configuration.
+	MissingGlobs []string
+	// ExtraGlobs are globs in the configuration which are not generated.
+	ExtraGlobs []string
+}
+
+// Diff compares team labels of the configuration with the generated ones. Labels without a given prefix are ignored.
+func Diff(cfg *Config, generated []Label, prefix string) []Drift {
+	var out []Drift
+
+	for _, want := range generated {
+		got, found := cfg.Lookup(want.Name)
+		if !found {
+			out = append(out, Drift{Label: want.Name, Team: want.Team, Missing: true, MissingGlobs: want.Globs})
+			continue
+		}
+		missing, extra := difference(want.Globs, got.Globs), difference(got.Globs, want.Globs)
+		if len(missing) > 0 || len(extra) > 0 {
+			out = append(out, Drift{Label: want.Name, Team: want.Team, MissingGlobs: missing, ExtraGlobs: extra})
+		}
+	}
+
+	wanted := map[string]struct{}{}
+	for _, l := range generated {
+		wanted[l.Name] = struct{}{}
+	}
+	for _, l := range cfg.Labels {
+		if _, found := wanted[l.Name]; found || !strings.HasPrefix(l.Name, prefix) {
+			continue
+		}
+		out = append(out, Drift{Label: l.Name, Stale: true, ExtraGlobs: l.Globs})
+	}
+	return out
+}
+
+// Render returns the configuration with team labels replaced by the generated ones. Labels without
+// a given prefix are kept as they are, including comments, and the generated labels are appended
+// after them. The format of the labeler v5 is used, unless the configuration uses the legacy one.
+func Render(cfg *Config, generated []Label, prefix string) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	if cfg.root != nil {
+		doc.HeadComment, doc.FootComment = cfg.root.HeadComment, cfg.root.FootComment
+		existing := cfg.root.Content[0]
+		for i := 0; i+1 < len(existing.Content); i += 2 {
+			if strings.HasPrefix(existing.Content[i].Value, prefix) {
+				continue
+			}
+			root.Content = append(root.Content, existing.Content[i], existing.Content[i+1])
+		}
+	}
+
+	for _, l := range generated {
+		root.Content = append(root.Content, scalar(l.Name), labelRules(l.Globs, cfg.Legacy))
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, errors.Wrap(err, "while encoding labeler configuration")
+	}
+	if err := enc.Close(); err != nil {
+		return nil, errors.Wrap(err, "while encoding labeler configuration")
+	}
+	return buf.Bytes(), nil
+}
+
+func labelRules(globs []string, legacy bool) *yaml.Node {
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, g := range globs {
+		seq.Content = append(seq.Content, scalar(g))
+	}
+	if legacy {
+		return seq
+	}
+
+	match := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalar(anyGlobKey), seq}}
+	changed := &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{match}}
+	rule := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalar(changedFilesKey), changed}}
+	return &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{rule}}
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalars(node *yaml.Node) []string {
+	if node.Kind == yaml.ScalarNode {
+		return []string{node.Value}
+	}
+	var out []string
+	for _, n := range node.Content {
+		if n.Kind == yaml.ScalarNode {
+			out = append(out, n.Value)
+		}
+	}
+	return out
+}
+
+func sortedUnique(items []string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(items))
+	for _, i := range items {
+		if _, found := seen[i]; found {
+			continue
+		}
+		seen[i] = struct{}{}
+		out = append(out, i)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// difference returns items of a which are not in b.
+func difference(a, b []string) []string {
+	in := map[string]struct{}{}
+	for _, i := range b {
+		in[i] = struct{}{}
+	}
+	var out []string
+	for _, i := range a {
+		if _, found := in[i]; !found {
+			out = append(out, i)
+		}
+	}
+	return out
+}
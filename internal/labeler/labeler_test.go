@@ -0,0 +1,191 @@
+package labeler_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/labeler"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCODEOWNERS = `
+*           @org/platform
+/docs/      @org/Docs @alice
+*.md        @org/docs
+/build.go   @org/platform
+/vendor/
+`
+
+func TestGlobs(t *testing.T) {
+	tests := map[string]struct {
+		pattern  string
+		expGlobs []string
+	}{
+		"Catch-all":                   {pattern: "*", expGlobs: []string{"**"}},
+		"Anchored directory":          {pattern: "/docs/", expGlobs: []string{"docs/**"}},
+		"Unanchored directory":        {pattern: "docs/", expGlobs: []string{"**/docs/**"}},
+		"Anchored literal":            {pattern: "/build.go", expGlobs: []string{"build.go", "build.go/**"}},
+		"Nested path is anchored":     {pattern: "cmd/main.go", expGlobs: []string{"cmd/main.go", "cmd/main.go/**"}},
+		"Extension":                   {pattern: "*.go", expGlobs: []string{"**/*.go"}},
+		"Direct children":             {pattern: "/docs/*", expGlobs: []string{"docs/*"}},
+		"Double asterisk is kept":     {pattern: "**/logs", expGlobs: []string{"**/logs", "**/logs/**"}},
+		"Trailing double asterisk":    {pattern: "/apps/**", expGlobs: []string{"apps/**"}},
+		"Single-segment literal name": {pattern: "Makefile", expGlobs: []string{"**/Makefile", "**/Makefile/**"}},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			globs := labeler.Globs(tc.pattern)
+
+			// then
+			assert.Equal(t, tc.expGlobs, globs)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS))
+
+	// when
+	labels := labeler.Generate(entries, "team/")
+
+	// then
+	assert.Equal(t, []labeler.Label{
+		{Name: "team/docs", Team: "@org/Docs", Globs: []string{"**/*.md", "docs/**"}},
+		{Name: "team/platform", Team: "@org/platform", Globs: []string{"**", "build.go", "build.go/**"}},
+	}, labels)
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		config    string
+		expLabels []labeler.Label
+		expLegacy bool
+	}{
+		"Labeler v5": {
+			config: `
+team/docs:
+- changed-files:
+  - any-glob-to-any-file: ['docs/**', '**/*.md']
+  - all-globs-to-all-files: ['!**/*.go']
+release:
+- base-branch: 'main'
+`,
+			expLabels: []labeler.Label{
+				{Name: "team/docs", Globs: []string{"**/*.md", "docs/**"}},
+				{Name: "release", Globs: []string{}},
+			},
+		},
+		"Labeler v4": {
+			config: `
+team/docs: ['docs/**']
+team/platform:
+- any: ['**', 'build.go']
+`,
+			expLabels: []labeler.Label{
+				{Name: "team/docs", Globs: []string{"docs/**"}},
+				{Name: "team/platform", Globs: []string{"**", "build.go"}},
+			},
+			expLegacy: true,
+		},
+		"Empty file": {config: "# no labels yet\n"},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			cfg, err := labeler.Parse(strings.NewReader(tc.config))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expLabels, cfg.Labels)
+			assert.Equal(t, tc.expLegacy, cfg.Legacy)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	// given
+	cfg, err := labeler.Parse(strings.NewReader(`
+team/docs: ['docs/**', 'website/**']
+team/old: ['legacy/**']
+documentation: ['**/*.md']
+`))
+	require.NoError(t, err)
+	generated := labeler.Generate(codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS)), "team/")
+
+	// when
+	drifts := labeler.Diff(cfg, generated, "team/")
+
+	// then
+	assert.Equal(t, []labeler.Drift{
+		{Label: "team/docs", Team: "@org/Docs", MissingGlobs: []string{"**/*.md"}, ExtraGlobs: []string{"website/**"}},
+		{Label: "team/platform", Team: "@org/platform", Missing: true, MissingGlobs: []string{"**", "build.go", "build.go/**"}},
+		{Label: "team/old", Stale: true, ExtraGlobs: []string{"legacy/**"}},
+	}, drifts)
+}
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		config    string
+		expOutput string
+	}{
+		"Keeps other labels and replaces team labels": {
+			config: `# Managed partially by codeowners
+release:
+  - base-branch: 'main' # release branches
+team/old:
+  - changed-files:
+      - any-glob-to-any-file: ['legacy/**']
+`,
+			expOutput: `# Managed partially by codeowners
+release:
+  - base-branch: 'main' # release branches
+team/docs:
+  - changed-files:
+      - any-glob-to-any-file:
+          - '**/*.md'
+          - docs/**
+team/platform:
+  - changed-files:
+      - any-glob-to-any-file:
+          - '**'
+          - build.go
+          - build.go/**
+`,
+		},
+		"Keeps the legacy format": {
+			config: "team/docs: ['docs/**']\n",
+			expOutput: `team/docs:
+  - '**/*.md'
+  - docs/**
+team/platform:
+  - '**'
+  - build.go
+  - build.go/**
+`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			cfg, err := labeler.Parse(strings.NewReader(tc.config))
+			require.NoError(t, err)
+			generated := labeler.Generate(codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS)), "team/")
+
+			// when
+			out, err := labeler.Render(cfg, generated, "team/")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOutput, string(out))
+
+			rendered, err := labeler.Parse(strings.NewReader(string(out)))
+			require.NoError(t, err)
+			assert.Empty(t, labeler.Diff(rendered, generated, "team/"))
+		})
+	}
+}
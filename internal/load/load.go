@@ -1,12 +1,23 @@
+//go:build !minimal
+
 package load
 
 import (
 	"context"
+	"os"
+	"strings"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/check"
 	"go.szostok.io/codeowners/internal/config"
 	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/identity"
+	"go.szostok.io/codeowners/internal/isolation"
+	"go.szostok.io/codeowners/internal/jira"
+	"go.szostok.io/codeowners/internal/oncall"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
 
 	"github.com/pkg/errors"
 	"github.com/vrischmann/envconfig"
@@ -16,11 +27,35 @@ import (
 // and do not create clients which will not be used because of the given checker.
 //
 // MAYBE in the future the https://github.com/uber-go/dig will be used.
-func Checks(ctx context.Context, cfg *config.Config) ([]api.Checker, error) {
+//
+// Given client options are applied to all created GitHub clients.
+func Checks(ctx context.Context, cfg *config.Config, opts ...github.ClientOption) ([]api.Checker, error) {
 	var checks []api.Checker
 
+	if err := validateNoNetwork(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := validateNoGit(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := validateIsolation(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	providers, err := codeowners.ParseProviders(cfg.Providers)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing providers")
+	}
+
 	if isEnabled(cfg.Checks, "syntax") {
-		checks = append(checks, check.NewValidSyntax())
+		checks = append(checks, check.NewValidSyntax().WithProviders(providers...))
+	}
+
+	// dialects are compared only if providers are explicitly configured
+	if len(cfg.Providers) > 0 && isEnabled(cfg.Checks, "dialects") {
+		checks = append(checks, check.NewProviderDialects(providers))
 	}
 
 	if isEnabled(cfg.Checks, "duppatterns") {
@@ -28,11 +63,25 @@ func Checks(ctx context.Context, cfg *config.Config) ([]api.Checker, error) {
 	}
 
 	if isEnabled(cfg.Checks, "files") {
-		checks = append(checks, check.NewFileExist())
+		checks = append(checks, isolate(cfg, "files", check.NewFileExist()))
 	}
 
-	if isEnabled(cfg.Checks, "owners") {
-		ghClient, isApp, err := github.NewClient(ctx, cfg)
+	ownersCfg, err := provider.ForOwners(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "while enabling 'owners' checker")
+	}
+
+	// other providers are validated only with the provider-agnostic owners check
+	if isEnabled(cfg.Checks, "owners") && !provider.IsGitHub(ownersCfg) {
+		p, err := provider.New(ctx, ownersCfg, opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'owners' checker")
+		}
+		checks = append(checks, check.NewProviderOwner(cfg, p))
+	}
+
+	if isEnabled(cfg.Checks, "owners") && provider.IsGitHub(ownersCfg) {
+		ghClient, isApp, err := github.NewClient(ctx, cfg, opts...)
 		if err != nil {
 			return nil, errors.Wrap(err, "while creating GitHub client")
 		}
@@ -49,7 +98,7 @@ func Checks(ctx context.Context, cfg *config.Config) ([]api.Checker, error) {
 		checks = append(checks, owners)
 	}
 
-	expChecks, err := loadExperimentalChecks(cfg.ExperimentalChecks)
+	expChecks, err := loadExperimentalChecks(ctx, cfg, opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "while loading experimental checks")
 	}
@@ -57,44 +106,342 @@ func Checks(ctx context.Context, cfg *config.Config) ([]api.Checker, error) {
 	return append(checks, expChecks...), nil
 }
 
-func loadExperimentalChecks(experimentalChecks []string) ([]api.Checker, error) {
+func loadExperimentalChecks(ctx context.Context, cfg *config.Config, opts ...github.ClientOption) ([]api.Checker, error) {
 	var checks []api.Checker
 
-	if contains(experimentalChecks, "notowned") {
-		var cfg struct {
-			NotOwnedChecker check.NotOwnedFileConfig
-		}
-		if err := envconfig.Init(&cfg); err != nil {
-			return nil, errors.Wrapf(err, "while loading config for %s", "notowned")
+	experimentalChecks := cfg.ExperimentalChecks
+
+	virtualOwners, err := check.ParseVirtualOwners(cfg.VirtualOwners)
+	if err != nil {
+		return nil, err
+	}
+
+	if contains(experimentalChecks, "not-owned") {
+		notOwnedCfg, err := notOwnedConfig(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while loading config for %s", "not-owned")
 		}
 
-		checks = append(checks, check.NewNotOwnedFile(cfg.NotOwnedChecker))
+		checks = append(checks, isolate(cfg, "not-owned", check.NewNotOwnedFile(notOwnedCfg)))
 	}
 
 	if contains(experimentalChecks, "avoid-shadowing") {
 		checks = append(checks, check.NewAvoidShadowing())
 	}
 
+	if contains(experimentalChecks, "expiration") {
+		checks = append(checks, check.NewOwnershipExpiration(check.OwnershipExpirationConfig{
+			WarnBefore: cfg.ExpirationCheckerWarnBefore,
+		}))
+	}
+
+	if contains(experimentalChecks, "path-hazards") {
+		checks = append(checks, isolate(cfg, "path-hazards", check.NewPathHazards()))
+	}
+
+	if contains(experimentalChecks, "expensive-patterns") {
+		checks = append(checks, isolate(cfg, "expensive-patterns", check.NewExpensivePattern().WithLogger(redact.NewLogger())))
+	}
+
+	if contains(experimentalChecks, "stewardship") {
+		stewardship, err := check.NewStewardship(check.StewardshipConfig{
+			Team:          cfg.StewardshipCheckerTeam,
+			SizeThreshold: cfg.StewardshipCheckerSizeThreshold,
+			Extensions:    cfg.StewardshipCheckerExtensions,
+			VirtualOwners: virtualOwners,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'stewardship' checker")
+		}
+
+		checks = append(checks, isolate(cfg, "stewardship", stewardship))
+	}
+
+	if contains(experimentalChecks, "ownership-sla") {
+		ownershipSLA, err := check.NewOwnershipSLA(check.OwnershipSLAConfig{
+			GracePeriod: cfg.OwnershipSLACheckerGracePeriod,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'ownership-sla' checker")
+		}
+
+		checks = append(checks, isolate(cfg, "ownership-sla", ownershipSLA))
+	}
+
+	if contains(experimentalChecks, "broad-ownership") {
+		broadOwnership, err := check.NewBroadOwnership(check.BroadOwnershipConfig{
+			Threshold: cfg.BroadOwnershipCheckerThreshold,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'broad-ownership' checker")
+		}
+
+		checks = append(checks, isolate(cfg, "broad-ownership", broadOwnership))
+	}
+
+	if contains(experimentalChecks, "governance") {
+		governance, err := check.NewGovernance(check.GovernanceConfig{
+			Team:          cfg.GovernanceCheckerTeam,
+			Paths:         cfg.GovernanceCheckerPaths,
+			VirtualOwners: virtualOwners,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'governance' checker")
+		}
+
+		checks = append(checks, isolate(cfg, "governance", governance))
+	}
+
+	if contains(experimentalChecks, "module-boundaries") {
+		moduleBoundaries, err := check.NewModuleBoundaries(check.ModuleBoundariesConfig{
+			Ecosystems: cfg.ModuleBoundariesCheckerEcosystems,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'module-boundaries' checker")
+		}
+
+		checks = append(checks, isolate(cfg, "module-boundaries", moduleBoundaries))
+	}
+
+	if contains(experimentalChecks, "labeler") {
+		labelerSync, err := check.NewLabelerSync(check.LabelerSyncConfig{
+			File:        cfg.LabelerCheckerFile,
+			LabelPrefix: cfg.LabelerCheckerLabelPrefix,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'labeler' checker")
+		}
+
+		checks = append(checks, isolate(cfg, "labeler", labelerSync))
+	}
+
+	if contains(experimentalChecks, "owner-casing") {
+		checks = append(checks, check.NewOwnerCasing())
+	}
+
+	if contains(experimentalChecks, "encoding") {
+		checks = append(checks, check.NewFileEncoding())
+	}
+
+	if contains(experimentalChecks, "invisible-chars") {
+		checks = append(checks, check.NewInvisibleChars())
+	}
+
+	if contains(experimentalChecks, "unsafe-patterns") {
+		checks = append(checks, check.NewUnsafePattern())
+	}
+
+	if contains(experimentalChecks, "stale-teams") {
+		ghClient, _, err := github.NewClient(ctx, cfg, opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "while creating GitHub client")
+		}
+
+		checks = append(checks, check.NewStaleTeams(ghClient))
+	}
+
+	if contains(experimentalChecks, "identities") {
+		source, err := identity.NewSource(identity.Config{
+			Type:     identity.SourceType(cfg.IdentityCheckerSourceType),
+			Location: cfg.IdentityCheckerSource,
+			Token:    cfg.IdentityCheckerSourceToken,
+		})
+		if err != nil {
+			return nil, &api.ConfigError{Field: "IDENTITY_CHECKER_SOURCE", Err: errors.Wrap(err, "while enabling 'identities' checker")}
+		}
+
+		checks = append(checks, check.NewActiveIdentity(check.ActiveIdentityConfig{
+			IgnoredOwners: cfg.OwnerCheckerIgnoredOwners,
+		}, source))
+	}
+
+	if contains(experimentalChecks, "oncall") {
+		mapping, err := oncall.LoadMapping(cfg.OnCallCheckerMapping)
+		if err != nil {
+			return nil, &api.ConfigError{Field: "ONCALL_CHECKER_MAPPING", Err: errors.Wrap(err, "while enabling 'oncall' checker")}
+		}
+
+		checks = append(checks, check.NewOnCallMapping(mapping))
+	}
+
+	if contains(experimentalChecks, "jira") {
+		mapping, err := jira.LoadMapping(cfg.JiraCheckerMapping)
+		if err != nil {
+			return nil, &api.ConfigError{Field: "JIRA_CHECKER_MAPPING", Err: errors.Wrap(err, "while enabling 'jira' checker")}
+		}
+		client, err := jira.NewClient(cfg.JiraCheckerBaseURL, cfg.JiraCheckerUser, cfg.JiraCheckerToken)
+		if err != nil {
+			return nil, &api.ConfigError{Field: "JIRA_CHECKER_BASE_URL", Err: errors.Wrap(err, "while enabling 'jira' checker")}
+		}
+
+		checks = append(checks, check.NewJIRAComponents(mapping, client).WithVirtualOwners(virtualOwners))
+	}
+
+	if contains(experimentalChecks, "approvals") {
+		ghClient, _, err := github.NewClient(ctx, cfg, opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "while creating GitHub client")
+		}
+
+		approvals, err := check.NewProtectedApproval(check.ProtectedApprovalConfig{
+			Repository:        cfg.OwnerCheckerRepository,
+			BaseRef:           cfg.ApprovalCheckerBaseRef,
+			PullRequestNumber: cfg.ApprovalCheckerPullRequestNumber,
+			VirtualOwners:     virtualOwners,
+		}, ghClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'approvals' checker")
+		}
+
+		checks = append(checks, approvals)
+	}
+
+	if contains(experimentalChecks, "github-errors") {
+		ghClient, _, err := github.NewClient(ctx, cfg, opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "while creating GitHub client")
+		}
+
+		githubErrors, err := check.NewGitHubErrors(check.GitHubErrorsConfig{
+			Repository: cfg.OwnerCheckerRepository,
+			Ref:        cfg.GithubErrorsCheckerRef,
+		}, ghClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "while enabling 'github-errors' checker")
+		}
+
+		checks = append(checks, githubErrors)
+	}
+
 	return checks, nil
 }
 
-func isEnabled(checks []string, name string) bool {
-	// if a user does not specify concrete checks then all checks are enabled
-	if len(checks) == 0 {
-		return true
+// validateNoNetwork returns an error if a check that requires network access is enabled while the network
+// is disabled, including the owners check enabled by default, so no check is silently skipped.
+func validateNoNetwork(cfg *config.Config) error {
+	if !cfg.NoNetwork {
+		return nil
+	}
+
+	if len(cfg.Checks) == 0 {
+		return &api.ConfigError{
+			Field: "NO_NETWORK",
+			Err:   errors.New("the owners check is enabled by default and requires network access, which is disabled, select the checks with the CHECKS option, e.g. syntax,duppatterns,files"),
+		}
+	}
+
+	var networkChecks []string
+	if contains(cfg.Checks, "owners") {
+		networkChecks = append(networkChecks, "owners")
+	}
+	for _, name := range []string{"stale-teams", "approvals", "jira", "github-errors"} {
+		if contains(cfg.ExperimentalChecks, name) {
+			networkChecks = append(networkChecks, name)
+		}
+	}
+	if contains(cfg.ExperimentalChecks, "identities") && (identity.Config{Type: identity.SourceType(cfg.IdentityCheckerSourceType), Location: cfg.IdentityCheckerSource}).IsRemote() {
+		networkChecks = append(networkChecks, "identities")
+	}
+
+	if len(networkChecks) > 0 {
+		return &api.ConfigError{
+			Field: "NO_NETWORK",
+			Err:   errors.Errorf("the %s check(s) require network access, which is disabled", strings.Join(networkChecks, ", ")),
+		}
+	}
+	return nil
+}
+
+// validateNoGit returns an error if a check or option that requires a git repository is enabled
+// while the repository is validated as a plain directory.
+func validateNoGit(cfg *config.Config) error {
+	if !cfg.NoGit {
+		return nil
+	}
+
+	if cfg.DiffBaseRef != "" {
+		return &api.ConfigError{Field: "NO_GIT", Err: errors.New("the diff base reference requires a git repository")}
+	}
+
+	var gitChecks []string
+	for _, name := range []string{"stewardship", "approvals", "ownership-sla"} {
+		if contains(cfg.ExperimentalChecks, name) {
+			gitChecks = append(gitChecks, name)
+		}
+	}
+	if len(gitChecks) > 0 {
+		return &api.ConfigError{
+			Field: "NO_GIT",
+			Err:   errors.Errorf("the %s check(s) require a git repository", strings.Join(gitChecks, ", ")),
+		}
+	}
+	return nil
+}
+
+func validateIsolation(ctx context.Context, cfg *config.Config) error {
+	switch cfg.Isolation {
+	case "", isolation.None:
+		return nil
+	case isolation.Docker:
+		if err := dockerConfig(cfg).CheckAvailable(ctx); err != nil {
+			return &api.ConfigError{Field: "ISOLATION", Err: errors.Wrap(err, "while enabling docker isolation")}
+		}
+		return nil
+	default:
+		return &api.ConfigError{
+			Field: "ISOLATION",
+			Err:   errors.Errorf("unknown isolation %q, supported values: %s, %s", cfg.Isolation, isolation.None, isolation.Docker),
+		}
 	}
+}
 
-	if contains(checks, name) {
-		return true
+// isolate wraps the check that executes git commands, so it's executed in a container if the isolation is enabled.
+func isolate(cfg *config.Config, checkID string, c api.Checker) api.Checker {
+	if cfg.Isolation != isolation.Docker || !isolation.IsIsolated(checkID) {
+		return c
 	}
-	return false
+	return isolation.NewChecker(dockerConfig(cfg), checkID, c)
+}
+
+func dockerConfig(cfg *config.Config) isolation.DockerConfig {
+	return isolation.DockerConfig{Image: cfg.IsolationImage, Config: cfg}
 }
 
-func contains(checks []string, name string) bool {
-	for _, c := range checks {
-		if c == name {
-			return true
+// notOwnedConfig returns the not-owned check configuration. The NOT_OWNED_CHECKER_* environment variables
+// without the prefix take precedence over the configuration, as they were its only source before. Their values
+// are stored in the configuration, so they are passed to the isolated check as well.
+func notOwnedConfig(cfg *config.Config) (check.NotOwnedFileConfig, error) {
+	var env struct {
+		NotOwnedChecker check.NotOwnedFileConfig
+	}
+	if err := envconfig.Init(&env); err != nil {
+		return check.NotOwnedFileConfig{}, err
+	}
+
+	overrides := map[string]func(){
+		"NOT_OWNED_CHECKER_TRUST_WORKSPACE": func() { cfg.NotOwnedCheckerTrustWorkspace = env.NotOwnedChecker.TrustWorkspace },
+		"NOT_OWNED_CHECKER_SKIP_PATTERNS":   func() { cfg.NotOwnedCheckerSkipPatterns = env.NotOwnedChecker.SkipPatterns },
+		"NOT_OWNED_CHECKER_SUBDIRECTORIES":  func() { cfg.NotOwnedCheckerSubdirectories = env.NotOwnedChecker.Subdirectories },
+		"NOT_OWNED_CHECKER_SKIP_GENERATED":  func() { cfg.NotOwnedCheckerSkipGenerated = env.NotOwnedChecker.SkipGenerated },
+		"NOT_OWNED_CHECKER_SUGGEST_OWNERS":  func() { cfg.NotOwnedCheckerSuggestOwners = env.NotOwnedChecker.SuggestOwners },
+		"NOT_OWNED_CHECKER_IGNORE_SOURCES":  func() { cfg.NotOwnedCheckerIgnoreSources = env.NotOwnedChecker.IgnoreSources },
+	}
+	for name, override := range overrides {
+		if _, set := os.LookupEnv(name); set {
+			override()
 		}
 	}
-	return false
+	if len(cfg.NotOwnedCheckerIgnoreSources) == 0 {
+		// the configuration is not loaded with the command defaults, e.g. in tests
+		cfg.NotOwnedCheckerIgnoreSources = env.NotOwnedChecker.IgnoreSources
+	}
+
+	return check.NotOwnedFileConfig{
+		TrustWorkspace: cfg.NotOwnedCheckerTrustWorkspace,
+		SkipPatterns:   cfg.NotOwnedCheckerSkipPatterns,
+		Subdirectories: cfg.NotOwnedCheckerSubdirectories,
+		SkipGenerated:  cfg.NotOwnedCheckerSkipGenerated,
+		SuggestOwners:  cfg.NotOwnedCheckerSuggestOwners,
+		IgnoreSources:  cfg.NotOwnedCheckerIgnoreSources,
+	}, nil
 }
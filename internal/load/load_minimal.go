@@ -0,0 +1,22 @@
+//go:build minimal
+
+package load
+
+import (
+	"context"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// errNoChecks is returned in the minimal build, which doesn't embed the clients of checks which need network access.
+// The minimal binary selects its local checks itself, see the minimal package.
+var errNoChecks = errors.New("checks are not loaded in the minimal build, use the minimal package instead")
+
+// Checks returns checks enabled by a given configuration, which is not available in the minimal build.
+func Checks(context.Context, *config.Config, ...github.ClientOption) ([]api.Checker, error) {
+	return nil, errNoChecks
+}
@@ -0,0 +1,100 @@
+//go:build !minimal
+
+package load_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/load"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksWithoutNetwork(t *testing.T) {
+	// given
+	cfg := &config.Config{Checks: []string{"syntax", "duppatterns", "files"}, NoNetwork: true}
+
+	// when
+	checks, err := load.Checks(context.Background(), cfg)
+
+	// then
+	require.NoError(t, err)
+
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name())
+	}
+	assert.Equal(t, []string{"Valid Syntax Checker", "Duplicated Pattern Checker", "File Exist Checker"}, names)
+}
+
+func TestChecksWithoutNetworkErrors(t *testing.T) {
+	tests := map[string]struct {
+		cfg    config.Config
+		expErr string
+	}{
+		"Owners check enabled by default": {
+			cfg:    config.Config{},
+			expErr: "the owners check is enabled by default and requires network access, which is disabled, select the checks with the CHECKS option, e.g. syntax,duppatterns,files",
+		},
+		"Owners check": {
+			cfg:    config.Config{Checks: []string{"syntax", "owners"}},
+			expErr: "the owners check(s) require network access, which is disabled",
+		},
+		"Experimental GitHub checks": {
+			cfg:    config.Config{Checks: []string{"syntax"}, ExperimentalChecks: []string{"stale-teams", "approvals"}},
+			expErr: "the stale-teams, approvals check(s) require network access, which is disabled",
+		},
+		"Remote identity source": {
+			cfg:    config.Config{Checks: []string{"syntax"}, ExperimentalChecks: []string{"identities"}, IdentityCheckerSourceType: "json", IdentityCheckerSource: "https://hr.example.com/employees.json"},
+			expErr: "the identities check(s) require network access, which is disabled",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			cfg := tc.cfg
+			cfg.NoNetwork = true
+
+			// when
+			_, err := load.Checks(context.Background(), &cfg)
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+			assert.Equal(t, "Check the NO_NETWORK configuration.", api.Hint(err))
+		})
+	}
+}
+
+func TestChecksWithoutGitErrors(t *testing.T) {
+	tests := map[string]struct {
+		cfg    config.Config
+		expErr string
+	}{
+		"Experimental git checks": {
+			cfg:    config.Config{ExperimentalChecks: []string{"not-owned", "stewardship", "approvals"}},
+			expErr: "the stewardship, approvals check(s) require a git repository",
+		},
+		"Diff base reference": {
+			cfg:    config.Config{DiffBaseRef: "origin/main"},
+			expErr: "the diff base reference requires a git repository",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			cfg := tc.cfg
+			cfg.NoGit = true
+
+			// when
+			_, err := load.Checks(context.Background(), &cfg)
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+			assert.Equal(t, "Check the NO_GIT configuration.", api.Hint(err))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package load
+
+import (
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/provider"
+)
+
+// GitHubChecks returns names of enabled checks which call the GitHub API, e.g. to estimate the API usage
+// of the validation before it is executed.
+func GitHubChecks(cfg *config.Config) []string {
+	if cfg.NoNetwork {
+		return nil
+	}
+
+	var out []string
+	// an unknown owners platform fails the validation, so it doesn't matter here
+	if ownersCfg, err := provider.ForOwners(cfg); err == nil && isEnabled(cfg.Checks, "owners") && provider.IsGitHub(ownersCfg) {
+		out = append(out, "owners")
+	}
+	for _, name := range []string{"stale-teams", "approvals", "github-errors"} {
+		if contains(cfg.ExperimentalChecks, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func isEnabled(checks []string, name string) bool {
+	// if a user does not specify concrete checks then all checks are enabled
+	if len(checks) == 0 {
+		return true
+	}
+
+	if contains(checks, name) {
+		return true
+	}
+	return false
+}
+
+func contains(checks []string, name string) bool {
+	for _, c := range checks {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
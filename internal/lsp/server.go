@@ -0,0 +1,335 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	diagnosticSource = "codeowners"
+	// maxContentLength limits the message size, so a malformed header cannot exhaust the memory.
+	maxContentLength = 64 << 20
+)
+
+// Server is a minimal language server that publishes issues reported by the given checks
+// as diagnostics and exposes their suggested fixes as quick fix code actions.
+//
+// Only checks that do not require access to the repository or external APIs should be used,
+// as they are executed on each change of the document.
+type Server struct {
+	in     *bufio.Reader
+	out    io.Writer
+	outMux sync.Mutex
+
+	checks []api.Checker
+	docs   map[string]string
+}
+
+// NewServer returns new instance of the Server which communicates over the given streams.
+func NewServer(in io.Reader, out io.Writer, checks ...api.Checker) *Server {
+	return &Server{
+		in:     bufio.NewReader(in),
+		out:    out,
+		checks: checks,
+		docs:   map[string]string{},
+	}
+}
+
+// Run serves requests until the exit notification is received or the input is closed.
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		req, err := s.readRequest()
+		var respErr *responseError
+		switch {
+		case err == io.EOF:
+			return nil
+		case errors.As(err, &respErr):
+			// the message body cannot be skipped without a valid length, so the server stops after reporting the error
+			if err := s.write(response{JSONRPC: "2.0", Error: respErr}); err != nil {
+				return err
+			}
+			return err
+		case err != nil:
+			return err
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		if err := s.handle(ctx, req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req request) error {
+	switch req.Method {
+	case "initialize":
+		return s.reply(req, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   textDocumentSyncFull,
+				CodeActionProvider: true,
+			},
+			ServerInfo: serverInfo{Name: "codeowners"},
+		}, nil)
+	case "shutdown":
+		return s.reply(req, nil, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		return s.update(ctx, params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// full synchronization, the last change holds the whole document
+		return s.update(ctx, params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didSave":
+		var params didSaveParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Text == nil {
+			return nil
+		}
+		return s.update(ctx, params.TextDocument.URI, *params.Text)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []diagnostic{},
+		})
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.reply(req, nil, &responseError{Code: errCodeInvalidParams, Message: err.Error()})
+		}
+		actions, err := s.codeActions(ctx, params)
+		if err != nil {
+			return err
+		}
+		return s.reply(req, actions, nil)
+	}
+
+	// notifications without handler, such as "initialized", are ignored
+	if req.ID == nil {
+		return nil
+	}
+	return s.reply(req, nil, &responseError{
+		Code:    errCodeMethodNotFound,
+		Message: fmt.Sprintf("method %q is not supported", req.Method),
+	})
+}
+
+func (s *Server) update(ctx context.Context, uri, text string) error {
+	s.docs[uri] = text
+
+	issues, err := s.issues(ctx, text)
+	if err != nil {
+		return err
+	}
+
+	diags := []diagnostic{}
+	for _, i := range issues {
+		diags = append(diags, toDiagnostic(i))
+	}
+
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diags,
+	})
+}
+
+func (s *Server) codeActions(ctx context.Context, params codeActionParams) ([]codeAction, error) {
+	actions := []codeAction{}
+
+	text, found := s.docs[params.TextDocument.URI]
+	if !found {
+		return actions, nil
+	}
+
+	issues, err := s.issues(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range issues {
+		line := issueLine(i)
+		if line < params.Range.Start.Line || line > params.Range.End.Line {
+			continue
+		}
+
+		for _, fix := range i.Fixes {
+			var edits []textEdit
+			for _, e := range fix.Edits {
+				edits = append(edits, toTextEdit(e))
+			}
+
+			actions = append(actions, codeAction{
+				Title:       fix.Description,
+				Kind:        codeActionKindQuickFix,
+				Diagnostics: []diagnostic{toDiagnostic(i)},
+				Edit: workspaceEdit{
+					Changes: map[string][]textEdit{params.TextDocument.URI: edits},
+				},
+			})
+		}
+	}
+
+	return actions, nil
+}
+
+func (s *Server) issues(ctx context.Context, text string) ([]api.Issue, error) {
+	in := api.Input{
+		CodeownersEntries: codeowners.ParseCodeowners(strings.NewReader(text)),
+	}
+
+	var out []api.Issue
+	for _, c := range s.checks {
+		res, err := c.Check(ctx, in)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while executing %s", c.Name())
+		}
+		out = append(out, res.Issues...)
+	}
+
+	return out, nil
+}
+
+// issueLine returns the zero-based line of the issue. Issues reported for the whole file
+// are placed on the first line edited by their fix, or on the first line of the file.
+func issueLine(i api.Issue) uint64 {
+	switch {
+	case i.LineNo != nil:
+		return *i.LineNo - 1
+	case len(i.Fixes) > 0 && len(i.Fixes[0].Edits) > 0 && i.Fixes[0].Edits[0].LineNo > 0:
+		return i.Fixes[0].Edits[0].LineNo - 1
+	default:
+		return 0
+	}
+}
+
+func toDiagnostic(i api.Issue) diagnostic {
+	line := issueLine(i)
+
+	severity := diagnosticSeverityError
+	if i.Severity == api.Warning {
+		severity = diagnosticSeverityWarning
+	}
+
+	rng := lspRange{
+		Start: position{Line: line},
+		End:   position{Line: line + 1},
+	}
+	if i.LineNo != nil && i.Column != nil {
+		rng = lspRange{
+			Start: position{Line: line, Character: *i.Column - 1},
+			End:   position{Line: line, Character: *i.Column},
+		}
+	}
+
+	return diagnostic{
+		Range:    rng,
+		Severity: severity,
+		Source:   diagnosticSource,
+		Message:  i.Message,
+	}
+}
+
+// toTextEdit replaces the whole line, including its line break, or inserts a new line.
+func toTextEdit(e api.LineEdit) textEdit {
+	if e.Insert {
+		return textEdit{
+			Range: lspRange{
+				Start: position{Line: e.LineNo},
+				End:   position{Line: e.LineNo},
+			},
+			NewText: e.NewText + "\n",
+		}
+	}
+
+	line := e.LineNo - 1
+	edit := textEdit{
+		Range: lspRange{
+			Start: position{Line: line},
+			End:   position{Line: line + 1},
+		},
+	}
+	if !e.Delete {
+		edit.NewText = e.NewText + "\n"
+	}
+	return edit
+}
+
+func (s *Server) readRequest() (request, error) {
+	headers, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return request{}, err
+	}
+
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil {
+		return request{}, errors.Wrap(err, "while parsing Content-Length header")
+	}
+	if length <= 0 || length > maxContentLength {
+		return request{}, &responseError{
+			Code:    errCodeInvalidRequest,
+			Message: fmt.Sprintf("Content-Length must be between 1 and %d bytes, got %d", maxContentLength, length),
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return request{}, errors.Wrap(err, "while reading message body")
+	}
+
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return request{}, errors.Wrap(err, "while decoding message")
+	}
+	return req, nil
+}
+
+func (s *Server) reply(req request, result interface{}, respErr *responseError) error {
+	if req.ID == nil {
+		return nil
+	}
+	return s.write(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: respErr})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.outMux.Lock()
+	defer s.outMux.Unlock()
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
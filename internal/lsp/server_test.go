@@ -0,0 +1,160 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/lsp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const docURI = "file:///repo/.github/CODEOWNERS"
+
+func TestServerPublishesDiagnosticsAndQuickFixes(t *testing.T) {
+	// given
+	in := &bytes.Buffer{}
+	writeMessage(t, in, 1, "initialize", map[string]interface{}{})
+	writeMessage(t, in, nil, "initialized", map[string]interface{}{})
+	writeMessage(t, in, nil, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{
+			"uri":  docURI,
+			"text": "*.md @Alice\n*.md @alice\n",
+		},
+	})
+	writeMessage(t, in, 2, "textDocument/codeAction", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": docURI},
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": 0, "character": 0},
+			"end":   map[string]interface{}{"line": 1, "character": 0},
+		},
+	})
+	writeMessage(t, in, 3, "shutdown", nil)
+	writeMessage(t, in, nil, "exit", nil)
+
+	out := &bytes.Buffer{}
+	sut := lsp.NewServer(in, out, check.NewDuplicatedPattern(), check.NewOwnerCasing())
+
+	// when
+	err := sut.Run(context.Background())
+
+	// then
+	require.NoError(t, err)
+
+	msgs := readMessages(t, out)
+	require.Len(t, msgs, 4)
+
+	assert.Equal(t, map[string]interface{}{
+		"textDocumentSync":   float64(1),
+		"codeActionProvider": true,
+	}, msgs[0]["result"].(map[string]interface{})["capabilities"])
+
+	assert.Equal(t, "textDocument/publishDiagnostics", msgs[1]["method"])
+	diags := msgs[1]["params"].(map[string]interface{})["diagnostics"].([]interface{})
+	assert.Len(t, diags, 2)
+
+	var titles []string
+	for _, a := range msgs[2]["result"].([]interface{}) {
+		action := a.(map[string]interface{})
+		assert.Equal(t, "quickfix", action["kind"])
+		titles = append(titles, action["title"].(string))
+	}
+	assert.ElementsMatch(t, []string{
+		"Remove duplicated lines, keep line 2 which takes precedence",
+		"Correct owner casing",
+	}, titles)
+
+	assert.Nil(t, msgs[3]["result"])
+}
+
+func TestServerRejectsInvalidContentLength(t *testing.T) {
+	tests := map[string]struct {
+		length int
+	}{
+		"Empty message": {
+			length: 0,
+		},
+		"Negative length": {
+			length: -1,
+		},
+		"Message above 64 MiB": {
+			length: 64<<20 + 1,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			in := bytes.NewBufferString(fmt.Sprintf("Content-Length: %d\r\n\r\n{}", tc.length))
+			out := &bytes.Buffer{}
+			sut := lsp.NewServer(in, out)
+
+			// when
+			err := sut.Run(context.Background())
+
+			// then
+			expMsg := fmt.Sprintf("Content-Length must be between 1 and 67108864 bytes, got %d", tc.length)
+			assert.EqualError(t, err, expMsg)
+
+			msgs := readMessages(t, out)
+			require.Len(t, msgs, 1)
+			assert.Nil(t, msgs[0]["id"])
+			assert.Equal(t, map[string]interface{}{
+				"code":    float64(-32600),
+				"message": expMsg,
+			}, msgs[0]["error"])
+		})
+	}
+}
+
+func writeMessage(t *testing.T, w io.Writer, id interface{}, method string, params interface{}) {
+	t.Helper()
+
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method}
+	if id != nil {
+		msg["id"] = id
+	}
+	if params != nil {
+		msg["params"] = params
+	}
+
+	body, err := json.Marshal(msg)
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	require.NoError(t, err)
+}
+
+func readMessages(t *testing.T, r io.Reader) []map[string]interface{} {
+	t.Helper()
+
+	var (
+		out []map[string]interface{}
+		br  = bufio.NewReader(r)
+	)
+	for {
+		headers, err := textproto.NewReader(br).ReadMIMEHeader()
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(t, err)
+
+		length, err := strconv.Atoi(headers.Get("Content-Length"))
+		require.NoError(t, err)
+
+		body := make([]byte, length)
+		_, err = io.ReadFull(br, body)
+		require.NoError(t, err)
+
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &msg))
+		out = append(out, msg)
+	}
+}
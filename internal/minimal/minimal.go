@@ -0,0 +1,187 @@
+// Package minimal is the command line interface of the minimal build, produced with the `minimal` build tag.
+// It parses the CODEOWNERS file and executes only checks which don't need network access, so the binary
+// doesn't embed the GitHub client, the cobra extensions, or go-git, e.g. for pre-commit hooks on slow networks.
+package minimal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"go.szostok.io/version"
+)
+
+// Exit codes in addition to the ones of the runner.
+const (
+	// ExitCodeError is returned if the validation could not be executed, e.g. because of a wrong flag.
+	ExitCodeError = 1
+	// ExitCodeInterrupted is returned if the validation was interrupted by the operating system.
+	ExitCodeInterrupted = 2
+)
+
+// Checks are names of checks available in the minimal build.
+var Checks = []string{"syntax", "duppatterns", "files"}
+
+// ExperimentalChecks are names of experimental checks available in the minimal build.
+var ExperimentalChecks = []string{"not-owned", "avoid-shadowing", "path-hazards", "expensive-patterns", "encoding", "invisible-chars", "unsafe-patterns"}
+
+// Run validates the repository with given command line arguments and returns the exit code.
+// Flags have the same names as the ones of the `codeowners validate` command.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("codeowners", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		repoPath      = flags.String("repository-path", ".", "Path to your repository on your local machine")
+		checks        = flags.String("checks", strings.Join(Checks, ","), "The comma-separated list of checks to be executed. Possible values: "+strings.Join(Checks, ", "))
+		experimental  = flags.String("experimental-checks", "", "The comma-separated list of experimental checks that should be executed. Possible values: "+strings.Join(ExperimentalChecks, ", "))
+		flavor        = flags.String("codeowners-flavor", "auto", "Dialect in which the CODEOWNERS file is parsed. Possible values: auto, github, gitlab")
+		printVersion  = flags.Bool("version", false, "Print the version of the binary")
+		failureLevel  = api.Warning
+		skipPatterns  = flags.String("not-owned-checker-skip-patterns", "", "The comma-separated list of patterns that should be ignored by not-owned-checker")
+		subdirs       = flags.String("not-owned-checker-subdirectories", "", "The comma-separated list of subdirectories to check in not-owned-checker")
+		ignoreSources = flags.String("not-owned-checker-ignore-sources", strings.Join([]string{check.IgnoreSourceGitignore, check.IgnoreSourceInfoExclude, check.IgnoreSourceGlobal}, ","), "The comma-separated list of ignore rule sources which tracked files are treated as owned by the not-owned-checker")
+	)
+	flags.Var(&failureLevel, "check-failure-level", "Defines the level on which the application should treat check issues as failures. Possible values: error, warning")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage of the minimal build of codeowners:\n\n  codeowners [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return runner.ExitCodeOK
+		}
+		return ExitCodeError
+	}
+	if *printVersion {
+		fmt.Fprintf(stdout, "codeowners %s (minimal)\n", version.Get().Version)
+		return runner.ExitCodeOK
+	}
+
+	log := redact.NewLogger()
+	log.SetOutput(stderr)
+
+	enabled, err := newChecks(splitList(*checks), splitList(*experimental), check.NotOwnedFileConfig{
+		SkipPatterns:   splitList(*skipPatterns),
+		Subdirectories: splitList(*subdirs),
+		IgnoreSources:  splitList(*ignoreSources),
+	})
+	if err != nil {
+		log.Error(err)
+		return ExitCodeError
+	}
+
+	entries, err := loadCodeowners(*repoPath, *flavor)
+	if err != nil {
+		log.Error(err)
+		return ExitCodeError
+	}
+
+	absRepoPath, err := filepath.Abs(*repoPath)
+	if err != nil {
+		log.Error(err)
+		return ExitCodeError
+	}
+
+	checkRunner := runner.NewCheckRunner(log, entries, absRepoPath, failureLevel, enabled...)
+	checkRunner.Run(ctx)
+
+	if ctx.Err() != nil {
+		log.Error("Application was interrupted by operating system")
+		return ExitCodeInterrupted
+	}
+	return checkRunner.ExitCode()
+}
+
+// newChecks returns checks with given names. Unknown names, including checks which are not available
+// in the minimal build, are reported as an error, so they are never silently skipped.
+func newChecks(names, experimental []string, notOwned check.NotOwnedFileConfig) ([]api.Checker, error) {
+	var out []api.Checker
+	for _, name := range names {
+		switch name {
+		case "syntax":
+			out = append(out, check.NewValidSyntax())
+		case "duppatterns":
+			out = append(out, check.NewDuplicatedPattern())
+		case "files":
+			out = append(out, check.NewFileExist())
+		default:
+			return nil, &api.ConfigError{
+				Field: "CHECKS",
+				Err:   errors.Errorf("check %q is not available in the minimal build, possible values: %s", name, strings.Join(Checks, ", ")),
+			}
+		}
+	}
+
+	for _, name := range experimental {
+		switch name {
+		case "not-owned":
+			out = append(out, check.NewNotOwnedFile(notOwned))
+		case "avoid-shadowing":
+			out = append(out, check.NewAvoidShadowing())
+		case "path-hazards":
+			out = append(out, check.NewPathHazards())
+		case "expensive-patterns":
+			out = append(out, check.NewExpensivePattern())
+		case "encoding":
+			out = append(out, check.NewFileEncoding())
+		case "invisible-chars":
+			out = append(out, check.NewInvisibleChars())
+		case "unsafe-patterns":
+			out = append(out, check.NewUnsafePattern())
+		default:
+			return nil, &api.ConfigError{
+				Field: "EXPERIMENTAL_CHECKS",
+				Err:   errors.Errorf("experimental check %q is not available in the minimal build, possible values: %s", name, strings.Join(ExperimentalChecks, ", ")),
+			}
+		}
+	}
+	return out, nil
+}
+
+// loadCodeowners parses the CODEOWNERS file of the repository in a given dialect. The auto flavor parses
+// GitLab sections if the file has section headers.
+func loadCodeowners(repoPath, flavor string) ([]codeowners.Entry, error) {
+	path, err := codeowners.FindCodeownersFile(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider codeowners.Provider
+	switch flavor {
+	case "", "auto":
+		provider = codeowners.DetectProvider(raw)
+	default:
+		provider, err = codeowners.ParseProvider(flavor)
+		if err != nil {
+			return nil, &api.ConfigError{Field: "CODEOWNERS_FLAVOR", Err: err}
+		}
+	}
+	return codeowners.ParseCodeownersAs(strings.NewReader(string(raw)), provider)
+}
+
+func splitList(in string) []string {
+	var out []string
+	for _, item := range strings.Split(in, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
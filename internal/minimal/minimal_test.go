@@ -0,0 +1,75 @@
+package minimal_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/minimal"
+	"go.szostok.io/codeowners/internal/runner"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		givenCodeowners string
+		givenArgs       []string
+		expCode         int
+		expStderr       string
+	}{
+		"Should pass valid CODEOWNERS": {
+			givenCodeowners: "*.go @org/core\n",
+			expCode:         runner.ExitCodeOK,
+		},
+		"Should fail on duplicated patterns": {
+			givenCodeowners: "*.go @org/core\n*.go @org/api\n",
+			expCode:         runner.ExitCodeCheckFailure,
+		},
+		"Should fail on patterns without files": {
+			givenCodeowners: "*.go @org/core\n/docs/ @org/writers\n",
+			expCode:         runner.ExitCodeCheckFailure,
+		},
+		"Should execute only given checks": {
+			givenCodeowners: "*.go @org/core\n/docs/ @org/writers\n",
+			givenArgs:       []string{"-checks", "syntax,duppatterns"},
+			expCode:         runner.ExitCodeOK,
+		},
+		"Should reject checks which need network access": {
+			givenCodeowners: "*.go @org/core\n",
+			givenArgs:       []string{"-checks", "syntax,owners"},
+			expCode:         minimal.ExitCodeError,
+			expStderr:       `check \"owners\" is not available in the minimal build`,
+		},
+		"Should reject unknown flags": {
+			givenCodeowners: "*.go @org/core\n",
+			givenArgs:       []string{"-github-access-token", "secret"},
+			expCode:         minimal.ExitCodeError,
+			expStderr:       "flag provided but not defined: -github-access-token",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte(tc.givenCodeowners), 0o600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600))
+			for _, args := range [][]string{{"init", "--quiet"}, {"add", "."}} {
+				out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+				require.NoError(t, err, string(out))
+			}
+			var stdout, stderr bytes.Buffer
+
+			// when
+			code := minimal.Run(context.Background(), append([]string{"-repository-path", dir}, tc.givenArgs...), &stdout, &stderr)
+
+			// then
+			assert.Equal(t, tc.expCode, code, stderr.String())
+			assert.Contains(t, stderr.String(), tc.expStderr)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package netguard_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/identity"
+	"go.szostok.io/codeowners/internal/jira"
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnforceClients builds clients of all checks and commands before the network is disabled, as they
+// are built in the application, and verifies that none of them can reach the network afterwards.
+func TestEnforceClients(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("request should not be sent")
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	ghClient, _, err := github.NewClient(ctx, &config.Config{GithubAccessToken: "token", GithubBaseURL: srv.URL})
+	require.NoError(t, err)
+	ghAppClient, _, err := github.NewClient(ctx, &config.Config{GithubAppID: 1, GithubAppInstallationID: 1, GithubAppPrivateKey: privateKey(t), GithubBaseURL: srv.URL})
+	require.NoError(t, err)
+	jiraClient, err := jira.NewClient(srv.URL, "user", "token")
+	require.NoError(t, err)
+	gitlab := provider.NewGitLab(srv.URL, "token")
+	gitea := provider.NewGitea(srv.URL, "token")
+	bitbucket := provider.NewBitbucket(srv.URL, "token")
+	jsonSource := identity.NewJSONSource(srv.URL, "token")
+	scimSource := identity.NewSCIMSource(srv.URL, "token")
+	ldapSource, err := identity.NewLDAPSource("ldap://"+srv.Listener.Addr().String()+"/dc=example,dc=com", "")
+	require.NoError(t, err)
+	adminClient := server.NewAdminClient(srv.URL, "token")
+	notifier := server.NewWebhookNotifier(srv.URL)
+
+	// when
+	netguard.Enforce()
+
+	// then
+	tests := map[string]func() error{
+		"GitHub client": func() error {
+			_, _, err := ghClient.Users.Get(ctx, "octocat")
+			return err
+		},
+		"GitHub App client": func() error {
+			_, _, err := ghAppClient.Users.Get(ctx, "octocat")
+			return err
+		},
+		"Jira client": func() error {
+			_, err := jiraClient.Components(ctx, "PAY")
+			return err
+		},
+		"GitLab client": func() error {
+			_, err := gitlab.ResolveOwner(ctx, "@group")
+			return err
+		},
+		"Gitea client": func() error {
+			_, err := gitea.ResolveOwner(ctx, "@user")
+			return err
+		},
+		"Bitbucket client": func() error {
+			_, err := bitbucket.ResolveOwner(ctx, "@user")
+			return err
+		},
+		"JSON identity source": func() error {
+			_, err := jsonSource.Identities(ctx)
+			return err
+		},
+		"SCIM identity source": func() error {
+			_, err := scimSource.Identities(ctx)
+			return err
+		},
+		"LDAP identity source": func() error {
+			_, err := ldapSource.Identities(ctx)
+			return err
+		},
+		"Server admin client": func() error {
+			_, err := adminClient.ListRepositories(ctx)
+			return err
+		},
+		"Webhook notifier": func() error {
+			return notifier.Notify(ctx, server.SnapshotDiff{Repository: "org/repo"})
+		},
+	}
+	for tn, call := range tests {
+		t.Run(tn, func(t *testing.T) {
+			err := call()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), netguard.ErrNetworkDisabled.Error())
+		})
+	}
+
+	t.Run("Git subprocess", func(t *testing.T) {
+		out, err := exec.Command("git", "ls-remote", srv.URL).CombinedOutput()
+		require.Error(t, err)
+		assert.Contains(t, string(out), "transport 'http' not allowed")
+	})
+}
+
+func privateKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
@@ -0,0 +1,82 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNetworkDisabled is returned for each outbound connection attempted when the network is disabled.
+var ErrNetworkDisabled = errors.New("network access is disabled")
+
+// Transport is an http.RoundTripper which rejects all requests.
+type Transport struct{}
+
+// RoundTrip always returns ErrNetworkDisabled.
+func (Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.Wrapf(ErrNetworkDisabled, "while sending %s request to %s", req.Method, req.URL.Host)
+}
+
+// dialer has the same settings as the dialer of the http.DefaultTransport.
+var dialer = &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+// disabled is set to 1 by Enforce.
+var disabled int32
+
+// init makes the http.DefaultTransport connect through the DialContext, so transports which captured it
+// before the network was disabled, e.g. wrapped by GitHub App clients or recordings, are guarded too.
+func init() {
+	if tr, ok := http.DefaultTransport.(*http.Transport); ok {
+		tr.DialContext = DialContext
+	}
+}
+
+// DialContext connects to a given address, unless the network is disabled. It's the single dialer of all
+// HTTP clients created by the application.
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if Disabled() {
+		return nil, errors.Wrapf(ErrNetworkDisabled, "while dialing %s %s", network, address)
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// BaseTransport returns the transport which all HTTP clients created by the application are built on.
+// It's the current http.DefaultTransport, so clients honor recordings, which replace it, and connect
+// through the DialContext.
+func BaseTransport() http.RoundTripper {
+	return http.DefaultTransport
+}
+
+var enforceOnce sync.Once
+
+// Enforce disables outbound connections for the whole process. It rejects connections of the DialContext,
+// replaces the default HTTP transport and the default DNS resolver, and allows git subprocesses to use
+// only the local file protocol, so that any code path that tries to reach the network fails instead
+// of silently leaking data.
+// It cannot be reverted.
+func Enforce() {
+	enforceOnce.Do(func() {
+		atomic.StoreInt32(&disabled, 1)
+		http.DefaultTransport = Transport{}
+		http.DefaultClient.Transport = Transport{}
+		net.DefaultResolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(_ context.Context, network, address string) (net.Conn, error) {
+				return nil, errors.Wrapf(ErrNetworkDisabled, "while resolving via %s %s", network, address)
+			},
+		}
+		// see: https://git-scm.com/docs/git#Documentation/git.txt-codeGITALLOWPROTOCOLcode
+		_ = os.Setenv("GIT_ALLOW_PROTOCOL", "file")
+	})
+}
+
+// Disabled returns true if the network is disabled with Enforce.
+func Disabled() bool {
+	return atomic.LoadInt32(&disabled) == 1
+}
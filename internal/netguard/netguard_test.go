@@ -0,0 +1,54 @@
+package netguard_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/netguard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestEnforce(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("request should not be sent")
+	}))
+	defer srv.Close()
+
+	// when
+	netguard.Enforce()
+
+	// then
+	tests := map[string]func() error{
+		"Default client": func() error {
+			_, err := http.Get(srv.URL)
+			return err
+		},
+		"Client with the default transport": func() error {
+			_, err := (&http.Client{}).Get(srv.URL)
+			return err
+		},
+		"OAuth2 client": func() error {
+			client := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}))
+			_, err := client.Get(srv.URL)
+			return err
+		},
+		"DNS resolution": func() error {
+			_, err := net.DefaultResolver.LookupHost(context.Background(), "github.com")
+			return err
+		},
+	}
+	for tn, call := range tests {
+		t.Run(tn, func(t *testing.T) {
+			err := call()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), netguard.ErrNetworkDisabled.Error())
+		})
+	}
+}
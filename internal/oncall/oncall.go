@@ -0,0 +1,142 @@
+package oncall
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping maps CODEOWNERS owners to the escalation policies of the incident tooling, e.g.:
+//
+//	teams:
+//	  "@org/platform":
+//	    pagerduty: PABC123
+//	    opsgenie: platform-escalation
+//	  "@org/docs":
+//	    opsgenie: docs-escalation
+type Mapping struct {
+	Teams map[string]Policy `yaml:"teams"`
+}
+
+// Policy holds the escalation policies of a single owner. At least one of them is set.
+type Policy struct {
+	// PagerDuty is the PagerDuty escalation policy ID.
+	PagerDuty string `yaml:"pagerduty" json:"pagerduty,omitempty"`
+	// Opsgenie is the Opsgenie escalation name.
+	Opsgenie string `yaml:"opsgenie" json:"opsgenie,omitempty"`
+}
+
+// LoadMapping reads the mapping file in YAML format.
+func LoadMapping(path string) (*Mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening on-call mapping file")
+	}
+	defer f.Close()
+
+	return ParseMapping(f)
+}
+
+// ParseMapping decodes the mapping in YAML format. Owners are matched case-insensitively.
+func ParseMapping(r io.Reader) (*Mapping, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var raw Mapping
+	if err := dec.Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "while decoding on-call mapping")
+	}
+
+	out := &Mapping{Teams: map[string]Policy{}}
+	for owner, policy := range raw.Teams {
+		if policy.PagerDuty == "" && policy.Opsgenie == "" {
+			return nil, errors.Errorf("owner %s has no escalation policy, set pagerduty or opsgenie", owner)
+		}
+		out.Teams[strings.ToLower(owner)] = policy
+	}
+	return out, nil
+}
+
+// Lookup returns the escalation policy of a given owner.
+func (m *Mapping) Lookup(owner string) (Policy, bool) {
+	p, found := m.Teams[strings.ToLower(owner)]
+	return p, found
+}
+
+// Route is a single row of the routing table. It describes who is paged for incidents in files matched by the pattern.
+type Route struct {
+	Pattern string `json:"pattern"`
+	// Targets lists escalation policies of the mapped owners, in the order of owners.
+	Targets []Target `json:"targets"`
+	// Unmapped lists owners without an escalation policy.
+	Unmapped []string `json:"unmapped,omitempty"`
+}
+
+// Target is the escalation policy of a single owner.
+type Target struct {
+	Owner string `json:"owner"`
+	Policy
+}
+
+// RoutingTable joins CODEOWNERS entries with the mapping. Entries without owners are skipped.
+// As in CODEOWNERS, the last matching pattern takes the most precedence, so the order of entries is preserved.
+func RoutingTable(entries []codeowners.Entry, mapping *Mapping) []Route {
+	var out []Route
+	for _, entry := range entries {
+		if len(entry.Owners) == 0 {
+			continue
+		}
+
+		route := Route{Pattern: entry.Pattern, Targets: []Target{}}
+		for _, owner := range entry.Owners {
+			policy, found := mapping.Lookup(owner)
+			if !found {
+				route.Unmapped = append(route.Unmapped, owner)
+				continue
+			}
+			route.Targets = append(route.Targets, Target{Owner: owner, Policy: policy})
+		}
+		out = append(out, route)
+	}
+	return out
+}
+
+// WriteJSON writes the routing table as a JSON array.
+func WriteJSON(w io.Writer, routes []Route) error {
+	if routes == nil {
+		routes = []Route{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(routes)
+}
+
+// WriteCSV writes the routing table in CSV format, one row per pattern and mapped owner.
+// Unmapped owners are written with empty escalation policies.
+func WriteCSV(w io.Writer, routes []Route) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"pattern", "owner", "pagerduty", "opsgenie"}); err != nil {
+		return err
+	}
+	for _, r := range routes {
+		for _, t := range r.Targets {
+			if err := out.Write([]string{r.Pattern, t.Owner, t.PagerDuty, t.Opsgenie}); err != nil {
+				return err
+			}
+		}
+		for _, owner := range r.Unmapped {
+			if err := out.Write([]string{r.Pattern, owner, "", ""}); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,101 @@
+package oncall_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/oncall"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMapping = `
+teams:
+  "@org/platform":
+    pagerduty: PABC123
+    opsgenie: platform-escalation
+  "@org/docs":
+    opsgenie: docs-escalation
+`
+
+const testCODEOWNERS = `
+*          @org/Platform
+/docs/     @org/docs @alice
+/vendor/
+`
+
+func TestRoutingTableJSON(t *testing.T) {
+	// given
+	mapping, err := oncall.ParseMapping(strings.NewReader(testMapping))
+	require.NoError(t, err)
+
+	entries := codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS))
+
+	var buff bytes.Buffer
+
+	// when
+	err = oncall.WriteJSON(&buff, oncall.RoutingTable(entries, mapping))
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"pattern": "*", "targets": [{"owner": "@org/Platform", "pagerduty": "PABC123", "opsgenie": "platform-escalation"}]},
+		{"pattern": "/docs/", "targets": [{"owner": "@org/docs", "opsgenie": "docs-escalation"}], "unmapped": ["@alice"]}
+	]`, buff.String())
+}
+
+func TestRoutingTableCSV(t *testing.T) {
+	// given
+	mapping, err := oncall.ParseMapping(strings.NewReader(testMapping))
+	require.NoError(t, err)
+
+	entries := codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS))
+
+	var buff bytes.Buffer
+
+	// when
+	err = oncall.WriteCSV(&buff, oncall.RoutingTable(entries, mapping))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `pattern,owner,pagerduty,opsgenie
+*,@org/Platform,PABC123,platform-escalation
+/docs/,@org/docs,,docs-escalation
+/docs/,@alice,,
+`, buff.String())
+}
+
+func TestParseMappingErrors(t *testing.T) {
+	tests := map[string]struct {
+		mapping  string
+		expError string
+	}{
+		"Owner without policy": {
+			mapping: `
+teams:
+  "@org/platform": {}
+`,
+			expError: "owner @org/platform has no escalation policy, set pagerduty or opsgenie",
+		},
+		"Unknown field": {
+			mapping: `
+teams:
+  "@org/platform":
+    victorops: team
+`,
+			expError: "while decoding on-call mapping: yaml: unmarshal errors:\n  line 4: field victorops not found in type oncall.Policy",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := oncall.ParseMapping(strings.NewReader(tc.mapping))
+
+			// then
+			assert.EqualError(t, err, tc.expError)
+		})
+	}
+}
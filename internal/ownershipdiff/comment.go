@@ -0,0 +1,156 @@
+package ownershipdiff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// CommentMarker identifies the pull request comment managed by the PRCommenter, so comments written by people
+// are never modified.
+const CommentMarker = "<!-- codeowners:ownership-moves -->"
+
+// MovesComment returns the Markdown body of the pull request comment about moved files which effective owners
+// changed. Owners which lose and gain files are mentioned, so both sides are notified about the transfer.
+// It returns an empty string if no moved file changed its owners.
+func MovesComment(changes []Change) string {
+	var (
+		moves           []Change
+		losing, gaining = map[string]struct{}{}, map[string]struct{}{}
+	)
+	for _, c := range changes {
+		if c.OldPath == "" {
+			continue
+		}
+		moves = append(moves, c)
+		for _, o := range missingOwners(c.OldOwners, c.NewOwners) {
+			losing[o] = struct{}{}
+		}
+		for _, o := range missingOwners(c.NewOwners, c.OldOwners) {
+			gaining[o] = struct{}{}
+		}
+	}
+	if len(moves) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s\n### Ownership changes caused by moved files\n\n", CommentMarker)
+	fmt.Fprintf(&out, "This pull request moves %d file(s) under CODEOWNERS patterns with different owners.\n\n", len(moves))
+	if len(losing) > 0 {
+		fmt.Fprintf(&out, "**Losing ownership:** %s\n", strings.Join(sortedOwners(losing), ", "))
+	}
+	if len(gaining) > 0 {
+		fmt.Fprintf(&out, "**Gaining ownership:** %s\n", strings.Join(sortedOwners(gaining), ", "))
+	}
+
+	out.WriteString("\n| File | Moved from | Previous owners | New owners |\n|---|---|---|---|\n")
+	for _, c := range moves {
+		fmt.Fprintf(&out, "| `%s` | `%s` | %s | %s |\n", c.Path, c.OldPath, quoteOwners(c.OldOwners), quoteOwners(c.NewOwners))
+	}
+
+	return out.String()
+}
+
+// missingOwners returns owners from a which are not listed in b.
+func missingOwners(a, b []string) []string {
+	var out []string
+	for _, o := range a {
+		found := false
+		for _, other := range b {
+			if strings.EqualFold(o, other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func sortedOwners(owners map[string]struct{}) []string {
+	out := make([]string, 0, len(owners))
+	for o := range owners {
+		out = append(out, o)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// quoteOwners formats owners as code, so owners are mentioned only once, in the summary.
+func quoteOwners(owners []string) string {
+	if len(owners) == 0 {
+		return "nobody"
+	}
+	out := make([]string, 0, len(owners))
+	for _, o := range owners {
+		out = append(out, "`"+o+"`")
+	}
+	return strings.Join(out, ", ")
+}
+
+// PRCommenter keeps a single pull request comment about ownership changes up to date.
+type PRCommenter struct {
+	client *github.Client
+}
+
+// NewPRCommenter returns new instance of the PRCommenter.
+func NewPRCommenter(client *github.Client) *PRCommenter {
+	return &PRCommenter{client: client}
+}
+
+// Sync creates or updates the comment marked with the CommentMarker on a given pull request. The body which
+// didn't change is not updated, so reviewers are not notified on every push. An empty body deletes the comment,
+// e.g. once the moves which changed owners were reverted.
+func (c *PRCommenter) Sync(ctx context.Context, repo string, number int, body string) error {
+	idx := strings.LastIndex(repo, "/")
+	if idx <= 0 || idx == len(repo)-1 {
+		return errors.Errorf("wrong repository name, expected pattern 'owner/repository', got %q", repo)
+	}
+	owner, name := repo[:idx], repo[idx+1:]
+
+	existing, err := c.find(ctx, owner, name, number)
+	if err != nil {
+		return errors.Wrapf(err, "while listing comments of pull request #%d", number)
+	}
+
+	switch {
+	case existing == nil && body == "":
+	case existing == nil:
+		_, _, err = c.client.Issues.CreateComment(ctx, owner, name, number, &github.IssueComment{Body: &body})
+	case body == "":
+		_, err = c.client.Issues.DeleteComment(ctx, owner, name, existing.GetID())
+	case existing.GetBody() != body:
+		_, _, err = c.client.Issues.EditComment(ctx, owner, name, existing.GetID(), &github.IssueComment{Body: &body})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "while saving comment of pull request #%d", number)
+	}
+	return nil
+}
+
+// find returns the comment marked with the CommentMarker, or nil if the pull request doesn't have it.
+func (c *PRCommenter) find(ctx context.Context, owner, name string, number int) (*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.client.Issues.ListComments(ctx, owner, name, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range page {
+			if strings.HasPrefix(comment.GetBody(), CommentMarker) {
+				return comment, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
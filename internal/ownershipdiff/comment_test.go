@@ -0,0 +1,125 @@
+package ownershipdiff_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/ownershipdiff"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovesComment(t *testing.T) {
+	tests := map[string]struct {
+		givenChanges []ownershipdiff.Change
+		expBody      string
+	}{
+		"Moved files": {
+			givenChanges: []ownershipdiff.Change{
+				{Path: "api/util.go", OldPath: "lib/util.go", OldOwners: []string{"@org/core"}, NewOwners: []string{"@org/api", "@alice"}, Reason: ownershipdiff.MovedAndRulesChanged},
+				{Path: "web/b.go", OldPath: "api/b.go", OldOwners: []string{"@org/api"}, NewOwners: []string{}, Reason: ownershipdiff.Moved},
+				{Path: "web/index.html", OldOwners: []string{"@org/web"}, NewOwners: []string{"@org/frontend"}, Reason: ownershipdiff.RulesChanged},
+			},
+			expBody: ownershipdiff.CommentMarker + "\n" +
+				"### Ownership changes caused by moved files\n\n" +
+				"This pull request moves 2 file(s) under CODEOWNERS patterns with different owners.\n\n" +
+				"**Losing ownership:** @org/api, @org/core\n" +
+				"**Gaining ownership:** @alice, @org/api\n\n" +
+				"| File | Moved from | Previous owners | New owners |\n" +
+				"|---|---|---|---|\n" +
+				"| `api/util.go` | `lib/util.go` | `@org/core` | `@org/api`, `@alice` |\n" +
+				"| `web/b.go` | `api/b.go` | `@org/api` | nobody |\n",
+		},
+		"Only rules changed": {
+			givenChanges: []ownershipdiff.Change{
+				{Path: "web/index.html", OldOwners: []string{"@org/web"}, NewOwners: []string{"@org/frontend"}, Reason: ownershipdiff.RulesChanged},
+			},
+			expBody: "",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			body := ownershipdiff.MovesComment(tc.givenChanges)
+
+			// then
+			assert.Equal(t, tc.expBody, body)
+		})
+	}
+}
+
+func TestPRCommenterSync(t *testing.T) {
+	markedComment := fmt.Sprintf(`[{"id": 1, "body": "LGTM"}, {"id": 2, "body": %q}]`, ownershipdiff.CommentMarker+"\nold")
+
+	tests := map[string]struct {
+		givenComments string
+		givenBody     string
+		expRequest    string
+		expBody       string
+	}{
+		"Should create the comment": {
+			givenComments: `[{"id": 1, "body": "LGTM"}]`,
+			givenBody:     ownershipdiff.CommentMarker + "\nnew",
+			expRequest:    "POST /repos/org/repo/issues/7/comments",
+			expBody:       fmt.Sprintf(`{"body": %q}`, ownershipdiff.CommentMarker+"\nnew"),
+		},
+		"Should update the marked comment": {
+			givenComments: markedComment,
+			givenBody:     ownershipdiff.CommentMarker + "\nnew",
+			expRequest:    "PATCH /repos/org/repo/issues/comments/2",
+			expBody:       fmt.Sprintf(`{"body": %q}`, ownershipdiff.CommentMarker+"\nnew"),
+		},
+		"Should delete the marked comment when there are no moves": {
+			givenComments: markedComment,
+			givenBody:     "",
+			expRequest:    "DELETE /repos/org/repo/issues/comments/2",
+		},
+		"Should not update the unchanged comment": {
+			givenComments: markedComment,
+			givenBody:     ownershipdiff.CommentMarker + "\nold",
+		},
+		"Should not create an empty comment": {
+			givenComments: `[]`,
+			givenBody:     "",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			var gotRequest, gotBody string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					assert.Equal(t, "/repos/org/repo/issues/7/comments", r.URL.Path)
+					w.Write([]byte(tc.givenComments))
+					return
+				}
+				raw, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				gotRequest, gotBody = r.Method+" "+r.URL.Path, string(raw)
+				w.Write([]byte(`{"id": 3}`))
+			}))
+			defer srv.Close()
+
+			client := github.NewClient(nil)
+			client.BaseURL, _ = url.Parse(srv.URL + "/")
+			sut := ownershipdiff.NewPRCommenter(client)
+
+			// when
+			err := sut.Sync(context.Background(), "org/repo", 7, tc.givenBody)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expRequest, gotRequest)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, gotBody)
+			}
+		})
+	}
+}
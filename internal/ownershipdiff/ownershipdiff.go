@@ -0,0 +1,198 @@
+package ownershipdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// codeownersLocations are the CODEOWNERS locations in the order used by GitHub.
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Reason describes why the effective owner of a file changed.
+type Reason string
+
+// Reasons of ownership changes.
+const (
+	// RulesChanged means that the file stayed in place, but the CODEOWNERS rules changed.
+	RulesChanged Reason = "rules-changed"
+	// Moved means that the file moved under a pattern with different owners.
+	Moved Reason = "moved"
+	// MovedAndRulesChanged means that the file moved and the rules for its previous path changed too.
+	MovedAndRulesChanged Reason = "moved-and-rules-changed"
+)
+
+// Change is a file which effective owners differ between two revisions.
+type Change struct {
+	// Path of the file in the head revision.
+	Path string `json:"path"`
+	// OldPath is the path in the base revision, set only for moved files.
+	OldPath   string   `json:"oldPath,omitempty"`
+	OldOwners []string `json:"oldOwners"`
+	NewOwners []string `json:"newOwners"`
+	Reason    Reason   `json:"reason"`
+}
+
+// Compute returns files present in both revisions which effective owners differ. Files are tracked across
+// renames, so a file moved under a pattern with different owners is reported even if CODEOWNERS did not change.
+// Added and deleted files are not reported.
+func Compute(repoDir, baseRef, headRef string) ([]Change, error) {
+	baseMatcher, err := MatcherAt(repoDir, baseRef)
+	if err != nil {
+		return nil, err
+	}
+	headMatcher, err := MatcherAt(repoDir, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	headFiles, err := git.ListTree(repoDir, headRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while listing files at %s", headRef)
+	}
+	changed, err := git.ChangedFilesBetween(repoDir, baseRef, headRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while computing changes between %s and %s", baseRef, headRef)
+	}
+
+	oldPaths := map[string]string{}
+	added := map[string]struct{}{}
+	for _, f := range changed {
+		switch f.Status {
+		case git.Renamed:
+			oldPaths[f.Path] = f.OldPath
+		case git.Added:
+			added[f.Path] = struct{}{}
+		}
+	}
+
+	var out []Change
+	for _, path := range headFiles {
+		if _, found := added[path]; found {
+			continue
+		}
+		oldPath, moved := oldPaths[path]
+		if !moved {
+			oldPath = path
+		}
+
+		oldOwners, newOwners := ownersOf(baseMatcher, oldPath), ownersOf(headMatcher, path)
+		if equalOwners(oldOwners, newOwners) {
+			continue
+		}
+
+		change := Change{Path: path, OldOwners: oldOwners, NewOwners: newOwners, Reason: RulesChanged}
+		if moved {
+			change.OldPath = oldPath
+			change.Reason = Moved
+			if !equalOwners(oldOwners, ownersOf(headMatcher, oldPath)) {
+				change.Reason = MovedAndRulesChanged
+			}
+		}
+		out = append(out, change)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Path < out[j].Path
+	})
+
+	return out, nil
+}
+
+// MatcherAt returns the matcher of the CODEOWNERS file at a given revision. If the revision doesn't have
+// the CODEOWNERS file, the matcher doesn't match any file.
+func MatcherAt(repoDir, ref string) (*codeowners.Matcher, error) {
+	files, err := git.ListTree(repoDir, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while listing files at %s", ref)
+	}
+	tracked := map[string]struct{}{}
+	for _, f := range files {
+		tracked[f] = struct{}{}
+	}
+
+	var entries []codeowners.Entry
+	for _, loc := range codeownersLocations {
+		if _, found := tracked[loc]; !found {
+			continue
+		}
+		raw, err := git.ShowFile(repoDir, ref, loc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while reading %s at %s", loc, ref)
+		}
+		entries = codeowners.ParseCodeowners(bytes.NewReader(raw))
+		break
+	}
+
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while compiling CODEOWNERS patterns at %s", ref)
+	}
+	return matcher, nil
+}
+
+func ownersOf(m *codeowners.Matcher, path string) []string {
+	e, found := m.Match(path)
+	if !found || len(e.Owners) == 0 {
+		return []string{}
+	}
+	return e.Owners
+}
+
+func equalOwners(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteJSON writes changes as a JSON array.
+func WriteJSON(w io.Writer, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(changes)
+}
+
+// WriteText writes changes in a human-readable form, one file per line.
+func WriteText(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No effective ownership changes")
+		return err
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Effective ownership changed for %d file(s):\n", len(changes))
+	for _, c := range changes {
+		path := c.Path
+		if c.OldPath != "" {
+			path = fmt.Sprintf("%s (moved from %s)", c.Path, c.OldPath)
+		}
+		fmt.Fprintf(&out, "    %s: %s -> %s [%s]\n", path, describeOwners(c.OldOwners), describeOwners(c.NewOwners), c.Reason)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func describeOwners(owners []string) string {
+	if len(owners) == 0 {
+		return "nobody"
+	}
+	return strings.Join(owners, ", ")
+}
@@ -0,0 +1,98 @@
+package ownershipdiff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/ownershipdiff"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+
+	gitrepo.WriteFiles(t, repoDir, map[string]string{
+		".github/CODEOWNERS": "*       @org/core\n/api/   @org/api\n/web/   @org/web\n",
+		"README":             "readme",
+		"api/a.go":           "package api // a",
+		"api/b.go":           "package api // b",
+		"lib/util.go":        "package lib // util",
+		"web/index.html":     "<html></html>",
+	})
+	gitrepo.Run(t, repoDir, "add", "-A")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "base")
+	gitrepo.Run(t, repoDir, "tag", "base")
+
+	gitrepo.WriteFiles(t, repoDir, map[string]string{
+		".github/CODEOWNERS": "*       @org/core\n/api/   @org/api\n/web/   @org/frontend\n/lib/   @org/lib\n",
+		"new.go":             "package main",
+	})
+	gitrepo.Run(t, repoDir, "mv", "api/b.go", "web/b.go")
+	gitrepo.Run(t, repoDir, "mv", "lib/util.go", "api/util.go")
+	gitrepo.Run(t, repoDir, "add", "-A")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "head")
+
+	expChanges := []ownershipdiff.Change{
+		{Path: "api/util.go", OldPath: "lib/util.go", OldOwners: []string{"@org/core"}, NewOwners: []string{"@org/api"}, Reason: ownershipdiff.MovedAndRulesChanged},
+		{Path: "web/b.go", OldPath: "api/b.go", OldOwners: []string{"@org/api"}, NewOwners: []string{"@org/frontend"}, Reason: ownershipdiff.Moved},
+		{Path: "web/index.html", OldOwners: []string{"@org/web"}, NewOwners: []string{"@org/frontend"}, Reason: ownershipdiff.RulesChanged},
+	}
+
+	// when
+	changes, err := ownershipdiff.Compute(repoDir, "base", "HEAD")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, expChanges, changes)
+}
+
+func TestWriteText(t *testing.T) {
+	tests := map[string]struct {
+		givenChanges []ownershipdiff.Change
+		expOutput    string
+	}{
+		"Changes": {
+			givenChanges: []ownershipdiff.Change{
+				{Path: "web/b.go", OldPath: "api/b.go", OldOwners: []string{"@org/api"}, NewOwners: []string{}, Reason: ownershipdiff.Moved},
+				{Path: "web/index.html", OldOwners: []string{"@org/web"}, NewOwners: []string{"@org/frontend", "@alice"}, Reason: ownershipdiff.RulesChanged},
+			},
+			expOutput: "Effective ownership changed for 2 file(s):\n" +
+				"    web/b.go (moved from api/b.go): @org/api -> nobody [moved]\n" +
+				"    web/index.html: @org/web -> @org/frontend, @alice [rules-changed]\n",
+		},
+		"No changes": {
+			expOutput: "No effective ownership changes\n",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			var buff bytes.Buffer
+
+			// when
+			err := ownershipdiff.WriteText(&buff, tc.givenChanges)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOutput, buff.String())
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	// given
+	var buff bytes.Buffer
+
+	// when
+	err := ownershipdiff.WriteJSON(&buff, []ownershipdiff.Change{
+		{Path: "web/b.go", OldPath: "api/b.go", OldOwners: []string{"@org/api"}, NewOwners: []string{}, Reason: ownershipdiff.Moved},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"path": "web/b.go", "oldPath": "api/b.go", "oldOwners": ["@org/api"], "newOwners": [], "reason": "moved"}]`, buff.String())
+}
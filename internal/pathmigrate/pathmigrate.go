@@ -0,0 +1,313 @@
+// Package pathmigrate rewrites CODEOWNERS patterns after directories are moved, e.g. during a monorepo restructure,
+// so moved files keep their owners, and verifies that ownership of other files doesn't change.
+package pathmigrate
+
+import (
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// Rename is a single moved directory or file, with slash-separated paths relative to the repository root.
+type Rename struct {
+	From string
+	To   string
+}
+
+// ParseRename parses a rename written as `old/dir=new/dir`. Leading and trailing slashes are ignored.
+func ParseRename(in string) (Rename, error) {
+	from, to, found := strings.Cut(in, "=")
+	if !found {
+		return Rename{}, errors.Errorf("rename %q must be in the form old/dir=new/dir", in)
+	}
+
+	r := Rename{From: strings.Trim(from, "/"), To: strings.Trim(to, "/")}
+	if r.From == "" || r.To == "" {
+		return Rename{}, errors.Errorf("rename %q must have both paths, the repository root cannot be moved", in)
+	}
+	if strings.ContainsAny(r.From+r.To, "*?[") {
+		return Rename{}, errors.Errorf("rename %q cannot have wildcards", in)
+	}
+	if r.From == r.To {
+		return Rename{}, errors.Errorf("rename %q doesn't change the path", in)
+	}
+	return r, nil
+}
+
+// ParseRenames parses given renames. Renames of the same path to different destinations are rejected.
+func ParseRenames(in []string) ([]Rename, error) {
+	var out []Rename
+	for _, raw := range in {
+		r, err := ParseRename(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, checkConflicts(out)
+}
+
+func checkConflicts(renames []Rename) error {
+	dest := map[string]string{}
+	for _, r := range renames {
+		if to, found := dest[r.From]; found && to != r.To {
+			return errors.Errorf("path %s is renamed to both %s and %s", r.From, to, r.To)
+		}
+		dest[r.From] = r.To
+	}
+	return nil
+}
+
+// DetectRenames returns renames of directories derived from renamed files, e.g. as reported by `git diff -M`.
+// A directory is considered moved only if all its renamed files moved to the same directory and none of its files
+// is left in the current files. Otherwise, files are renamed one by one, so patterns of the directory
+// are not rewritten for files which didn't move. Renames of subdirectories implied by renames of their parents
+// are dropped.
+func DetectRenames(changes []git.ChangedFile, files []string) []Rename {
+	type dirMove struct {
+		to    string
+		files []Rename
+		split bool
+	}
+
+	var (
+		fileRenames []Rename
+		moves       = map[string]*dirMove{}
+	)
+	for _, c := range changes {
+		if c.Status != git.Renamed {
+			continue
+		}
+		file := Rename{From: c.OldPath, To: c.Path}
+
+		from, to := movedDirs(c.OldPath, c.Path)
+		if from == "" || to == "" {
+			fileRenames = append(fileRenames, file)
+			continue
+		}
+
+		m, found := moves[from]
+		if !found {
+			m = &dirMove{to: to}
+			moves[from] = m
+		}
+		m.split = m.split || m.to != to
+		m.files = append(m.files, file)
+	}
+
+	var dirRenames []Rename
+	for from, m := range moves {
+		if m.split || hasFileUnder(files, from) {
+			fileRenames = append(fileRenames, m.files...)
+			continue
+		}
+		dirRenames = append(dirRenames, Rename{From: from, To: m.to})
+	}
+
+	var out []Rename
+	for _, r := range append(dirRenames, fileRenames...) {
+		if impliedByParent(r, dirRenames) {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].From < out[j].From })
+	return out
+}
+
+// movedDirs returns directories of a renamed file if only the directory changed, e.g. `old/dir` and `new/dir`
+// for `old/dir/a.go` renamed to `new/dir/a.go`. Both are empty if the file name changed or the file moved
+// from or to the repository root.
+func movedDirs(oldPath, newPath string) (string, string) {
+	oldDir, oldName := splitPath(oldPath)
+	newDir, newName := splitPath(newPath)
+	if oldName != newName {
+		return "", ""
+	}
+	return oldDir, newDir
+}
+
+func splitPath(path string) (string, string) {
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 {
+		return "", path
+	}
+	return path[:idx], path[idx+1:]
+}
+
+// impliedByParent returns true if a given rename follows from the rename of a parent directory.
+func impliedByParent(r Rename, dirRenames []Rename) bool {
+	for _, parent := range dirRenames {
+		if strings.HasPrefix(r.From, parent.From+"/") && r.To == parent.To+strings.TrimPrefix(r.From, parent.From) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFileUnder(files []string, dir string) bool {
+	for _, f := range files {
+		if strings.HasPrefix(f, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RewritePattern returns a given pattern with the moved path replaced, and whether it was rewritten.
+// Only patterns anchored to the repository root which start with a moved path are rewritten, e.g. `/old/dir/**/*.go`
+// becomes `/new/dir/**/*.go`. Patterns which match at any level, such as `*.go` or `**/dir/`, are left as they are.
+// The most specific rename wins.
+func RewritePattern(pattern string, renames []Rename) (string, bool) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(trimmed, "/") || strings.HasPrefix(pattern, "**/") {
+		// the pattern matches at any level
+		return pattern, false
+	}
+
+	rooted := strings.HasPrefix(pattern, "/")
+	body := strings.TrimPrefix(pattern, "/")
+
+	var best *Rename
+	for idx, r := range renames {
+		if body != r.From && !strings.HasPrefix(body, r.From+"/") {
+			continue
+		}
+		if best == nil || len(r.From) > len(best.From) {
+			best = &renames[idx]
+		}
+	}
+	if best == nil {
+		return pattern, false
+	}
+
+	body = best.To + strings.TrimPrefix(body, best.From)
+	// a pattern without a slash other than the trailing one would match at any level, so it must stay anchored
+	if rooted || !strings.Contains(strings.TrimSuffix(body, "/"), "/") {
+		body = "/" + body
+	}
+	return body, true
+}
+
+// Rewrite rewrites patterns of all entries of a given CODEOWNERS AST. It returns the number of rewritten entries.
+func Rewrite(ast *codeowners.AST, renames []Rename) int {
+	rewritten := 0
+	for _, e := range ast.Entries() {
+		if ast.EditPattern(e.LineNo, func(pattern string) string {
+			out, _ := RewritePattern(pattern, renames)
+			return out
+		}) {
+			rewritten++
+		}
+	}
+	return rewritten
+}
+
+// Change is a file which effective owners differ before and after the migration.
+type Change struct {
+	// Path of the file after the migration.
+	Path string
+	// OldPath is the path before the migration, set only for moved files.
+	OldPath   string
+	OldOwners []string
+	NewOwners []string
+}
+
+// Report holds ownership changes caused by the migration.
+type Report struct {
+	// Moved are moved files which owners at the new path differ from the owners at the old path, e.g. because
+	// the new path is matched by a pattern which matches at any level.
+	Moved []Change
+	// Unrelated are files outside the moved paths which owners changed. The migration should not change them.
+	Unrelated []Change
+}
+
+// Verify compares ownership of given files before and after the migration. Files may be listed either before
+// or after they are moved. Files under the old or new path of a rename are compared with their counterpart
+// path, all other files with themselves.
+func Verify(before, after []codeowners.Entry, files []string, renames []Rename) (Report, error) {
+	beforeMatcher, err := codeowners.NewMatcher(before)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "while compiling entries before migration")
+	}
+	afterMatcher, err := codeowners.NewMatcher(after)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "while compiling entries after migration")
+	}
+
+	var (
+		out  Report
+		seen = map[string]struct{}{}
+	)
+	for _, f := range files {
+		oldPath, newPath := f, f
+		if moved, ok := movePath(f, renames, false); ok {
+			newPath = moved
+		} else if original, ok := movePath(f, renames, true); ok {
+			oldPath = original
+		}
+		if _, dup := seen[newPath]; dup {
+			continue
+		}
+		seen[newPath] = struct{}{}
+
+		oldOwners, newOwners := ownersOf(beforeMatcher, oldPath), ownersOf(afterMatcher, newPath)
+		if equalOwners(oldOwners, newOwners) {
+			continue
+		}
+
+		change := Change{Path: newPath, OldOwners: oldOwners, NewOwners: newOwners}
+		if oldPath == newPath {
+			out.Unrelated = append(out.Unrelated, change)
+			continue
+		}
+		change.OldPath = oldPath
+		out.Moved = append(out.Moved, change)
+	}
+	return out, nil
+}
+
+// movePath returns the path of a given file after the most specific rename, or before it if reverse is true.
+func movePath(path string, renames []Rename, reverse bool) (string, bool) {
+	var (
+		out     string
+		longest = -1
+	)
+	for _, r := range renames {
+		from, to := r.From, r.To
+		if reverse {
+			from, to = to, from
+		}
+		if path != from && !strings.HasPrefix(path, from+"/") {
+			continue
+		}
+		if len(from) > longest {
+			out, longest = to+strings.TrimPrefix(path, from), len(from)
+		}
+	}
+	return out, longest >= 0
+}
+
+func ownersOf(m *codeowners.Matcher, path string) []string {
+	e, found := m.Match(path)
+	if !found {
+		return nil
+	}
+	return e.Owners
+}
+
+func equalOwners(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if !strings.EqualFold(a[idx], b[idx]) {
+			return false
+		}
+	}
+	return true
+}
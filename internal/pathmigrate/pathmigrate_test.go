@@ -0,0 +1,236 @@
+package pathmigrate_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/pathmigrate"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRenames(t *testing.T) {
+	tests := map[string]struct {
+		givenRenames []string
+		expRenames   []pathmigrate.Rename
+		expErr       string
+	}{
+		"Should trim slashes": {
+			givenRenames: []string{"/services/payments/=platform/payments", "docs=handbook"},
+			expRenames: []pathmigrate.Rename{
+				{From: "services/payments", To: "platform/payments"},
+				{From: "docs", To: "handbook"},
+			},
+		},
+		"Should reject rename without separator": {
+			givenRenames: []string{"services/payments"},
+			expErr:       `rename "services/payments" must be in the form old/dir=new/dir`,
+		},
+		"Should reject rename of the repository root": {
+			givenRenames: []string{"/=platform"},
+			expErr:       `rename "/=platform" must have both paths, the repository root cannot be moved`,
+		},
+		"Should reject wildcards": {
+			givenRenames: []string{"services/*=platform"},
+			expErr:       `rename "services/*=platform" cannot have wildcards`,
+		},
+		"Should reject the same path": {
+			givenRenames: []string{"docs/=/docs"},
+			expErr:       `rename "docs/=/docs" doesn't change the path`,
+		},
+		"Should reject conflicting renames": {
+			givenRenames: []string{"docs=handbook", "docs=guides"},
+			expErr:       "path docs is renamed to both handbook and guides",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			renames, err := pathmigrate.ParseRenames(tc.givenRenames)
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expRenames, renames)
+		})
+	}
+}
+
+func TestDetectRenames(t *testing.T) {
+	// given
+	changes := []git.ChangedFile{
+		{Path: "platform/payments/api/a.go", OldPath: "services/payments/api/a.go", Status: git.Renamed},
+		{Path: "platform/payments/b.go", OldPath: "services/payments/b.go", Status: git.Renamed},
+		// the rest of the directory stays in place
+		{Path: "shared/util.go", OldPath: "lib/util.go", Status: git.Renamed},
+		// files of the directory moved to different places
+		{Path: "web/index.html", OldPath: "site/index.html", Status: git.Renamed},
+		{Path: "docs/site.md", OldPath: "site/site.md", Status: git.Renamed},
+		// the file name changed
+		{Path: "tools/build.sh", OldPath: "tools/make.sh", Status: git.Renamed},
+		{Path: "main.go", Status: git.Modified},
+	}
+	files := []string{"platform/payments/api/a.go", "platform/payments/b.go", "shared/util.go", "lib/other.go", "web/index.html", "docs/site.md", "tools/build.sh", "main.go"}
+
+	// when
+	renames := pathmigrate.DetectRenames(changes, files)
+
+	// then
+	assert.Equal(t, []pathmigrate.Rename{
+		{From: "lib/util.go", To: "shared/util.go"},
+		{From: "services/payments", To: "platform/payments"},
+		{From: "site/index.html", To: "web/index.html"},
+		{From: "site/site.md", To: "docs/site.md"},
+		{From: "tools/make.sh", To: "tools/build.sh"},
+	}, renames)
+}
+
+func TestRewritePattern(t *testing.T) {
+	renames := []pathmigrate.Rename{
+		{From: "services/payments", To: "platform/payments"},
+		{From: "services/payments/legacy", To: "archive/payments"},
+		{From: "docs/api", To: "api"},
+	}
+
+	tests := map[string]struct {
+		givenPattern string
+		expPattern   string
+		expRewritten bool
+	}{
+		"Should rewrite the directory": {
+			givenPattern: "/services/payments/",
+			expPattern:   "/platform/payments/",
+			expRewritten: true,
+		},
+		"Should rewrite the directory without the trailing slash": {
+			givenPattern: "/services/payments",
+			expPattern:   "/platform/payments",
+			expRewritten: true,
+		},
+		"Should rewrite patterns under the directory": {
+			givenPattern: "services/payments/**/*.go",
+			expPattern:   "platform/payments/**/*.go",
+			expRewritten: true,
+		},
+		"Should use the most specific rename": {
+			givenPattern: "/services/payments/legacy/db/",
+			expPattern:   "/archive/payments/db/",
+			expRewritten: true,
+		},
+		"Should keep the pattern anchored": {
+			givenPattern: "docs/api/",
+			expPattern:   "/api/",
+			expRewritten: true,
+		},
+		"Should not rewrite directory with the same prefix": {
+			givenPattern: "/services/payments-v2/",
+			expPattern:   "/services/payments-v2/",
+		},
+		"Should not rewrite parent directory": {
+			givenPattern: "/services/",
+			expPattern:   "/services/",
+		},
+		"Should not rewrite pattern matching at any level": {
+			givenPattern: "payments/",
+			expPattern:   "payments/",
+		},
+		"Should not rewrite pattern with leading double asterisk": {
+			givenPattern: "**/services/payments/",
+			expPattern:   "**/services/payments/",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			pattern, rewritten := pathmigrate.RewritePattern(tc.givenPattern, renames)
+
+			// then
+			assert.Equal(t, tc.expPattern, pattern)
+			assert.Equal(t, tc.expRewritten, rewritten)
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	// given
+	ast, err := codeowners.ParseAST(strings.NewReader("# Payments\n*                   @org/core\n/services/payments/  @org/payments # team\n*.md                @org/docs\n"))
+	require.NoError(t, err)
+
+	// when
+	rewritten := pathmigrate.Rewrite(ast, []pathmigrate.Rename{{From: "services/payments", To: "platform/payments"}})
+
+	// then
+	assert.Equal(t, 1, rewritten)
+	assert.Equal(t, "# Payments\n*                   @org/core\n/platform/payments/  @org/payments # team\n*.md                @org/docs\n", string(ast.Bytes()))
+}
+
+func TestVerify(t *testing.T) {
+	renames := []pathmigrate.Rename{{From: "services/payments", To: "platform/payments"}}
+	before := []codeowners.Entry{
+		{LineNo: 1, Pattern: "*", Owners: []string{"@org/core"}},
+		{LineNo: 2, Pattern: "/platform/", Owners: []string{"@org/platform"}},
+		{LineNo: 3, Pattern: "/services/payments/", Owners: []string{"@org/payments"}},
+	}
+	migrated := []codeowners.Entry{
+		{LineNo: 1, Pattern: "*", Owners: []string{"@org/core"}},
+		{LineNo: 2, Pattern: "/platform/", Owners: []string{"@org/platform"}},
+		{LineNo: 3, Pattern: "/platform/payments/", Owners: []string{"@org/payments"}},
+	}
+
+	tests := map[string]struct {
+		givenAfter []codeowners.Entry
+		givenFiles []string
+		expReport  pathmigrate.Report
+	}{
+		"Should report nothing if ownership is kept": {
+			givenAfter: migrated,
+			givenFiles: []string{"services/payments/a.go", "platform/infra/main.tf", "README.md"},
+		},
+		"Should compare files listed after they are moved": {
+			givenAfter: migrated,
+			givenFiles: []string{"platform/payments/a.go", "platform/infra/main.tf", "README.md"},
+		},
+		"Should report moved files which owners changed": {
+			givenAfter: []codeowners.Entry{
+				{LineNo: 1, Pattern: "*", Owners: []string{"@org/core"}},
+				{LineNo: 2, Pattern: "/platform/payments/", Owners: []string{"@org/payments"}},
+				{LineNo: 3, Pattern: "/platform/", Owners: []string{"@org/platform"}},
+			},
+			givenFiles: []string{"services/payments/a.go", "README.md"},
+			expReport: pathmigrate.Report{
+				Moved: []pathmigrate.Change{
+					{Path: "platform/payments/a.go", OldPath: "services/payments/a.go", OldOwners: []string{"@org/payments"}, NewOwners: []string{"@org/platform"}},
+				},
+			},
+		},
+		"Should report unrelated files which owners changed": {
+			givenAfter: []codeowners.Entry{
+				{LineNo: 1, Pattern: "*", Owners: []string{"@org/core"}},
+				{LineNo: 2, Pattern: "/platform/", Owners: []string{"@org/infra"}},
+				{LineNo: 3, Pattern: "/platform/payments/", Owners: []string{"@org/payments"}},
+			},
+			givenFiles: []string{"services/payments/a.go", "platform/infra/main.tf"},
+			expReport: pathmigrate.Report{
+				Unrelated: []pathmigrate.Change{
+					{Path: "platform/infra/main.tf", OldOwners: []string{"@org/platform"}, NewOwners: []string{"@org/infra"}},
+				},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			report, err := pathmigrate.Verify(before, tc.givenAfter, tc.givenFiles, renames)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expReport, report)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package placeholder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderRegexp matches placeholders such as `{{TEAM_BACKEND}}` or `{{ TEAM_BACKEND }}`.
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Values maps placeholder names to their values.
+type Values map[string]string
+
+// LoadValues reads placeholder values from a YAML or JSON file. Each value is either a string,
+// or a list of strings which are joined with spaces, e.g. to expand a single placeholder into multiple owners:
+//
+//	TEAM_BACKEND: "@org/backend"
+//	REVIEWERS: ["@org/security", "@alice"]
+func LoadValues(path string) (Values, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(raw, &parsed); err != nil {
+		return nil, errors.Wrapf(err, "while parsing %s", path)
+	}
+
+	out := Values{}
+	for name, v := range parsed {
+		var value string
+		switch v := v.(type) {
+		case string:
+			value = v
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, errors.Errorf("value of %q must be a string or a list of strings", name)
+				}
+				items = append(items, s)
+			}
+			value = strings.Join(items, " ")
+		default:
+			return nil, errors.Errorf("value of %q must be a string or a list of strings", name)
+		}
+
+		// values must not change line numbers reported by checks
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errors.Errorf("value of %q must not contain line breaks", name)
+		}
+		out[name] = value
+	}
+
+	return out, nil
+}
+
+// Render replaces all placeholders in the content with their values. It fails if any placeholder has no value.
+func Render(content []byte, values Values) ([]byte, error) {
+	var unresolved []string
+	for idx, line := range bytes.Split(content, []byte("\n")) {
+		for _, m := range placeholderRegexp.FindAllSubmatch(line, -1) {
+			if _, found := values[string(m[1])]; !found {
+				unresolved = append(unresolved, fmt.Sprintf("%s (line %d)", m[1], idx+1))
+			}
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, errors.Errorf("unresolved placeholders: %s", strings.Join(unresolved, ", "))
+	}
+
+	return placeholderRegexp.ReplaceAllFunc(content, func(m []byte) []byte {
+		name := placeholderRegexp.FindSubmatch(m)[1]
+		return []byte(values[string(name)])
+	}), nil
+}
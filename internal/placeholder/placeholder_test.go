@@ -0,0 +1,100 @@
+package placeholder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/placeholder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadValues(t *testing.T) {
+	tests := map[string]struct {
+		givenContent string
+		expValues    placeholder.Values
+		expErrString string
+	}{
+		"YAML with strings and lists": {
+			givenContent: "TEAM_BACKEND: \"@org/backend\"\nREVIEWERS:\n  - \"@org/security\"\n  - \"@alice\"\n",
+			expValues: placeholder.Values{
+				"TEAM_BACKEND": "@org/backend",
+				"REVIEWERS":    "@org/security @alice",
+			},
+		},
+		"JSON": {
+			givenContent: `{"TEAM_BACKEND": "@org/backend"}`,
+			expValues:    placeholder.Values{"TEAM_BACKEND": "@org/backend"},
+		},
+		"Value with line break": {
+			givenContent: "TEAM_BACKEND: \"@org/backend\\n@org/frontend\"\n",
+			expErrString: `value of "TEAM_BACKEND" must not contain line breaks`,
+		},
+		"Value of unsupported type": {
+			givenContent: "TEAM_BACKEND: 42\n",
+			expErrString: `value of "TEAM_BACKEND" must be a string or a list of strings`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), "values.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.givenContent), 0o600))
+
+			// when
+			values, err := placeholder.LoadValues(path)
+
+			// then
+			if tc.expErrString != "" {
+				require.EqualError(t, err, tc.expErrString)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expValues, values)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		givenContent string
+		givenValues  placeholder.Values
+		expContent   string
+		expErrString string
+	}{
+		"Resolves all placeholders": {
+			givenContent: "*        {{TEAM_DEFAULT}}\n/api/    {{ TEAM_BACKEND }} {{REVIEWERS}} # {{TEAM_BACKEND}}\n",
+			givenValues: placeholder.Values{
+				"TEAM_DEFAULT": "@org/core",
+				"TEAM_BACKEND": "@org/backend",
+				"REVIEWERS":    "@org/security @alice",
+			},
+			expContent: "*        @org/core\n/api/    @org/backend @org/security @alice # @org/backend\n",
+		},
+		"Content without placeholders": {
+			givenContent: "* @org/core\n",
+			expContent:   "* @org/core\n",
+		},
+		"Unresolved placeholders": {
+			givenContent: "*        {{TEAM_DEFAULT}}\n/api/    {{TEAM_BACKEND}}\n/web/    {{TEAM_FRONTEND}}\n",
+			givenValues:  placeholder.Values{"TEAM_DEFAULT": "@org/core"},
+			expErrString: "unresolved placeholders: TEAM_BACKEND (line 2), TEAM_FRONTEND (line 3)",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			out, err := placeholder.Render([]byte(tc.givenContent), tc.givenValues)
+
+			// then
+			if tc.expErrString != "" {
+				require.EqualError(t, err, tc.expErrString)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expContent, string(out))
+		})
+	}
+}
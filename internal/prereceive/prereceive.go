@@ -0,0 +1,187 @@
+package prereceive
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// ZeroRev is the revision used by git for a created or deleted ref.
+const ZeroRev = "0000000000000000000000000000000000000000"
+
+// gitEnv are the environment variables set by git for hooks. They point to the receiving repository
+// and the quarantined objects, so they must not leak into git commands executed in a checkout.
+var gitEnv = []string{
+	"GIT_DIR", "GIT_WORK_TREE", "GIT_INDEX_FILE", "GIT_OBJECT_DIRECTORY",
+	"GIT_ALTERNATE_OBJECT_DIRECTORIES", "GIT_QUARANTINE_PATH",
+}
+
+// codeownersLocations are the paths where the CODEOWNERS file is searched for.
+var codeownersLocations = []string{"CODEOWNERS", "docs/CODEOWNERS", ".github/CODEOWNERS"}
+
+// Update is a single ref update read from the pre-receive hook input.
+type Update struct {
+	OldRev string
+	NewRev string
+	Ref    string
+}
+
+// IsDelete returns true if the update deletes the ref.
+func (u Update) IsDelete() bool {
+	return u.NewRev == ZeroRev
+}
+
+// IsCreate returns true if the update creates the ref.
+func (u Update) IsCreate() bool {
+	return u.OldRev == ZeroRev
+}
+
+// ParseUpdates parses the pre-receive hook input, which contains one `<old-rev> <new-rev> <ref>` line per updated ref.
+func ParseUpdates(r io.Reader) ([]Update, error) {
+	var out []Update
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, errors.Errorf("line %d: expected '<old-rev> <new-rev> <ref>', got %q", lineNo, line)
+		}
+		out = append(out, Update{OldRev: fields[0], NewRev: fields[1], Ref: fields[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "while reading ref updates")
+	}
+
+	return out, nil
+}
+
+// MatchRef returns true if the ref matches any of the given glob patterns, e.g. `refs/heads/*`.
+// Empty patterns match all refs.
+func MatchRef(ref string, patterns []string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	for _, p := range patterns {
+		matched, err := path.Match(p, ref)
+		if err != nil {
+			return false, errors.Wrapf(err, "while matching ref pattern %q", p)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Repository gives access to objects of the receiving repository, including the quarantined ones
+// which are not visible outside the hook until the push is accepted.
+type Repository struct {
+	objectDirs []string
+}
+
+// NewRepositoryFromEnv returns the repository which receives the push, as described by the hook environment.
+// The gitDir is used if the GIT_DIR variable is not set. The git environment variables are removed from
+// the process environment afterwards, so they do not affect git commands executed later in checkouts.
+func NewRepositoryFromEnv(gitDir string) (*Repository, error) {
+	if dir := os.Getenv("GIT_DIR"); dir != "" {
+		gitDir = dir
+	}
+	gitDir, err := filepath.Abs(gitDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// quarantined objects first, then the already accepted ones
+	var objectDirs []string
+	if dir := os.Getenv("GIT_OBJECT_DIRECTORY"); dir != "" {
+		objectDirs = append(objectDirs, dir)
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("GIT_ALTERNATE_OBJECT_DIRECTORIES")) {
+		if dir != "" {
+			objectDirs = append(objectDirs, dir)
+		}
+	}
+	objectDirs = append(objectDirs, filepath.Join(gitDir, "objects"))
+
+	for idx, dir := range objectDirs {
+		if !filepath.IsAbs(dir) {
+			objectDirs[idx] = filepath.Join(gitDir, dir)
+		}
+	}
+
+	for _, name := range gitEnv {
+		if err := os.Unsetenv(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Repository{objectDirs: objectDirs}, nil
+}
+
+// Checkout creates a temporary repository which borrows objects from the receiving repository and checks out
+// a given revision, so checks can be executed against the pushed tree. The caller must call the returned cleanup function.
+func (r *Repository) Checkout(rev string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "codeowners-pre-receive-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	if err := run(dir, "init", "--quiet"); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	alternates := filepath.Join(dir, ".git", "objects", "info", "alternates")
+	if err := os.WriteFile(alternates, []byte(strings.Join(r.objectDirs, "\n")+"\n"), 0o600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	if err := run(dir, "-c", "advice.detachedHead=false", "checkout", "--quiet", "--detach", rev); err != nil {
+		cleanup()
+		return "", nil, errors.Wrapf(err, "while checking out %s", rev)
+	}
+
+	return dir, cleanup, nil
+}
+
+// CodeownersChanged returns true if the CODEOWNERS file differs between both revisions of the checkout.
+func CodeownersChanged(checkoutDir, oldRev, newRev string) (bool, error) {
+	args := append([]string{"diff", "--quiet", "--no-ext-diff", oldRev, newRev, "--"}, codeownersLocations...)
+	gitdiff := pipe.Script(
+		pipe.ChDir(checkoutDir),
+		pipe.Exec("git", args...),
+	)
+	_, stderr, err := pipe.DividedOutput(gitdiff)
+	switch {
+	case err == nil:
+		return false, nil
+	case len(stderr) == 0: // `git diff --quiet` exits with 1 if there are differences
+		return true, nil
+	default:
+		return false, errors.Wrap(err, string(stderr))
+	}
+}
+
+func run(dir string, args ...string) error {
+	gitcmd := pipe.Script(
+		pipe.ChDir(dir),
+		pipe.Exec("git", args...),
+	)
+	_, stderr, err := pipe.DividedOutput(gitcmd)
+	if err != nil {
+		return errors.Wrap(err, string(stderr))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package prereceive_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/prereceive"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUpdates(t *testing.T) {
+	tests := map[string]struct {
+		givenInput   string
+		expUpdates   []prereceive.Update
+		expErrString string
+	}{
+		"Multiple updates": {
+			givenInput: "1111111111111111111111111111111111111111 2222222222222222222222222222222222222222 refs/heads/main\n" +
+				"\n" +
+				prereceive.ZeroRev + " 3333333333333333333333333333333333333333 refs/tags/v1.0.0\n",
+			expUpdates: []prereceive.Update{
+				{OldRev: "1111111111111111111111111111111111111111", NewRev: "2222222222222222222222222222222222222222", Ref: "refs/heads/main"},
+				{OldRev: prereceive.ZeroRev, NewRev: "3333333333333333333333333333333333333333", Ref: "refs/tags/v1.0.0"},
+			},
+		},
+		"Empty input": {
+			givenInput: "",
+		},
+		"Malformed line": {
+			givenInput:   "1111111111111111111111111111111111111111 refs/heads/main\n",
+			expErrString: "line 1: expected '<old-rev> <new-rev> <ref>', got \"1111111111111111111111111111111111111111 refs/heads/main\"",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			updates, err := prereceive.ParseUpdates(strings.NewReader(tc.givenInput))
+
+			// then
+			if tc.expErrString != "" {
+				require.EqualError(t, err, tc.expErrString)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expUpdates, updates)
+		})
+	}
+}
+
+func TestUpdateKind(t *testing.T) {
+	// given
+	created := prereceive.Update{OldRev: prereceive.ZeroRev, NewRev: "1111111111111111111111111111111111111111"}
+	deleted := prereceive.Update{OldRev: "1111111111111111111111111111111111111111", NewRev: prereceive.ZeroRev}
+
+	// then
+	assert.True(t, created.IsCreate())
+	assert.False(t, created.IsDelete())
+	assert.True(t, deleted.IsDelete())
+	assert.False(t, deleted.IsCreate())
+}
+
+func TestMatchRef(t *testing.T) {
+	tests := map[string]struct {
+		givenRef      string
+		givenPatterns []string
+		expMatched    bool
+	}{
+		"Matches branch": {
+			givenRef:      "refs/heads/main",
+			givenPatterns: []string{"refs/tags/*", "refs/heads/*"},
+			expMatched:    true,
+		},
+		"Does not match nested branch": {
+			givenRef:      "refs/heads/feature/abc",
+			givenPatterns: []string{"refs/heads/*"},
+			expMatched:    false,
+		},
+		"Does not match tag": {
+			givenRef:      "refs/tags/v1.0.0",
+			givenPatterns: []string{"refs/heads/*"},
+			expMatched:    false,
+		},
+		"Empty patterns match all refs": {
+			givenRef:   "refs/tags/v1.0.0",
+			expMatched: true,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			matched, err := prereceive.MatchRef(tc.givenRef, tc.givenPatterns)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expMatched, matched)
+		})
+	}
+}
+
+func TestRepositoryCheckout(t *testing.T) {
+	// given
+	root := t.TempDir()
+	serverDir := filepath.Join(root, "server.git")
+	workDir := filepath.Join(root, "work")
+	gitrepo.Run(t, root, "init", "--quiet", "--bare", serverDir)
+	require.NoError(t, os.Mkdir(workDir, 0o755))
+	gitrepo.Init(t, workDir)
+
+	gitrepo.CommitFiles(t, workDir, map[string]string{"CODEOWNERS": "* @org/team\n"})
+	oldRev := gitrepo.Run(t, workDir, "rev-parse", "HEAD")
+	gitrepo.CommitFiles(t, workDir, map[string]string{"README.md": "docs\n"})
+	readmeRev := gitrepo.Run(t, workDir, "rev-parse", "HEAD")
+	gitrepo.CommitFiles(t, workDir, map[string]string{"CODEOWNERS": "* @org/team\n/docs/ @org/docs\n"})
+	newRev := gitrepo.Run(t, workDir, "rev-parse", "HEAD")
+	gitrepo.Run(t, workDir, "push", "--quiet", serverDir, "HEAD:refs/heads/main")
+
+	repo, err := prereceive.NewRepositoryFromEnv(serverDir)
+	require.NoError(t, err)
+
+	// when
+	dir, cleanup, err := repo.Checkout(newRev)
+	require.NoError(t, err)
+	defer cleanup()
+
+	// then
+	content, err := os.ReadFile(filepath.Join(dir, "CODEOWNERS"))
+	require.NoError(t, err)
+	assert.Equal(t, "* @org/team\n/docs/ @org/docs\n", string(content))
+
+	changed, err := prereceive.CodeownersChanged(dir, oldRev, readmeRev)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = prereceive.CodeownersChanged(dir, readmeRev, newRev)
+	require.NoError(t, err)
+	assert.True(t, changed)
+}
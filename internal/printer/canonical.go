@@ -0,0 +1,103 @@
+package printer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// CanonicalJSONPrinter prints the report of the JSONPrinter in the canonical form, followed by its SHA-256 hash,
+// so downstream systems can verify the report integrity and deduplicate identical results, e.g. of retried CI jobs.
+//
+// The same results are always printed as the same bytes: object keys are sorted, there is no insignificant
+// whitespace, HTML characters are not escaped, durations are omitted, and issues are sorted by the check,
+// line, column, severity, and message. The report is terminated by a newline, which is covered by the hash,
+// so the hash is the same as the one computed by `sha256sum` for the saved report.
+type CanonicalJSONPrinter struct {
+	resultCollector
+	w     io.Writer
+	hashW io.Writer
+}
+
+// NewCanonicalJSONPrinter returns new instance of the CanonicalJSONPrinter. The report is written to w, and the hash
+// to hashW, so it doesn't change the report. Entries are used to resolve patterns of reported lines.
+func NewCanonicalJSONPrinter(w, hashW io.Writer, entries []codeowners.Entry) *CanonicalJSONPrinter {
+	return &CanonicalJSONPrinter{resultCollector: newResultCollector(entries), w: w, hashW: hashW}
+}
+
+func (p *CanonicalJSONPrinter) PrintSummary(allCheck, failedChecks int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	report := p.report(allCheck, failedChecks)
+	for i := range report.Checks {
+		report.Checks[i].DurationMS = nil
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return issueLess(report.Issues[i], report.Issues[j])
+	})
+
+	raw, err := CanonicalJSON(report)
+	if err != nil {
+		fmt.Fprintf(p.hashW, "Cannot print the canonical report: %v\n", err)
+		return
+	}
+	raw = append(raw, '\n')
+
+	// the output is best-effort, a broken pipe must not affect the validation
+	_, _ = p.w.Write(raw)
+	fmt.Fprintf(p.hashW, "Report SHA-256: %x\n", sha256.Sum256(raw))
+}
+
+// CanonicalJSON returns the JSON encoding of v with object keys sorted, without insignificant whitespace,
+// and without escaping HTML characters. Numbers are kept as they are encoded by the encoding/json package.
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// maps are encoded with sorted keys, so decoding the document into generic values sorts keys of all objects
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(tree); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func issueLess(a, b IssueReport) bool {
+	if a.Check != b.Check {
+		return a.Check < b.Check
+	}
+	if la, lb := derefOrZero(a.Line), derefOrZero(b.Line); la != lb {
+		return la < lb
+	}
+	if ca, cb := derefOrZero(a.Column), derefOrZero(b.Column); ca != cb {
+		return ca < cb
+	}
+	if a.Severity != b.Severity {
+		return a.Severity < b.Severity
+	}
+	return a.Message < b.Message
+}
+
+func derefOrZero(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,119 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJSONPrinter(t *testing.T) {
+	t.Run("Should print sorted report without durations and its hash", func(t *testing.T) {
+		// given
+		report, hash := &bytes.Buffer{}, &bytes.Buffer{}
+		p := NewCanonicalJSONPrinter(report, hash, reportEntries)
+
+		// when
+		printReportResults(p)
+
+		// then
+		exp := `{"checks":[{"error":"some check internal error","issues":0,"name":"Bar Checker"},` +
+			`{"issues":1,"name":"Baz Checker"},{"issues":2,"name":"[Experimental] Foo Checker"}],` +
+			`"issues":[{"check":"Baz Checker","line":2,"message":"Simulate warning in line 2","pattern":"*","severity":"warning"},` +
+			`{"check":"[Experimental] Foo Checker","message":"Warning without line number","severity":"warning"},` +
+			`{"check":"[Experimental] Foo Checker","column":8,"line":42,"message":"Simulate error in line 42","pattern":"/docs/","severity":"error"}],` +
+			`"summary":{"checks":3,"failed":3}}` + "\n"
+		assert.Equal(t, exp, report.String())
+		assert.Equal(t, fmt.Sprintf("Report SHA-256: %x\n", sha256.Sum256([]byte(exp))), hash.String())
+	})
+
+	t.Run("Should print the same bytes regardless of the order and duration of checks", func(t *testing.T) {
+		// given
+		first, second := &bytes.Buffer{}, &bytes.Buffer{}
+		p1 := NewCanonicalJSONPrinter(first, &bytes.Buffer{}, reportEntries)
+		p2 := NewCanonicalJSONPrinter(second, &bytes.Buffer{}, reportEntries)
+		issues := []api.Issue{
+			{Severity: api.Warning, LineNo: ptr.Uint64Ptr(2), Message: "b"},
+			{Severity: api.Warning, LineNo: ptr.Uint64Ptr(2), Message: "a"},
+		}
+
+		// when
+		p1.PrintCheckResult("Foo Checker", time.Second, api.Output{Issues: issues}, nil)
+		p1.PrintCheckResult("Bar Checker", time.Millisecond, api.Output{}, nil)
+		p1.PrintSummary(2, 1)
+
+		p2.PrintCheckResult("Bar Checker", 3*time.Second, api.Output{}, nil)
+		p2.PrintCheckResult("Foo Checker", time.Minute, api.Output{Issues: []api.Issue{issues[1], issues[0]}}, nil)
+		p2.PrintSummary(2, 1)
+
+		// then
+		assert.Equal(t, first.String(), second.String())
+	})
+}
+
+func TestCanonicalJSONPrinterReproducibleChecks(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	gitrepo.CommitFiles(t, repoDir, map[string]string{
+		"CODEOWNERS":  "* @org/all\n**/*.go @org/go\n/docs/ @org/docs\n/src/**/app/**/* @org/app\n",
+		"src/main.go": "package main",
+		"README.md":   "# Readme",
+	})
+	entries, err := codeowners.NewFromPath(repoDir)
+	require.NoError(t, err)
+	in := api.Input{RepoDir: repoDir, CodeownersEntries: entries}
+
+	checks := []api.Checker{
+		// skip patterns are kept in a map, so their order would be random without sorting
+		check.NewNotOwnedFile(check.NotOwnedFileConfig{SkipPatterns: []string{"*", "**/*.go", "/docs/", "/src/**/app/**/*"}}),
+		check.NewExpensivePattern(),
+	}
+
+	render := func() (string, string) {
+		report, hash := &bytes.Buffer{}, &bytes.Buffer{}
+		p := NewCanonicalJSONPrinter(report, hash, entries)
+		for _, c := range checks {
+			out, err := c.Check(context.Background(), in)
+			require.NoError(t, err)
+			p.PrintCheckResult(c.Name(), time.Duration(rand.Int63()), out, nil)
+		}
+		p.PrintSummary(len(checks), len(checks))
+		return report.String(), hash.String()
+	}
+
+	// when
+	firstReport, firstHash := render()
+	secondReport, secondHash := render()
+
+	// then
+	assert.Contains(t, firstReport, `skipped patterns: \"*,**/*.go,/docs/,/src/**/app/**/*\"`)
+	assert.Equal(t, firstReport, secondReport)
+	assert.Equal(t, firstHash, secondHash)
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	// given
+	in := map[string]interface{}{
+		"z": []interface{}{map[string]interface{}{"b": 1, "a": "<&>"}},
+		"a": uint64(12345678901234567890),
+	}
+
+	// when
+	out, err := CanonicalJSON(in)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":12345678901234567890,"z":[{"a":"<&>","b":1}]}`, string(out))
+}
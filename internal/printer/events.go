@@ -0,0 +1,143 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// Event types emitted by the EventPrinter.
+const (
+	EventStarted  = "started"
+	EventIssue    = "issue"
+	EventFinished = "finished"
+	EventStats    = "stats"
+	EventSummary  = "summary"
+)
+
+// Event is a single line of the NDJSON event stream. Only fields relevant to a given event type are set.
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// Check is the name of the check, empty for the summary event.
+	Check string `json:"check,omitempty"`
+
+	// issue event
+	Severity string  `json:"severity,omitempty"`
+	Line     *uint64 `json:"line,omitempty"`
+	Column   *uint64 `json:"column,omitempty"`
+	Message  string  `json:"message,omitempty"`
+
+	// finished and stats events
+	DurationMS *float64 `json:"durationMs,omitempty"`
+	Issues     *int     `json:"issues,omitempty"`
+	Error      string   `json:"error,omitempty"`
+
+	// stats event
+	APICalls     *int     `json:"apiCalls,omitempty"`
+	CacheHits    *int     `json:"cacheHits,omitempty"`
+	CacheMisses  *int     `json:"cacheMisses,omitempty"`
+	CacheHitRate *float64 `json:"cacheHitRate,omitempty"`
+
+	// summary event
+	Checks *int `json:"checks,omitempty"`
+	Failed *int `json:"failed,omitempty"`
+}
+
+// EventPrinter emits machine-readable progress events as newline-delimited JSON, one event per line,
+// so wrapping tools can render live progress and attribute time to each check.
+type EventPrinter struct {
+	m   sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewEventPrinter returns new instance of the EventPrinter which writes events to a given writer.
+func NewEventPrinter(w io.Writer) *EventPrinter {
+	return &EventPrinter{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// PrintCheckRunning emits the started event. It should be called when the check execution starts.
+func (p *EventPrinter) PrintCheckRunning(checkName string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.emit(Event{Type: EventStarted, Check: checkName})
+}
+
+// PrintCheckResult emits an issue event for each reported issue, followed by the finished event.
+func (p *EventPrinter) PrintCheckResult(checkName string, duration time.Duration, checkOut api.Output, checkErr error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, i := range checkOut.Issues {
+		p.emit(Event{
+			Type:     EventIssue,
+			Check:    checkName,
+			Severity: strings.ToLower(i.Severity.String()),
+			Line:     i.LineNo,
+			Column:   i.Column,
+			Message:  i.Message,
+		})
+	}
+
+	durationMS, issues := float64(duration)/float64(time.Millisecond), len(checkOut.Issues)
+	finished := Event{
+		Type:       EventFinished,
+		Check:      checkName,
+		DurationMS: &durationMS,
+		Issues:     &issues,
+	}
+	if checkErr != nil {
+		finished.Error = redact.String(checkErr.Error())
+	}
+	p.emit(finished)
+}
+
+// PrintStats emits the stats event with the wall time, external API calls, and cache lookups of each check,
+// so CI can track the performance of the validation over time. The cache hit rate is omitted if the cache
+// wasn't used by the check.
+func (p *EventPrinter) PrintStats(stats []usage.CheckStats) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, s := range stats {
+		s := s
+		durationMS := float64(s.Duration) / float64(time.Millisecond)
+		e := Event{
+			Type:        EventStats,
+			Check:       s.Check,
+			DurationMS:  &durationMS,
+			APICalls:    &s.APICalls,
+			CacheHits:   &s.CacheHits,
+			CacheMisses: &s.CacheMisses,
+		}
+		if rate := s.CacheHitRate(); rate >= 0 {
+			e.CacheHitRate = &rate
+		}
+		p.emit(e)
+	}
+}
+
+// PrintSummary emits the summary event.
+func (p *EventPrinter) PrintSummary(allCheck, failedChecks int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.emit(Event{Type: EventSummary, Checks: &allCheck, Failed: &failedChecks})
+}
+
+func (p *EventPrinter) emit(e Event) {
+	e.Time = p.now().UTC()
+	// events are best-effort, a broken stream must not affect the validation
+	_ = p.enc.Encode(e)
+}
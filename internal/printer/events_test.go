@@ -0,0 +1,48 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestEventPrinter(t *testing.T) {
+	// given
+	buff := &bytes.Buffer{}
+	p := NewEventPrinter(buff)
+	p.now = func() time.Time { return time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC) }
+
+	// when
+	p.PrintCheckRunning("Foo Checker")
+	p.PrintCheckRunning("Bar Checker")
+	p.PrintCheckResult("Bar Checker", 1500*time.Millisecond, api.Output{}, errors.New("some check internal error"))
+	p.PrintCheckResult("Foo Checker", 2*time.Second, api.Output{
+		Issues: []api.Issue{
+			{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(42),
+				Message:  "Simulate error in line 42",
+			},
+			{
+				Severity: api.Warning,
+				Message:  "Warning without line number",
+			},
+		},
+	}, nil)
+	p.PrintStats([]usage.CheckStats{
+		{Check: "Foo Checker", Duration: 2 * time.Second, APICalls: 3, CacheHits: 1, CacheMisses: 3},
+		{Check: "Bar Checker", Duration: 1500 * time.Millisecond},
+	})
+	p.PrintSummary(2, 2)
+
+	// then
+	g := goldie.New(t, goldie.WithNameSuffix(".golden.txt"))
+	g.Assert(t, t.Name(), buff.Bytes())
+}
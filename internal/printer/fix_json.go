@@ -0,0 +1,101 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// FixJSONPrinter collects issues that have suggested fixes and prints them as a single JSON array
+// once all checks are executed. It allows editors and scripts to apply chosen fixes in batch.
+type FixJSONPrinter struct {
+	m      sync.Mutex
+	issues []FixableIssue
+}
+
+// FixableIssue represents an issue with suggested fixes in the JSON output.
+type FixableIssue struct {
+	Check    string    `json:"check"`
+	Severity string    `json:"severity"`
+	Line     *uint64   `json:"line,omitempty"`
+	Column   *uint64   `json:"column,omitempty"`
+	Message  string    `json:"message"`
+	Fixes    []FixJSON `json:"fixes"`
+}
+
+// FixJSON represents a single suggested fix in the JSON output.
+type FixJSON struct {
+	Description string         `json:"description"`
+	Edits       []LineEditJSON `json:"edits"`
+}
+
+// LineEditJSON represents a single line edit in the JSON output.
+type LineEditJSON struct {
+	Line    uint64 `json:"line"`
+	NewText string `json:"newText,omitempty"`
+	Delete  bool   `json:"delete,omitempty"`
+	Insert  bool   `json:"insert,omitempty"`
+}
+
+func (p *FixJSONPrinter) PrintCheckResult(checkName string, _ time.Duration, checkOut api.Output, _ error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, i := range checkOut.Issues {
+		if len(i.Fixes) == 0 {
+			continue
+		}
+
+		issue := FixableIssue{
+			Check:    checkName,
+			Severity: i.Severity.String(),
+			Line:     i.LineNo,
+			Column:   i.Column,
+			Message:  i.Message,
+		}
+		for _, f := range i.Fixes {
+			fix := FixJSON{Description: f.Description}
+			for _, e := range f.Edits {
+				fix.Edits = append(fix.Edits, LineEditJSON{Line: e.LineNo, NewText: e.NewText, Delete: e.Delete, Insert: e.Insert})
+			}
+			issue.Fixes = append(issue.Fixes, fix)
+		}
+		p.issues = append(p.issues, issue)
+	}
+}
+
+func (p *FixJSONPrinter) PrintSummary(_, _ int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// checks are executed in parallel, sort to have a stable output
+	sort.SliceStable(p.issues, func(i, j int) bool {
+		if p.issues[i].Check != p.issues[j].Check {
+			return p.issues[i].Check < p.issues[j].Check
+		}
+		return lineOf(p.issues[i]) < lineOf(p.issues[j])
+	})
+
+	out := p.issues
+	if out == nil {
+		out = []FixableIssue{}
+	}
+
+	raw, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		fmt.Fprintf(writer, "while marshaling fixes: %v\n", err)
+		return
+	}
+	fmt.Fprintln(writer, string(raw))
+}
+
+func lineOf(i FixableIssue) uint64 {
+	if i.Line == nil {
+		return 0
+	}
+	return *i.Line
+}
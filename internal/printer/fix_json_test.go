@@ -0,0 +1,80 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestFixJSONPrinter(t *testing.T) {
+	t.Run("Should print only issues with fixes", func(t *testing.T) {
+		// given
+		p := FixJSONPrinter{}
+
+		buff := &bytes.Buffer{}
+		restore := overrideWriter(buff)
+		defer restore()
+
+		// when
+		p.PrintCheckResult("Foo Checker", time.Second, api.Output{
+			Issues: []api.Issue{
+				{
+					Severity: api.Warning,
+					LineNo:   ptr.Uint64Ptr(4),
+					Message:  "Owner casing differs",
+					Fixes: []api.Fix{
+						{
+							Description: "Correct owner casing",
+							Edits:       []api.LineEdit{{LineNo: 4, NewText: "* @Alice"}},
+						},
+					},
+				},
+				{
+					Severity: api.Error,
+					Message:  "Issue without fix",
+				},
+			},
+		}, nil)
+		p.PrintCheckResult("Bar Checker", time.Second, api.Output{
+			Issues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(2),
+					Message:  "Pattern is duplicated",
+					Fixes: []api.Fix{
+						{
+							Description: "Remove duplicated lines",
+							Edits:       []api.LineEdit{{LineNo: 2, Delete: true}},
+						},
+					},
+				},
+			},
+		}, nil)
+		p.PrintSummary(2, 2)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.json"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+
+	t.Run("Should print empty array when there are no fixes", func(t *testing.T) {
+		// given
+		p := FixJSONPrinter{}
+
+		buff := &bytes.Buffer{}
+		restore := overrideWriter(buff)
+		defer restore()
+
+		// when
+		p.PrintSummary(1, 0)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.json"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+}
@@ -0,0 +1,164 @@
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// Printer prints the checks results.
+type Printer interface {
+	PrintCheckResult(checkName string, duration time.Duration, checkOut api.Output, err error)
+	PrintSummary(allCheck int, failedChecks int)
+}
+
+// GroupingPrinter collapses issues reported by multiple checks for the same CODEOWNERS line into a single issue,
+// which lists messages of all checks, to reduce noise, e.g. in pull request comments. The grouped issue is reported
+// by the first check which reported an issue for the line. Results are forwarded to the wrapped printer once all
+// checks are executed.
+type GroupingPrinter struct {
+	m       sync.Mutex
+	inner   Printer
+	results []checkResult
+	stats   []usage.CheckStats
+}
+
+type checkResult struct {
+	name     string
+	duration time.Duration
+	out      api.Output
+	err      error
+}
+
+// NewGroupingPrinter returns a GroupingPrinter which forwards grouped results to a given printer.
+func NewGroupingPrinter(inner Printer) *GroupingPrinter {
+	return &GroupingPrinter{inner: inner}
+}
+
+// PrintCheckStarted forwards the event, if the wrapped printer renders the progress of running checks.
+func (p *GroupingPrinter) PrintCheckStarted(checkName string) {
+	if progress, ok := p.inner.(interface{ PrintCheckStarted(string) }); ok {
+		progress.PrintCheckStarted(checkName)
+	}
+}
+
+func (p *GroupingPrinter) PrintCheckResult(checkName string, duration time.Duration, checkOut api.Output, err error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.results = append(p.results, checkResult{name: checkName, duration: duration, out: checkOut, err: err})
+}
+
+// PrintStats stores the usage of checks, which is forwarded after the grouped results, if the wrapped
+// printer reports it.
+func (p *GroupingPrinter) PrintStats(stats []usage.CheckStats) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.stats = stats
+}
+
+func (p *GroupingPrinter) PrintSummary(allCheck, failedChecks int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// checks are executed in parallel, sort to have a stable output
+	sort.SliceStable(p.results, func(i, j int) bool {
+		return p.results[i].name < p.results[j].name
+	})
+
+	for _, r := range groupIssues(p.results) {
+		p.inner.PrintCheckResult(r.name, r.duration, r.out, r.err)
+	}
+	if stats, ok := p.inner.(interface{ PrintStats([]usage.CheckStats) }); ok && p.stats != nil {
+		stats.PrintStats(p.stats)
+	}
+	p.inner.PrintSummary(allCheck, failedChecks)
+}
+
+// groupIssues moves issues of lines reported by more than one check to the first of them.
+func groupIssues(results []checkResult) []checkResult {
+	byLine := map[uint64][]reportedIssue{}
+	for _, r := range results {
+		for _, i := range r.out.Issues {
+			if i.LineNo != nil {
+				byLine[*i.LineNo] = append(byLine[*i.LineNo], reportedIssue{check: r.name, issue: i})
+			}
+		}
+	}
+
+	grouped := map[uint64]bool{}
+	for line, issues := range byLine {
+		for _, i := range issues[1:] {
+			if i.check != issues[0].check {
+				grouped[line] = true
+				break
+			}
+		}
+	}
+
+	emitted := map[uint64]bool{}
+	out := make([]checkResult, 0, len(results))
+	for _, r := range results {
+		var (
+			issues []api.Issue
+			moved  = api.Issue{Severity: api.Warning}
+			cnt    int
+		)
+		for _, i := range r.out.Issues {
+			switch {
+			case i.LineNo == nil || !grouped[*i.LineNo]:
+				issues = append(issues, i)
+			case !emitted[*i.LineNo]:
+				issues = append(issues, mergeIssues(*i.LineNo, byLine[*i.LineNo]))
+				emitted[*i.LineNo] = true
+			case byLine[*i.LineNo][0].check != r.name:
+				cnt++
+				if i.Severity < moved.Severity {
+					moved.Severity = i.Severity
+				}
+			}
+		}
+		// the check is still reported as failed, so the moved issues are not silently dropped
+		if cnt > 0 {
+			moved.Message = fmt.Sprintf("%d issue(s) grouped with issues of other checks for the same lines", cnt)
+			issues = append(issues, moved)
+		}
+		r.out.Issues = issues
+		out = append(out, r)
+	}
+	return out
+}
+
+type reportedIssue struct {
+	check string
+	issue api.Issue
+}
+
+// mergeIssues returns a single issue with messages and fixes of all given issues, and the highest severity.
+func mergeIssues(lineNo uint64, issues []reportedIssue) api.Issue {
+	merged := api.Issue{Severity: api.Warning, LineNo: &lineNo}
+
+	var (
+		checks []string
+		points []string
+	)
+	for _, rep := range issues {
+		if rep.issue.Severity < merged.Severity {
+			merged.Severity = rep.issue.Severity
+		}
+		merged.Fixes = append(merged.Fixes, rep.issue.Fixes...)
+		if len(checks) == 0 || checks[len(checks)-1] != rep.check {
+			checks = append(checks, rep.check)
+		}
+		points = append(points, fmt.Sprintf("            * [%s] %s", rep.check, rep.issue.Message))
+	}
+
+	merged.Message = fmt.Sprintf("%d issues reported by %s:\n%s", len(points), strings.Join(checks, ", "), strings.Join(points, "\n"))
+	return merged
+}
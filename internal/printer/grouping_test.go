@@ -0,0 +1,53 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestGroupingPrinter(t *testing.T) {
+	// given
+	p := NewGroupingPrinter(&TTYPrinter{})
+
+	buff := &bytes.Buffer{}
+	restore := overrideWriter(buff)
+	defer restore()
+
+	// when
+	p.PrintCheckResult("Syntax Checker", time.Second, api.Output{
+		Issues: []api.Issue{
+			{Severity: api.Warning, LineNo: ptr.Uint64Ptr(2), Message: "Owner '@team!x' does not look like a GitHub username"},
+			{Severity: api.Warning, LineNo: ptr.Uint64Ptr(5), Message: "Reported only by the syntax checker"},
+		},
+	}, nil)
+	p.PrintCheckResult("Casing Checker", time.Second, api.Output{
+		Issues: []api.Issue{
+			{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(2),
+				Message:  "Owner \"@team!x\" is written as \"@Team!x\" in line 1",
+				Fixes: []api.Fix{
+					{Description: "Correct owner casing", Edits: []api.LineEdit{{LineNo: 2, NewText: "/a/ @Team!x"}}},
+				},
+			},
+		},
+	}, nil)
+	p.PrintCheckResult("Files Checker", time.Second, api.Output{
+		Issues: []api.Issue{
+			{Severity: api.Error, Message: "Issue without line"},
+		},
+	}, nil)
+	p.PrintCheckResult("Broken Checker", time.Second, api.Output{}, errors.New("boom"))
+	p.PrintSummary(4, 4)
+
+	// then
+	g := goldie.New(t, goldie.WithNameSuffix(".golden.txt"))
+	g.Assert(t, t.Name(), buff.Bytes())
+}
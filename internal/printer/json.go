@@ -0,0 +1,142 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// Report is the result of the validation printed by the JSONPrinter.
+type Report struct {
+	Checks  []CheckReport `json:"checks"`
+	Issues  []IssueReport `json:"issues"`
+	Summary SummaryReport `json:"summary"`
+}
+
+// CheckReport describes a single executed check.
+type CheckReport struct {
+	Name string `json:"name"`
+	// DurationMS is omitted in the canonical report, as it differs between runs.
+	DurationMS *float64 `json:"durationMs,omitempty"`
+	Issues     int      `json:"issues"`
+	// Error is set if the check could not be completed.
+	Error string `json:"error,omitempty"`
+}
+
+// IssueReport is a single issue with structured fields, so it can be consumed by CI dashboards.
+type IssueReport struct {
+	Check    string  `json:"check"`
+	Severity string  `json:"severity"`
+	Line     *uint64 `json:"line,omitempty"`
+	Column   *uint64 `json:"column,omitempty"`
+	// Pattern is the pattern of the CODEOWNERS entry in the issue line, empty if the issue doesn't concern an entry.
+	Pattern string `json:"pattern,omitempty"`
+	Message string `json:"message"`
+}
+
+// SummaryReport holds the number of executed and failed checks.
+type SummaryReport struct {
+	Checks int `json:"checks"`
+	Failed int `json:"failed"`
+}
+
+// resultCollector collects results of checks executed in parallel, so structured printers can print
+// them as a single document once all checks are executed.
+type resultCollector struct {
+	m        sync.Mutex
+	results  []checkResult
+	patterns map[uint64]string
+}
+
+func newResultCollector(entries []codeowners.Entry) resultCollector {
+	patterns := map[uint64]string{}
+	for _, e := range entries {
+		patterns[e.LineNo] = e.Pattern
+	}
+	return resultCollector{patterns: patterns}
+}
+
+func (c *resultCollector) PrintCheckResult(checkName string, duration time.Duration, checkOut api.Output, err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.results = append(c.results, checkResult{name: checkName, duration: duration, out: checkOut, err: err})
+}
+
+// sorted returns collected results sorted by the check name, as checks are executed in parallel.
+func (c *resultCollector) sorted() []checkResult {
+	sort.SliceStable(c.results, func(i, j int) bool {
+		return c.results[i].name < c.results[j].name
+	})
+	return c.results
+}
+
+func (c *resultCollector) issueReport(check string, i api.Issue) IssueReport {
+	out := IssueReport{
+		Check:    check,
+		Severity: strings.ToLower(i.Severity.String()),
+		Line:     i.LineNo,
+		Column:   i.Column,
+		Message:  i.Message,
+	}
+	if i.LineNo != nil {
+		out.Pattern = c.patterns[*i.LineNo]
+	}
+	return out
+}
+
+// JSONPrinter prints results of all checks as a single JSON document once all checks are executed.
+type JSONPrinter struct {
+	resultCollector
+	w io.Writer
+}
+
+// NewJSONPrinter returns new instance of the JSONPrinter. Entries are used to resolve patterns of reported lines.
+func NewJSONPrinter(w io.Writer, entries []codeowners.Entry) *JSONPrinter {
+	return &JSONPrinter{resultCollector: newResultCollector(entries), w: w}
+}
+
+// report returns the report of collected results. The caller must hold the lock.
+func (c *resultCollector) report(allCheck, failedChecks int) Report {
+	out := Report{
+		Checks:  []CheckReport{},
+		Issues:  []IssueReport{},
+		Summary: SummaryReport{Checks: allCheck, Failed: failedChecks},
+	}
+	for _, r := range c.sorted() {
+		durationMS := float64(r.duration) / float64(time.Millisecond)
+		check := CheckReport{
+			Name:       r.name,
+			DurationMS: &durationMS,
+			Issues:     len(r.out.Issues),
+		}
+		if r.err != nil {
+			check.Error = redact.String(r.err.Error())
+		}
+		out.Checks = append(out.Checks, check)
+
+		for _, i := range r.out.Issues {
+			out.Issues = append(out.Issues, c.issueReport(r.name, i))
+		}
+	}
+	return out
+}
+
+func (p *JSONPrinter) PrintSummary(allCheck, failedChecks int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	report := p.report(allCheck, failedChecks)
+
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	// the output is best-effort, a broken pipe must not affect the validation
+	_ = enc.Encode(report)
+}
@@ -0,0 +1,70 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+var reportEntries = []codeowners.Entry{
+	{LineNo: 2, Pattern: "*", Owners: []string{"@org/platform"}},
+	{LineNo: 42, Pattern: "/docs/", Owners: []string{"@org/docs"}},
+}
+
+// printReportResults prints results of checks executed in parallel, which are reported out of order.
+func printReportResults(p Printer) {
+	p.PrintCheckResult("[Experimental] Foo Checker", 2*time.Second, api.Output{
+		Issues: []api.Issue{
+			{
+				Severity: api.Error,
+				LineNo:   ptr.Uint64Ptr(42),
+				Column:   ptr.Uint64Ptr(8),
+				Message:  "Simulate error in line 42",
+			},
+			{
+				Severity: api.Warning,
+				Message:  "Warning without line number",
+			},
+		},
+	}, nil)
+	p.PrintCheckResult("Bar Checker", 1500*time.Millisecond, api.Output{}, errors.New("some check internal error"))
+	p.PrintCheckResult("Baz Checker", time.Millisecond, api.Output{
+		Issues: []api.Issue{{Severity: api.Warning, LineNo: ptr.Uint64Ptr(2), Message: "Simulate warning in line 2"}},
+	}, nil)
+	p.PrintSummary(3, 3)
+}
+
+func TestJSONPrinter(t *testing.T) {
+	t.Run("Should print issues with patterns of reported lines", func(t *testing.T) {
+		// given
+		buff := &bytes.Buffer{}
+		p := NewJSONPrinter(buff, reportEntries)
+
+		// when
+		printReportResults(p)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.json"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+
+	t.Run("Should print empty arrays when no checks were executed", func(t *testing.T) {
+		// given
+		buff := &bytes.Buffer{}
+		p := NewJSONPrinter(buff, nil)
+
+		// when
+		p.PrintSummary(0, 0)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.json"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+}
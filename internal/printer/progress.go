@@ -0,0 +1,128 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// ProgressPrinter buffers the output of each check and flushes all of them in the order in which
+// checks were started, so the output of checks executed in parallel does not interleave.
+// If the output is a terminal, a live status line is rendered for each check while they are running.
+type ProgressPrinter struct {
+	m        sync.Mutex
+	tty      TTYPrinter
+	live     bool
+	order    []string
+	checks   map[string]*checkProgress
+	stats    []usage.CheckStats
+	rendered int
+}
+
+type checkProgress struct {
+	done     bool
+	failed   bool
+	duration time.Duration
+	out      bytes.Buffer
+}
+
+// NewProgressPrinter returns new instance of the ProgressPrinter.
+// Live status lines are rendered only if the output is a terminal.
+func NewProgressPrinter() *ProgressPrinter {
+	f, isFile := writer.(*os.File)
+	return &ProgressPrinter{
+		live:   isFile && isatty.IsTerminal(f.Fd()),
+		checks: map[string]*checkProgress{},
+	}
+}
+
+// PrintCheckStarted registers the check. The output of checks is flushed in the registration order.
+func (p *ProgressPrinter) PrintCheckStarted(checkName string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.register(checkName)
+	p.render()
+}
+
+func (p *ProgressPrinter) PrintCheckResult(checkName string, duration time.Duration, checkOut api.Output, checkErr error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	progress := p.register(checkName)
+	progress.done = true
+	progress.failed = checkErr != nil || len(checkOut.Issues) > 0
+	progress.duration = duration
+	p.tty.printCheckResult(&progress.out, checkName, duration, checkOut, checkErr)
+
+	p.render()
+}
+
+// PrintStats stores the usage of checks, which is printed after the results of all checks.
+func (p *ProgressPrinter) PrintStats(stats []usage.CheckStats) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.stats = stats
+}
+
+func (p *ProgressPrinter) PrintSummary(allCheck, failedChecks int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.clear()
+	for _, name := range p.order {
+		_, _ = p.checks[name].out.WriteTo(writer)
+	}
+	printStats(writer, p.stats)
+
+	p.tty.PrintSummary(allCheck, failedChecks)
+}
+
+func (p *ProgressPrinter) register(checkName string) *checkProgress {
+	progress, found := p.checks[checkName]
+	if !found {
+		progress = &checkProgress{}
+		p.checks[checkName] = progress
+		p.order = append(p.order, checkName)
+	}
+	return progress
+}
+
+// render redraws status lines of all checks in place.
+func (p *ProgressPrinter) render() {
+	if !p.live {
+		return
+	}
+
+	p.clear()
+	for _, name := range p.order {
+		progress := p.checks[name]
+		switch {
+		case !progress.done:
+			fmt.Fprintf(writer, "=> [running] %s\n", name)
+		case progress.failed:
+			color.New(color.FgRed).Fprintf(writer, "=> [failed %v] %s\n", progress.duration.Round(time.Millisecond), name)
+		default:
+			color.New(color.FgGreen).Fprintf(writer, "=> [done %v] %s\n", progress.duration.Round(time.Millisecond), name)
+		}
+	}
+	p.rendered = len(p.order)
+}
+
+// clear removes rendered status lines.
+func (p *ProgressPrinter) clear() {
+	if !p.live || p.rendered == 0 {
+		return
+	}
+	// move the cursor up and erase everything below
+	fmt.Fprintf(writer, "\x1b[%dA\x1b[J", p.rendered)
+	p.rendered = 0
+}
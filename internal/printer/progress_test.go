@@ -0,0 +1,94 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/fatih/color"
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestProgressPrinter(t *testing.T) {
+	givenResults := func(p *ProgressPrinter) {
+		p.PrintCheckStarted("Foo Checker")
+		p.PrintCheckStarted("Bar Checker")
+
+		// checks finish in a different order than they were started
+		p.PrintCheckResult("Bar Checker", time.Second, api.Output{}, nil)
+		p.PrintCheckResult("Foo Checker", 2*time.Second, api.Output{
+			Issues: []api.Issue{
+				{
+					Severity: api.Error,
+					LineNo:   ptr.Uint64Ptr(42),
+					Message:  "Simulate error in line 42",
+				},
+			},
+		}, nil)
+		p.PrintSummary(2, 1)
+	}
+
+	t.Run("Should flush results in the order checks were started", func(t *testing.T) {
+		// given
+		buff := &bytes.Buffer{}
+		restore := overrideWriter(buff)
+		defer restore()
+
+		p := NewProgressPrinter()
+
+		// when
+		givenResults(p)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.txt"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+
+	t.Run("Should render live status lines on terminal", func(t *testing.T) {
+		// given
+		buff := &bytes.Buffer{}
+		restore := overrideWriter(buff)
+		defer restore()
+
+		noColor := color.NoColor
+		color.NoColor = true
+		defer func() { color.NoColor = noColor }()
+
+		p := NewProgressPrinter()
+		p.live = true
+
+		// when
+		givenResults(p)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.txt"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+	t.Run("Should print stats of checks after their results", func(t *testing.T) {
+		// given
+		buff := &bytes.Buffer{}
+		restore := overrideWriter(buff)
+		defer restore()
+
+		p := NewProgressPrinter()
+
+		// when
+		p.PrintCheckStarted("Foo Checker")
+		p.PrintCheckStarted("Bar Checker")
+		p.PrintCheckResult("Bar Checker", time.Second, api.Output{}, nil)
+		p.PrintCheckResult("Foo Checker", 2*time.Second, api.Output{}, nil)
+		p.PrintStats([]usage.CheckStats{
+			{Check: "Foo Checker", Duration: 2 * time.Second, APICalls: 3, CacheHits: 9, CacheMisses: 3},
+			{Check: "Bar Checker", Duration: time.Second},
+		})
+		p.PrintSummary(2, 0)
+
+		// then
+		g := goldie.New(t, goldie.WithNameSuffix(".golden.txt"))
+		g.Assert(t, t.Name(), buff.Bytes())
+	})
+}
@@ -0,0 +1,195 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"go.szostok.io/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "codeowners-validator"
+	toolURI      = "https://github.com/mszostok/codeowners"
+)
+
+// SARIF log subset used to report CODEOWNERS issues, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	SARIFLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []SARIFRun `json:"runs"`
+	}
+
+	SARIFRun struct {
+		Tool        SARIFTool         `json:"tool"`
+		Invocations []SARIFInvocation `json:"invocations"`
+		Results     []SARIFResult     `json:"results"`
+	}
+
+	SARIFTool struct {
+		Driver SARIFDriver `json:"driver"`
+	}
+
+	SARIFDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version,omitempty"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []SARIFRule `json:"rules"`
+	}
+
+	SARIFRule struct {
+		ID               string       `json:"id"`
+		Name             string       `json:"name"`
+		ShortDescription SARIFMessage `json:"shortDescription"`
+	}
+
+	SARIFInvocation struct {
+		ExecutionSuccessful        bool                `json:"executionSuccessful"`
+		ToolExecutionNotifications []SARIFNotification `json:"toolExecutionNotifications,omitempty"`
+	}
+
+	SARIFNotification struct {
+		Level   string       `json:"level"`
+		Message SARIFMessage `json:"message"`
+		// Descriptor references the rule of the check which failed.
+		Descriptor SARIFReference `json:"descriptor"`
+	}
+
+	SARIFReference struct {
+		ID    string `json:"id"`
+		Index int    `json:"index"`
+	}
+
+	SARIFResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   SARIFMessage    `json:"message"`
+		Locations []SARIFLocation `json:"locations"`
+		// Properties holds the pattern of the reported CODEOWNERS entry.
+		Properties map[string]string `json:"properties,omitempty"`
+	}
+
+	SARIFMessage struct {
+		Text string `json:"text"`
+	}
+
+	SARIFLocation struct {
+		PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+	}
+
+	SARIFPhysicalLocation struct {
+		ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+		// Region is nil if the issue concerns the whole file.
+		Region *SARIFRegion `json:"region,omitempty"`
+	}
+
+	SARIFArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	SARIFRegion struct {
+		StartLine   uint64  `json:"startLine"`
+		StartColumn *uint64 `json:"startColumn,omitempty"`
+	}
+)
+
+// SARIFPrinter prints results of all checks as a SARIF log once all checks are executed, so they can be
+// uploaded to GitHub code scanning. Each check is a rule, and issues are located in the CODEOWNERS file.
+type SARIFPrinter struct {
+	resultCollector
+	w           io.Writer
+	artifact    string
+	toolVersion string
+}
+
+// NewSARIFPrinter returns new instance of the SARIFPrinter. The artifact is the slash-separated path of the
+// CODEOWNERS file relative to the repository root, e.g. `.github/CODEOWNERS`.
+func NewSARIFPrinter(w io.Writer, entries []codeowners.Entry, artifact string) *SARIFPrinter {
+	return &SARIFPrinter{
+		resultCollector: newResultCollector(entries),
+		w:               w,
+		artifact:        artifact,
+		toolVersion:     version.Get().Version,
+	}
+}
+
+func (p *SARIFPrinter) PrintSummary(_, _ int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           toolName,
+			Version:        p.toolVersion,
+			InformationURI: toolURI,
+			Rules:          []SARIFRule{},
+		}},
+		Invocations: []SARIFInvocation{{ExecutionSuccessful: true}},
+		Results:     []SARIFResult{},
+	}
+
+	for idx, r := range p.sorted() {
+		rule := SARIFRule{ID: RuleID(r.name), Name: r.name, ShortDescription: SARIFMessage{Text: r.name}}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		if r.err != nil {
+			inv := &run.Invocations[0]
+			inv.ExecutionSuccessful = false
+			inv.ToolExecutionNotifications = append(inv.ToolExecutionNotifications, SARIFNotification{
+				Level:      "error",
+				Message:    SARIFMessage{Text: redact.String(r.err.Error())},
+				Descriptor: SARIFReference{ID: rule.ID, Index: idx},
+			})
+		}
+
+		for _, i := range r.out.Issues {
+			issue := p.issueReport(r.name, i)
+			result := SARIFResult{
+				RuleID:    rule.ID,
+				RuleIndex: idx,
+				Level:     sarifLevel(i.Severity),
+				Message:   SARIFMessage{Text: i.Message},
+				Locations: []SARIFLocation{{PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: p.artifact},
+				}}},
+			}
+			if i.LineNo != nil {
+				result.Locations[0].PhysicalLocation.Region = &SARIFRegion{StartLine: *i.LineNo, StartColumn: i.Column}
+			}
+			if issue.Pattern != "" {
+				result.Properties = map[string]string{"pattern": issue.Pattern}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	// the output is best-effort, a broken pipe must not affect the validation
+	_ = enc.Encode(SARIFLog{Version: sarifVersion, Schema: sarifSchema, Runs: []SARIFRun{run}})
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// RuleID returns the identifier of a check, e.g. `governance-checker` for the `[Experimental] Governance Checker`
+// check. The experimental prefix is dropped, so alerts are kept when the check graduates.
+func RuleID(checkName string) string {
+	name := strings.TrimPrefix(checkName, "[Experimental] ")
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func sarifLevel(s api.SeverityType) string {
+	if s == api.Warning {
+		return "warning"
+	}
+	return "error"
+}
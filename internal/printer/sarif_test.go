@@ -0,0 +1,43 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSARIFPrinter(t *testing.T) {
+	// given
+	buff := &bytes.Buffer{}
+	p := NewSARIFPrinter(buff, reportEntries, ".github/CODEOWNERS")
+	p.toolVersion = "v1.0.0"
+
+	// when
+	printReportResults(p)
+
+	// then
+	g := goldie.New(t, goldie.WithNameSuffix(".golden.json"))
+	g.Assert(t, t.Name(), buff.Bytes())
+}
+
+func TestRuleID(t *testing.T) {
+	tests := map[string]struct {
+		checkName string
+		expected  string
+	}{
+		"Stable check":       {checkName: "Valid Syntax Checker", expected: "valid-syntax-checker"},
+		"Experimental check": {checkName: "[Experimental] Ownership SLA Checker", expected: "ownership-sla-checker"},
+		"Punctuation":        {checkName: "GitHub CODEOWNERS Errors Checker (ref: main)", expected: "github-codeowners-errors-checker-ref-main"},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got := RuleID(tc.checkName)
+
+			// then
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
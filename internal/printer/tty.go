@@ -6,10 +6,13 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/fatih/color"
-	"go.szostok.io/codeowners/internal/api"
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
 )
 
 // writer used for test purpose
@@ -23,6 +26,10 @@ func (tty *TTYPrinter) PrintCheckResult(checkName string, duration time.Duration
 	tty.m.Lock()
 	defer tty.m.Unlock()
 
+	tty.printCheckResult(writer, checkName, duration, checkOut, checkErr)
+}
+
+func (tty *TTYPrinter) printCheckResult(writer io.Writer, checkName string, duration time.Duration, checkOut api.Output, checkErr error) {
 	header := color.New(color.Bold).FprintfFunc()
 	issueBody := color.New(color.FgWhite).FprintfFunc()
 	okCheck := color.New(color.FgGreen).FprintlnFunc()
@@ -33,7 +40,10 @@ func (tty *TTYPrinter) PrintCheckResult(checkName string, duration time.Duration
 		issueSeverity := tty.severityPrintfFunc(i.Severity)
 
 		issueSeverity(writer, "    [%s]", strings.ToLower(i.Severity.String()[:3]))
-		if i.LineNo != nil {
+		switch {
+		case i.LineNo != nil && i.Column != nil:
+			issueBody(writer, " line %d, column %d:", *i.LineNo, *i.Column)
+		case i.LineNo != nil:
 			issueBody(writer, " line %d:", *i.LineNo)
 		}
 		issueBody(writer, " %s\n", i.Message)
@@ -44,7 +54,10 @@ func (tty *TTYPrinter) PrintCheckResult(checkName string, duration time.Duration
 		okCheck(writer, "    Check OK")
 	case checkErr != nil:
 		errCheck(writer, "    [Internal Error]")
-		issueBody(writer, " %s\n", checkErr)
+		issueBody(writer, " %s\n", redact.String(checkErr.Error()))
+		if hint := api.Hint(checkErr); hint != "" {
+			issueBody(writer, "    Hint: %s\n", hint)
+		}
 	}
 }
 
@@ -60,6 +73,36 @@ func (*TTYPrinter) severityPrintfFunc(severity api.SeverityType) func(w io.Write
 	return p.FprintfFunc()
 }
 
+// PrintStats prints the wall time, external API calls, and the cache hit rate of each check.
+func (tty *TTYPrinter) PrintStats(stats []usage.CheckStats) {
+	tty.m.Lock()
+	defer tty.m.Unlock()
+
+	printStats(writer, stats)
+}
+
+func printStats(w io.Writer, stats []usage.CheckStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tTIME\tAPI CALLS\tCACHE HIT RATE")
+	for _, s := range append(stats, usage.Total(stats)) {
+		fmt.Fprintf(tw, "%s\t%v\t%d\t%s\n", s.Check, s.Duration.Round(time.Millisecond), s.APICalls, hitRate(s))
+	}
+	_ = tw.Flush()
+}
+
+func hitRate(s usage.CheckStats) string {
+	rate := s.CacheHitRate()
+	if rate < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%% (%d/%d)", rate*100, s.CacheHits, s.CacheHits+s.CacheMisses)
+}
+
 func (*TTYPrinter) PrintSummary(allCheck, failedChecks int) {
 	failures := "no"
 	if failedChecks > 0 {
@@ -7,8 +7,8 @@ import (
 	"testing"
 	"time"
 
-	"go.szostok.io/codeowners/internal/api"
 	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/api"
 
 	"github.com/sebdah/goldie/v2"
 )
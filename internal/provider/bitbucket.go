@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBitbucketBaseURL is the API URL of Bitbucket Cloud.
+const DefaultBitbucketBaseURL = "https://api.bitbucket.org"
+
+// Bitbucket implements the Provider with the Bitbucket Cloud REST API. Teams are workspace groups,
+// referenced as @workspace/group-slug, which are available only in the API 1.0.
+type Bitbucket struct {
+	api *restClient
+}
+
+// NewBitbucket returns new instance of the Bitbucket provider. The token is a workspace or repository access token.
+func NewBitbucket(baseURL, token string, opts ...RESTOption) *Bitbucket {
+	if baseURL == "" {
+		baseURL = DefaultBitbucketBaseURL
+	}
+	return &Bitbucket{api: newRESTClient("Bitbucket", baseURL, func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}, opts)}
+}
+
+// Name returns human-readable name of the provider.
+func (*Bitbucket) Name() string {
+	return "Bitbucket"
+}
+
+// ResolveOwner resolves users and workspace groups. Bitbucket doesn't support roles as owners.
+func (p *Bitbucket) ResolveOwner(ctx context.Context, name string) (Owner, error) {
+	kind, workspace, slug, err := splitOwner(name)
+	if err != nil {
+		return Owner{}, err
+	}
+
+	op := fmt.Sprintf("resolving owner %s", name)
+	switch kind {
+	case Email:
+		return Owner{Name: name, Kind: Email}, nil
+	case Role:
+		return Owner{}, notFound(p.Name(), op)
+	case User:
+		var user struct{}
+		if _, err := p.api.getJSON(ctx, "/2.0/users/"+url.PathEscape(slug), op, &user); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: User}, nil
+	default:
+		if _, err := p.groupMembers(ctx, workspace, slug, op); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: Team}, nil
+	}
+}
+
+// ListTeamMembers returns nicknames of members of a given workspace group.
+func (p *Bitbucket) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	kind, workspace, slug, err := splitOwner(team)
+	if err != nil || kind != Team {
+		return nil, errors.Errorf("not a valid team %q", team)
+	}
+	return p.groupMembers(ctx, workspace, slug, fmt.Sprintf("listing members of group %s", team))
+}
+
+func (p *Bitbucket) groupMembers(ctx context.Context, workspace, slug, op string) ([]string, error) {
+	var members []struct {
+		Nickname string `json:"nickname"`
+	}
+	if _, err := p.api.getJSON(ctx, fmt.Sprintf("/1.0/groups/%s/%s/members", url.PathEscape(workspace), url.PathEscape(slug)), op, &members); err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(members))
+	for _, m := range members {
+		out = append(out, m.Nickname)
+	}
+	return out, nil
+}
+
+// FetchFile returns the content of a given file.
+func (p *Bitbucket) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	ref, err := p.ref(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return p.api.getRaw(ctx, fmt.Sprintf("/2.0/repositories/%s/src/%s/%s", escapePath(repo), url.PathEscape(ref), escapePath(path)),
+		fmt.Sprintf("fetching %s of %s", path, repo))
+}
+
+// ListTree returns paths of all files of the repository. Directories are listed one by one, as the API
+// doesn't list the tree recursively.
+func (p *Bitbucket) ListTree(ctx context.Context, repo, ref string) ([]string, error) {
+	ref, err := p.ref(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	type page struct {
+		Values []struct {
+			Type string `json:"type"`
+			Path string `json:"path"`
+		} `json:"values"`
+		Next string `json:"next"`
+	}
+
+	op := fmt.Sprintf("listing files of %s", repo)
+	var out []string
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		next := fmt.Sprintf("/2.0/repositories/%s/src/%s/%s?pagelen=100", escapePath(repo), url.PathEscape(ref), escapePath(dir))
+		for next != "" {
+			var resp page
+			if _, err := p.api.getJSON(ctx, next, op, &resp); err != nil {
+				return nil, err
+			}
+			for _, v := range resp.Values {
+				switch v.Type {
+				case "commit_file":
+					out = append(out, v.Path)
+				case "commit_directory":
+					dirs = append(dirs, strings.TrimSuffix(v.Path, "/")+"/")
+				}
+			}
+			next = resp.Next
+		}
+	}
+	return out, nil
+}
+
+// PostComment adds a comment to a given pull request.
+func (p *Bitbucket) PostComment(ctx context.Context, repo string, number int, body string) error {
+	if _, _, err := splitRepo(repo); err != nil {
+		return err
+	}
+
+	in := map[string]interface{}{"content": map[string]string{"raw": body}}
+	return p.api.postJSON(ctx, fmt.Sprintf("/2.0/repositories/%s/pullrequests/%d/comments", escapePath(repo), number),
+		fmt.Sprintf("commenting pull request #%d of %s", number, repo), in)
+}
+
+// ref returns a given ref, or the main branch of the repository if it's empty, as the API requires it.
+func (p *Bitbucket) ref(ctx context.Context, repo, ref string) (string, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return "", err
+	}
+	if ref != "" {
+		return ref, nil
+	}
+
+	var out struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if _, err := p.api.getJSON(ctx, "/2.0/repositories/"+escapePath(repo), fmt.Sprintf("getting repository %s", repo), &out); err != nil {
+		return "", err
+	}
+	return out.MainBranch.Name, nil
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultGiteaBaseURL is the API URL of gitea.com.
+const DefaultGiteaBaseURL = "https://gitea.com/api/v1"
+
+// giteaPageSize is the page size used for listing, the default maximum of Gitea instances is 50.
+const giteaPageSize = 50
+
+// Gitea implements the Provider with the Gitea REST API. Teams are organization teams, referenced as @org/team.
+type Gitea struct {
+	api *restClient
+}
+
+// NewGitea returns new instance of the Gitea provider. The token is a personal access token.
+func NewGitea(baseURL, token string, opts ...RESTOption) *Gitea {
+	if baseURL == "" {
+		baseURL = DefaultGiteaBaseURL
+	}
+	return &Gitea{api: newRESTClient("Gitea", baseURL, func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	}, opts)}
+}
+
+// Name returns human-readable name of the provider.
+func (*Gitea) Name() string {
+	return "Gitea"
+}
+
+// ResolveOwner resolves users and organization teams. Gitea doesn't support roles as owners.
+func (p *Gitea) ResolveOwner(ctx context.Context, name string) (Owner, error) {
+	kind, org, slug, err := splitOwner(name)
+	if err != nil {
+		return Owner{}, err
+	}
+
+	op := fmt.Sprintf("resolving owner %s", name)
+	switch kind {
+	case Email:
+		return Owner{Name: name, Kind: Email}, nil
+	case Role:
+		return Owner{}, notFound(p.Name(), op)
+	case User:
+		var user struct{}
+		if _, err := p.api.getJSON(ctx, "/users/"+url.PathEscape(slug), op, &user); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: User}, nil
+	default:
+		if _, err := p.teamID(ctx, org, slug, op); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: Team}, nil
+	}
+}
+
+// teamID finds the team by name, as the Gitea API addresses teams only by their IDs.
+func (p *Gitea) teamID(ctx context.Context, org, team, op string) (int64, error) {
+	var out struct {
+		Data []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	path := fmt.Sprintf("/orgs/%s/teams/search?q=%s&limit=%d", url.PathEscape(org), url.QueryEscape(team), giteaPageSize)
+	if _, err := p.api.getJSON(ctx, path, op, &out); err != nil {
+		return 0, err
+	}
+
+	for _, t := range out.Data {
+		if strings.EqualFold(t.Name, team) {
+			return t.ID, nil
+		}
+	}
+	return 0, notFound(p.Name(), op)
+}
+
+// ListTeamMembers returns logins of members of a given team.
+func (p *Gitea) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	kind, org, slug, err := splitOwner(team)
+	if err != nil || kind != Team {
+		return nil, errors.Errorf("not a valid team %q", team)
+	}
+
+	op := fmt.Sprintf("listing members of team %s", team)
+	id, err := p.teamID(ctx, org, slug, op)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for page := 1; ; page++ {
+		var members []struct {
+			Login string `json:"login"`
+		}
+		if _, err := p.api.getJSON(ctx, fmt.Sprintf("/teams/%d/members?page=%d&limit=%d", id, page, giteaPageSize), op, &members); err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			out = append(out, m.Login)
+		}
+		if len(members) < giteaPageSize {
+			return out, nil
+		}
+	}
+}
+
+// FetchFile returns the content of a given file.
+func (p *Gitea) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return nil, err
+	}
+
+	query := ""
+	if ref != "" {
+		query = "?ref=" + url.QueryEscape(ref)
+	}
+	return p.api.getRaw(ctx, fmt.Sprintf("/repos/%s/raw/%s%s", escapePath(repo), escapePath(path), query),
+		fmt.Sprintf("fetching %s of %s", path, repo))
+}
+
+// ListTree returns paths of all files of the repository.
+func (p *Gitea) ListTree(ctx context.Context, repo, ref string) ([]string, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		var r struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if _, err := p.api.getJSON(ctx, "/repos/"+escapePath(repo), fmt.Sprintf("getting repository %s", repo), &r); err != nil {
+			return nil, err
+		}
+		ref = r.DefaultBranch
+	}
+
+	op := fmt.Sprintf("listing files of %s", repo)
+	var out []string
+	for page := 1; ; page++ {
+		var tree struct {
+			Tree []struct {
+				Path string `json:"path"`
+				Type string `json:"type"`
+			} `json:"tree"`
+			Truncated bool `json:"truncated"`
+		}
+		path := fmt.Sprintf("/repos/%s/git/trees/%s?recursive=true&page=%d&per_page=1000", escapePath(repo), url.PathEscape(ref), page)
+		if _, err := p.api.getJSON(ctx, path, op, &tree); err != nil {
+			return nil, err
+		}
+		for _, e := range tree.Tree {
+			if e.Type == "blob" {
+				out = append(out, e.Path)
+			}
+		}
+		if !tree.Truncated || len(tree.Tree) == 0 {
+			return out, nil
+		}
+	}
+}
+
+// PostComment adds a comment to a given pull request.
+func (p *Gitea) PostComment(ctx context.Context, repo string, number int, body string) error {
+	if _, _, err := splitRepo(repo); err != nil {
+		return err
+	}
+
+	return p.api.postJSON(ctx, fmt.Sprintf("/repos/%s/issues/%d/comments", escapePath(repo), number),
+		fmt.Sprintf("commenting pull request #%d of %s", number, repo), map[string]string{"body": body})
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// GitHub implements the Provider with the GitHub REST API.
+type GitHub struct {
+	client *github.Client
+}
+
+// NewGitHub returns new instance of the GitHub provider.
+func NewGitHub(client *github.Client) *GitHub {
+	return &GitHub{client: client}
+}
+
+// Name returns human-readable name of the provider.
+func (*GitHub) Name() string {
+	return "GitHub"
+}
+
+// ResolveOwner resolves users and organization teams. GitHub doesn't support roles as owners.
+func (p *GitHub) ResolveOwner(ctx context.Context, name string) (Owner, error) {
+	kind, org, slug, err := splitOwner(name)
+	if err != nil {
+		return Owner{}, err
+	}
+
+	op := fmt.Sprintf("resolving owner %s", name)
+	switch kind {
+	case Team:
+		_, _, err = p.client.Teams.GetTeamBySlug(ctx, org, slug)
+	case User:
+		_, _, err = p.client.Users.Get(ctx, slug)
+	case Role:
+		return Owner{}, notFound(p.Name(), op)
+	}
+	if err != nil {
+		return Owner{}, githubError(err, op)
+	}
+	return Owner{Name: name, Kind: kind}, nil
+}
+
+// ListTeamMembers returns logins of members of a given team, including members of its child teams.
+func (p *GitHub) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	kind, org, slug, err := splitOwner(team)
+	if err != nil || kind != Team {
+		return nil, errors.Errorf("not a valid team %q", team)
+	}
+
+	var out []string
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		members, resp, err := p.client.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, githubError(err, fmt.Sprintf("listing members of team %s", team))
+		}
+		for _, m := range members {
+			out = append(out, m.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// FetchFile returns the content of a given file.
+func (p *GitHub) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	op := fmt.Sprintf("fetching %s of %s", path, repo)
+	file, _, _, err := p.client.Repositories.GetContents(ctx, owner, name, path, &github.RepositoryContentGetOptions{Ref: ref})
+	switch {
+	case err != nil:
+		return nil, githubError(err, op)
+	case file == nil: // a directory with such name
+		return nil, notFound(p.Name(), op)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, errors.Wrapf(err, "while decoding %s of %s", path, repo)
+	}
+	return []byte(content), nil
+}
+
+// ListTree returns paths of all files of the repository.
+func (p *GitHub) ListTree(ctx context.Context, repo, ref string) ([]string, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		r, _, err := p.client.Repositories.Get(ctx, owner, name)
+		if err != nil {
+			return nil, githubError(err, fmt.Sprintf("getting repository %s", repo))
+		}
+		ref = r.GetDefaultBranch()
+	}
+
+	tree, _, err := p.client.Git.GetTree(ctx, owner, name, ref, true)
+	if err != nil {
+		return nil, githubError(err, fmt.Sprintf("listing files of %s", repo))
+	}
+	if tree.GetTruncated() {
+		return nil, errors.Errorf("tree of %s at %s is too large to be listed with the GitHub API", repo, ref)
+	}
+
+	var out []string
+	for _, e := range tree.Entries {
+		if e.GetType() == "blob" {
+			out = append(out, e.GetPath())
+		}
+	}
+	return out, nil
+}
+
+// PostComment adds a comment to a given pull request.
+func (p *GitHub) PostComment(ctx context.Context, repo string, number int, body string) error {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = p.client.Issues.CreateComment(ctx, owner, name, number, &github.IssueComment{Body: &body})
+	if err != nil {
+		return githubError(err, fmt.Sprintf("commenting pull request #%d of %s", number, repo))
+	}
+	return nil
+}
+
+// githubError converts the GitHub client error into the api.APIError. Context errors are returned as they are.
+func githubError(err error, op string) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	out := &api.APIError{Provider: "GitHub", Op: op, Err: err}
+
+	var (
+		rateErr  *github.RateLimitError
+		abuseErr *github.AbuseRateLimitError
+		respErr  *github.ErrorResponse
+	)
+	switch {
+	case errors.As(err, &rateErr):
+		out.StatusCode, out.RateLimited = rateErr.Response.StatusCode, true
+		if reset := time.Until(rateErr.Rate.Reset.Time); reset > 0 {
+			out.RetryAfter = reset
+		}
+	case errors.As(err, &abuseErr):
+		out.StatusCode, out.RateLimited = abuseErr.Response.StatusCode, true
+		if abuseErr.RetryAfter != nil {
+			out.RetryAfter = *abuseErr.RetryAfter
+		}
+	case errors.As(err, &respErr):
+		out.StatusCode = respErr.Response.StatusCode
+		out.RateLimited, out.RetryAfter = api.RateLimit(respErr.Response)
+	}
+
+	return out
+}
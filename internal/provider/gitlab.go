@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultGitLabBaseURL is the API URL of gitlab.com.
+const DefaultGitLabBaseURL = "https://gitlab.com/api/v4"
+
+// gitlabRoles are roles which can be used as owners, e.g. @@maintainers.
+var gitlabRoles = map[string]struct{}{
+	"developer": {}, "developers": {},
+	"maintainer": {}, "maintainers": {},
+	"owner": {}, "owners": {},
+}
+
+// gitlabDeveloperAccess is the access level of the Developer role, the lowest one which allows approving merge requests.
+const gitlabDeveloperAccess = 30
+
+// GitLab implements the Provider with the GitLab REST API v4. Teams are GitLab groups, which may be nested.
+type GitLab struct {
+	api *restClient
+
+	// mu guards the lazily fetched projects, as the owners may be validated concurrently
+	mu       sync.Mutex
+	projects map[string]*gitlabProject
+}
+
+// gitlabProject holds the groups which have access to the project.
+type gitlabProject struct {
+	Namespace struct {
+		FullPath string `json:"full_path"`
+	} `json:"namespace"`
+	SharedWithGroups []struct {
+		GroupFullPath    string `json:"group_full_path"`
+		GroupAccessLevel int    `json:"group_access_level"`
+	} `json:"shared_with_groups"`
+}
+
+// NewGitLab returns new instance of the GitLab provider. The token is a personal, group, or project access token.
+func NewGitLab(baseURL, token string, opts ...RESTOption) *GitLab {
+	if baseURL == "" {
+		baseURL = DefaultGitLabBaseURL
+	}
+	return &GitLab{api: newRESTClient("GitLab", baseURL, func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	}, opts)}
+}
+
+// Name returns human-readable name of the provider.
+func (*GitLab) Name() string {
+	return "GitLab"
+}
+
+// ResolveOwner resolves users, groups, roles, and emails. In GitLab, a name without a slash may reference
+// a top-level group, so it's resolved as a group if there is no such user. Emails must belong to a user,
+// GitLab finds users only by their public email, unless the token belongs to an administrator.
+func (p *GitLab) ResolveOwner(ctx context.Context, name string) (Owner, error) {
+	kind, namespace, slug, err := splitOwner(name)
+	if err != nil {
+		return Owner{}, err
+	}
+
+	op := fmt.Sprintf("resolving owner %s", name)
+	switch kind {
+	case Email:
+		if _, err := p.userID(ctx, Owner{Name: name, Kind: Email}, op); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: Email}, nil
+	case Role:
+		if _, found := gitlabRoles[strings.ToLower(slug)]; !found {
+			return Owner{}, notFound(p.Name(), op)
+		}
+		return Owner{Name: name, Kind: Role}, nil
+	case User:
+		_, err := p.userID(ctx, Owner{Name: name, Kind: User}, op)
+		if !IsNotFound(err) {
+			return Owner{Name: name, Kind: User}, err
+		}
+		if _, err := p.group(ctx, slug, op); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: Team}, nil
+	default:
+		if _, err := p.group(ctx, namespace+"/"+slug, op); err != nil {
+			return Owner{}, err
+		}
+		return Owner{Name: name, Kind: Team}, nil
+	}
+}
+
+// userID returns the ID of a user referenced by the username or the email.
+func (p *GitLab) userID(ctx context.Context, owner Owner, op string) (int, error) {
+	query := "username=" + url.QueryEscape(strings.TrimPrefix(owner.Name, "@"))
+	if owner.Kind == Email {
+		query = "search=" + url.QueryEscape(owner.Name)
+	}
+
+	var users []struct {
+		ID int `json:"id"`
+	}
+	if _, err := p.api.getJSON(ctx, "/users?"+query, op, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, notFound(p.Name(), op)
+	}
+	return users[0].ID, nil
+}
+
+func (p *GitLab) group(ctx context.Context, fullPath, op string) (int, error) {
+	var group struct {
+		ID int `json:"id"`
+	}
+	if _, err := p.api.getJSON(ctx, "/groups/"+url.PathEscape(fullPath), op, &group); err != nil {
+		return 0, err
+	}
+	return group.ID, nil
+}
+
+// HasAccess returns true if a given owner can approve merge requests of the project. Users and emails must be
+// members of the project, including inherited members, with at least the Developer role. Groups must own
+// the project, directly or as a parent group, or the project must be shared with them with at least
+// the Developer role. Roles always refer to members of the project.
+func (p *GitLab) HasAccess(ctx context.Context, repo string, owner Owner) (bool, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return false, err
+	}
+
+	op := fmt.Sprintf("checking access of %s to %s", owner.Name, repo)
+	switch owner.Kind {
+	case Role:
+		return true, nil
+	case Team:
+		project, err := p.project(ctx, repo, op)
+		if err != nil {
+			return false, err
+		}
+		group := strings.ToLower(strings.TrimPrefix(owner.Name, "@"))
+		namespace := strings.ToLower(project.Namespace.FullPath)
+		if namespace == group || strings.HasPrefix(namespace, group+"/") {
+			return true, nil
+		}
+		for _, shared := range project.SharedWithGroups {
+			if strings.EqualFold(shared.GroupFullPath, group) && shared.GroupAccessLevel >= gitlabDeveloperAccess {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		id, err := p.userID(ctx, owner, op)
+		if err != nil {
+			return false, err
+		}
+		var member struct {
+			AccessLevel int `json:"access_level"`
+		}
+		_, err = p.api.getJSON(ctx, fmt.Sprintf("/projects/%s/members/all/%d", url.PathEscape(repo), id), op, &member)
+		switch {
+		case IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+		return member.AccessLevel >= gitlabDeveloperAccess, nil
+	}
+}
+
+// project returns a given project, which is fetched on the first call.
+func (p *GitLab) project(ctx context.Context, repo, op string) (*gitlabProject, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if project, found := p.projects[repo]; found {
+		return project, nil
+	}
+	project := &gitlabProject{}
+	if _, err := p.api.getJSON(ctx, "/projects/"+url.PathEscape(repo), op, project); err != nil {
+		return nil, err
+	}
+	if p.projects == nil {
+		p.projects = map[string]*gitlabProject{}
+	}
+	p.projects[repo] = project
+	return project, nil
+}
+
+// ListTeamMembers returns usernames of all members of a given group, including inherited ones.
+func (p *GitLab) ListTeamMembers(ctx context.Context, team string) ([]string, error) {
+	group := strings.TrimPrefix(team, "@")
+	if group == "" || strings.HasPrefix(group, "@") {
+		return nil, errors.Errorf("not a valid team %q", team)
+	}
+
+	op := fmt.Sprintf("listing members of group %s", team)
+	var out []string
+	for page := "1"; page != ""; {
+		var members []struct {
+			Username string `json:"username"`
+		}
+		header, err := p.api.getJSON(ctx, fmt.Sprintf("/groups/%s/members/all?per_page=100&page=%s", url.PathEscape(group), page), op, &members)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			out = append(out, m.Username)
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return out, nil
+}
+
+// FetchFile returns the content of a given file.
+func (p *GitLab) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return nil, err
+	}
+
+	query := ""
+	if ref != "" {
+		query = "?ref=" + url.QueryEscape(ref)
+	}
+	return p.api.getRaw(ctx, fmt.Sprintf("/projects/%s/repository/files/%s/raw%s", url.PathEscape(repo), url.PathEscape(path), query),
+		fmt.Sprintf("fetching %s of %s", path, repo))
+}
+
+// ListTree returns paths of all files of the repository.
+func (p *GitLab) ListTree(ctx context.Context, repo, ref string) ([]string, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"recursive": {"true"}, "per_page": {"100"}}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+
+	op := fmt.Sprintf("listing files of %s", repo)
+	var out []string
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var entries []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		}
+		header, err := p.api.getJSON(ctx, fmt.Sprintf("/projects/%s/repository/tree?%s", url.PathEscape(repo), query.Encode()), op, &entries)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Type == "blob" {
+				out = append(out, e.Path)
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return out, nil
+}
+
+// PostComment adds a note to a given merge request.
+func (p *GitLab) PostComment(ctx context.Context, repo string, number int, body string) error {
+	if _, _, err := splitRepo(repo); err != nil {
+		return err
+	}
+
+	return p.api.postJSON(ctx, fmt.Sprintf("/projects/%s/merge_requests/%d/notes", url.PathEscape(repo), number),
+		fmt.Sprintf("commenting merge request !%d of %s", number, repo), map[string]string{"body": body})
+}
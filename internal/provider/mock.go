@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Mock is an in-memory Provider, which allows testing checks without a VCS API.
+// Emails are always valid, as other providers don't verify them either.
+type Mock struct {
+	// ProviderName is returned by Name. Defaults to Mock.
+	ProviderName string
+	// Users are usernames, e.g. @user.
+	Users []string
+	// Teams are members of teams, e.g. @org/team.
+	Teams map[string][]string
+	// Roles are roles, e.g. @@maintainers.
+	Roles []string
+	// Outsiders are existing owners which don't have access to repositories. Other owners have access to all of them.
+	Outsiders []string
+	// Files are contents of files of repositories, e.g. Files["org/repo"]["CODEOWNERS"].
+	Files map[string]map[string]string
+	// Err, if set, is returned by all methods, e.g. to simulate an unavailable API.
+	Err error
+
+	mu       sync.Mutex
+	comments map[string][]string
+}
+
+// Name returns the ProviderName.
+func (m *Mock) Name() string {
+	if m.ProviderName == "" {
+		return "Mock"
+	}
+	return m.ProviderName
+}
+
+// ResolveOwner resolves the configured users, teams, and roles.
+func (m *Mock) ResolveOwner(_ context.Context, name string) (Owner, error) {
+	if m.Err != nil {
+		return Owner{}, m.Err
+	}
+	kind, _, _, err := splitOwner(name)
+	if err != nil {
+		return Owner{}, err
+	}
+
+	var found bool
+	switch kind {
+	case Email:
+		found = true
+	case User:
+		found = contains(m.Users, name)
+	case Team:
+		_, found = m.Teams[name]
+	case Role:
+		found = contains(m.Roles, name)
+	}
+	if !found {
+		return Owner{}, notFound(m.Name(), fmt.Sprintf("resolving owner %s", name))
+	}
+	return Owner{Name: name, Kind: kind}, nil
+}
+
+// ListTeamMembers returns members of a given configured team.
+func (m *Mock) ListTeamMembers(_ context.Context, team string) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	members, found := m.Teams[team]
+	if !found {
+		return nil, notFound(m.Name(), fmt.Sprintf("listing members of team %s", team))
+	}
+	return members, nil
+}
+
+// HasAccess returns false for the configured outsiders.
+func (m *Mock) HasAccess(_ context.Context, _ string, owner Owner) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	return !contains(m.Outsiders, owner.Name), nil
+}
+
+// FetchFile returns a given configured file. The ref is ignored.
+func (m *Mock) FetchFile(_ context.Context, repo, path, _ string) ([]byte, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	content, found := m.Files[repo][path]
+	if !found {
+		return nil, notFound(m.Name(), fmt.Sprintf("fetching %s of %s", path, repo))
+	}
+	return []byte(content), nil
+}
+
+// ListTree returns sorted paths of the configured files of a given repository. The ref is ignored.
+func (m *Mock) ListTree(_ context.Context, repo, _ string) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	files, found := m.Files[repo]
+	if !found {
+		return nil, notFound(m.Name(), fmt.Sprintf("listing files of %s", repo))
+	}
+
+	out := make([]string, 0, len(files))
+	for path := range files {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// PostComment stores the comment, which can be read with Comments.
+func (m *Mock) PostComment(_ context.Context, repo string, number int, body string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.comments == nil {
+		m.comments = map[string][]string{}
+	}
+	key := fmt.Sprintf("%s#%d", repo, number)
+	m.comments[key] = append(m.comments[key], body)
+	return nil
+}
+
+// Comments returns comments posted to a given pull request.
+func (m *Mock) Comments(repo string, number int) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.comments[fmt.Sprintf("%s#%d", repo, number)]
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/github"
+	"go.szostok.io/codeowners/internal/httpheader"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the supported providers.
+const (
+	GitHubName    = "github"
+	GitLabName    = "gitlab"
+	BitbucketName = "bitbucket"
+	GiteaName     = "gitea"
+)
+
+// Provider gives checks access to the VCS hosting service, so they don't depend on a specific forge.
+// Repositories are given in the 'owner/repository' form, where the owner may contain nested groups
+// if the provider supports them.
+type Provider interface {
+	// Name returns human-readable name of the provider, e.g. GitHub.
+	Name() string
+	// ResolveOwner returns the user, team, or other owner referenced in CODEOWNERS by a given name,
+	// e.g. @user or @org/team. The returned error satisfies IsNotFound if the owner doesn't exist.
+	ResolveOwner(ctx context.Context, name string) (Owner, error)
+	// ListTeamMembers returns usernames of members of a given team, e.g. @org/team.
+	ListTeamMembers(ctx context.Context, team string) ([]string, error)
+	// FetchFile returns the content of a file at a given ref. The empty ref means the default branch.
+	// The returned error satisfies IsNotFound if the file doesn't exist.
+	FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error)
+	// ListTree returns paths of all files at a given ref. The empty ref means the default branch.
+	ListTree(ctx context.Context, repo, ref string) ([]string, error)
+	// PostComment adds a comment to a given pull request, called merge request by some providers.
+	PostComment(ctx context.Context, repo string, number int, body string) error
+}
+
+// AccessChecker is implemented by providers which can verify that an owner has access to a repository.
+type AccessChecker interface {
+	// HasAccess returns true if a given owner can approve changes in a given repository.
+	HasAccess(ctx context.Context, repo string, owner Owner) (bool, error)
+}
+
+// OwnerKind is the kind of the CODEOWNERS owner.
+type OwnerKind string
+
+const (
+	// User is a single account.
+	User OwnerKind = "user"
+	// Team is a group of accounts, e.g. a GitHub team or a GitLab group.
+	Team OwnerKind = "team"
+	// Email is an owner referenced by the email address. Providers don't verify them.
+	Email OwnerKind = "email"
+	// Role is a GitLab role, e.g. @@maintainer.
+	Role OwnerKind = "role"
+)
+
+// Owner is a resolved CODEOWNERS owner.
+type Owner struct {
+	// Name as referenced in CODEOWNERS, e.g. @org/team.
+	Name string
+	Kind OwnerKind
+}
+
+// IsNotFound returns true if the error is returned for a resource which doesn't exist.
+func IsNotFound(err error) bool {
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// splitOwner returns the kind of the owner, and for teams, the namespace and the team name, e.g. `org` and `team`
+// for `@org/team`. Nested namespaces, e.g. GitLab subgroups, are kept in the namespace.
+func splitOwner(name string) (kind OwnerKind, namespace, slug string, err error) {
+	switch {
+	case strings.HasPrefix(name, "@@"):
+		return Role, "", strings.TrimPrefix(name, "@@"), nil
+	case strings.HasPrefix(name, "@"):
+		trimmed := strings.TrimPrefix(name, "@")
+		idx := strings.LastIndex(trimmed, "/")
+		if idx == -1 {
+			return User, "", trimmed, nil
+		}
+		if idx == 0 || idx == len(trimmed)-1 {
+			return "", "", "", errors.Errorf("not a valid owner %q", name)
+		}
+		return Team, trimmed[:idx], trimmed[idx+1:], nil
+	default:
+		if _, err := mail.ParseAddress(name); err != nil {
+			return "", "", "", errors.Errorf("not a valid owner %q", name)
+		}
+		return Email, "", name, nil
+	}
+}
+
+// splitRepo returns the owner and the name of the repository given in the 'owner/repository' form.
+func splitRepo(repo string) (owner, name string, err error) {
+	idx := strings.LastIndex(repo, "/")
+	if idx <= 0 || idx == len(repo)-1 {
+		return "", "", errors.Errorf("wrong repository name, expected pattern 'owner/repository', got %q", repo)
+	}
+	return repo[:idx], repo[idx+1:], nil
+}
+
+// notFound returns the error satisfying IsNotFound.
+func notFound(provider, op string) error {
+	return &api.APIError{Provider: provider, Op: op, StatusCode: http.StatusNotFound, Err: errors.New("not found")}
+}
+
+// New returns the provider configured with the VCS_PROVIDER option. GitHub clients are created with
+// the GitHub authorization options and given client options, other providers use the VCS_TOKEN.
+// All clients send the headers configured with HTTP_HEADERS and HTTP_USER_AGENT.
+func New(ctx context.Context, cfg *config.Config, opts ...github.ClientOption) (Provider, error) {
+	if IsGitHub(cfg) {
+		ghClient, _, err := github.NewClient(ctx, cfg, opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "while creating GitHub client")
+		}
+		return NewGitHub(ghClient), nil
+	}
+
+	transport, err := httpheader.Wrap(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	restOpts := []RESTOption{WithTransport(transport)}
+
+	switch strings.ToLower(cfg.VCSProvider) {
+	case GitLabName:
+		return NewGitLab(cfg.VCSBaseURL, cfg.VCSToken, restOpts...), nil
+	case BitbucketName:
+		return NewBitbucket(cfg.VCSBaseURL, cfg.VCSToken, restOpts...), nil
+	case GiteaName:
+		return NewGitea(cfg.VCSBaseURL, cfg.VCSToken, restOpts...), nil
+	default:
+		return nil, &api.ConfigError{
+			Field: "VCS_PROVIDER",
+			Err:   errors.Errorf("unknown provider %q, possible values: %s, %s, %s, %s", cfg.VCSProvider, GitHubName, GitLabName, BitbucketName, GiteaName),
+		}
+	}
+}
+
+// ForOwners returns the configuration of the provider which validates owners. It's the provider selected with
+// the OWNER_CHECKER_PLATFORM option, e.g. if the repository is mirrored to GitHub but owners are GitLab users
+// and groups, or the VCS_PROVIDER if the option is not set. The given configuration is not modified.
+func ForOwners(cfg *config.Config) (*config.Config, error) {
+	if cfg.OwnerCheckerPlatform == "" {
+		return cfg, nil
+	}
+
+	switch name := strings.ToLower(cfg.OwnerCheckerPlatform); name {
+	case GitHubName, GitLabName, BitbucketName, GiteaName:
+		out := *cfg
+		out.VCSProvider = name
+		return &out, nil
+	default:
+		return nil, &api.ConfigError{
+			Field: "OWNER_CHECKER_PLATFORM",
+			Err:   errors.Errorf("unknown platform %q, possible values: %s, %s, %s, %s", cfg.OwnerCheckerPlatform, GitHubName, GitLabName, BitbucketName, GiteaName),
+		}
+	}
+}
+
+// IsGitHub returns true if GitHub is the configured provider, which is the default.
+func IsGitHub(cfg *config.Config) bool {
+	return cfg.VCSProvider == "" || strings.EqualFold(cfg.VCSProvider, GitHubName)
+}
@@ -0,0 +1,355 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/provider"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forge is a fake VCS API, which serves the same repository and owners for each provider:
+// the @alice user, the @org/docs team with the alice member, and the org/repo repository with
+// the CODEOWNERS and docs/index.md files. The comment posted to the pull request #7 is recorded.
+type forge struct {
+	routes  map[string]string
+	comment string
+}
+
+func newForge(t *testing.T, routes map[string]string, commentPath string) (*forge, *httptest.Server) {
+	t.Helper()
+	f := &forge{routes: routes}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == commentPath {
+			raw, _ := io.ReadAll(r.Body)
+			f.comment = string(raw)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("{}"))
+			return
+		}
+		body, found := f.routes[r.URL.RequestURI()]
+		if !found {
+			body, found = f.routes[r.URL.Path]
+		}
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func TestProviders(t *testing.T) {
+	const codeowners = "/docs/ @org/docs\n"
+
+	tests := map[string]struct {
+		routes      map[string]string
+		commentPath string
+		expComment  string
+		newProvider func(baseURL string) provider.Provider
+	}{
+		"GitHub": {
+			routes: map[string]string{
+				"/users/alice":                        `{"login": "alice"}`,
+				"/orgs/org/teams/docs":                `{"slug": "docs"}`,
+				"/orgs/org/teams/docs/members":        `[{"login": "alice"}]`,
+				"/repos/org/repo/contents/CODEOWNERS": fmt.Sprintf(`{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(codeowners))),
+				"/repos/org/repo":                     `{"default_branch": "main"}`,
+				"/repos/org/repo/git/trees/main":      `{"tree": [{"path": "CODEOWNERS", "type": "blob"}, {"path": "docs", "type": "tree"}, {"path": "docs/index.md", "type": "blob"}]}`,
+			},
+			commentPath: "/repos/org/repo/issues/7/comments",
+			expComment:  `{"body":"hello"}`,
+			newProvider: func(baseURL string) provider.Provider {
+				client := github.NewClient(nil)
+				client.BaseURL, _ = url.Parse(baseURL + "/")
+				return provider.NewGitHub(client)
+			},
+		},
+		"GitLab": {
+			routes: map[string]string{
+				"/users?username=alice":                                                   `[{"username": "alice"}]`,
+				"/users?username=bob":                                                     `[]`,
+				"/groups/org%2Fdocs":                                                      `{"id": 1}`,
+				"/groups/org%2Fdocs/members/all?per_page=100&page=1":                      `[{"username": "alice"}]`,
+				"/projects/org%2Frepo/repository/files/CODEOWNERS/raw":                    codeowners,
+				"/projects/org%2Frepo/repository/tree?page=1&per_page=100&recursive=true": `[{"path": "CODEOWNERS", "type": "blob"}, {"path": "docs", "type": "tree"}, {"path": "docs/index.md", "type": "blob"}]`,
+			},
+			commentPath: "/projects/org/repo/merge_requests/7/notes",
+			expComment:  `{"body":"hello"}`,
+			newProvider: func(baseURL string) provider.Provider {
+				return provider.NewGitLab(baseURL, "token")
+			},
+		},
+		"Bitbucket": {
+			routes: map[string]string{
+				"/2.0/users/alice":                                      `{"nickname": "alice"}`,
+				"/1.0/groups/org/docs/members":                          `[{"nickname": "alice"}]`,
+				"/2.0/repositories/org/repo":                            `{"mainbranch": {"name": "main"}}`,
+				"/2.0/repositories/org/repo/src/main/CODEOWNERS":        codeowners,
+				"/2.0/repositories/org/repo/src/main/?pagelen=100":      `{"values": [{"type": "commit_file", "path": "CODEOWNERS"}, {"type": "commit_directory", "path": "docs"}]}`,
+				"/2.0/repositories/org/repo/src/main/docs/?pagelen=100": `{"values": [{"type": "commit_file", "path": "docs/index.md"}]}`,
+			},
+			commentPath: "/2.0/repositories/org/repo/pullrequests/7/comments",
+			expComment:  `{"content":{"raw":"hello"}}`,
+			newProvider: func(baseURL string) provider.Provider {
+				return provider.NewBitbucket(baseURL, "token")
+			},
+		},
+		"Gitea": {
+			routes: map[string]string{
+				"/users/alice":                   `{"login": "alice"}`,
+				"/orgs/org/teams/search":         `{"ok": true, "data": [{"id": 3, "name": "docs"}]}`,
+				"/teams/3/members":               `[{"login": "alice"}]`,
+				"/repos/org/repo/raw/CODEOWNERS": codeowners,
+				"/repos/org/repo":                `{"default_branch": "main"}`,
+				"/repos/org/repo/git/trees/main": `{"tree": [{"path": "CODEOWNERS", "type": "blob"}, {"path": "docs", "type": "tree"}, {"path": "docs/index.md", "type": "blob"}], "truncated": false}`,
+			},
+			commentPath: "/repos/org/repo/issues/7/comments",
+			expComment:  `{"body":"hello"}`,
+			newProvider: func(baseURL string) provider.Provider {
+				return provider.NewGitea(baseURL, "token")
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			ctx := context.Background()
+			f, srv := newForge(t, tc.routes, tc.commentPath)
+			sut := tc.newProvider(srv.URL)
+
+			// when
+			user, err := sut.ResolveOwner(ctx, "@alice")
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, provider.Owner{Name: "@alice", Kind: provider.User}, user)
+
+			// when
+			team, err := sut.ResolveOwner(ctx, "@org/docs")
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, provider.Owner{Name: "@org/docs", Kind: provider.Team}, team)
+
+			// when
+			_, err = sut.ResolveOwner(ctx, "@org/missing")
+			// then
+			assert.True(t, provider.IsNotFound(err), "expected not found error, got %v", err)
+
+			// when
+			members, err := sut.ListTeamMembers(ctx, "@org/docs")
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"alice"}, members)
+
+			// when
+			content, err := sut.FetchFile(ctx, "org/repo", "CODEOWNERS", "")
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, codeowners, string(content))
+
+			// when
+			_, err = sut.FetchFile(ctx, "org/repo", "docs/CODEOWNERS", "")
+			// then
+			assert.True(t, provider.IsNotFound(err), "expected not found error, got %v", err)
+
+			// when
+			files, err := sut.ListTree(ctx, "org/repo", "")
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"CODEOWNERS", "docs/index.md"}, files)
+
+			// when
+			err = sut.PostComment(ctx, "org/repo", 7, "hello")
+			// then
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expComment, f.comment)
+		})
+	}
+}
+
+func TestResolveOwnerWithoutLookup(t *testing.T) {
+	tests := map[string]struct {
+		givenOwner  string
+		expOwner    provider.Owner
+		expNotFound bool
+	}{
+		"Should accept GitLab roles": {
+			givenOwner: "@@maintainers",
+			expOwner:   provider.Owner{Name: "@@maintainers", Kind: provider.Role},
+		},
+		"Should reject unknown GitLab roles": {
+			givenOwner:  "@@reviewers",
+			expNotFound: true,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := provider.NewGitLab("http://127.0.0.1:0", "")
+
+			// when
+			owner, err := sut.ResolveOwner(context.Background(), tc.givenOwner)
+
+			// then
+			if tc.expNotFound {
+				assert.True(t, provider.IsNotFound(err), "expected not found error, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOwner, owner)
+		})
+	}
+}
+
+func TestNewSendsConfiguredHeaders(t *testing.T) {
+	tests := map[string]struct {
+		givenConfig func(baseURL string) *config.Config
+	}{
+		"GitHub": {
+			givenConfig: func(baseURL string) *config.Config {
+				return &config.Config{GithubAccessToken: "token", GithubBaseURL: baseURL}
+			},
+		},
+		"GitLab": {
+			givenConfig: func(baseURL string) *config.Config {
+				return &config.Config{VCSProvider: provider.GitLabName, VCSBaseURL: baseURL}
+			},
+		},
+		"Gitea": {
+			givenConfig: func(baseURL string) *config.Config {
+				return &config.Config{VCSProvider: provider.GiteaName, VCSBaseURL: baseURL}
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			var got http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				_, _ = w.Write([]byte(`{"login": "alice", "username": "alice", "type": "User"}`))
+			}))
+			defer srv.Close()
+
+			cfg := tc.givenConfig(srv.URL + "/")
+			cfg.HTTPHeaders = []string{"X-Org-Token: secret"}
+			cfg.HTTPUserAgent = "corp-validator/1.0"
+
+			sut, err := provider.New(context.Background(), cfg)
+			require.NoError(t, err)
+
+			// when
+			_, _ = sut.ResolveOwner(context.Background(), "@alice")
+
+			// then
+			require.NotNil(t, got, "provider didn't send any request")
+			assert.Equal(t, "secret", got.Get("X-Org-Token"))
+			assert.Equal(t, "corp-validator/1.0", got.Get("User-Agent"))
+		})
+	}
+}
+
+func TestGitLabHasAccess(t *testing.T) {
+	_, srv := newForge(t, map[string]string{
+		"/users?username=alice":                      `[{"id": 1, "username": "alice"}]`,
+		"/users?username=guest":                      `[{"id": 2, "username": "guest"}]`,
+		"/users?username=outsider":                   `[{"id": 3, "username": "outsider"}]`,
+		"/users?search=alice%40example.com":          `[{"id": 1, "username": "alice"}]`,
+		"/users?search=unknown%40example.com":        `[]`,
+		"/groups/org%2Fdocs":                         `{"id": 1}`,
+		"/projects/org%2Fgroup%2Frepo/members/all/1": `{"id": 1, "access_level": 30}`,
+		"/projects/org%2Fgroup%2Frepo/members/all/2": `{"id": 2, "access_level": 10}`,
+		"/projects/org%2Fgroup%2Frepo": `{"namespace": {"full_path": "org/group"}, "shared_with_groups": [
+			{"group_full_path": "partners/docs", "group_access_level": 30},
+			{"group_full_path": "partners/guests", "group_access_level": 10}
+		]}`,
+	}, "")
+	sut := provider.NewGitLab(srv.URL, "token")
+
+	tests := map[string]struct {
+		givenOwner provider.Owner
+		expAccess  bool
+	}{
+		"Should accept developer":                 {givenOwner: provider.Owner{Name: "@alice", Kind: provider.User}, expAccess: true},
+		"Should accept email of developer":        {givenOwner: provider.Owner{Name: "alice@example.com", Kind: provider.Email}, expAccess: true},
+		"Should reject guest":                     {givenOwner: provider.Owner{Name: "@guest", Kind: provider.User}},
+		"Should reject user who is not a member":  {givenOwner: provider.Owner{Name: "@outsider", Kind: provider.User}},
+		"Should accept group of the project":      {givenOwner: provider.Owner{Name: "@org/group", Kind: provider.Team}, expAccess: true},
+		"Should accept parent group":              {givenOwner: provider.Owner{Name: "@org", Kind: provider.Team}, expAccess: true},
+		"Should accept group sharing the project": {givenOwner: provider.Owner{Name: "@Partners/Docs", Kind: provider.Team}, expAccess: true},
+		"Should reject group sharing as guests":   {givenOwner: provider.Owner{Name: "@partners/guests", Kind: provider.Team}},
+		"Should reject other group":               {givenOwner: provider.Owner{Name: "@org/docs", Kind: provider.Team}},
+		"Should accept role":                      {givenOwner: provider.Owner{Name: "@@maintainers", Kind: provider.Role}, expAccess: true},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			hasAccess, err := sut.HasAccess(context.Background(), "org/group/repo", tc.givenOwner)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expAccess, hasAccess)
+		})
+	}
+
+	t.Run("Should resolve emails of users", func(t *testing.T) {
+		// when
+		owner, err := sut.ResolveOwner(context.Background(), "alice@example.com")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, provider.Owner{Name: "alice@example.com", Kind: provider.Email}, owner)
+
+		// when
+		_, err = sut.ResolveOwner(context.Background(), "unknown@example.com")
+		// then
+		assert.True(t, provider.IsNotFound(err), "expected not found error, got %v", err)
+	})
+}
+
+func TestForOwners(t *testing.T) {
+	tests := map[string]struct {
+		givenConfig config.Config
+		expProvider string
+		expErr      string
+	}{
+		"Should use the VCS provider by default": {
+			givenConfig: config.Config{VCSProvider: provider.GiteaName},
+			expProvider: provider.GiteaName,
+		},
+		"Should use the owners platform": {
+			givenConfig: config.Config{VCSProvider: provider.GitHubName, OwnerCheckerPlatform: "GitLab"},
+			expProvider: provider.GitLabName,
+		},
+		"Should reject unknown platform": {
+			givenConfig: config.Config{OwnerCheckerPlatform: "svn"},
+			expErr:      `unknown platform "svn", possible values: github, gitlab, bitbucket, gitea`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			cfg, err := provider.ForOwners(&tc.givenConfig)
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expProvider, cfg.VCSProvider)
+		})
+	}
+}
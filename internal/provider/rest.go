@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+)
+
+// restClient calls JSON REST APIs of providers which don't have a dedicated Go client in the project.
+type restClient struct {
+	provider string
+	baseURL  string
+	auth     func(req *http.Request)
+	http     *http.Client
+}
+
+// RESTOption allows to customize clients of providers other than GitHub.
+type RESTOption func(*restOptions)
+
+type restOptions struct {
+	transport http.RoundTripper
+}
+
+// WithTransport sends requests with a given transport, e.g. one which adds headers required by an API gateway.
+func WithTransport(transport http.RoundTripper) RESTOption {
+	return func(o *restOptions) {
+		o.transport = transport
+	}
+}
+
+func newRESTClient(provider, baseURL string, auth func(req *http.Request), opts []RESTOption) *restClient {
+	var options restOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &restClient{
+		provider: provider,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		auth:     auth,
+		http:     &http.Client{Timeout: 30 * time.Second, Transport: usage.Transport(options.transport)},
+	}
+}
+
+// getJSON fetches a given path, or an absolute URL, and decodes the JSON response into out.
+// Response headers are returned, as some providers paginate with them.
+func (c *restClient) getJSON(ctx context.Context, path, op string, out interface{}) (http.Header, error) {
+	resp, err := c.do(ctx, http.MethodGet, path, op, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, errors.Wrapf(err, "while decoding response of %s", op)
+	}
+	return resp.Header, nil
+}
+
+// getRaw fetches a given path and returns the response body as it is.
+func (c *restClient) getRaw(ctx context.Context, path, op string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, path, op, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading response of %s", op)
+	}
+	return raw, nil
+}
+
+// postJSON sends a given body encoded as JSON.
+func (c *restClient) postJSON(ctx context.Context, path, op string, in interface{}) error {
+	resp, err := c.do(ctx, http.MethodPost, path, op, in)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *restClient) do(ctx context.Context, method, path, op string, in interface{}) (*http.Response, error) {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = c.baseURL + path
+	}
+
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while encoding request of %s", op)
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != nil {
+		c.auth(req)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &api.APIError{Provider: c.provider, Op: op, Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		limited, retryAfter := api.RateLimit(resp)
+		return nil, &api.APIError{
+			Provider:    c.provider,
+			Op:          op,
+			StatusCode:  resp.StatusCode,
+			RateLimited: limited,
+			RetryAfter:  retryAfter,
+			Err:         errors.Errorf("unexpected status %s: %s", resp.Status, msg),
+		}
+	}
+	return resp, nil
+}
+
+// escapePath escapes each segment of a given path, keeping the slashes.
+func escapePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,286 @@
+package quorum
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// searchLimit bounds the number of partial covers explored by the exact search. Diffs which require more
+// are answered with the greedy estimate, which is not guaranteed to be the smallest.
+const searchLimit = 200000
+
+// Result is the smallest set of owners whose approvals satisfy the code owner review of all changed files.
+type Result struct {
+	// Files is the number of changed files.
+	Files int `json:"files"`
+	// Reviewers are sorted by the number of files they can approve, the broadest first.
+	Reviewers []Reviewer `json:"reviewers"`
+	// Optimal is false if the search was too expensive, and the reviewers are only a greedy estimate.
+	Optimal bool `json:"optimal"`
+	// Unowned are changed files which don't require any code owner approval.
+	Unowned []string `json:"unowned"`
+}
+
+// Reviewer is an owner whose approval is required.
+type Reviewer struct {
+	Owner string `json:"owner"`
+	// Files is the number of changed files the owner can approve.
+	Files int `json:"files"`
+	// Required is set if the owner is the only owner of some of the changed files.
+	Required bool `json:"required"`
+}
+
+// Estimate computes the smallest set of owners that can approve all given files. As in GitHub, a file
+// is approved if any one of its owners approves the change. Owners are compared case-insensitively.
+func Estimate(entries []codeowners.Entry, files []string) (Result, error) {
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "while compiling CODEOWNERS patterns")
+	}
+
+	out := Result{Files: len(files), Unowned: []string{}, Optimal: true}
+
+	spelling := map[string]string{}
+	approves := map[string]int{}
+	groupsByKey := map[string][]string{}
+	for _, f := range files {
+		entry, found := matcher.Match(f)
+		if !found || len(entry.Owners) == 0 {
+			out.Unowned = append(out.Unowned, f)
+			continue
+		}
+
+		group := normalize(entry.Owners, spelling)
+		for _, o := range group {
+			approves[o]++
+		}
+		groupsByKey[strings.Join(group, " ")] = group
+	}
+
+	groups := minimalGroups(groupsByKey)
+	cover, optimal := smallestCover(groups)
+	out.Optimal = optimal
+
+	required := map[string]bool{}
+	for _, g := range groups {
+		if len(g) == 1 {
+			required[g[0]] = true
+		}
+	}
+	for _, o := range cover {
+		out.Reviewers = append(out.Reviewers, Reviewer{Owner: spelling[o], Files: approves[o], Required: required[o]})
+	}
+	sort.Slice(out.Reviewers, func(i, j int) bool {
+		a, b := out.Reviewers[i], out.Reviewers[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return strings.ToLower(a.Owner) < strings.ToLower(b.Owner)
+	})
+	sort.Strings(out.Unowned)
+
+	return out, nil
+}
+
+// normalize returns sorted, lower-cased and deduplicated owners, remembering the first spelling of each of them.
+func normalize(owners []string, spelling map[string]string) []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, o := range owners {
+		key := strings.ToLower(o)
+		if _, found := spelling[key]; !found {
+			spelling[key] = o
+		}
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// minimalGroups drops owner groups which are supersets of other groups, as they are approved by any cover
+// of the smaller group. Groups are sorted by size, so the most constrained ones are covered first.
+func minimalGroups(byKey map[string][]string) [][]string {
+	all := make([][]string, 0, len(byKey))
+	for _, g := range byKey {
+		all = append(all, g)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if len(all[i]) != len(all[j]) {
+			return len(all[i]) < len(all[j])
+		}
+		return strings.Join(all[i], " ") < strings.Join(all[j], " ")
+	})
+
+	var out [][]string
+	for _, g := range all {
+		redundant := false
+		for _, kept := range out {
+			if isSubset(kept, g) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+func isSubset(small, big []string) bool {
+	for _, s := range small {
+		if !contains(big, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(owners []string, owner string) bool {
+	for _, o := range owners {
+		if o == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// smallestCover returns the smallest set of owners which has at least one owner of each group. The exact
+// branch and bound search starts with the greedy cover as the upper bound, and returns the best cover found
+// so far if the search limit is reached.
+func smallestCover(groups [][]string) ([]string, bool) {
+	best := greedyCover(groups)
+
+	var (
+		explored int
+		chosen   []string
+		search   func(covered int) bool
+	)
+	search = func(covered int) bool {
+		explored++
+		if explored > searchLimit {
+			return false
+		}
+
+		// branch on the first group which is not covered yet, its owners are the only way to cover it
+		next := -1
+		for i := covered; i < len(groups); i++ {
+			if !coversGroup(chosen, groups[i]) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			if len(chosen) < len(best) {
+				best = append([]string(nil), chosen...)
+			}
+			return true
+		}
+		if len(chosen)+1 >= len(best) {
+			return true
+		}
+
+		for _, o := range groups[next] {
+			chosen = append(chosen, o)
+			ok := search(next + 1)
+			chosen = chosen[:len(chosen)-1]
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	optimal := search(0)
+	sort.Strings(best)
+	return best, optimal
+}
+
+// greedyCover repeatedly picks the owner who covers the most uncovered groups.
+func greedyCover(groups [][]string) []string {
+	var out []string
+	uncovered := append([][]string(nil), groups...)
+	for len(uncovered) > 0 {
+		counts := map[string]int{}
+		for _, g := range uncovered {
+			for _, o := range g {
+				counts[o]++
+			}
+		}
+
+		var pick string
+		for o, n := range counts {
+			if n > counts[pick] || (n == counts[pick] && o < pick) {
+				pick = o
+			}
+		}
+		out = append(out, pick)
+
+		rest := uncovered[:0]
+		for _, g := range uncovered {
+			if !contains(g, pick) {
+				rest = append(rest, g)
+			}
+		}
+		uncovered = rest
+	}
+	return out
+}
+
+func coversGroup(chosen, group []string) bool {
+	for _, o := range chosen {
+		if contains(group, o) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the result as a JSON object.
+func WriteJSON(w io.Writer, r Result) error {
+	if r.Reviewers == nil {
+		r.Reviewers = []Reviewer{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes the result as an aligned plain text table.
+func WriteText(w io.Writer, r Result) error {
+	estimate := ""
+	if !r.Optimal {
+		estimate = " (estimate, the diff is too large to find the smallest set)"
+	}
+	fmt.Fprintf(w, "Changed files:       %d\n", r.Files)
+	fmt.Fprintf(w, "Unowned files:       %d\n", len(r.Unowned))
+	fmt.Fprintf(w, "Required approvals:  %d%s\n\n", len(r.Reviewers), estimate)
+
+	if len(r.Reviewers) == 0 {
+		_, err := fmt.Fprintln(w, "No code owner approvals are required.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REVIEWER\tCAN APPROVE FILES\t")
+	for _, rev := range r.Reviewers {
+		owner := rev.Owner
+		if rev.Required {
+			owner += " (required)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t\n", owner, rev.Files)
+	}
+	return tw.Flush()
+}
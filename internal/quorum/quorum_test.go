@@ -0,0 +1,81 @@
+package quorum_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/quorum"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := map[string]struct {
+		codeowners string
+		files      []string
+		expResult  quorum.Result
+	}{
+		"Should find a smaller set than the greedy one": {
+			// greedy picks @x first, as it owns most files, and then needs both @y and @z
+			codeowners: `
+				/f1  @x @y @p1
+				/f2  @x @z @p2
+				/f3  @x @y @p3
+				/f4  @x @z @p4
+				/f5  @y @p5
+				/f6  @Z @p6
+				/docs/
+			`,
+			files: []string{"f1", "f2", "f3", "f4", "f5", "f6", "docs/README.md", "main.go"},
+			expResult: quorum.Result{
+				Files: 8,
+				Reviewers: []quorum.Reviewer{
+					{Owner: "@y", Files: 3},
+					{Owner: "@z", Files: 3},
+				},
+				Optimal: true,
+				Unowned: []string{"docs/README.md", "main.go"},
+			},
+		},
+		"Should mark the only owners of files as required": {
+			codeowners: `
+				*           @org/platform
+				/svc/       @org/svc
+				/svc/api/   @org/api @org/svc
+			`,
+			files: []string{"svc/main.go", "svc/api/api.proto", "svc/api/api.pb.go"},
+			expResult: quorum.Result{
+				Files: 3,
+				Reviewers: []quorum.Reviewer{
+					{Owner: "@org/svc", Files: 3, Required: true},
+				},
+				Optimal: true,
+				Unowned: []string{},
+			},
+		},
+		"Should not require approvals of unowned files": {
+			codeowners: `/docs/ @org/docs`,
+			files:      []string{"main.go"},
+			expResult: quorum.Result{
+				Files:   1,
+				Optimal: true,
+				Unowned: []string{"main.go"},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			entries := codeowners.ParseCodeowners(strings.NewReader(tc.codeowners))
+
+			// when
+			result, err := quorum.Estimate(entries, tc.files)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expResult, result)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package recording
+
+import (
+	"net/http"
+	"sync"
+
+	"go.szostok.io/codeowners/internal/redact"
+)
+
+var (
+	installOnce sync.Once
+	installed   *Recorder
+)
+
+// Record records interactions of the whole process. Similar to the netguard.Enforce, it replaces the default
+// HTTP transport, used by all HTTP clients created by the application, so it must be called before the clients
+// are created. Credentials are scrubbed with the redact.Default redactor. Subsequent calls return the same Recorder.
+func Record() *Recorder {
+	installOnce.Do(func() {
+		installed = NewRecorder(http.DefaultTransport, redact.Default)
+		http.DefaultTransport = installed
+		http.DefaultClient.Transport = installed
+	})
+	return installed
+}
+
+// Replay serves requests of the whole process from a given fixture, so no request reaches the network.
+// Similar to the Record, it must be called before the HTTP clients are created. It cannot be reverted.
+func Replay(path string) error {
+	f, err := LoadFixture(path)
+	if err != nil {
+		return err
+	}
+
+	replayer := NewReplayer(f, redact.Default)
+	http.DefaultTransport = replayer
+	http.DefaultClient.Transport = replayer
+	return nil
+}
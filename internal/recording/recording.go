@@ -0,0 +1,238 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/internal/redact"
+
+	"github.com/pkg/errors"
+)
+
+// fixtureVersion is the version of the fixture format, bumped on incompatible changes.
+const fixtureVersion = 1
+
+// ErrNotRecorded is returned by the Replayer for requests which are not in the fixture.
+var ErrNotRecorded = errors.New("no recorded response, the recording may be outdated")
+
+// skippedHeaders are response headers which are not recorded, as they are specific to the session.
+var skippedHeaders = map[string]struct{}{
+	"Set-Cookie": {},
+	"Date":       {},
+}
+
+// Interaction is a recorded request to an external API together with its response.
+type Interaction struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"requestBody,omitempty"`
+	StatusCode  int         `json:"statusCode"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+func (i Interaction) key() string {
+	return requestKey(i.Method, i.URL, i.RequestBody)
+}
+
+// Fixture holds interactions recorded during a single run.
+type Fixture struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadFixture reads the fixture saved by the Recorder.
+func LoadFixture(path string) (*Fixture, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading recording")
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, errors.Wrapf(err, "while decoding recording %s", path)
+	}
+	if f.Version != fixtureVersion {
+		return nil, errors.Errorf("unsupported version %d of recording %s, expected %d", f.Version, path, fixtureVersion)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to a given path. The file is replaced atomically, so it's never left half-written.
+func (f *Fixture) Save(path string) error {
+	raw, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "while encoding recording")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "while creating recording")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "while writing recording")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "while writing recording")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), "while saving recording")
+}
+
+// Recorder is an http.RoundTripper which captures interactions with external APIs. Credentials are scrubbed
+// from the recorded URLs, headers, and bodies with the redactor, and request headers are not recorded at all,
+// so recordings can be attached to bug reports.
+type Recorder struct {
+	base     http.RoundTripper
+	redactor *redact.Redactor
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns new instance of the Recorder which sends requests with a given transport.
+// If the transport is nil, the netguard.BaseTransport is used.
+func NewRecorder(base http.RoundTripper, redactor *redact.Redactor) *Recorder {
+	if base == nil {
+		base = netguard.BaseTransport()
+	}
+	return &Recorder{base: base, redactor: redactor}
+}
+
+// RoundTrip sends the request and records it together with the response. Failed requests are not recorded.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading request body")
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading response body")
+	}
+
+	header := http.Header{}
+	for name, values := range resp.Header {
+		if _, skipped := skippedHeaders[http.CanonicalHeaderKey(name)]; skipped {
+			continue
+		}
+		for _, v := range values {
+			header.Add(name, r.redactor.String(v))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Method:      req.Method,
+		URL:         r.redactor.String(req.URL.String()),
+		RequestBody: r.redactor.String(string(reqBody)),
+		StatusCode:  resp.StatusCode,
+		Header:      header,
+		Body:        r.redactor.String(string(respBody)),
+	})
+	return resp, nil
+}
+
+// Fixture returns interactions recorded so far. Checks send requests concurrently, so interactions are sorted
+// by the request to make fixtures stable. The order of the same repeated requests is kept.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	out := make([]Interaction, len(r.interactions))
+	copy(out, r.interactions)
+	r.mu.Unlock()
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].key() < out[j].key()
+	})
+	return &Fixture{Version: fixtureVersion, Interactions: out}
+}
+
+// Replayer is an http.RoundTripper which serves responses from a fixture instead of sending requests.
+// Requests are matched by the method, the URL, and the body, scrubbed in the same way as during the recording.
+// The same requests are served with their recorded responses in order, and the last one is repeated
+// when they run out.
+type Replayer struct {
+	redactor *redact.Redactor
+
+	mu        sync.Mutex
+	responses map[string][]Interaction
+}
+
+// NewReplayer returns new instance of the Replayer serving a given fixture.
+func NewReplayer(f *Fixture, redactor *redact.Redactor) *Replayer {
+	responses := map[string][]Interaction{}
+	for _, i := range f.Interactions {
+		responses[i.key()] = append(responses[i.key()], i)
+	}
+	return &Replayer{redactor: redactor, responses: responses}
+}
+
+// RoundTrip returns the recorded response of a given request, or ErrNotRecorded if there is none.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading request body")
+	}
+
+	url := r.redactor.String(req.URL.String())
+	key := requestKey(req.Method, url, r.redactor.String(string(reqBody)))
+
+	r.mu.Lock()
+	recorded := r.responses[key]
+	if len(recorded) == 0 {
+		r.mu.Unlock()
+		return nil, errors.Wrapf(ErrNotRecorded, "for %s request to %s", req.Method, url)
+	}
+	i := recorded[0]
+	if len(recorded) > 1 {
+		r.responses[key] = recorded[1:]
+	}
+	r.mu.Unlock()
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(i.Body)),
+		ContentLength: int64(len(i.Body)),
+		Request:       req,
+	}, nil
+}
+
+func requestKey(method, url, body string) string {
+	return method + " " + url + " " + body
+}
+
+// readBody reads the whole body and replaces it with a copy, so it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	raw, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(raw))
+	return raw, nil
+}
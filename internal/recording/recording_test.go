@@ -0,0 +1,116 @@
+package recording_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/recording"
+	"go.szostok.io/codeowners/internal/redact"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "s3cr3t-token"
+
+func TestRecordAndReplay(t *testing.T) {
+	// given
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-Next-Page", "")
+		w.Header().Set("X-Echo", "Bearer "+secret)
+		w.Header().Set("Set-Cookie", "session=abc")
+		switch r.URL.Path {
+		case "/users":
+			_, _ = io.WriteString(w, `[{"username": "alice"}]`)
+		case "/counter":
+			_, _ = io.WriteString(w, strings.Repeat("x", int(n)))
+		case "/comments":
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message": "not found", "token": "`+secret+`"}`)
+		}
+	}))
+	defer srv.Close()
+
+	redactor := redact.New(secret)
+	recorder := recording.NewRecorder(nil, redactor)
+	client := &http.Client{Transport: recorder}
+
+	requests := []func(c *http.Client) (*http.Response, error){
+		func(c *http.Client) (*http.Response, error) {
+			return c.Get(srv.URL + "/users?username=alice&private_token=" + secret)
+		},
+		func(c *http.Client) (*http.Response, error) { return c.Get(srv.URL + "/missing") },
+		func(c *http.Client) (*http.Response, error) { return c.Get(srv.URL + "/counter") },
+		func(c *http.Client) (*http.Response, error) { return c.Get(srv.URL + "/counter") },
+		func(c *http.Client) (*http.Response, error) {
+			return c.Post(srv.URL+"/comments", "application/json", strings.NewReader(`{"body": "hello"}`))
+		},
+	}
+
+	var recorded []string
+	for _, send := range requests {
+		resp, err := send(client)
+		recorded = append(recorded, dump(t, resp, err))
+	}
+
+	// when
+	path := filepath.Join(t.TempDir(), "recording.json")
+	require.NoError(t, recorder.Fixture().Save(path))
+	srv.Close()
+
+	fixture, err := recording.LoadFixture(path)
+	require.NoError(t, err)
+	replay := &http.Client{Transport: recording.NewReplayer(fixture, redactor)}
+
+	// then
+	for idx, send := range requests {
+		resp, err := send(replay)
+		got := dump(t, resp, err)
+		assert.Equal(t, redactor.String(recorded[idx]), got, "response of request %d", idx)
+	}
+	assert.EqualValues(t, len(requests), atomic.LoadInt32(&calls))
+
+	for _, i := range fixture.Interactions {
+		assert.NotContains(t, i.URL, secret)
+		assert.NotContains(t, i.Body, secret)
+		assert.NotContains(t, i.Header.Get("X-Echo"), secret)
+		assert.Empty(t, i.Header.Get("Set-Cookie"))
+	}
+
+	_, err = replay.Get(srv.URL + "/unknown")
+	assert.True(t, errors.Is(err, recording.ErrNotRecorded), "expected not recorded error, got %v", err)
+}
+
+func TestLoadFixtureUnsupportedVersion(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "recording.json")
+	require.NoError(t, (&recording.Fixture{Version: 99}).Save(path))
+
+	// when
+	_, err := recording.LoadFixture(path)
+
+	// then
+	assert.ErrorContains(t, err, "unsupported version 99")
+}
+
+func dump(t *testing.T, resp *http.Response, err error) string {
+	t.Helper()
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.Status + "\n" + resp.Header.Get("X-Echo") + "\n" + string(body)
+}
@@ -0,0 +1,90 @@
+package redact
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formatter scrubs secrets from log entries formatted by the underlying formatter,
+// including the message, fields, and wrapped errors.
+type Formatter struct {
+	inner    logrus.Formatter
+	redactor *Redactor
+}
+
+// NewFormatter returns a formatter which scrubs output of a given formatter using the Default redactor.
+func NewFormatter(inner logrus.Formatter) *Formatter {
+	return &Formatter{inner: inner, redactor: Default}
+}
+
+// WithRedactor sets the redactor used to scrub the log entries.
+func (f *Formatter) WithRedactor(r *Redactor) *Formatter {
+	f.redactor = r
+	return f
+}
+
+// Format formats the log entry and scrubs secrets from the output.
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	out, err := f.inner.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(f.redactor.String(string(out))), nil
+}
+
+// NewLogger returns a logger which scrubs secrets from all log levels.
+func NewLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetFormatter(NewFormatter(log.Formatter))
+	return log
+}
+
+// WrapLogger returns a logger which scrubs secrets from log entries using a given redactor and passes them
+// to a given logger, e.g. injected by a program, which is used for formatting and writing the entries.
+func WrapLogger(inner logrus.FieldLogger, r *Redactor) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	// the inner logger filters entries by its own level
+	log.SetLevel(logrus.TraceLevel)
+	log.AddHook(&forwardHook{inner: inner, redactor: r})
+	return log
+}
+
+// forwardHook passes scrubbed log entries to the inner logger.
+type forwardHook struct {
+	inner    logrus.FieldLogger
+	redactor *Redactor
+}
+
+func (h *forwardHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *forwardHook) Fire(entry *logrus.Entry) error {
+	fields := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		switch val := v.(type) {
+		case string:
+			v = h.redactor.String(val)
+		case error, fmt.Stringer:
+			v = h.redactor.String(fmt.Sprint(val))
+		}
+		fields[k] = v
+	}
+
+	log, msg := h.inner.WithFields(fields), h.redactor.String(entry.Message)
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		// the wrapping logger panics or exits itself
+		log.Error(msg)
+	case logrus.WarnLevel:
+		log.Warn(msg)
+	case logrus.InfoLevel:
+		log.Info(msg)
+	default:
+		log.Debug(msg)
+	}
+	return nil
+}
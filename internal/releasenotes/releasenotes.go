@@ -0,0 +1,175 @@
+// Package releasenotes attributes changes of a release to the owners of the changed files, so release managers
+// get a per-team changelog skeleton instead of sorting commits manually.
+package releasenotes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/ownershipdiff"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// shortSHALength is the length of commit SHAs printed in the Markdown changelog.
+const shortSHALength = 7
+
+// Notes attribute commits of a revision range to owners.
+type Notes struct {
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Sections []Section `json:"sections"`
+	// Unowned lists commits which changed files without owners. It's nil if all changed files are owned.
+	Unowned *Section `json:"unowned,omitempty"`
+}
+
+// Section lists changes owned by a single owner.
+type Section struct {
+	// Owner is empty for the unowned section.
+	Owner   string   `json:"owner,omitempty"`
+	Commits []Change `json:"commits"`
+	// Files are sorted files changed by the commits and owned by the owner.
+	Files []string `json:"files"`
+}
+
+// Change is a commit attributed to the owner.
+type Change struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+}
+
+// Attribute resolves owners of files changed by each commit between two revisions. Files are resolved
+// with the CODEOWNERS file of the `to` revision, the same as GitHub requests reviews for the released code.
+// A commit is listed in the section of each owner of any file it changed, the newest commit first.
+func Attribute(repoDir, fromRef, toRef string) (Notes, error) {
+	matcher, err := ownershipdiff.MatcherAt(repoDir, toRef)
+	if err != nil {
+		return Notes{}, err
+	}
+
+	commits, err := git.CommitsInRange(repoDir, fromRef+".."+toRef)
+	if err != nil {
+		return Notes{}, errors.Wrapf(err, "while listing commits between %s and %s", fromRef, toRef)
+	}
+
+	return Build(fromRef, toRef, commits, matcher), nil
+}
+
+// Build attributes given commits to owners resolved by the matcher. Owners are compared case-insensitively,
+// as in GitHub, and the spelling of the first occurrence is used. Sections are sorted by the owner.
+func Build(fromRef, toRef string, commits []git.Commit, matcher *codeowners.Matcher) Notes {
+	sections := map[string]*sectionBuilder{}
+	unowned := newSectionBuilder("")
+
+	for _, c := range commits {
+		change := Change{SHA: c.SHA, Subject: c.Subject}
+		for _, f := range c.Files {
+			entry, found := matcher.Match(f)
+			if !found || len(entry.Owners) == 0 {
+				unowned.add(change, f)
+				continue
+			}
+			for _, owner := range entry.Owners {
+				key := strings.ToLower(owner)
+				if sections[key] == nil {
+					sections[key] = newSectionBuilder(owner)
+				}
+				sections[key].add(change, f)
+			}
+		}
+	}
+
+	out := Notes{From: fromRef, To: toRef, Sections: []Section{}}
+	for _, s := range sections {
+		out.Sections = append(out.Sections, s.section())
+	}
+	sort.Slice(out.Sections, func(i, j int) bool {
+		return strings.ToLower(out.Sections[i].Owner) < strings.ToLower(out.Sections[j].Owner)
+	})
+	if len(unowned.commits) > 0 {
+		s := unowned.section()
+		out.Unowned = &s
+	}
+	return out
+}
+
+type sectionBuilder struct {
+	owner   string
+	commits []Change
+	seen    map[string]struct{}
+	files   map[string]struct{}
+}
+
+func newSectionBuilder(owner string) *sectionBuilder {
+	return &sectionBuilder{owner: owner, seen: map[string]struct{}{}, files: map[string]struct{}{}}
+}
+
+func (b *sectionBuilder) add(c Change, file string) {
+	b.files[file] = struct{}{}
+	if _, found := b.seen[c.SHA]; found {
+		return
+	}
+	b.seen[c.SHA] = struct{}{}
+	b.commits = append(b.commits, c)
+}
+
+func (b *sectionBuilder) section() Section {
+	files := make([]string, 0, len(b.files))
+	for f := range b.files {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return Section{Owner: b.owner, Commits: b.commits, Files: files}
+}
+
+// WriteJSON writes the notes as a JSON object.
+func WriteJSON(w io.Writer, notes Notes) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(notes)
+}
+
+// WriteMarkdown writes the changelog skeleton with a section per owner. Each section has a placeholder
+// for the summary written by the owner, the attributed commits, and a collapsed list of changed files.
+func WriteMarkdown(w io.Writer, notes Notes) error {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# Changes between %s and %s\n", notes.From, notes.To)
+
+	sections := notes.Sections
+	if notes.Unowned != nil {
+		sections = append(sections[:len(sections):len(sections)], *notes.Unowned)
+	}
+	if len(sections) == 0 {
+		out.WriteString("\nNo changes.\n")
+	}
+	for _, s := range sections {
+		title := s.Owner
+		if title == "" {
+			title = "Unowned"
+		}
+		fmt.Fprintf(&out, "\n## %s\n\n<!-- TODO: summarize the changes -->\n\n", title)
+		for _, c := range s.Commits {
+			fmt.Fprintf(&out, "- %s %s\n", shortSHA(c.SHA), c.Subject)
+		}
+		fmt.Fprintf(&out, "\n<details>\n<summary>Changed files (%d)</summary>\n\n", len(s.Files))
+		for _, f := range s.Files {
+			fmt.Fprintf(&out, "- `%s`\n", f)
+		}
+		out.WriteString("\n</details>\n")
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > shortSHALength {
+		return sha[:shortSHALength]
+	}
+	return sha
+}
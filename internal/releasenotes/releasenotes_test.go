@@ -0,0 +1,134 @@
+package releasenotes_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/releasenotes"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	// given
+	matcher, err := codeowners.NewMatcher(codeowners.ParseCodeowners(strings.NewReader(`
+/api/   @org/api @alice
+/web/   @org/Web
+/docs/  @org/web
+/vendor/
+`)))
+	require.NoError(t, err)
+
+	commits := []git.Commit{
+		{SHA: "3333333333", Subject: "Update docs and API", Files: []string{"docs/api.md", "api/server.go"}},
+		{SHA: "2222222222", Subject: "Bump vendored lib", Files: []string{"vendor/lib.go", "go.mod"}},
+		{SHA: "1111111111", Subject: "Fix web layout", Files: []string{"web/index.html", "web/style.css"}},
+	}
+
+	// when
+	notes := releasenotes.Build("v1.0.0", "v1.1.0", commits, matcher)
+
+	// then
+	assert.Equal(t, releasenotes.Notes{
+		From: "v1.0.0",
+		To:   "v1.1.0",
+		Sections: []releasenotes.Section{
+			{Owner: "@alice", Commits: []releasenotes.Change{{SHA: "3333333333", Subject: "Update docs and API"}}, Files: []string{"api/server.go"}},
+			{Owner: "@org/api", Commits: []releasenotes.Change{{SHA: "3333333333", Subject: "Update docs and API"}}, Files: []string{"api/server.go"}},
+			{
+				Owner: "@org/web",
+				Commits: []releasenotes.Change{
+					{SHA: "3333333333", Subject: "Update docs and API"},
+					{SHA: "1111111111", Subject: "Fix web layout"},
+				},
+				Files: []string{"docs/api.md", "web/index.html", "web/style.css"},
+			},
+		},
+		Unowned: &releasenotes.Section{
+			Commits: []releasenotes.Change{{SHA: "2222222222", Subject: "Bump vendored lib"}},
+			Files:   []string{"go.mod", "vendor/lib.go"},
+		},
+	}, notes)
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	// given
+	notes := releasenotes.Notes{
+		From: "v1.0.0",
+		To:   "v1.1.0",
+		Sections: []releasenotes.Section{
+			{Owner: "@org/web", Commits: []releasenotes.Change{{SHA: "1111111111", Subject: "Fix web layout"}}, Files: []string{"web/index.html"}},
+		},
+		Unowned: &releasenotes.Section{
+			Commits: []releasenotes.Change{{SHA: "2222222222", Subject: "Bump vendored lib"}},
+			Files:   []string{"go.mod"},
+		},
+	}
+	var buff bytes.Buffer
+
+	// when
+	err := releasenotes.WriteMarkdown(&buff, notes)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "# Changes between v1.0.0 and v1.1.0\n"+`
+## @org/web
+
+<!-- TODO: summarize the changes -->
+
+- 1111111 Fix web layout
+
+<details>
+<summary>Changed files (1)</summary>
+
+- `+"`web/index.html`"+`
+
+</details>
+
+## Unowned
+
+<!-- TODO: summarize the changes -->
+
+- 2222222 Bump vendored lib
+
+<details>
+<summary>Changed files (1)</summary>
+
+- `+"`go.mod`"+`
+
+</details>
+`, buff.String())
+}
+
+func TestAttribute(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+
+	gitrepo.WriteFiles(t, repoDir, map[string]string{"CODEOWNERS": "/api/ @org/old\n", "api/a.go": "package api"})
+	gitrepo.Run(t, repoDir, "add", "-A")
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-m", "initial")
+	gitrepo.Run(t, repoDir, "tag", "v1")
+
+	gitrepo.WriteFiles(t, repoDir, map[string]string{"CODEOWNERS": "/api/ @org/api\n"})
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-am", "transfer API ownership")
+	gitrepo.WriteFiles(t, repoDir, map[string]string{"api/a.go": "package api // changed"})
+	gitrepo.Run(t, repoDir, "commit", "--quiet", "-am", "change API")
+	gitrepo.Run(t, repoDir, "tag", "v2")
+
+	// when
+	notes, err := releasenotes.Attribute(repoDir, "v1", "v2")
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, notes.Sections, 1)
+	assert.Equal(t, "@org/api", notes.Sections[0].Owner)
+	require.Len(t, notes.Sections[0].Commits, 1)
+	assert.Equal(t, "change API", notes.Sections[0].Commits[0].Subject)
+	require.NotNil(t, notes.Unowned)
+	assert.Equal(t, []string{"CODEOWNERS"}, notes.Unowned.Files)
+}
@@ -0,0 +1,162 @@
+package remediation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// Options customizes the remediation report.
+type Options struct {
+	// Since limits the change history taken into account. Zero value means the whole history.
+	Since time.Time
+	// Depth groups unowned files by directories at most Depth levels deep. Zero value groups files by their parent directory.
+	Depth int
+	// Limit is the maximum number of reported directories. Zero value means no limit.
+	Limit int
+	// SkipGenerated excludes files marked as `linguist-generated` or `linguist-vendored` in .gitattributes.
+	SkipGenerated bool
+}
+
+// Offender is a directory with unowned files.
+type Offender struct {
+	// Directory relative to the repository root, `/` for the root directory.
+	Directory string `json:"directory"`
+	// UnownedFiles is the number of unowned files in the directory.
+	UnownedFiles int `json:"unownedFiles"`
+	// Size is the total size of the unowned files in bytes.
+	Size int64 `json:"size"`
+	// Changes is the total number of commits that changed the unowned files.
+	Changes int `json:"changes"`
+}
+
+// TopOffenders ranks directories with unowned files, so teams can prioritize which unowned areas to fix first.
+// Directories are ranked by the change frequency of the unowned files, as frequently changed files benefit
+// the most from automatic review requests, and then by their size.
+func TopOffenders(repoDir string, entries []codeowners.Entry, opts Options) ([]Offender, error) {
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "while compiling CODEOWNERS patterns")
+	}
+
+	files, err := git.ListFiles(repoDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing repository files")
+	}
+	if opts.SkipGenerated {
+		files, err = git.WithoutGenerated(repoDir, files)
+		if err != nil {
+			return nil, errors.Wrap(err, "while excluding generated files")
+		}
+	}
+
+	changes, err := git.ChangeCounts(repoDir, opts.Since)
+	if err != nil {
+		return nil, errors.Wrap(err, "while computing change history")
+	}
+
+	byDir := map[string]*Offender{}
+	for _, f := range files {
+		if entry, found := matcher.Match(f); found && len(entry.Owners) > 0 {
+			continue
+		}
+
+		dir := groupDir(f, opts.Depth)
+		o, found := byDir[dir]
+		if !found {
+			o = &Offender{Directory: dir}
+			byDir[dir] = o
+		}
+
+		o.UnownedFiles++
+		o.Changes += changes[f]
+		// tracked files can be removed from the working tree, they are counted without the size
+		if fi, err := os.Lstat(filepath.Join(repoDir, filepath.FromSlash(f))); err == nil {
+			o.Size += fi.Size()
+		}
+	}
+
+	out := make([]Offender, 0, len(byDir))
+	for _, o := range byDir {
+		out = append(out, *o)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Changes != out[j].Changes {
+			return out[i].Changes > out[j].Changes
+		}
+		if out[i].Size != out[j].Size {
+			return out[i].Size > out[j].Size
+		}
+		return out[i].Directory < out[j].Directory
+	})
+
+	if opts.Limit > 0 && len(out) > opts.Limit {
+		out = out[:opts.Limit]
+	}
+	return out, nil
+}
+
+func groupDir(file string, depth int) string {
+	dir := path.Dir(file)
+	if dir == "." {
+		return "/"
+	}
+	if depth > 0 {
+		if segments := strings.Split(dir, "/"); len(segments) > depth {
+			dir = strings.Join(segments[:depth], "/")
+		}
+	}
+	return "/" + dir + "/"
+}
+
+// WriteJSON writes the offenders as a JSON array.
+func WriteJSON(w io.Writer, offenders []Offender) error {
+	if offenders == nil {
+		offenders = []Offender{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(offenders)
+}
+
+// WriteMarkdown writes the offenders as a Markdown table.
+func WriteMarkdown(w io.Writer, offenders []Offender) error {
+	if len(offenders) == 0 {
+		_, err := fmt.Fprintln(w, "All files have owners.")
+		return err
+	}
+
+	var out strings.Builder
+	out.WriteString("| Rank | Directory | Unowned files | Size | Changes |\n")
+	out.WriteString("|-----:|-----------|--------------:|-----:|--------:|\n")
+	for idx, o := range offenders {
+		fmt.Fprintf(&out, "| %d | `%s` | %d | %s | %d |\n", idx+1, o.Directory, o.UnownedFiles, humanSize(o.Size), o.Changes)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func humanSize(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
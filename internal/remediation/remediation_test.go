@@ -0,0 +1,122 @@
+package remediation_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/remediation"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCODEOWNERS = `
+/docs/      @org/docs
+/legacy/
+`
+
+func TestTopOffenders(t *testing.T) {
+	// given
+	repoDir := prepareRepo(t)
+	entries := codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS))
+
+	tests := map[string]struct {
+		opts         remediation.Options
+		expOffenders []remediation.Offender
+	}{
+		"Group by parent directory": {
+			expOffenders: []remediation.Offender{
+				{Directory: "/svc/api/", UnownedFiles: 2, Size: 9, Changes: 4},
+				{Directory: "/", UnownedFiles: 2, Size: 33, Changes: 2},
+				{Directory: "/legacy/", UnownedFiles: 1, Size: 100, Changes: 1},
+				{Directory: "/svc/worker/", UnownedFiles: 1, Size: 10, Changes: 1},
+			},
+		},
+		"Group by top-level directory": {
+			opts: remediation.Options{Depth: 1},
+			expOffenders: []remediation.Offender{
+				{Directory: "/svc/", UnownedFiles: 3, Size: 19, Changes: 5},
+				{Directory: "/", UnownedFiles: 2, Size: 33, Changes: 2},
+				{Directory: "/legacy/", UnownedFiles: 1, Size: 100, Changes: 1},
+			},
+		},
+		"Skip generated and vendored files": {
+			opts: remediation.Options{SkipGenerated: true},
+			expOffenders: []remediation.Offender{
+				{Directory: "/svc/api/", UnownedFiles: 2, Size: 9, Changes: 4},
+				{Directory: "/", UnownedFiles: 2, Size: 33, Changes: 2},
+				{Directory: "/svc/worker/", UnownedFiles: 1, Size: 10, Changes: 1},
+			},
+		},
+		"Limit": {
+			opts: remediation.Options{Limit: 1},
+			expOffenders: []remediation.Offender{
+				{Directory: "/svc/api/", UnownedFiles: 2, Size: 9, Changes: 4},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			offenders, err := remediation.TopOffenders(repoDir, entries, tc.opts)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOffenders, offenders)
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	// given
+	offenders := []remediation.Offender{
+		{Directory: "/svc/api/", UnownedFiles: 2, Size: 3 << 20, Changes: 4},
+		{Directory: "/", UnownedFiles: 1, Size: 5, Changes: 0},
+	}
+	var buff bytes.Buffer
+
+	// when
+	err := remediation.WriteMarkdown(&buff, offenders)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "| Rank | Directory | Unowned files | Size | Changes |\n"+
+		"|-----:|-----------|--------------:|-----:|--------:|\n"+
+		"| 1 | `/svc/api/` | 2 | 3.0 MiB | 4 |\n"+
+		"| 2 | `/` | 1 | 5 B | 0 |\n", buff.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	// given
+	var buff bytes.Buffer
+
+	// when
+	err := remediation.WriteJSON(&buff, []remediation.Offender{{Directory: "/svc/", UnownedFiles: 1, Size: 2, Changes: 3}})
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"directory": "/svc/", "unownedFiles": 1, "size": 2, "changes": 3}]`, buff.String())
+}
+
+func prepareRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := gitrepo.New(t)
+
+	gitrepo.CommitFiles(t, dir, map[string]string{
+		"README":               "hello",
+		".gitattributes":       "legacy/** linguist-vendored\n",
+		"docs/index.md":        "docs",
+		"legacy/big.bin":       strings.Repeat("x", 100),
+		"svc/api/main.go":      "v1",
+		"svc/api/handler.go":   "handler",
+		"svc/worker/worker.go": "worker-v1!",
+	})
+	gitrepo.CommitFiles(t, dir, map[string]string{"svc/api/main.go": "v2"})
+	gitrepo.CommitFiles(t, dir, map[string]string{"svc/api/main.go": "v3", "docs/index.md": "docs v2"})
+
+	return dir
+}
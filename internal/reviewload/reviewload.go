@@ -0,0 +1,227 @@
+package reviewload
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBottleneckFactor is the default ratio of the owner review requests to the average ones
+// above which the owner is reported as a bottleneck.
+const DefaultBottleneckFactor = 2.0
+
+// Options customizes the review load analysis.
+type Options struct {
+	// Since limits the change history taken into account. Zero value means the whole history.
+	Since time.Time
+	// Limit is the maximum number of reported owners. Zero value means no limit.
+	Limit int
+	// SkipGenerated excludes files marked as `linguist-generated` or `linguist-vendored` in .gitattributes.
+	SkipGenerated bool
+	// BottleneckFactor is the ratio of the owner review requests to the average ones above which the owner
+	// is reported as a bottleneck. Zero value means DefaultBottleneckFactor.
+	BottleneckFactor float64
+}
+
+// Report is the estimated review load of all owners.
+type Report struct {
+	// Commits is the number of analyzed commits.
+	Commits int `json:"commits"`
+	// AverageReviewRequests is the average number of review requests per owner.
+	AverageReviewRequests float64 `json:"averageReviewRequests"`
+	// Owners are sorted by the review requests, the most loaded first.
+	Owners []OwnerLoad `json:"owners"`
+}
+
+// OwnerLoad is the estimated review load of a single owner.
+type OwnerLoad struct {
+	Owner string `json:"owner"`
+	// Patterns is the number of CODEOWNERS entries which list the owner.
+	Patterns int `json:"patterns"`
+	// OwnedFiles is the number of tracked files owned by the owner, i.e. the ownership breadth.
+	OwnedFiles int `json:"ownedFiles"`
+	// ReviewRequests is the number of commits that would request a review from the owner.
+	ReviewRequests int `json:"reviewRequests"`
+	// Share is the fraction of the analyzed commits that would request a review from the owner.
+	Share float64 `json:"share"`
+	// Bottleneck is set if the owner gets significantly more review requests than the average owner.
+	Bottleneck bool `json:"bottleneck"`
+}
+
+// Analyze estimates the review load of each owner by combining the ownership breadth with the change
+// frequency. Each commit in the history is treated as a pull request that requests a review from owners
+// of all changed files, according to the current CODEOWNERS rules.
+func Analyze(repoDir string, entries []codeowners.Entry, opts Options) (Report, error) {
+	if opts.BottleneckFactor == 0 {
+		opts.BottleneckFactor = DefaultBottleneckFactor
+	}
+
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "while compiling CODEOWNERS patterns")
+	}
+
+	files, err := git.ListFiles(repoDir)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "while listing repository files")
+	}
+	if opts.SkipGenerated {
+		files, err = git.WithoutGenerated(repoDir, files)
+		if err != nil {
+			return Report{}, errors.Wrap(err, "while excluding generated files")
+		}
+	}
+	tracked := map[string]struct{}{}
+	for _, f := range files {
+		tracked[f] = struct{}{}
+	}
+
+	commits, err := git.CommitFiles(repoDir, opts.Since)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "while computing change history")
+	}
+
+	byOwner := map[string]*OwnerLoad{}
+	load := func(owner string) *OwnerLoad {
+		l, found := byOwner[owner]
+		if !found {
+			l = &OwnerLoad{Owner: owner}
+			byOwner[owner] = l
+		}
+		return l
+	}
+
+	for _, e := range entries {
+		for _, o := range e.Owners {
+			load(o).Patterns++
+		}
+	}
+	for _, f := range files {
+		if entry, found := matcher.Match(f); found {
+			for _, o := range entry.Owners {
+				load(o).OwnedFiles++
+			}
+		}
+	}
+	for _, changed := range commits {
+		requested := map[string]struct{}{}
+		for _, f := range changed {
+			if _, found := tracked[f]; !found {
+				continue // removed since then, or generated
+			}
+			if entry, found := matcher.Match(f); found {
+				for _, o := range entry.Owners {
+					requested[o] = struct{}{}
+				}
+			}
+		}
+		for o := range requested {
+			load(o).ReviewRequests++
+		}
+	}
+
+	out := Report{Commits: len(commits)}
+	total := 0
+	for _, l := range byOwner {
+		total += l.ReviewRequests
+	}
+	if len(byOwner) > 0 {
+		out.AverageReviewRequests = float64(total) / float64(len(byOwner))
+	}
+
+	for _, l := range byOwner {
+		if out.Commits > 0 {
+			l.Share = float64(l.ReviewRequests) / float64(out.Commits)
+		}
+		// a single owner cannot be compared with others
+		l.Bottleneck = len(byOwner) > 1 && l.ReviewRequests > 0 &&
+			float64(l.ReviewRequests) >= opts.BottleneckFactor*out.AverageReviewRequests
+		out.Owners = append(out.Owners, *l)
+	}
+
+	sort.Slice(out.Owners, func(i, j int) bool {
+		a, b := out.Owners[i], out.Owners[j]
+		if a.ReviewRequests != b.ReviewRequests {
+			return a.ReviewRequests > b.ReviewRequests
+		}
+		if a.OwnedFiles != b.OwnedFiles {
+			return a.OwnedFiles > b.OwnedFiles
+		}
+		return a.Owner < b.Owner
+	})
+	if opts.Limit > 0 && len(out.Owners) > opts.Limit {
+		out.Owners = out.Owners[:opts.Limit]
+	}
+
+	return out, nil
+}
+
+// WriteJSON writes the report as a JSON object.
+func WriteJSON(w io.Writer, r Report) error {
+	if r.Owners == nil {
+		r.Owners = []OwnerLoad{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report as a Markdown table with bottlenecks marked in bold.
+func WriteMarkdown(w io.Writer, r Report) error {
+	if len(r.Owners) == 0 {
+		_, err := fmt.Fprintln(w, "No owners found.")
+		return err
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Review load estimated from %d commits, %.1f review requests per owner on average.\n\n", r.Commits, r.AverageReviewRequests)
+	out.WriteString("| Owner | Review requests | Share | Owned files | Patterns | Bottleneck |\n")
+	out.WriteString("|-------|----------------:|------:|------------:|---------:|:----------:|\n")
+	for _, l := range r.Owners {
+		owner, bottleneck := fmt.Sprintf("`%s`", l.Owner), ""
+		if l.Bottleneck {
+			owner, bottleneck = fmt.Sprintf("**`%s`**", l.Owner), "⚠️"
+		}
+		fmt.Fprintf(&out, "| %s | %d | %.0f%% | %d | %d | %s |\n", owner, l.ReviewRequests, l.Share*100, l.OwnedFiles, l.Patterns, bottleneck)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// WriteStats writes the report as an aligned plain text table.
+func WriteStats(w io.Writer, r Report) error {
+	fmt.Fprintf(w, "Commits analyzed:          %d\n", r.Commits)
+	fmt.Fprintf(w, "Average review requests:   %.1f\n", r.AverageReviewRequests)
+	fmt.Fprintf(w, "Bottlenecks:               %d\n\n", countBottlenecks(r.Owners))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OWNER\tREVIEW REQUESTS\tSHARE\tOWNED FILES\tPATTERNS\t")
+	for _, l := range r.Owners {
+		owner := l.Owner
+		if l.Bottleneck {
+			owner += " (bottleneck)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.0f%%\t%d\t%d\t\n", owner, l.ReviewRequests, l.Share*100, l.OwnedFiles, l.Patterns)
+	}
+	return tw.Flush()
+}
+
+func countBottlenecks(owners []OwnerLoad) int {
+	n := 0
+	for _, l := range owners {
+		if l.Bottleneck {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,174 @@
+package reviewload_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/reviewload"
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCODEOWNERS = `
+*          @org/core
+/docs/     @org/docs
+/svc/api/  @org/api @alice
+`
+
+func TestAnalyze(t *testing.T) {
+	// given
+	repoDir := prepareRepo(t)
+	entries := codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS))
+
+	tests := map[string]struct {
+		opts      reviewload.Options
+		expReport reviewload.Report
+	}{
+		"No bottlenecks with default factor": {
+			expReport: reviewload.Report{
+				Commits:               4,
+				AverageReviewRequests: 2.75,
+				Owners: []reviewload.OwnerLoad{
+					{Owner: "@alice", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1},
+					{Owner: "@org/api", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1},
+					{Owner: "@org/docs", Patterns: 1, OwnedFiles: 1, ReviewRequests: 2, Share: 0.5},
+					{Owner: "@org/core", Patterns: 1, OwnedFiles: 3, ReviewRequests: 1, Share: 0.25},
+				},
+			},
+		},
+		"Bottlenecks with lower factor": {
+			opts: reviewload.Options{BottleneckFactor: 1.4},
+			expReport: reviewload.Report{
+				Commits:               4,
+				AverageReviewRequests: 2.75,
+				Owners: []reviewload.OwnerLoad{
+					{Owner: "@alice", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1, Bottleneck: true},
+					{Owner: "@org/api", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1, Bottleneck: true},
+					{Owner: "@org/docs", Patterns: 1, OwnedFiles: 1, ReviewRequests: 2, Share: 0.5},
+					{Owner: "@org/core", Patterns: 1, OwnedFiles: 3, ReviewRequests: 1, Share: 0.25},
+				},
+			},
+		},
+		"Skip generated files": {
+			opts: reviewload.Options{SkipGenerated: true},
+			expReport: reviewload.Report{
+				Commits:               4,
+				AverageReviewRequests: 2.25,
+				Owners: []reviewload.OwnerLoad{
+					{Owner: "@alice", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1},
+					{Owner: "@org/api", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1},
+					{Owner: "@org/core", Patterns: 1, OwnedFiles: 3, ReviewRequests: 1, Share: 0.25},
+					{Owner: "@org/docs", Patterns: 1, OwnedFiles: 0, ReviewRequests: 0, Share: 0},
+				},
+			},
+		},
+		"Limit": {
+			opts: reviewload.Options{Limit: 1},
+			expReport: reviewload.Report{
+				Commits:               4,
+				AverageReviewRequests: 2.75,
+				Owners: []reviewload.OwnerLoad{
+					{Owner: "@alice", Patterns: 1, OwnedFiles: 2, ReviewRequests: 4, Share: 1},
+				},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			report, err := reviewload.Analyze(repoDir, entries, tc.opts)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expReport, report)
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	// given
+	report := reviewload.Report{
+		Commits:               10,
+		AverageReviewRequests: 4.5,
+		Owners: []reviewload.OwnerLoad{
+			{Owner: "@org/api", Patterns: 2, OwnedFiles: 40, ReviewRequests: 8, Share: 0.8, Bottleneck: true},
+			{Owner: "@org/docs", Patterns: 1, OwnedFiles: 3, ReviewRequests: 1, Share: 0.1},
+		},
+	}
+	var buff bytes.Buffer
+
+	// when
+	err := reviewload.WriteMarkdown(&buff, report)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Review load estimated from 10 commits, 4.5 review requests per owner on average.\n\n"+
+		"| Owner | Review requests | Share | Owned files | Patterns | Bottleneck |\n"+
+		"|-------|----------------:|------:|------------:|---------:|:----------:|\n"+
+		"| **`@org/api`** | 8 | 80% | 40 | 2 | ⚠️ |\n"+
+		"| `@org/docs` | 1 | 10% | 3 | 1 |  |\n", buff.String())
+}
+
+func TestWriteStats(t *testing.T) {
+	// given
+	report := reviewload.Report{
+		Commits:               10,
+		AverageReviewRequests: 4.5,
+		Owners: []reviewload.OwnerLoad{
+			{Owner: "@org/api", Patterns: 2, OwnedFiles: 40, ReviewRequests: 8, Share: 0.8, Bottleneck: true},
+			{Owner: "@org/docs", Patterns: 1, OwnedFiles: 3, ReviewRequests: 1, Share: 0.1},
+		},
+	}
+	var buff bytes.Buffer
+
+	// when
+	err := reviewload.WriteStats(&buff, report)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Commits analyzed:          10\n"+
+		"Average review requests:   4.5\n"+
+		"Bottlenecks:               1\n\n"+
+		"OWNER                  REVIEW REQUESTS  SHARE  OWNED FILES  PATTERNS  \n"+
+		"@org/api (bottleneck)  8                80%    40           2         \n"+
+		"@org/docs              1                10%    3            1         \n", buff.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	// given
+	var buff bytes.Buffer
+
+	// when
+	err := reviewload.WriteJSON(&buff, reviewload.Report{Commits: 2, AverageReviewRequests: 2, Owners: []reviewload.OwnerLoad{
+		{Owner: "@org/api", Patterns: 1, OwnedFiles: 3, ReviewRequests: 2, Share: 1},
+	}})
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"commits": 2, "averageReviewRequests": 2, "owners": [
+		{"owner": "@org/api", "patterns": 1, "ownedFiles": 3, "reviewRequests": 2, "share": 1, "bottleneck": false}
+	]}`, buff.String())
+}
+
+func prepareRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := gitrepo.New(t)
+
+	gitrepo.CommitFiles(t, dir, map[string]string{
+		"README":               "hello",
+		".gitattributes":       "docs/** linguist-generated\n",
+		"docs/index.md":        "docs",
+		"svc/api/main.go":      "v1",
+		"svc/worker/worker.go": "worker",
+	})
+	gitrepo.CommitFiles(t, dir, map[string]string{"svc/api/main.go": "v2"})
+	gitrepo.CommitFiles(t, dir, map[string]string{"svc/api/main.go": "v3", "docs/index.md": "docs v2"})
+	gitrepo.CommitFiles(t, dir, map[string]string{"svc/api/handler.go": "handler"})
+
+	return dir
+}
@@ -5,8 +5,10 @@ import (
 	"sync"
 	"time"
 
-	"go.szostok.io/codeowners/internal/api"
+	"go.szostok.io/codeowners/internal/baseline"
 	"go.szostok.io/codeowners/internal/printer"
+	"go.szostok.io/codeowners/internal/usage"
+	"go.szostok.io/codeowners/pkg/api"
 	"go.szostok.io/codeowners/pkg/codeowners"
 
 	"github.com/sirupsen/logrus"
@@ -17,6 +19,24 @@ const (
 	MaxUint = ^uint(0)
 	// MaxInt defines the max signed int value.
 	MaxInt = int(MaxUint >> 1)
+
+	// maxCheckAttempts is the number of times a check is executed if it fails with a retryable error.
+	maxCheckAttempts = 3
+	// retryBackoff is multiplied by the attempt number to get the delay before the next attempt.
+	retryBackoff = time.Second
+	// maxRetryAfter is the longest delay requested by the provider which the check waits for before the next attempt.
+	maxRetryAfter = time.Minute
+)
+
+// Exit codes returned by the CheckRunner.ExitCode.
+const (
+	// ExitCodeOK means that no failures were found.
+	ExitCodeOK = 0
+	// ExitCodeCheckFailure means that issues with the failure severity were found, or a check failed permanently.
+	ExitCodeCheckFailure = 3
+	// ExitCodeRetryableError means that a check could not be completed because of a temporary error,
+	// e.g. the provider API was unavailable, and it is worth to retry the validation.
+	ExitCodeRetryableError = 4
 )
 
 // Printer prints the checks results
@@ -25,6 +45,31 @@ type Printer interface {
 	PrintSummary(allCheck int, failedChecks int)
 }
 
+// ProgressPrinter is implemented by printers which render the progress of running checks.
+type ProgressPrinter interface {
+	PrintCheckStarted(checkName string)
+}
+
+// EventPrinter is implemented by printers which emit machine-readable events of the checks execution
+// in real time, in addition to the Printer output.
+type EventPrinter interface {
+	Printer
+	PrintCheckRunning(checkName string)
+}
+
+// StatsPrinter is implemented by printers which report the wall time and the external API budget
+// used by each check.
+type StatsPrinter interface {
+	PrintStats(stats []usage.CheckStats)
+}
+
+// CheckResult is the result of a single executed check, with severities already resolved.
+type CheckResult struct {
+	Check  string
+	Output api.Output
+	Err    error
+}
+
 // CheckRunner runs all registered checks in parallel.
 // Needs to be initialized via NewCheckRunner func.
 type CheckRunner struct {
@@ -32,11 +77,24 @@ type CheckRunner struct {
 	log                logrus.FieldLogger
 	codeowners         []codeowners.Entry
 	repoPath           string
+	diffBaseRef        string
+	tree               api.Tree
+	nonGit             bool
+	scope              *api.Scope
+	baseline           *baseline.Baseline
+	reportBaselined    bool
+	escalatePaths      []string
 	treatedAsFailure   api.SeverityType
 	checks             []api.Checker
 	printer            Printer
+	events             EventPrinter
+	verboseSummary     bool
+	stats              map[string]usage.CheckStats
+	results            map[string]CheckResult
 	allFoundIssues     map[api.SeverityType]uint32
 	notPassedChecksCnt int
+	retryableErrCnt    int
+	baselinedCnt       int
 }
 
 // NewCheckRunner is a constructor for CheckRunner
@@ -48,35 +106,212 @@ func NewCheckRunner(log logrus.FieldLogger, co []codeowners.Entry, repoPath stri
 		codeowners:       co,
 		checks:           checks,
 
-		printer:        &printer.TTYPrinter{},
+		printer:        printer.NewProgressPrinter(),
+		stats:          map[string]usage.CheckStats{},
+		results:        map[string]CheckResult{},
 		allFoundIssues: map[api.SeverityType]uint32{},
 	}
 }
 
+// WithPrinter overrides the default TTY printer.
+func (r *CheckRunner) WithPrinter(p Printer) *CheckRunner {
+	r.printer = p
+	return r
+}
+
+// WithDiffBaseRef provides checks with files changed between the merge base of a given reference and HEAD.
+func (r *CheckRunner) WithDiffBaseRef(ref string) *CheckRunner {
+	r.diffBaseRef = ref
+	return r
+}
+
+// WithTree lists repository files with a given tree instead of git.
+func (r *CheckRunner) WithTree(t api.Tree) *CheckRunner {
+	r.tree = t
+	return r
+}
+
+// WithNonGit tells checks that the repository is not managed by git.
+func (r *CheckRunner) WithNonGit() *CheckRunner {
+	r.nonGit = true
+	return r
+}
+
+// WithScope limits files considered by checks to a given scope.
+func (r *CheckRunner) WithScope(s *api.Scope) *CheckRunner {
+	r.scope = s
+	return r
+}
+
+// WithBaseline suppresses issues recorded in a given baseline, so only new issues are reported and fail the validation.
+func (r *CheckRunner) WithBaseline(b *baseline.Baseline) *CheckRunner {
+	r.baseline = b
+	return r
+}
+
+// WithFailOnNewOnly reports issues recorded in the baseline too, marked as baselined, but only new issues
+// fail the validation.
+func (r *CheckRunner) WithFailOnNewOnly() *CheckRunner {
+	r.reportBaselined = true
+	return r
+}
+
+// WithEscalatedPaths reports issues of entries which patterns touch any of given paths as errors,
+// regardless of the severity reported by checks.
+func (r *CheckRunner) WithEscalatedPaths(paths []string) *CheckRunner {
+	r.escalatePaths = paths
+	return r
+}
+
+// WithEvents enables emitting events of the checks execution.
+func (r *CheckRunner) WithEvents(p EventPrinter) *CheckRunner {
+	r.events = p
+	return r
+}
+
+// WithVerboseSummary prints the wall time, external API calls, and cache hit rate of each check
+// before the summary. The events always include them.
+func (r *CheckRunner) WithVerboseSummary() *CheckRunner {
+	r.verboseSummary = true
+	return r
+}
+
 // Run executes given test in a loop with given throttle
 func (r *CheckRunner) Run(ctx context.Context) {
 	wg := sync.WaitGroup{}
 
+	if p, ok := r.printer.(ProgressPrinter); ok {
+		for _, c := range r.checks {
+			p.PrintCheckStarted(c.Name())
+		}
+	}
+
+	// the analysis is shared by all checks, so the expensive work, such as listing repository files
+	// or computing the diff, is done only once per run
+	in := api.Input{
+		CodeownersEntries: r.codeowners,
+		RepoDir:           r.repoPath,
+		DiffBaseRef:       r.diffBaseRef,
+		Analysis:          api.NewAnalysis(r.codeowners),
+		Tree:              r.tree,
+		NonGit:            r.nonGit,
+		Scope:             r.scope,
+	}
+	if r.baseline != nil {
+		in.Baseline = r.baseline
+	}
+	severity := newSeverityResolver(r.codeowners, r.escalatePaths)
+	fingerprints := baseline.NewFingerprinter(r.codeowners)
+
 	// TODO(mszostok): timeout per check?
 	wg.Add(len(r.checks))
 	for _, c := range r.checks {
 		go func(c api.Checker) {
 			defer wg.Done()
+			if r.events != nil {
+				r.events.PrintCheckRunning(c.Name())
+			}
+
+			counters := &usage.Counters{}
 			startTime := time.Now()
-			out, err := c.Check(ctx, api.Input{
-				CodeownersEntries: r.codeowners,
-				RepoDir:           r.repoPath,
-			})
+			out, err := r.runCheck(usage.WithCounters(ctx, counters), c, in)
+			duration := time.Since(startTime)
+			out, fresh := r.applyBaseline(fingerprints, c.Name(), severity.Resolve(out))
 
-			r.collectMetrics(out, err)
-			r.printer.PrintCheckResult(c.Name(), time.Since(startTime), out, err)
+			r.collectMetrics(fresh, err)
+			r.collectStats(counters.Stats(c.Name(), duration))
+			r.collectResult(CheckResult{Check: c.Name(), Output: out, Err: err})
+			r.printer.PrintCheckResult(c.Name(), duration, out, err)
+			if r.events != nil {
+				r.events.PrintCheckResult(c.Name(), duration, out, err)
+			}
 		}(c)
 	}
 	wg.Wait()
 
+	if r.baselinedCnt > 0 && !r.reportBaselined {
+		r.log.Infof("Suppressed %d issues recorded in the baseline", r.baselinedCnt)
+	}
+
+	stats := r.Stats()
+	if p, ok := r.printer.(StatsPrinter); ok && r.verboseSummary {
+		p.PrintStats(stats)
+	}
+	if p, ok := r.events.(StatsPrinter); ok {
+		p.PrintStats(stats)
+	}
+
 	r.printer.PrintSummary(len(r.checks), r.notPassedChecksCnt)
+	if r.events != nil {
+		r.events.PrintSummary(len(r.checks), r.notPassedChecksCnt)
+	}
 }
 
+// applyBaseline returns issues to report and new issues, which are not recorded in the baseline. Known issues
+// are reported only if the baselined issues are reported too, see WithFailOnNewOnly.
+func (r *CheckRunner) applyBaseline(fingerprints *baseline.Fingerprinter, check string, out api.Output) (api.Output, api.Output) {
+	if r.baseline == nil {
+		return out, out
+	}
+
+	var reported, fresh api.Output
+	for _, i := range out.Issues {
+		if !r.baseline.Contains(fingerprints.Findings(check, i)) {
+			reported.Issues = append(reported.Issues, i)
+			fresh.Issues = append(fresh.Issues, i)
+			continue
+		}
+
+		r.m.Lock()
+		r.baselinedCnt++
+		r.m.Unlock()
+		if r.reportBaselined {
+			i.Message = "(baselined) " + i.Message
+			reported.Issues = append(reported.Issues, i)
+		}
+	}
+	return reported, fresh
+}
+
+// runCheck executes the check and retries it if it fails with a retryable error.
+func (r *CheckRunner) runCheck(ctx context.Context, c api.Checker, in api.Input) (api.Output, error) {
+	for attempt := 1; ; attempt++ {
+		out, err := c.Check(ctx, in)
+		if err == nil || !api.IsRetryable(err) || attempt == maxCheckAttempts {
+			return out, err
+		}
+
+		delay := retryBackoff * time.Duration(attempt)
+		if retryAfter := api.RetryAfter(err); retryAfter > delay {
+			if retryAfter > maxRetryAfter {
+				// e.g. the hourly rate limit is reset later, waiting would only block the other checks
+				return out, err
+			}
+			delay = retryAfter
+		}
+
+		r.log.WithError(err).Warnf("Check %q failed with a temporary error, retrying in %s (%d/%d)", c.Name(), delay, attempt, maxCheckAttempts-1)
+		select {
+		case <-ctx.Done():
+			return out, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// ExitCode returns the application exit code for the results of all executed checks.
+func (r *CheckRunner) ExitCode() int {
+	switch {
+	case r.ShouldExitWithCheckFailure():
+		return ExitCodeCheckFailure
+	case r.retryableErrCnt > 0:
+		return ExitCodeRetryableError
+	default:
+		return ExitCodeOK
+	}
+}
+
+// ShouldExitWithCheckFailure returns true if issues with the failure severity were found, or a check failed permanently.
 func (r *CheckRunner) ShouldExitWithCheckFailure() bool {
 	higherOccurredIssue := api.SeverityType(MaxInt)
 	for key := range r.allFoundIssues {
@@ -88,6 +323,61 @@ func (r *CheckRunner) ShouldExitWithCheckFailure() bool {
 	return higherOccurredIssue <= r.treatedAsFailure
 }
 
+// Stats returns the usage of executed checks in the order in which they were registered.
+func (r *CheckRunner) Stats() []usage.CheckStats {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make([]usage.CheckStats, 0, len(r.stats))
+	for _, c := range r.checks {
+		if s, found := r.stats[c.Name()]; found {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Results returns results of executed checks in the order in which they were registered.
+func (r *CheckRunner) Results() []CheckResult {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make([]CheckResult, 0, len(r.results))
+	for _, c := range r.checks {
+		if res, found := r.results[c.Name()]; found {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Findings returns findings of issues reported by executed checks, e.g. to record them in the baseline.
+// Issues marked as baselined by WithFailOnNewOnly are not known by the baseline, so it shouldn't be used
+// together with Findings.
+func (r *CheckRunner) Findings() []baseline.Finding {
+	fingerprints := baseline.NewFingerprinter(r.codeowners)
+
+	var out []baseline.Finding
+	for _, res := range r.Results() {
+		for _, i := range res.Output.Issues {
+			out = append(out, fingerprints.Findings(res.Check, i)...)
+		}
+	}
+	return out
+}
+
+func (r *CheckRunner) collectResult(res CheckResult) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.results[res.Check] = res
+}
+
+func (r *CheckRunner) collectStats(stats usage.CheckStats) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.stats[stats.Check] = stats
+}
+
 func (r *CheckRunner) collectMetrics(checkOut api.Output, err error) {
 	r.m.Lock()
 	defer r.m.Unlock()
@@ -95,7 +385,10 @@ func (r *CheckRunner) collectMetrics(checkOut api.Output, err error) {
 		r.allFoundIssues[i.Severity]++
 	}
 
-	if err != nil {
+	switch {
+	case api.IsRetryable(err):
+		r.retryableErrCnt++
+	case err != nil:
 		r.allFoundIssues[api.Error]++
 	}
 
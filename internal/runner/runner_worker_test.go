@@ -0,0 +1,117 @@
+package runner_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/baseline"
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRunnerResults(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader("* @org/all\n"))
+	sut := runner.NewCheckRunner(logrus.New(), entries, t.TempDir(), api.Error, warnEachLine{}).
+		WithPrinter(&recordingPrinter{})
+
+	// when
+	sut.Run(context.Background())
+
+	// then
+	assert.Equal(t, []runner.CheckResult{
+		{
+			Check: "Warn Each Line",
+			Output: api.Output{Issues: []api.Issue{
+				{Severity: api.Warning, LineNo: ptr.Uint64Ptr(1), Message: "*"},
+				{Severity: api.Warning, Message: "not reported for any line"},
+			}},
+		},
+	}, sut.Results())
+	assert.Equal(t, runner.ExitCodeOK, sut.ExitCode())
+}
+
+func TestCheckRunnerBaseline(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader("* @org/all\n/docs/ @org/docs\n"))
+	fingerprints := baseline.NewFingerprinter(entries)
+	known := append(
+		fingerprints.Findings("Warn Each Line", api.Issue{LineNo: ptr.Uint64Ptr(1), Message: "*"}),
+		fingerprints.Findings("Warn Each Line", api.Issue{Message: "not reported for any line"})...,
+	)
+	path := filepath.Join(t.TempDir(), baseline.DefaultFilename)
+	require.NoError(t, baseline.NewFile(known).Save(path))
+	b, err := baseline.Load(path)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		failOnNewOnly bool
+		expIssues     []api.Issue
+	}{
+		"Suppresses known issues": {
+			expIssues: []api.Issue{
+				{Severity: api.Warning, LineNo: ptr.Uint64Ptr(2), Message: "/docs/"},
+			},
+		},
+		"Reports known issues which don't fail": {
+			failOnNewOnly: true,
+			expIssues: []api.Issue{
+				{Severity: api.Warning, LineNo: ptr.Uint64Ptr(1), Message: "(baselined) *"},
+				{Severity: api.Warning, LineNo: ptr.Uint64Ptr(2), Message: "/docs/"},
+				{Severity: api.Warning, Message: "(baselined) not reported for any line"},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			printer := &recordingPrinter{}
+			sut := runner.NewCheckRunner(logrus.New(), entries, t.TempDir(), api.Warning, warnEachLine{}).
+				WithPrinter(printer).
+				WithBaseline(b)
+			if tc.failOnNewOnly {
+				sut.WithFailOnNewOnly()
+			}
+
+			// when
+			sut.Run(context.Background())
+
+			// then
+			assert.Equal(t, tc.expIssues, printer.issues)
+			assert.Equal(t, runner.ExitCodeCheckFailure, sut.ExitCode())
+		})
+	}
+}
+
+func TestCheckRunnerBaselineWithoutNewIssues(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader("* @org/all\n"))
+	fingerprints := baseline.NewFingerprinter(entries)
+	known := append(
+		fingerprints.Findings("Warn Each Line", api.Issue{LineNo: ptr.Uint64Ptr(1), Message: "*"}),
+		fingerprints.Findings("Warn Each Line", api.Issue{Message: "not reported for any line"})...,
+	)
+	path := filepath.Join(t.TempDir(), baseline.DefaultFilename)
+	require.NoError(t, baseline.NewFile(known).Save(path))
+	b, err := baseline.Load(path)
+	require.NoError(t, err)
+
+	sut := runner.NewCheckRunner(logrus.New(), entries, t.TempDir(), api.Warning, warnEachLine{}).
+		WithPrinter(&recordingPrinter{}).
+		WithBaseline(b).
+		WithFailOnNewOnly()
+
+	// when
+	sut.Run(context.Background())
+
+	// then
+	assert.Equal(t, runner.ExitCodeOK, sut.ExitCode())
+	assert.Len(t, sut.Results()[0].Output.Issues, 2)
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// severityResolver resolves the final severity of reported issues.
+type severityResolver struct {
+	// escalated are literal path prefixes of paths which issues are always reported as errors.
+	escalated []string
+	byLineNo  map[uint64]codeowners.Entry
+}
+
+func newSeverityResolver(entries []codeowners.Entry, escalatePaths []string) *severityResolver {
+	r := &severityResolver{byLineNo: map[uint64]codeowners.Entry{}}
+	for _, p := range escalatePaths {
+		// escalated paths are always relative to the repository root
+		r.escalated = append(r.escalated, literalPrefix("/"+strings.TrimPrefix(p, "/")))
+	}
+	for _, e := range entries {
+		r.byLineNo[e.LineNo] = e
+	}
+	return r
+}
+
+// Resolve escalates issues reported for entries which patterns touch any of the escalated paths to errors.
+// Issues which are not reported for a specific entry are left unchanged.
+func (r *severityResolver) Resolve(out api.Output) api.Output {
+	if len(r.escalated) == 0 {
+		return out
+	}
+
+	issues := make([]api.Issue, 0, len(out.Issues))
+	for _, i := range out.Issues {
+		if i.LineNo != nil && r.touchesEscalated(*i.LineNo) {
+			i.Severity = api.Error
+		}
+		issues = append(issues, i)
+	}
+	out.Issues = issues
+	return out
+}
+
+func (r *severityResolver) touchesEscalated(lineNo uint64) bool {
+	e, found := r.byLineNo[lineNo]
+	if !found {
+		return false
+	}
+
+	prefix := literalPrefix(e.Pattern)
+	for _, escalated := range r.escalated {
+		if isPathPrefix(prefix, escalated) || isPathPrefix(escalated, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// literalPrefix returns the leading directories of the pattern which don't contain wildcards, e.g. `security/keys`
+// for `/security/keys/*.pem`. Patterns which can match at any depth, such as `*.pem` or `docs/`, return an empty
+// prefix, as they may match files in any directory.
+func literalPrefix(pattern string) string {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(trimmed, "/") {
+		return ""
+	}
+
+	var out []string
+	for _, segment := range strings.Split(strings.TrimPrefix(trimmed, "/"), "/") {
+		if strings.ContainsAny(segment, "*?[\\") {
+			break
+		}
+		out = append(out, segment)
+	}
+	return strings.Join(out, "/")
+}
+
+// isPathPrefix returns true if the prefix is equal to the path, or is one of its parent directories.
+func isPathPrefix(prefix, path string) bool {
+	return prefix == "" || prefix == path || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,76 @@
+package runner_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// warnEachLine reports a warning for each CODEOWNERS entry.
+type warnEachLine struct{}
+
+func (warnEachLine) Check(_ context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+	for _, e := range in.CodeownersEntries {
+		bldr.ReportIssue(e.Pattern, api.WithEntry(e), api.WithSeverity(api.Warning))
+	}
+	bldr.ReportIssue("not reported for any line", api.WithSeverity(api.Warning))
+	return bldr.Output(), nil
+}
+
+func (warnEachLine) Name() string { return "Warn Each Line" }
+
+type recordingPrinter struct {
+	issues []api.Issue
+}
+
+func (p *recordingPrinter) PrintCheckResult(_ string, _ time.Duration, out api.Output, _ error) {
+	p.issues = append(p.issues, out.Issues...)
+}
+
+func (*recordingPrinter) PrintSummary(int, int) {}
+
+func TestCheckRunnerEscalatedPaths(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader(`
+*                      @org/all
+*.pem                  @org/security
+/security/             @org/security
+/security/keys/*.pem   @org/security
+/securityx/            @org/other
+/docs/**/*.md          @org/docs
+/src/**                @org/dev
+`))
+	p := &recordingPrinter{}
+	sut := runner.NewCheckRunner(logrus.New(), entries, t.TempDir(), api.Error, warnEachLine{}).
+		WithPrinter(p).
+		WithEscalatedPaths([]string{"security/**", "/src/internal/"})
+
+	// when
+	sut.Run(context.Background())
+
+	// then
+	severities := map[string]api.SeverityType{}
+	for _, i := range p.issues {
+		severities[i.Message] = i.Severity
+	}
+	assert.Equal(t, map[string]api.SeverityType{
+		"*":                         api.Error,
+		"*.pem":                     api.Error,
+		"/security/":                api.Error,
+		"/security/keys/*.pem":      api.Error,
+		"/securityx/":               api.Warning,
+		"/docs/**/*.md":             api.Warning,
+		"/src/**":                   api.Error,
+		"not reported for any line": api.Warning,
+	}, severities)
+	assert.Equal(t, runner.ExitCodeCheckFailure, sut.ExitCode())
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// adminPrefix is the path prefix of the admin endpoints.
+const adminPrefix = "/admin/"
+
+// RepositoryStatus describes a repository tracked by the server.
+type RepositoryStatus struct {
+	Repository string `json:"repository"`
+	Path       string `json:"path"`
+	// Tenant is empty if tenants are not configured.
+	Tenant string `json:"tenant,omitempty"`
+	// LastScan is the time of the last snapshot, nil if the repository wasn't scanned yet.
+	LastScan *time.Time `json:"lastScan,omitempty"`
+	Findings int        `json:"findings"`
+}
+
+// RepositoriesResponse is returned by the GET /admin/repos endpoint.
+type RepositoriesResponse struct {
+	Repositories []RepositoryStatus `json:"repositories"`
+}
+
+// TrendPoint summarizes a single snapshot of the repository history.
+type TrendPoint struct {
+	TakenAt  time.Time `json:"takenAt"`
+	Findings int       `json:"findings"`
+	// Kinds counts findings of each kind, e.g. 'unowned-directory'.
+	Kinds map[string]int `json:"kinds"`
+}
+
+// HistoryResponse is returned by the GET /admin/repos/{owner}/{repo}/history endpoint.
+type HistoryResponse struct {
+	Repository string       `json:"repository"`
+	Points     []TrendPoint `json:"points"`
+}
+
+// AdminHandler exposes endpoints for operating the server, authenticated with a bearer token:
+//   - GET /admin/repos lists the tracked repositories with the status of their last scan,
+//   - GET /admin/repos/{owner}/{repo}/report returns the last snapshot of findings,
+//   - GET /admin/repos/{owner}/{repo}/history?since=<RFC3339 time> returns the number of findings of each scan,
+//     if the snapshot store keeps the history,
+//   - POST /admin/repos/{owner}/{repo}/revalidate scans the repository immediately and returns the new snapshot.
+type AdminHandler struct {
+	// base writes the JSON responses
+	base    *Handler
+	log     logrus.FieldLogger
+	token   string
+	watcher *Watcher
+	tenants *Tenants
+}
+
+// NewAdminHandler returns new instance of the AdminHandler. Tenants are optional.
+func NewAdminHandler(log logrus.FieldLogger, token string, watcher *Watcher, tenants *Tenants) *AdminHandler {
+	log = log.WithField("service", "admin")
+	return &AdminHandler{
+		base:    &Handler{log: log},
+		log:     log,
+		token:   token,
+		watcher: watcher,
+		tenants: tenants,
+	}
+}
+
+// ServeHTTP authenticates the request and dispatches it to the endpoint handler.
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !validBearerToken(r.Header.Get("Authorization"), h.token) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="codeowners"`)
+		h.base.writeError(w, http.StatusUnauthorized, errors.New("missing or wrong admin token"))
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, adminPrefix)
+	if path == "repos" {
+		h.repositories(w, r)
+		return
+	}
+
+	rest := strings.TrimPrefix(path, "repos/")
+	idx := strings.LastIndex(rest, "/")
+	if rest == path || idx <= 0 {
+		h.base.writeError(w, http.StatusNotFound, errors.Errorf("endpoint %s not found", r.URL.Path))
+		return
+	}
+	repo, action := rest[:idx], rest[idx+1:]
+	if _, found := h.watcher.Path(repo); !found {
+		h.base.writeError(w, http.StatusNotFound, errors.Errorf("repository %q is not tracked", repo))
+		return
+	}
+
+	switch action {
+	case "report":
+		h.report(w, r, repo)
+	case "history":
+		h.history(w, r, repo)
+	case "revalidate":
+		h.revalidate(w, r, repo)
+	default:
+		h.base.writeError(w, http.StatusNotFound, errors.Errorf("endpoint %s not found", r.URL.Path))
+	}
+}
+
+func (h *AdminHandler) repositories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.base.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	out := RepositoriesResponse{Repositories: []RepositoryStatus{}}
+	for _, repo := range h.watcher.Repositories() {
+		status := RepositoryStatus{Repository: repo}
+		status.Path, _ = h.watcher.Path(repo)
+
+		if h.tenants != nil {
+			tenant, err := h.tenants.Resolve(r.Context(), repo)
+			if err != nil {
+				h.base.writeError(w, http.StatusBadGateway, err)
+				return
+			}
+			status.Tenant = tenant.Name
+		}
+
+		snapshot, err := h.watcher.LastSnapshot(repo)
+		if err != nil {
+			h.log.WithError(err).WithField("repo", repo).Error("Cannot load snapshot")
+			h.base.writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if snapshot != nil {
+			status.LastScan, status.Findings = &snapshot.TakenAt, len(snapshot.Findings)
+		}
+		out.Repositories = append(out.Repositories, status)
+	}
+
+	h.base.writeJSON(w, http.StatusOK, out)
+}
+
+func (h *AdminHandler) report(w http.ResponseWriter, r *http.Request, repo string) {
+	if r.Method != http.MethodGet {
+		h.base.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	h.writeSnapshot(w, repo)
+}
+
+func (h *AdminHandler) writeSnapshot(w http.ResponseWriter, repo string) {
+	snapshot, err := h.watcher.LastSnapshot(repo)
+	switch {
+	case err != nil:
+		h.log.WithError(err).WithField("repo", repo).Error("Cannot load snapshot")
+		h.base.writeError(w, http.StatusInternalServerError, err)
+	case snapshot == nil:
+		h.base.writeError(w, http.StatusNotFound, errors.Errorf("repository %q was not scanned yet", repo))
+	default:
+		h.base.writeJSON(w, http.StatusOK, snapshot)
+	}
+}
+
+func (h *AdminHandler) history(w http.ResponseWriter, r *http.Request, repo string) {
+	if r.Method != http.MethodGet {
+		h.base.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	var since time.Time
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, raw); err != nil {
+			h.base.writeError(w, http.StatusBadRequest, errors.Wrap(err, "while parsing the since parameter"))
+			return
+		}
+	}
+
+	snapshots, err := h.watcher.History(repo, since)
+	switch {
+	case errors.Is(err, ErrHistoryNotSupported):
+		h.base.writeError(w, http.StatusNotImplemented, err)
+		return
+	case err != nil:
+		h.log.WithError(err).WithField("repo", repo).Error("Cannot query history")
+		h.base.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := HistoryResponse{Repository: repo, Points: make([]TrendPoint, 0, len(snapshots))}
+	for _, s := range snapshots {
+		p := TrendPoint{TakenAt: s.TakenAt, Findings: len(s.Findings), Kinds: map[string]int{}}
+		for _, f := range s.Findings {
+			p.Kinds[f.Kind]++
+		}
+		out.Points = append(out.Points, p)
+	}
+	h.base.writeJSON(w, http.StatusOK, out)
+}
+
+func (h *AdminHandler) revalidate(w http.ResponseWriter, r *http.Request, repo string) {
+	if r.Method != http.MethodPost {
+		h.base.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	if err := h.watcher.Scan(r.Context(), repo); err != nil {
+		h.log.WithError(err).WithField("repo", repo).Error("Cannot revalidate repository")
+		h.base.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	h.log.WithField("repo", repo).Info("Revalidated repository on demand")
+	h.writeSnapshot(w, repo)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/netguard"
+
+	"github.com/pkg/errors"
+)
+
+// AdminClient calls the admin endpoints of the server.
+type AdminClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewAdminClient returns new instance of the AdminClient. The base URL is the address of the server, e.g. 'http://localhost:8080'.
+func NewAdminClient(baseURL, token string) *AdminClient {
+	return &AdminClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		// revalidation of big repositories takes a while
+		client: &http.Client{Timeout: 5 * time.Minute, Transport: netguard.BaseTransport()},
+	}
+}
+
+// ListRepositories returns the repositories tracked by the server.
+func (c *AdminClient) ListRepositories(ctx context.Context) ([]RepositoryStatus, error) {
+	var out RepositoriesResponse
+	if err := c.do(ctx, http.MethodGet, "repos", &out); err != nil {
+		return nil, err
+	}
+	return out.Repositories, nil
+}
+
+// Report returns the last snapshot of findings of a given repository.
+func (c *AdminClient) Report(ctx context.Context, repo string) (*Snapshot, error) {
+	var out Snapshot
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/report", repo), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// History returns the number of findings of each scan of a given repository since a given time.
+func (c *AdminClient) History(ctx context.Context, repo string, since time.Time) ([]TrendPoint, error) {
+	var out HistoryResponse
+	endpoint := fmt.Sprintf("repos/%s/history?since=%s", repo, url.QueryEscape(since.Format(time.RFC3339)))
+	if err := c.do(ctx, http.MethodGet, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out.Points, nil
+}
+
+// Revalidate scans a given repository immediately and returns the new snapshot of findings.
+func (c *AdminClient) Revalidate(ctx context.Context, repo string) (*Snapshot, error) {
+	var out Snapshot
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/revalidate", repo), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *AdminClient) do(ctx context.Context, method, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+adminPrefix+endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "while reading response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if err := json.Unmarshal(raw, &e); err == nil && e.Error != "" {
+			return errors.Errorf("server responded with status %s: %s", resp.Status, e.Error)
+		}
+		return errors.Errorf("server responded with status %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return errors.Wrap(err, "while decoding response")
+	}
+	return nil
+}
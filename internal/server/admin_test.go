@@ -0,0 +1,184 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/checktest"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdminServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/docs/ old-team\n",
+		Files: map[string]string{
+			"docs/README.md": "docs",
+			"src/main.go":    "package main",
+		},
+	})
+
+	svc := server.NewService(server.NewLocalSource(nil), check.NewValidSyntax())
+	watcher := server.NewWatcher(logrus.New(), svc, map[string]string{"org/repo": in.RepoDir}, server.NewFileSnapshotStore(t.TempDir()), nil)
+	handler := server.NewHandler(logrus.New(), svc).WithAdmin(server.NewAdminHandler(logrus.New(), "secret", watcher, nil))
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAdminClient(t *testing.T) {
+	// given
+	srv := newAdminServer(t)
+	sut := server.NewAdminClient(srv.URL, "secret")
+	ctx := context.Background()
+
+	// when
+	repos, err := sut.ListRepositories(ctx)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	assert.Equal(t, "org/repo", repos[0].Repository)
+	assert.Nil(t, repos[0].LastScan)
+
+	// when
+	_, err = sut.Report(ctx, "org/repo")
+
+	// then
+	assert.EqualError(t, err, `server responded with status 404 Not Found: repository "org/repo" was not scanned yet`)
+
+	// when
+	revalidated, err := sut.Revalidate(ctx, "org/repo")
+	require.NoError(t, err)
+	report, err := sut.Report(ctx, "org/repo")
+	require.NoError(t, err)
+	repos, err = sut.ListRepositories(ctx)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, []server.Finding{
+		{Kind: server.CheckIssue, Subject: "[error] Owner 'old-team' does not look like an email"},
+		{Kind: server.UnownedDirectory, Subject: "."},
+		{Kind: server.UnownedDirectory, Subject: "src"},
+	}, revalidated.Findings)
+	assert.Equal(t, revalidated, report)
+	require.NotNil(t, repos[0].LastScan)
+	assert.Equal(t, 3, repos[0].Findings)
+}
+
+func TestAdminHandlerErrors(t *testing.T) {
+	srv := newAdminServer(t)
+
+	tests := map[string]struct {
+		givenMethod string
+		givenPath   string
+		givenToken  string
+		expStatus   int
+	}{
+		"Should reject missing token": {
+			givenMethod: http.MethodGet,
+			givenPath:   "/admin/repos",
+			expStatus:   http.StatusUnauthorized,
+		},
+		"Should reject wrong token": {
+			givenMethod: http.MethodGet,
+			givenPath:   "/admin/repos",
+			givenToken:  "wrong",
+			expStatus:   http.StatusUnauthorized,
+		},
+		"Should reject untracked repository": {
+			givenMethod: http.MethodPost,
+			givenPath:   "/admin/repos/org/other/revalidate",
+			givenToken:  "secret",
+			expStatus:   http.StatusNotFound,
+		},
+		"Should reject revalidation with GET": {
+			givenMethod: http.MethodGet,
+			givenPath:   "/admin/repos/org/repo/revalidate",
+			givenToken:  "secret",
+			expStatus:   http.StatusMethodNotAllowed,
+		},
+		"Should reject unknown endpoint": {
+			givenMethod: http.MethodGet,
+			givenPath:   "/admin/tenants",
+			givenToken:  "secret",
+			expStatus:   http.StatusNotFound,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(tc.givenMethod, srv.URL+tc.givenPath, nil)
+			require.NoError(t, err)
+			if tc.givenToken != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.givenToken)
+			}
+
+			// when
+			resp, err := http.DefaultClient.Do(req)
+
+			// then
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tc.expStatus, resp.StatusCode)
+		})
+	}
+}
+
+type historyStore struct {
+	*server.FileSnapshotStore
+	snapshots []server.Snapshot
+}
+
+func (s *historyStore) History(_ string, since time.Time) ([]server.Snapshot, error) {
+	var out []server.Snapshot
+	for _, snapshot := range s.snapshots {
+		if !snapshot.TakenAt.Before(since) {
+			out = append(out, snapshot)
+		}
+	}
+	return out, nil
+}
+
+func TestAdminClientHistory(t *testing.T) {
+	// given
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &historyStore{FileSnapshotStore: server.NewFileSnapshotStore(t.TempDir()), snapshots: []server.Snapshot{
+		{Repository: "org/repo", TakenAt: since, Findings: []server.Finding{{Kind: server.UnownedDirectory, Subject: "src"}}},
+		{Repository: "org/repo", TakenAt: since.Add(time.Hour), Findings: []server.Finding{
+			{Kind: server.UnownedDirectory, Subject: "src"},
+			{Kind: server.CheckIssue, Subject: "[error] Owner 'old-team' does not look like an email"},
+		}},
+	}}
+
+	svc := server.NewService(server.NewLocalSource(nil))
+	withHistory := server.NewWatcher(logrus.New(), svc, map[string]string{"org/repo": t.TempDir()}, store, nil)
+	withoutHistory := server.NewWatcher(logrus.New(), svc, map[string]string{"org/repo": t.TempDir()}, server.NewFileSnapshotStore(t.TempDir()), nil)
+
+	srv := httptest.NewServer(server.NewHandler(logrus.New(), svc).WithAdmin(server.NewAdminHandler(logrus.New(), "secret", withHistory, nil)))
+	defer srv.Close()
+	srvWithoutHistory := httptest.NewServer(server.NewHandler(logrus.New(), svc).WithAdmin(server.NewAdminHandler(logrus.New(), "secret", withoutHistory, nil)))
+	defer srvWithoutHistory.Close()
+
+	// when
+	points, err := server.NewAdminClient(srv.URL, "secret").History(context.Background(), "org/repo", since.Add(time.Minute))
+	_, errNotSupported := server.NewAdminClient(srvWithoutHistory.URL, "secret").History(context.Background(), "org/repo", since)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []server.TrendPoint{
+		{TakenAt: since.Add(time.Hour), Findings: 2, Kinds: map[string]int{server.UnownedDirectory: 1, server.CheckIssue: 1}},
+	}, points)
+	assert.EqualError(t, errNotSupported, "server responded with status 501 Not Implemented: snapshot store doesn't keep the history of snapshots")
+}
@@ -0,0 +1,25 @@
+package server
+
+import "time"
+
+// SetClock replaces the clock of the CachedSource, so tests can expire cached rulesets.
+func (c *CachedSource) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// SetMaxSize limits the number of rulesets cached by the CachedSource, so tests can fill the cache.
+func (c *CachedSource) SetMaxSize(size int) {
+	c.maxSize = size
+}
+
+// Len returns the number of rulesets cached by the CachedSource.
+func (c *CachedSource) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.rulesets)
+}
+
+// SetClock replaces the clock of the Watcher, so tests can control the snapshot time.
+func (w *Watcher) SetClock(now func() time.Time) {
+	w.now = now
+}
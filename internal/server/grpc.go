@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"io"
+
+	"go.szostok.io/codeowners/internal/redact"
+	pb "go.szostok.io/codeowners/internal/server/ownershippb"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer exposes the ownership queries over gRPC. Paths are streamed in batches,
+// so high-throughput clients don't pay the cost of a request per path.
+type GRPCServer struct {
+	pb.UnimplementedOwnershipServiceServer
+
+	svc *Service
+	log logrus.FieldLogger
+}
+
+// NewGRPCServer returns new instance of the GRPCServer.
+func NewGRPCServer(log logrus.FieldLogger, svc *Service) *GRPCServer {
+	return &GRPCServer{svc: svc, log: log}
+}
+
+// TokenAuth returns server options which require a given token in the 'authorization: Bearer <token>'
+// metadata of all calls, the same way as the read token of the HTTP Handler.
+func TokenAuth(token string) []grpc.ServerOption {
+	authorize := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, header := range md.Get("authorization") {
+			if validBearerToken(header, token) {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "missing or wrong read token")
+	}
+
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authorize(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// Register registers the ownership service in a given gRPC server.
+func (s *GRPCServer) Register(srv *grpc.Server) {
+	pb.RegisterOwnershipServiceServer(srv, s)
+}
+
+// ResolveOwners resolves owners of each streamed batch of paths.
+func (s *GRPCServer) ResolveOwners(stream pb.OwnershipService_ResolveOwnersServer) error {
+	for {
+		req, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		if req.GetRepo() == "" {
+			return status.Error(codes.InvalidArgument, "repo is required")
+		}
+
+		results, err := s.svc.ResolveOwners(stream.Context(), req.GetRepo(), req.GetPaths())
+		if err != nil {
+			return s.toStatus(err, req.GetRepo())
+		}
+
+		resp := &pb.ResolveOwnersResponse{Repo: req.GetRepo(), Results: make([]*pb.Resolution, 0, len(results))}
+		for _, r := range results {
+			resp.Results = append(resp.Results, &pb.Resolution{Path: r.Path, Owners: r.Owners, Pattern: r.Pattern, Line: r.Line})
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// ValidateFile executes the offline checks against the CODEOWNERS content.
+func (s *GRPCServer) ValidateFile(ctx context.Context, req *pb.ValidateFileRequest) (*pb.ValidateFileResponse, error) {
+	issues, err := s.svc.ValidateFile(ctx, req.GetContent())
+	if err != nil {
+		s.log.WithError(err).Error("Cannot validate CODEOWNERS")
+		return nil, status.Error(codes.Internal, redact.String(err.Error()))
+	}
+
+	resp := &pb.ValidateFileResponse{Issues: make([]*pb.Issue, 0, len(issues))}
+	for _, i := range issues {
+		out := &pb.Issue{Severity: toSeverity(i.Severity), Message: i.Message}
+		if i.LineNo != nil {
+			out.Line = *i.LineNo
+		}
+		resp.Issues = append(resp.Issues, out)
+	}
+	return resp, nil
+}
+
+// Coverage computes how many of the streamed paths are owned. The repository is taken from the first message.
+func (s *GRPCServer) Coverage(stream pb.OwnershipService_CoverageServer) error {
+	var (
+		repo     string
+		coverage Coverage
+	)
+	for {
+		req, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return stream.SendAndClose(&pb.CoverageResponse{
+				Total:        coverage.Total,
+				Owned:        coverage.Owned,
+				UnownedPaths: coverage.UnownedPaths,
+			})
+		case err != nil:
+			return err
+		}
+
+		if repo == "" {
+			repo = req.GetRepo()
+		}
+		if repo == "" {
+			return status.Error(codes.InvalidArgument, "repo is required")
+		}
+
+		results, err := s.svc.ResolveOwners(stream.Context(), repo, req.GetPaths())
+		if err != nil {
+			return s.toStatus(err, repo)
+		}
+		for _, r := range results {
+			coverage.Add(r)
+		}
+	}
+}
+
+func (s *GRPCServer) toStatus(err error, repo string) error {
+	if errors.Is(err, ErrUnknownRepository) {
+		return status.Errorf(codes.NotFound, "CODEOWNERS of repository %q is not available", repo)
+	}
+	s.log.WithError(err).WithField("repo", repo).Error("Cannot resolve owners")
+	return status.Error(codes.Unavailable, redact.String(err.Error()))
+}
+
+func toSeverity(s api.SeverityType) pb.Severity {
+	switch s {
+	case api.Error:
+		return pb.Severity_SEVERITY_ERROR
+	case api.Warning:
+		return pb.Severity_SEVERITY_WARNING
+	default:
+		return pb.Severity_SEVERITY_UNSPECIFIED
+	}
+}
@@ -0,0 +1,173 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/server"
+	pb "go.szostok.io/codeowners/internal/server/ownershippb"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "CODEOWNERS"), []byte(testCODEOWNERS), 0o600))
+
+	svc := server.NewService(server.NewLocalSource(map[string]string{"org/repo": repoDir}), check.NewDuplicatedPattern())
+	client := newGRPCClient(t, server.NewGRPCServer(logrus.New(), svc))
+	ctx := context.Background()
+
+	t.Run("Resolve owners of streamed batches", func(t *testing.T) {
+		// given
+		stream, err := client.ResolveOwners(ctx)
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, stream.Send(&pb.ResolveOwnersRequest{Repo: "org/repo", Paths: []string{"docs/README.md", "main.go"}}))
+		first, err := stream.Recv()
+		require.NoError(t, err)
+
+		require.NoError(t, stream.Send(&pb.ResolveOwnersRequest{Repo: "org/repo", Paths: []string{"vendor/lib.go"}}))
+		second, err := stream.Recv()
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, first.Results, 2)
+		assert.Equal(t, []string{"@org/docs"}, first.Results[0].Owners)
+		assert.Equal(t, "/docs/", first.Results[0].Pattern)
+		assert.Equal(t, uint64(3), first.Results[0].Line)
+		assert.Equal(t, []string{"@org/platform"}, first.Results[1].Owners)
+
+		require.Len(t, second.Results, 1)
+		assert.Empty(t, second.Results[0].Owners)
+		assert.Equal(t, "/vendor/", second.Results[0].Pattern)
+	})
+
+	t.Run("Unknown repository", func(t *testing.T) {
+		// given
+		stream, err := client.ResolveOwners(ctx)
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, stream.Send(&pb.ResolveOwnersRequest{Repo: "org/other", Paths: []string{"main.go"}}))
+		_, err = stream.Recv()
+
+		// then
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Validate file", func(t *testing.T) {
+		// when
+		resp, err := client.ValidateFile(ctx, &pb.ValidateFileRequest{Content: "*.go @org/a\n*.go @org/b\n"})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, resp.Issues, 1)
+		assert.Equal(t, pb.Severity_SEVERITY_ERROR, resp.Issues[0].Severity)
+		assert.Contains(t, resp.Issues[0].Message, `Pattern "*.go" is defined 2 times`)
+	})
+
+	t.Run("Coverage of streamed paths", func(t *testing.T) {
+		// given
+		stream, err := client.Coverage(ctx)
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, stream.Send(&pb.CoverageRequest{Repo: "org/repo", Paths: []string{"main.go", "vendor/a.go"}}))
+		require.NoError(t, stream.Send(&pb.CoverageRequest{Paths: []string{"docs/index.md", "vendor/b.go"}}))
+		resp, err := stream.CloseAndRecv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), resp.Total)
+		assert.Equal(t, uint64(2), resp.Owned)
+		assert.Equal(t, []string{"vendor/a.go", "vendor/b.go"}, resp.UnownedPaths)
+	})
+
+	t.Run("Coverage without repository", func(t *testing.T) {
+		// given
+		stream, err := client.Coverage(ctx)
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, stream.Send(&pb.CoverageRequest{Paths: []string{"main.go"}}))
+		_, err = stream.CloseAndRecv()
+
+		// then
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPCServerTokenAuth(t *testing.T) {
+	// given
+	svc := server.NewService(server.NewLocalSource(map[string]string{}))
+	client := newGRPCClient(t, server.NewGRPCServer(logrus.New(), svc), server.TokenAuth("secret")...)
+
+	tests := map[string]struct {
+		authorization string
+		expCode       codes.Code
+	}{
+		"With token": {
+			authorization: "Bearer secret",
+			expCode:       codes.OK,
+		},
+		"Without token": {
+			expCode: codes.Unauthenticated,
+		},
+		"Wrong token": {
+			authorization: "Bearer other",
+			expCode:       codes.Unauthenticated,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.authorization)
+			}
+
+			// when
+			_, err := client.ValidateFile(ctx, &pb.ValidateFileRequest{Content: "* @org/platform\n"})
+
+			// then
+			assert.Equal(t, tc.expCode, status.Code(err))
+		})
+	}
+}
+
+func newGRPCClient(t *testing.T, sut *server.GRPCServer, opts ...grpc.ServerOption) pb.OwnershipServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(opts...)
+	sut.Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewOwnershipServiceClient(conn)
+}
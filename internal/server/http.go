@@ -0,0 +1,159 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.szostok.io/codeowners/internal/redact"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxRequestBody limits the size of the POST /match request body.
+const maxRequestBody = 10 << 20
+
+// MatchRequest is the body of the POST /match request.
+type MatchRequest struct {
+	Repo  string   `json:"repo"`
+	Paths []string `json:"paths"`
+}
+
+// OwnersResponse is returned by the GET /owners and POST /match endpoints.
+type OwnersResponse struct {
+	Repo    string       `json:"repo"`
+	Results []Resolution `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler exposes the ownership queries over HTTP:
+//   - GET /owners?repo=org/repo&path=docs/README.md, the path parameter can be repeated,
+//   - POST /match with the MatchRequest body, for bigger batches of paths.
+//
+// If the read token is set, the queries are authenticated with a bearer token.
+type Handler struct {
+	svc       *Service
+	log       logrus.FieldLogger
+	mux       *http.ServeMux
+	readToken string
+}
+
+// NewHandler returns new instance of the Handler.
+func NewHandler(log logrus.FieldLogger, svc *Service) *Handler {
+	h := &Handler{svc: svc, log: log, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/owners", h.owners)
+	h.mux.HandleFunc("/match", h.match)
+	h.mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return h
+}
+
+// WithReadToken requires a given token in the 'Authorization: Bearer <token>' header of the ownership queries.
+func (h *Handler) WithReadToken(token string) *Handler {
+	h.readToken = token
+	return h
+}
+
+// WithAdmin serves the admin endpoints under the /admin/ path.
+func (h *Handler) WithAdmin(admin *AdminHandler) *Handler {
+	h.mux.Handle(adminPrefix, admin)
+	return h
+}
+
+// ServeHTTP dispatches the request to the endpoint handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) owners(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	h.resolve(w, r, MatchRequest{Repo: query.Get("repo"), Paths: query["path"]})
+}
+
+func (h *Handler) match(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	var req MatchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, errors.Wrap(err, "while decoding request body"))
+		return
+	}
+	h.resolve(w, r, req)
+}
+
+func (h *Handler) resolve(w http.ResponseWriter, r *http.Request, req MatchRequest) {
+	switch {
+	case req.Repo == "":
+		h.writeError(w, http.StatusBadRequest, errors.New("repo is required"))
+		return
+	case len(req.Paths) == 0:
+		h.writeError(w, http.StatusBadRequest, errors.New("at least one path is required"))
+		return
+	}
+
+	results, err := h.svc.ResolveOwners(r.Context(), req.Repo, req.Paths)
+	switch {
+	case errors.Is(err, ErrUnknownRepository):
+		h.writeError(w, http.StatusNotFound, errors.Errorf("CODEOWNERS of repository %q is not available", req.Repo))
+		return
+	case err != nil:
+		h.log.WithError(err).WithField("repo", req.Repo).Error("Cannot resolve owners")
+		h.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, OwnersResponse{Repo: req.Repo, Results: results})
+}
+
+// authorize writes the 401 response and returns false if the read token is set and the request doesn't send it.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if h.readToken == "" || validBearerToken(r.Header.Get("Authorization"), h.readToken) {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="codeowners"`)
+	h.writeError(w, http.StatusUnauthorized, errors.New("missing or wrong read token"))
+	return false
+}
+
+// validBearerToken returns true if a given Authorization header value is 'Bearer <token>' with the expected token.
+// The empty expected token never matches.
+func validBearerToken(header, expected string) bool {
+	// the scheme is required, so a bare token is not accepted
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(header, "Bearer ")
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	h.writeJSON(w, status, errorResponse{Error: redact.String(err.Error())})
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.log.WithError(err).Debug("Cannot write response")
+	}
+}
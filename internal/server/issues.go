@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// ReminderLabel marks issues managed by the GitHubIssueTracker, so issues created by people are never modified.
+const ReminderLabel = "codeowners-reminder"
+
+// GitHubIssueTracker implements the IssueTracker with GitHub issues labeled with the ReminderLabel.
+type GitHubIssueTracker struct {
+	client *github.Client
+}
+
+// NewGitHubIssueTracker returns new instance of the GitHubIssueTracker.
+func NewGitHubIssueTracker(client *github.Client) *GitHubIssueTracker {
+	return &GitHubIssueTracker{client: client}
+}
+
+// Sync opens, updates, and closes reminder issues of a given repository. Bodies which didn't change
+// are not updated, so watchers are not notified on every run.
+func (t *GitHubIssueTracker) Sync(ctx context.Context, repo string, issues map[string]string) error {
+	owner, name, err := splitRepository(repo)
+	if err != nil {
+		return err
+	}
+
+	open, err := t.listOpen(ctx, owner, name)
+	if err != nil {
+		return errors.Wrapf(err, "while listing reminder issues of %s", repo)
+	}
+
+	for title, body := range issues {
+		body := body
+		existing, found := open[title]
+		switch {
+		case !found:
+			_, _, err = t.client.Issues.Create(ctx, owner, name, &github.IssueRequest{
+				Title:  github.String(title),
+				Body:   &body,
+				Labels: &[]string{ReminderLabel},
+			})
+		case existing.GetBody() != body:
+			_, _, err = t.client.Issues.Edit(ctx, owner, name, existing.GetNumber(), &github.IssueRequest{Body: &body})
+		}
+		if err != nil {
+			return errors.Wrapf(err, "while saving issue %q of %s", title, repo)
+		}
+	}
+
+	for title, issue := range open {
+		if _, found := issues[title]; found {
+			continue
+		}
+		_, _, err = t.client.Issues.Edit(ctx, owner, name, issue.GetNumber(), &github.IssueRequest{State: github.String("closed")})
+		if err != nil {
+			return errors.Wrapf(err, "while closing issue %q of %s", title, repo)
+		}
+	}
+	return nil
+}
+
+// listOpen returns open reminder issues indexed by the title. Pull requests are skipped.
+func (t *GitHubIssueTracker) listOpen(ctx context.Context, owner, name string) (map[string]*github.Issue, error) {
+	out := map[string]*github.Issue{}
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      []string{ReminderLabel},
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := t.client.Issues.ListByRepo(ctx, owner, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page {
+			if issue.IsPullRequest() {
+				continue
+			}
+			out[issue.GetTitle()] = issue
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func splitRepository(repo string) (owner, name string, err error) {
+	idx := strings.LastIndex(repo, "/")
+	if idx <= 0 || idx == len(repo)-1 {
+		return "", "", errors.Errorf("wrong repository name, expected pattern 'owner/repository', got %q", repo)
+	}
+	return repo[:idx], repo[idx+1:], nil
+}
@@ -0,0 +1,4 @@
+// Package ownershippb contains the protobuf messages and gRPC bindings of the ownership service.
+package ownershippb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ownership.proto
@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: ownership.proto
+
+package ownershippb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	OwnershipService_ResolveOwners_FullMethodName = "/codeowners.ownership.v1.OwnershipService/ResolveOwners"
+	OwnershipService_ValidateFile_FullMethodName  = "/codeowners.ownership.v1.OwnershipService/ValidateFile"
+	OwnershipService_Coverage_FullMethodName      = "/codeowners.ownership.v1.OwnershipService/Coverage"
+)
+
+// OwnershipServiceClient is the client API for OwnershipService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OwnershipServiceClient interface {
+	// ResolveOwners resolves owners of path batches streamed by the client.
+	// Each request is answered with a single response that preserves the order of paths.
+	ResolveOwners(ctx context.Context, opts ...grpc.CallOption) (OwnershipService_ResolveOwnersClient, error)
+	// ValidateFile executes offline checks against the given CODEOWNERS content.
+	ValidateFile(ctx context.Context, in *ValidateFileRequest, opts ...grpc.CallOption) (*ValidateFileResponse, error)
+	// Coverage computes how many of the paths streamed by the client are owned.
+	Coverage(ctx context.Context, opts ...grpc.CallOption) (OwnershipService_CoverageClient, error)
+}
+
+type ownershipServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOwnershipServiceClient(cc grpc.ClientConnInterface) OwnershipServiceClient {
+	return &ownershipServiceClient{cc}
+}
+
+func (c *ownershipServiceClient) ResolveOwners(ctx context.Context, opts ...grpc.CallOption) (OwnershipService_ResolveOwnersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OwnershipService_ServiceDesc.Streams[0], OwnershipService_ResolveOwners_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ownershipServiceResolveOwnersClient{stream}
+	return x, nil
+}
+
+type OwnershipService_ResolveOwnersClient interface {
+	Send(*ResolveOwnersRequest) error
+	Recv() (*ResolveOwnersResponse, error)
+	grpc.ClientStream
+}
+
+type ownershipServiceResolveOwnersClient struct {
+	grpc.ClientStream
+}
+
+func (x *ownershipServiceResolveOwnersClient) Send(m *ResolveOwnersRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ownershipServiceResolveOwnersClient) Recv() (*ResolveOwnersResponse, error) {
+	m := new(ResolveOwnersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ownershipServiceClient) ValidateFile(ctx context.Context, in *ValidateFileRequest, opts ...grpc.CallOption) (*ValidateFileResponse, error) {
+	out := new(ValidateFileResponse)
+	err := c.cc.Invoke(ctx, OwnershipService_ValidateFile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ownershipServiceClient) Coverage(ctx context.Context, opts ...grpc.CallOption) (OwnershipService_CoverageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OwnershipService_ServiceDesc.Streams[1], OwnershipService_Coverage_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ownershipServiceCoverageClient{stream}
+	return x, nil
+}
+
+type OwnershipService_CoverageClient interface {
+	Send(*CoverageRequest) error
+	CloseAndRecv() (*CoverageResponse, error)
+	grpc.ClientStream
+}
+
+type ownershipServiceCoverageClient struct {
+	grpc.ClientStream
+}
+
+func (x *ownershipServiceCoverageClient) Send(m *CoverageRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ownershipServiceCoverageClient) CloseAndRecv() (*CoverageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(CoverageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OwnershipServiceServer is the server API for OwnershipService service.
+// All implementations must embed UnimplementedOwnershipServiceServer
+// for forward compatibility
+type OwnershipServiceServer interface {
+	// ResolveOwners resolves owners of path batches streamed by the client.
+	// Each request is answered with a single response that preserves the order of paths.
+	ResolveOwners(OwnershipService_ResolveOwnersServer) error
+	// ValidateFile executes offline checks against the given CODEOWNERS content.
+	ValidateFile(context.Context, *ValidateFileRequest) (*ValidateFileResponse, error)
+	// Coverage computes how many of the paths streamed by the client are owned.
+	Coverage(OwnershipService_CoverageServer) error
+	mustEmbedUnimplementedOwnershipServiceServer()
+}
+
+// UnimplementedOwnershipServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOwnershipServiceServer struct {
+}
+
+func (UnimplementedOwnershipServiceServer) ResolveOwners(OwnershipService_ResolveOwnersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ResolveOwners not implemented")
+}
+func (UnimplementedOwnershipServiceServer) ValidateFile(context.Context, *ValidateFileRequest) (*ValidateFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateFile not implemented")
+}
+func (UnimplementedOwnershipServiceServer) Coverage(OwnershipService_CoverageServer) error {
+	return status.Errorf(codes.Unimplemented, "method Coverage not implemented")
+}
+func (UnimplementedOwnershipServiceServer) mustEmbedUnimplementedOwnershipServiceServer() {}
+
+// UnsafeOwnershipServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OwnershipServiceServer will
+// result in compilation errors.
+type UnsafeOwnershipServiceServer interface {
+	mustEmbedUnimplementedOwnershipServiceServer()
+}
+
+func RegisterOwnershipServiceServer(s grpc.ServiceRegistrar, srv OwnershipServiceServer) {
+	s.RegisterService(&OwnershipService_ServiceDesc, srv)
+}
+
+func _OwnershipService_ResolveOwners_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OwnershipServiceServer).ResolveOwners(&ownershipServiceResolveOwnersServer{stream})
+}
+
+type OwnershipService_ResolveOwnersServer interface {
+	Send(*ResolveOwnersResponse) error
+	Recv() (*ResolveOwnersRequest, error)
+	grpc.ServerStream
+}
+
+type ownershipServiceResolveOwnersServer struct {
+	grpc.ServerStream
+}
+
+func (x *ownershipServiceResolveOwnersServer) Send(m *ResolveOwnersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ownershipServiceResolveOwnersServer) Recv() (*ResolveOwnersRequest, error) {
+	m := new(ResolveOwnersRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _OwnershipService_ValidateFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OwnershipServiceServer).ValidateFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OwnershipService_ValidateFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OwnershipServiceServer).ValidateFile(ctx, req.(*ValidateFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OwnershipService_Coverage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OwnershipServiceServer).Coverage(&ownershipServiceCoverageServer{stream})
+}
+
+type OwnershipService_CoverageServer interface {
+	SendAndClose(*CoverageResponse) error
+	Recv() (*CoverageRequest, error)
+	grpc.ServerStream
+}
+
+type ownershipServiceCoverageServer struct {
+	grpc.ServerStream
+}
+
+func (x *ownershipServiceCoverageServer) SendAndClose(m *CoverageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ownershipServiceCoverageServer) Recv() (*CoverageRequest, error) {
+	m := new(CoverageRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OwnershipService_ServiceDesc is the grpc.ServiceDesc for OwnershipService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OwnershipService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "codeowners.ownership.v1.OwnershipService",
+	HandlerType: (*OwnershipServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateFile",
+			Handler:    _OwnershipService_ValidateFile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ResolveOwners",
+			Handler:       _OwnershipService_ResolveOwners_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Coverage",
+			Handler:       _OwnershipService_Coverage_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ownership.proto",
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ReminderEntry is a CODEOWNERS entry which ownership should be reviewed by the team.
+type ReminderEntry struct {
+	LineNo  uint64
+	Pattern string
+	// Problem describes why the entry should be reviewed, e.g. that the ownership expires soon.
+	Problem string
+}
+
+// Reminder lists ownership problems of a single team in a given repository.
+type Reminder struct {
+	Repository string
+	Team       string
+	Entries    []ReminderEntry
+	// UnownedDirectories are directories without owners placed next to directories owned by the team.
+	UnownedDirectories []string
+}
+
+// IsEmpty returns true if the team doesn't have anything to review.
+func (r Reminder) IsEmpty() bool {
+	return len(r.Entries) == 0 && len(r.UnownedDirectories) == 0
+}
+
+// Title returns the title of the reminder issue. It's stable, so it identifies the issue of the team.
+func (r Reminder) Title() string {
+	return fmt.Sprintf("CODEOWNERS review for %s", r.Team)
+}
+
+// Body returns the Markdown body of the reminder issue. The team is mentioned, so its members are notified.
+func (r Reminder) Body() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s, please review the ownership of your areas in the CODEOWNERS file of %s.\n", r.Team, r.Repository)
+
+	if len(r.Entries) > 0 {
+		out.WriteString("\n### Entries\n\n")
+		for _, e := range r.Entries {
+			fmt.Fprintf(&out, "- [ ] line %d `%s`: %s\n", e.LineNo, e.Pattern, e.Problem)
+		}
+	}
+	if len(r.UnownedDirectories) > 0 {
+		out.WriteString("\n### Unowned directories next to your areas\n\n")
+		for _, d := range r.UnownedDirectories {
+			fmt.Fprintf(&out, "- [ ] `%s`\n", d)
+		}
+	}
+
+	out.WriteString("\nThis issue is updated by codeowners and closed once there is nothing left to review.\n")
+	return out.String()
+}
+
+// BuildReminders returns reminders of all teams which own entries of the CODEOWNERS file, sorted by the team.
+// Entries are reported if their `expires:` annotation is expired or expires within warnBefore, or if their
+// pattern doesn't match any of the repository files. Reminders of teams without problems are empty.
+func BuildReminders(ctx context.Context, repo string, entries []codeowners.Entry, files []string, warnBefore time.Duration) ([]Reminder, error) {
+	byTeam := map[string]*Reminder{}
+	teamsOf := func(owners []string) []*Reminder {
+		var out []*Reminder
+		for _, o := range owners {
+			if !isTeam(o) {
+				continue
+			}
+			r, found := byTeam[o]
+			if !found {
+				r = &Reminder{Repository: repo, Team: o}
+				byTeam[o] = r
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+
+	byLine := map[uint64]codeowners.Entry{}
+	for _, e := range entries {
+		byLine[e.LineNo] = e
+		teamsOf(e.Owners)
+	}
+
+	expiration, err := check.NewOwnershipExpiration(check.OwnershipExpirationConfig{WarnBefore: warnBefore}).
+		Check(ctx, api.Input{CodeownersEntries: entries})
+	if err != nil {
+		return nil, errors.Wrap(err, "while checking ownership expiration")
+	}
+	for _, i := range expiration.Issues {
+		if i.LineNo == nil {
+			continue
+		}
+		e := byLine[*i.LineNo]
+		for _, r := range teamsOf(e.Owners) {
+			r.Entries = append(r.Entries, ReminderEntry{LineNo: e.LineNo, Pattern: e.Pattern, Problem: i.Message})
+		}
+	}
+
+	for _, e := range entries {
+		if matchesAny(e.Pattern, files) {
+			continue
+		}
+		for _, r := range teamsOf(e.Owners) {
+			r.Entries = append(r.Entries, ReminderEntry{LineNo: e.LineNo, Pattern: e.Pattern, Problem: "Pattern doesn't match any file"})
+		}
+	}
+
+	ruleset, err := NewRuleset(repo, entries)
+	if err != nil {
+		return nil, err
+	}
+	ownedDirs := map[string]map[string]struct{}{}
+	unownedDirs := map[string]struct{}{}
+	for _, f := range files {
+		dir := path.Dir(f)
+		owners := ruleset.Resolve(f).Owners
+		if len(owners) == 0 {
+			unownedDirs[dir] = struct{}{}
+			continue
+		}
+		for _, o := range owners {
+			if !isTeam(o) {
+				continue
+			}
+			if ownedDirs[dir] == nil {
+				ownedDirs[dir] = map[string]struct{}{}
+			}
+			ownedDirs[dir][o] = struct{}{}
+		}
+	}
+	for dir := range unownedDirs {
+		if dir == "." {
+			// the root is next to all top-level areas, so it's not a concern of any particular team
+			continue
+		}
+		parent := path.Dir(dir)
+		adjacent := map[string]struct{}{}
+		for owned, teams := range ownedDirs {
+			if owned != dir && owned != parent && path.Dir(owned) != parent {
+				continue
+			}
+			for t := range teams {
+				adjacent[t] = struct{}{}
+			}
+		}
+		for t := range adjacent {
+			byTeam[t].UnownedDirectories = append(byTeam[t].UnownedDirectories, dir)
+		}
+	}
+
+	out := make([]Reminder, 0, len(byTeam))
+	for _, r := range byTeam {
+		sort.SliceStable(r.Entries, func(i, j int) bool { return r.Entries[i].LineNo < r.Entries[j].LineNo })
+		sort.Strings(r.UnownedDirectories)
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Team < out[j].Team })
+	return out, nil
+}
+
+// isTeam returns true for owners in the @org/team format.
+func isTeam(owner string) bool {
+	return strings.HasPrefix(owner, "@") && strings.Contains(owner, "/")
+}
+
+func matchesAny(pattern string, files []string) bool {
+	p, err := codeowners.NewPattern(pattern)
+	if err != nil {
+		// invalid patterns are reported by the syntax check
+		return true
+	}
+	for _, f := range files {
+		if p.Match(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueTracker keeps reminder issues of a repository up to date.
+type IssueTracker interface {
+	// Sync opens issues with given titles, or updates bodies of already open ones. Other open reminder
+	// issues are closed, as there is nothing left to review.
+	Sync(ctx context.Context, repo string, issues map[string]string) error
+}
+
+// Reminders periodically opens or updates issues which remind teams about expiring ownership,
+// patterns which don't match any file, and unowned directories next to their areas.
+type Reminders struct {
+	log        logrus.FieldLogger
+	paths      map[string]string
+	tracker    IssueTracker
+	warnBefore time.Duration
+}
+
+// NewReminders returns new instance of the Reminders. Paths are indexed by the repository name, e.g. 'org/repo'.
+func NewReminders(log logrus.FieldLogger, paths map[string]string, tracker IssueTracker, warnBefore time.Duration) *Reminders {
+	return &Reminders{
+		log:        log.WithField("service", "reminders"),
+		paths:      paths,
+		tracker:    tracker,
+		warnBefore: warnBefore,
+	}
+}
+
+// Run reminds teams of all repositories immediately and then in a given interval, until the context is canceled.
+func (r *Reminders) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.RemindAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RemindAll reminds teams of all repositories. Errors are logged, so a single broken repository doesn't block others.
+func (r *Reminders) RemindAll(ctx context.Context) {
+	repos := make([]string, 0, len(r.paths))
+	for repo := range r.paths {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := r.Remind(ctx, repo); err != nil {
+			r.log.WithError(err).WithField("repo", repo).Error("Cannot remind owners")
+		}
+	}
+}
+
+// Remind syncs reminder issues of a given repository. Issues of teams without problems are closed.
+func (r *Reminders) Remind(ctx context.Context, repo string) error {
+	dir := r.paths[repo]
+
+	file, err := codeowners.FindCodeownersFile(dir)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	files, err := git.ListFiles(dir)
+	if err != nil {
+		return errors.Wrap(err, "while listing repository files")
+	}
+
+	reminders, err := BuildReminders(ctx, repo, codeowners.ParseCodeowners(bytes.NewReader(content)), files, r.warnBefore)
+	if err != nil {
+		return err
+	}
+
+	issues := map[string]string{}
+	for _, rem := range reminders {
+		if !rem.IsEmpty() {
+			issues[rem.Title()] = rem.Body()
+		}
+	}
+	if err := r.tracker.Sync(ctx, repo, issues); err != nil {
+		return errors.Wrap(err, "while syncing reminder issues")
+	}
+
+	r.log.WithField("repo", repo).Infof("Synced reminder issues of %d team(s)", len(issues))
+	return nil
+}
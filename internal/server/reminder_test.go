@@ -0,0 +1,176 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/checktest"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReminders(t *testing.T) {
+	// given
+	expired := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+	distant := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	entries := []codeowners.Entry{
+		{LineNo: 1, Pattern: "/docs/", Owners: []string{"@org/docs"}},
+		{LineNo: 2, Pattern: "/src/api/", Owners: []string{"@org/api", "@alice"}, Comment: "# expires:" + expired},
+		{LineNo: 3, Pattern: "/src/web/", Owners: []string{"@org/web"}, Comment: "# expires:" + distant},
+		{LineNo: 4, Pattern: "/legacy/", Owners: []string{"@org/web"}},
+	}
+	files := []string{
+		"docs/README.md",
+		"src/api/api.go",
+		"src/web/index.html",
+		"src/cli/main.go",
+		"tools/gen.go",
+	}
+
+	// when
+	reminders, err := server.BuildReminders(context.Background(), "org/repo", entries, files, 14*24*time.Hour)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []server.Reminder{
+		{
+			Repository: "org/repo",
+			Team:       "@org/api",
+			Entries: []server.ReminderEntry{
+				{LineNo: 2, Pattern: "/src/api/", Problem: fmt.Sprintf("Ownership of %q expired on %s (3 day(s) ago)", "/src/api/", expired)},
+			},
+			UnownedDirectories: []string{"src/cli"},
+		},
+		{Repository: "org/repo", Team: "@org/docs", UnownedDirectories: []string{"tools"}},
+		{
+			Repository: "org/repo",
+			Team:       "@org/web",
+			Entries: []server.ReminderEntry{
+				{LineNo: 4, Pattern: "/legacy/", Problem: "Pattern doesn't match any file"},
+			},
+			UnownedDirectories: []string{"src/cli"},
+		},
+	}, reminders)
+}
+
+func TestReminderBody(t *testing.T) {
+	// given
+	reminder := server.Reminder{
+		Repository:         "org/repo",
+		Team:               "@org/web",
+		Entries:            []server.ReminderEntry{{LineNo: 4, Pattern: "/legacy/", Problem: "Pattern doesn't match any file"}},
+		UnownedDirectories: []string{"src/cli"},
+	}
+
+	// when
+	title, body := reminder.Title(), reminder.Body()
+
+	// then
+	assert.Equal(t, "CODEOWNERS review for @org/web", title)
+	assert.Equal(t, `@org/web, please review the ownership of your areas in the CODEOWNERS file of org/repo.
+
+### Entries
+
+- [ ] line 4 `+"`/legacy/`"+`: Pattern doesn't match any file
+
+### Unowned directories next to your areas
+
+- [ ] `+"`src/cli`"+`
+
+This issue is updated by codeowners and closed once there is nothing left to review.
+`, body)
+}
+
+type recordingTracker struct {
+	issues map[string]map[string]string
+}
+
+func (t *recordingTracker) Sync(_ context.Context, repo string, issues map[string]string) error {
+	t.issues[repo] = issues
+	return nil
+}
+
+func TestRemindersRemind(t *testing.T) {
+	// given
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/docs/ @org/docs\n/src/ @org/dev\n",
+		Files: map[string]string{
+			"docs/README.md": "docs",
+			"src/main.go":    "package main",
+			"tools/gen.go":   "package tools",
+		},
+	})
+	tracker := &recordingTracker{issues: map[string]map[string]string{}}
+	sut := server.NewReminders(logrus.New(), map[string]string{"org/repo": in.RepoDir}, tracker, 0)
+
+	// when
+	err := sut.Remind(context.Background(), "org/repo")
+
+	// then
+	require.NoError(t, err)
+	require.Contains(t, tracker.issues, "org/repo")
+	issues := tracker.issues["org/repo"]
+	assert.Len(t, issues, 2)
+	assert.Contains(t, issues["CODEOWNERS review for @org/docs"], "- [ ] `tools`")
+	assert.Contains(t, issues["CODEOWNERS review for @org/dev"], "- [ ] `tools`")
+}
+
+func TestGitHubIssueTrackerSync(t *testing.T) {
+	// given
+	type call struct {
+		Method string
+		Path   string
+		Body   map[string]interface{}
+	}
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := call{Method: r.Method, Path: r.URL.Path}
+		if r.Method != http.MethodGet {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&c.Body))
+		} else {
+			assert.Equal(t, url.Values{"state": {"open"}, "labels": {server.ReminderLabel}, "per_page": {"100"}}, r.URL.Query())
+			fmt.Fprint(w, `[
+				{"number": 1, "title": "CODEOWNERS review for @org/web", "body": "outdated"},
+				{"number": 2, "title": "CODEOWNERS review for @org/docs", "body": "unchanged"},
+				{"number": 3, "title": "CODEOWNERS review for @org/api", "body": "resolved"},
+				{"number": 4, "title": "CODEOWNERS review for @org/cli", "pull_request": {"url": "https://example.com"}}
+			]`)
+			return
+		}
+		calls = append(calls, c)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	sut := server.NewGitHubIssueTracker(client)
+
+	// when
+	err := sut.Sync(context.Background(), "org/repo", map[string]string{
+		"CODEOWNERS review for @org/web":  "current",
+		"CODEOWNERS review for @org/docs": "unchanged",
+		"CODEOWNERS review for @org/cli":  "new",
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []call{
+		{Method: http.MethodPost, Path: "/repos/org/repo/issues", Body: map[string]interface{}{
+			"title": "CODEOWNERS review for @org/cli", "body": "new", "labels": []interface{}{server.ReminderLabel},
+		}},
+		{Method: http.MethodPatch, Path: "/repos/org/repo/issues/1", Body: map[string]interface{}{"body": "current"}},
+		{Method: http.MethodPatch, Path: "/repos/org/repo/issues/3", Body: map[string]interface{}{"state": "closed"}},
+	}, calls)
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownRepository is returned when the ruleset of a given repository is not available.
+var ErrUnknownRepository = errors.New("unknown repository")
+
+// Ruleset is the parsed CODEOWNERS file of a single repository.
+type Ruleset struct {
+	Repository string
+	Entries    []codeowners.Entry
+	matcher    *codeowners.Matcher
+}
+
+// NewRuleset compiles patterns of all given entries.
+func NewRuleset(repo string, entries []codeowners.Entry) (*Ruleset, error) {
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while compiling CODEOWNERS patterns of %s", repo)
+	}
+
+	return &Ruleset{
+		Repository: repo,
+		Entries:    entries,
+		matcher:    matcher,
+	}, nil
+}
+
+// Resolution describes the owners of a single path.
+type Resolution struct {
+	Path string `json:"path"`
+	// Owners is empty if the path is not owned.
+	Owners []string `json:"owners"`
+	// Pattern is the last matching CODEOWNERS pattern, empty if none matches.
+	Pattern string `json:"pattern,omitempty"`
+	// Line is the CODEOWNERS line number of the matching pattern.
+	Line uint64 `json:"line,omitempty"`
+}
+
+// Resolve returns the owners of a given path.
+func (r *Ruleset) Resolve(path string) Resolution {
+	out := Resolution{Path: path, Owners: []string{}}
+
+	entry, found := r.matcher.Match(path)
+	if !found {
+		return out
+	}
+
+	out.Pattern, out.Line = entry.Pattern, entry.LineNo
+	if len(entry.Owners) > 0 {
+		out.Owners = entry.Owners
+	}
+	return out
+}
+
+// RulesetSource loads the CODEOWNERS ruleset of a given repository.
+type RulesetSource interface {
+	Ruleset(ctx context.Context, repo string) (*Ruleset, error)
+}
+
+// LocalSource loads rulesets from the repositories cloned on the local machine.
+type LocalSource struct {
+	paths map[string]string
+}
+
+// NewLocalSource returns new instance of the LocalSource. Paths are indexed by the repository name, e.g. 'org/repo'.
+func NewLocalSource(paths map[string]string) *LocalSource {
+	return &LocalSource{paths: paths}
+}
+
+// Ruleset reads the CODEOWNERS file of a given repository.
+func (s *LocalSource) Ruleset(_ context.Context, repo string) (*Ruleset, error) {
+	path, found := s.paths[repo]
+	if !found {
+		return nil, ErrUnknownRepository
+	}
+
+	entries, err := codeowners.NewFromPath(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading CODEOWNERS of %s", repo)
+	}
+	return NewRuleset(repo, entries)
+}
+
+// codeownersLocations are checked in the same order as GitHub does.
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// ProviderSource loads rulesets from the default branch of repositories hosted by a given provider.
+type ProviderSource struct {
+	provider provider.Provider
+}
+
+// NewProviderSource returns new instance of the ProviderSource.
+func NewProviderSource(p provider.Provider) *ProviderSource {
+	return &ProviderSource{provider: p}
+}
+
+// Ruleset fetches the CODEOWNERS file of a given repository.
+func (s *ProviderSource) Ruleset(ctx context.Context, repo string) (*Ruleset, error) {
+	split := strings.Split(repo, "/")
+	if len(split) < 2 || split[0] == "" || split[len(split)-1] == "" {
+		return nil, ErrUnknownRepository
+	}
+
+	for _, location := range codeownersLocations {
+		content, err := s.provider.FetchFile(ctx, repo, location, "")
+		switch {
+		case provider.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, errors.Wrapf(err, "while fetching %s of %s", location, repo)
+		}
+		return NewRuleset(repo, codeowners.ParseCodeowners(bytes.NewReader(content)))
+	}
+
+	return nil, ErrUnknownRepository
+}
+
+// ChainSource returns the ruleset from the first source that knows a given repository.
+type ChainSource []RulesetSource
+
+// Ruleset returns the first found ruleset.
+func (c ChainSource) Ruleset(ctx context.Context, repo string) (*Ruleset, error) {
+	for _, s := range c {
+		r, err := s.Ruleset(ctx, repo)
+		if errors.Is(err, ErrUnknownRepository) {
+			continue
+		}
+		return r, err
+	}
+	return nil, ErrUnknownRepository
+}
+
+// maxCachedRulesets limits the number of cached rulesets, as unknown repositories are cached too, and anyone
+// allowed to query the server can ask for arbitrary repository names.
+const maxCachedRulesets = 10000
+
+// CachedSource caches rulesets of a given source for the TTL period. Unknown repositories are cached too,
+// so repeated queries of repositories without CODEOWNERS don't exhaust the API rate limit of the provider.
+// The cache holds a limited number of rulesets. When it's full, expired rulesets are evicted, or the one
+// which expires first if none has expired.
+type CachedSource struct {
+	source  RulesetSource
+	ttl     time.Duration
+	now     func() time.Time
+	maxSize int
+
+	mu       sync.Mutex
+	rulesets map[string]cachedRuleset
+}
+
+type cachedRuleset struct {
+	ruleset *Ruleset
+	// unknown is true if the source doesn't know the repository
+	unknown   bool
+	expiresAt time.Time
+}
+
+// NewCachedSource returns new instance of the CachedSource.
+func NewCachedSource(source RulesetSource, ttl time.Duration) *CachedSource {
+	return &CachedSource{
+		source:   source,
+		ttl:      ttl,
+		now:      time.Now,
+		maxSize:  maxCachedRulesets,
+		rulesets: map[string]cachedRuleset{},
+	}
+}
+
+// Ruleset returns the cached ruleset, or loads it if expired. The ErrUnknownRepository is cached as well,
+// other errors are not cached.
+func (c *CachedSource) Ruleset(ctx context.Context, repo string) (*Ruleset, error) {
+	c.mu.Lock()
+	cached, found := c.rulesets[repo]
+	c.mu.Unlock()
+	if found && c.now().Before(cached.expiresAt) {
+		if cached.unknown {
+			return nil, ErrUnknownRepository
+		}
+		return cached.ruleset, nil
+	}
+
+	r, err := c.source.Ruleset(ctx, repo)
+	unknown := errors.Is(err, ErrUnknownRepository)
+	if err != nil && !unknown {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if _, found := c.rulesets[repo]; !found && len(c.rulesets) >= c.maxSize {
+		c.evict()
+	}
+	c.rulesets[repo] = cachedRuleset{ruleset: r, unknown: unknown, expiresAt: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return r, err
+}
+
+// evict removes expired rulesets, or the one which expires first if none has expired. It must be called
+// with the lock held.
+func (c *CachedSource) evict() {
+	var (
+		now      = c.now()
+		first    string
+		firstExp time.Time
+	)
+	for repo, cached := range c.rulesets {
+		if !now.Before(cached.expiresAt) {
+			delete(c.rulesets, repo)
+			continue
+		}
+		if first == "" || cached.expiresAt.Before(firstExp) {
+			first, firstExp = repo, cached.expiresAt
+		}
+	}
+	if len(c.rulesets) >= c.maxSize {
+		delete(c.rulesets, first)
+	}
+}
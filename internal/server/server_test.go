@@ -0,0 +1,325 @@
+package server_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCODEOWNERS = `
+*          @org/platform
+/docs/     @org/docs
+/vendor/
+`
+
+func TestHandler(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "CODEOWNERS"), []byte(testCODEOWNERS), 0o600))
+
+	source := server.NewLocalSource(map[string]string{"org/repo": repoDir})
+	srv := httptest.NewServer(server.NewHandler(logrus.New(), server.NewService(source)))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		method    string
+		target    string
+		body      string
+		expStatus int
+		expBody   string
+	}{
+		"Get owners": {
+			method:    http.MethodGet,
+			target:    "/owners?repo=org/repo&path=docs/README.md&path=main.go",
+			expStatus: http.StatusOK,
+			expBody: `{"repo":"org/repo","results":[` +
+				`{"path":"docs/README.md","owners":["@org/docs"],"pattern":"/docs/","line":3},` +
+				`{"path":"main.go","owners":["@org/platform"],"pattern":"*","line":2}]}`,
+		},
+		"Match paths": {
+			method:    http.MethodPost,
+			target:    "/match",
+			body:      `{"repo": "org/repo", "paths": ["vendor/lib.go"]}`,
+			expStatus: http.StatusOK,
+			expBody:   `{"repo":"org/repo","results":[{"path":"vendor/lib.go","owners":[],"pattern":"/vendor/","line":4}]}`,
+		},
+		"Unknown repository": {
+			method:    http.MethodGet,
+			target:    "/owners?repo=org/other&path=main.go",
+			expStatus: http.StatusNotFound,
+			expBody:   `{"error":"CODEOWNERS of repository \"org/other\" is not available"}`,
+		},
+		"Missing path": {
+			method:    http.MethodGet,
+			target:    "/owners?repo=org/repo",
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"at least one path is required"}`,
+		},
+		"Missing repository": {
+			method:    http.MethodPost,
+			target:    "/match",
+			body:      `{"paths": ["main.go"]}`,
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"repo is required"}`,
+		},
+		"Malformed body": {
+			method:    http.MethodPost,
+			target:    "/match",
+			body:      `{`,
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"while decoding request body: unexpected EOF"}`,
+		},
+		"Wrong method": {
+			method:    http.MethodPost,
+			target:    "/owners?repo=org/repo&path=main.go",
+			expStatus: http.StatusMethodNotAllowed,
+			expBody:   `{"error":"method POST is not allowed"}`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, srv.URL+tc.target, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			// when
+			resp, err := http.DefaultClient.Do(req)
+
+			// then
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expStatus, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			assert.JSONEq(t, tc.expBody, string(body))
+		})
+	}
+}
+
+func TestHandlerReadToken(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "CODEOWNERS"), []byte(testCODEOWNERS), 0o600))
+
+	source := server.NewLocalSource(map[string]string{"org/repo": repoDir})
+	srv := httptest.NewServer(server.NewHandler(logrus.New(), server.NewService(source)).WithReadToken("secret"))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		method        string
+		target        string
+		authorization string
+		expStatus     int
+	}{
+		"Get owners with token": {
+			method:        http.MethodGet,
+			target:        "/owners?repo=org/repo&path=main.go",
+			authorization: "Bearer secret",
+			expStatus:     http.StatusOK,
+		},
+		"Get owners without token": {
+			method:    http.MethodGet,
+			target:    "/owners?repo=org/repo&path=main.go",
+			expStatus: http.StatusUnauthorized,
+		},
+		"Get owners with token without the Bearer scheme": {
+			method:        http.MethodGet,
+			target:        "/owners?repo=org/repo&path=main.go",
+			authorization: "secret",
+			expStatus:     http.StatusUnauthorized,
+		},
+		"Match paths with wrong token": {
+			method:        http.MethodPost,
+			target:        "/match",
+			authorization: "Bearer other",
+			expStatus:     http.StatusUnauthorized,
+		},
+		"Health check without token": {
+			method:    http.MethodGet,
+			target:    "/healthz",
+			expStatus: http.StatusOK,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, srv.URL+tc.target, strings.NewReader(`{"repo": "org/repo", "paths": ["main.go"]}`))
+			require.NoError(t, err)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			// when
+			resp, err := http.DefaultClient.Do(req)
+
+			// then
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tc.expStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestProviderSource(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/org/repo/contents/.github/CODEOWNERS", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/org/repo/contents/CODEOWNERS", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(testCODEOWNERS)))
+	})
+	mux.HandleFunc("/repos/org/empty/contents/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	sut := server.NewProviderSource(provider.NewGitHub(ghClient))
+
+	// when
+	ruleset, err := sut.Ruleset(context.Background(), "org/repo")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@org/docs"}, ruleset.Resolve("docs/index.md").Owners)
+
+	// when
+	_, err = sut.Ruleset(context.Background(), "org/empty")
+
+	// then
+	assert.ErrorIs(t, err, server.ErrUnknownRepository)
+}
+
+type countingSource struct {
+	calls int
+}
+
+func (s *countingSource) Ruleset(_ context.Context, repo string) (*server.Ruleset, error) {
+	s.calls++
+	return server.NewRuleset(repo, codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS)))
+}
+
+func TestCachedSource(t *testing.T) {
+	// given
+	source := &countingSource{}
+	sut := server.NewCachedSource(source, time.Hour)
+
+	// when
+	for i := 0; i < 3; i++ {
+		_, err := sut.Ruleset(context.Background(), "org/repo")
+		require.NoError(t, err)
+	}
+	_, err := sut.Ruleset(context.Background(), "org/other")
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, 2, source.calls)
+}
+
+func TestCachedSourceUnknownRepository(t *testing.T) {
+	// given
+	source := &unknownSource{}
+	now := time.Now()
+	sut := server.NewCachedSource(source, time.Hour)
+	sut.SetClock(func() time.Time { return now })
+
+	// when
+	for i := 0; i < 3; i++ {
+		_, err := sut.Ruleset(context.Background(), "org/missing")
+		require.ErrorIs(t, err, server.ErrUnknownRepository)
+	}
+	cached := source.calls
+	now = now.Add(2 * time.Hour)
+	_, err := sut.Ruleset(context.Background(), "org/missing")
+
+	// then
+	require.ErrorIs(t, err, server.ErrUnknownRepository)
+	assert.Equal(t, 1, cached)
+	assert.Equal(t, 2, source.calls)
+}
+
+func TestCachedSourceEvictsRulesets(t *testing.T) {
+	// given
+	source := &unknownSource{}
+	now := time.Now()
+	sut := server.NewCachedSource(source, time.Hour)
+	sut.SetClock(func() time.Time { return now })
+	sut.SetMaxSize(2)
+
+	query := func(repo string) {
+		_, err := sut.Ruleset(context.Background(), repo)
+		require.ErrorIs(t, err, server.ErrUnknownRepository)
+	}
+
+	// when the cache is full
+	query("org/first")
+	now = now.Add(time.Minute)
+	query("org/second")
+	query("org/third")
+
+	// then the ruleset which expires first is evicted
+	assert.Equal(t, 2, sut.Len())
+	query("org/second")
+	query("org/third")
+	assert.Equal(t, 3, source.calls)
+
+	// when all rulesets expired
+	now = now.Add(2 * time.Hour)
+	query("org/fourth")
+
+	// then
+	assert.Equal(t, 1, sut.Len())
+}
+
+type unknownSource struct {
+	calls int
+}
+
+func (s *unknownSource) Ruleset(context.Context, string) (*server.Ruleset, error) {
+	s.calls++
+	return nil, server.ErrUnknownRepository
+}
+
+func TestChainSource(t *testing.T) {
+	// given
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "CODEOWNERS"), []byte("* @org/local\n"), 0o600))
+
+	sut := server.ChainSource{
+		server.NewLocalSource(map[string]string{"org/local": repoDir}),
+		&countingSource{},
+	}
+
+	// when
+	local, err := sut.Ruleset(context.Background(), "org/local")
+	require.NoError(t, err)
+	other, err := sut.Ruleset(context.Background(), "org/other")
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, []string{"@org/local"}, local.Resolve("main.go").Owners)
+	assert.Equal(t, []string{"@org/platform"}, other.Resolve("main.go").Owners)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// Service resolves ownership of repository paths.
+type Service struct {
+	source  RulesetSource
+	checks  []api.Checker
+	tenants *Tenants
+}
+
+// NewService returns new instance of the Service. Given checks are executed by ValidateFile,
+// so they must not depend on the repository content, e.g. syntax or duplicated patterns checks.
+func NewService(source RulesetSource, checks ...api.Checker) *Service {
+	return &Service{source: source, checks: checks}
+}
+
+// WithTenants applies policies of given tenants to the validated CODEOWNERS files. The checks of the service are
+// then replaced by the checks enabled by policies.
+func (s *Service) WithTenants(tenants *Tenants) *Service {
+	s.tenants = tenants
+	return s
+}
+
+// ResolveOwners returns the owners of given paths in a given repository.
+func (s *Service) ResolveOwners(ctx context.Context, repo string, paths []string) ([]Resolution, error) {
+	r, err := s.source.Ruleset(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Resolution, 0, len(paths))
+	for _, p := range paths {
+		out = append(out, r.Resolve(p))
+	}
+	return out, nil
+}
+
+// ValidateFile executes the offline checks against a given CODEOWNERS content. If tenants are configured,
+// the default policy is applied, as the repository of the content is not known.
+func (s *Service) ValidateFile(ctx context.Context, content string) ([]api.Issue, error) {
+	if s.tenants == nil {
+		return s.validate(ctx, content, s.serviceChecks())
+	}
+
+	cfg, err := s.tenants.Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.validate(ctx, content, s.tenants.policyChecks(cfg.Default))
+}
+
+// ValidateRepositoryFile executes the offline checks enabled by the policy of the tenant of a given repository.
+func (s *Service) ValidateRepositoryFile(ctx context.Context, repo, content string) ([]api.Issue, error) {
+	if s.tenants == nil {
+		return s.validate(ctx, content, s.serviceChecks())
+	}
+
+	tenant, err := s.tenants.Resolve(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return s.validate(ctx, content, s.tenants.policyChecks(tenant.Policy))
+}
+
+func (s *Service) serviceChecks() []policyCheck {
+	out := make([]policyCheck, 0, len(s.checks))
+	for _, c := range s.checks {
+		out = append(out, policyCheck{checker: c})
+	}
+	return out
+}
+
+func (s *Service) validate(ctx context.Context, content string, checks []policyCheck) ([]api.Issue, error) {
+	in := api.Input{
+		CodeownersEntries: codeowners.ParseCodeowners(strings.NewReader(content)),
+	}
+
+	var out []api.Issue
+	for _, c := range checks {
+		res, err := c.checker.Check(ctx, in)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while executing %s", c.checker.Name())
+		}
+		for _, i := range res.Issues {
+			if c.severity != nil {
+				i.Severity = *c.severity
+			}
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
+
+// Coverage summarizes how many paths are owned.
+type Coverage struct {
+	Total        uint64
+	Owned        uint64
+	UnownedPaths []string
+}
+
+// Add records the ownership of a single path.
+func (c *Coverage) Add(r Resolution) {
+	c.Total++
+	if len(r.Owners) > 0 {
+		c.Owned++
+		return
+	}
+	c.UnownedPaths = append(c.UnownedPaths, r.Path)
+}
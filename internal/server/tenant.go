@@ -0,0 +1,354 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTenant is the name of the tenant which repositories are not assigned to any configured tenant.
+const DefaultTenant = "default"
+
+// TenantConfig assigns repositories to tenants, so a single server serves business units with different policies, e.g.:
+//
+//	default:
+//	  checks: [syntax, duppatterns]
+//	  notify-url: https://hooks.example.com/platform
+//	tenants:
+//	  - name: payments
+//	    organizations: [acme-payments]
+//	    repositories: [acme/billing]
+//	    checks: [syntax, duppatterns, owner-casing]
+//	    severities:
+//	      owner-casing: error
+//	    notify-url: https://hooks.example.com/payments
+type TenantConfig struct {
+	// Default is the policy of repositories not assigned to any tenant. Its fields are inherited by all tenants.
+	Default Policy `yaml:"default"`
+	// Tenants to which repositories are assigned.
+	Tenants []Tenant `yaml:"tenants"`
+}
+
+// Tenant is a business unit which repositories share the same policy.
+type Tenant struct {
+	Name string `yaml:"name"`
+	// Organizations are owners of the tenant repositories, e.g. 'acme-payments'. A GitHub App is installed
+	// per organization, so they also select the app installations of the tenant.
+	Organizations []string `yaml:"organizations"`
+	// Repositories in form 'owner/repository'. They take precedence over organizations of other tenants,
+	// so a single repository of a shared organization can be assigned to a tenant.
+	Repositories []string `yaml:"repositories"`
+
+	Policy `yaml:",inline"`
+}
+
+// Policy configures the offline checks and notifications. Empty fields of tenant policies are inherited from the default one.
+type Policy struct {
+	// Checks are IDs of the enabled offline checks, e.g. 'syntax'. All offline checks are enabled if empty.
+	Checks []string `yaml:"checks"`
+	// Severities override the severity of issues reported by given checks, e.g. 'owner-casing: warning'.
+	Severities map[string]string `yaml:"severities"`
+	// NotifyURL is the webhook URL to which changed findings are posted.
+	NotifyURL string `yaml:"notify-url"`
+}
+
+// LoadTenantConfig decodes the tenants configuration. The origin describes where the configuration comes from
+// and is used only in error messages.
+func LoadTenantConfig(raw []byte, origin string) (*TenantConfig, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+
+	var out TenantConfig
+	if err := dec.Decode(&out); err != nil {
+		return nil, errors.Wrapf(err, "while decoding tenants configuration %s", origin)
+	}
+
+	names := map[string]struct{}{}
+	assigned := map[string]string{}
+	for idx, t := range out.Tenants {
+		switch {
+		case t.Name == "":
+			return nil, errors.Errorf("tenant #%d in %s: name is required", idx+1, origin)
+		case t.Name == DefaultTenant:
+			return nil, errors.Errorf("tenant #%d in %s: name %q is reserved for repositories without a tenant", idx+1, origin, DefaultTenant)
+		case len(t.Organizations) == 0 && len(t.Repositories) == 0:
+			return nil, errors.Errorf("tenant %q in %s: at least one organization or repository is required", t.Name, origin)
+		}
+		if _, found := names[t.Name]; found {
+			return nil, errors.Errorf("tenant %q in %s: name is already used", t.Name, origin)
+		}
+		names[t.Name] = struct{}{}
+
+		for _, repo := range t.Repositories {
+			if owner, name, found := strings.Cut(repo, "/"); !found || owner == "" || name == "" || strings.Contains(name, "/") {
+				return nil, errors.Errorf("tenant %q in %s: wrong repository name, expected pattern 'owner/repository', got %q", t.Name, origin, repo)
+			}
+		}
+		for _, key := range append(append([]string{}, t.Organizations...), t.Repositories...) {
+			key = strings.ToLower(key)
+			if other, found := assigned[key]; found {
+				return nil, errors.Errorf("tenant %q in %s: %q is already assigned to tenant %q", t.Name, origin, key, other)
+			}
+			assigned[key] = t.Name
+		}
+	}
+
+	return &out, nil
+}
+
+// Tenant returns the tenant of a given repository with the policy merged with the default one. Repository and
+// organization names are case-insensitive, as on GitHub. Repositories without a tenant get the DefaultTenant.
+func (c *TenantConfig) Tenant(repo string) Tenant {
+	repo = strings.ToLower(repo)
+	org, _, _ := strings.Cut(repo, "/")
+
+	var match *Tenant
+	for idx := range c.Tenants {
+		t := &c.Tenants[idx]
+		if containsFold(t.Repositories, repo) {
+			match = t
+			break
+		}
+		if match == nil && containsFold(t.Organizations, org) {
+			match = t
+		}
+	}
+	if match == nil {
+		return Tenant{Name: DefaultTenant, Policy: c.Default}
+	}
+
+	out := *match
+	out.Policy = c.Default.merge(match.Policy)
+	return out
+}
+
+// merge returns the policy with empty fields of a given policy inherited from p.
+func (p Policy) merge(o Policy) Policy {
+	out := Policy{Checks: p.Checks, NotifyURL: p.NotifyURL, Severities: map[string]string{}}
+	if len(o.Checks) > 0 {
+		out.Checks = o.Checks
+	}
+	if o.NotifyURL != "" {
+		out.NotifyURL = o.NotifyURL
+	}
+	for _, sev := range []map[string]string{p.Severities, o.Severities} {
+		for id, level := range sev {
+			out.Severities[id] = level
+		}
+	}
+	return out
+}
+
+func containsFold(items []string, s string) bool {
+	for _, i := range items {
+		if strings.EqualFold(i, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantSource loads the tenants configuration.
+type TenantSource interface {
+	Load(ctx context.Context) (*TenantConfig, error)
+}
+
+// FileTenantSource loads the tenants configuration from a local file, e.g. a checkout of the central config repository.
+type FileTenantSource struct {
+	path string
+}
+
+// NewFileTenantSource returns new instance of the FileTenantSource.
+func NewFileTenantSource(path string) *FileTenantSource {
+	return &FileTenantSource{path: path}
+}
+
+// Load reads the tenants file.
+func (s *FileTenantSource) Load(_ context.Context) (*TenantConfig, error) {
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading tenants configuration")
+	}
+	return LoadTenantConfig(raw, s.path)
+}
+
+// RepositoryTenantSource loads the tenants configuration from the default branch of the central config repository
+// hosted by a given provider, so policies are changed by pull requests reviewed like any other code.
+type RepositoryTenantSource struct {
+	provider provider.Provider
+	repo     string
+	path     string
+}
+
+// NewRepositoryTenantSource returns new instance of the RepositoryTenantSource.
+func NewRepositoryTenantSource(p provider.Provider, repo, path string) *RepositoryTenantSource {
+	return &RepositoryTenantSource{provider: p, repo: repo, path: path}
+}
+
+// Load fetches the tenants file.
+func (s *RepositoryTenantSource) Load(ctx context.Context) (*TenantConfig, error) {
+	raw, err := s.provider.FetchFile(ctx, s.repo, s.path, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "while fetching tenants configuration %s of %s", s.path, s.repo)
+	}
+	return LoadTenantConfig(raw, s.repo+":"+s.path)
+}
+
+// Tenants resolves policies of repositories. The configuration is reloaded after the TTL period,
+// so policy changes are applied without restarting the server.
+type Tenants struct {
+	source TenantSource
+	checks map[string]api.Checker
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	cfg       *TenantConfig
+	expiresAt time.Time
+}
+
+// NewTenants returns new instance of the Tenants. Checks are the offline checks available to policies,
+// indexed by their IDs, e.g. 'syntax'.
+func NewTenants(source TenantSource, checks map[string]api.Checker, ttl time.Duration) *Tenants {
+	return &Tenants{
+		source: source,
+		checks: checks,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Config returns the cached configuration, or loads it if expired. Configurations which enable unknown
+// checks or set wrong severities are rejected, and errors are not cached.
+func (t *Tenants) Config(ctx context.Context) (*TenantConfig, error) {
+	t.mu.Lock()
+	cfg, expiresAt := t.cfg, t.expiresAt
+	t.mu.Unlock()
+	if cfg != nil && t.now().Before(expiresAt) {
+		return cfg, nil
+	}
+
+	cfg, err := t.source.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validate(cfg); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.cfg, t.expiresAt = cfg, t.now().Add(t.ttl)
+	t.mu.Unlock()
+
+	return cfg, nil
+}
+
+// Resolve returns the tenant of a given repository.
+func (t *Tenants) Resolve(ctx context.Context, repo string) (Tenant, error) {
+	cfg, err := t.Config(ctx)
+	if err != nil {
+		return Tenant{}, err
+	}
+	return cfg.Tenant(repo), nil
+}
+
+func (t *Tenants) validate(cfg *TenantConfig) error {
+	policies := map[string]Policy{DefaultTenant: cfg.Default}
+	for _, tenant := range cfg.Tenants {
+		policies[tenant.Name] = tenant.Policy
+	}
+
+	for name, p := range policies {
+		for _, id := range p.Checks {
+			if _, found := t.checks[id]; !found {
+				return errors.Errorf("tenant %q: unknown offline check %q, expected one of %s", name, id, strings.Join(t.checkIDs(), ", "))
+			}
+		}
+		for id, level := range p.Severities {
+			if _, found := t.checks[id]; !found {
+				return errors.Errorf("tenant %q: severity of unknown offline check %q", name, id)
+			}
+			var sev api.SeverityType
+			if err := sev.Unmarshal(level); err != nil {
+				return errors.Wrapf(err, "tenant %q: severity of %q", name, id)
+			}
+		}
+	}
+	return nil
+}
+
+// policyCheck is a check enabled by a policy with the optional severity override.
+type policyCheck struct {
+	checker  api.Checker
+	severity *api.SeverityType
+}
+
+// policyChecks returns checks enabled by a given policy. If the policy doesn't list checks, all of them
+// are returned in the order of their IDs.
+func (t *Tenants) policyChecks(p Policy) []policyCheck {
+	ids := p.Checks
+	if len(ids) == 0 {
+		ids = t.checkIDs()
+	}
+
+	out := make([]policyCheck, 0, len(ids))
+	for _, id := range ids {
+		pc := policyCheck{checker: t.checks[id]}
+		if level, found := p.Severities[id]; found {
+			var sev api.SeverityType
+			// already validated when the configuration was loaded
+			_ = sev.Unmarshal(level)
+			pc.severity = &sev
+		}
+		out = append(out, pc)
+	}
+	return out
+}
+
+func (t *Tenants) checkIDs() []string {
+	out := make([]string, 0, len(t.checks))
+	for id := range t.checks {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// TenantNotifier posts changed findings to the webhook of the tenant which the repository is assigned to.
+type TenantNotifier struct {
+	tenants  *Tenants
+	fallback string
+}
+
+// NewTenantNotifier returns new instance of the TenantNotifier. The fallback URL is used by tenants
+// which policy doesn't set the webhook URL.
+func NewTenantNotifier(tenants *Tenants, fallback string) *TenantNotifier {
+	return &TenantNotifier{tenants: tenants, fallback: fallback}
+}
+
+// Notify posts the diff to the tenant webhook. Findings of tenants without any webhook are not posted.
+func (n *TenantNotifier) Notify(ctx context.Context, diff SnapshotDiff) error {
+	tenant, err := n.tenants.Resolve(ctx, diff.Repository)
+	if err != nil {
+		return err
+	}
+
+	url := tenant.NotifyURL
+	if url == "" {
+		url = n.fallback
+	}
+	if url == "" {
+		return nil
+	}
+	return NewWebhookNotifier(url).Notify(ctx, diff)
+}
@@ -0,0 +1,261 @@
+package server_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/provider"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTenants = `
+default:
+  checks: [syntax]
+  notify-url: https://hooks.example.com/default
+tenants:
+  - name: payments
+    organizations: [acme-payments]
+    repositories: [acme/billing]
+    checks: [syntax, duppatterns]
+    severities:
+      duppatterns: error
+    notify-url: https://hooks.example.com/payments
+  - name: platform
+    organizations: [acme]
+    severities:
+      syntax: warning
+`
+
+func testTenantChecks() map[string]api.Checker {
+	return map[string]api.Checker{
+		"syntax":      check.NewValidSyntax(),
+		"duppatterns": check.NewDuplicatedPattern(),
+	}
+}
+
+func TestTenantConfigTenant(t *testing.T) {
+	// given
+	cfg, err := server.LoadTenantConfig([]byte(testTenants), "tenants.yaml")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		givenRepo    string
+		expName      string
+		expChecks    []string
+		expSevs      map[string]string
+		expNotifyURL string
+	}{
+		"Should assign repository by organization": {
+			givenRepo:    "acme-payments/gateway",
+			expName:      "payments",
+			expChecks:    []string{"syntax", "duppatterns"},
+			expSevs:      map[string]string{"duppatterns": "error"},
+			expNotifyURL: "https://hooks.example.com/payments",
+		},
+		"Should prefer repository over organization of other tenant": {
+			givenRepo:    "ACME/Billing",
+			expName:      "payments",
+			expChecks:    []string{"syntax", "duppatterns"},
+			expSevs:      map[string]string{"duppatterns": "error"},
+			expNotifyURL: "https://hooks.example.com/payments",
+		},
+		"Should inherit empty fields from default policy": {
+			givenRepo:    "acme/website",
+			expName:      "platform",
+			expChecks:    []string{"syntax"},
+			expSevs:      map[string]string{"syntax": "warning"},
+			expNotifyURL: "https://hooks.example.com/default",
+		},
+		"Should return default tenant for other repositories": {
+			givenRepo:    "other/repo",
+			expName:      server.DefaultTenant,
+			expChecks:    []string{"syntax"},
+			expNotifyURL: "https://hooks.example.com/default",
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			tenant := cfg.Tenant(tc.givenRepo)
+
+			// then
+			assert.Equal(t, tc.expName, tenant.Name)
+			assert.Equal(t, tc.expChecks, tenant.Checks)
+			if tc.expSevs != nil {
+				assert.Equal(t, tc.expSevs, tenant.Severities)
+			} else {
+				assert.Empty(t, tenant.Severities)
+			}
+			assert.Equal(t, tc.expNotifyURL, tenant.NotifyURL)
+		})
+	}
+}
+
+func TestLoadTenantConfigErrors(t *testing.T) {
+	tests := map[string]struct {
+		givenConfig string
+		expErr      string
+	}{
+		"Should reject tenant without name": {
+			givenConfig: "tenants:\n  - organizations: [acme]\n",
+			expErr:      "tenant #1 in tenants.yaml: name is required",
+		},
+		"Should reject reserved name": {
+			givenConfig: "tenants:\n  - name: default\n    organizations: [acme]\n",
+			expErr:      `tenant #1 in tenants.yaml: name "default" is reserved for repositories without a tenant`,
+		},
+		"Should reject tenant without repositories": {
+			givenConfig: "tenants:\n  - name: payments\n",
+			expErr:      `tenant "payments" in tenants.yaml: at least one organization or repository is required`,
+		},
+		"Should reject wrong repository name": {
+			givenConfig: "tenants:\n  - name: payments\n    repositories: [billing]\n",
+			expErr:      `tenant "payments" in tenants.yaml: wrong repository name, expected pattern 'owner/repository', got "billing"`,
+		},
+		"Should reject organization assigned twice": {
+			givenConfig: "tenants:\n  - name: payments\n    organizations: [acme]\n  - name: platform\n    organizations: [ACME]\n",
+			expErr:      `tenant "platform" in tenants.yaml: "acme" is already assigned to tenant "payments"`,
+		},
+		"Should reject unknown fields": {
+			givenConfig: "tenants:\n  - name: payments\n    orgs: [acme]\n",
+			expErr:      "while decoding tenants configuration tenants.yaml: yaml: unmarshal errors:\n  line 3: field orgs not found in type server.Tenant",
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := server.LoadTenantConfig([]byte(tc.givenConfig), "tenants.yaml")
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestTenantsConfigValidatesPolicies(t *testing.T) {
+	tests := map[string]struct {
+		givenConfig string
+		expErr      string
+	}{
+		"Should reject unknown check": {
+			givenConfig: "default:\n  checks: [files]\n",
+			expErr:      `tenant "default": unknown offline check "files", expected one of duppatterns, syntax`,
+		},
+		"Should reject wrong severity": {
+			givenConfig: "tenants:\n  - name: payments\n    organizations: [acme]\n    severities:\n      syntax: fatal\n",
+			expErr:      `tenant "payments": severity of "syntax": not a valid severity type: "fatal"`,
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			file := filepath.Join(t.TempDir(), "tenants.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tc.givenConfig), 0o600))
+			sut := server.NewTenants(server.NewFileTenantSource(file), testTenantChecks(), time.Hour)
+
+			// when
+			_, err := sut.Config(context.Background())
+
+			// then
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestServiceValidateRepositoryFile(t *testing.T) {
+	// given
+	file := filepath.Join(t.TempDir(), "tenants.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testTenants), 0o600))
+
+	tenants := server.NewTenants(server.NewFileTenantSource(file), testTenantChecks(), time.Hour)
+	sut := server.NewService(server.NewLocalSource(nil)).WithTenants(tenants)
+	content := "* @org/team-a\n* @org/team-b\n/docs/ old-team\n"
+
+	// when
+	payments, err := sut.ValidateRepositoryFile(context.Background(), "acme-payments/gateway", content)
+	require.NoError(t, err)
+	platform, err := sut.ValidateRepositoryFile(context.Background(), "acme/website", content)
+	require.NoError(t, err)
+
+	// then
+	require.Len(t, payments, 2)
+	assert.Equal(t, api.Error, payments[0].Severity)
+	assert.Equal(t, "Owner 'old-team' does not look like an email", payments[0].Message)
+	assert.Equal(t, api.Error, payments[1].Severity)
+	assert.Contains(t, payments[1].Message, `Pattern "*" is defined 2 times`)
+
+	require.Len(t, platform, 1)
+	assert.Equal(t, api.Warning, platform[0].Severity)
+}
+
+func TestTenantNotifier(t *testing.T) {
+	// given
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var diff server.SnapshotDiff
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&diff))
+		got = append(got, r.URL.Path+" "+diff.Repository)
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "tenants.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(`
+tenants:
+  - name: payments
+    organizations: [acme-payments]
+    notify-url: %s/payments
+  - name: platform
+    organizations: [acme]
+`, srv.URL)), 0o600))
+
+	tenants := server.NewTenants(server.NewFileTenantSource(file), testTenantChecks(), time.Hour)
+	sut := server.NewTenantNotifier(tenants, srv.URL+"/fallback")
+
+	// when
+	for _, repo := range []string{"acme-payments/gateway", "acme/website"} {
+		require.NoError(t, sut.Notify(context.Background(), server.SnapshotDiff{Repository: repo}))
+	}
+
+	// then
+	assert.Equal(t, []string{"/payments acme-payments/gateway", "/fallback acme/website"}, got)
+}
+
+func TestRepositoryTenantSource(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/config/contents/codeowners/tenants.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(testTenants)))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	sut := server.NewRepositoryTenantSource(provider.NewGitHub(ghClient), "acme/config", "codeowners/tenants.yaml")
+
+	// when
+	cfg, err := sut.Load(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "payments", cfg.Tenant("acme/billing").Name)
+}
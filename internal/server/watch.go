@@ -0,0 +1,369 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/internal/netguard"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of findings recorded in snapshots.
+const (
+	// UnownedDirectory is a directory with files which don't have any owner.
+	UnownedDirectory = "unowned-directory"
+	// CheckIssue is an issue reported by the offline checks, e.g. an invalid owner.
+	CheckIssue = "issue"
+)
+
+// Finding is a single ownership problem found in a repository.
+type Finding struct {
+	Kind string `json:"kind"`
+	// Subject is the directory for unowned directories, and the issue severity and message for issues.
+	// The line of the issue is not part of the finding, so moving an entry doesn't change it.
+	Subject string `json:"subject"`
+}
+
+// Snapshot holds findings of a single repository at a given time.
+type Snapshot struct {
+	Repository string    `json:"repository"`
+	TakenAt    time.Time `json:"takenAt"`
+	Findings   []Finding `json:"findings"`
+}
+
+// SnapshotDiff describes how findings of a repository changed since the previous snapshot.
+type SnapshotDiff struct {
+	Repository string    `json:"repository"`
+	Since      time.Time `json:"since"`
+	TakenAt    time.Time `json:"takenAt"`
+	Added      []Finding `json:"added"`
+	Resolved   []Finding `json:"resolved"`
+}
+
+// IsEmpty returns true if findings didn't change.
+func (d SnapshotDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Resolved) == 0
+}
+
+// DiffSnapshots returns findings added and resolved between two snapshots.
+func DiffSnapshots(prev, cur Snapshot) SnapshotDiff {
+	out := SnapshotDiff{Repository: cur.Repository, Since: prev.TakenAt, TakenAt: cur.TakenAt}
+
+	prevSet := map[Finding]struct{}{}
+	for _, f := range prev.Findings {
+		prevSet[f] = struct{}{}
+	}
+	curSet := map[Finding]struct{}{}
+	for _, f := range cur.Findings {
+		curSet[f] = struct{}{}
+		if _, found := prevSet[f]; !found {
+			out.Added = append(out.Added, f)
+		}
+	}
+	for _, f := range prev.Findings {
+		if _, found := curSet[f]; !found {
+			out.Resolved = append(out.Resolved, f)
+		}
+	}
+	return out
+}
+
+// SnapshotStore persists the last snapshot of each repository.
+type SnapshotStore interface {
+	// Load returns the last snapshot of a given repository, or nil if there is none.
+	Load(repo string) (*Snapshot, error)
+	Save(s Snapshot) error
+}
+
+// SnapshotHistory is implemented by snapshot stores which keep all snapshots, e.g. databases.
+type SnapshotHistory interface {
+	// History returns snapshots of a given repository taken since a given time, the oldest first.
+	History(repo string, since time.Time) ([]Snapshot, error)
+}
+
+// ErrHistoryNotSupported is returned when the snapshot store keeps only the last snapshot.
+var ErrHistoryNotSupported = errors.New("snapshot store doesn't keep the history of snapshots")
+
+// FileSnapshotStore stores snapshots as JSON files in a given directory.
+type FileSnapshotStore struct {
+	dir string
+}
+
+// NewFileSnapshotStore returns new instance of the FileSnapshotStore.
+func NewFileSnapshotStore(dir string) *FileSnapshotStore {
+	return &FileSnapshotStore{dir: dir}
+}
+
+// Load reads the snapshot file of a given repository.
+func (s *FileSnapshotStore) Load(repo string) (*Snapshot, error) {
+	raw, err := os.ReadFile(s.path(repo))
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var out Snapshot
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, errors.Wrapf(err, "while decoding snapshot of %s", repo)
+	}
+	return &out, nil
+}
+
+// Save writes the snapshot file. The file is replaced atomically, so a crash doesn't leave a partial snapshot.
+func (s *FileSnapshotStore) Save(snapshot Snapshot) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(snapshot.Repository))
+}
+
+func (s *FileSnapshotStore) path(repo string) string {
+	return filepath.Join(s.dir, strings.ReplaceAll(repo, "/", "__")+".json")
+}
+
+// Notifier sends notifications about changed findings.
+type Notifier interface {
+	Notify(ctx context.Context, diff SnapshotDiff) error
+}
+
+// WebhookNotifier posts the snapshot diff as JSON to a given URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns new instance of the WebhookNotifier.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 30 * time.Second, Transport: netguard.BaseTransport()}}
+}
+
+// Notify posts the diff. Responses with status codes other than 2xx are errors.
+func (n *WebhookNotifier) Notify(ctx context.Context, diff SnapshotDiff) error {
+	raw, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// Watcher periodically takes snapshots of findings in local repositories, and notifies only when they change.
+// The first snapshot of a repository is the baseline, so it doesn't trigger a notification.
+type Watcher struct {
+	log      logrus.FieldLogger
+	svc      *Service
+	paths    map[string]string
+	store    SnapshotStore
+	notifier Notifier
+	now      func() time.Time
+
+	// mu serializes scans, so on-demand scans don't race with the periodic ones
+	mu sync.Mutex
+}
+
+// NewWatcher returns new instance of the Watcher. Paths are indexed by the repository name, e.g. 'org/repo'.
+// If the notifier is nil, snapshots are only recorded.
+func NewWatcher(log logrus.FieldLogger, svc *Service, paths map[string]string, store SnapshotStore, notifier Notifier) *Watcher {
+	return &Watcher{
+		log:      log.WithField("service", "watcher"),
+		svc:      svc,
+		paths:    paths,
+		store:    store,
+		notifier: notifier,
+		now:      time.Now,
+	}
+}
+
+// Run scans all repositories immediately and then in a given interval, until the context is canceled.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.ScanAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ScanAll scans all repositories. Errors are logged, so a single broken repository doesn't block others.
+func (w *Watcher) ScanAll(ctx context.Context) {
+	for _, repo := range w.Repositories() {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := w.Scan(ctx, repo); err != nil {
+			w.log.WithError(err).WithField("repo", repo).Error("Cannot scan repository")
+		}
+	}
+}
+
+// Repositories returns sorted names of the watched repositories.
+func (w *Watcher) Repositories() []string {
+	out := make([]string, 0, len(w.paths))
+	for repo := range w.paths {
+		out = append(out, repo)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Path returns the local path of a given repository.
+func (w *Watcher) Path(repo string) (string, bool) {
+	p, found := w.paths[repo]
+	return p, found
+}
+
+// LastSnapshot returns the last snapshot of a given repository, or nil if it wasn't scanned yet.
+func (w *Watcher) LastSnapshot(repo string) (*Snapshot, error) {
+	return w.store.Load(repo)
+}
+
+// History returns snapshots of a given repository taken since a given time. It returns ErrHistoryNotSupported
+// if the store doesn't implement SnapshotHistory.
+func (w *Watcher) History(repo string, since time.Time) ([]Snapshot, error) {
+	h, ok := w.store.(SnapshotHistory)
+	if !ok {
+		return nil, ErrHistoryNotSupported
+	}
+	return h.History(repo, since)
+}
+
+// Scan takes a snapshot of a given repository, and notifies if findings changed since the previous one.
+// The snapshot is saved only after the notification is sent, so failed notifications are retried by the next scan.
+func (w *Watcher) Scan(ctx context.Context, repo string) error {
+	if _, found := w.paths[repo]; !found {
+		return ErrUnknownRepository
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cur, err := w.snapshot(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	prev, err := w.store.Load(repo)
+	if err != nil {
+		return errors.Wrap(err, "while loading previous snapshot")
+	}
+	if prev != nil && w.notifier != nil {
+		// unchanged snapshots are saved too, so the history has no gaps and the last scan time is up to date
+		if diff := DiffSnapshots(*prev, cur); !diff.IsEmpty() {
+			if err := w.notifier.Notify(ctx, diff); err != nil {
+				return errors.Wrap(err, "while sending notification")
+			}
+			w.log.WithField("repo", repo).Infof("Notified about %d new and %d resolved findings", len(diff.Added), len(diff.Resolved))
+		}
+	}
+
+	return w.store.Save(cur)
+}
+
+func (w *Watcher) snapshot(ctx context.Context, repo string) (Snapshot, error) {
+	dir := w.paths[repo]
+	out := Snapshot{Repository: repo, TakenAt: w.now().UTC(), Findings: []Finding{}}
+
+	file, err := codeowners.FindCodeownersFile(dir)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	ruleset, err := NewRuleset(repo, codeowners.ParseCodeowners(bytes.NewReader(content)))
+	if err != nil {
+		return Snapshot{}, err
+	}
+	files, err := git.ListFiles(dir)
+	if err != nil {
+		return Snapshot{}, errors.Wrap(err, "while listing repository files")
+	}
+
+	unowned := map[string]struct{}{}
+	for _, f := range files {
+		if len(ruleset.Resolve(f).Owners) == 0 {
+			unowned[path.Dir(f)] = struct{}{}
+		}
+	}
+	for d := range unowned {
+		out.Findings = append(out.Findings, Finding{Kind: UnownedDirectory, Subject: d})
+	}
+
+	issues, err := w.svc.ValidateRepositoryFile(ctx, repo, string(content))
+	if err != nil {
+		return Snapshot{}, err
+	}
+	seen := map[string]struct{}{}
+	for _, i := range issues {
+		if _, found := seen[i.Message]; found {
+			continue
+		}
+		seen[i.Message] = struct{}{}
+		out.Findings = append(out.Findings, Finding{Kind: CheckIssue, Subject: fmt.Sprintf("[%s] %s", strings.ToLower(i.Severity.String()), i.Message)})
+	}
+
+	sort.Slice(out.Findings, func(i, j int) bool {
+		if out.Findings[i].Kind != out.Findings[j].Kind {
+			return out.Findings[i].Kind < out.Findings[j].Kind
+		}
+		return out.Findings[i].Subject < out.Findings[j].Subject
+	})
+	return out, nil
+}
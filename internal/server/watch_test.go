@@ -0,0 +1,155 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/pkg/checktest"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	diffs []server.SnapshotDiff
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, diff server.SnapshotDiff) error {
+	n.diffs = append(n.diffs, diff)
+	return nil
+}
+
+func TestWatcherScan(t *testing.T) {
+	// given
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/docs/ @org/docs\n",
+		Files: map[string]string{
+			"docs/README.md":   "docs",
+			"src/main.go":      "package main",
+			"src/util/util.go": "package util",
+		},
+	})
+
+	notifier := &recordingNotifier{}
+	svc := server.NewService(server.NewLocalSource(nil), check.NewValidSyntax())
+	sut := server.NewWatcher(logrus.New(), svc, map[string]string{"org/repo": in.RepoDir}, server.NewFileSnapshotStore(t.TempDir()), notifier)
+
+	// when the baseline is taken and nothing changes
+	require.NoError(t, sut.Scan(context.Background(), "org/repo"))
+	require.NoError(t, sut.Scan(context.Background(), "org/repo"))
+
+	// then
+	assert.Empty(t, notifier.diffs)
+
+	// when findings change
+	codeowners := "/docs/ @org/docs\n/src/ old-team\n"
+	require.NoError(t, os.WriteFile(filepath.Join(in.RepoDir, "CODEOWNERS"), []byte(codeowners), 0o600))
+	require.NoError(t, sut.Scan(context.Background(), "org/repo"))
+
+	// then
+	require.Len(t, notifier.diffs, 1)
+	diff := notifier.diffs[0]
+	assert.Equal(t, "org/repo", diff.Repository)
+	assert.Equal(t, []server.Finding{
+		{Kind: server.CheckIssue, Subject: "[error] Owner 'old-team' does not look like an email"},
+	}, diff.Added)
+	assert.Equal(t, []server.Finding{
+		{Kind: server.UnownedDirectory, Subject: "src"},
+		{Kind: server.UnownedDirectory, Subject: "src/util"},
+	}, diff.Resolved)
+}
+
+func TestWatcherScanSavesUnchangedSnapshots(t *testing.T) {
+	// given
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/docs/ @org/docs\n",
+		Files:      map[string]string{"src/main.go": "package main"},
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	svc := server.NewService(server.NewLocalSource(nil))
+	sut := server.NewWatcher(logrus.New(), svc, map[string]string{"org/repo": in.RepoDir}, server.NewFileSnapshotStore(t.TempDir()), notifier)
+	sut.SetClock(func() time.Time { return now })
+	require.NoError(t, sut.Scan(context.Background(), "org/repo"))
+
+	// when nothing changes
+	now = now.Add(time.Hour)
+	err := sut.Scan(context.Background(), "org/repo")
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, notifier.diffs)
+
+	last, err := sut.LastSnapshot("org/repo")
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.Equal(t, now, last.TakenAt)
+}
+
+func TestWatcherScanRetriesFailedNotifications(t *testing.T) {
+	// given
+	in := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "/docs/ @org/docs\n",
+		Files:      map[string]string{"src/main.go": "package main"},
+	})
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	svc := server.NewService(server.NewLocalSource(nil))
+	sut := server.NewWatcher(logrus.New(), svc, map[string]string{"org/repo": in.RepoDir}, server.NewFileSnapshotStore(t.TempDir()), server.NewWebhookNotifier(srv.URL))
+	require.NoError(t, sut.Scan(context.Background(), "org/repo"))
+	require.NoError(t, os.WriteFile(filepath.Join(in.RepoDir, "CODEOWNERS"), []byte("* @org/all\n"), 0o600))
+
+	// when
+	errFirst := sut.Scan(context.Background(), "org/repo")
+	errSecond := sut.Scan(context.Background(), "org/repo")
+	errThird := sut.Scan(context.Background(), "org/repo")
+
+	// then
+	assert.EqualError(t, errFirst, "while sending notification: webhook responded with status 502 Bad Gateway")
+	assert.NoError(t, errSecond)
+	assert.NoError(t, errThird)
+	assert.Equal(t, 2, calls)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	// given
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := server.Snapshot{Repository: "org/repo", TakenAt: since, Findings: []server.Finding{
+		{Kind: server.UnownedDirectory, Subject: "a"},
+		{Kind: server.UnownedDirectory, Subject: "b"},
+	}}
+	cur := server.Snapshot{Repository: "org/repo", TakenAt: since.Add(time.Hour), Findings: []server.Finding{
+		{Kind: server.UnownedDirectory, Subject: "b"},
+		{Kind: server.UnownedDirectory, Subject: "c"},
+	}}
+
+	// when
+	diff := server.DiffSnapshots(prev, cur)
+
+	// then
+	assert.Equal(t, server.SnapshotDiff{
+		Repository: "org/repo",
+		Since:      since,
+		TakenAt:    since.Add(time.Hour),
+		Added:      []server.Finding{{Kind: server.UnownedDirectory, Subject: "c"}},
+		Resolved:   []server.Finding{{Kind: server.UnownedDirectory, Subject: "a"}},
+	}, diff)
+	assert.True(t, server.DiffSnapshots(cur, cur).IsEmpty())
+}
@@ -0,0 +1,188 @@
+// Package store persists snapshots of findings of the server mode in a SQL database. Unlike the JSON files
+// which keep only the last snapshot, the database keeps the history of all scans, so trends can be queried.
+//
+// SQLite is used by default, as it needs no setup. Postgres is supported for highly available deployments,
+// in which several server replicas share the same results.
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/server"
+
+	"github.com/pkg/errors"
+
+	// registers the "pgx" database/sql driver
+	_ "github.com/jackc/pgx/v5/stdlib"
+	// registers the "sqlite" database/sql driver, which doesn't require cgo
+	_ "modernc.org/sqlite"
+)
+
+// schema is compatible with both SQLite and Postgres. The time of the scan is stored as Unix nanoseconds.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS snapshots (
+		repository TEXT NOT NULL,
+		taken_at   BIGINT NOT NULL,
+		findings   TEXT NOT NULL,
+		PRIMARY KEY (repository, taken_at)
+	)`,
+	`CREATE INDEX IF NOT EXISTS snapshots_taken_at ON snapshots (taken_at)`,
+}
+
+// SQLStore stores snapshots of findings in a SQL database.
+type SQLStore struct {
+	db        *sql.DB
+	retention time.Duration
+}
+
+// Open connects to the database described by a given DSN and creates the schema if needed. DSNs with
+// the 'postgres://' or 'postgresql://' scheme select Postgres. Other DSNs are paths to the SQLite database
+// file, optionally prefixed with 'sqlite://' and followed by query parameters of the driver, e.g.
+// '?_pragma=journal_mode(WAL)'. Snapshots older than the retention period are pruned on each save.
+// The zero retention keeps all snapshots.
+func Open(dsn string, retention time.Duration) (*SQLStore, error) {
+	driver, source := "sqlite", strings.TrimPrefix(dsn, "sqlite://")
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		driver, source = "pgx", dsn
+	}
+
+	if driver == "sqlite" {
+		var err error
+		if source, err = sqliteSource(source); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := sql.Open(driver, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening results store")
+	}
+	if driver == "sqlite" {
+		// SQLite allows a single writer
+		db.SetMaxOpenConns(1)
+	}
+
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, errors.Wrap(err, "while creating results store schema")
+		}
+	}
+
+	return &SQLStore{db: db, retention: retention}, nil
+}
+
+// sqliteSource creates the directory of the SQLite database file and returns the source which sets the busy timeout,
+// so concurrent writers wait for the lock instead of failing. Query parameters of the DSN are preserved,
+// and the busy timeout is not overridden if it's already set.
+func sqliteSource(dsn string) (string, error) {
+	path, query, hasQuery := strings.Cut(dsn, "?")
+	if path == "" {
+		return "", errors.New("path to the SQLite database is required")
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return "", errors.Wrap(err, "while parsing SQLite DSN parameters")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	for _, p := range params["_pragma"] {
+		if strings.HasPrefix(strings.ToLower(p), "busy_timeout") {
+			return dsn, nil
+		}
+	}
+	sep := "&"
+	if !hasQuery {
+		sep = "?"
+	}
+	return dsn + sep + "_pragma=busy_timeout(5000)", nil
+}
+
+// Load returns the last snapshot of a given repository, or nil if there is none.
+func (s *SQLStore) Load(repo string) (*server.Snapshot, error) {
+	row := s.db.QueryRow(`SELECT taken_at, findings FROM snapshots WHERE repository = $1 ORDER BY taken_at DESC LIMIT 1`, repo)
+
+	out, err := scanSnapshot(repo, row)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "while loading snapshot of %s", repo)
+	}
+	return &out, nil
+}
+
+// Save inserts the snapshot and prunes snapshots older than the retention period.
+func (s *SQLStore) Save(snapshot server.Snapshot) error {
+	findings, err := json.Marshal(snapshot.Findings)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	_, err = tx.Exec(`INSERT INTO snapshots (repository, taken_at, findings) VALUES ($1, $2, $3)`,
+		snapshot.Repository, snapshot.TakenAt.UnixNano(), string(findings))
+	if err != nil {
+		return errors.Wrapf(err, "while saving snapshot of %s", snapshot.Repository)
+	}
+	if s.retention > 0 {
+		before := snapshot.TakenAt.Add(-s.retention).UnixNano()
+		if _, err := tx.Exec(`DELETE FROM snapshots WHERE repository = $1 AND taken_at < $2`, snapshot.Repository, before); err != nil {
+			return errors.Wrapf(err, "while pruning snapshots of %s", snapshot.Repository)
+		}
+	}
+	return tx.Commit()
+}
+
+// History returns snapshots of a given repository taken since a given time, the oldest first.
+func (s *SQLStore) History(repo string, since time.Time) ([]server.Snapshot, error) {
+	rows, err := s.db.Query(`SELECT taken_at, findings FROM snapshots WHERE repository = $1 AND taken_at >= $2 ORDER BY taken_at`, repo, since.UnixNano())
+	if err != nil {
+		return nil, errors.Wrapf(err, "while querying history of %s", repo)
+	}
+	defer rows.Close()
+
+	out := []server.Snapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(repo, rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while querying history of %s", repo)
+		}
+		out = append(out, snapshot)
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database connections.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+func scanSnapshot(repo string, row interface{ Scan(...interface{}) error }) (server.Snapshot, error) {
+	var (
+		takenAt  int64
+		findings string
+	)
+	if err := row.Scan(&takenAt, &findings); err != nil {
+		return server.Snapshot{}, err
+	}
+
+	out := server.Snapshot{Repository: repo, TakenAt: time.Unix(0, takenAt).UTC()}
+	if err := json.Unmarshal([]byte(findings), &out.Findings); err != nil {
+		return server.Snapshot{}, err
+	}
+	return out, nil
+}
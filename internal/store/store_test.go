@@ -0,0 +1,117 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/server"
+	"go.szostok.io/codeowners/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotAt(repo string, at time.Time, subjects ...string) server.Snapshot {
+	out := server.Snapshot{Repository: repo, TakenAt: at, Findings: []server.Finding{}}
+	for _, s := range subjects {
+		out.Findings = append(out.Findings, server.Finding{Kind: server.UnownedDirectory, Subject: s})
+	}
+	return out
+}
+
+func TestSQLStoreLoadSave(t *testing.T) {
+	// given
+	sut, err := store.Open(filepath.Join(t.TempDir(), "nested", "codeowners.db"), 0)
+	require.NoError(t, err)
+	defer sut.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 123, time.UTC)
+
+	// when
+	empty, err := sut.Load("org/repo")
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	// when
+	require.NoError(t, sut.Save(snapshotAt("org/repo", since, "a")))
+	require.NoError(t, sut.Save(snapshotAt("org/repo", since.Add(time.Hour), "a", "b")))
+	require.NoError(t, sut.Save(snapshotAt("org/other", since.Add(2*time.Hour))))
+	last, err := sut.Load("org/repo")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, snapshotAt("org/repo", since.Add(time.Hour), "a", "b"), *last)
+}
+
+func TestSQLStoreHistory(t *testing.T) {
+	// given
+	sut, err := store.Open("sqlite://"+filepath.Join(t.TempDir(), "codeowners.db"), 48*time.Hour)
+	require.NoError(t, err)
+	defer sut.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 4; day++ {
+		require.NoError(t, sut.Save(snapshotAt("org/repo", since.Add(time.Duration(day)*24*time.Hour), "a")))
+	}
+
+	// when
+	all, err := sut.History("org/repo", time.Time{})
+	require.NoError(t, err)
+	recent, err := sut.History("org/repo", since.Add(72*time.Hour))
+	require.NoError(t, err)
+
+	// then the snapshot older than the retention period is pruned
+	require.Len(t, all, 3)
+	assert.Equal(t, since.Add(24*time.Hour), all[0].TakenAt)
+	assert.Equal(t, since.Add(72*time.Hour), all[2].TakenAt)
+	assert.Equal(t, []server.Snapshot{snapshotAt("org/repo", since.Add(72*time.Hour), "a")}, recent)
+}
+
+func TestOpenRejectsEmptyPath(t *testing.T) {
+	// when
+	_, err := store.Open("sqlite://", 0)
+
+	// then
+	assert.EqualError(t, err, "path to the SQLite database is required")
+}
+
+func TestOpenKeepsSQLiteQueryParameters(t *testing.T) {
+	tests := map[string]struct {
+		givenQuery string
+	}{
+		"Without busy timeout": {
+			givenQuery: "?_pragma=journal_mode(WAL)",
+		},
+		"With busy timeout": {
+			givenQuery: "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(100)",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), "nested", "codeowners.db")
+
+			// when
+			sut, err := store.Open("sqlite://"+path+tc.givenQuery, 0)
+
+			// then
+			require.NoError(t, err)
+			defer sut.Close()
+			require.NoError(t, sut.Save(snapshotAt("org/repo", time.Now(), "a")))
+			assert.FileExists(t, path)
+			// the journal mode set by the parameter keeps the write-ahead log next to the database file
+			assert.FileExists(t, path+"-wal")
+		})
+	}
+}
+
+func TestOpenRejectsInvalidSQLiteQuery(t *testing.T) {
+	// when
+	_, err := store.Open(filepath.Join(t.TempDir(), "codeowners.db")+"?_pragma=%zz", 0)
+
+	// then
+	assert.ErrorContains(t, err, "while parsing SQLite DSN parameters")
+}
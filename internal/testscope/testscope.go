@@ -0,0 +1,158 @@
+// Package testscope selects CI jobs to run for changed paths, based on owners of the paths and the test scope
+// declared for each owner. It enables ownership-driven selective testing in monorepos.
+package testscope
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping maps owners to CI jobs which test the code they own, e.g.:
+//
+//	teams:
+//	  "@org/payments": [payments-unit, payments-e2e]
+//	  "@org/docs": [docs-lint]
+//	fallback: [full-suite]
+//
+// Fallback jobs are run for paths which are not owned, or which owners don't have a test scope,
+// so changes are never left untested.
+type Mapping struct {
+	Teams    map[string][]string `yaml:"teams"`
+	Fallback []string            `yaml:"fallback"`
+}
+
+// LoadMapping reads the mapping file in YAML format.
+func LoadMapping(path string) (*Mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening test scope mapping file")
+	}
+	defer f.Close()
+
+	return ParseMapping(f)
+}
+
+// ParseMapping decodes the mapping in YAML format. Owners are matched case-insensitively.
+func ParseMapping(r io.Reader) (*Mapping, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var raw Mapping
+	if err := dec.Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "while decoding test scope mapping")
+	}
+
+	out := &Mapping{Teams: map[string][]string{}, Fallback: raw.Fallback}
+	for owner, jobs := range raw.Teams {
+		if len(jobs) == 0 {
+			return nil, errors.Errorf("owner %s has no jobs, remove it to use the fallback jobs", owner)
+		}
+		out.Teams[strings.ToLower(owner)] = jobs
+	}
+	return out, nil
+}
+
+// Jobs returns jobs of a given owner.
+func (m *Mapping) Jobs(owner string) ([]string, bool) {
+	jobs, found := m.Teams[strings.ToLower(owner)]
+	return jobs, found
+}
+
+// PathScope describes why jobs are selected for a single path.
+type PathScope struct {
+	Path   string   `json:"path"`
+	Owners []string `json:"owners"`
+	Jobs   []string `json:"jobs"`
+	// Fallback is true if the fallback jobs are selected, as no owner of the path has a test scope.
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+// Selection holds CI jobs to run for given paths.
+type Selection struct {
+	// Jobs are the sorted, deduplicated jobs of all paths.
+	Jobs []string `json:"jobs"`
+	// Owners are the sorted owners of all paths, deduplicated case-insensitively.
+	Owners []string    `json:"owners"`
+	Paths  []PathScope `json:"paths"`
+}
+
+// Selector selects CI jobs for changed paths.
+type Selector struct {
+	matcher *codeowners.Matcher
+	mapping *Mapping
+}
+
+// NewSelector returns new instance of the Selector which resolves owners with given CODEOWNERS entries.
+func NewSelector(entries []codeowners.Entry, mapping *Mapping) (*Selector, error) {
+	matcher, err := codeowners.NewMatcher(entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "while compiling CODEOWNERS patterns")
+	}
+	return &Selector{matcher: matcher, mapping: mapping}, nil
+}
+
+// Select returns jobs of owners of given paths. As in GitHub, the last matching pattern decides the owners.
+// If none of the owners of a path has a test scope, the fallback jobs are selected.
+func (s *Selector) Select(paths []string) Selection {
+	jobs := map[string]struct{}{}
+	owners := map[string]string{}
+	out := Selection{Jobs: []string{}, Owners: []string{}, Paths: []PathScope{}}
+
+	for _, p := range paths {
+		scope := PathScope{Path: p, Owners: []string{}}
+		if entry, found := s.matcher.Match(p); found && len(entry.Owners) > 0 {
+			scope.Owners = entry.Owners
+		}
+
+		var selected []string
+		for _, o := range scope.Owners {
+			if _, found := owners[strings.ToLower(o)]; !found {
+				owners[strings.ToLower(o)] = o
+			}
+			ownerJobs, _ := s.mapping.Jobs(o)
+			selected = append(selected, ownerJobs...)
+		}
+		if len(selected) == 0 {
+			selected, scope.Fallback = s.mapping.Fallback, true
+		}
+
+		scope.Jobs = sortedUnique(selected)
+		for _, j := range scope.Jobs {
+			jobs[j] = struct{}{}
+		}
+		out.Paths = append(out.Paths, scope)
+	}
+
+	for j := range jobs {
+		out.Jobs = append(out.Jobs, j)
+	}
+	sort.Strings(out.Jobs)
+	for _, o := range owners {
+		out.Owners = append(out.Owners, o)
+	}
+	sort.Slice(out.Owners, func(i, j int) bool {
+		return strings.ToLower(out.Owners[i]) < strings.ToLower(out.Owners[j])
+	})
+	return out
+}
+
+func sortedUnique(items []string) []string {
+	seen := map[string]struct{}{}
+	out := []string{}
+	for _, i := range items {
+		if _, found := seen[i]; found {
+			continue
+		}
+		seen[i] = struct{}{}
+		out = append(out, i)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,100 @@
+package testscope_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/testscope"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMapping = `
+teams:
+  "@org/Payments": [payments-unit, payments-e2e]
+  "@org/api": [api-unit, payments-e2e]
+fallback: [full-suite]
+`
+
+const testCODEOWNERS = `
+*            @org/platform
+/payments/   @org/payments @alice
+/api/        @org/api @org/payments
+/vendor/
+`
+
+func TestSelectorSelect(t *testing.T) {
+	// given
+	mapping, err := testscope.ParseMapping(strings.NewReader(testMapping))
+	require.NoError(t, err)
+
+	sut, err := testscope.NewSelector(codeowners.ParseCodeowners(strings.NewReader(testCODEOWNERS)), mapping)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		paths        []string
+		expSelection testscope.Selection
+	}{
+		"Jobs of all owners are deduplicated": {
+			paths: []string{"payments/charge.go", "api/server.go"},
+			expSelection: testscope.Selection{
+				Jobs:   []string{"api-unit", "payments-e2e", "payments-unit"},
+				Owners: []string{"@alice", "@org/api", "@org/payments"},
+				Paths: []testscope.PathScope{
+					{Path: "payments/charge.go", Owners: []string{"@org/payments", "@alice"}, Jobs: []string{"payments-e2e", "payments-unit"}},
+					{Path: "api/server.go", Owners: []string{"@org/api", "@org/payments"}, Jobs: []string{"api-unit", "payments-e2e", "payments-unit"}},
+				},
+			},
+		},
+		"Fallback for owners without test scope and unowned paths": {
+			paths: []string{"README.md", "vendor/lib.go"},
+			expSelection: testscope.Selection{
+				Jobs:   []string{"full-suite"},
+				Owners: []string{"@org/platform"},
+				Paths: []testscope.PathScope{
+					{Path: "README.md", Owners: []string{"@org/platform"}, Jobs: []string{"full-suite"}, Fallback: true},
+					{Path: "vendor/lib.go", Owners: []string{}, Jobs: []string{"full-suite"}, Fallback: true},
+				},
+			},
+		},
+		"No paths": {
+			expSelection: testscope.Selection{Jobs: []string{}, Owners: []string{}, Paths: []testscope.PathScope{}},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			selection := sut.Select(tc.paths)
+
+			// then
+			assert.Equal(t, tc.expSelection, selection)
+		})
+	}
+}
+
+func TestParseMappingErrors(t *testing.T) {
+	tests := map[string]struct {
+		mapping  string
+		expError string
+	}{
+		"Owner without jobs": {
+			mapping:  "teams:\n  \"@org/docs\": []\n",
+			expError: "owner @org/docs has no jobs, remove it to use the fallback jobs",
+		},
+		"Unknown field": {
+			mapping:  "teams: {}\ndefault: [full-suite]\n",
+			expError: "while decoding test scope mapping: yaml: unmarshal errors:\n  line 2: field default not found in type testscope.Mapping",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			_, err := testscope.ParseMapping(strings.NewReader(tc.mapping))
+
+			// then
+			assert.EqualError(t, err, tc.expError)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package gitrepo provides git repository fixtures for tests.
+package gitrepo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// New returns a new repository initialized in a temporary directory.
+func New(t testing.TB) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	Init(t, dir)
+	return dir
+}
+
+// Init initializes a repository in a given directory, with the committer identity set
+// and commit signing disabled, so commits don't depend on the git configuration of the host.
+func Init(t testing.TB, dir string) {
+	t.Helper()
+
+	Run(t, dir, "init", "--quiet")
+	Run(t, dir, "config", "user.email", "codeowners@example.com")
+	Run(t, dir, "config", "user.name", "codeowners")
+	Run(t, dir, "config", "commit.gpgsign", "false")
+}
+
+// WriteFiles writes given files into a directory, creating their parent directories.
+// File names are slash-separated paths relative to the directory.
+func WriteFiles(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+}
+
+// CommitFiles writes given files into the repository and commits all changes.
+func CommitFiles(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+
+	WriteFiles(t, dir, files)
+	Run(t, dir, "add", "-A")
+	Run(t, dir, "commit", "--quiet", "--allow-empty", "-m", "test commit")
+}
+
+// Run executes git with given arguments in a directory and returns its trimmed output.
+func Run(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,41 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Lines returns a line-based diff between the old and new text.
+// Only changed lines are returned, prefixed with the line number and `-` or `+` sign.
+func Lines(oldText, newText string) string {
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldText, newText)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	var (
+		out          strings.Builder
+		oldNo, newNo = 1, 1
+	)
+	for _, d := range diffs {
+		chunk := strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n")
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			oldNo += len(chunk)
+			newNo += len(chunk)
+		case diffmatchpatch.DiffDelete:
+			for _, l := range chunk {
+				fmt.Fprintf(&out, "%4d - %s\n", oldNo, l)
+				oldNo++
+			}
+		case diffmatchpatch.DiffInsert:
+			for _, l := range chunk {
+				fmt.Fprintf(&out, "%4d + %s\n", newNo, l)
+				newNo++
+			}
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,25 @@
+package textdiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.szostok.io/codeowners/internal/textdiff"
+)
+
+func TestLines(t *testing.T) {
+	// given
+	oldText := "* @a\n/docs/ @b\n/src/ @c\n"
+	newText := "* @a\n/docs/   @b\n/src/ @c\n/pkg/ @d\n"
+
+	// when
+	out := textdiff.Lines(oldText, newText)
+
+	// then
+	assert.Equal(t, `   2 - /docs/ @b
+   2 + /docs/   @b
+   4 + /pkg/ @d
+`, out)
+
+	assert.Empty(t, textdiff.Lines(oldText, oldText))
+}
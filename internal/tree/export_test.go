@@ -0,0 +1,4 @@
+package tree
+
+// ParseSubversionInfo exports the svn output parser for tests, as the svn client is not available everywhere.
+var ParseSubversionInfo = parseSubversionInfo
@@ -0,0 +1,153 @@
+package tree
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// ignoreFiles are the files which ignore rules are honored by the FSTree.
+var ignoreFiles = []string{".gitignore", ".p4ignore"}
+
+// metadataDirs are directories with version control metadata, which are never listed.
+var metadataDirs = map[string]struct{}{".git": {}, ".svn": {}, ".hg": {}, ".bzr": {}}
+
+// FSTree lists files by walking the filesystem, e.g. for trees in the middle of a migration to git.
+// Files ignored by .gitignore and .p4ignore files are skipped. Ignore rules follow the gitignore syntax,
+// with the same limitations as CODEOWNERS patterns, except that `!` re-includes previously ignored files.
+type FSTree struct{}
+
+type ignoreRule struct {
+	// dir is the directory of the ignore file, relative to the root. It's empty for the root directory.
+	dir     string
+	pattern codeowners.Pattern
+	negate  bool
+}
+
+// ListFiles returns all files under the root which are not ignored.
+func (t *FSTree) ListFiles(root string, paths ...string) ([]string, error) {
+	var (
+		files []string
+		rules = map[string][]ignoreRule{}
+	)
+
+	err := filepath.WalkDir(root, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, fullPath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if _, found := metadataDirs[d.Name()]; found {
+				return filepath.SkipDir
+			}
+			if rel == "." {
+				rel = ""
+			} else if isIgnored(rel, rules) {
+				return filepath.SkipDir
+			}
+
+			dirRules, err := loadIgnoreRules(fullPath, rel)
+			if err != nil {
+				return err
+			}
+			rules[rel] = dirRules
+			return nil
+		}
+
+		if !isIgnored(rel, rules) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "while walking %s", root)
+	}
+
+	sort.Strings(files)
+	return filterPaths(files, paths), nil
+}
+
+// isIgnored evaluates rules of all parent directories, from the root to the deepest one.
+// As in git, the last matching rule wins.
+func isIgnored(rel string, rules map[string][]ignoreRule) bool {
+	ignored := false
+	for _, dir := range parentDirs(rel) {
+		for _, r := range rules[dir] {
+			relToRule := rel
+			if r.dir != "" {
+				relToRule = strings.TrimPrefix(rel, r.dir+"/")
+			}
+			if r.pattern.Match(relToRule) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// parentDirs returns parent directories of a given path, starting with the root represented by an empty string.
+func parentDirs(rel string) []string {
+	var dirs []string
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		dirs = append(dirs, dir)
+	}
+
+	out := []string{""}
+	for idx := len(dirs) - 1; idx >= 0; idx-- {
+		out = append(out, dirs[idx])
+	}
+	return out
+}
+
+func loadIgnoreRules(fullDir, rel string) ([]ignoreRule, error) {
+	var out []ignoreRule
+	for _, name := range ignoreFiles {
+		f, err := os.Open(filepath.Join(fullDir, name))
+		switch {
+		case err == nil:
+		case os.IsNotExist(err):
+			continue
+		default:
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			rule := ignoreRule{dir: rel}
+			if strings.HasPrefix(line, "!") {
+				rule.negate = true
+				line = line[1:]
+			}
+			rule.pattern, err = codeowners.NewPattern(line)
+			if err != nil {
+				f.Close()
+				return nil, errors.Wrapf(err, "while compiling ignore pattern %q", line)
+			}
+			out = append(out, rule)
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package tree
+
+import (
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+)
+
+// Supported tree backends.
+const (
+	Git        = "git"
+	FS         = "fs"
+	Perforce   = "perforce"
+	Subversion = "svn"
+)
+
+// New returns the tree for a given backend. It returns nil for the git backend,
+// as checks list files with git if the tree is not set.
+func New(backend string) (api.Tree, error) {
+	switch backend {
+	case Git, "":
+		return nil, nil
+	case FS:
+		return &FSTree{}, nil
+	case Perforce:
+		return &PerforceTree{}, nil
+	case Subversion:
+		return &SubversionTree{}, nil
+	default:
+		return nil, errors.Errorf("unknown tree %q, possible values: %s", backend, strings.Join([]string{Git, FS, Perforce, Subversion}, ", "))
+	}
+}
+
+// ListedTree holds files listed upfront, e.g. on the host for a check executed in a container.
+type ListedTree struct {
+	Files []string `json:"files"`
+}
+
+// ListFiles returns listed files under given paths. The root is ignored.
+func (t *ListedTree) ListFiles(_ string, paths ...string) ([]string, error) {
+	return filterPaths(t.Files, paths), nil
+}
+
+// filterPaths returns files under any of the given paths. All files are returned if no paths are given.
+func filterPaths(files []string, paths []string) []string {
+	if len(paths) == 0 {
+		return files
+	}
+
+	var out []string
+	for _, f := range files {
+		for _, p := range paths {
+			p = strings.TrimSuffix(codeowners.NormalizePath(p), "/")
+			if p == "" || p == "." || f == p || strings.HasPrefix(f, p+"/") {
+				out = append(out, f)
+				break
+			}
+		}
+	}
+	return out
+}
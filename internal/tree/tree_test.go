@@ -0,0 +1,129 @@
+package tree_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/internal/tree"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		givenBackend string
+		expTree      interface{}
+		expErrString string
+	}{
+		"Git is the default": {
+			givenBackend: "git",
+			expTree:      nil,
+		},
+		"Filesystem": {
+			givenBackend: "fs",
+			expTree:      &tree.FSTree{},
+		},
+		"Perforce": {
+			givenBackend: "perforce",
+			expTree:      &tree.PerforceTree{},
+		},
+		"Subversion": {
+			givenBackend: "svn",
+			expTree:      &tree.SubversionTree{},
+		},
+		"Unknown": {
+			givenBackend: "cvs",
+			expErrString: `unknown tree "cvs", possible values: git, fs, perforce, svn`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got, err := tree.New(tc.givenBackend)
+
+			// then
+			if tc.expErrString != "" {
+				require.EqualError(t, err, tc.expErrString)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expTree == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tc.expTree, got)
+		})
+	}
+}
+
+func TestFSTreeListFiles(t *testing.T) {
+	// given
+	root := t.TempDir()
+	gitrepo.WriteFiles(t, root, map[string]string{
+		".gitignore":           "*.log\n/build/\n!keep.log\n",
+		".git/config":          "",
+		".svn/wc.db":           "",
+		"README.md":            "",
+		"app.log":              "",
+		"keep.log":             "",
+		"build/out.bin":        "",
+		"src/main.go":          "",
+		"src/build/gen.go":     "",
+		"src/debug.log":        "",
+		"src/vendor/.p4ignore": "*\n!.p4ignore\n",
+		"src/vendor/lib.go":    "",
+	})
+
+	tests := map[string]struct {
+		givenPaths []string
+		expFiles   []string
+	}{
+		"All files": {
+			expFiles: []string{
+				".gitignore",
+				"README.md",
+				"keep.log",
+				"src/build/gen.go",
+				"src/main.go",
+				"src/vendor/.p4ignore",
+			},
+		},
+		"Files under given paths": {
+			givenPaths: []string{"src/build/", "./README.md"},
+			expFiles: []string{
+				"README.md",
+				"src/build/gen.go",
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			files, err := (&tree.FSTree{}).ListFiles(root, tc.givenPaths...)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expFiles, files)
+		})
+	}
+}
+
+func TestParseSubversionInfo(t *testing.T) {
+	// given
+	givenOutput := `<?xml version="1.0" encoding="UTF-8"?>
+<info>
+<entry kind="dir" path="." revision="12"><url>https://svn.example.com/repo/trunk</url></entry>
+<entry kind="file" path="src/main.go" revision="12"><url>https://svn.example.com/repo/trunk/src/main.go</url></entry>
+<entry kind="dir" path="src" revision="12"><url>https://svn.example.com/repo/trunk/src</url></entry>
+<entry kind="file" path="README.md" revision="12"><url>https://svn.example.com/repo/trunk/README.md</url></entry>
+</info>
+`
+
+	// when
+	files, err := tree.ParseSubversionInfo([]byte(givenOutput))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "src/main.go"}, files)
+}
@@ -0,0 +1,99 @@
+package tree
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/pkg/errors"
+	"gopkg.in/pipe.v2"
+)
+
+// PerforceTree lists files synced to the Perforce workspace with `p4 have`.
+// The p4 client must be configured, e.g. with the P4PORT, P4USER, and P4CLIENT environment variables.
+type PerforceTree struct{}
+
+// ListFiles returns files synced to the workspace under the root.
+func (t *PerforceTree) ListFiles(root string, paths ...string) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// p4 resolves relative paths against the PWD variable instead of the actual working directory
+	p4have := pipe.Script(
+		pipe.ChDir(root),
+		pipe.SetEnvVar("PWD", root),
+		pipe.Exec("p4", "-F", "%path%", "have", "./..."),
+	)
+	stdout, stderr, err := pipe.DividedOutput(p4have)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(stdout), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		rel, err := codeowners.RelPath(root, line)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rel)
+	}
+
+	sort.Strings(files)
+	return filterPaths(files, paths), nil
+}
+
+// SubversionTree lists files of the Subversion working copy with `svn info`,
+// which works offline, unlike `svn list`.
+type SubversionTree struct{}
+
+type svnInfo struct {
+	Entries []struct {
+		Kind string `xml:"kind,attr"`
+		Path string `xml:"path,attr"`
+	} `xml:"entry"`
+}
+
+// ListFiles returns files versioned in the working copy under the root.
+func (t *SubversionTree) ListFiles(root string, paths ...string) ([]string, error) {
+	svninfo := pipe.Script(
+		pipe.ChDir(root),
+		pipe.Exec("svn", "info", "--recursive", "--xml", "."),
+	)
+	stdout, stderr, err := pipe.DividedOutput(svninfo)
+	if err != nil {
+		return nil, errors.Wrap(err, string(stderr))
+	}
+
+	files, err := parseSubversionInfo(stdout)
+	if err != nil {
+		return nil, err
+	}
+	return filterPaths(files, paths), nil
+}
+
+func parseSubversionInfo(in []byte) ([]string, error) {
+	var info svnInfo
+	if err := xml.Unmarshal(in, &info); err != nil {
+		return nil, errors.Wrap(err, "while parsing svn info output")
+	}
+
+	var files []string
+	for _, e := range info.Entries {
+		if e.Kind != "file" {
+			continue
+		}
+		files = append(files, codeowners.NormalizePath(e.Path))
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.szostok.io/codeowners/internal/netguard"
+)
+
+// Counters count external API calls and cache lookups made on behalf of a single check.
+// They are attached to the context passed to the check, so clients shared by checks running
+// in parallel attribute requests to the right check.
+type Counters struct {
+	apiCalls    int64
+	cacheHits   int64
+	cacheMisses int64
+}
+
+type countersKey struct{}
+
+// WithCounters returns a copy of the context which collects usage in given counters.
+func WithCounters(ctx context.Context, c *Counters) context.Context {
+	return context.WithValue(ctx, countersKey{}, c)
+}
+
+// FromContext returns counters attached to the context, or nil if there are none.
+// All Counters methods are no-op for nil counters.
+func FromContext(ctx context.Context) *Counters {
+	c, _ := ctx.Value(countersKey{}).(*Counters)
+	return c
+}
+
+// APICall records an outbound request to an external API.
+func (c *Counters) APICall() {
+	if c != nil {
+		atomic.AddInt64(&c.apiCalls, 1)
+	}
+}
+
+// CacheHit records a request served from the cache.
+func (c *Counters) CacheHit() {
+	if c != nil {
+		atomic.AddInt64(&c.cacheHits, 1)
+	}
+}
+
+// CacheMiss records a cacheable request which was not found in the cache.
+func (c *Counters) CacheMiss() {
+	if c != nil {
+		atomic.AddInt64(&c.cacheMisses, 1)
+	}
+}
+
+// Stats returns the snapshot of counters for a given check.
+func (c *Counters) Stats(check string, duration time.Duration) CheckStats {
+	out := CheckStats{Check: check, Duration: duration}
+	if c != nil {
+		out.APICalls = int(atomic.LoadInt64(&c.apiCalls))
+		out.CacheHits = int(atomic.LoadInt64(&c.cacheHits))
+		out.CacheMisses = int(atomic.LoadInt64(&c.cacheMisses))
+	}
+	return out
+}
+
+// CheckStats is the wall time and the external API budget used by a single check.
+type CheckStats struct {
+	Check    string
+	Duration time.Duration
+	// APICalls is the number of requests which reached external APIs, e.g. GitHub or JIRA.
+	APICalls int
+	// CacheHits and CacheMisses count GitHub API lookups served, or not, from the response cache.
+	CacheHits   int
+	CacheMisses int
+}
+
+// CacheHitRate returns the fraction of cache lookups served from the cache, or -1 if the cache wasn't used.
+func (s CheckStats) CacheHitRate() float64 {
+	lookups := s.CacheHits + s.CacheMisses
+	if lookups == 0 {
+		return -1
+	}
+	return float64(s.CacheHits) / float64(lookups)
+}
+
+// Total sums the usage of all checks. The duration is the longest one, as checks are executed in parallel.
+func Total(stats []CheckStats) CheckStats {
+	out := CheckStats{Check: "total"}
+	for _, s := range stats {
+		if s.Duration > out.Duration {
+			out.Duration = s.Duration
+		}
+		out.APICalls += s.APICalls
+		out.CacheHits += s.CacheHits
+		out.CacheMisses += s.CacheMisses
+	}
+	return out
+}
+
+// Transport returns transport which counts requests in the counters attached to the request context.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = netguard.BaseTransport()
+	}
+	return &countingTransport{base: base}
+}
+
+type countingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	FromContext(req.Context()).APICall()
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,89 @@
+package usage_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/usage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	// given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: usage.Transport(nil)}
+	foo, bar := &usage.Counters{}, &usage.Counters{}
+
+	get := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// when
+	get(usage.WithCounters(context.Background(), foo))
+	get(usage.WithCounters(context.Background(), foo))
+	get(usage.WithCounters(context.Background(), bar))
+	get(context.Background())
+
+	// then
+	assert.Equal(t, 2, foo.Stats("Foo Checker", 0).APICalls)
+	assert.Equal(t, 1, bar.Stats("Bar Checker", 0).APICalls)
+}
+
+func TestCheckStats(t *testing.T) {
+	tests := map[string]struct {
+		givenStats      []usage.CheckStats
+		expTotal        usage.CheckStats
+		expCacheHitRate float64
+	}{
+		"Should report no cache usage": {
+			givenStats: []usage.CheckStats{
+				{Check: "Foo Checker", Duration: time.Second},
+			},
+			expTotal:        usage.CheckStats{Check: "total", Duration: time.Second},
+			expCacheHitRate: -1,
+		},
+		"Should sum usage and take the longest duration": {
+			givenStats: []usage.CheckStats{
+				{Check: "Foo Checker", Duration: time.Second, APICalls: 2, CacheHits: 1, CacheMisses: 2},
+				{Check: "Bar Checker", Duration: 3 * time.Second, APICalls: 1, CacheHits: 5},
+			},
+			expTotal:        usage.CheckStats{Check: "total", Duration: 3 * time.Second, APICalls: 3, CacheHits: 6, CacheMisses: 2},
+			expCacheHitRate: 0.75,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			total := usage.Total(tc.givenStats)
+
+			// then
+			assert.Equal(t, tc.expTotal, total)
+			assert.Equal(t, tc.expCacheHitRate, total.CacheHitRate())
+		})
+	}
+}
+
+func TestNilCounters(t *testing.T) {
+	// given
+	counters := usage.FromContext(context.Background())
+
+	// when
+	counters.APICall()
+	counters.CacheHit()
+	counters.CacheMiss()
+
+	// then
+	assert.Nil(t, counters)
+	assert.Equal(t, usage.CheckStats{Check: "Foo Checker"}, counters.Stats("Foo Checker", 0))
+}
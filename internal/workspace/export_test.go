@@ -0,0 +1,12 @@
+package workspace
+
+import (
+	"context"
+	"time"
+)
+
+// WithClock overrides the clock of the scheduler, so pauses are not really waited for in tests.
+func (s *Scheduler) WithClock(now func() time.Time, sleep func(ctx context.Context, d time.Duration) error) *Scheduler {
+	s.now, s.sleep = now, sleep
+	return s
+}
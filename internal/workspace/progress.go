@@ -0,0 +1,115 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Progress records results of repositories validated so far, so a run interrupted by a crash, a signal,
+// or an exhausted API rate limit can be resumed without validating the completed repositories again.
+type Progress struct {
+	// Digest identifies the content of the workspace file. Progress of a different workspace is rejected.
+	Digest string `json:"digest"`
+	// Results are keyed by the repository path.
+	Results map[string]Result `json:"results"`
+}
+
+// Result is the outcome of a completed repository validation.
+type Result struct {
+	Name     string `json:"name"`
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ProgressPath returns the default progress file of a given workspace file, e.g. `codeowners-workspace.progress.json`.
+func ProgressPath(workspaceFile string) string {
+	return strings.TrimSuffix(workspaceFile, filepath.Ext(workspaceFile)) + ".progress.json"
+}
+
+// NewProgress returns empty progress of a given workspace file.
+func NewProgress(workspaceFile string) (*Progress, error) {
+	digest, err := fileDigest(workspaceFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Progress{Digest: digest, Results: map[string]Result{}}, nil
+}
+
+// LoadProgress reads the progress saved by an interrupted run of a given workspace file. If there is
+// no progress file, empty progress is returned. Progress saved for a different version of the workspace
+// file is rejected, as the completed repositories may have been validated with different settings.
+func LoadProgress(path, workspaceFile string) (*Progress, error) {
+	out, err := NewProgress(workspaceFile)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return out, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "while reading workspace progress")
+	}
+
+	var saved Progress
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		return nil, errors.Wrapf(err, "while decoding workspace progress %s", path)
+	}
+	if saved.Digest != out.Digest {
+		return nil, errors.Errorf("workspace file %s changed since the progress %s was saved, run without resuming", workspaceFile, path)
+	}
+	if saved.Results != nil {
+		out.Results = saved.Results
+	}
+	return out, nil
+}
+
+// Done returns the result of a given repository, if it was completed.
+func (p *Progress) Done(repoPath string) (Result, bool) {
+	r, found := p.Results[repoPath]
+	return r, found
+}
+
+// Record marks the repository as completed.
+func (p *Progress) Record(repoPath string, r Result) {
+	p.Results[repoPath] = r
+}
+
+// Save writes the progress file. The file is replaced atomically, so a crash doesn't leave a partial file.
+func (p *Progress) Save(path string) error {
+	raw, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "while encoding workspace progress")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "while creating workspace progress")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "while writing workspace progress")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "while writing workspace progress")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), "while writing workspace progress")
+}
+
+func fileDigest(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "while reading workspace file")
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
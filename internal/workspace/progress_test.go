@@ -0,0 +1,86 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/workspace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressResume(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	wsFile := filepath.Join(dir, "codeowners-workspace.yaml")
+	require.NoError(t, os.WriteFile(wsFile, []byte("repositories:\n  - path: a\n  - path: b\n"), 0o644))
+	progressFile := workspace.ProgressPath(wsFile)
+
+	interrupted, err := workspace.NewProgress(wsFile)
+	require.NoError(t, err)
+	interrupted.Record("/repos/a", workspace.Result{Name: "org/a", ExitCode: 3})
+	require.NoError(t, interrupted.Save(progressFile))
+
+	// when
+	resumed, err := workspace.LoadProgress(progressFile, wsFile)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "codeowners-workspace.progress.json"), progressFile)
+
+	res, done := resumed.Done("/repos/a")
+	assert.True(t, done)
+	assert.Equal(t, workspace.Result{Name: "org/a", ExitCode: 3}, res)
+
+	_, done = resumed.Done("/repos/b")
+	assert.False(t, done)
+}
+
+func TestLoadProgressErrors(t *testing.T) {
+	tests := map[string]struct {
+		givenProgress string
+		expErr        string
+	}{
+		"Should reject progress of a changed workspace file": {
+			givenProgress: `{"digest": "outdated", "results": {}}`,
+			expErr:        "changed since the progress",
+		},
+		"Should reject malformed progress": {
+			givenProgress: `{`,
+			expErr:        "while decoding workspace progress",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			dir := t.TempDir()
+			wsFile := filepath.Join(dir, "codeowners-workspace.yaml")
+			require.NoError(t, os.WriteFile(wsFile, []byte("repositories:\n  - path: a\n"), 0o644))
+			progressFile := filepath.Join(dir, "progress.json")
+			require.NoError(t, os.WriteFile(progressFile, []byte(tc.givenProgress), 0o644))
+
+			// when
+			_, err := workspace.LoadProgress(progressFile, wsFile)
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expErr)
+		})
+	}
+}
+
+func TestLoadProgressNotExist(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	wsFile := filepath.Join(dir, "codeowners-workspace.yaml")
+	require.NoError(t, os.WriteFile(wsFile, []byte("repositories:\n  - path: a\n"), 0o644))
+
+	// when
+	progress, err := workspace.LoadProgress(filepath.Join(dir, "missing.json"), wsFile)
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, progress.Results)
+}
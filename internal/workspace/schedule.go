@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimit reports the remaining budget of the API rate limit shared by all repository validations.
+type RateLimit interface {
+	// Remaining returns the number of requests left in the current window and the time the window resets.
+	// The known result is false until the first API response is received.
+	Remaining() (remaining int, reset time.Time, known bool)
+}
+
+// Scheduler orders repository validations to get the most out of the hourly API quota. Repositories which
+// call the API are interleaved with the ones which don't, and when the budget falls below the threshold,
+// only the API-light repositories are validated. If only API-heavy ones are left, the scheduler pauses
+// until the rate limit window resets, instead of letting validations fail with rate limit errors.
+type Scheduler struct {
+	log       logrus.FieldLogger
+	limit     RateLimit
+	threshold int
+	weight    func(RepositoryConfig) int
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewScheduler returns new instance of the Scheduler. The weight estimates API usage of a repository
+// validation, e.g. the number of enabled checks which call the API. Zero weight means the validation
+// doesn't call the API. Zero threshold disables pausing.
+func NewScheduler(log logrus.FieldLogger, limit RateLimit, threshold int, weight func(RepositoryConfig) int) *Scheduler {
+	return &Scheduler{
+		log:       log,
+		limit:     limit,
+		threshold: threshold,
+		weight:    weight,
+		now:       time.Now,
+		sleep:     sleepCtx,
+	}
+}
+
+// Run calls fn for each repository in the scheduled order. It returns the context error if it was canceled
+// while paused.
+func (s *Scheduler) Run(ctx context.Context, repos []RepositoryConfig, fn func(RepositoryConfig)) error {
+	queue := s.interleave(repos)
+	for len(queue) > 0 {
+		idx := 0
+		if s.weight(queue[idx]) > 0 && s.lowBudget() {
+			idx = s.firstLight(queue)
+		}
+		if idx == -1 {
+			if err := s.pause(ctx); err != nil {
+				return err
+			}
+			idx = 0
+		}
+
+		repo := queue[idx]
+		queue = append(queue[:idx], queue[idx+1:]...)
+		fn(repo)
+	}
+	return nil
+}
+
+// interleave alternates API-heavy repositories, the heaviest first, with API-light ones, so the light ones
+// are still queued when the budget runs low. The relative order of light repositories is kept.
+func (s *Scheduler) interleave(repos []RepositoryConfig) []RepositoryConfig {
+	var heavy, light []RepositoryConfig
+	for _, r := range repos {
+		if s.weight(r) > 0 {
+			heavy = append(heavy, r)
+		} else {
+			light = append(light, r)
+		}
+	}
+	sort.SliceStable(heavy, func(i, j int) bool {
+		return s.weight(heavy[i]) > s.weight(heavy[j])
+	})
+
+	out := make([]RepositoryConfig, 0, len(repos))
+	for i := 0; i < len(heavy) || i < len(light); i++ {
+		if i < len(heavy) {
+			out = append(out, heavy[i])
+		}
+		if i < len(light) {
+			out = append(out, light[i])
+		}
+	}
+	return out
+}
+
+func (s *Scheduler) firstLight(queue []RepositoryConfig) int {
+	for i, r := range queue {
+		if s.weight(r) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// lowBudget returns true if the remaining budget is below the threshold and the window did not reset yet.
+func (s *Scheduler) lowBudget() bool {
+	if s.threshold <= 0 {
+		return false
+	}
+	remaining, reset, known := s.limit.Remaining()
+	return known && remaining < s.threshold && s.now().Before(reset)
+}
+
+func (s *Scheduler) pause(ctx context.Context) error {
+	remaining, reset, _ := s.limit.Remaining()
+	// a second of margin, as the reset time is rounded down to seconds
+	wait := reset.Sub(s.now()) + time.Second
+	s.log.Infof("API rate limit budget is low (%d requests left), pausing until it resets at %s", remaining, reset.Format(time.RFC3339))
+	return s.sleep(ctx, wait)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
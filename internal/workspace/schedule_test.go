@@ -0,0 +1,106 @@
+package workspace_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/workspace"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRateLimit struct {
+	remaining int
+	reset     time.Time
+	known     bool
+}
+
+func (f *fakeRateLimit) Remaining() (int, time.Time, bool) {
+	return f.remaining, f.reset, f.known
+}
+
+func TestSchedulerRun(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	weights := map[string]int{"heavy-a": 1, "heavy-b": 2, "light-a": 0, "light-b": 0, "light-c": 0}
+
+	tests := map[string]struct {
+		givenLimit fakeRateLimit
+		// givenDrain is the budget left after each repository validation
+		givenDrain map[string]int
+		expOrder   []string
+		expPauses  []time.Duration
+	}{
+		"Should interleave heavy repositories, the heaviest first, with light ones": {
+			givenLimit: fakeRateLimit{known: false},
+			expOrder:   []string{"heavy-b", "light-a", "heavy-a", "light-b", "light-c"},
+		},
+		"Should validate only light repositories when the budget is low": {
+			givenLimit: fakeRateLimit{known: true, remaining: 1000, reset: now.Add(time.Minute)},
+			givenDrain: map[string]int{"heavy-b": 10},
+			expOrder:   []string{"heavy-b", "light-a", "light-b", "light-c", "heavy-a"},
+			expPauses:  []time.Duration{time.Minute + time.Second},
+		},
+		"Should not pause if the rate limit window already reset": {
+			givenLimit: fakeRateLimit{known: true, remaining: 10, reset: now.Add(-time.Minute)},
+			expOrder:   []string{"heavy-b", "light-a", "heavy-a", "light-b", "light-c"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			var repos []workspace.RepositoryConfig
+			for _, name := range []string{"light-a", "heavy-a", "light-b", "heavy-b", "light-c"} {
+				repos = append(repos, workspace.RepositoryConfig{Name: name, Config: &config.Config{}})
+			}
+
+			limit := tc.givenLimit
+			var pauses []time.Duration
+			log, _ := test.NewNullLogger()
+			scheduler := workspace.NewScheduler(log, &limit, 100, func(r workspace.RepositoryConfig) int {
+				return weights[r.Name]
+			}).WithClock(func() time.Time { return now }, func(_ context.Context, d time.Duration) error {
+				pauses = append(pauses, d)
+				return nil
+			})
+
+			// when
+			var order []string
+			err := scheduler.Run(context.Background(), repos, func(r workspace.RepositoryConfig) {
+				order = append(order, r.Name)
+				if left, found := tc.givenDrain[r.Name]; found {
+					limit.remaining = left
+				}
+			})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOrder, order)
+			assert.Equal(t, tc.expPauses, pauses)
+		})
+	}
+}
+
+func TestSchedulerRunCanceledWhilePaused(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	limit := &fakeRateLimit{known: true, remaining: 0, reset: time.Now().Add(time.Hour)}
+	repos := []workspace.RepositoryConfig{{Name: "heavy", Config: &config.Config{}}}
+	scheduler := workspace.NewScheduler(logrus.New(), limit, 100, func(workspace.RepositoryConfig) int { return 1 })
+
+	// when
+	var validated []string
+	err := scheduler.Run(ctx, repos, func(r workspace.RepositoryConfig) {
+		validated = append(validated, r.Name)
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, validated)
+}
@@ -0,0 +1,138 @@
+package workspace
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFilename is the name of the workspace file looked up in the current directory.
+const DefaultFilename = "codeowners-workspace.yaml"
+
+// Workspace lists repositories validated together, e.g.:
+//
+//	defaults:
+//	  checks: [syntax, duppatterns, owners]
+//	repositories:
+//	  - path: ../service-a
+//	  - path: ../service-b
+//	    repository: org/service-b
+//	    providers: [github, gitlab]
+//	    check-failure-level: error
+type Workspace struct {
+	// Defaults are applied to all repositories.
+	Defaults Overrides `yaml:"defaults"`
+	// Repositories to validate.
+	Repositories []Repository `yaml:"repositories"`
+}
+
+// Overrides holds settings which override the application configuration. Empty fields are not applied.
+type Overrides struct {
+	Providers          []string `yaml:"providers"`
+	Checks             []string `yaml:"checks"`
+	ExperimentalChecks []string `yaml:"experimental-checks"`
+	CheckFailureLevel  string   `yaml:"check-failure-level"`
+}
+
+// Repository is a single repository in the workspace.
+type Repository struct {
+	// Path to the repository, relative to the workspace file.
+	Path string `yaml:"path"`
+	// Repository is in form 'owner/repository'. If not set, it is derived from the git remote.
+	Repository string `yaml:"repository"`
+
+	Overrides `yaml:",inline"`
+}
+
+// RepositoryConfig is the application configuration of a single workspace repository.
+type RepositoryConfig struct {
+	Name   string
+	Config *config.Config
+}
+
+// Load reads the workspace file. Relative repository paths are resolved against the workspace file directory.
+func Load(path string) (*Workspace, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading workspace file")
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+
+	var ws Workspace
+	if err := dec.Decode(&ws); err != nil {
+		return nil, errors.Wrapf(err, "while decoding workspace file %s", path)
+	}
+
+	if len(ws.Repositories) == 0 {
+		return nil, errors.Errorf("workspace file %s does not list any repositories", path)
+	}
+
+	dir := filepath.Dir(path)
+	for idx, repo := range ws.Repositories {
+		if repo.Path == "" {
+			return nil, errors.Errorf("repository #%d in workspace file %s: path is required", idx+1, path)
+		}
+		if !filepath.IsAbs(repo.Path) {
+			ws.Repositories[idx].Path = filepath.Join(dir, repo.Path)
+		}
+	}
+
+	return &ws, nil
+}
+
+// Configs returns the configuration of each repository: the base configuration overridden first by
+// the workspace defaults and then by the repository settings.
+func (w *Workspace) Configs(base config.Config) ([]RepositoryConfig, error) {
+	out := make([]RepositoryConfig, 0, len(w.Repositories))
+	for _, repo := range w.Repositories {
+		cfg := base
+		cfg.RepositoryPath = repo.Path
+		if repo.Repository != "" {
+			cfg.OwnerCheckerRepository = repo.Repository
+		}
+
+		for _, o := range []Overrides{w.Defaults, repo.Overrides} {
+			if err := o.apply(&cfg); err != nil {
+				return nil, errors.Wrapf(err, "while configuring repository %s", repo.Path)
+			}
+		}
+
+		name := repo.Repository
+		if name == "" {
+			name = repo.Path
+		}
+		out = append(out, RepositoryConfig{Name: name, Config: &cfg})
+	}
+
+	return out, nil
+}
+
+func (o Overrides) apply(cfg *config.Config) error {
+	if len(o.Providers) > 0 {
+		cfg.Providers = o.Providers
+	}
+	// deprecated check IDs are still accepted in the manifest
+	if len(o.Checks) > 0 {
+		cfg.Checks, _ = config.Deprecated.MigrateChecks(o.Checks)
+	}
+	if len(o.ExperimentalChecks) > 0 {
+		cfg.ExperimentalChecks, _ = config.Deprecated.MigrateChecks(o.ExperimentalChecks)
+	}
+	if o.CheckFailureLevel != "" {
+		var level api.SeverityType
+		if err := level.Unmarshal(o.CheckFailureLevel); err != nil {
+			return err
+		}
+		cfg.CheckFailureLevel = level
+	}
+
+	return nil
+}
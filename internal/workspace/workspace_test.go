@@ -0,0 +1,88 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/config"
+	"go.szostok.io/codeowners/internal/workspace"
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceConfigs(t *testing.T) {
+	// given
+	base := config.Config{
+		GithubAccessToken:  "token",
+		Checks:             []string{"owners"},
+		ExperimentalChecks: []string{"not-owned"},
+		CheckFailureLevel:  api.Error,
+	}
+
+	ws, err := workspace.Load(filepath.Join("testdata", "codeowners-workspace.yaml"))
+	require.NoError(t, err)
+
+	// when
+	got, err := ws.Configs(base)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, filepath.Join("testdata", "service-a"), got[0].Name)
+	assert.Equal(t, &config.Config{
+		GithubAccessToken:  "token",
+		Checks:             []string{"syntax", "duppatterns"},
+		ExperimentalChecks: []string{"not-owned"},
+		CheckFailureLevel:  api.Warning,
+		RepositoryPath:     filepath.Join("testdata", "service-a"),
+	}, got[0].Config)
+
+	assert.Equal(t, "org/service-b", got[1].Name)
+	assert.Equal(t, &config.Config{
+		GithubAccessToken:      "token",
+		Checks:                 []string{"syntax", "duppatterns"},
+		ExperimentalChecks:     []string{"owner-casing"},
+		CheckFailureLevel:      api.Error,
+		OwnerCheckerRepository: "org/service-b",
+		Providers:              []string{"github", "gitlab"},
+		RepositoryPath:         "/repos/service-b",
+	}, got[1].Config)
+}
+
+func TestWorkspaceLoadErrors(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		expErr  string
+	}{
+		"Should reject unknown fields": {
+			content: "repositories:\n  - path: a\n    chekcs: [syntax]\n",
+			expErr:  "field chekcs not found",
+		},
+		"Should require repositories": {
+			content: "defaults:\n  checks: [syntax]\n",
+			expErr:  "does not list any repositories",
+		},
+		"Should require repository path": {
+			content: "repositories:\n  - repository: org/repo\n",
+			expErr:  "repository #1 in workspace file",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), workspace.DefaultFilename)
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			// when
+			_, err := workspace.Load(path)
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expErr)
+		})
+	}
+}
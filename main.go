@@ -1,10 +1,10 @@
+//go:build !minimal
+
 package main
 
 import (
 	"context"
 	"os"
-	"os/signal"
-	"syscall"
 
 	cmd "go.szostok.io/codeowners/cmd/codeowners"
 )
@@ -20,21 +20,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
-// WithStopContext returns a copy of parent with a new Done channel. The returned
-// context's Done channel is closed on of SIGINT or SIGTERM signals.
-func WithStopContext(parent context.Context) (context.Context, context.CancelFunc) {
-	ctx, cancel := context.WithCancel(parent)
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		select {
-		case <-ctx.Done():
-		case <-sigCh:
-			cancel()
-		}
-	}()
-
-	return ctx, cancel
-}
@@ -0,0 +1,21 @@
+//go:build minimal
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"go.szostok.io/codeowners/internal/minimal"
+)
+
+// main of the minimal build executes only checks which don't need network access.
+// Build it with `go build -tags minimal`.
+func main() {
+	ctx, cancelFunc := WithStopContext(context.Background())
+
+	code := minimal.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	cancelFunc()
+	//nolint:gocritic
+	os.Exit(code)
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"go.szostok.io/codeowners/internal/git"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// Analysis memoizes expensive work shared by checks during a single run, such as compiled patterns
+// and owner validation results, so it is not repeated by each check. Repository data, such as the tracked
+// files and the pull request diff, is computed on first use. It's safe for concurrent use.
+type Analysis struct {
+	entries []codeowners.Entry
+
+	filesOnce sync.Once
+	files     []string
+	filesErr  error
+
+	scopedOnce  sync.Once
+	scopedFiles []string
+
+	diffOnce sync.Once
+	diff     *Diff
+	diffErr  error
+
+	matcherOnce sync.Once
+	matcher     *codeowners.Matcher
+	matcherErr  error
+
+	patternsMu sync.Mutex
+	patterns   map[string]compiledPattern
+
+	ownersMu sync.Mutex
+	owners   map[string]*ownerValidation
+}
+
+// OwnerValidation is the result of validating a single owner.
+type OwnerValidation struct {
+	// Problem describes why the owner is not valid. It's empty if the owner is valid.
+	Problem string
+	// Permanent is set if no other owner can be validated either, e.g. because of missing permissions.
+	Permanent bool
+}
+
+// Diff holds files changed between the merge base of the base reference and HEAD, e.g. in a pull request.
+type Diff struct {
+	BaseRef string
+	Files   []ChangedFile
+}
+
+type (
+	// ChangedFile represents a single file changed between two revisions.
+	ChangedFile = git.ChangedFile
+	// ChangeStatus is the kind of change of a single file.
+	ChangeStatus = git.ChangeStatus
+)
+
+// Change statuses of files.
+const (
+	Added    = git.Added
+	Modified = git.Modified
+	Deleted  = git.Deleted
+	Renamed  = git.Renamed
+)
+
+type compiledPattern struct {
+	pattern codeowners.Pattern
+	err     error
+}
+
+type ownerValidation struct {
+	once   sync.Once
+	result OwnerValidation
+}
+
+// NewAnalysis returns new instance of the Analysis for given CODEOWNERS entries.
+func NewAnalysis(entries []codeowners.Entry) *Analysis {
+	return &Analysis{
+		entries:  entries,
+		patterns: map[string]compiledPattern{},
+		owners:   map[string]*ownerValidation{},
+	}
+}
+
+// Matcher returns the matcher compiled from all CODEOWNERS entries.
+func (a *Analysis) Matcher() (*codeowners.Matcher, error) {
+	a.matcherOnce.Do(func() {
+		a.matcher, a.matcherErr = codeowners.NewMatcher(a.entries)
+	})
+	return a.matcher, a.matcherErr
+}
+
+// Files returns paths of all files tracked in the repository, relative to the repository root.
+func (a *Analysis) Files(repoDir string) ([]string, error) {
+	return a.treeFiles(nil, repoDir)
+}
+
+// treeFiles returns paths of all files listed by a given tree. Files are listed only once per run,
+// as all checks of a single run share the same tree.
+func (a *Analysis) treeFiles(tree Tree, repoDir string) ([]string, error) {
+	a.filesOnce.Do(func() {
+		a.files, a.filesErr = listFiles(tree, repoDir)
+	})
+	return a.files, a.filesErr
+}
+
+// scopedTreeFiles returns files listed by a given tree which are in a given scope. The scope is the same
+// for all checks of a single run, so files are filtered only once.
+func (a *Analysis) scopedTreeFiles(tree Tree, repoDir string, scope *Scope) ([]string, error) {
+	files, err := a.treeFiles(tree, repoDir)
+	if err != nil || scope == nil {
+		return files, err
+	}
+	a.scopedOnce.Do(func() {
+		a.scopedFiles = scope.Filter(files)
+	})
+	return a.scopedFiles, nil
+}
+
+func listFiles(tree Tree, repoDir string) ([]string, error) {
+	if tree == nil {
+		files, err := git.ListFiles(repoDir)
+		if err != nil {
+			return nil, &GitError{Op: "listing repository files", Err: err}
+		}
+		return files, nil
+	}
+
+	files, err := tree.ListFiles(repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("while listing repository files: %w", err)
+	}
+	return files, nil
+}
+
+// Diff returns files changed between the merge base of baseRef and HEAD.
+func (a *Analysis) Diff(repoDir, baseRef string) (*Diff, error) {
+	a.diffOnce.Do(func() {
+		a.diff, a.diffErr = changedFiles(repoDir, baseRef)
+	})
+	return a.diff, a.diffErr
+}
+
+func changedFiles(repoDir, baseRef string) (*Diff, error) {
+	files, err := git.ChangedFiles(repoDir, baseRef)
+	if err != nil {
+		return nil, &GitError{Op: "computing changes against " + baseRef, Err: err}
+	}
+	return &Diff{BaseRef: baseRef, Files: files}, nil
+}
+
+// Pattern returns the compiled CODEOWNERS pattern.
+func (a *Analysis) Pattern(raw string) (codeowners.Pattern, error) {
+	a.patternsMu.Lock()
+	defer a.patternsMu.Unlock()
+
+	compiled, found := a.patterns[raw]
+	if !found {
+		compiled.pattern, compiled.err = codeowners.NewPattern(raw)
+		a.patterns[raw] = compiled
+	}
+	return compiled.pattern, compiled.err
+}
+
+// ValidateOwner returns the memoized validation result of a given owner. The validate function is executed
+// only once per owner, even if the owner is validated by multiple checks at the same time.
+func (a *Analysis) ValidateOwner(owner string, validate func() OwnerValidation) OwnerValidation {
+	a.ownersMu.Lock()
+	v, found := a.owners[owner]
+	if !found {
+		v = &ownerValidation{}
+		a.owners[owner] = v
+	}
+	a.ownersMu.Unlock()
+
+	v.once.Do(func() {
+		v.result = validate()
+	})
+	return v.result
+}
+
+// Matcher returns the matcher of the CODEOWNERS entries. It's shared by all checks if the input
+// has the Analysis, otherwise it's compiled on each call.
+func (in Input) Matcher() (*codeowners.Matcher, error) {
+	if in.Analysis == nil {
+		return codeowners.NewMatcher(in.CodeownersEntries)
+	}
+	return in.Analysis.Matcher()
+}
+
+// Pattern returns the compiled CODEOWNERS pattern. It's shared by all checks if the input
+// has the Analysis, otherwise it's compiled on each call.
+func (in Input) Pattern(raw string) (codeowners.Pattern, error) {
+	if in.Analysis == nil {
+		return codeowners.NewPattern(raw)
+	}
+	return in.Analysis.Pattern(raw)
+}
+
+// ValidateOwner returns the validation result of a given owner. It's memoized for all checks if the input
+// has the Analysis, otherwise the validate function is executed on each call.
+func (in Input) ValidateOwner(owner string, validate func() OwnerValidation) OwnerValidation {
+	if in.Analysis == nil {
+		return validate()
+	}
+	return in.Analysis.ValidateOwner(owner, validate)
+}
+
+// Files returns paths of files tracked in the repository which are in the Scope, relative to the repository root.
+// Files are listed with the Tree, if set, otherwise with git. The list is shared by all checks
+// if the input has the Analysis, otherwise it's computed on each call.
+// Callers must not modify the returned slice.
+func (in Input) Files() ([]string, error) {
+	if in.Analysis == nil {
+		files, err := listFiles(in.Tree, in.RepoDir)
+		if err != nil {
+			return nil, err
+		}
+		return in.Scope.Filter(files), nil
+	}
+	return in.Analysis.scopedTreeFiles(in.Tree, in.RepoDir, in.Scope)
+}
+
+// AllFiles returns paths of all files tracked in the repository, regardless of the Scope, e.g. to tell
+// if a pattern matches files outside the validated area. Callers must not modify the returned slice.
+func (in Input) AllFiles() ([]string, error) {
+	if in.Analysis == nil {
+		return listFiles(in.Tree, in.RepoDir)
+	}
+	return in.Analysis.treeFiles(in.Tree, in.RepoDir)
+}
+
+// Diff returns files changed between the merge base of DiffBaseRef and HEAD. It returns nil if the
+// DiffBaseRef is not set, e.g. when the validation is not executed for a pull request.
+func (in Input) Diff() (*Diff, error) {
+	switch {
+	case in.DiffBaseRef == "":
+		return nil, nil
+	case in.Analysis == nil:
+		return changedFiles(in.RepoDir, in.DiffBaseRef)
+	default:
+		return in.Analysis.Diff(in.RepoDir, in.DiffBaseRef)
+	}
+}
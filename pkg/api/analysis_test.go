@@ -0,0 +1,162 @@
+package api_test
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.szostok.io/codeowners/internal/testutil/gitrepo"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalysisSharedByChecks(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader("*  @org/platform\n/docs/  @org/docs\n"))
+	in := api.Input{CodeownersEntries: entries, Analysis: api.NewAnalysis(entries)}
+
+	t.Run("Compiles matcher once", func(t *testing.T) {
+		// when
+		first, err := in.Matcher()
+		require.NoError(t, err)
+		second, err := in.Matcher()
+		require.NoError(t, err)
+
+		// then
+		assert.Same(t, first, second)
+		entry, found := first.Match("docs/README.md")
+		assert.True(t, found)
+		assert.Equal(t, []string{"@org/docs"}, entry.Owners)
+	})
+
+	t.Run("Validates each owner once", func(t *testing.T) {
+		// given
+		var calls int32
+		validate := func() api.OwnerValidation {
+			atomic.AddInt32(&calls, 1)
+			return api.OwnerValidation{Problem: "Team \"@org/platform\" does not exist"}
+		}
+
+		// when
+		var wg sync.WaitGroup
+		results := make([]api.OwnerValidation, 10)
+		for idx := range results {
+			wg.Add(1)
+			go func(idx int) {
+				defer wg.Done()
+				results[idx] = in.ValidateOwner("@org/platform", validate)
+			}(idx)
+		}
+		wg.Wait()
+
+		// then
+		assert.EqualValues(t, 1, calls)
+		for _, r := range results {
+			assert.Equal(t, "Team \"@org/platform\" does not exist", r.Problem)
+		}
+	})
+}
+
+func TestInputWithoutAnalysis(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader("/docs/  @org/docs\n"))
+	in := api.Input{CodeownersEntries: entries}
+
+	// when
+	matcher, err := in.Matcher()
+	require.NoError(t, err)
+	pattern, err := in.Pattern("/docs/")
+	require.NoError(t, err)
+
+	var calls int
+	for i := 0; i < 2; i++ {
+		in.ValidateOwner("@org/docs", func() api.OwnerValidation {
+			calls++
+			return api.OwnerValidation{}
+		})
+	}
+
+	// then
+	_, found := matcher.Match("docs/index.md")
+	assert.True(t, found)
+	assert.True(t, pattern.Match("docs/index.md"))
+	assert.Equal(t, 2, calls)
+}
+
+func TestInputRepositoryData(t *testing.T) {
+	// given
+	repoDir := gitrepo.New(t)
+	gitrepo.Run(t, repoDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	gitrepo.CommitFiles(t, repoDir, map[string]string{"README.md": "hello", "docs/index.md": "docs"})
+	gitrepo.Run(t, repoDir, "checkout", "--quiet", "-b", "feature")
+	gitrepo.CommitFiles(t, repoDir, map[string]string{"docs/index.md": "docs v2", "main.go": "package main"})
+
+	expFiles := []string{"README.md", "docs/index.md", "main.go"}
+	expDiff := &api.Diff{
+		BaseRef: "main",
+		Files: []api.ChangedFile{
+			{Path: "docs/index.md", Status: api.Modified},
+			{Path: "main.go", Status: api.Added},
+		},
+	}
+
+	tests := map[string]struct {
+		analysis *api.Analysis
+	}{
+		"Shared by checks": {
+			analysis: api.NewAnalysis(nil),
+		},
+		"Computed on demand": {
+			analysis: nil,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			in := api.Input{RepoDir: repoDir, DiffBaseRef: "main", Analysis: tc.analysis}
+
+			// when
+			files, err := in.Files()
+			require.NoError(t, err)
+			diff, err := in.Diff()
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, expFiles, files)
+			assert.Equal(t, expDiff, diff)
+		})
+	}
+
+	t.Run("Files in scope", func(t *testing.T) {
+		for tn, analysis := range map[string]*api.Analysis{"Shared by checks": api.NewAnalysis(nil), "Computed on demand": nil} {
+			t.Run(tn, func(t *testing.T) {
+				// given
+				scope, err := api.NewScope([]string{"/docs/", "*.go"}, nil)
+				require.NoError(t, err)
+				in := api.Input{RepoDir: repoDir, Analysis: analysis, Scope: scope}
+
+				// when
+				files, err := in.Files()
+				require.NoError(t, err)
+				allFiles, err := in.AllFiles()
+				require.NoError(t, err)
+
+				// then
+				assert.Equal(t, []string{"docs/index.md", "main.go"}, files)
+				assert.Equal(t, expFiles, allFiles)
+			})
+		}
+	})
+
+	t.Run("No diff without base reference", func(t *testing.T) {
+		// when
+		diff, err := api.Input{RepoDir: repoDir, Analysis: api.NewAnalysis(nil)}.Diff()
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, diff)
+	})
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.szostok.io/codeowners/internal/ptr"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+type (
+	// Checker allows to execute check in a generic way
+	Checker interface {
+		Check(ctx context.Context, in Input) (Output, error)
+		Name() string
+	}
+
+	Issue struct {
+		Severity SeverityType // enum // default error
+		LineNo   *uint64
+		// Column is the one-based column of the issue within the line, counted in characters.
+		// It's nil if the issue concerns the whole line.
+		Column  *uint64
+		Message string
+		// Fixes holds suggested changes of the CODEOWNERS file that resolve the issue.
+		Fixes []Fix
+		// Paths are repository files listed by the issue, e.g. not owned files. The baseline records them one by one,
+		// so a new file listed together with known ones is still reported.
+		Paths []string
+	}
+
+	// Fix describes a single, independently applicable change of the CODEOWNERS file.
+	Fix struct {
+		Description string
+		Edits       []LineEdit
+	}
+
+	// LineEdit replaces the whole line with a new text. If Delete is set, the line is removed.
+	// If Insert is set, the new text is inserted as a new line after the line, or before the first line if LineNo is 0.
+	LineEdit struct {
+		LineNo  uint64
+		NewText string
+		Delete  bool
+		Insert  bool
+	}
+
+	Input struct {
+		RepoDir           string
+		CodeownersEntries []codeowners.Entry
+		// DiffBaseRef is the git reference against which the pull request changes are computed. It's empty if
+		// the changes are not known.
+		DiffBaseRef string
+		// Analysis is shared by all checks executed in a single run. It's nil if a check is executed on its own.
+		Analysis *Analysis
+		// Tree lists the repository files. It's nil if files are listed with git.
+		Tree Tree
+		// NonGit is true if the repository is not managed by git, so it has no ignore rules, .gitattributes,
+		// or history. It's set independently of the Tree, as git repositories may be listed with a Tree too.
+		NonGit bool
+		// Scope limits files returned by Files. It's nil if all files are validated.
+		Scope *Scope
+		// Baseline holds known issues, which don't fail the validation. It's nil if no baseline is used.
+		Baseline Baseline
+	}
+
+	// Baseline tells if files listed by issues are known, so checks which list files in a single issue
+	// can report known files separately from new ones.
+	Baseline interface {
+		// HasPath returns true if a given file listed by an issue of a given check is known.
+		HasPath(check, path string) bool
+	}
+
+	// Tree lists files of a repository tree, so checks which only match files against patterns can be executed
+	// against trees not managed by git, e.g. Perforce workspaces.
+	Tree interface {
+		// ListFiles returns slash-separated paths of all files in the tree, relative to the root.
+		// If paths are given, only files under them are returned.
+		ListFiles(root string, paths ...string) ([]string, error)
+	}
+
+	Output struct {
+		Issues []Issue
+	}
+
+	OutputBuilder struct {
+		mux    sync.Mutex
+		issues []Issue
+	}
+)
+
+type ReportIssueOpt func(*Issue)
+
+func WithSeverity(s SeverityType) ReportIssueOpt {
+	return func(i *Issue) {
+		i.Severity = s
+	}
+}
+
+func WithEntry(e codeowners.Entry) ReportIssueOpt {
+	return func(i *Issue) {
+		i.LineNo = ptr.Uint64Ptr(e.LineNo)
+	}
+}
+
+// WithLineNo locates the issue in a given line, e.g. a GitLab section header, which is not an entry.
+func WithLineNo(lineNo uint64) ReportIssueOpt {
+	return func(i *Issue) {
+		i.LineNo = ptr.Uint64Ptr(lineNo)
+	}
+}
+
+// WithOwnersOf locates the issue in the line which lists owners of the entry, see codeowners.Entry.OwnersLineNo.
+func WithOwnersOf(e codeowners.Entry) ReportIssueOpt {
+	return func(i *Issue) {
+		i.LineNo = ptr.Uint64Ptr(e.OwnersLineNo())
+	}
+}
+
+// WithColumn sets the one-based column of the issue within its line.
+func WithColumn(col uint64) ReportIssueOpt {
+	return func(i *Issue) {
+		i.Column = ptr.Uint64Ptr(col)
+	}
+}
+
+// WithPaths lists repository files which the issue is about.
+func WithPaths(paths ...string) ReportIssueOpt {
+	return func(i *Issue) {
+		i.Paths = append(i.Paths, paths...)
+	}
+}
+
+// WithFix attaches a suggested fix to the issue.
+func WithFix(f Fix) ReportIssueOpt {
+	return func(i *Issue) {
+		i.Fixes = append(i.Fixes, f)
+	}
+}
+
+func (bldr *OutputBuilder) ReportIssue(msg string, opts ...ReportIssueOpt) *OutputBuilder {
+	if bldr == nil { // TODO: error?
+		return nil
+	}
+
+	i := Issue{
+		Severity: Error,
+		Message:  msg,
+	}
+
+	for _, opt := range opts {
+		opt(&i)
+	}
+
+	bldr.mux.Lock()
+	defer bldr.mux.Unlock()
+	bldr.issues = append(bldr.issues, i)
+
+	return bldr
+}
+
+func (bldr *OutputBuilder) Output() Output {
+	if bldr == nil {
+		return Output{}
+	}
+	return Output{Issues: bldr.issues}
+}
+
+type SeverityType int
+
+const (
+	Error SeverityType = iota + 1
+	Warning
+)
+
+func (s SeverityType) String() string {
+	switch s {
+	case Error:
+		return "Error"
+	case Warning:
+		return "Warning"
+	default:
+		return ""
+	}
+}
+
+// Unmarshal provides custom parsing of severity type.
+// Implements envconfig.Unmarshal interface.
+func (s *SeverityType) Unmarshal(in string) error {
+	switch strings.ToLower(in) {
+	case "error", "err":
+		*s = Error
+	case "warning", "warn":
+		*s = Warning
+	default:
+		return fmt.Errorf("not a valid severity type: %q", in)
+	}
+
+	return nil
+}
+
+func (s *SeverityType) Set(in string) error {
+	switch strings.ToLower(in) {
+	case "error", "err":
+		*s = Error
+	case "warning", "warn":
+		*s = Warning
+	default:
+		return fmt.Errorf("not a valid severity type: %q", in)
+	}
+	return nil
+}
+
+func (s *SeverityType) Type() string {
+	return "SeverityType"
+}
@@ -4,7 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"go.szostok.io/codeowners/internal/api"
+	"go.szostok.io/codeowners/pkg/api"
 )
 
 func TestAPIBuilder(t *testing.T) {
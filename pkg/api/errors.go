@@ -0,0 +1,181 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.szostok.io/codeowners/internal/redact"
+)
+
+type (
+	// APIError is returned when a call to the provider API fails.
+	APIError struct {
+		// Provider is a human-readable provider name, e.g. GitHub.
+		Provider string
+		// Op describes the failed operation, e.g. "listing reviews of pull request #1".
+		Op string
+		// StatusCode is the HTTP status code, 0 if no response was received.
+		StatusCode int
+		// RateLimited is set if the request was rejected because of the rate limit.
+		RateLimited bool
+		// RetryAfter is the delay before the next attempt requested by the provider, 0 if not requested.
+		RetryAfter time.Duration
+		Err        error
+	}
+
+	// ConfigError is returned when the configuration is invalid.
+	ConfigError struct {
+		// Field is the configuration option name, e.g. OWNER_CHECKER_REPOSITORY.
+		Field string
+		Err   error
+	}
+
+	// GitError is returned when a git command fails.
+	GitError struct {
+		// Op describes the failed operation, e.g. "listing repository files".
+		Op  string
+		Err error
+	}
+
+	// MatchError is returned when a CODEOWNERS pattern cannot be compiled.
+	MatchError struct {
+		Pattern string
+		Err     error
+	}
+)
+
+// Error returns the error message with credentials scrubbed, as wrapped HTTP errors may contain request details.
+func (e *APIError) Error() string { return redact.String(fmt.Sprintf("while %s: %v", e.Op, e.Err)) }
+func (e *APIError) Unwrap() error { return e.Err }
+
+// Retryable returns true if the request may succeed if retried, e.g. on server errors or when rate limited.
+func (e *APIError) Retryable() bool {
+	switch {
+	case e.StatusCode == 0, e.StatusCode == http.StatusTooManyRequests, e.RateLimited:
+		return true
+	case e.StatusCode >= http.StatusInternalServerError:
+		return true
+	default:
+		return false
+	}
+}
+
+// Hint returns the troubleshooting hint.
+func (e *APIError) Hint() string {
+	switch {
+	case e.RateLimited:
+		return fmt.Sprintf("%s API rate limit exceeded. Authenticate to increase the limit or retry later.", e.Provider)
+	case e.StatusCode == http.StatusUnauthorized:
+		return fmt.Sprintf("Verify that the %s token is valid and not expired.", e.Provider)
+	case e.StatusCode == http.StatusForbidden:
+		return fmt.Sprintf("Verify that the %s token has the required permissions, e.g. the 'read:org' scope.", e.Provider)
+	case e.StatusCode == http.StatusNotFound:
+		return fmt.Sprintf("The resource does not exist or the %s token has no access to it. For private repositories, the token requires the 'repo' scope.", e.Provider)
+	case e.Retryable():
+		return fmt.Sprintf("%s API is temporarily unavailable, retry later.", e.Provider)
+	default:
+		return ""
+	}
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// Hint returns the troubleshooting hint.
+func (e *ConfigError) Hint() string {
+	if e.Field == "" {
+		return ""
+	}
+	return fmt.Sprintf("Check the %s configuration.", e.Field)
+}
+
+func (e *GitError) Error() string { return fmt.Sprintf("while %s: %v", e.Op, e.Err) }
+func (e *GitError) Unwrap() error { return e.Err }
+
+// Hint returns the troubleshooting hint.
+func (e *GitError) Hint() string {
+	msg := e.Err.Error()
+	switch {
+	case strings.Contains(msg, "dubious ownership"):
+		return "The repository is owned by a different user. Mark it as safe with 'git config --global --add safe.directory <path>'."
+	case strings.Contains(msg, "not a git repository"):
+		return "Set REPOSITORY_PATH to the root of a git repository, or set TREE to list files of a repository not managed by git."
+	default:
+		return ""
+	}
+}
+
+func (e *MatchError) Error() string {
+	return fmt.Sprintf("while compiling pattern %s: %v", e.Pattern, e.Err)
+}
+func (e *MatchError) Unwrap() error { return e.Err }
+
+// Hint returns the troubleshooting hint.
+func (e *MatchError) Hint() string {
+	return fmt.Sprintf("Verify the syntax of the %q pattern.", e.Pattern)
+}
+
+// IsRetryable returns true if the error is temporary and the failed operation may succeed if retried.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
+
+// RetryAfter returns the delay before the next attempt requested by the provider, or 0 if not requested.
+func RetryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// RateLimit returns true if a given response rejected the request because of the rate limit, and the delay
+// requested in the Retry-After header. Besides 429, providers such as GitHub reject rate-limited requests with 403
+// and the Retry-After or `X-RateLimit-Remaining: 0` headers.
+func RateLimit(resp *http.Response) (bool, time.Duration) {
+	if resp == nil {
+		return false, 0
+	}
+	retryAfter, requested := parseRetryAfter(resp.Header.Get("Retry-After"))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true, retryAfter
+	case http.StatusForbidden:
+		limited := requested || resp.Header.Get("X-RateLimit-Remaining") == "0"
+		return limited, retryAfter
+	default:
+		return false, 0
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, which holds either the number of seconds or the HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(at); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// Hint returns the troubleshooting hint for a given error, or an empty string if there is none.
+func Hint(err error) string {
+	var h interface{ Hint() string }
+	if errors.As(err, &h) {
+		return h.Hint()
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package api_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorTaxonomy(t *testing.T) {
+	tests := map[string]struct {
+		err          error
+		expRetryable bool
+		expHint      string
+	}{
+		"Server error is retryable": {
+			err:          &api.APIError{Provider: "GitHub", Op: "listing teams", StatusCode: http.StatusBadGateway, Err: errors.New("bad gateway")},
+			expRetryable: true,
+			expHint:      "GitHub API is temporarily unavailable, retry later.",
+		},
+		"Rate-limited forbidden error is retryable": {
+			err:          &api.APIError{Provider: "GitHub", Op: "listing teams", StatusCode: http.StatusForbidden, RateLimited: true, Err: errors.New("rate limit")},
+			expRetryable: true,
+			expHint:      "GitHub API rate limit exceeded. Authenticate to increase the limit or retry later.",
+		},
+		"Forbidden error is not retryable": {
+			err:     &api.APIError{Provider: "GitHub", Op: "listing teams", StatusCode: http.StatusForbidden, Err: errors.New("forbidden")},
+			expHint: "Verify that the GitHub token has the required permissions, e.g. the 'read:org' scope.",
+		},
+		"Wrapped unauthorized error": {
+			err:     fmt.Errorf("wrapped: %w", &api.APIError{Provider: "GitHub", Op: "listing teams", StatusCode: http.StatusUnauthorized, Err: errors.New("bad credentials")}),
+			expHint: "Verify that the GitHub token is valid and not expired.",
+		},
+		"Git error of directory outside repository": {
+			err:     &api.GitError{Op: "listing repository files", Err: errors.New("fatal: not a git repository (or any of the parent directories): .git")},
+			expHint: "Set REPOSITORY_PATH to the root of a git repository, or set TREE to list files of a repository not managed by git.",
+		},
+		"Config error": {
+			err:     &api.ConfigError{Field: "OWNER_CHECKER_REPOSITORY", Err: errors.New("wrong repository name")},
+			expHint: "Check the OWNER_CHECKER_REPOSITORY configuration.",
+		},
+		"Plain error": {
+			err: errors.New("boom"),
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expRetryable, api.IsRetryable(tc.err))
+			assert.Equal(t, tc.expHint, api.Hint(tc.err))
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	tests := map[string]struct {
+		statusCode    int
+		headers       map[string]string
+		expLimited    bool
+		expRetryAfter time.Duration
+	}{
+		"Too many requests": {
+			statusCode: http.StatusTooManyRequests,
+			expLimited: true,
+		},
+		"Too many requests with Retry-After seconds": {
+			statusCode:    http.StatusTooManyRequests,
+			headers:       map[string]string{"Retry-After": "30"},
+			expLimited:    true,
+			expRetryAfter: 30 * time.Second,
+		},
+		"Forbidden with Retry-After": {
+			statusCode:    http.StatusForbidden,
+			headers:       map[string]string{"Retry-After": "60"},
+			expLimited:    true,
+			expRetryAfter: time.Minute,
+		},
+		"Forbidden with exhausted rate limit": {
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{"X-RateLimit-Remaining": "0"},
+			expLimited: true,
+		},
+		"Forbidden with remaining rate limit": {
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{"X-RateLimit-Remaining": "4999"},
+		},
+		"Forbidden with Retry-After date in the past": {
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{"Retry-After": "Wed, 21 Oct 2015 07:28:00 GMT"},
+			expLimited: true,
+		},
+		"Server error with Retry-After": {
+			statusCode: http.StatusServiceUnavailable,
+			headers:    map[string]string{"Retry-After": "30"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			resp := &http.Response{StatusCode: tc.statusCode, Header: http.Header{}}
+			for k, v := range tc.headers {
+				resp.Header.Set(k, v)
+			}
+
+			// when
+			limited, retryAfter := api.RateLimit(resp)
+
+			// then
+			assert.Equal(t, tc.expLimited, limited)
+			assert.Equal(t, tc.expRetryAfter, retryAfter)
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &api.APIError{StatusCode: http.StatusForbidden, RateLimited: true, RetryAfter: time.Minute, Err: errors.New("rate limit")})
+
+	assert.True(t, api.IsRetryable(err))
+	assert.Equal(t, time.Minute, api.RetryAfter(err))
+	assert.Zero(t, api.RetryAfter(errors.New("boom")))
+}
+
+func TestAPIErrorRedactsCredentials(t *testing.T) {
+	// given
+	err := &api.APIError{
+		Provider: "Identity source",
+		Op:       "fetching identities",
+		Err:      errors.New(`unexpected status 400: {"headers": {"Authorization": "Bearer abc"}}`),
+	}
+
+	// when
+	msg := err.Error()
+
+	// then
+	assert.Equal(t, `while fetching identities: unexpected status 400: {"headers": {"Authorization": "[REDACTED]"}}`, msg)
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// Scope limits files considered by checks to the ones which match any include path, if given, and don't match
+// any exclude path, so teams can validate only their area of a monorepo. Paths are written in the CODEOWNERS
+// pattern syntax, e.g. `/services/payments/` or `*.proto`. The nil Scope contains all files.
+type Scope struct {
+	include []codeowners.Pattern
+	exclude []codeowners.Pattern
+}
+
+// NewScope returns new instance of the Scope. It returns nil if no paths are given.
+func NewScope(include, exclude []string) (*Scope, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	var (
+		s   Scope
+		err error
+	)
+	if s.include, err = compileScopePaths(include); err != nil {
+		return nil, err
+	}
+	if s.exclude, err = compileScopePaths(exclude); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// scopePaths is the JSON representation of the Scope.
+type scopePaths struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// MarshalJSON encodes the include and exclude paths of the scope, e.g. to pass it to a check executed in another process.
+func (s *Scope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(scopePaths{Include: patternStrings(s.include), Exclude: patternStrings(s.exclude)})
+}
+
+// UnmarshalJSON decodes the scope encoded by MarshalJSON.
+func (s *Scope) UnmarshalJSON(data []byte) error {
+	var paths scopePaths
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return err
+	}
+
+	var err error
+	if s.include, err = compileScopePaths(paths.Include); err != nil {
+		return err
+	}
+	if s.exclude, err = compileScopePaths(paths.Exclude); err != nil {
+		return err
+	}
+	return nil
+}
+
+func patternStrings(patterns []codeowners.Pattern) []string {
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		out = append(out, p.String())
+	}
+	return out
+}
+
+func compileScopePaths(paths []string) ([]codeowners.Pattern, error) {
+	out := make([]codeowners.Pattern, 0, len(paths))
+	for _, p := range paths {
+		compiled, err := codeowners.NewPattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("while compiling path %q: %w", p, err)
+		}
+		out = append(out, compiled)
+	}
+	return out, nil
+}
+
+// Contains returns true if a given slash-separated path, relative to the repository root, is in the scope.
+func (s *Scope) Contains(path string) bool {
+	if s == nil {
+		return true
+	}
+	for _, p := range s.exclude {
+		if p.Match(path) {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, p := range s.include {
+		if p.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns files which are in the scope. The given slice is returned as it is for the nil Scope.
+func (s *Scope) Filter(files []string) []string {
+	if s == nil {
+		return files
+	}
+
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if s.Contains(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// CoversPattern returns true if a given CODEOWNERS pattern may own files in the scope, even if it doesn't match
+// any file, e.g. to report stale patterns only to the team which owns the area. Patterns are judged by their
+// leading directories without wildcards, so patterns which may match anywhere, such as `*.md` or `docs/`,
+// are covered by every scope, and patterns of parent directories are covered by scopes of their subdirectories.
+func (s *Scope) CoversPattern(pattern string) bool {
+	if s == nil {
+		return true
+	}
+
+	prefix, isFile := staticPrefix(pattern)
+	if prefix == "" {
+		return true
+	}
+	// the pattern owns files under the leading directories, and, if it has no wildcards nor the trailing slash,
+	// it may be the path of a file itself
+	if (isFile && s.Contains(prefix)) || s.Contains(prefix+"/"+scopeProbe) {
+		return true
+	}
+
+	for _, p := range s.include {
+		in, _ := staticPrefix(p.String())
+		if strings.HasPrefix(in, prefix+"/") && (s.Contains(in) || s.Contains(in+"/"+scopeProbe)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeProbe is the name of a file which is assumed to be in a directory, to check if the directory is in the scope.
+const scopeProbe = "file"
+
+// staticPrefix returns leading segments of a pattern without wildcards, joined with slashes, and whether they are
+// the whole pattern without the trailing slash. The prefix is empty if the pattern may match at any level, as,
+// like in gitignore, a pattern without a slash, except the trailing one, matches at any level.
+func staticPrefix(pattern string) (string, bool) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(trimmed, "/") {
+		return "", false
+	}
+
+	segments := strings.Split(strings.TrimPrefix(trimmed, "/"), "/")
+	var prefix []string
+	for _, segment := range segments {
+		if segment == "" || strings.ContainsAny(segment, "*?") {
+			break
+		}
+		prefix = append(prefix, segment)
+	}
+	return strings.Join(prefix, "/"), len(prefix) == len(segments) && !strings.HasSuffix(pattern, "/")
+}
@@ -0,0 +1,121 @@
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.szostok.io/codeowners/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeFilter(t *testing.T) {
+	files := []string{
+		"README.md",
+		"services/payments/api.go",
+		"services/payments/vendor/lib.go",
+		"services/search/index.go",
+		"proto/payments.proto",
+	}
+
+	tests := map[string]struct {
+		include  []string
+		exclude  []string
+		expFiles []string
+	}{
+		"No paths": {
+			expFiles: files,
+		},
+		"Include directory": {
+			include:  []string{"/services/payments/"},
+			expFiles: []string{"services/payments/api.go", "services/payments/vendor/lib.go"},
+		},
+		"Include multiple paths": {
+			include:  []string{"/services/payments/", "*.proto"},
+			expFiles: []string{"services/payments/api.go", "services/payments/vendor/lib.go", "proto/payments.proto"},
+		},
+		"Exclude takes precedence over include": {
+			include:  []string{"/services/payments/"},
+			exclude:  []string{"vendor/"},
+			expFiles: []string{"services/payments/api.go"},
+		},
+		"Exclude only": {
+			exclude:  []string{"/services/"},
+			expFiles: []string{"README.md", "proto/payments.proto"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			scope, err := api.NewScope(tc.include, tc.exclude)
+			require.NoError(t, err)
+
+			// when
+			got := scope.Filter(files)
+
+			// then
+			assert.Equal(t, tc.expFiles, got)
+		})
+	}
+}
+
+func TestScopeCoversPattern(t *testing.T) {
+	// given
+	scope, err := api.NewScope([]string{"/services/payments/"}, []string{"/services/payments/legacy/"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		pattern   string
+		expCovers bool
+	}{
+		"Pattern which matches at any level":     {pattern: "*.md", expCovers: true},
+		"Directory which matches at any level":   {pattern: "docs/", expCovers: true},
+		"Pattern starting with wildcard":         {pattern: "/**/api.go", expCovers: true},
+		"Directory in the scope":                 {pattern: "/services/payments/", expCovers: true},
+		"Subdirectory in the scope":              {pattern: "/services/payments/api/", expCovers: true},
+		"File in the scope":                      {pattern: "/services/payments/api.go", expCovers: true},
+		"Wildcard under directory in the scope":  {pattern: "/services/payments/**/*.go", expCovers: true},
+		"Parent of the scope":                    {pattern: "/services/", expCovers: true},
+		"Sibling directory":                      {pattern: "/services/search/", expCovers: false},
+		"Wildcard under directory outside scope": {pattern: "/services/search/*.go", expCovers: false},
+		"Excluded directory":                     {pattern: "/services/payments/legacy/", expCovers: false},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got := scope.CoversPattern(tc.pattern)
+
+			// then
+			assert.Equal(t, tc.expCovers, got)
+		})
+	}
+}
+
+func TestNilScopeContainsAllFiles(t *testing.T) {
+	// given
+	var scope *api.Scope
+
+	// then
+	assert.True(t, scope.Contains("any/file.go"))
+	assert.True(t, scope.CoversPattern("/any/"))
+	assert.Equal(t, []string{"a.go"}, scope.Filter([]string{"a.go"}))
+}
+
+func TestScopeJSONRoundTrip(t *testing.T) {
+	// given
+	scope, err := api.NewScope([]string{"/services/payments/"}, []string{"*.tmp"})
+	require.NoError(t, err)
+
+	// when
+	raw, err := json.Marshal(scope)
+	require.NoError(t, err)
+	var got api.Scope
+	require.NoError(t, json.Unmarshal(raw, &got))
+
+	// then
+	assert.JSONEq(t, `{"include": ["/services/payments/"], "exclude": ["*.tmp"]}`, string(raw))
+	assert.Equal(t, scope, &got)
+	assert.True(t, got.Contains("services/payments/main.go"))
+	assert.False(t, got.Contains("services/payments/build.tmp"))
+}
@@ -0,0 +1,157 @@
+// Package checktest provides helpers to test CODEOWNERS checks against in-memory repository fixtures.
+//
+// A fixture is materialized as a temporary git repository, so checks which list tracked files or compute
+// pull request changes work the same way as in a real repository. The check output can be asserted
+// against golden files:
+//
+//	func TestMyCheck(t *testing.T) {
+//		out := checktest.Run(t, NewMyCheck(), checktest.Fixture{
+//			Codeowners: "*  @org/platform\n",
+//			Files:      map[string]string{"main.go": "package main"},
+//		})
+//		checktest.AssertGolden(t, out)
+//	}
+//
+// Golden files are stored in the `testdata` directory and are updated with the `-update` test flag.
+package checktest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+// BaseRef is the branch with the base version of the fixture repository. It's set as the Input.DiffBaseRef
+// if the fixture has changes.
+const BaseRef = "base"
+
+// Fixture describes the repository on which the check is executed.
+type Fixture struct {
+	// Codeowners is the CODEOWNERS file content. It's committed in the repository root and parsed into
+	// the Input entries, so reported line numbers refer to this content.
+	Codeowners string
+	// Files maps paths relative to the repository root to their content.
+	Files map[string]string
+	// Changes maps paths of files added or modified on top of the base version to their content.
+	// Changes and Removed are committed on a separate branch, so diff-aware checks can be tested.
+	Changes map[string]string
+	// Removed lists paths of files removed on top of the base version.
+	Removed []string
+}
+
+// Repo materializes a given fixture as a git repository in a temporary directory and returns the check input.
+// The repository is removed when the test and all its subtests complete.
+func Repo(t testing.TB, fx Fixture) api.Input {
+	t.Helper()
+
+	dir := t.TempDir()
+	git(t, dir, "init", "--quiet")
+	git(t, dir, "config", "user.email", "checktest@example.com")
+	git(t, dir, "config", "user.name", "checktest")
+	git(t, dir, "config", "commit.gpgsign", "false")
+	git(t, dir, "checkout", "--quiet", "-b", BaseRef)
+
+	files := map[string]string{"CODEOWNERS": fx.Codeowners}
+	for name, content := range fx.Files {
+		files[name] = content
+	}
+	commit(t, dir, files, nil)
+
+	entries := codeowners.ParseCodeowners(strings.NewReader(fx.Codeowners))
+	in := api.Input{
+		RepoDir:           dir,
+		CodeownersEntries: entries,
+		Analysis:          api.NewAnalysis(entries),
+	}
+
+	if len(fx.Changes) > 0 || len(fx.Removed) > 0 {
+		git(t, dir, "checkout", "--quiet", "-b", "changes")
+		commit(t, dir, fx.Changes, fx.Removed)
+		in.DiffBaseRef = BaseRef
+	}
+
+	return in
+}
+
+// Run executes the check against a given fixture. The test fails immediately if the check returns an error.
+func Run(t testing.TB, c api.Checker, fx Fixture) api.Output {
+	t.Helper()
+
+	out, err := c.Check(context.Background(), Repo(t, fx))
+	if err != nil {
+		t.Fatalf("%s failed: %v", c.Name(), err)
+	}
+	return out
+}
+
+// AssertGolden compares the formatted check output with the `testdata/<test name>.golden.txt` file.
+func AssertGolden(t *testing.T, out api.Output) {
+	t.Helper()
+
+	g := goldie.New(t, goldie.WithNameSuffix(".golden.txt"))
+	g.Assert(t, t.Name(), []byte(Format(out)))
+}
+
+// Format returns a stable, human-readable form of the check output, one issue per line, e.g.:
+//
+//	[error] line 2: "/docs/" does not match any files in repository
+//	    fix: Remove the entry
+func Format(out api.Output) string {
+	if len(out.Issues) == 0 {
+		return "no issues\n"
+	}
+
+	var b strings.Builder
+	for _, i := range out.Issues {
+		fmt.Fprintf(&b, "[%s]", strings.ToLower(i.Severity.String()))
+		if i.LineNo != nil {
+			fmt.Fprintf(&b, " line %d:", *i.LineNo)
+		}
+		fmt.Fprintf(&b, " %s\n", i.Message)
+		for _, f := range i.Fixes {
+			fmt.Fprintf(&b, "    fix: %s\n", f.Description)
+		}
+	}
+	return b.String()
+}
+
+func commit(t testing.TB, dir string, files map[string]string, removed []string) {
+	t.Helper()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("while creating fixture directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("while writing fixture file: %v", err)
+		}
+	}
+	for _, name := range removed {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Fatalf("while removing fixture file: %v", err)
+		}
+	}
+
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "--quiet", "--allow-empty", "-m", "checktest fixture")
+}
+
+func git(t testing.TB, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+	}
+}
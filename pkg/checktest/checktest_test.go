@@ -0,0 +1,103 @@
+package checktest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/checktest"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// unownedChanges is a custom check which reports files changed in the pull request without owners.
+type unownedChanges struct{}
+
+func (unownedChanges) Check(_ context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+
+	diff, err := in.Diff()
+	if err != nil || diff == nil {
+		return api.Output{}, err
+	}
+	matcher, err := in.Matcher()
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	for _, f := range diff.Files {
+		if f.Status == api.Deleted {
+			continue
+		}
+		if _, owned := matcher.Match(f.Path); !owned {
+			bldr.ReportIssue(fmt.Sprintf("File %q is %s without an owner", f.Path, f.Status), api.WithSeverity(api.Warning))
+		}
+	}
+	return bldr.Output(), nil
+}
+
+func (unownedChanges) Name() string {
+	return "Unowned Changes Checker"
+}
+
+func TestRunWithChanges(t *testing.T) {
+	// given
+	fx := checktest.Fixture{
+		Codeowners: "/docs/ @org/docs\n",
+		Files: map[string]string{
+			"docs/README.md": "# Docs",
+			"main.go":        "package main",
+		},
+		Changes: map[string]string{
+			"docs/install.md": "# Install",
+			"main.go":         "package main // changed",
+			"cmd/app/app.go":  "package app",
+		},
+		Removed: []string{"docs/README.md"},
+	}
+
+	// when
+	out := checktest.Run(t, unownedChanges{}, fx)
+
+	// then
+	checktest.AssertGolden(t, out)
+}
+
+func TestRunWithoutChanges(t *testing.T) {
+	// given
+	fx := checktest.Fixture{
+		Codeowners: "*  @org/all\n",
+		Files:      map[string]string{"main.go": "package main"},
+	}
+
+	// when
+	in := checktest.Repo(t, fx)
+	out := checktest.Run(t, unownedChanges{}, fx)
+
+	// then
+	assert.Empty(t, in.DiffBaseRef)
+	files, err := in.Files()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CODEOWNERS", "main.go"}, files)
+	assert.Equal(t, "no issues\n", checktest.Format(out))
+}
+
+func TestFormat(t *testing.T) {
+	// given
+	var bldr api.OutputBuilder
+	bldr.ReportIssue("Pattern is duplicated", api.WithEntry(entryAt(3)), api.WithFix(api.Fix{Description: "Remove line 3"}))
+	bldr.ReportIssue("Team is stale", api.WithSeverity(api.Warning))
+
+	// when
+	got := checktest.Format(bldr.Output())
+
+	// then
+	assert.Equal(t, "[error] line 3: Pattern is duplicated\n    fix: Remove line 3\n[warning] Team is stale\n", got)
+}
+
+func entryAt(lineNo uint64) codeowners.Entry {
+	return codeowners.Entry{LineNo: lineNo, Pattern: "*", Owners: []string{"@org/all"}}
+}
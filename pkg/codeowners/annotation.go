@@ -0,0 +1,25 @@
+package codeowners
+
+import "strings"
+
+// Annotation returns the value of the `key:value` annotation placed in the entry inline comment.
+// Both `# key:value` and `# key: value` forms are supported. Keys are compared case-insensitively.
+//
+// For example, for the `/infra/ @org/sre # expires:2025-12-31` entry, Annotation("expires")
+// returns "2025-12-31".
+func (e Entry) Annotation(key string) (string, bool) {
+	fields := strings.Fields(e.Comment)
+	for idx, f := range fields {
+		name, value, found := strings.Cut(f, ":")
+		if !found || !strings.EqualFold(name, key) {
+			continue
+		}
+
+		if value == "" && idx+1 < len(fields) {
+			value = fields[idx+1]
+		}
+		return value, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,62 @@
+package codeowners_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestEntryAnnotation(t *testing.T) {
+	tests := map[string]struct {
+		line     string
+		key      string
+		expValue string
+		expFound bool
+	}{
+		"Should return value without space": {
+			line:     "/infra/ @org/sre # expires:2025-12-31",
+			key:      "expires",
+			expValue: "2025-12-31",
+			expFound: true,
+		},
+		"Should return value separated by space": {
+			line:     "/infra/ @org/sre # approved-by: @org/eng-leads",
+			key:      "approved-by",
+			expValue: "@org/eng-leads",
+			expFound: true,
+		},
+		"Should match key case-insensitively": {
+			line:     "/infra/ @org/sre # temporary, Expires:2025-12-31",
+			key:      "expires",
+			expValue: "2025-12-31",
+			expFound: true,
+		},
+		"Should not find missing annotation": {
+			line:     "/infra/ @org/sre # just a comment",
+			key:      "expires",
+			expFound: false,
+		},
+		"Should not find annotation without comment": {
+			line:     "/infra/ @org/sre",
+			key:      "expires",
+			expFound: false,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			entries := codeowners.ParseCodeowners(strings.NewReader(tc.line))
+			require.Len(t, entries, 1)
+
+			// when
+			value, found := entries[0].Annotation(tc.key)
+
+			// then
+			assert.Equal(t, tc.expFound, found)
+			assert.Equal(t, tc.expValue, value)
+		})
+	}
+}
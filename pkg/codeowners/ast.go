@@ -0,0 +1,233 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NodeKind describes the kind of the CODEOWNERS line.
+type NodeKind int
+
+const (
+	// BlankNode is an empty line or a line with whitespaces only.
+	BlankNode NodeKind = iota
+	// CommentNode is a line with a comment only.
+	CommentNode
+	// SectionNode is a GitLab section header, such as `[Docs]`, `^[Optional docs]`, or `[Docs][2] @docs-team`.
+	SectionNode
+	// EntryNode is a line with a pattern and its owners.
+	EntryNode
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case BlankNode:
+		return "blank"
+	case CommentNode:
+		return "comment"
+	case SectionNode:
+		return "section"
+	case EntryNode:
+		return "entry"
+	default:
+		return "unknown"
+	}
+}
+
+// sectionRegexp matches GitLab section headers. Captures the name and the number of required approvals.
+var sectionRegexp = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[(\d+)\])?`)
+
+// Section is a GitLab section header.
+type Section struct {
+	Name string
+	// LineNo is the line of the section header.
+	LineNo uint64
+	// Optional is true for sections prefixed with `^`, which don't require approvals.
+	Optional bool
+	// Approvals is the number of required approvals, 0 if not set.
+	Approvals int
+	// Owners are the default owners of entries in the section which don't have their own owners.
+	Owners []string
+	// Comment holds the inline comment placed after the header, without the leading '#'.
+	Comment string
+}
+
+// Node is a single line of the CODEOWNERS file.
+type Node struct {
+	Kind NodeKind
+	// Text is the line as it appears in the file, without the line terminator.
+	Text string
+	// EOL is the line terminator, i.e. "\n", "\r\n", or an empty string for the last line without a terminator.
+	EOL    string
+	LineNo uint64
+	// Offset is the byte offset of the line in the file.
+	Offset int64
+	// Entry is set for entry nodes. Changing its pattern, owners, or comment rewrites the line when serialized.
+	Entry *Entry
+	// Section is set for section nodes, and for entry nodes placed in a section.
+	Section *Section
+
+	// orig holds the entry as parsed, to detect changes.
+	orig Entry
+}
+
+// AST is the lossless representation of the CODEOWNERS file.
+// Serializing the AST without changes reproduces the input byte-for-byte.
+type AST struct {
+	Nodes []*Node
+}
+
+// ParseAST reads the CODEOWNERS content into the AST.
+// Section headers are recognized in the GitLab syntax, other lines are parsed the same way as by ParseCodeowners.
+func ParseAST(r io.Reader) (*AST, error) {
+	var (
+		out      AST
+		comments []string
+		section  *Section
+		offset   int64
+	)
+
+	br := bufio.NewReader(r)
+	for no := uint64(1); ; no++ {
+		line, err := br.ReadString('\n')
+		if line == "" {
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		text := strings.TrimSuffix(line, "\n")
+		text = strings.TrimSuffix(text, "\r")
+		node := &Node{
+			Text:   text,
+			EOL:    line[len(text):],
+			LineNo: no,
+			Offset: offset,
+		}
+		offset += int64(len(line))
+
+		fields := strings.Fields(text)
+		switch {
+		case len(fields) == 0:
+			node.Kind = BlankNode
+			comments = nil
+		case strings.HasPrefix(fields[0], "#"):
+			node.Kind = CommentNode
+			comments = append(comments, text)
+		case sectionRegexp.MatchString(strings.TrimSpace(text)):
+			node.Kind = SectionNode
+			section = parseSection(strings.TrimSpace(text))
+			section.LineNo = no
+			node.Section = section
+			comments = nil
+		default:
+			entry := newEntry(fields)
+			entry.LineNo = no
+			entry.LeadingComments = comments
+			entry.Raw = text
+			entry.Offset = node.Offset
+
+			node.Kind = EntryNode
+			node.Entry = &entry
+			node.Section = section
+			node.orig = entry
+			node.orig.Owners = append([]string(nil), entry.Owners...)
+			comments = nil
+		}
+
+		out.Nodes = append(out.Nodes, node)
+		if err != nil {
+			break
+		}
+	}
+
+	return &out, nil
+}
+
+func parseSection(line string) *Section {
+	m := sectionRegexp.FindStringSubmatch(line)
+	out := &Section{
+		Name:     m[1],
+		Optional: strings.HasPrefix(line, "^"),
+	}
+	if m[2] != "" {
+		out.Approvals, _ = strconv.Atoi(m[2])
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, m[0]))
+	for idx, field := range fields {
+		if strings.HasPrefix(field, "#") {
+			out.Comment = strings.TrimSpace(strings.TrimPrefix(strings.Join(fields[idx:], " "), "#"))
+			break
+		}
+		out.Owners = append(out.Owners, field)
+	}
+	return out
+}
+
+// Entries returns entries of the current AST content.
+func (a *AST) Entries() []Entry {
+	var out []Entry
+	for _, n := range a.Nodes {
+		if n.Kind == EntryNode && n.Entry != nil {
+			out = append(out, *n.Entry)
+		}
+	}
+	return out
+}
+
+// WriteTo writes the CODEOWNERS content. Lines of unchanged nodes are written as they were read.
+func (a *AST) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, n := range a.Nodes {
+		written, err := io.WriteString(w, n.String()+n.EOL)
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Bytes returns the CODEOWNERS content.
+func (a *AST) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = a.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// String returns the line text. Entry lines changed after parsing are rewritten,
+// preserving the original indentation and whitespaces between pattern, owners, and comment.
+func (n *Node) String() string {
+	if n.Kind != EntryNode || n.Entry == nil || !n.changed() {
+		return n.Text
+	}
+
+	parts, ok := splitEntryLine(n.Text)
+	if !ok {
+		parts = entryParts{gap: " ", sep: " ", commentGap: " "}
+	}
+	indent := parts.pattern[:len(parts.pattern)-len(strings.TrimLeft(parts.pattern, " \t"))]
+	parts.pattern = indent + n.Entry.Pattern
+	parts.owners = n.Entry.Owners
+	if n.Entry.Comment != n.orig.Comment {
+		parts.comment = ""
+		if n.Entry.Comment != "" {
+			parts.comment = "# " + n.Entry.Comment
+		}
+	}
+	return parts.String()
+}
+
+func (n *Node) changed() bool {
+	return n.Entry.Pattern != n.orig.Pattern ||
+		n.Entry.Comment != n.orig.Comment ||
+		!equalStrings(n.Entry.Owners, n.orig.Owners)
+}
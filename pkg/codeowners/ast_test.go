@@ -0,0 +1,105 @@
+package codeowners_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestParseASTRoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		givenInput string
+	}{
+		"Empty file":                  {givenInput: ""},
+		"No trailing newline":         {givenInput: "* @a\n/docs/ @b"},
+		"CRLF and mixed line endings": {givenInput: "* @a\r\n\r\n/docs/ @b\n# c\r\n"},
+		"Whitespace-only lines":       {givenInput: "* @a\n \t \n\n   \n/docs/\t\t@b   \n"},
+		"Indentation and comments":    {givenInput: "  # header\n\t*   @a  @b   #  inline  \n#\n"},
+		"Sections":                    {givenInput: "[Docs][2] @docs # c\n/docs/\n\n^[Optional section]\n/opt/ @o\n"},
+		"Malformed content":           {givenInput: "\xef\xbb\xbf* @bom\n\x00 @null\n\xff\xfe @invalid"},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			ast, err := codeowners.ParseAST(strings.NewReader(tc.givenInput))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.givenInput, string(ast.Bytes()))
+			assert.Equal(t, parseWithoutSections(tc.givenInput, ast), ast.Entries())
+		})
+	}
+}
+
+func TestParseASTNodes(t *testing.T) {
+	// given
+	content := "# Owners\n\n[Docs][2] @docs-team # required\n# API\n/docs/api/  @api\r\n^[Tools]\n/tools/"
+
+	// when
+	ast, err := codeowners.ParseAST(strings.NewReader(content))
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, ast.Nodes, 7)
+
+	var kinds []string
+	for _, n := range ast.Nodes {
+		kinds = append(kinds, n.Kind.String())
+	}
+	assert.Equal(t, []string{"comment", "blank", "section", "comment", "entry", "section", "entry"}, kinds)
+
+	docs := ast.Nodes[2]
+	assert.Equal(t, &codeowners.Section{Name: "Docs", LineNo: 3, Approvals: 2, Owners: []string{"@docs-team"}, Comment: "required"}, docs.Section)
+	assert.Equal(t, int64(10), docs.Offset)
+
+	api := ast.Nodes[4]
+	assert.Equal(t, "\r\n", api.EOL)
+	assert.Equal(t, uint64(5), api.LineNo)
+	assert.Same(t, docs.Section, api.Section)
+	assert.Equal(t, []string{"# API"}, api.Entry.LeadingComments)
+	assert.Equal(t, content[api.Offset:api.Offset+int64(len(api.Text))], api.Text)
+
+	tools := ast.Nodes[6]
+	assert.Equal(t, &codeowners.Section{Name: "Tools", LineNo: 6, Optional: true}, tools.Section)
+	assert.Empty(t, tools.EOL)
+	assert.Empty(t, tools.Entry.Owners)
+}
+
+func TestASTEdit(t *testing.T) {
+	// given
+	content := "# header\n  /docs/    @a   @b   # keep me\r\n/src/\t@c\n/old/ @d # drop me\n"
+	ast, err := codeowners.ParseAST(strings.NewReader(content))
+	require.NoError(t, err)
+
+	// when
+	ast.Nodes[1].Entry.Owners = append(ast.Nodes[1].Entry.Owners, "@e")
+	ast.Nodes[2].Entry.Pattern = "/src/**"
+	ast.Nodes[3].Entry.Comment = ""
+	ast.Nodes = append(ast.Nodes, &codeowners.Node{
+		Kind:  codeowners.EntryNode,
+		EOL:   "\n",
+		Entry: &codeowners.Entry{Pattern: "/new/", Owners: []string{"@f"}, Comment: "added"},
+	})
+
+	// then
+	assert.Equal(t, "# header\n  /docs/    @a   @b   @e   # keep me\r\n/src/**\t@c\n/old/ @d\n/new/ @f # added\n", string(ast.Bytes()))
+}
+
+// parseWithoutSections returns entries parsed by ParseCodeowners, without section headers, which it reads as entries.
+func parseWithoutSections(content string, ast *codeowners.AST) []codeowners.Entry {
+	sections := map[uint64]bool{}
+	for _, n := range ast.Nodes {
+		sections[n.LineNo] = n.Kind == codeowners.SectionNode
+	}
+
+	var out []codeowners.Entry
+	for _, e := range codeowners.ParseCodeowners(strings.NewReader(content)) {
+		if !sections[e.LineNo] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
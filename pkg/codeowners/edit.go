@@ -0,0 +1,138 @@
+package codeowners
+
+import "strings"
+
+// EditOwners replaces owners of the entry in a given line with the result of the edit function.
+// Returns false if there is no entry in a given line or owners were not changed.
+func (a *AST) EditOwners(lineNo uint64, edit func(owners []string) []string) bool {
+	n := a.entryNode(lineNo)
+	if n == nil {
+		return false
+	}
+
+	owners := edit(append([]string(nil), n.Entry.Owners...))
+	if equalStrings(owners, n.Entry.Owners) {
+		return false
+	}
+
+	n.Entry.Owners = owners
+	return true
+}
+
+// EditPattern replaces the pattern of the entry in a given line with the result of the edit function.
+// Indentation, owners, and the inline comment are preserved. Returns false if there is no entry in a given line
+// or the pattern was not changed.
+func (a *AST) EditPattern(lineNo uint64, edit func(pattern string) string) bool {
+	n := a.entryNode(lineNo)
+	if n == nil {
+		return false
+	}
+
+	edited := edit(n.Entry.Pattern)
+	if edited == n.Entry.Pattern || edited == "" {
+		return false
+	}
+
+	n.Entry.Pattern = edited
+	return true
+}
+
+// entryNode returns the entry node of a given line, or nil if the line is not an entry.
+func (a *AST) entryNode(lineNo uint64) *Node {
+	for _, n := range a.Nodes {
+		if n.LineNo == lineNo && n.Kind == EntryNode && n.Entry != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+// entryParts holds parts of the entry line together with whitespaces that separate them.
+type entryParts struct {
+	// pattern with leading whitespaces
+	pattern string
+	// gap between the pattern and the first owner
+	gap    string
+	owners []string
+	// sep is a separator used between owners
+	sep string
+	// commentGap is a whitespace before the inline comment
+	commentGap string
+	comment    string
+}
+
+// String joins parts back into the entry line.
+func (p entryParts) String() string {
+	var out strings.Builder
+	out.WriteString(p.pattern)
+	if len(p.owners) > 0 {
+		out.WriteString(p.gap)
+		out.WriteString(strings.Join(p.owners, p.sep))
+	}
+	if p.comment != "" {
+		out.WriteString(p.commentGap)
+		out.WriteString(p.comment)
+	}
+	return out.String()
+}
+
+func splitEntryLine(line string) (entryParts, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return entryParts{}, false
+	}
+
+	out := entryParts{gap: " ", sep: " ", commentGap: " "}
+
+	end := len(line) - len(trimmed)
+	for end < len(line) && !isSpace(line[end]) {
+		end++
+	}
+	out.pattern = line[:end]
+
+	for idx := end; idx < len(line); {
+		start := idx
+		for idx < len(line) && isSpace(line[idx]) {
+			idx++
+		}
+		ws := line[start:idx]
+		if idx == len(line) {
+			// trailing whitespaces are dropped
+			break
+		}
+
+		if line[idx] == '#' {
+			out.commentGap = ws
+			out.comment = strings.TrimRight(line[idx:], " \t")
+			break
+		}
+
+		tokenEnd := idx
+		for tokenEnd < len(line) && !isSpace(line[tokenEnd]) {
+			tokenEnd++
+		}
+
+		switch len(out.owners) {
+		case 0:
+			out.gap = ws
+		case 1:
+			out.sep = ws
+		}
+		out.owners = append(out.owners, line[idx:tokenEnd])
+		idx = tokenEnd
+	}
+
+	return out, true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
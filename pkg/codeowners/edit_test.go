@@ -0,0 +1,159 @@
+package codeowners_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestASTEditOwners(t *testing.T) {
+	tests := map[string]struct {
+		givenInput  string
+		givenLineNo uint64
+		givenEdit   func([]string) []string
+		expChanged  bool
+		expOutput   string
+	}{
+		"Should preserve alignment and comment": {
+			givenInput:  "# header\n*      @a   @b   # keep me\n/docs/ @c\r\n",
+			givenLineNo: 2,
+			givenEdit: func(owners []string) []string {
+				return append(owners, "@d")
+			},
+			expChanged: true,
+			expOutput:  "# header\n*      @a   @b   @d   # keep me\n/docs/ @c\r\n",
+		},
+		"Should preserve CRLF line ending": {
+			givenInput:  "/docs/ @c\r\n",
+			givenLineNo: 1,
+			givenEdit: func([]string) []string {
+				return []string{"@new"}
+			},
+			expChanged: true,
+			expOutput:  "/docs/ @new\r\n",
+		},
+		"Should add owners to unowned entry": {
+			givenInput:  "/docs/\t# unowned\n",
+			givenLineNo: 1,
+			givenEdit: func([]string) []string {
+				return []string{"@docs"}
+			},
+			expChanged: true,
+			expOutput:  "/docs/ @docs\t# unowned\n",
+		},
+		"Should remove all owners": {
+			givenInput:  "/docs/  @a @b # comment\n",
+			givenLineNo: 1,
+			givenEdit: func([]string) []string {
+				return nil
+			},
+			expChanged: true,
+			expOutput:  "/docs/ # comment\n",
+		},
+		"Should ignore comment lines": {
+			givenInput:  "# /docs/ @a\n",
+			givenLineNo: 1,
+			givenEdit: func([]string) []string {
+				return []string{"@b"}
+			},
+			expChanged: false,
+			expOutput:  "# /docs/ @a\n",
+		},
+		"Should ignore section headers": {
+			givenInput:  "[Docs] @a\n/docs/\n",
+			givenLineNo: 1,
+			givenEdit: func([]string) []string {
+				return []string{"@b"}
+			},
+			expChanged: false,
+			expOutput:  "[Docs] @a\n/docs/\n",
+		},
+		"Should not change line if owners are the same": {
+			givenInput:  "/docs/    @a\n",
+			givenLineNo: 1,
+			givenEdit: func(owners []string) []string {
+				return owners
+			},
+			expChanged: false,
+			expOutput:  "/docs/    @a\n",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			ast, err := codeowners.ParseAST(strings.NewReader(tc.givenInput))
+			require.NoError(t, err)
+
+			// when
+			changed := ast.EditOwners(tc.givenLineNo, tc.givenEdit)
+
+			// then
+			assert.Equal(t, tc.expChanged, changed)
+			assert.Equal(t, tc.expOutput, string(ast.Bytes()))
+		})
+	}
+}
+
+func TestASTEditPattern(t *testing.T) {
+	tests := map[string]struct {
+		givenInput  string
+		givenLineNo uint64
+		givenEdit   func(string) string
+		expChanged  bool
+		expOutput   string
+	}{
+		"Should preserve indentation, owners, and comment": {
+			givenInput:  "# header\n  /old/   @a @b  # keep me\r\n",
+			givenLineNo: 2,
+			givenEdit: func(string) string {
+				return "/new/dir/"
+			},
+			expChanged: true,
+			expOutput:  "# header\n  /new/dir/   @a @b  # keep me\r\n",
+		},
+		"Should edit unowned entry": {
+			givenInput:  "/old/\n",
+			givenLineNo: 1,
+			givenEdit: func(string) string {
+				return "/new/"
+			},
+			expChanged: true,
+			expOutput:  "/new/\n",
+		},
+		"Should ignore comment lines": {
+			givenInput:  "# /old/ @a\n",
+			givenLineNo: 1,
+			givenEdit: func(string) string {
+				return "/new/"
+			},
+			expChanged: false,
+			expOutput:  "# /old/ @a\n",
+		},
+		"Should not change line if pattern is empty": {
+			givenInput:  "/old/ @a\n",
+			givenLineNo: 1,
+			givenEdit: func(string) string {
+				return ""
+			},
+			expChanged: false,
+			expOutput:  "/old/ @a\n",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			ast, err := codeowners.ParseAST(strings.NewReader(tc.givenInput))
+			require.NoError(t, err)
+
+			// when
+			changed := ast.EditPattern(tc.givenLineNo, tc.givenEdit)
+
+			// then
+			assert.Equal(t, tc.expChanged, changed)
+			assert.Equal(t, tc.expOutput, string(ast.Bytes()))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package codeowners
+
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encodings detected by InspectEncoding.
+const (
+	UTF8    = "UTF-8"
+	UTF16LE = "UTF-16LE"
+	UTF16BE = "UTF-16BE"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// EncodingReport describes how the raw CODEOWNERS content differs from UTF-8 without a byte order mark
+// and with LF line endings, which is the only form read the same way by all tools.
+type EncodingReport struct {
+	// Encoding is UTF16LE or UTF16BE if the content starts with a UTF-16 byte order mark, and UTF8 otherwise.
+	Encoding string
+	// BOM is true if the content starts with a byte order mark.
+	BOM bool
+	// CRLFLines are numbers of lines which end with CRLF.
+	CRLFLines []uint64
+	// InvalidLines are numbers of lines which are not valid UTF-8. They are empty for UTF-16 content.
+	InvalidLines []uint64
+}
+
+// IsNormalized returns true if the content doesn't need to be normalized.
+func (r EncodingReport) IsNormalized() bool {
+	return r.Encoding == UTF8 && !r.BOM && len(r.CRLFLines) == 0 && len(r.InvalidLines) == 0
+}
+
+// InspectEncoding reports the encoding, byte order mark, line endings, and invalid UTF-8 lines of the content.
+// Line numbers refer to the content decoded to UTF-8, so they match line numbers of parsed entries.
+func InspectEncoding(raw []byte) EncodingReport {
+	out := EncodingReport{Encoding: UTF8}
+	switch {
+	case bytes.HasPrefix(raw, bomUTF8):
+		out.BOM = true
+		raw = raw[len(bomUTF8):]
+	case bytes.HasPrefix(raw, bomUTF16LE):
+		out.Encoding, out.BOM = UTF16LE, true
+		raw = decodeUTF16(raw[len(bomUTF16LE):], false)
+	case bytes.HasPrefix(raw, bomUTF16BE):
+		out.Encoding, out.BOM = UTF16BE, true
+		raw = decodeUTF16(raw[len(bomUTF16BE):], true)
+	}
+
+	for no, line := range splitLines(raw) {
+		if bytes.HasSuffix(line, []byte("\r\n")) {
+			out.CRLFLines = append(out.CRLFLines, uint64(no+1))
+		}
+		if !utf8.Valid(line) {
+			out.InvalidLines = append(out.InvalidLines, uint64(no+1))
+		}
+	}
+	return out
+}
+
+// NormalizeEncoding returns the content encoded as UTF-8 without a byte order mark and with LF line endings.
+// UTF-16 content is recognized by its byte order mark. Lines which are not valid UTF-8 are decoded
+// as ISO-8859-1, the most common single-byte encoding of files written by legacy editors.
+func NormalizeEncoding(raw []byte) []byte {
+	switch {
+	case bytes.HasPrefix(raw, bomUTF8):
+		raw = raw[len(bomUTF8):]
+	case bytes.HasPrefix(raw, bomUTF16LE):
+		raw = decodeUTF16(raw[len(bomUTF16LE):], false)
+	case bytes.HasPrefix(raw, bomUTF16BE):
+		raw = decodeUTF16(raw[len(bomUTF16BE):], true)
+	}
+
+	out := make([]byte, 0, len(raw))
+	for _, line := range splitLines(raw) {
+		if bytes.HasSuffix(line, []byte("\r\n")) {
+			line = append(line[:len(line)-2:len(line)-2], '\n')
+		}
+		if !utf8.Valid(line) {
+			line = decodeLatin1(line)
+		}
+		out = append(out, line...)
+	}
+	return out
+}
+
+// splitLines splits the content after each LF, keeping line endings.
+func splitLines(raw []byte) [][]byte {
+	return bytes.SplitAfter(raw, []byte("\n"))
+}
+
+func decodeUTF16(raw []byte, bigEndian bool) []byte {
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		} else {
+			units = append(units, uint16(raw[i+1])<<8|uint16(raw[i]))
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+func decodeLatin1(raw []byte) []byte {
+	runes := make([]rune, len(raw))
+	for i, b := range raw {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
@@ -0,0 +1,56 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestEncoding(t *testing.T) {
+	tests := map[string]struct {
+		givenInput []byte
+		expReport  codeowners.EncodingReport
+		expOutput  string
+	}{
+		"Should keep normalized content unchanged": {
+			givenInput: []byte("* @global-owner1\n/docs/ @docs # żółw\n"),
+			expReport:  codeowners.EncodingReport{Encoding: codeowners.UTF8},
+			expOutput:  "* @global-owner1\n/docs/ @docs # żółw\n",
+		},
+		"Should remove UTF-8 byte order mark and CRLF line endings": {
+			givenInput: []byte("\xEF\xBB\xBF* @global-owner1\r\n/docs/ @docs\n/src/ @src\r\n"),
+			expReport:  codeowners.EncodingReport{Encoding: codeowners.UTF8, BOM: true, CRLFLines: []uint64{1, 3}},
+			expOutput:  "* @global-owner1\n/docs/ @docs\n/src/ @src\n",
+		},
+		"Should decode UTF-16 little endian content": {
+			givenInput: []byte("\xFF\xFE*\x00 \x00@\x00a\x00\r\x00\n\x00"),
+			expReport:  codeowners.EncodingReport{Encoding: codeowners.UTF16LE, BOM: true, CRLFLines: []uint64{1}},
+			expOutput:  "* @a\n",
+		},
+		"Should decode UTF-16 big endian content": {
+			givenInput: []byte("\xFE\xFF\x00*\x00 \x00@\x00a\x00\n"),
+			expReport:  codeowners.EncodingReport{Encoding: codeowners.UTF16BE, BOM: true},
+			expOutput:  "* @a\n",
+		},
+		"Should decode invalid UTF-8 lines as ISO-8859-1": {
+			givenInput: []byte("* @a\n/caf\xE9/ @b\n"),
+			expReport:  codeowners.EncodingReport{Encoding: codeowners.UTF8, InvalidLines: []uint64{2}},
+			expOutput:  "* @a\n/café/ @b\n",
+		},
+	}
+
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			report := codeowners.InspectEncoding(tc.givenInput)
+			normalized := codeowners.NormalizeEncoding(tc.givenInput)
+
+			// then
+			assert.Equal(t, tc.expReport, report)
+			assert.Equal(t, report.IsNormalized(), tc.expOutput == string(tc.givenInput))
+			assert.Equal(t, tc.expOutput, string(normalized))
+			assert.True(t, codeowners.InspectEncoding(normalized).IsNormalized())
+		})
+	}
+}
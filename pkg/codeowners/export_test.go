@@ -2,6 +2,16 @@ package codeowners
 
 import "github.com/spf13/afero"
 
+// SetPathSeparator sets the OS-specific path separator, e.g. to test Windows paths on all OSes.
+func SetPathSeparator(sep rune) func() {
+	old := pathSeparator
+	pathSeparator = sep
+
+	return func() {
+		pathSeparator = old
+	}
+}
+
 func SetFS(newFs afero.Fs) func() {
 	oldFS := fs
 	fs = newFs
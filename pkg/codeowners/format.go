@@ -0,0 +1,166 @@
+package codeowners
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// FormatOptions customizes the canonical CODEOWNERS style.
+type FormatOptions struct {
+	// SortEntries sorts entries by pattern within each block of entries delimited by comments or blank lines.
+	// CAUTION: sorting changes the precedence of patterns, as the last matching pattern takes the most precedence.
+	SortEntries bool
+}
+
+type lineKind int
+
+const (
+	blankLine lineKind = iota
+	commentLine
+	entryLine
+)
+
+type formatLine struct {
+	kind    lineKind
+	text    string
+	pattern string
+	owners  []string
+	comment string
+}
+
+// Format rewrites the CODEOWNERS content into the canonical style:
+//   - owners are aligned in a single column within each section delimited by blank lines,
+//   - pattern, owners, and inline comment are separated by single spaces,
+//   - leading and trailing whitespaces are removed and consecutive blank lines are collapsed,
+//   - comments are preserved.
+func Format(r io.Reader, opts FormatOptions) ([]byte, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []formatLine
+	for _, l := range strings.Split(string(raw), "\n") {
+		lines = append(lines, parseFormatLine(strings.TrimSpace(l)))
+	}
+
+	var out strings.Builder
+	for _, section := range splitSections(lines) {
+		if opts.SortEntries {
+			sortEntries(section)
+		}
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		writeSection(&out, section)
+	}
+
+	return []byte(out.String()), nil
+}
+
+func parseFormatLine(line string) formatLine {
+	switch {
+	case line == "":
+		return formatLine{kind: blankLine}
+	case strings.HasPrefix(line, "#"):
+		return formatLine{kind: commentLine, text: line}
+	}
+
+	out := formatLine{kind: entryLine}
+	for idx := 0; idx < len(line); {
+		if isSpace(line[idx]) {
+			idx++
+			continue
+		}
+
+		end := idx
+		for end < len(line) && !isSpace(line[end]) {
+			end++
+		}
+
+		token := line[idx:end]
+		switch {
+		case out.pattern == "":
+			out.pattern = token
+		case strings.HasPrefix(token, "#"):
+			out.comment = line[idx:]
+			return out
+		default:
+			out.owners = append(out.owners, token)
+		}
+		idx = end
+	}
+
+	return out
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// splitSections groups lines into sections delimited by blank lines.
+func splitSections(lines []formatLine) [][]formatLine {
+	var (
+		sections [][]formatLine
+		current  []formatLine
+	)
+	for _, l := range lines {
+		if l.kind != blankLine {
+			current = append(current, l)
+			continue
+		}
+		if len(current) > 0 {
+			sections = append(sections, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+
+	return sections
+}
+
+// sortEntries sorts entries within each block of consecutive entry lines.
+func sortEntries(section []formatLine) {
+	start := 0
+	for idx := 0; idx <= len(section); idx++ {
+		if idx < len(section) && section[idx].kind == entryLine {
+			continue
+		}
+		block := section[start:idx]
+		sort.SliceStable(block, func(i, j int) bool {
+			return block[i].pattern < block[j].pattern
+		})
+		start = idx + 1
+	}
+}
+
+func writeSection(out *strings.Builder, section []formatLine) {
+	width := 0
+	for _, l := range section {
+		if l.kind == entryLine && (len(l.owners) > 0 || l.comment != "") && len(l.pattern) > width {
+			width = len(l.pattern)
+		}
+	}
+
+	for _, l := range section {
+		if l.kind == commentLine {
+			out.WriteString(l.text)
+			out.WriteString("\n")
+			continue
+		}
+
+		out.WriteString(l.pattern)
+		rest := l.owners
+		if l.comment != "" {
+			rest = append(rest[:len(rest):len(rest)], l.comment)
+		}
+		if len(rest) > 0 {
+			out.WriteString(strings.Repeat(" ", width-len(l.pattern)+1))
+			out.WriteString(strings.Join(rest, " "))
+		}
+		out.WriteString("\n")
+	}
+}
@@ -0,0 +1,95 @@
+package codeowners_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestFormat(t *testing.T) {
+	tests := map[string]struct {
+		givenInput string
+		givenOpts  codeowners.FormatOptions
+		expOutput  string
+	}{
+		"Should align owners and normalize spacing": {
+			givenInput: "\n\n  # Default owners\n*\t\t@global-owner1   @global-owner2   \n*.js @js-owner # inline   comment\n\n\n\n/build/logs/ @doctocat\r\n/docs\n",
+			expOutput: `# Default owners
+*    @global-owner1 @global-owner2
+*.js @js-owner # inline   comment
+
+/build/logs/ @doctocat
+/docs
+`,
+		},
+		"Should keep formatted file unchanged": {
+			givenInput: `# Default owners
+*    @global-owner1 @global-owner2
+*.js @js-owner
+`,
+			expOutput: `# Default owners
+*    @global-owner1 @global-owner2
+*.js @js-owner
+`,
+		},
+		"Should sort entries within comment-delimited blocks": {
+			givenInput: `
+/z/ @z
+/a/ @a
+# Frontend
+*.ts @ts
+*.js @js
+
+/y/ @y
+/b/ @b
+`,
+			givenOpts: codeowners.FormatOptions{SortEntries: true},
+			expOutput: `/a/  @a
+/z/  @z
+# Frontend
+*.js @js
+*.ts @ts
+
+/b/ @b
+/y/ @y
+`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			out, err := codeowners.Format(strings.NewReader(tc.givenInput), tc.givenOpts)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expOutput, string(out))
+		})
+	}
+}
+
+func TestFormatPreservesEntries(t *testing.T) {
+	// given
+	in := "*  @a   @b # comment\n/docs/\t@c\n"
+
+	// when
+	out, err := codeowners.Format(strings.NewReader(in), codeowners.FormatOptions{})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t,
+		withoutLayout(codeowners.ParseCodeowners(strings.NewReader(in))),
+		withoutLayout(codeowners.ParseCodeowners(strings.NewReader(string(out)))),
+	)
+}
+
+// withoutLayout drops the raw text and offsets of entries, which are changed by formatting.
+func withoutLayout(entries []codeowners.Entry) []codeowners.Entry {
+	for idx := range entries {
+		entries[idx].Raw = ""
+		entries[idx].Offset = 0
+	}
+	return entries
+}
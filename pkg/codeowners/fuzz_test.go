@@ -0,0 +1,115 @@
+package codeowners_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+var fuzzSeeds = []string{
+	"",
+	"*\t@everyone\n",
+	"# comment\n/docs/ @org/docs # approved-by: @org/leads\n",
+	"src/** @a @b\r\n*.go @gophers\r\n",
+	"/a/**/b/*.txt @x\n",
+	"\x00 @null\n\xff\xfe @invalid\n",
+	"🦄/**  @unicorn  #  🌈\n",
+	"[a-z]?/!x\\y @odd\n",
+	strings.Repeat("a/", 10000) + " @deep\n",
+	strings.Repeat("x", 100000) + " @long\n* @after-long\n",
+}
+
+func FuzzParseCodeowners(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		entries := codeowners.ParseCodeowners(strings.NewReader(content))
+
+		lines := uint64(strings.Count(content, "\n") + 1)
+		for _, e := range entries {
+			if e.LineNo == 0 || e.LineNo > lines {
+				t.Fatalf("line number %d out of range [1, %d]", e.LineNo, lines)
+			}
+			if e.Pattern == "" || strings.HasPrefix(e.Pattern, "#") {
+				t.Fatalf("unexpected pattern %q", e.Pattern)
+			}
+			e.Annotation("approved-by")
+		}
+
+		// compilation errors are fine, e.g. too large patterns, panics are not
+		_, _ = codeowners.NewMatcher(entries)
+	})
+}
+
+func FuzzPatternMatch(f *testing.F) {
+	f.Add("*", "main.go")
+	f.Add("/docs/", "docs/README.md")
+	f.Add("a/**/b", "a/x/y/b")
+	f.Add("**/*.go", "./cmd/main.go")
+	f.Add("\xff*", "\xffa")
+	f.Add("?", "🦄")
+
+	f.Fuzz(func(t *testing.T, pattern, path string) {
+		p, err := codeowners.NewPattern(pattern)
+		if err != nil {
+			return
+		}
+		p.Match(path)
+
+		fold, err := codeowners.NewPattern(pattern, codeowners.WithIgnoreCase())
+		if err != nil {
+			t.Fatalf("pattern %q compiles only case-sensitively: %v", pattern, err)
+		}
+		if p.Match(path) && !fold.Match(path) {
+			t.Fatalf("pattern %q matches %q only case-sensitively", pattern, path)
+		}
+	})
+}
+
+func FuzzFormat(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		out, err := codeowners.Format(strings.NewReader(content), codeowners.FormatOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		again, err := codeowners.Format(bytes.NewReader(out), codeowners.FormatOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out, again) {
+			t.Fatalf("formatting is not idempotent:\n%q\n%q", out, again)
+		}
+
+		if utf8.ValidString(content) && !utf8.Valid(out) {
+			t.Fatalf("formatting produced invalid UTF-8 from valid input")
+		}
+	})
+}
+
+func FuzzParseASTRoundTrip(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Add("[Docs][2] @docs\n^[Optional] @x # c\n/docs/ @d\n")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		ast, err := codeowners.ParseAST(strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := ast.Bytes(); !bytes.Equal(got, []byte(content)) {
+			t.Fatalf("round trip mismatch: got %q, want %q", got, content)
+		}
+	})
+}
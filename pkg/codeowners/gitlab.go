@@ -0,0 +1,67 @@
+package codeowners
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// ParseCodeownersAs returns entries from the CODEOWNERS content written in the dialect of a given provider.
+//
+// In the GitLab dialect, section headers, such as `[Backend]`, `^[Optional]`, or `[Docs][2] @docs-team`, are not
+// entries. Entries placed in a section have the Section set, and entries which don't list their own owners get
+// the default owners of the section. Other providers use the GitHub dialect, see ParseCodeowners.
+func ParseCodeownersAs(r io.Reader, p Provider) ([]Entry, error) {
+	if p != GitLab {
+		return ParseCodeowners(r), nil
+	}
+
+	ast, err := ParseAST(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Entry
+	for _, n := range ast.Nodes {
+		if n.Kind != EntryNode {
+			continue
+		}
+		entry := *n.Entry
+		entry.Section = n.Section
+		if len(entry.Owners) == 0 && n.Section != nil && len(n.Section.Owners) > 0 {
+			entry.Owners = append([]string(nil), n.Section.Owners...)
+			entry.DefaultOwners = true
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+// DetectProvider returns GitLab if the CODEOWNERS content has GitLab section headers, and GitHub otherwise.
+func DetectProvider(content []byte) Provider {
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if sectionRegexp.Match(bytes.TrimSpace(line)) {
+			return GitLab
+		}
+	}
+	return GitHub
+}
+
+// OwnersLineNo returns the line which lists owners of the entry. Default owners of a GitLab section
+// are listed in the section header.
+func (e Entry) OwnersLineNo() uint64 {
+	if e.DefaultOwners && e.Section != nil {
+		return e.Section.LineNo
+	}
+	return e.LineNo
+}
+
+// SectionKey returns the key which identifies the section of a given entry. GitLab combines sections with
+// the same name, and names are case-insensitive, so sections `[Docs]` and `[docs]` have the same key.
+// Entries placed before the first section header have an empty key.
+func SectionKey(e Entry) string {
+	if e.Section == nil {
+		return ""
+	}
+	return strings.ToLower(e.Section.Name)
+}
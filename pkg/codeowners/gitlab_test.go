@@ -0,0 +1,112 @@
+package codeowners_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+const gitlabCodeowners = `*            @org/core
+
+[Backend][2] @org/backend
+/api/
+/api/auth/   @org/security
+
+^[Optional docs] @org/writers
+*.md
+
+[backend]
+/db/         @org/dba
+`
+
+func TestParseCodeownersAsGitLab(t *testing.T) {
+	// when
+	entries, err := codeowners.ParseCodeownersAs(strings.NewReader(gitlabCodeowners), codeowners.GitLab)
+
+	// then
+	require.NoError(t, err)
+
+	backend := &codeowners.Section{Name: "Backend", LineNo: 3, Approvals: 2, Owners: []string{"@org/backend"}}
+	docs := &codeowners.Section{Name: "Optional docs", LineNo: 7, Optional: true, Owners: []string{"@org/writers"}}
+	combined := &codeowners.Section{Name: "backend", LineNo: 10}
+
+	type entry struct {
+		LineNo        uint64
+		Pattern       string
+		Owners        []string
+		Section       *codeowners.Section
+		DefaultOwners bool
+	}
+	var got []entry
+	for _, e := range entries {
+		got = append(got, entry{e.LineNo, e.Pattern, e.Owners, e.Section, e.DefaultOwners})
+	}
+	assert.Equal(t, []entry{
+		{LineNo: 1, Pattern: "*", Owners: []string{"@org/core"}},
+		{LineNo: 4, Pattern: "/api/", Owners: []string{"@org/backend"}, Section: backend, DefaultOwners: true},
+		{LineNo: 5, Pattern: "/api/auth/", Owners: []string{"@org/security"}, Section: backend},
+		{LineNo: 8, Pattern: "*.md", Owners: []string{"@org/writers"}, Section: docs, DefaultOwners: true},
+		{LineNo: 11, Pattern: "/db/", Owners: []string{"@org/dba"}, Section: combined},
+	}, got)
+}
+
+func TestParseCodeownersAsGitHub(t *testing.T) {
+	// when
+	entries, err := codeowners.ParseCodeownersAs(strings.NewReader(gitlabCodeowners), codeowners.GitHub)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, codeowners.ParseCodeowners(strings.NewReader(gitlabCodeowners)), entries)
+}
+
+func TestDetectProvider(t *testing.T) {
+	tests := map[string]struct {
+		givenContent string
+		expProvider  codeowners.Provider
+	}{
+		"Section":          {givenContent: gitlabCodeowners, expProvider: codeowners.GitLab},
+		"Optional section": {givenContent: "  ^[Docs]\n*.md @writers\n", expProvider: codeowners.GitLab},
+		"No sections":      {givenContent: "* @org/core\n# [Backend]\n/api/ @org/backend\n", expProvider: codeowners.GitHub},
+		"Empty":            {givenContent: "", expProvider: codeowners.GitHub},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			p := codeowners.DetectProvider([]byte(tc.givenContent))
+
+			// then
+			assert.Equal(t, tc.expProvider, p)
+		})
+	}
+}
+
+func TestMatcherMatchAll(t *testing.T) {
+	// given
+	entries, err := codeowners.ParseCodeownersAs(strings.NewReader(gitlabCodeowners), codeowners.GitLab)
+	require.NoError(t, err)
+	matcher, err := codeowners.NewMatcher(entries)
+	require.NoError(t, err)
+
+	tests := map[string][]uint64{
+		"main.go":          {1},
+		"api/handler.go":   {1, 4},
+		"api/auth/auth.go": {1, 5},
+		"api/README.md":    {1, 4, 8},
+		// sections with the same name are combined, so the last match of both wins
+		"db/schema.sql": {1, 11},
+	}
+	for path, expLines := range tests {
+		// when
+		matched := matcher.MatchAll(path)
+
+		// then
+		var lines []uint64
+		for _, e := range matched {
+			lines = append(lines, e.LineNo)
+		}
+		assert.Equal(t, expLines, lines, path)
+	}
+}
@@ -0,0 +1,259 @@
+package codeowners
+
+import (
+	"fmt"
+	pathpkg "path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Pattern is a compiled CODEOWNERS file pattern.
+//
+// CODEOWNERS patterns follow most of the rules used in gitignore files, see:
+// https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners#codeowners-syntax
+//
+// The following gitignore syntax is not supported by GitHub and therefore is matched literally:
+//   - using `!` to negate a pattern,
+//   - using `[ ]` to define a character range,
+//   - escaping a pattern with `\`.
+type Pattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+type patternOptions struct {
+	ignoreCase bool
+}
+
+// PatternOption allows to customize how the pattern is compiled.
+type PatternOption func(*patternOptions)
+
+// WithIgnoreCase compiles pattern that matches paths case-insensitively.
+func WithIgnoreCase() PatternOption {
+	return func(o *patternOptions) {
+		o.ignoreCase = true
+	}
+}
+
+// NewPattern compiles a given CODEOWNERS pattern.
+func NewPattern(pattern string, opts ...PatternOption) (Pattern, error) {
+	var options patternOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	expr := patternToRegexp(pattern)
+	if options.ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return Pattern{}, err
+	}
+
+	return Pattern{raw: pattern, re: re}, nil
+}
+
+// String returns the raw pattern.
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// Match returns true if the given file path is matched by the pattern.
+// Path needs to be relative to the repository root, e.g. `docs/README.md`.
+func (p Pattern) Match(path string) bool {
+	if p.re == nil {
+		return false
+	}
+	return p.re.MatchString(NormalizePath(path))
+}
+
+// pathSeparator is the OS-specific path separator. It's a variable, so Windows paths can be tested on all OSes.
+var pathSeparator = filepath.Separator
+
+// NormalizePath converts path to the form expected by the matcher, e.g. `./docs/README.md`,
+// or `.\docs\README.md` on Windows, into `docs/README.md`.
+func NormalizePath(path string) string {
+	path = toSlash(path)
+	path = strings.TrimPrefix(path, "./")
+	return strings.TrimPrefix(path, "/")
+}
+
+// RelPath returns the path relative to the repository root in the form expected by the matcher,
+// e.g. `C:\repo\docs\README.md` with the `c:\repo` root into `docs/README.md`. Drive letters are
+// compared case-insensitively. Both paths must be either absolute, or relative to the same directory.
+func RelPath(root, path string) (string, error) {
+	root, path = toSlash(root), toSlash(path)
+
+	rootVol, pathVol := volumeName(root), volumeName(path)
+	if !strings.EqualFold(rootVol, pathVol) {
+		return "", fmt.Errorf("path %q is outside of the repository %q", path, root)
+	}
+
+	cleanRoot, cleanPath := pathpkg.Clean(root[len(rootVol):]), pathpkg.Clean(path[len(pathVol):])
+	if cleanRoot == cleanPath {
+		return "", nil
+	}
+	prefix := strings.TrimSuffix(cleanRoot, "/") + "/"
+	if cleanRoot == "." {
+		prefix = ""
+	}
+	if !strings.HasPrefix(cleanPath, prefix) || cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return "", fmt.Errorf("path %q is outside of the repository %q", path, root)
+	}
+
+	return strings.TrimPrefix(cleanPath, prefix), nil
+}
+
+func toSlash(path string) string {
+	if pathSeparator == '/' {
+		return path
+	}
+	return strings.ReplaceAll(path, string(pathSeparator), "/")
+}
+
+// volumeName returns the leading Windows volume name of a slash-separated path, e.g. `C:` or `//server/share`.
+// On other OSes, it's always empty.
+func volumeName(path string) string {
+	if pathSeparator != '\\' {
+		return ""
+	}
+	if len(path) >= 2 && path[1] == ':' && isLetter(path[0]) {
+		return path[:2]
+	}
+	if strings.HasPrefix(path, "//") {
+		// UNC path, e.g. //server/share/repo
+		parts := strings.SplitN(path[2:], "/", 3)
+		if len(parts) >= 2 {
+			return "//" + parts[0] + "/" + parts[1]
+		}
+	}
+	return ""
+}
+
+func isLetter(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func patternToRegexp(pattern string) string {
+	var re strings.Builder
+
+	// A pattern with a slash at the beginning or in the middle is relative to the repository root,
+	// otherwise it matches at any level.
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	re.WriteString(`\A`)
+	if !anchored {
+		re.WriteString(`(?:.*/)?`)
+	}
+
+	segments := strings.Split(pattern, "/")
+	for idx, segment := range segments {
+		last := idx == len(segments)-1
+		switch {
+		case segment == "**" && last:
+			// "abc/**" matches everything inside "abc"
+			re.WriteString(`.+`)
+		case segment == "**":
+			// "**/foo" and "a/**/b" match zero or more directories
+			re.WriteString(`(?:.+/)?`)
+		default:
+			writeSegment(&re, segment)
+			if !last {
+				re.WriteString(`/`)
+			}
+		}
+	}
+
+	switch {
+	case dirOnly:
+		re.WriteString(`/.+`)
+	case segments[len(segments)-1] == "*" && anchored, segments[len(segments)-1] == "**":
+		// "docs/*" and "/*" match only direct children of a given directory
+	default:
+		// a pattern matches both a file and the whole directory with such name
+		re.WriteString(`(?:/.+)?`)
+	}
+	re.WriteString(`\z`)
+
+	return re.String()
+}
+
+func writeSegment(re *strings.Builder, segment string) {
+	for _, r := range segment {
+		switch r {
+		case '*':
+			re.WriteString(`[^/]*`)
+		case '?':
+			re.WriteString(`[^/]`)
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+}
+
+// Matcher resolves the owning entry of a given path.
+// As in GitHub, the last matching pattern takes the most precedence.
+type Matcher struct {
+	entries  []Entry
+	patterns []Pattern
+}
+
+// NewMatcher compiles patterns of all given entries.
+func NewMatcher(entries []Entry) (*Matcher, error) {
+	patterns := make([]Pattern, 0, len(entries))
+	for _, e := range entries {
+		p, err := NewPattern(e.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+
+	return &Matcher{
+		entries:  entries,
+		patterns: patterns,
+	}, nil
+}
+
+// Match returns the last entry which pattern matches a given path.
+func (m *Matcher) Match(path string) (Entry, bool) {
+	path = NormalizePath(path)
+	for idx := len(m.patterns) - 1; idx >= 0; idx-- {
+		if m.patterns[idx].re.MatchString(path) {
+			return m.entries[idx], true
+		}
+	}
+	return Entry{}, false
+}
+
+// MatchAll returns the last matching entry of each section, ordered by the first matching entry of the section.
+// As in GitLab, a file is owned by owners of all sections which match it, and within a section the last
+// matching pattern takes the most precedence. Entries without sections, e.g. parsed in the GitHub dialect,
+// form a single section, so at most one entry is returned for them, the same as by Match.
+func (m *Matcher) MatchAll(path string) []Entry {
+	path = NormalizePath(path)
+
+	var (
+		out   []Entry
+		index = map[string]int{}
+	)
+	for idx := range m.patterns {
+		if !m.patterns[idx].re.MatchString(path) {
+			continue
+		}
+		key := SectionKey(m.entries[idx])
+		if pos, seen := index[key]; seen {
+			out[pos] = m.entries[idx]
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, m.entries[idx])
+	}
+	return out
+}
@@ -0,0 +1,250 @@
+package codeowners_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+func TestPatternMatch(t *testing.T) {
+	tests := map[string]struct {
+		pattern    string
+		matches    []string
+		notMatches []string
+	}{
+		"Should match everything": {
+			pattern: "*",
+			matches: []string{"README.md", "docs/README.md", "a/b/c/d.go"},
+		},
+		"Should match extension at any level": {
+			pattern:    "*.js",
+			matches:    []string{"index.js", "web/app/index.js"},
+			notMatches: []string{"index.jsx", "js/index.ts"},
+		},
+		"Should match directory anywhere": {
+			pattern:    "apps/",
+			matches:    []string{"apps/main.go", "src/apps/a/b.go"},
+			notMatches: []string{"apps", "myapps/main.go"},
+		},
+		"Should match directory relative to root": {
+			pattern:    "/build/logs/",
+			matches:    []string{"build/logs/out.log", "build/logs/a/out.log"},
+			notMatches: []string{"src/build/logs/out.log", "build/logs"},
+		},
+		"Should match direct children only": {
+			pattern:    "docs/*",
+			matches:    []string{"docs/getting-started.md"},
+			notMatches: []string{"docs/build-app/troubleshooting.md", "src/docs/a.md"},
+		},
+		"Should match root files only": {
+			pattern:    "/*",
+			matches:    []string{"README.md", "go.mod"},
+			notMatches: []string{"docs/README.md"},
+		},
+		"Should match file or directory without trailing slash": {
+			pattern:    "/script",
+			matches:    []string{"script", "script/run.sh"},
+			notMatches: []string{"scripts/run.sh", "src/script"},
+		},
+		"Should match name at any level": {
+			pattern:    "**/foo",
+			matches:    []string{"foo", "a/foo", "a/b/foo/bar.js"},
+			notMatches: []string{"a/foobar"},
+		},
+		"Should match everything inside directory": {
+			pattern:    "abc/**",
+			matches:    []string{"abc/a.js", "abc/a/b/c.js"},
+			notMatches: []string{"abc", "x/abc/a.js"},
+		},
+		"Should match zero or more directories": {
+			pattern:    "a/**/b",
+			matches:    []string{"a/b", "a/x/b", "a/x/y/b/c.js"},
+			notMatches: []string{"a/xb", "x/a/b"},
+		},
+		"Should match single character": {
+			pattern:    "/v?.txt",
+			matches:    []string{"v1.txt"},
+			notMatches: []string{"v10.txt", "v/.txt"},
+		},
+		"Should not support negation": {
+			pattern:    "!/codeowners",
+			notMatches: []string{"codeowners", "a/codeowners"},
+		},
+		"Should handle leading slash and dot in paths": {
+			pattern: "/docs/",
+			matches: []string{"/docs/README.md", "./docs/README.md"},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			p, err := codeowners.NewPattern(tc.pattern)
+			require.NoError(t, err)
+
+			// then
+			for _, path := range tc.matches {
+				assert.Truef(t, p.Match(path), "expected %q to match %q", tc.pattern, path)
+			}
+			for _, path := range tc.notMatches {
+				assert.Falsef(t, p.Match(path), "expected %q to not match %q", tc.pattern, path)
+			}
+		})
+	}
+}
+
+func TestPatternMatchIgnoreCase(t *testing.T) {
+	// given
+	p, err := codeowners.NewPattern("/Docs/", codeowners.WithIgnoreCase())
+	require.NoError(t, err)
+
+	// then
+	assert.True(t, p.Match("docs/README.md"))
+}
+
+func TestMatcherLastMatchWins(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader(`
+*          @global-owner
+*.js       @js-owner
+/docs/     @docs-owner
+`))
+	matcher, err := codeowners.NewMatcher(entries)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"main.go":         "*",
+		"web/index.js":    "*.js",
+		"docs/index.js":   "/docs/",
+		"docs/README.md":  "/docs/",
+		"/docs/README.md": "/docs/",
+	}
+	for path, expPattern := range tests {
+		// when
+		entry, found := matcher.Match(path)
+
+		// then
+		require.True(t, found)
+		assert.Equal(t, expPattern, entry.Pattern, path)
+	}
+}
+
+func TestPathSeparators(t *testing.T) {
+	// given
+	entries := codeowners.ParseCodeowners(strings.NewReader(`
+*          @global-owner
+/docs/     @docs-owner
+`))
+	matcher, err := codeowners.NewMatcher(entries)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		separator  rune
+		path       string
+		expPattern string
+	}{
+		"Unix path": {
+			separator:  '/',
+			path:       "./docs/README.md",
+			expPattern: "/docs/",
+		},
+		"Backslash is a valid file name character on Unix": {
+			separator:  '/',
+			path:       `docs\README.md`,
+			expPattern: "*",
+		},
+		"Windows path": {
+			separator:  '\\',
+			path:       `.\docs\README.md`,
+			expPattern: "/docs/",
+		},
+		"Windows path with mixed separators": {
+			separator:  '\\',
+			path:       `docs\guides/README.md`,
+			expPattern: "/docs/",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			defer codeowners.SetPathSeparator(tc.separator)()
+
+			// when
+			entry, found := matcher.Match(tc.path)
+
+			// then
+			require.True(t, found)
+			assert.Equal(t, tc.expPattern, entry.Pattern)
+		})
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	tests := map[string]struct {
+		separator rune
+		root      string
+		path      string
+		exp       string
+		expErr    string
+	}{
+		"Unix absolute path": {
+			separator: '/',
+			root:      "/home/repo",
+			path:      "/home/repo/docs/README.md",
+			exp:       "docs/README.md",
+		},
+		"Unix relative path": {
+			separator: '/',
+			root:      ".",
+			path:      "./docs/../src/main.go",
+			exp:       "src/main.go",
+		},
+		"Unix path outside repository": {
+			separator: '/',
+			root:      "/home/repo",
+			path:      "/home/repository/main.go",
+			expErr:    `path "/home/repository/main.go" is outside of the repository "/home/repo"`,
+		},
+		"Windows path with different drive letter case": {
+			separator: '\\',
+			root:      `C:\Users\dev\repo\`,
+			path:      `c:\Users\dev\repo\docs\README.md`,
+			exp:       "docs/README.md",
+		},
+		"Windows UNC path": {
+			separator: '\\',
+			root:      `\\server\share\repo`,
+			path:      `\\server\share\repo\src\main.go`,
+			exp:       "src/main.go",
+		},
+		"Windows path on different drive": {
+			separator: '\\',
+			root:      `C:\repo`,
+			path:      `D:\repo\main.go`,
+			expErr:    `path "D:/repo/main.go" is outside of the repository "C:/repo"`,
+		},
+		"Windows relative path outside repository": {
+			separator: '\\',
+			root:      `.`,
+			path:      `..\other\main.go`,
+			expErr:    `path "../other/main.go" is outside of the repository "."`,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			defer codeowners.SetPathSeparator(tc.separator)()
+
+			// when
+			got, err := codeowners.RelPath(tc.root, tc.path)
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, got)
+		})
+	}
+}
@@ -20,6 +20,20 @@ type Entry struct {
 	LineNo  uint64
 	Pattern string
 	Owners  []string
+	// Comment holds the inline comment placed after the owners, without the leading '#'.
+	Comment string
+	// LeadingComments holds the comment lines placed directly above the entry, without blank lines in between,
+	// as they appear in the file, including the leading '#'.
+	LeadingComments []string
+	// Raw is the entry line as it appears in the file, without the line terminator.
+	Raw string
+	// Offset is the byte offset of the entry line in the file. The line spans the [Offset, Offset+len(Raw)) range.
+	Offset int64
+	// Section is the GitLab section of the entry. It's nil in the GitHub flavor, and for entries placed
+	// before the first section header.
+	Section *Section
+	// DefaultOwners is true if Owners are the default owners of the section, as the entry doesn't list its own.
+	DefaultOwners bool
 }
 
 func (e Entry) String() string {
@@ -37,14 +51,23 @@ func NewFromPath(repoPath string) ([]Entry, error) {
 }
 
 // openCodeownersFile finds a CODEOWNERS file and returns content.
-// see: https://help.github.com/articles/about-code-owners/#codeowners-file-location
 func openCodeownersFile(dir string) (io.Reader, error) {
+	f, err := FindCodeownersFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Open(f)
+}
+
+// FindCodeownersFile returns the path to the CODEOWNERS file located in the given repository.
+// see: https://help.github.com/articles/about-code-owners/#codeowners-file-location
+func FindCodeownersFile(dir string) (string, error) {
 	var detectedFiles []string
 	for _, p := range []string{".", "docs", ".github"} {
 		pth := path.Join(dir, p)
 		exists, err := afero.DirExists(fs, pth)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		if !exists {
@@ -58,7 +81,7 @@ func openCodeownersFile(dir string) (io.Reader, error) {
 		case os.IsNotExist(err):
 			continue
 		default:
-			return nil, err
+			return "", err
 		}
 
 		detectedFiles = append(detectedFiles, f)
@@ -66,11 +89,11 @@ func openCodeownersFile(dir string) (io.Reader, error) {
 
 	switch l := len(detectedFiles); l {
 	case 0:
-		return nil, fmt.Errorf("No CODEOWNERS found in the root, docs/, or .github/ directory of the repository %s", dir)
+		return "", fmt.Errorf("No CODEOWNERS found in the root, docs/, or .github/ directory of the repository %s", dir)
 	case 1:
-		return fs.Open(detectedFiles[0])
+		return detectedFiles[0], nil
 	default:
-		return nil, fmt.Errorf("Multiple CODEOWNERS files found in the %s locations of the repository %s",
+		return "", fmt.Errorf("Multiple CODEOWNERS files found in the %s locations of the repository %s",
 			english.OxfordWordSeries(replacePrefix(detectedFiles, dir, "./"), "and"),
 			dir)
 	}
@@ -83,36 +106,68 @@ func replacePrefix(in []string, prefix, s string) []string {
 	return in
 }
 
+// ParseCodeowners returns entries from the CODEOWNERS content. Lines of any length are supported,
+// and malformed content, such as invalid UTF-8 or null bytes, never causes a panic.
 func ParseCodeowners(r io.Reader) []Entry {
-	var e []Entry
-	s := bufio.NewScanner(r)
+	var (
+		e        []Entry
+		comments []string
+		offset   int64
+	)
+	br := bufio.NewReader(r)
 	no := uint64(0)
-	for s.Scan() {
+	for {
+		// unlike bufio.Scanner, reading whole lines doesn't stop on lines longer than the buffer size
+		line, err := br.ReadString('\n')
+		if line == "" && err != nil {
+			break
+		}
 		no++
-		fields := strings.Fields(s.Text())
+		start := offset
+		offset += int64(len(line))
+		raw := strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		fields := strings.Fields(line)
 
 		if len(fields) == 0 { // empty
+			comments = nil
 			continue
 		}
 
 		if strings.HasPrefix(fields[0], "#") { // comment
+			comments = append(comments, raw)
 			continue
 		}
 
-		n := len(fields)
-		for idx, x := range fields {
-			if !strings.HasPrefix(x, "#") {
-				continue
-			}
+		entry := newEntry(fields)
+		entry.LineNo = no
+		entry.LeadingComments = comments
+		entry.Raw = raw
+		entry.Offset = start
+		e = append(e, entry)
+		comments = nil
+	}
+
+	return e
+}
+
+// newEntry returns the entry built from non-empty fields of the entry line.
+func newEntry(fields []string) Entry {
+	n := len(fields)
+	for idx, x := range fields {
+		if strings.HasPrefix(x, "#") {
 			n = idx
+			break
 		}
+	}
 
-		e = append(e, Entry{
-			Pattern: fields[0],
-			Owners:  fields[1:n],
-			LineNo:  no,
-		})
+	var comment string
+	if n < len(fields) {
+		comment = strings.TrimSpace(strings.TrimPrefix(strings.Join(fields[n:], " "), "#"))
 	}
 
-	return e
+	return Entry{
+		Pattern: fields[0],
+		Owners:  fields[1:n],
+		Comment: comment,
+	}
 }
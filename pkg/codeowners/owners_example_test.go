@@ -2,6 +2,7 @@ package codeowners_test
 
 import (
 	"fmt"
+	"strings"
 
 	"go.szostok.io/codeowners/pkg/codeowners"
 )
@@ -27,3 +28,25 @@ func ExampleNewFromPath() {
 	// [line] 33: [pattern]: apps/ [owners]: [@octocat]
 	// [line] 37: [pattern]: /docs/ [owners]: [@doctocat]
 }
+
+func ExampleParseAST() {
+	content := "# Docs owners\n/docs/   @doctocat   # reviewed weekly\n*.go\t@gophers\n"
+
+	ast, err := codeowners.ParseAST(strings.NewReader(content))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, n := range ast.Nodes {
+		if n.Kind == codeowners.EntryNode && n.Entry.Pattern == "/docs/" {
+			n.Entry.Owners = append(n.Entry.Owners, "@octocat")
+		}
+	}
+
+	fmt.Print(string(ast.Bytes()))
+
+	// Output:
+	// # Docs owners
+	// /docs/   @doctocat @octocat   # reviewed weekly
+	// *.go	@gophers
+}
@@ -3,6 +3,7 @@ package codeowners_test
 import (
 	"fmt"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -27,29 +28,42 @@ func TestParseCodeownersSuccess(t *testing.T) {
 	givenCodeownerPath := "workspace/go/repo-name"
 	expEntries := []codeowners.Entry{
 		{
-			LineNo:  3,
-			Pattern: "*",
-			Owners:  []string{"@everyone"},
+			LineNo:          3,
+			Pattern:         "*",
+			Owners:          []string{"@everyone"},
+			LeadingComments: []string{"# Sample codeowner file"},
+			Raw:             "*\t@everyone",
+			Offset:          25,
 		},
 		{
 			LineNo:  5,
 			Pattern: "src/**",
 			Owners:  []string{"@org/hakuna-matata", "@pico-bello"},
+			Raw:     "src/**\t@org/hakuna-matata @pico-bello",
+			Offset:  38,
 		},
 		{
 			LineNo:  6,
 			Pattern: "pkg/github.com/**",
 			Owners:  []string{"@myk"},
+			Raw:     "pkg/github.com/**\t@myk",
+			Offset:  76,
 		},
 		{
 			LineNo:  7,
 			Pattern: "tests/**",
 			Owners:  []string{"@ghost"},
+			Comment: "some comment",
+			Raw:     "tests/**\t@ghost # some comment",
+			Offset:  99,
 		},
 		{
 			LineNo:  8,
 			Pattern: "internal/**",
 			Owners:  []string{"@ghost"},
+			Comment: "some comment v2",
+			Raw:     "internal/**\t@ghost #some comment v2",
+			Offset:  130,
 		},
 	}
 
@@ -72,6 +86,67 @@ func TestParseCodeownersSuccess(t *testing.T) {
 	}
 }
 
+func TestParseCodeownersMalformedContent(t *testing.T) {
+	longPattern := strings.Repeat("x", 100*1024)
+
+	tests := map[string]struct {
+		content    string
+		expEntries []codeowners.Entry
+	}{
+		"Should parse lines longer than the default scanner buffer": {
+			content: longPattern + " @long\n* @after",
+			expEntries: []codeowners.Entry{
+				{LineNo: 1, Pattern: longPattern, Owners: []string{"@long"}, Raw: longPattern + " @long"},
+				{LineNo: 2, Pattern: "*", Owners: []string{"@after"}, Raw: "* @after", Offset: int64(len(longPattern)) + 7},
+			},
+		},
+		"Should strip carriage returns of CRLF line endings": {
+			content: "*.go @gophers\r\n\r\n/docs/ @docs # approved-by: @leads\r\n",
+			expEntries: []codeowners.Entry{
+				{LineNo: 1, Pattern: "*.go", Owners: []string{"@gophers"}, Raw: "*.go @gophers"},
+				{LineNo: 3, Pattern: "/docs/", Owners: []string{"@docs"}, Comment: "approved-by: @leads", Raw: "/docs/ @docs # approved-by: @leads", Offset: 17},
+			},
+		},
+		"Should keep null bytes and invalid UTF-8 as is": {
+			content: "a\x00b @null\n\xff\xfe @invalid\n",
+			expEntries: []codeowners.Entry{
+				{LineNo: 1, Pattern: "a\x00b", Owners: []string{"@null"}, Raw: "a\x00b @null"},
+				{LineNo: 2, Pattern: "\xff\xfe", Owners: []string{"@invalid"}, Raw: "\xff\xfe @invalid", Offset: 10},
+			},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			entries := codeowners.ParseCodeowners(strings.NewReader(tc.content))
+
+			// then
+			assert.Equal(t, tc.expEntries, entries)
+		})
+	}
+}
+
+func TestParseCodeownersMetadata(t *testing.T) {
+	// given
+	content := "# Default owners\n# of the repository\n*       @org/core\n\n# detached comment\n\n/docs/  @org/docs # inline\n# API owners\n/api/   @org/api\n/web/   @org/web"
+
+	// when
+	entries := codeowners.ParseCodeowners(strings.NewReader(content))
+
+	// then
+	require.Len(t, entries, 4)
+	assert.Equal(t, []string{"# Default owners", "# of the repository"}, entries[0].LeadingComments)
+	assert.Empty(t, entries[1].LeadingComments)
+	assert.Equal(t, []string{"# API owners"}, entries[2].LeadingComments)
+	assert.Empty(t, entries[3].LeadingComments)
+
+	// raw lines are located at their offsets, so the file can be reconstructed from them
+	for _, e := range entries {
+		assert.Equal(t, e.Raw, content[e.Offset:e.Offset+int64(len(e.Raw))])
+	}
+	assert.Equal(t, "/docs/  @org/docs # inline", entries[1].Raw)
+}
+
 func TestFindCodeownersFileSuccess(t *testing.T) {
 	tests := map[string]struct {
 		basePath string
@@ -0,0 +1,43 @@
+package codeowners
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Provider is a code hosting provider which defines its own CODEOWNERS dialect.
+type Provider string
+
+const (
+	// GitHub dialect, see: https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+	GitHub Provider = "github"
+	// GitLab dialect, see: https://docs.gitlab.com/ee/user/project/codeowners/reference.html
+	GitLab Provider = "gitlab"
+)
+
+// ParseProvider returns the provider with a given name. The name is case-insensitive.
+func ParseProvider(name string) (Provider, error) {
+	switch p := Provider(strings.ToLower(strings.TrimSpace(name))); p {
+	case GitHub, GitLab:
+		return p, nil
+	default:
+		return "", fmt.Errorf("not a valid provider: %q, possible values: %s, %s", name, GitHub, GitLab)
+	}
+}
+
+// ParseProviders returns providers with given names. If no names are given, GitHub is returned.
+func ParseProviders(names []string) ([]Provider, error) {
+	if len(names) == 0 {
+		return []Provider{GitHub}, nil
+	}
+
+	out := make([]Provider, 0, len(names))
+	for _, name := range names {
+		p, err := ParseProvider(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
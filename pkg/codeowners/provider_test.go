@@ -0,0 +1,44 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProviders(t *testing.T) {
+	tests := map[string]struct {
+		names    []string
+		expected []codeowners.Provider
+	}{
+		"Should default to GitHub": {
+			names:    nil,
+			expected: []codeowners.Provider{codeowners.GitHub},
+		},
+		"Should parse names case-insensitively": {
+			names:    []string{"GitHub", " gitlab"},
+			expected: []codeowners.Provider{codeowners.GitHub, codeowners.GitLab},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// when
+			got, err := codeowners.ParseProviders(tc.names)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("Should reject unknown provider", func(t *testing.T) {
+		// when
+		_, err := codeowners.ParseProviders([]string{"bitbucket"})
+
+		// then
+		assert.EqualError(t, err, `not a valid provider: "bitbucket", possible values: github, gitlab`)
+	})
+}
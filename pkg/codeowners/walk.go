@@ -0,0 +1,136 @@
+package codeowners
+
+import (
+	"errors"
+	"strings"
+)
+
+// SkipSection is returned by the visitor to skip the remaining nodes of the current section.
+// Returned outside any section, it skips the nodes placed before the first section header.
+var SkipSection = errors.New("skip this section") //nolint:revive // named as filepath.SkipDir
+
+// Visitor is called by Walk for each node of the AST.
+type Visitor func(node *Node) error
+
+// Walk calls the visitor for each node in the file order. Walking stops at the first error returned by the visitor,
+// which is returned by Walk, unless it's SkipSection.
+func (a *AST) Walk(fn Visitor) error {
+	skipping := false
+	for _, n := range a.Nodes {
+		if n.Kind == SectionNode {
+			skipping = false
+		}
+		if skipping {
+			continue
+		}
+
+		err := fn(n)
+		switch {
+		case errors.Is(err, SkipSection):
+			skipping = true
+		case err != nil:
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkMatching calls the visitor only for nodes which satisfy a given matcher.
+func (a *AST) WalkMatching(m NodeMatcher, fn Visitor) error {
+	return a.Walk(func(node *Node) error {
+		if !m(node) {
+			return nil
+		}
+		return fn(node)
+	})
+}
+
+// NodeMatcher reports whether a given node should be visited. Matchers can be combined with
+// MatchAll, MatchAny, and MatchNone.
+type NodeMatcher func(node *Node) bool
+
+// MatchKind matches nodes of given kinds.
+func MatchKind(kinds ...NodeKind) NodeMatcher {
+	return func(node *Node) bool {
+		for _, k := range kinds {
+			if node.Kind == k {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchSection matches section headers and entries of a section with a given name. Names are compared
+// case-insensitively, the same as GitLab does.
+func MatchSection(name string) NodeMatcher {
+	return func(node *Node) bool {
+		return node.Section != nil && strings.EqualFold(node.Section.Name, name)
+	}
+}
+
+// MatchOwner matches entries owned by a given owner. Owners are compared case-insensitively, as GitHub does.
+func MatchOwner(owner string) NodeMatcher {
+	return func(node *Node) bool {
+		if node.Kind != EntryNode || node.Entry == nil {
+			return false
+		}
+		for _, o := range node.Entry.Owners {
+			if strings.EqualFold(o, owner) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchUnowned matches entries without owners.
+func MatchUnowned() NodeMatcher {
+	return func(node *Node) bool {
+		return node.Kind == EntryNode && node.Entry != nil && len(node.Entry.Owners) == 0
+	}
+}
+
+// MatchPath matches entries which pattern matches a given slash-separated path, e.g. `docs/README.md`.
+// Entries with invalid patterns are not matched.
+func MatchPath(path string) NodeMatcher {
+	return func(node *Node) bool {
+		if node.Kind != EntryNode || node.Entry == nil {
+			return false
+		}
+		p, err := NewPattern(node.Entry.Pattern)
+		return err == nil && p.Match(path)
+	}
+}
+
+// MatchAll matches nodes which satisfy all given matchers.
+func MatchAll(matchers ...NodeMatcher) NodeMatcher {
+	return func(node *Node) bool {
+		for _, m := range matchers {
+			if !m(node) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAny matches nodes which satisfy at least one of given matchers.
+func MatchAny(matchers ...NodeMatcher) NodeMatcher {
+	return func(node *Node) bool {
+		for _, m := range matchers {
+			if m(node) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchNone matches nodes which don't satisfy any of given matchers.
+func MatchNone(matchers ...NodeMatcher) NodeMatcher {
+	matchAny := MatchAny(matchers...)
+	return func(node *Node) bool {
+		return !matchAny(node)
+	}
+}
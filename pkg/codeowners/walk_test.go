@@ -0,0 +1,134 @@
+package codeowners_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const walkContent = `# Owners
+/README.md @org/writers
+[Docs]
+/docs/ @org/writers @alice
+/docs/api/
+[Backend] @org/backend
+/api/ @bob
+/internal/
+`
+
+func TestASTWalk(t *testing.T) {
+	tests := map[string]struct {
+		visit    func(n *codeowners.Node) error
+		expLines []uint64
+		expErr   string
+	}{
+		"Should visit all nodes in order": {
+			visit:    func(*codeowners.Node) error { return nil },
+			expLines: []uint64{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		"Should skip the rest of the section": {
+			visit: func(n *codeowners.Node) error {
+				if n.Kind == codeowners.SectionNode && n.Section.Name == "Docs" {
+					return codeowners.SkipSection
+				}
+				return nil
+			},
+			expLines: []uint64{1, 2, 3, 6, 7, 8},
+		},
+		"Should stop on error": {
+			visit: func(n *codeowners.Node) error {
+				if n.LineNo == 4 {
+					return errors.New("stop")
+				}
+				return nil
+			},
+			expLines: []uint64{1, 2, 3, 4},
+			expErr:   "stop",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			ast, err := codeowners.ParseAST(strings.NewReader(walkContent))
+			require.NoError(t, err)
+
+			// when
+			var visited []uint64
+			err = ast.Walk(func(n *codeowners.Node) error {
+				visited = append(visited, n.LineNo)
+				return tc.visit(n)
+			})
+
+			// then
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expLines, visited)
+		})
+	}
+}
+
+func TestASTWalkMatching(t *testing.T) {
+	tests := map[string]struct {
+		matcher  codeowners.NodeMatcher
+		expLines []uint64
+	}{
+		"Kind": {
+			matcher:  codeowners.MatchKind(codeowners.CommentNode, codeowners.SectionNode),
+			expLines: []uint64{1, 3, 6},
+		},
+		"Section": {
+			matcher:  codeowners.MatchSection("docs"),
+			expLines: []uint64{3, 4, 5},
+		},
+		"Owner": {
+			matcher:  codeowners.MatchOwner("@ORG/writers"),
+			expLines: []uint64{2, 4},
+		},
+		"Unowned": {
+			matcher:  codeowners.MatchUnowned(),
+			expLines: []uint64{5, 8},
+		},
+		"Path": {
+			matcher:  codeowners.MatchPath("docs/api/index.md"),
+			expLines: []uint64{4, 5},
+		},
+		"All": {
+			matcher:  codeowners.MatchAll(codeowners.MatchSection("Backend"), codeowners.MatchKind(codeowners.EntryNode)),
+			expLines: []uint64{7, 8},
+		},
+		"Any": {
+			matcher:  codeowners.MatchAny(codeowners.MatchOwner("@alice"), codeowners.MatchOwner("@bob")),
+			expLines: []uint64{4, 7},
+		},
+		"None": {
+			matcher:  codeowners.MatchNone(codeowners.MatchKind(codeowners.EntryNode)),
+			expLines: []uint64{1, 3, 6},
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			ast, err := codeowners.ParseAST(strings.NewReader(walkContent))
+			require.NoError(t, err)
+
+			// when
+			var visited []uint64
+			err = ast.WalkMatching(tc.matcher, func(n *codeowners.Node) error {
+				visited = append(visited, n.LineNo)
+				return nil
+			})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expLines, visited)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package lint allows writing custom CODEOWNERS lint rules in Go against the file structure, without
+// reimplementing the parsing. Rules visit nodes of the codeowners.AST and are executed as a regular check:
+//
+//	rule := lint.Rule{
+//		Name:  "docs-owned-by-writers",
+//		Match: codeowners.MatchAll(codeowners.MatchPath("docs/index.md"), codeowners.MatchNone(codeowners.MatchOwner("@org/writers"))),
+//		Check: func(n *codeowners.Node) string {
+//			return fmt.Sprintf("Pattern %q owns docs, but it's not owned by @org/writers", n.Entry.Pattern)
+//		},
+//	}
+//	checker := lint.NewChecker("Docs Ownership Checker", rule)
+//
+// The checker implements the api.Checker, so it can be tested with the checktest package.
+package lint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+)
+
+// Rule is a single lint rule.
+type Rule struct {
+	// Name identifies the rule in the reported issues.
+	Name string
+	// Severity of the reported issues. Defaults to api.Error.
+	Severity api.SeverityType
+	// Match selects nodes passed to the Check. All nodes are passed if it's nil.
+	Match codeowners.NodeMatcher
+	// Check returns the problem of a given node, or an empty string if there is none.
+	Check func(node *codeowners.Node) string
+}
+
+// Checker executes lint rules against the CODEOWNERS file of the repository.
+type Checker struct {
+	name  string
+	rules []Rule
+}
+
+// NewChecker returns new instance of the Checker with a given human-readable name.
+func NewChecker(name string, rules ...Rule) *Checker {
+	return &Checker{name: name, rules: rules}
+}
+
+// Check parses the CODEOWNERS file into the AST and reports problems found by the rules, in the rules order.
+func (c *Checker) Check(ctx context.Context, in api.Input) (api.Output, error) {
+	ast, err := loadAST(in.RepoDir)
+	if err != nil {
+		return api.Output{}, err
+	}
+
+	var bldr api.OutputBuilder
+	for _, rule := range c.rules {
+		if err := ctx.Err(); err != nil {
+			return api.Output{}, err
+		}
+
+		visit := func(node *codeowners.Node) error {
+			if problem := rule.Check(node); problem != "" {
+				bldr.ReportIssue(fmt.Sprintf("%s: %s", rule.Name, problem), withNode(node), withSeverity(rule.Severity))
+			}
+			return nil
+		}
+		if rule.Match == nil {
+			err = ast.Walk(visit)
+		} else {
+			err = ast.WalkMatching(rule.Match, visit)
+		}
+		if err != nil {
+			return api.Output{}, err
+		}
+	}
+
+	return bldr.Output(), nil
+}
+
+// Name returns the human-readable name of the checker.
+func (c *Checker) Name() string {
+	return c.name
+}
+
+func loadAST(repoDir string) (*codeowners.AST, error) {
+	path, err := codeowners.FindCodeownersFile(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading %s: %w", path, err)
+	}
+	return codeowners.ParseAST(bytes.NewReader(raw))
+}
+
+func withNode(node *codeowners.Node) api.ReportIssueOpt {
+	return func(i *api.Issue) {
+		lineNo := node.LineNo
+		i.LineNo = &lineNo
+	}
+}
+
+func withSeverity(s api.SeverityType) api.ReportIssueOpt {
+	return func(i *api.Issue) {
+		if s != 0 {
+			i.Severity = s
+		}
+	}
+}
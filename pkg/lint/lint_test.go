@@ -0,0 +1,45 @@
+package lint_test
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/checktest"
+	"go.szostok.io/codeowners/pkg/codeowners"
+	"go.szostok.io/codeowners/pkg/lint"
+)
+
+func TestChecker(t *testing.T) {
+	// given
+	rules := []lint.Rule{
+		{
+			Name:  "docs-owned-by-writers",
+			Match: codeowners.MatchAll(codeowners.MatchPath("docs/index.md"), codeowners.MatchNone(codeowners.MatchOwner("@org/writers"))),
+			Check: func(n *codeowners.Node) string {
+				return fmt.Sprintf("Pattern %q owns docs, but it's not owned by @org/writers", n.Entry.Pattern)
+			},
+		},
+		{
+			Name:     "section-name-case",
+			Severity: api.Warning,
+			Match:    codeowners.MatchKind(codeowners.SectionNode),
+			Check: func(n *codeowners.Node) string {
+				if !unicode.IsUpper([]rune(n.Section.Name)[0]) {
+					return fmt.Sprintf("Section %q should start with an uppercase letter", n.Section.Name)
+				}
+				return ""
+			},
+		},
+	}
+
+	// when
+	out := checktest.Run(t, lint.NewChecker("Docs Lint Checker", rules...), checktest.Fixture{
+		Codeowners: "* @org/platform\n[docs]\n/docs/ @alice\n[Backend]\n/api/ @bob\n",
+		Files:      map[string]string{"docs/index.md": "# Docs"},
+	})
+
+	// then
+	checktest.AssertGolden(t, out)
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.szostok.io/codeowners/internal/check"
+	"go.szostok.io/codeowners/pkg/api"
+)
+
+// Factory returns a new instance of a check.
+type Factory func() (api.Checker, error)
+
+// Registry maps check names to factories, so checks can be selected by name, as with the `--checks` flag.
+// It's safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry returns new instance of the Registry with the built-in checks which don't need any configuration
+// nor network access: syntax, duppatterns, files, not-owned, avoid-shadowing, path-hazards, expensive-patterns,
+// owner-casing, encoding, invisible-chars, and unsafe-patterns. The not-owned check uses the default configuration
+// of the CLI. Other built-in checks are available only in the CLI.
+func NewRegistry() *Registry {
+	r := &Registry{factories: map[string]Factory{}}
+	for name, c := range map[string]func() api.Checker{
+		"syntax":             func() api.Checker { return check.NewValidSyntax() },
+		"duppatterns":        func() api.Checker { return check.NewDuplicatedPattern() },
+		"files":              func() api.Checker { return check.NewFileExist() },
+		"avoid-shadowing":    func() api.Checker { return check.NewAvoidShadowing() },
+		"path-hazards":       func() api.Checker { return check.NewPathHazards() },
+		"expensive-patterns": func() api.Checker { return check.NewExpensivePattern() },
+		"owner-casing":       func() api.Checker { return check.NewOwnerCasing() },
+		"encoding":           func() api.Checker { return check.NewFileEncoding() },
+		"invisible-chars":    func() api.Checker { return check.NewInvisibleChars() },
+		"unsafe-patterns":    func() api.Checker { return check.NewUnsafePattern() },
+		"not-owned": func() api.Checker {
+			return check.NewNotOwnedFile(check.NotOwnedFileConfig{
+				IgnoreSources: []string{check.IgnoreSourceGitignore, check.IgnoreSourceInfoExclude, check.IgnoreSourceGlobal},
+			})
+		},
+	} {
+		c := c
+		r.factories[name] = func() (api.Checker, error) { return c(), nil }
+	}
+	return r
+}
+
+// Register adds a check with a given name. It returns an error if the name is empty or already registered,
+// so custom checks don't silently replace the built-in ones.
+func (r *Registry) Register(name string, factory Factory) error {
+	if name == "" {
+		return errors.New("check name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("factory of check %q cannot be nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.factories[name]; found {
+		return fmt.Errorf("check %q is already registered", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// New returns a new instance of a check with a given name.
+func (r *Registry) New(name string) (api.Checker, error) {
+	r.mu.RLock()
+	factory, found := r.factories[name]
+	r.mu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("unknown check %q, registered checks: %v", name, r.Names())
+	}
+	c, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("while creating check %q: %w", name, err)
+	}
+	return c, nil
+}
+
+// Names returns sorted names of the registered checks.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
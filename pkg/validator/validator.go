@@ -0,0 +1,314 @@
+// Package validator runs CODEOWNERS checks from Go programs, e.g. repository bots, without shelling out to the CLI.
+//
+// Checks implement the Checker interface and are selected by name from a Registry, which has the built-in
+// checks and custom ones registered by the program, or given directly as instances:
+//
+//	reg := validator.NewRegistry()
+//	if err := reg.Register("my-check", func() (validator.Checker, error) { return NewMyCheck(), nil }); err != nil {
+//		return err
+//	}
+//
+//	res, err := validator.New(
+//		validator.WithRepositoryPath("/path/to/repo"),
+//		validator.WithRegistry(reg),
+//		validator.WithCheckNames("syntax", "duppatterns", "my-check"),
+//	).Run(ctx)
+//
+// The package is a stable API. New options and result fields may be added, but existing ones are not changed
+// in a backward-incompatible way.
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.szostok.io/codeowners/internal/redact"
+	"go.szostok.io/codeowners/internal/runner"
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/codeowners"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// Checker is the interface of checks. It's an alias of the api.Checker, so the same checks work with
+	// the validator and the pkg/checktest package.
+	Checker = api.Checker
+	// Input is the input of checks.
+	Input = api.Input
+	// Output is the output of checks.
+	Output = api.Output
+	// Issue is a problem reported by a check.
+	Issue = api.Issue
+	// SeverityType is the severity of issues.
+	SeverityType = api.SeverityType
+)
+
+// DefaultCheckNames are names of checks executed if no checks are given, the same as in the CLI.
+var DefaultCheckNames = []string{"syntax", "duppatterns", "files"}
+
+// Exit codes of the CLI, returned by the Result.ExitCode.
+const (
+	// ExitCodeOK means that no failures were found.
+	ExitCodeOK = runner.ExitCodeOK
+	// ExitCodeCheckFailure means that issues with the failure severity were found, or a check failed permanently.
+	ExitCodeCheckFailure = runner.ExitCodeCheckFailure
+	// ExitCodeRetryableError means that a check could not be completed because of a temporary error.
+	ExitCodeRetryableError = runner.ExitCodeRetryableError
+)
+
+// Option configures the Validator.
+type Option func(*Validator)
+
+// WithRepositoryPath validates the repository in a given directory. Defaults to the current directory.
+func WithRepositoryPath(path string) Option {
+	return func(v *Validator) {
+		v.repoPath = path
+	}
+}
+
+// WithEntries validates given CODEOWNERS entries instead of the ones loaded from the repository.
+func WithEntries(entries []codeowners.Entry) Option {
+	return func(v *Validator) {
+		v.entries = entries
+		v.entriesSet = true
+	}
+}
+
+// WithFlavor parses the CODEOWNERS file of the repository in the dialect of a given provider.
+// By default, GitLab sections are parsed if the file has section headers.
+func WithFlavor(p codeowners.Provider) Option {
+	return func(v *Validator) {
+		v.flavor = p
+	}
+}
+
+// WithRegistry selects checks by name from a given registry. Defaults to the one returned by NewRegistry.
+func WithRegistry(r *Registry) Option {
+	return func(v *Validator) {
+		v.registry = r
+	}
+}
+
+// WithCheckNames executes checks with given names from the registry.
+func WithCheckNames(names ...string) Option {
+	return func(v *Validator) {
+		v.checkNames = append(v.checkNames, names...)
+	}
+}
+
+// WithChecks executes given check instances. If no check names are given, only these checks are executed.
+func WithChecks(checks ...Checker) Option {
+	return func(v *Validator) {
+		v.checks = append(v.checks, checks...)
+	}
+}
+
+// WithFailureLevel sets the lowest severity of issues which fail the validation. Defaults to api.Warning.
+func WithFailureLevel(s SeverityType) Option {
+	return func(v *Validator) {
+		v.failureLevel = s
+	}
+}
+
+// WithDiffBaseRef provides checks with files changed between the merge base of a given reference and HEAD.
+func WithDiffBaseRef(ref string) Option {
+	return func(v *Validator) {
+		v.diffBaseRef = ref
+	}
+}
+
+// WithTree lists repository files with a given tree instead of git. The tree is treated as not managed by git,
+// so checks which need ignore rules, .gitattributes, or history are not supported.
+func WithTree(t api.Tree) Option {
+	return func(v *Validator) {
+		v.tree = t
+	}
+}
+
+// WithLogger logs warnings, e.g. about retried checks, with a given logger. By default, nothing is logged.
+// Credentials, e.g. tokens in headers and URLs, are scrubbed from entries before they are passed to the logger.
+func WithLogger(log logrus.FieldLogger) Option {
+	return func(v *Validator) {
+		v.log = log
+	}
+}
+
+// Validator executes checks against a repository. Needs to be initialized via New func.
+type Validator struct {
+	repoPath     string
+	entries      []codeowners.Entry
+	entriesSet   bool
+	flavor       codeowners.Provider
+	registry     *Registry
+	checkNames   []string
+	checks       []Checker
+	failureLevel SeverityType
+	diffBaseRef  string
+	tree         api.Tree
+	log          logrus.FieldLogger
+}
+
+// New returns new instance of the Validator
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		repoPath:     ".",
+		failureLevel: api.Warning,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.registry == nil {
+		v.registry = NewRegistry()
+	}
+	if v.log == nil {
+		log := redact.NewLogger()
+		log.SetOutput(io.Discard)
+		v.log = log
+	} else {
+		// checks may log credentials, e.g. in failed request errors
+		v.log = redact.WrapLogger(v.log, redact.Default)
+	}
+	return v
+}
+
+// Result holds results of all executed checks.
+type Result struct {
+	// Checks are results of checks in the order in which they were given.
+	Checks []CheckResult
+	// Failed is true if issues with the failure severity were found, or a check failed permanently.
+	Failed bool
+	// ExitCode is the exit code which the CLI returns for the same results.
+	ExitCode int
+}
+
+// Issues returns issues reported by all checks.
+func (r Result) Issues() []Issue {
+	var out []Issue
+	for _, c := range r.Checks {
+		out = append(out, c.Issues...)
+	}
+	return out
+}
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	// Name is the human-readable name of the check.
+	Name string
+	// Issues are issues reported by the check, with severities resolved as in the CLI.
+	Issues []Issue
+	// Err is set if the check could not be completed.
+	Err error
+	// Duration is the wall time of the check.
+	Duration time.Duration
+}
+
+// Run executes the checks in parallel and returns their results. The error is returned if the validation
+// could not be started, e.g. if the CODEOWNERS file is missing or a check name is unknown, or if the context
+// is canceled. Problems found by checks are reported in the Result.
+func (v *Validator) Run(ctx context.Context) (Result, error) {
+	checks, err := v.selectChecks()
+	if err != nil {
+		return Result{}, err
+	}
+
+	entries, err := v.loadEntries()
+	if err != nil {
+		return Result{}, err
+	}
+
+	absRepoPath, err := filepath.Abs(v.repoPath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	checkRunner := runner.NewCheckRunner(v.log, entries, absRepoPath, v.failureLevel, checks...).
+		WithPrinter(discardPrinter{}).
+		WithDiffBaseRef(v.diffBaseRef)
+	if v.tree != nil {
+		checkRunner = checkRunner.WithTree(v.tree).WithNonGit()
+	}
+	checkRunner.Run(ctx)
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	durations := map[string]time.Duration{}
+	for _, s := range checkRunner.Stats() {
+		durations[s.Check] = s.Duration
+	}
+	out := Result{
+		Failed:   checkRunner.ShouldExitWithCheckFailure(),
+		ExitCode: checkRunner.ExitCode(),
+	}
+	for _, res := range checkRunner.Results() {
+		out.Checks = append(out.Checks, CheckResult{
+			Name:     res.Check,
+			Issues:   res.Output.Issues,
+			Err:      res.Err,
+			Duration: durations[res.Check],
+		})
+	}
+	return out, nil
+}
+
+// selectChecks returns checks with given names followed by given check instances.
+func (v *Validator) selectChecks() ([]Checker, error) {
+	names := v.checkNames
+	if len(names) == 0 && len(v.checks) == 0 {
+		names = DefaultCheckNames
+	}
+
+	var out []Checker
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		c, err := v.registry.New(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return append(out, v.checks...), nil
+}
+
+func (v *Validator) loadEntries() ([]codeowners.Entry, error) {
+	if v.entriesSet {
+		return v.entries, nil
+	}
+
+	path, err := codeowners.FindCodeownersFile(v.repoPath)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	flavor := v.flavor
+	if flavor == "" {
+		flavor = codeowners.DetectProvider(raw)
+	}
+	entries, err := codeowners.ParseCodeownersAs(bytes.NewReader(raw), flavor)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// discardPrinter doesn't print anything, as results are returned to the caller.
+type discardPrinter struct{}
+
+func (discardPrinter) PrintCheckResult(string, time.Duration, api.Output, error) {}
+func (discardPrinter) PrintSummary(int, int)                                     {}
@@ -0,0 +1,155 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"go.szostok.io/codeowners/pkg/api"
+	"go.szostok.io/codeowners/pkg/checktest"
+	"go.szostok.io/codeowners/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readmeCheck reports the README.md file if it's not owned.
+type readmeCheck struct{}
+
+func (readmeCheck) Check(_ context.Context, in api.Input) (api.Output, error) {
+	var bldr api.OutputBuilder
+	for _, e := range in.CodeownersEntries {
+		if e.Pattern == "/README.md" {
+			return bldr.Output(), nil
+		}
+	}
+	return bldr.ReportIssue("README.md is not owned", api.WithSeverity(api.Warning)).Output(), nil
+}
+
+func (readmeCheck) Name() string {
+	return "README Checker"
+}
+
+func TestValidatorRun(t *testing.T) {
+	repo := checktest.Repo(t, checktest.Fixture{
+		Codeowners: "*.go @org/core\n*.go @org/api\n/docs/ @org/writers\n",
+		Files:      map[string]string{"main.go": "package main"},
+	})
+
+	tests := map[string]struct {
+		givenOpts   []validator.Option
+		expChecks   []string
+		expIssues   []string
+		expExitCode int
+	}{
+		"Should execute default checks": {
+			expChecks:   []string{"Valid Syntax Checker", "Duplicated Pattern Checker", "File Exist Checker"},
+			expIssues:   []string{"Pattern \"*.go\" is defined 2 times in lines:\n            * 1: with owners: [@org/core]\n            * 2: with owners: [@org/api]", `"/docs/" does not match any files in repository`},
+			expExitCode: validator.ExitCodeCheckFailure,
+		},
+		"Should execute only given checks": {
+			givenOpts:   []validator.Option{validator.WithCheckNames("syntax")},
+			expChecks:   []string{"Valid Syntax Checker"},
+			expExitCode: validator.ExitCodeOK,
+		},
+		"Should execute custom check instances": {
+			givenOpts:   []validator.Option{validator.WithChecks(readmeCheck{}), validator.WithFailureLevel(api.Error)},
+			expChecks:   []string{"README Checker"},
+			expIssues:   []string{"README.md is not owned"},
+			expExitCode: validator.ExitCodeOK,
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := validator.New(append([]validator.Option{validator.WithRepositoryPath(repo.RepoDir)}, tc.givenOpts...)...)
+
+			// when
+			res, err := sut.Run(context.Background())
+
+			// then
+			require.NoError(t, err)
+			var checks, issues []string
+			for _, c := range res.Checks {
+				require.NoError(t, c.Err)
+				checks = append(checks, c.Name)
+			}
+			for _, i := range res.Issues() {
+				issues = append(issues, i.Message)
+			}
+			assert.Equal(t, tc.expChecks, checks)
+			assert.Equal(t, tc.expIssues, issues)
+			assert.Equal(t, tc.expExitCode, res.ExitCode)
+			assert.Equal(t, tc.expExitCode == validator.ExitCodeCheckFailure, res.Failed)
+		})
+	}
+}
+
+func TestValidatorRunRegisteredCheck(t *testing.T) {
+	// given
+	reg := validator.NewRegistry()
+	require.NoError(t, reg.Register("readme", func() (validator.Checker, error) { return readmeCheck{}, nil }))
+
+	sut := validator.New(
+		validator.WithRepositoryPath(checktest.Repo(t, checktest.Fixture{Codeowners: "* @org/core\n"}).RepoDir),
+		validator.WithRegistry(reg),
+		validator.WithCheckNames("syntax", "readme"),
+	)
+
+	// when
+	res, err := sut.Run(context.Background())
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, res.Checks, 2)
+	assert.Equal(t, "README Checker", res.Checks[1].Name)
+	assert.Equal(t, []validator.Issue{{Severity: api.Warning, Message: "README.md is not owned"}}, res.Issues())
+	assert.True(t, res.Failed)
+}
+
+func TestValidatorRunErrors(t *testing.T) {
+	tests := map[string]struct {
+		givenOpts []validator.Option
+		expErr    string
+	}{
+		"Should reject unknown check": {
+			givenOpts: []validator.Option{validator.WithCheckNames("owners")},
+			expErr:    `unknown check "owners", registered checks: [avoid-shadowing duppatterns encoding expensive-patterns files invisible-chars not-owned owner-casing path-hazards syntax unsafe-patterns]`,
+		},
+		"Should report missing CODEOWNERS file": {
+			givenOpts: []validator.Option{validator.WithCheckNames("syntax")},
+			expErr:    "No CODEOWNERS found in the root, docs/, or .github/ directory of the repository",
+		},
+	}
+	for tn, tc := range tests {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			sut := validator.New(append([]validator.Option{validator.WithRepositoryPath(t.TempDir())}, tc.givenOpts...)...)
+
+			// when
+			_, err := sut.Run(context.Background())
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expErr)
+		})
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	// given
+	reg := validator.NewRegistry()
+	factory := func() (validator.Checker, error) { return readmeCheck{}, nil }
+
+	// when
+	errBuiltin := reg.Register("syntax", factory)
+	errEmpty := reg.Register("", factory)
+	errCustom := reg.Register("readme", factory)
+	errDuplicate := reg.Register("readme", factory)
+
+	// then
+	assert.EqualError(t, errBuiltin, `check "syntax" is already registered`)
+	assert.EqualError(t, errEmpty, "check name cannot be empty")
+	assert.NoError(t, errCustom)
+	assert.EqualError(t, errDuplicate, `check "readme" is already registered`)
+	assert.Contains(t, reg.Names(), "readme")
+}
@@ -62,7 +62,8 @@ type Executor struct {
 func Exec() *Executor {
 	return &Executor{
 		arguments: make([]string, 0),
-		envs:      map[string]string{},
+		// golden files expect the full report, so the cached verdicts are never used
+		envs: map[string]string{envPrefix + "NO_SKIP_CACHE": "true"},
 	}
 }
 